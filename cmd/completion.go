@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/storage"
 )
 
 // completionCmd represents the completion command
@@ -71,6 +74,9 @@ PowerShell:
 
 func init() {
 	rootCmd.AddCommand(completionCmd)
+
+	// Complete @project and #tag tokens when logging an entry
+	rootCmd.ValidArgsFunction = completeEntryArgs
 }
 
 // generateCompletion generates the appropriate completion script based on shell type
@@ -99,3 +105,61 @@ func generateCompletion(shell string) {
 		return
 	}
 }
+
+// completeEntryArgs completes @project and #tag tokens in an entry description
+// against the projects and tags already used in storage, quoting multi-word
+// names. Other tokens are free text, so no completions (and no file names) are
+// offered for them. A missing or unreadable storage file simply yields no
+// completions.
+func completeEntryArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if !strings.HasPrefix(toComplete, "@") && !strings.HasPrefix(toComplete, "#") {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	storagePath, err := deps.StoragePath()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	result, err := storage.ReadEntriesWithWarnings(storagePath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Collect the known names for the prefix being completed
+	prefix := toComplete[:1]
+	known := make(map[string]bool)
+	for _, e := range result.Entries {
+		if e.DeletedAt != nil {
+			continue
+		}
+		if prefix == "@" {
+			if e.Project != "" {
+				known[e.Project] = true
+			}
+			continue
+		}
+		for _, tag := range e.Tags {
+			known[tag] = true
+		}
+	}
+
+	// Multi-word names complete in quotes, like @"Acme North America". Once a
+	// quote is typed, single-word names are completed in quotes as well, so
+	// the completion still starts with what was typed.
+	partial := toComplete[1:]
+	quoted := strings.HasPrefix(partial, `"`)
+	partial = strings.ToLower(strings.TrimPrefix(partial, `"`))
+	var completions []string
+	for name := range known {
+		if !strings.HasPrefix(strings.ToLower(name), partial) {
+			continue
+		}
+		if quoted || strings.Contains(name, " ") {
+			name = `"` + name + `"`
+		}
+		completions = append(completions, prefix+name)
+	}
+	sort.Strings(completions)
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -3,8 +3,16 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
 )
 
 // TestGenerateCompletion_Bash tests generating bash completion script
@@ -778,3 +786,113 @@ func TestGenerateCompletion_SimilarShellNames(t *testing.T) {
 		})
 	}
 }
+
+func TestCompleteEntryArgs(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	deletedAt := time.Now()
+	for _, e := range []entry.Entry{
+		{Timestamp: time.Now(), Description: "fix", DurationMinutes: 30, Project: "acme", Tags: []string{"bugfix", "Backend"}},
+		{Timestamp: time.Now(), Description: "call", DurationMinutes: 30, Project: "client", Tags: []string{"meeting"}},
+		{Timestamp: time.Now(), Description: "old", DurationMinutes: 30, Project: "archived", Tags: []string{"bygone"}, DeletedAt: &deletedAt},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, _, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	tests := []struct {
+		toComplete string
+		expected   []string
+	}{
+		{"@", []string{"@acme", "@client"}},
+		{"@a", []string{"@acme"}},
+		{"@ACM", []string{"@acme"}},
+		{"#b", []string{"#Backend", "#bugfix"}},
+		{"#", []string{"#Backend", "#bugfix", "#meeting"}},
+		{"#zzz", nil},
+		{"fix", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		got, directive := completeEntryArgs(rootCmd, []string{"fix", "bug"}, tt.toComplete)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("completeEntryArgs(%q) = %v, expected %v", tt.toComplete, got, tt.expected)
+		}
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Errorf("completeEntryArgs(%q) directive = %v, expected NoFileComp", tt.toComplete, directive)
+		}
+	}
+}
+
+func TestCompleteEntryArgs_MultiWordNames(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	for _, e := range []entry.Entry{
+		{Timestamp: time.Now(), Description: "review", DurationMinutes: 60, Project: "Acme North America"},
+		{Timestamp: time.Now(), Description: "fix", DurationMinutes: 30, Project: "acme"},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, _, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	tests := []struct {
+		toComplete string
+		expected   []string
+	}{
+		{"@acme", []string{`@"Acme North America"`, "@acme"}},
+		{"@Acme N", []string{`@"Acme North America"`}},
+		{`@"acme`, []string{`@"Acme North America"`, `@"acme"`}},
+		{`@"Acme North`, []string{`@"Acme North America"`}},
+	}
+
+	for _, tt := range tests {
+		got, _ := completeEntryArgs(rootCmd, nil, tt.toComplete)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("completeEntryArgs(%q) = %v, expected %v", tt.toComplete, got, tt.expected)
+		}
+	}
+}
+
+func TestCompleteEntryArgs_MissingOrCorruptedStorage(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Missing storage file
+	d, _, _ := testDeps(filepath.Join(tmpDir, "missing.jsonl"))
+	SetDeps(d)
+	defer ResetDeps()
+
+	got, directive := completeEntryArgs(rootCmd, nil, "@")
+	if len(got) != 0 || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Expected no completions for missing storage, got %v (%v)", got, directive)
+	}
+
+	// Corrupted lines are skipped
+	storagePath := filepath.Join(tmpDir, "corrupted.jsonl")
+	content := "not json\n" + `{"timestamp":"2024-01-15T10:00:00Z","description":"x","duration_minutes":5,"raw_input":"x for 5m","project":"acme"}` + "\n{broken\n"
+	if err := os.WriteFile(storagePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write storage file: %v", err)
+	}
+	d, _, _ = testDeps(storagePath)
+	SetDeps(d)
+
+	got, _ = completeEntryArgs(rootCmd, nil, "@")
+	if !reflect.DeepEqual(got, []string{"@acme"}) {
+		t.Errorf("Expected [@acme] from corrupted storage, got %v", got)
+	}
+
+	// Storage path errors yield no completions
+	d.StoragePath = func() (string, error) { return "", os.ErrPermission }
+	got, directive = completeEntryArgs(rootCmd, nil, "#")
+	if len(got) != 0 || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Expected no completions on storage path error, got %v (%v)", got, directive)
+	}
+}
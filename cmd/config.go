@@ -4,10 +4,14 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/timeutil"
 )
 
 var configInitFlag bool
@@ -32,7 +36,8 @@ Examples:
     did config                       Show all current settings
 
   Create a sample configuration file:
-    did config --init                Create config.toml with all options
+    did config init                  Create config.toml with all options
+    did config init --force          Replace an existing config.toml
 
 Configuration file location:
   ~/.config/did/config.toml          Linux/macOS
@@ -48,8 +53,31 @@ To customize settings, create a config.toml file at the location shown above.`,
 	},
 }
 
+// configInitCmd represents the config init command
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a sample configuration file",
+	Long: `Create config.toml at the standard location with every option listed,
+set to its default value and commented out, with a short description.
+
+An existing config file is never overwritten unless --force is given.
+
+Examples:
+  did config init                  Create config.toml with all options
+  did config init --force          Replace an existing config.toml`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		initConfigFile(force)
+	},
+}
+
 func init() {
+	configCmd.AddCommand(configInitCmd)
+
 	configCmd.Flags().BoolVar(&configInitFlag, "init", false, "create a sample configuration file")
+	_ = configCmd.Flags().MarkDeprecated("init", "use 'did config init' instead")
+	configInitCmd.Flags().Bool("force", false, "Overwrite an existing config file")
 }
 
 // showConfig displays the current effective configuration
@@ -71,12 +99,13 @@ func showConfig() {
 	}
 
 	// Load config (will use defaults if file doesn't exist)
-	cfg, err := config.LoadOrDefault(configPath)
+	cfg, err := config.LoadWithEnv(configPath)
 	if err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to load configuration")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
 		_, _ = fmt.Fprintln(deps.Stderr)
 		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that your config file is valid TOML format: %s\n", configPath)
+		_, _ = fmt.Fprintf(deps.Stderr, "      and that these environment variables are valid if set: %s\n", envOverrideNames())
 		_, _ = fmt.Fprintln(deps.Stderr, "Valid week_start_day values: monday, sunday")
 		_, _ = fmt.Fprintln(deps.Stderr, "Valid timezone examples: Local, America/New_York, Europe/London, Asia/Tokyo")
 		deps.Exit(1)
@@ -90,6 +119,11 @@ func showConfig() {
 
 	// Display config file location and status
 	_, _ = fmt.Fprintf(deps.Stdout, "Config file:     %s\n", configPath)
+	if source := config.ConfigPathSource(); source != "" {
+		_, _ = fmt.Fprintf(deps.Stdout, "Source:          %s\n", source)
+	} else {
+		_, _ = fmt.Fprintln(deps.Stdout, "Source:          default location")
+	}
 	if fileExists {
 		_, _ = fmt.Fprintln(deps.Stdout, "Status:          File exists (using custom configuration)")
 	} else {
@@ -100,7 +134,12 @@ func showConfig() {
 	// Display current settings
 	_, _ = fmt.Fprintln(deps.Stdout, "Current Settings:")
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("-", 60))
+	_, _ = fmt.Fprintf(deps.Stdout, "Config Version:  %d\n", cfg.Version)
+	if from, err := config.FileVersion(configPath); err == nil && fileExists && from < config.CurrentVersion {
+		_, _ = fmt.Fprintf(deps.Stdout, "                 (file is version %d, update it with 'did migrate --config-file')\n", from)
+	}
 	_, _ = fmt.Fprintf(deps.Stdout, "Week Start Day:  %s\n", cfg.WeekStartDay)
+	_, _ = fmt.Fprintf(deps.Stdout, "Month Start Day: %d\n", max(cfg.MonthStartDay, 1))
 	_, _ = fmt.Fprintf(deps.Stdout, "Timezone:        %s\n", cfg.Timezone)
 
 	// Display default_output_format with special handling for empty value
@@ -109,6 +148,83 @@ func showConfig() {
 	} else {
 		_, _ = fmt.Fprintf(deps.Stdout, "Output Format:   %s\n", cfg.DefaultOutputFormat)
 	}
+	_, _ = fmt.Fprintf(deps.Stdout, "Durable Writes:  %t\n", cfg.DurableWrites)
+	if cfg.StoragePath == "" {
+		_, _ = fmt.Fprintln(deps.Stdout, "Storage Path:    (default)")
+	} else {
+		_, _ = fmt.Fprintf(deps.Stdout, "Storage Path:    %s\n", cfg.StoragePath)
+	}
+	if cfg.MyFile != "" {
+		_, _ = fmt.Fprintf(deps.Stdout, "My File:         %s\n", cfg.MyFile)
+	}
+	if cfg.DefaultProject == "" {
+		_, _ = fmt.Fprintln(deps.Stdout, "Default Project: (none)")
+	} else {
+		_, _ = fmt.Fprintf(deps.Stdout, "Default Project: %s\n", cfg.DefaultProject)
+	}
+	if cfg.RoundMinutes == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, "Rounding:        off")
+	} else {
+		_, _ = fmt.Fprintf(deps.Stdout, "Rounding:        up to %s\n", formatDuration(cfg.RoundMinutes))
+	}
+	if cfg.RoundDisplayMinutes == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, "Display Round:   off")
+	} else {
+		_, _ = fmt.Fprintf(deps.Stdout, "Display Round:   to nearest %s\n", formatDuration(cfg.RoundDisplayMinutes))
+	}
+	_, _ = fmt.Fprintf(deps.Stdout, "Duration Word:   %s\n", cfg.EffectiveDurationKeyword())
+	if cfg.DefaultDurationMinutes == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, "Default Length:  off (duration required)")
+	} else {
+		_, _ = fmt.Fprintf(deps.Stdout, "Default Length:  %s\n", formatDuration(cfg.DefaultDurationMinutes))
+	}
+	_, _ = fmt.Fprintf(deps.Stdout, "Future Margin:   %s\n", formatDuration(cfg.FutureMarginMinutes))
+	_, _ = fmt.Fprintf(deps.Stdout, "Max Entry:       %s\n", formatDuration(cfg.MaxEntryMinutes()))
+	if cfg.TimestampPrecision == "" {
+		_, _ = fmt.Fprintf(deps.Stdout, "Timestamps:      %s\n", config.DefaultTimestampPrecision)
+	} else {
+		_, _ = fmt.Fprintf(deps.Stdout, "Timestamps:      %s\n", cfg.TimestampPrecision)
+	}
+	_, _ = fmt.Fprintf(deps.Stdout, "Split Days:      %t\n", cfg.SplitAtMidnight)
+	_, _ = fmt.Fprintf(deps.Stdout, "Footer Projects: %t\n", cfg.FooterBreakdown)
+	_, _ = fmt.Fprintf(deps.Stdout, "Sparkline:       %t\n", cfg.Sparkline)
+	_, _ = fmt.Fprintf(deps.Stdout, "Pager:           %t\n", cfg.Pager)
+	if cfg.NumberFormat == "" {
+		_, _ = fmt.Fprintf(deps.Stdout, "Number Format:   %s\n", timeutil.DefaultNumberFormat)
+	} else {
+		_, _ = fmt.Fprintf(deps.Stdout, "Number Format:   %s\n", cfg.NumberFormat)
+	}
+	if len(cfg.WorkingHours) == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, "Working Hours:   (not set)")
+	} else {
+		_, _ = fmt.Fprintf(deps.Stdout, "Working Hours:   %s\n", formatWorkingHours(cfg.WorkingHours))
+	}
+	if len(cfg.Workspaces) == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, "Workspaces:      (none)")
+	} else {
+		_, _ = fmt.Fprintln(deps.Stdout, "Workspaces:")
+		dirs := make([]string, 0, len(cfg.Workspaces))
+		for dir := range cfg.Workspaces {
+			dirs = append(dirs, dir)
+		}
+		sort.Strings(dirs)
+		for _, dir := range dirs {
+			ws := cfg.Workspaces[dir]
+			_, _ = fmt.Fprintf(deps.Stdout, "  %s → %s\n", dir, formatProjectAndTags(ws.Project, ws.Tags))
+		}
+	}
+
+	// Display environment variables overriding the config file
+	var overrides []string
+	for _, o := range config.EnvOverrides {
+		if os.Getenv(o.Name) != "" {
+			overrides = append(overrides, fmt.Sprintf("%s (%s)", o.Name, o.Key))
+		}
+	}
+	if len(overrides) > 0 {
+		_, _ = fmt.Fprintln(deps.Stdout)
+		_, _ = fmt.Fprintf(deps.Stdout, "Environment overrides: %s\n", strings.Join(overrides, ", "))
+	}
 
 	_, _ = fmt.Fprintln(deps.Stdout)
 
@@ -120,15 +236,11 @@ func showConfig() {
 	}
 }
 
-// initConfig creates a sample configuration file with all options documented
+// initConfig creates a sample configuration file with all options documented,
+// asking before overwriting an existing one (did config init)
 func initConfig() {
-	// Get config file path
-	configPath, err := config.GetConfigPath()
-	if err != nil {
-		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine config file location")
-		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
-		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
-		deps.Exit(1)
+	configPath, ok := resolveConfigPath()
+	if !ok {
 		return
 	}
 
@@ -142,11 +254,57 @@ func initConfig() {
 		}
 	}
 
+	writeSampleConfig(configPath)
+}
+
+// initConfigFile creates a sample configuration file with all options
+// documented, refusing to overwrite an existing one unless force is set
+// (did config init)
+func initConfigFile(force bool) {
+	configPath, ok := resolveConfigPath()
+	if !ok {
+		return
+	}
+
+	if _, err := os.Stat(configPath); err == nil && !force {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Config file already exists: %s\n", configPath)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use --force to replace it with the sample config, or run 'did config' to see its settings")
+		deps.Exit(1)
+		return
+	}
+
+	writeSampleConfig(configPath)
+}
+
+// resolveConfigPath returns the config file path, reporting a failure to determine it
+func resolveConfigPath() (string, bool) {
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine config file location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return "", false
+	}
+	return configPath, true
+}
+
+// writeConfigFile writes content to the config file at configPath, creating
+// the config directory first (config.GetConfigPath doesn't create it)
+func writeConfigFile(configPath, content string) error {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, []byte(content), 0644)
+}
+
+// writeSampleConfig writes the sample configuration to configPath and shows the next steps
+func writeSampleConfig(configPath string) {
 	// Generate sample config content
 	sampleConfig := config.GenerateSampleConfig()
 
 	// Write the sample config file
-	if err := os.WriteFile(configPath, []byte(sampleConfig), 0644); err != nil {
+	if err := writeConfigFile(configPath, sampleConfig); err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to create config file")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
 		deps.Exit(1)
@@ -176,3 +334,19 @@ func promptOverwriteConfirmation() bool {
 	response := strings.TrimSpace(scanner.Text())
 	return response == "y" || response == "Y"
 }
+
+// formatWorkingHours formats the configured schedule from Monday to Sunday,
+// e.g. "Mon 8h, Tue 8h, Fri 6h"
+func formatWorkingHours(schedule config.WorkingHours) string {
+	var parts []string
+	for i := 1; i <= 7; i++ {
+		day := time.Weekday(i % 7)
+		if minutes := schedule.ExpectedMinutes(day); minutes > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", day.String()[:3], formatDuration(minutes)))
+		}
+	}
+	if len(parts) == 0 {
+		return "no hours"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -872,3 +875,221 @@ func TestShowConfig_PartialConfig(t *testing.T) {
 		t.Errorf("Expected output to show '(default)' for output format, got: %s", output)
 	}
 }
+
+func TestShowConfig_EnvironmentOverrides(t *testing.T) {
+	configDir := t.TempDir()
+	osutil.SetProvider(&configMockPathProvider{
+		userConfigDirFn: func() (string, error) { return configDir, nil },
+		mkdirAllFn:      os.MkdirAll,
+	})
+	defer osutil.ResetProvider()
+
+	t.Setenv("DID_TIMEZONE", "Asia/Tokyo")
+	t.Setenv("DID_DEFAULT_PROJECT", "acme")
+	t.Setenv("DID_ROUND", "15")
+
+	d, stdout, stderr := testDeps("")
+	SetDeps(d)
+	defer ResetDeps()
+
+	showConfig()
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{
+		"Timezone:        Asia/Tokyo",
+		"Default Project: acme",
+		"Rounding:        up to 15m",
+		"Environment overrides: DID_TIMEZONE (timezone), DID_DEFAULT_PROJECT (default_project), DID_ROUND (round_minutes)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got: %s", want, output)
+		}
+	}
+}
+
+func TestShowConfig_ConfigSource(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "work.toml")
+	if err := os.WriteFile(configPath, []byte(`default_project = "acme"`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	t.Setenv(config.ConfigPathEnv, configPath)
+
+	d, stdout, stderr := testDeps("")
+	SetDeps(d)
+	defer ResetDeps()
+
+	showConfig()
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{
+		"Config file:     " + configPath,
+		"Source:          DID_CONFIG",
+		"Default Project: acme",
+		"(file is version 0, update it with 'did migrate --config-file')",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got: %s", want, output)
+		}
+	}
+}
+
+func TestShowConfig_InvalidEnvironmentOverride(t *testing.T) {
+	configDir := t.TempDir()
+	osutil.SetProvider(&configMockPathProvider{
+		userConfigDirFn: func() (string, error) { return configDir, nil },
+		mkdirAllFn:      os.MkdirAll,
+	})
+	defer osutil.ResetProvider()
+
+	t.Setenv("DID_WEEK_START", "friday")
+
+	d, _, stderr := testDeps("")
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+
+	showConfig()
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "DID_WEEK_START: invalid week_start_day") {
+		t.Errorf("Expected env variable in error, got: %s", stderr.String())
+	}
+}
+
+func TestFormatWorkingHours(t *testing.T) {
+	schedule := config.WorkingHours{"fri": 6, "mon": 8, "sun": 1.5, "tue": 0}
+	expected := "Mon 8h, Fri 6h, Sun 1h 30m"
+	if got := formatWorkingHours(schedule); got != expected {
+		t.Errorf("formatWorkingHours() = %q, expected %q", got, expected)
+	}
+	if got := formatWorkingHours(config.WorkingHours{"mon": 0}); got != "no hours" {
+		t.Errorf("formatWorkingHours() = %q, expected %q", got, "no hours")
+	}
+}
+
+// tempConfigDir points the config path at a temporary directory for the test
+func tempConfigDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	osutil.SetProvider(&configMockPathProvider{
+		userConfigDirFn: func() (string, error) { return dir, nil },
+		mkdirAllFn:      os.MkdirAll,
+	})
+	t.Cleanup(osutil.ResetProvider)
+
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		t.Fatalf("Failed to get config path: %v", err)
+	}
+	return configPath
+}
+
+func TestConfigInit_CreatesSampleConfig(t *testing.T) {
+	configPath := tempConfigDir(t)
+	d, stdout, stderr := testDeps("")
+	SetDeps(d)
+	defer ResetDeps()
+
+	configInitCmd.Run(configInitCmd, []string{})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Created sample configuration file: "+configPath) {
+		t.Errorf("Expected success message, got: %s", stdout.String())
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Expected config file to be created: %v", err)
+	}
+	if string(data) != config.GenerateSampleConfig() {
+		t.Error("Expected the sample config to be written")
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("Expected the created config to load: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, config.DefaultConfig()) {
+		t.Errorf("Expected the created config to have the defaults, got %+v", cfg)
+	}
+}
+
+func TestConfigInit_RefusesToOverwrite(t *testing.T) {
+	configPath := tempConfigDir(t)
+	if err := writeConfigFile(configPath, "week_start_day = \"sunday\"\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	exitCode := 0
+	d, _, stderr := testDeps("")
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+
+	initConfigFile(false)
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "Config file already exists") || !strings.Contains(stderr.String(), "--force") {
+		t.Errorf("Expected an error suggesting --force, got: %s", stderr.String())
+	}
+	if data, _ := os.ReadFile(configPath); string(data) != "week_start_day = \"sunday\"\n" {
+		t.Errorf("Expected the existing config to be kept, got: %s", data)
+	}
+}
+
+func TestConfigInit_Force(t *testing.T) {
+	configPath := tempConfigDir(t)
+	if err := writeConfigFile(configPath, "week_start_day = \"sunday\"\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	d, stdout, stderr := testDeps("")
+	SetDeps(d)
+	defer ResetDeps()
+	_ = configInitCmd.Flags().Set("force", "true")
+	defer func() { _ = configInitCmd.Flags().Set("force", "false") }()
+
+	configInitCmd.Run(configInitCmd, []string{})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Created sample configuration file") {
+		t.Errorf("Expected success message, got: %s", stdout.String())
+	}
+	if data, _ := os.ReadFile(configPath); string(data) != config.GenerateSampleConfig() {
+		t.Error("Expected --force to replace the config with the sample")
+	}
+}
+
+func TestSampleConfig_MentionsExistingCommands(t *testing.T) {
+	for _, match := range regexp.MustCompile(`'did ([^']+)'`).FindAllStringSubmatch(config.GenerateSampleConfig(), -1) {
+		var words []string
+		for _, field := range strings.Fields(match[1]) {
+			if !strings.HasPrefix(field, "-") {
+				words = append(words, field)
+			}
+		}
+		found, _, err := rootCmd.Find(words)
+		if err != nil || found.CommandPath() != "did "+strings.Join(words, " ") {
+			t.Errorf("Sample config mentions '%s', which is not a command", match[0])
+			continue
+		}
+		for _, field := range strings.Fields(match[1]) {
+			if name := strings.TrimLeft(field, "-"); name != field && found.Flags().Lookup(name) == nil {
+				t.Errorf("Sample config mentions '%s', but %s has no flag %s", match[0], found.CommandPath(), field)
+			}
+		}
+	}
+}
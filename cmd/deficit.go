@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/query"
+	"github.com/xolan/did/internal/stats"
+	"github.com/xolan/did/internal/storage"
+	"github.com/xolan/did/internal/timeutil"
+)
+
+// deficitCmd represents the deficit command
+var deficitCmd = &cobra.Command{
+	Use:   "deficit",
+	Short: "Compare logged time against your working hours",
+	Long: `Compare the time logged on each day against the working hours configured
+in your config file, and show the shortfall or surplus per day and for the
+whole period.
+
+The schedule is read from the [working_hours] table in the config file:
+
+  [working_hours]
+  mon = 8
+  tue = 8
+  wed = 8
+  thu = 8
+  fri = 6
+
+Days that are not listed expect no hours. Days later than today count as
+zero expected hours, so the total shows where you stand right now.
+
+Examples:
+  did deficit                     Deficit for the current week
+  did deficit --this-week         Same as above
+  did deficit --prev-week         Deficit for the previous week
+  did deficit --this-month        Deficit for the current month
+  did deficit --prev-month        Deficit for the previous month`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		showDeficit(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deficitCmd)
+
+	deficitCmd.Flags().BoolP("this-week", "w", false, "Show the deficit for the current week (default)")
+	deficitCmd.Flags().Bool("prev-week", false, "Show the deficit for the previous week")
+	deficitCmd.Flags().BoolP("this-month", "m", false, "Show the deficit for the current month")
+	deficitCmd.Flags().Bool("prev-month", false, "Show the deficit for the previous month")
+	addPeriodFlagAliases(deficitCmd)
+}
+
+// deficitDay is the logged and expected time for a single day
+type deficitDay struct {
+	Date            time.Time
+	LoggedMinutes   int
+	ExpectedMinutes int
+}
+
+// showDeficit prints logged against expected time per day for the selected period
+func showDeficit(cmd *cobra.Command) {
+	thisWeek, _ := cmd.Flags().GetBool("this-week")
+	prevWeek, _ := cmd.Flags().GetBool("prev-week")
+	thisMonth, _ := cmd.Flags().GetBool("this-month")
+	prevMonth, _ := cmd.Flags().GetBool("prev-month")
+
+	count := 0
+	for _, set := range []bool{thisWeek, prevWeek, thisMonth, prevMonth} {
+		if set {
+			count++
+		}
+	}
+	if count > 1 {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Time period flags are mutually exclusive")
+		_, _ = fmt.Fprintln(deps.Stderr, "Use only one of: --this-week, --prev-week, --this-month, --prev-month")
+		deps.Exit(1)
+		return
+	}
+
+	if len(deps.Config.WorkingHours) == 0 {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: No working hours configured")
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Add a [working_hours] table to your config file, e.g.:")
+		_, _ = fmt.Fprintln(deps.Stderr, "  [working_hours]")
+		_, _ = fmt.Fprintln(deps.Stderr, "  mon = 8")
+		_, _ = fmt.Fprintln(deps.Stderr, "  fri = 6")
+		_, _ = fmt.Fprintln(deps.Stderr, "Run 'did config' to see where your config file is")
+		deps.Exit(1)
+		return
+	}
+
+	now := configuredNow()
+	var start, end time.Time
+	var period, label string
+	switch {
+	case prevWeek:
+		lastWeek := now.AddDate(0, 0, -7)
+		start = timeutil.StartOfWeekWithConfig(lastWeek, deps.Config.WeekStartDay)
+		end = timeutil.EndOfWeekWithConfig(lastWeek, deps.Config.WeekStartDay)
+		period, label = "previous week", "Week"
+	case thisMonth:
+		start = timeutil.StartOfMonthWithConfig(now, deps.Config.MonthStartDay)
+		end = timeutil.EndOfMonthWithConfig(now, deps.Config.MonthStartDay)
+		period, label = "this month", "Month"
+	case prevMonth:
+		lastMonth := timeutil.StartOfMonthWithConfig(now, deps.Config.MonthStartDay).AddDate(0, 0, -1)
+		start = timeutil.StartOfMonthWithConfig(lastMonth, deps.Config.MonthStartDay)
+		end = timeutil.EndOfMonthWithConfig(lastMonth, deps.Config.MonthStartDay)
+		period, label = "previous month", "Month"
+	default:
+		start = timeutil.StartOfWeekWithConfig(now, deps.Config.WeekStartDay)
+		end = timeutil.EndOfWeekWithConfig(now, deps.Config.WeekStartDay)
+		period, label = "this week", "Week"
+	}
+
+	// Get storage path
+	storagePath, err := deps.StoragePath()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return
+	}
+
+	// Read all entries from storage
+	result, err := storage.ReadEntriesWithWarnings(storagePath)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", storagePath)
+		deps.Exit(1)
+		return
+	}
+
+	// Display warnings about corrupted lines to stderr
+	printCorruptionWarnings(result.Warnings)
+	printSuspectWarnings(countSuspect(result.Entries, func(e entry.Entry) bool {
+		return timeutil.IsInRange(e.Timestamp, start, end)
+	}))
+
+	// Filter out soft-deleted entries and entries with a zero or negative duration
+	var activeEntries []entry.Entry
+	for _, e := range result.Entries {
+		if e.DeletedAt == nil && e.HasValidDuration() {
+			activeEntries = append(activeEntries, e)
+		}
+	}
+
+	days := calculateDeficit(activeEntries, deps.Config.WorkingHours, start, end, now)
+
+	_, _ = fmt.Fprintf(deps.Stdout, "Working hours for %s (%s):\n\n", period, query.FormatDateRange(start, end))
+
+	totalLogged, totalExpected := 0, 0
+	for _, day := range days {
+		totalLogged += day.LoggedMinutes
+		totalExpected += day.ExpectedMinutes
+		_, _ = fmt.Fprintf(deps.Stdout, "  %s  %8s / %-8s %10s\n",
+			day.Date.Format("Mon Jan 02"),
+			formatDuration(day.LoggedMinutes),
+			formatDuration(day.ExpectedMinutes),
+			formatSignedDuration(day.LoggedMinutes-day.ExpectedMinutes))
+	}
+
+	_, _ = fmt.Fprintln(deps.Stdout)
+	_, _ = fmt.Fprintf(deps.Stdout, "%s deficit: %s (logged %s of %s expected)\n",
+		label,
+		formatSignedDuration(totalLogged-totalExpected),
+		formatDuration(totalLogged),
+		formatDuration(totalExpected))
+}
+
+// calculateDeficit returns the logged and expected minutes for every day from
+// start to end in the location of now. Days after now expect no time.
+func calculateDeficit(entries []entry.Entry, schedule config.WorkingHours, start, end, now time.Time) []deficitDay {
+	today := timeutil.StartOfDay(now)
+
+	var days []deficitDay
+	for _, day := range stats.DailyTotals(entries, start, end, now.Location()) {
+		expected := 0
+		if !day.Date.After(today) {
+			expected = schedule.ExpectedMinutes(day.Date.Weekday())
+		}
+		days = append(days, deficitDay{
+			Date:            day.Date,
+			LoggedMinutes:   day.Minutes,
+			ExpectedMinutes: expected,
+		})
+	}
+	return days
+}
+
+// formatSignedDuration formats a difference in minutes with a leading + or -
+func formatSignedDuration(minutes int) string {
+	switch {
+	case minutes > 0:
+		return "+" + formatDuration(minutes)
+	case minutes < 0:
+		return "-" + formatDuration(-minutes)
+	default:
+		return formatDuration(0)
+	}
+}
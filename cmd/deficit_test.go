@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+	"github.com/xolan/did/internal/timeutil"
+)
+
+// testWorkingHours is the schedule used by the deficit tests (38 hours a week)
+var testWorkingHours = config.WorkingHours{"mon": 8, "tue": 8, "wed": 8, "thu": 8, "fri": 6}
+
+// resetDeficitFlags clears the deficit period flags
+func resetDeficitFlags(cmd *cobra.Command) {
+	for _, name := range []string{"this-week", "prev-week", "this-month", "prev-month"} {
+		_ = cmd.Flags().Set(name, "false")
+		cmd.Flags().Lookup(name).Changed = false
+	}
+}
+
+func TestCalculateDeficit(t *testing.T) {
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC) // Monday
+	end := timeutil.EndOfWeek(start)
+	now := time.Date(2024, 1, 17, 12, 0, 0, 0, time.UTC) // Wednesday
+
+	entries := []entry.Entry{
+		{Timestamp: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), DurationMinutes: 300},
+		{Timestamp: time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC), DurationMinutes: 150},
+		{Timestamp: time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC), DurationMinutes: 540},
+		{Timestamp: time.Date(2024, 1, 20, 9, 0, 0, 0, time.UTC), DurationMinutes: 60},
+		{Timestamp: time.Date(2024, 1, 22, 9, 0, 0, 0, time.UTC), DurationMinutes: 480},
+	}
+
+	days := calculateDeficit(entries, testWorkingHours, start, end, now)
+
+	expected := []struct {
+		logged, expected int
+	}{
+		{450, 480}, // Mon
+		{540, 480}, // Tue
+		{0, 480},   // Wed (today)
+		{0, 0},     // Thu (future)
+		{0, 0},     // Fri (future)
+		{60, 0},    // Sat
+		{0, 0},     // Sun
+	}
+	if len(days) != len(expected) {
+		t.Fatalf("Expected %d days, got %d", len(expected), len(days))
+	}
+	for i, want := range expected {
+		day := days[i]
+		if day.Date.Day() != 15+i {
+			t.Errorf("Day %d: expected date Jan %d, got %s", i, 15+i, day.Date.Format("Jan 2"))
+		}
+		if day.LoggedMinutes != want.logged || day.ExpectedMinutes != want.expected {
+			t.Errorf("%s: expected %d/%d minutes, got %d/%d",
+				day.Date.Format("Mon"), want.logged, want.expected, day.LoggedMinutes, day.ExpectedMinutes)
+		}
+	}
+}
+
+func TestCalculateDeficit_UsesTimezoneOfNow(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("Timezone data not available: %v", err)
+	}
+
+	now := time.Date(2024, 1, 16, 12, 0, 0, 0, tokyo)
+	start := timeutil.StartOfDay(now)
+	end := timeutil.EndOfDay(now)
+
+	// 20:00 UTC on Monday is 05:00 on Tuesday in Tokyo
+	entries := []entry.Entry{
+		{Timestamp: time.Date(2024, 1, 15, 20, 0, 0, 0, time.UTC), DurationMinutes: 90},
+	}
+
+	days := calculateDeficit(entries, testWorkingHours, start, end, now)
+	if len(days) != 1 || days[0].LoggedMinutes != 90 {
+		t.Errorf("Expected 90 minutes logged on Tuesday in Tokyo, got %+v", days)
+	}
+}
+
+func TestFormatSignedDuration(t *testing.T) {
+	tests := map[int]string{
+		0:    "0m",
+		15:   "+15m",
+		-30:  "-30m",
+		120:  "+2h",
+		-195: "-3h 15m",
+	}
+	for minutes, want := range tests {
+		if got := formatSignedDuration(minutes); got != want {
+			t.Errorf("formatSignedDuration(%d) = %q, expected %q", minutes, got, want)
+		}
+	}
+}
+
+func TestShowDeficit_PreviousWeek(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	weekStart := timeutil.StartOfWeek(time.Now().AddDate(0, 0, -7))
+	entries := []entry.Entry{
+		{Timestamp: weekStart.Add(9 * time.Hour), Description: "monday work", DurationMinutes: 480},
+		{Timestamp: weekStart.AddDate(0, 0, 1).Add(9 * time.Hour), Description: "tuesday work", DurationMinutes: 300},
+		{Timestamp: weekStart.AddDate(0, 0, 4).Add(9 * time.Hour), Description: "friday work", DurationMinutes: 390},
+	}
+	for _, e := range entries {
+		e.RawInput = formatRawInput(e)
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.WorkingHours = testWorkingHours
+	d, stdout, stderr := testDepsWithConfig(storagePath, cfg)
+	SetDeps(d)
+	defer ResetDeps()
+	resetDeficitFlags(deficitCmd)
+	defer resetDeficitFlags(deficitCmd)
+	_ = deficitCmd.Flags().Set("prev-week", "true")
+
+	showDeficit(deficitCmd)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{
+		"Working hours for previous week",
+		"5h / 8h",
+		"-3h",
+		"6h 30m / 6h",
+		"+30m",
+		// 8h + 5h + 6h 30m = 19h 30m logged of 38h expected
+		"Week deficit: -18h 30m (logged 19h 30m of 38h expected)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if lines := strings.Count(output, "\n"); lines != 11 {
+		t.Errorf("Expected a header, 7 days and a total (11 lines), got %d:\n%s", lines, output)
+	}
+}
+
+func TestShowDeficit_NoSchedule(t *testing.T) {
+	d, stdout, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	resetDeficitFlags(deficitCmd)
+
+	showDeficit(deficitCmd)
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if stdout.Len() > 0 {
+		t.Errorf("Expected no stdout output, got: %s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "No working hours configured") ||
+		!strings.Contains(stderr.String(), "[working_hours]") {
+		t.Errorf("Expected error pointing to working_hours, got: %s", stderr.String())
+	}
+}
+
+func TestShowDeficit_MutuallyExclusiveFlags(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.WorkingHours = testWorkingHours
+	d, _, stderr := testDepsWithConfig(filepath.Join(t.TempDir(), "entries.jsonl"), cfg)
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	resetDeficitFlags(deficitCmd)
+	defer resetDeficitFlags(deficitCmd)
+	_ = deficitCmd.Flags().Set("this-week", "true")
+	_ = deficitCmd.Flags().Set("this-month", "true")
+
+	showDeficit(deficitCmd)
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "mutually exclusive") {
+		t.Errorf("Expected mutual exclusivity error, got: %s", stderr.String())
+	}
+}
@@ -439,8 +439,7 @@ func TestDeleteEntry_StoragePathError(t *testing.T) {
 }
 
 func TestDeleteEntry_ReadEntriesError(t *testing.T) {
-	// Use a path to a directory (not a file) to cause read error
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
 	exitCalled := false
 	stderr := &bytes.Buffer{}
@@ -450,7 +449,7 @@ func TestDeleteEntry_ReadEntriesError(t *testing.T) {
 		Stdin:  strings.NewReader(""),
 		Exit:   func(code int) { exitCalled = true },
 		StoragePath: func() (string, error) {
-			return tmpDir, nil // path to directory, not file
+			return storagePath, nil
 		},
 	}
 	SetDeps(d)
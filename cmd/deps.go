@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/didlib"
+	"github.com/xolan/did/internal/osutil"
 	"github.com/xolan/did/internal/storage"
 	"github.com/xolan/did/internal/timer"
+	"github.com/xolan/did/internal/timeutil"
 )
 
 // Deps holds external dependencies for CLI commands, enabling testability.
@@ -19,39 +23,94 @@ type Deps struct {
 	StoragePath func() (string, error)
 	TimerPath   func() (string, error)
 	Config      config.Config
+
+	// WarningStatePath locates the file throttling corrupted-line warnings.
+	// When nil, warnings are shown on every read.
+	WarningStatePath func() (string, error)
+
+	// ReadClipboard returns the text on the system clipboard for 'did paste'.
+	// When nil, no clipboard is available.
+	ReadClipboard func() (string, error)
+
+	// PrepareStorage creates the directory of the storage path before entries
+	// are added. Commands that only read never call it. When nil, nothing is created.
+	PrepareStorage func(storagePath string) error
+
+	// Getwd returns the working directory, matched against the configured
+	// workspaces when logging. When nil, no workspace applies.
+	Getwd func() (string, error)
 }
 
 // DefaultDeps returns the default production dependencies.
 func DefaultDeps() *Deps {
-	// Load config from file or use defaults
+	// Load config from file (with DID_* environment overrides) or use defaults
 	// Note: We don't call os.Exit() here to allow tests to work.
 	// Config validation happens in ValidateConfigOnStartup() which is called from main.
 	cfg := config.DefaultConfig()
 	configPath, err := config.GetConfigPath()
 	if err == nil {
 		// Try to load config from file
-		if loadedCfg, err := config.LoadOrDefault(configPath); err == nil {
+		if loadedCfg, err := config.LoadWithEnv(configPath); err == nil {
 			cfg = loadedCfg
 		}
 		// If there's an error, we use default config.
 		// Validation will happen in ValidateConfigOnStartup() for production.
 	}
+	setDisplayLocation(cfg.Timezone)
 
 	return &Deps{
 		Stdout:      os.Stdout,
 		Stderr:      os.Stderr,
 		Stdin:       os.Stdin,
 		Exit:        os.Exit,
-		StoragePath: storage.GetStoragePath,
+		StoragePath: func() (string, error) { return storage.ResolveStoragePath(cfg.StoragePath) },
 		TimerPath:   timer.GetTimerPath,
 		Config:      cfg,
+
+		WarningStatePath: storage.GetWarningStatePath,
+		ReadClipboard:    osutil.ReadClipboard,
+		PrepareStorage:   storage.EnsureStorageDir,
+		Getwd:            os.Getwd,
+	}
+}
+
+// Store opens the entries store at StoragePath, writing entries as the
+// config's durable_writes setting asks.
+func (d *Deps) Store() (*didlib.Store, error) {
+	storagePath, err := d.StoragePath()
+	if err != nil {
+		return nil, err
 	}
+	return didlib.OpenWithOptions(storagePath, didlib.Options{DurableWrites: d.Config.DurableWrites})
 }
 
-// ValidateConfigOnStartup checks if the config file is valid and shows helpful
-// error messages if not. This should be called from main() before executing commands.
+// prepareStorage creates the storage directory for a command that adds entries
+func (d *Deps) prepareStorage(storagePath string) error {
+	if d.PrepareStorage == nil {
+		return nil
+	}
+	return d.PrepareStorage(storagePath)
+}
+
+// ValidateConfigOnStartup checks if the config file and any DID_* environment
+// overrides are valid and shows helpful error messages if not. This should be
+// called from main() before executing commands.
 // Returns true if config is valid or doesn't exist, false if invalid.
 func ValidateConfigOnStartup() bool {
+	return validateConfigForArgs(os.Args[1:])
+}
+
+// validateConfigForArgs validates the config for the command line args. A
+// --config flag in args selects the config file, reloading deps from it; a
+// config file chosen with --config or DID_CONFIG must exist. Commands that
+// create the config file skip validation, so they can replace an invalid one.
+func validateConfigForArgs(args []string) bool {
+	if path := configFlagValue(args); path != "" {
+		config.SetConfigPath(path)
+		// deps was created before the flags were known
+		deps = DefaultDeps()
+	}
+
 	configPath, err := config.GetConfigPath()
 	if err != nil {
 		// Fatal error getting config path
@@ -61,27 +120,81 @@ func ValidateConfigOnStartup() bool {
 		return false
 	}
 
+	// Commands that write the config file must work without one, and must be
+	// able to replace a broken one (did config init --force)
+	if createsConfig(args) {
+		return true
+	}
+
+	if source := config.ConfigPathSource(); source != "" {
+		if _, err := os.Stat(configPath); os.IsNotExist(err) {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: Config file not found: %s\n", configPath)
+			_, _ = fmt.Fprintf(os.Stderr, "Details: the config file was chosen with %s\n", source)
+			_, _ = fmt.Fprintln(os.Stderr, "Hint: Create it with 'did config init', or fix the path")
+			return false
+		}
+	}
+
 	// Try to load config
-	_, err = config.LoadOrDefault(configPath)
+	_, err = config.LoadWithEnv(configPath)
 	if err != nil {
-		// Config file exists but is invalid - show helpful error
+		// Config file or an environment override is invalid - show helpful error
 		_, _ = fmt.Fprintln(os.Stderr, "Error: Failed to load configuration")
 		_, _ = fmt.Fprintf(os.Stderr, "Details: %v\n", err)
 		_, _ = fmt.Fprintln(os.Stderr)
 		_, _ = fmt.Fprintf(os.Stderr, "Config file: %s\n", configPath)
 		_, _ = fmt.Fprintln(os.Stderr)
-		_, _ = fmt.Fprintln(os.Stderr, "Hint: Check that your config file is valid TOML format.")
+		_, _ = fmt.Fprintln(os.Stderr, "Hint: Check that your config file is valid TOML format")
+		_, _ = fmt.Fprintf(os.Stderr, "      and that these environment variables are valid if set: %s\n", envOverrideNames())
 		_, _ = fmt.Fprintln(os.Stderr, "Valid week_start_day values: monday, sunday")
 		_, _ = fmt.Fprintln(os.Stderr, "Valid timezone examples: Local, America/New_York, Europe/London, Asia/Tokyo")
 		_, _ = fmt.Fprintln(os.Stderr)
 		_, _ = fmt.Fprintln(os.Stderr, "To see current config: did config")
-		_, _ = fmt.Fprintln(os.Stderr, "To create a fresh sample config: did config --init")
+		_, _ = fmt.Fprintln(os.Stderr, "To create a fresh sample config: did config init")
 		return false
 	}
 
+	checkConfigVersion(configPath, os.Stderr)
 	return true
 }
 
+// configFlagValue returns the value of the --config flag in args, or "" when
+// it is not given. Flags after "--" are ignored.
+func configFlagValue(args []string) string {
+	path := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return path
+		case arg == "--config" && i+1 < len(args):
+			path = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--config="):
+			path = strings.TrimPrefix(arg, "--config=")
+		}
+	}
+	return path
+}
+
+// createsConfig reports whether args run a command that writes the config
+// file, which may therefore not exist yet
+func createsConfig(args []string) bool {
+	c, _, err := rootCmd.Find(args)
+	return err == nil && (c == configInitCmd || c == initCmd)
+}
+
+// checkConfigVersion warns on w about a config file newer than this version
+// of did supports. An older file is only migrated in memory when loading, so
+// read-only commands never write the config file; 'did migrate --config-file'
+// updates it.
+func checkConfigVersion(configPath string, w io.Writer) {
+	from, err := config.FileVersion(configPath)
+	if err == nil && from > config.CurrentVersion {
+		_, _ = fmt.Fprintf(w, "Warning: Config file version %d is newer than this version of did supports (%d); unknown settings are ignored\n", from, config.CurrentVersion)
+	}
+}
+
 // deps is the global dependencies instance used by commands.
 // In production, this is DefaultDeps(). Tests can replace it.
 var deps = DefaultDeps()
@@ -89,9 +202,25 @@ var deps = DefaultDeps()
 // SetDeps sets the global dependencies (for testing).
 func SetDeps(d *Deps) {
 	deps = d
+	setDisplayLocation(d.Config.Timezone)
+}
+
+// setDisplayLocation shows the timestamps of entries, which are stored in UTC,
+// in the timezone tz
+func setDisplayLocation(tz string) {
+	storage.SetDisplayLocation(timeutil.MustLoadTimezone(tz))
 }
 
 // ResetDeps resets dependencies to defaults (for testing cleanup).
 func ResetDeps() {
 	deps = DefaultDeps()
 }
+
+// envOverrideNames returns the supported DID_* environment variable names, comma-separated
+func envOverrideNames() string {
+	names := make([]string, len(config.EnvOverrides))
+	for i, o := range config.EnvOverrides {
+		names[i] = o.Name
+	}
+	return strings.Join(names, ", ")
+}
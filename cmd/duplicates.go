@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/didlib"
+	"github.com/xolan/did/internal/query"
+)
+
+// defaultDuplicateWindow is the default --window of did duplicate-check, in minutes
+const defaultDuplicateWindow = 15
+
+// duplicateCheckCmd represents the duplicate-check command
+var duplicateCheckCmd = &cobra.Command{
+	Use:   "duplicate-check",
+	Short: "Find entries that look like duplicates",
+	Long: `Find entries that look like duplicates, for review before merging or deleting them.
+
+Entries with the same description that start on the same day less than
+--window minutes apart are shown together as a group. With --exact, only
+byte-identical entries (same timestamp, description, duration, project, tags
+and raw input, whenever each was logged) are reported.
+
+Nothing is changed: review each group and combine it with 'did merge' or
+remove entries with 'did delete'. The indices are the ones shown by 'did'.
+
+Examples:
+  did duplicate-check                 Same description within 15 minutes
+  did duplicate-check --window 60     Same description within an hour
+  did duplicate-check --exact         Only byte-identical entries`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkDuplicates(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(duplicateCheckCmd)
+
+	duplicateCheckCmd.Flags().Int("window", defaultDuplicateWindow, "Report entries with the same description starting less than this many minutes apart")
+	duplicateCheckCmd.Flags().Bool("exact", false, "Only report byte-identical entries")
+}
+
+// duplicateGroup is a set of entries that look like duplicates of each other
+type duplicateGroup struct {
+	Entries []didlib.IndexedEntry
+	// Exact is set when all entries of the group are byte-identical
+	Exact bool
+}
+
+// checkDuplicates prints the groups of entries that look like duplicates
+func checkDuplicates(cmd *cobra.Command) {
+	window, _ := cmd.Flags().GetInt("window")
+	exact, _ := cmd.Flags().GetBool("exact")
+
+	if window < 1 {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: --window must be at least 1 (got %d)\n", window)
+		deps.Exit(1)
+		return
+	}
+
+	store, err := deps.Store()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return
+	}
+
+	result, err := store.ListEntries(query.Criteria{})
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", store.Path())
+		deps.Exit(1)
+		return
+	}
+
+	var groups []duplicateGroup
+	if exact {
+		groups = findExactDuplicates(result.Entries)
+	} else {
+		groups = findDuplicates(result.Entries, time.Duration(window)*time.Minute)
+	}
+
+	if len(groups) == 0 {
+		if exact {
+			_, _ = fmt.Fprintln(deps.Stdout, "No identical entries found")
+		} else {
+			_, _ = fmt.Fprintf(deps.Stdout, "No duplicate entries found (same description within %s)\n", formatDuration(window))
+		}
+		return
+	}
+
+	// Calculate width for right-aligned indices
+	maxIndex := 0
+	for _, g := range groups {
+		for _, e := range g.Entries {
+			maxIndex = max(maxIndex, e.Index)
+		}
+	}
+	indexWidth := len(fmt.Sprintf("%d", maxIndex))
+
+	_, _ = fmt.Fprintf(deps.Stdout, "Found %s of possible duplicates:\n", formatCount(len(groups), "group", "groups"))
+	for _, g := range groups {
+		_, _ = fmt.Fprintln(deps.Stdout)
+		if g.Exact {
+			_, _ = fmt.Fprintln(deps.Stdout, "Identical entries:")
+		} else {
+			span := g.Entries[len(g.Entries)-1].Timestamp.Sub(g.Entries[0].Timestamp)
+			_, _ = fmt.Fprintf(deps.Stdout, "Same description within %s:\n", formatDuration(int(span.Minutes())))
+		}
+		for _, e := range g.Entries {
+			_, _ = fmt.Fprintf(deps.Stdout, "  [%*d] %s %s  %s  (%s)\n",
+				indexWidth,
+				e.Index,
+				e.Timestamp.Format("2006-01-02"),
+				e.Timestamp.Format("15:04"),
+				formatEntryForLog(e.Description, displayProject(e.Entry), e.Tags),
+				formatDuration(e.DurationMinutes))
+		}
+	}
+	_, _ = fmt.Fprintln(deps.Stdout)
+	_, _ = fmt.Fprintln(deps.Stdout, "Hint: Combine a group with 'did merge <index>...' or remove entries with 'did delete <index>'")
+}
+
+// findDuplicates groups chronologically sorted entries with the same
+// description that start on the same day less than window after the previous
+// entry of the group. Groups are ordered by their first entry.
+func findDuplicates(entries []didlib.IndexedEntry, window time.Duration) []duplicateGroup {
+	// The open group of each description, i.e. the one the next entry may join
+	open := make(map[string]int)
+	var groups []duplicateGroup
+	for _, e := range entries {
+		if i, ok := open[e.Description]; ok {
+			last := groups[i].Entries[len(groups[i].Entries)-1]
+			if sameDay(last.Timestamp, e.Timestamp) && e.Timestamp.Sub(last.Timestamp) < window {
+				groups[i].Entries = append(groups[i].Entries, e)
+				continue
+			}
+		}
+		open[e.Description] = len(groups)
+		groups = append(groups, duplicateGroup{Entries: []didlib.IndexedEntry{e}})
+	}
+
+	var duplicates []duplicateGroup
+	for _, g := range groups {
+		if len(g.Entries) < 2 {
+			continue
+		}
+		g.Exact = true
+		for _, e := range g.Entries[1:] {
+			if entryKey(e) != entryKey(g.Entries[0]) {
+				g.Exact = false
+				break
+			}
+		}
+		duplicates = append(duplicates, g)
+	}
+	return duplicates
+}
+
+// findExactDuplicates groups byte-identical entries, ordered by their first entry
+func findExactDuplicates(entries []didlib.IndexedEntry) []duplicateGroup {
+	byKey := make(map[string]int)
+	var groups []duplicateGroup
+	for _, e := range entries {
+		key := entryKey(e)
+		if i, ok := byKey[key]; ok {
+			groups[i].Entries = append(groups[i].Entries, e)
+			continue
+		}
+		byKey[key] = len(groups)
+		groups = append(groups, duplicateGroup{Entries: []didlib.IndexedEntry{e}, Exact: true})
+	}
+
+	var duplicates []duplicateGroup
+	for _, g := range groups {
+		if len(g.Entries) > 1 {
+			duplicates = append(duplicates, g)
+		}
+	}
+	return duplicates
+}
+
+// entryKey returns the stored form of an entry, which is equal for byte-identical
+// entries. LoggedAt is left out: the same entry logged twice is still a duplicate.
+func entryKey(e didlib.IndexedEntry) string {
+	e.LoggedAt = nil
+	// Entry struct contains only JSON-safe types, so Marshal cannot fail
+	line, _ := json.Marshal(e.Entry)
+	return string(line)
+}
+
+// sameDay reports whether a and b fall on the same calendar day in a's location
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.In(a.Location()).Date()
+	return ay == by && am == bm && ad == bd
+}
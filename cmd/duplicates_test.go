@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/didlib"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// indexed returns entries with their 1-based indices, as ListEntries returns them
+func indexed(entries ...entry.Entry) []didlib.IndexedEntry {
+	result := make([]didlib.IndexedEntry, len(entries))
+	for i, e := range entries {
+		result[i] = didlib.IndexedEntry{Entry: e, Index: i + 1}
+	}
+	return result
+}
+
+// groupIndices returns the entry indices of each group
+func groupIndices(groups []duplicateGroup) [][]int {
+	var result [][]int
+	for _, g := range groups {
+		var indices []int
+		for _, e := range g.Entries {
+			indices = append(indices, e.Index)
+		}
+		result = append(result, indices)
+	}
+	return result
+}
+
+func TestFindDuplicates(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	entries := indexed(
+		entry.Entry{Timestamp: at(15, 9, 0), Description: "standup", DurationMinutes: 15, RawInput: "standup for 15m"},
+		entry.Entry{Timestamp: at(15, 9, 0), Description: "standup", DurationMinutes: 15, RawInput: "standup for 15m"},
+		entry.Entry{Timestamp: at(15, 10, 0), Description: "review", DurationMinutes: 30},
+		entry.Entry{Timestamp: at(15, 10, 10), Description: "review", DurationMinutes: 45},
+		entry.Entry{Timestamp: at(15, 10, 20), Description: "review", DurationMinutes: 30},
+		entry.Entry{Timestamp: at(15, 11, 0), Description: "review", DurationMinutes: 30},
+		entry.Entry{Timestamp: at(15, 23, 55), Description: "deploy", DurationMinutes: 10},
+		entry.Entry{Timestamp: at(16, 0, 5), Description: "deploy", DurationMinutes: 10},
+		entry.Entry{Timestamp: at(16, 9, 0), Description: "Standup", DurationMinutes: 15},
+	)
+
+	groups := findDuplicates(entries, 15*time.Minute)
+
+	// Entries chain while each is within the window of the previous one; the
+	// review 40 minutes later, the deploy after midnight and "Standup" differ
+	got := groupIndices(groups)
+	want := [][]int{{1, 2}, {3, 4, 5}}
+	if len(got) != len(want) {
+		t.Fatalf("Expected groups %v, got %v", want, got)
+	}
+	for i := range want {
+		if len(got[i]) != len(want[i]) {
+			t.Fatalf("Expected groups %v, got %v", want, got)
+		}
+		for j := range want[i] {
+			if got[i][j] != want[i][j] {
+				t.Errorf("Expected groups %v, got %v", want, got)
+			}
+		}
+	}
+	if !groups[0].Exact || groups[1].Exact {
+		t.Errorf("Expected only the first group to be exact, got %v and %v", groups[0].Exact, groups[1].Exact)
+	}
+
+	// The window is exclusive
+	if groups := findDuplicates(entries[2:4], 10*time.Minute); len(groups) != 0 {
+		t.Errorf("Expected entries exactly a window apart not to match, got %v", groupIndices(groups))
+	}
+}
+
+func TestFindExactDuplicates(t *testing.T) {
+	at := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	entries := indexed(
+		entry.Entry{Timestamp: at, Description: "standup", DurationMinutes: 15, Tags: []string{"team"}},
+		entry.Entry{Timestamp: at, Description: "standup", DurationMinutes: 15, Tags: []string{"team"}},
+		entry.Entry{Timestamp: at, Description: "standup", DurationMinutes: 20, Tags: []string{"team"}},
+		entry.Entry{Timestamp: at.Add(time.Minute), Description: "standup", DurationMinutes: 15, Tags: []string{"team"}},
+		entry.Entry{Timestamp: at, Description: "standup", DurationMinutes: 15, Tags: []string{"team"}},
+	)
+
+	groups := findExactDuplicates(entries)
+
+	if got := groupIndices(groups); len(got) != 1 || len(got[0]) != 3 || got[0][0] != 1 || got[0][1] != 2 || got[0][2] != 5 {
+		t.Errorf("Expected one group [1 2 5], got %v", got)
+	}
+}
+
+func TestCheckDuplicates(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	at := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	for _, e := range []entry.Entry{
+		{Timestamp: at, Description: "standup", DurationMinutes: 15, Project: "acme"},
+		{Timestamp: at.Add(time.Hour), Description: "review", DurationMinutes: 30},
+		{Timestamp: at, Description: "standup", DurationMinutes: 15, Project: "acme"},
+		{Timestamp: at.Add(70 * time.Minute), Description: "review", DurationMinutes: 45},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		window   string
+		exact    bool
+		expected []string
+		absent   []string
+	}{
+		{
+			name:   "default window",
+			window: "15",
+			expected: []string{
+				"Found 2 groups of possible duplicates:",
+				"Identical entries:\n  [1] 2024-01-15 09:00  standup [@acme]  (15m)\n  [3] 2024-01-15 09:00  standup [@acme]  (15m)\n",
+				"Same description within 10m:\n  [2] 2024-01-15 10:00  review  (30m)\n  [4] 2024-01-15 10:10  review  (45m)\n",
+				"Hint: Combine a group with 'did merge <index>...'",
+			},
+		},
+		{
+			name:     "exact",
+			window:   "15",
+			exact:    true,
+			expected: []string{"Found 1 group of possible duplicates:", "Identical entries:"},
+			absent:   []string{"review"},
+		},
+		{
+			name:     "small window",
+			window:   "5",
+			expected: []string{"Found 1 group of possible duplicates:", "standup"},
+			absent:   []string{"review"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, stderr := testDeps(storagePath)
+			d.Config.Timezone = "UTC"
+			SetDeps(d)
+			defer ResetDeps()
+			_ = duplicateCheckCmd.Flags().Set("window", tt.window)
+			if tt.exact {
+				_ = duplicateCheckCmd.Flags().Set("exact", "true")
+			}
+			defer func() {
+				_ = duplicateCheckCmd.Flags().Set("window", "15")
+				_ = duplicateCheckCmd.Flags().Set("exact", "false")
+			}()
+
+			checkDuplicates(duplicateCheckCmd)
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr: %s", stderr.String())
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(stdout.String(), expected) {
+					t.Errorf("Expected %q in output, got: %s", expected, stdout.String())
+				}
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(stdout.String(), absent) {
+					t.Errorf("Expected no %q in output, got: %s", absent, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCheckDuplicates_NoneFound(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	if err := storage.AppendEntry(storagePath, entry.Entry{Timestamp: time.Now(), Description: "standup", DurationMinutes: 15}); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	checkDuplicates(duplicateCheckCmd)
+
+	if expected := "No duplicate entries found (same description within 15m)\n"; stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+}
+
+func TestCheckDuplicates_InvalidWindow(t *testing.T) {
+	d, _, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	_ = duplicateCheckCmd.Flags().Set("window", "0")
+	defer func() { _ = duplicateCheckCmd.Flags().Set("window", "15") }()
+
+	checkDuplicates(duplicateCheckCmd)
+
+	if exitCode != 1 || !strings.Contains(stderr.String(), "Error: --window must be at least 1 (got 0)") {
+		t.Errorf("Expected a --window error, got exit %d: %s", exitCode, stderr.String())
+	}
+}
+
+func TestCheckDuplicates_ReadError(t *testing.T) {
+	d, _, stderr := testDeps(unreadableStoragePath(t))
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+
+	checkDuplicates(duplicateCheckCmd)
+
+	if exitCode != 1 || !strings.Contains(stderr.String(), "Error: Failed to read entries from storage") {
+		t.Errorf("Expected a read error, got exit %d: %s", exitCode, stderr.String())
+	}
+}
@@ -1,16 +1,26 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
 	"github.com/xolan/did/internal/entry"
-	"github.com/xolan/did/internal/filter"
+	"github.com/xolan/did/internal/query"
+	"github.com/xolan/did/internal/stats"
 	"github.com/xolan/did/internal/storage"
 	"github.com/xolan/did/internal/timeutil"
 )
@@ -24,12 +34,40 @@ var exportCmd = &cobra.Command{
 Available formats:
   json    Export entries as JSON
   csv     Export entries as CSV
+  ndjson  Export entries as JSON, one per line, optionally following new entries
+
+Order:
+  Entries are exported oldest first; --order desc exports them newest first.
+  Entries with the same timestamp keep their order in the storage file, so
+  the same entries always export identically.
+
+Rounding:
+  --round-display N exports every duration rounded to the nearest N minutes
+  (never below N) without changing the stored entries. round_display_minutes
+  in the config does not apply to exports, so backups stay exact.
+
+Output:
+  By default the export is written to stdout. Use --output (-o) to write it
+  to a file instead; the file is written atomically and an existing file is
+  only replaced when --force is given. Use --count to check how many entries
+  match the filters without exporting anything.
+
+  --split-by project, tag or day writes one file per group into --output-dir
+  instead, named after the group (e.g. acme.csv, or no-project.csv for
+  entries without a project), and reports each file written. An entry with
+  several tags goes into the file of each tag. Names that map to the same
+  file name (e.g. acme.co and acme_co) get a numbered suffix (acme_co-2.csv).
 
 Examples:
   did export json                Export all entries as JSON
   did export json > backup.json  Export to file
+  did export json -o backup.json Export to file and print a summary
   did export csv                 Export all entries as CSV
-  did export csv > entries.csv   Export to file`,
+  did export csv > entries.csv   Export to file
+  did export csv -o entries.csv --force   Overwrite an existing file
+  did export json --last 30 @acme --count Count matching entries only
+  did export csv --last 1m --split-by project --output-dir invoices
+  did export ndjson --follow     Stream entries as they are logged`,
 }
 
 // exportJSONCmd represents the export json command
@@ -41,6 +79,11 @@ var exportJSONCmd = &cobra.Command{
 Output includes metadata (export timestamp, total entries, filter criteria)
 and an array of entry objects.
 
+With --include-summary the document also has a summary of the exported
+entries: total minutes, totals per project, tag and day, and the first and
+last timestamp. Like 'did stats', it leaves out deleted entries and entries
+with a zero or negative duration.
+
 Date Filtering:
   Use --from and --to to filter by date range
   Use --last to filter by relative days (e.g., 'last 7 days')
@@ -60,7 +103,8 @@ Examples:
   did export json --project acme           Export entries for project 'acme'
   did export json --tag review             Export entries tagged 'review'
   did export json @acme #review            Export using shorthand syntax
-  did export json --last 30 --project acme Export last 30 days for project`,
+  did export json --last 30 --project acme Export last 30 days for project
+  did export json --last 30 --include-summary   Add totals per project, tag and day`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Parse shorthand filters (@project, #tag) and remove them from args
 		_ = parseShorthandFilters(cmd, args)
@@ -68,13 +112,64 @@ Examples:
 	},
 }
 
+// exportNDJSONCmd represents the export ndjson command
+var exportNDJSONCmd = &cobra.Command{
+	Use:   "ndjson",
+	Short: "Export time entries as newline-delimited JSON",
+	Long: `Export time entries as newline-delimited JSON (NDJSON): one entry object
+per line, without metadata, for log shippers and line-oriented tools.
+
+Following:
+  With --follow the matching entries are written first and then the storage
+  file is watched (polled every second) and each entry appended to it is
+  written as soon as it is complete, until interrupted with Ctrl+C. Entries
+  are tracked by byte offset, so none is written twice while entries are
+  only appended. When the file is rewritten instead, e.g. by 'did edit' or
+  'did delete', a {"reset":true} line is written followed by all matching
+  entries again, so consumers can discard what they received before.
+
+  Corrupted lines are reported on stderr as one JSON object per line, e.g.
+  {"warning":"corrupted line","line":12,"content":"...","error":"..."}.
+  --follow needs a single storage file and writes to stdout only, so it
+  can't be combined with --output, --split-by, --count or --order desc.
+
+Date Filtering:
+  Use --from and --to to filter by date range
+  Use --last to filter by relative days (e.g., 'last 7 days')
+
+Project and Tag Filtering:
+  Use --project to filter by project
+  Use --tag to filter by tags (can be repeated)
+  Use @project shorthand for --project
+  Use #tag shorthand for --tag
+
+Examples:
+  did export ndjson                        Export all entries as NDJSON
+  did export ndjson --last 7 @acme         Export last 7 days for project
+  did export ndjson --follow               Export all entries, then new ones
+  did export ndjson --follow #billable | vector --config ship.toml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Parse shorthand filters (@project, #tag) and remove them from args
+		_ = parseShorthandFilters(cmd, args)
+		exportNDJSON(cmd)
+	},
+}
+
 // exportCSVCmd represents the export csv command
 var exportCSVCmd = &cobra.Command{
 	Use:   "csv",
 	Short: "Export time entries as CSV",
 	Long: `Export all time entries to CSV format.
 
-Output is in standard CSV format with headers.
+Output is in standard CSV format with headers. Use --no-header to write only
+the data rows, e.g. to append to an existing file with '>>'.
+
+Spreadsheet compatibility:
+  Use --bom to start the file with a UTF-8 byte order mark, which Excel on
+  Windows needs to read non-ASCII characters correctly. Use --delimiter to
+  separate fields with another character, e.g. ';' for locales that use a
+  decimal comma. With a ';' delimiter the tags in the tags column are
+  separated by ',' instead of ';'.
 
 Date Filtering:
   Use --from and --to to filter by date range
@@ -95,7 +190,9 @@ Examples:
   did export csv --project acme            Export entries for project 'acme'
   did export csv --tag review              Export entries tagged 'review'
   did export csv @acme #review             Export using shorthand syntax
-  did export csv --last 30 --project acme  Export last 30 days for project`,
+  did export csv --last 30 --project acme  Export last 30 days for project
+  did export csv --last 1 --no-header >> entries.csv   Append yesterday's rows
+  did export csv --bom --delimiter ';' -o entries.csv  Export for Excel`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Parse shorthand filters (@project, #tag) and remove them from args
 		_ = parseShorthandFilters(cmd, args)
@@ -107,239 +204,350 @@ func init() {
 	rootCmd.AddCommand(exportCmd)
 	exportCmd.AddCommand(exportJSONCmd)
 	exportCmd.AddCommand(exportCSVCmd)
+	exportCmd.AddCommand(exportNDJSONCmd)
+
+	// Output flags are persistent so every export format supports them
+	exportCmd.PersistentFlags().StringP("output", "o", "", "Write the export to a file instead of stdout")
+	exportCmd.PersistentFlags().Bool("force", false, "Overwrite the --output file if it already exists")
+	exportCmd.PersistentFlags().Bool("count", false, "Only print how many entries match the filters (to stderr), without exporting")
+	exportCmd.PersistentFlags().String("split-by", "", "Write one file per project, tag or day into --output-dir")
+	exportCmd.PersistentFlags().String("output-dir", "", "Directory for the files written with --split-by")
 
 	// Date filtering flags for JSON export
 	exportJSONCmd.Flags().String("from", "", "Start date for filtering (YYYY-MM-DD or DD/MM/YYYY)")
 	exportJSONCmd.Flags().String("to", "", "End date for filtering (YYYY-MM-DD or DD/MM/YYYY)")
-	exportJSONCmd.Flags().Int("last", 0, "Filter by last N days (e.g., --last 7 for last 7 days)")
+	exportJSONCmd.Flags().String("last", "", "Filter by last N days, or Nw weeks / Nm months (e.g., --last 7, --last 2w)")
+	exportJSONCmd.Flags().Bool("include-summary", false, "Add totals per project, tag and day of the exported entries")
 
 	// Date filtering flags for CSV export
 	exportCSVCmd.Flags().String("from", "", "Start date for filtering (YYYY-MM-DD or DD/MM/YYYY)")
 	exportCSVCmd.Flags().String("to", "", "End date for filtering (YYYY-MM-DD or DD/MM/YYYY)")
-	exportCSVCmd.Flags().Int("last", 0, "Filter by last N days (e.g., --last 7 for last 7 days)")
+	exportCSVCmd.Flags().String("last", "", "Filter by last N days, or Nw weeks / Nm months (e.g., --last 7, --last 2w)")
+	exportCSVCmd.Flags().Bool("no-header", false, "Omit the header row and write only data rows")
+	exportCSVCmd.Flags().Bool("bom", false, "Start the output with a UTF-8 byte order mark (for Excel)")
+	exportCSVCmd.Flags().String("delimiter", ",", "Field delimiter, a single character such as ';' or '\\t'")
+
+	// Date filtering flags for NDJSON export
+	exportNDJSONCmd.Flags().String("from", "", "Start date for filtering (YYYY-MM-DD or DD/MM/YYYY)")
+	exportNDJSONCmd.Flags().String("to", "", "End date for filtering (YYYY-MM-DD or DD/MM/YYYY)")
+	exportNDJSONCmd.Flags().String("last", "", "Filter by last N days, or Nw weeks / Nm months (e.g., --last 7, --last 2w)")
+	exportNDJSONCmd.Flags().Bool("follow", false, "Keep running and write entries appended to the storage file")
+
+	for _, cmd := range []*cobra.Command{exportJSONCmd, exportCSVCmd, exportNDJSONCmd} {
+		addOrderFlag(cmd)
+		addRoundDisplayFlag(cmd)
+	}
 
 	// Note: --project and --tag flags are inherited from root command's PersistentFlags
 }
 
 // exportJSON handles the export json command logic
 func exportJSON(cmd *cobra.Command) {
-	// Parse date filtering flags
-	fromStr, _ := cmd.Flags().GetString("from")
-	toStr, _ := cmd.Flags().GetString("to")
-	lastDays, _ := cmd.Flags().GetInt("last")
-
-	// Validate flag combinations
-	if lastDays > 0 && (fromStr != "" || toStr != "") {
-		_, _ = fmt.Fprintln(deps.Stderr, "Error: Cannot use --last with --from or --to")
-		_, _ = fmt.Fprintln(deps.Stderr, "Use either --last N or --from/--to, not both")
-		deps.Exit(1)
+	outputPath, force, countOnly := exportOutputOptions(cmd)
+	splitBy, outputDir, ok := exportSplitOptions(cmd, outputPath)
+	if !ok {
 		return
 	}
-
-	// Parse date range
-	var startDate, endDate time.Time
-	var hasDateFilter bool
-
-	if lastDays > 0 {
-		// Use relative days
-		now := time.Now()
-		endDate = timeutil.EndOfDay(now)
-		startDate = timeutil.StartOfDay(now.AddDate(0, 0, -(lastDays - 1)))
-		hasDateFilter = true
-	} else if fromStr != "" || toStr != "" {
-		// Use explicit date range
-		hasDateFilter = true
-
-		// Parse from date
-		if fromStr != "" {
-			var err error
-			startDate, err = timeutil.ParseDate(fromStr)
-			if err != nil {
-				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --from date: %v\n", err)
-				deps.Exit(1)
-				return
-			}
-		} else {
-			// No from date: use the beginning of time
-			startDate = time.Time{}
-		}
-
-		// Parse to date
-		if toStr != "" {
-			var err error
-			toDate, err := timeutil.ParseDate(toStr)
-			if err != nil {
-				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --to date: %v\n", err)
-				deps.Exit(1)
-				return
-			}
-			endDate = timeutil.EndOfDay(toDate)
-		} else {
-			// No to date: use now
-			endDate = timeutil.EndOfDay(time.Now())
-		}
+	if !countOnly && splitBy == "" && !checkExportOutputPath(outputPath, force) {
+		return
 	}
 
-	// Get storage path
-	storagePath, err := deps.StoragePath()
-	if err != nil {
-		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
-		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
-		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
-		deps.Exit(1)
+	c, entries, ok := readExportEntries(cmd)
+	if !ok {
 		return
 	}
 
-	// Read all entries from storage
-	result, err := storage.ReadEntriesWithWarnings(storagePath)
-	if err != nil {
-		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
-		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
-		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", storagePath)
-		deps.Exit(1)
+	if countOnly {
+		printExportCount(len(entries), c)
 		return
 	}
 
-	// Display warnings about corrupted lines to stderr
-	if len(result.Warnings) > 0 {
-		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Found %d corrupted line(s) in storage file:\n", len(result.Warnings))
-		for _, warning := range result.Warnings {
-			_, _ = fmt.Fprintln(deps.Stderr, formatCorruptionWarning(warning))
-		}
-		_, _ = fmt.Fprintln(deps.Stderr)
+	if splitBy != "" {
+		writeSplitExport(splitBy, outputDir, ".json", force, entries, func(out io.Writer, group exportGroup) bool {
+			return writeExportJSON(cmd, out, c, group.Entries, &exportSplitJSON{By: splitBy, Group: group.Name})
+		})
+		return
 	}
 
-	// Apply date filtering if specified
-	entries := result.Entries
-	if hasDateFilter {
-		filtered := make([]entry.Entry, 0)
-		for _, e := range entries {
-			if timeutil.IsInRange(e.Timestamp, startDate, endDate) {
-				filtered = append(filtered, e)
-			}
-		}
-		entries = filtered
+	var out io.Writer = deps.Stdout
+	var buf bytes.Buffer
+	if outputPath != "" {
+		out = &buf
+	}
+	if !writeExportJSON(cmd, out, c, entries, nil) {
+		return
 	}
 
-	// Get project and tag filter flags from root persistent flags
-	projectFilter, _ := cmd.Root().PersistentFlags().GetString("project")
-	tagFilters, _ := cmd.Root().PersistentFlags().GetStringSlice("tag")
-
-	// Apply project and tag filters if specified
-	f := filter.NewFilter("", projectFilter, tagFilters)
-	if !f.IsEmpty() {
-		entries = filter.FilterEntries(entries, f)
+	if outputPath != "" {
+		writeExportOutput(outputPath, buf.Bytes(), len(entries))
 	}
+}
 
+// exportSplitJSON describes the group of a file written by export json --split-by
+type exportSplitJSON struct {
+	By    string `json:"by"`
+	Group string `json:"group"`
+}
+
+// writeExportJSON writes the JSON export document of entries to out: metadata
+// describing the criteria c, the entries and, with --include-summary, their
+// totals. split names the group of a --split-by file. Returns false after
+// reporting an error.
+func writeExportJSON(cmd *cobra.Command, out io.Writer, c query.Criteria, entries []entry.Entry, split *exportSplitJSON) bool {
 	// Create output structure with metadata
 	output := struct {
 		Metadata struct {
 			ExportTimestamp time.Time              `json:"export_timestamp"`
 			TotalEntries    int                    `json:"total_entries"`
 			FilterCriteria  map[string]interface{} `json:"filter_criteria"`
+			Split           *exportSplitJSON       `json:"split,omitempty"`
 		} `json:"metadata"`
-		Entries []entry.Entry `json:"entries"`
+		Summary *exportSummaryJSON `json:"summary,omitempty"`
+		Entries []entry.Entry      `json:"entries"`
 	}{}
 
 	output.Metadata.ExportTimestamp = time.Now()
 	output.Metadata.TotalEntries = len(entries)
 	output.Metadata.FilterCriteria = make(map[string]interface{})
+	output.Metadata.Split = split
 
 	// Add date filter criteria to metadata if applicable
-	if hasDateFilter {
-		if lastDays > 0 {
-			output.Metadata.FilterCriteria["last_days"] = lastDays
-		} else {
-			if fromStr != "" {
-				output.Metadata.FilterCriteria["from"] = startDate.Format("2006-01-02")
-			}
-			if toStr != "" {
-				output.Metadata.FilterCriteria["to"] = endDate.Format("2006-01-02")
-			}
+	if c.LastDays > 0 {
+		output.Metadata.FilterCriteria["last_days"] = c.LastDays
+	} else if c.Last != "" {
+		output.Metadata.FilterCriteria["last"] = c.Last
+	} else {
+		if c.From != "" {
+			output.Metadata.FilterCriteria["from"] = c.Period.Start.Format("2006-01-02")
+		}
+		if c.To != "" {
+			output.Metadata.FilterCriteria["to"] = c.Period.End.Format("2006-01-02")
 		}
 	}
 
 	// Add project and tag filter criteria to metadata if applicable
-	if projectFilter != "" {
-		output.Metadata.FilterCriteria["project"] = projectFilter
+	if c.Project != "" {
+		output.Metadata.FilterCriteria["project"] = c.Project
 	}
-	if len(tagFilters) > 0 {
-		output.Metadata.FilterCriteria["tags"] = tagFilters
+	if c.Client != "" {
+		output.Metadata.FilterCriteria["client"] = c.Client
+	}
+	if len(c.Tags) > 0 {
+		output.Metadata.FilterCriteria["tags"] = c.Tags
 	}
 
+	if includeSummary, _ := cmd.Flags().GetBool("include-summary"); includeSummary {
+		summary := newExportSummary(entries)
+		output.Summary = &summary
+	}
 	output.Entries = entries
 
 	// Encode to JSON with pretty printing
-	encoder := json.NewEncoder(deps.Stdout)
+	encoder := json.NewEncoder(out)
 	encoder.SetIndent("", "  ")
 	if err := encoder.Encode(output); err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to encode JSON output")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
 		deps.Exit(1)
-		return
+		return false
+	}
+	return true
+}
+
+// exportSummaryJSON is the summary of export json --include-summary
+type exportSummaryJSON struct {
+	TotalMinutes   int               `json:"total_minutes"`
+	EntryCount     int               `json:"entry_count"`
+	FirstTimestamp *time.Time        `json:"first_timestamp"`
+	LastTimestamp  *time.Time        `json:"last_timestamp"`
+	Projects       []metadataSummary `json:"projects"`
+	Tags           []metadataSummary `json:"tags"`
+	Days           []dayJSON         `json:"days"`
+}
+
+// newExportSummary totals the active entries with a positive duration, using
+// the same breakdowns as the stats command. Days without entries are left out.
+func newExportSummary(entries []entry.Entry) exportSummaryJSON {
+	summary := exportSummaryJSON{Days: []dayJSON{}}
+	var counted []entry.Entry
+	for _, e := range entries {
+		if e.DeletedAt != nil || !e.HasValidDuration() {
+			continue
+		}
+		counted = append(counted, e)
+		summary.TotalMinutes += e.DurationMinutes
+		if summary.FirstTimestamp == nil || e.Timestamp.Before(*summary.FirstTimestamp) {
+			first := e.Timestamp
+			summary.FirstTimestamp = &first
+		}
+		if summary.LastTimestamp == nil || e.Timestamp.After(*summary.LastTimestamp) {
+			last := e.Timestamp
+			summary.LastTimestamp = &last
+		}
 	}
+	summary.EntryCount = len(counted)
+
+	// The entries already match the filters, so the breakdowns cover their whole span
+	start, end := time.Time{}, time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+	summary.Projects = sortedSummaries(projectSummaries(stats.CalculateProjectBreakdown(counted, start, end), true))
+	summary.Tags = sortedSummaries(tagSummaries(stats.CalculateTagBreakdown(counted, start, end), true))
+	if summary.LastTimestamp != nil {
+		for _, day := range stats.DailyTotals(counted, time.Time{}, *summary.LastTimestamp, configuredLocation()) {
+			if day.Minutes > 0 {
+				summary.Days = append(summary.Days, dayJSON{
+					Date:              day.Date.Format("2006-01-02"),
+					Minutes:           day.Minutes,
+					CumulativeMinutes: day.CumulativeMinutes,
+				})
+			}
+		}
+	}
+	return summary
 }
 
 // exportCSV handles the export csv command logic
 func exportCSV(cmd *cobra.Command) {
-	// Parse date filtering flags
-	fromStr, _ := cmd.Flags().GetString("from")
-	toStr, _ := cmd.Flags().GetString("to")
-	lastDays, _ := cmd.Flags().GetInt("last")
-
-	// Validate flag combinations
-	if lastDays > 0 && (fromStr != "" || toStr != "") {
-		_, _ = fmt.Fprintln(deps.Stderr, "Error: Cannot use --last with --from or --to")
-		_, _ = fmt.Fprintln(deps.Stderr, "Use either --last N or --from/--to, not both")
+	outputPath, force, countOnly := exportOutputOptions(cmd)
+	splitBy, outputDir, ok := exportSplitOptions(cmd, outputPath)
+	if !ok {
+		return
+	}
+	if !countOnly && splitBy == "" && !checkExportOutputPath(outputPath, force) {
+		return
+	}
+
+	delimiterStr, _ := cmd.Flags().GetString("delimiter")
+	delimiter, ok := parseCSVDelimiter(delimiterStr)
+	if !ok {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --delimiter value: %q\n", delimiterStr)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use a single character such as ',' or ';', or '\\t' for tabs")
 		deps.Exit(1)
 		return
 	}
 
-	// Parse date range
-	var startDate, endDate time.Time
-	var hasDateFilter bool
-
-	if lastDays > 0 {
-		// Use relative days
-		now := time.Now()
-		endDate = timeutil.EndOfDay(now)
-		startDate = timeutil.StartOfDay(now.AddDate(0, 0, -(lastDays - 1)))
-		hasDateFilter = true
-	} else if fromStr != "" || toStr != "" {
-		// Use explicit date range
-		hasDateFilter = true
-
-		// Parse from date
-		if fromStr != "" {
-			var err error
-			startDate, err = timeutil.ParseDate(fromStr)
-			if err != nil {
-				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --from date: %v\n", err)
-				deps.Exit(1)
-				return
-			}
-		} else {
-			// No from date: use the beginning of time
-			startDate = time.Time{}
-		}
+	c, entries, ok := readExportEntries(cmd)
+	if !ok {
+		return
+	}
 
-		// Parse to date
-		if toStr != "" {
-			var err error
-			toDate, err := timeutil.ParseDate(toStr)
-			if err != nil {
-				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --to date: %v\n", err)
-				deps.Exit(1)
-				return
-			}
-			endDate = timeutil.EndOfDay(toDate)
-		} else {
-			// No to date: use now
-			endDate = timeutil.EndOfDay(time.Now())
+	if countOnly {
+		printExportCount(len(entries), c)
+		return
+	}
+
+	if splitBy != "" {
+		writeSplitExport(splitBy, outputDir, ".csv", force, entries, func(out io.Writer, group exportGroup) bool {
+			return writeExportCSV(cmd, out, delimiter, group.Entries)
+		})
+		return
+	}
+
+	var out io.Writer = deps.Stdout
+	var buf bytes.Buffer
+	if outputPath != "" {
+		out = &buf
+	}
+	if !writeExportCSV(cmd, out, delimiter, entries) {
+		return
+	}
+
+	if outputPath != "" {
+		writeExportOutput(outputPath, buf.Bytes(), len(entries))
+	}
+}
+
+// followPollInterval is how often export ndjson --follow checks the storage
+// file for new entries. Tests shorten it.
+var followPollInterval = time.Second
+
+// exportNDJSON handles the export ndjson command logic
+func exportNDJSON(cmd *cobra.Command) {
+	if follow, _ := cmd.Flags().GetBool("follow"); follow {
+		followNDJSON(cmd)
+		return
+	}
+
+	outputPath, force, countOnly := exportOutputOptions(cmd)
+	splitBy, outputDir, ok := exportSplitOptions(cmd, outputPath)
+	if !ok {
+		return
+	}
+	if !countOnly && splitBy == "" && !checkExportOutputPath(outputPath, force) {
+		return
+	}
+
+	c, entries, ok := readExportEntries(cmd)
+	if !ok {
+		return
+	}
+
+	if countOnly {
+		printExportCount(len(entries), c)
+		return
+	}
+
+	if splitBy != "" {
+		writeSplitExport(splitBy, outputDir, ".ndjson", force, entries, func(out io.Writer, group exportGroup) bool {
+			return writeExportNDJSON(out, group.Entries)
+		})
+		return
+	}
+
+	var out io.Writer = deps.Stdout
+	var buf bytes.Buffer
+	if outputPath != "" {
+		out = &buf
+	}
+	if !writeExportNDJSON(out, entries) {
+		return
+	}
+
+	if outputPath != "" {
+		writeExportOutput(outputPath, buf.Bytes(), len(entries))
+	}
+}
+
+// writeExportNDJSON writes each entry to out as a JSON object on its own line.
+// Returns false after reporting an error.
+func writeExportNDJSON(out io.Writer, entries []entry.Entry) bool {
+	encoder := json.NewEncoder(out)
+	for _, e := range entries {
+		if err := encoder.Encode(e); err != nil {
+			_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to write NDJSON output")
+			_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+			deps.Exit(1)
+			return false
 		}
 	}
+	return true
+}
+
+// followNDJSON writes the entries matching the flags of cmd as NDJSON and
+// then the entries appended to the storage file, until interrupted
+// (export ndjson --follow)
+func followNDJSON(cmd *cobra.Command) {
+	outputPath, _, countOnly := exportOutputOptions(cmd)
+	splitBy, _ := cmd.InheritedFlags().GetString("split-by")
+	desc, ok := descendingOrder(cmd)
+	if !ok {
+		return
+	}
+	if outputPath != "" || splitBy != "" || countOnly || desc {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: --follow cannot be combined with --output, --split-by, --count or --order desc")
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: --follow writes entries to stdout, oldest first")
+		deps.Exit(1)
+		return
+	}
+
+	c, ok := resolveQuery(cmd)
+	if !ok {
+		return
+	}
+	roundStep, ok := roundDisplayStep(cmd, 0)
+	if !ok {
+		return
+	}
 
-	// Get storage path
 	storagePath, err := deps.StoragePath()
 	if err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
@@ -348,55 +556,105 @@ func exportCSV(cmd *cobra.Command) {
 		deps.Exit(1)
 		return
 	}
+	if storage.IsDirectory(storagePath) {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: --follow needs a single storage file")
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: %s is a storage directory; export without --follow instead\n", storagePath)
+		deps.Exit(1)
+		return
+	}
 
-	// Read all entries from storage
-	result, err := storage.ReadEntriesWithWarnings(storagePath)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	followStorage(ctx, storage.NewTail(storagePath), c, roundStep, followPollInterval)
+}
+
+// followStorage writes the entries of tail matching c as NDJSON, and then
+// checks tail for new entries every interval until ctx is done or writing
+// fails
+func followStorage(ctx context.Context, tail *storage.Tail, c query.Criteria, roundStep int, interval time.Duration) {
+	if !emitTailEntries(tail, c, roundStep) {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !emitTailEntries(tail, c, roundStep) {
+				return
+			}
+		}
+	}
+}
+
+// followWarningJSON is a corrupted line reported by export ndjson --follow
+type followWarningJSON struct {
+	Warning string `json:"warning"`
+	Line    int    `json:"line"`
+	Content string `json:"content"`
+	Error   string `json:"error"`
+}
+
+// emitTailEntries reads the entries appended to tail since its previous read
+// and writes those matching c as NDJSON, after a {"reset":true} line when the
+// file was rewritten. Corrupted lines are reported on stderr as JSON objects.
+// Returns false after reporting an error.
+func emitTailEntries(tail *storage.Tail, c query.Criteria, roundStep int) bool {
+	result, err := tail.Read()
 	if err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
-		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", storagePath)
 		deps.Exit(1)
-		return
+		return false
 	}
 
-	// Display warnings about corrupted lines to stderr
-	if len(result.Warnings) > 0 {
-		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Found %d corrupted line(s) in storage file:\n", len(result.Warnings))
-		for _, warning := range result.Warnings {
-			_, _ = fmt.Fprintln(deps.Stderr, formatCorruptionWarning(warning))
-		}
-		_, _ = fmt.Fprintln(deps.Stderr)
+	warnings := json.NewEncoder(deps.Stderr)
+	for _, w := range result.Warnings {
+		_ = warnings.Encode(followWarningJSON{Warning: "corrupted line", Line: w.LineNumber, Content: w.Content, Error: w.Error})
 	}
 
-	// Apply date filtering if specified
-	entries := result.Entries
-	if hasDateFilter {
-		filtered := make([]entry.Entry, 0)
-		for _, e := range entries {
-			if timeutil.IsInRange(e.Timestamp, startDate, endDate) {
-				filtered = append(filtered, e)
-			}
+	if result.Reset {
+		if _, err := fmt.Fprintln(deps.Stdout, `{"reset":true}`); err != nil {
+			_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to write NDJSON output")
+			_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+			deps.Exit(1)
+			return false
 		}
-		entries = filtered
 	}
+	entries := c.Apply(result.Entries)
+	entry.SortByTimestamp(entries, func(e entry.Entry) time.Time { return e.Timestamp }, false)
+	return writeExportNDJSON(deps.Stdout, roundDisplayEntries(entries, roundStep))
+}
 
-	// Get project and tag filter flags from root persistent flags
-	projectFilter, _ := cmd.Root().PersistentFlags().GetString("project")
-	tagFilters, _ := cmd.Root().PersistentFlags().GetStringSlice("tag")
-
-	// Apply project and tag filters if specified
-	f := filter.NewFilter("", projectFilter, tagFilters)
-	if !f.IsEmpty() {
-		entries = filter.FilterEntries(entries, f)
+// writeExportCSV writes entries to out as CSV rows separated by delimiter,
+// with a header row unless --no-header is given. Returns false after
+// reporting an error.
+func writeExportCSV(cmd *cobra.Command, out io.Writer, delimiter rune, entries []entry.Entry) bool {
+	if bom, _ := cmd.Flags().GetBool("bom"); bom {
+		if _, err := io.WriteString(out, "\ufeff"); err != nil {
+			_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to write CSV output")
+			_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+			deps.Exit(1)
+			return false
+		}
 	}
-
-	// Create CSV writer
-	writer := csv.NewWriter(deps.Stdout)
+	writer := csv.NewWriter(out)
+	writer.Comma = delimiter
 	defer writer.Flush()
 
-	headers := []string{"date", "description", "duration_minutes", "duration_hours", "project", "tags"}
-	if err := writeCSVHeader(writer, headers); err != nil {
-		return
+	// Keep the tags of an entry in one field when ';' separates the fields
+	tagSeparator := ";"
+	if delimiter == ';' {
+		tagSeparator = ","
+	}
+
+	if noHeader, _ := cmd.Flags().GetBool("no-header"); !noHeader {
+		headers := []string{"date", "description", "duration_minutes", "duration_hours", "project", "tags", "client"}
+		if err := writeCSVHeader(writer, headers); err != nil {
+			return false
+		}
 	}
 
 	// Write each entry as a CSV row
@@ -405,10 +663,10 @@ func exportCSV(cmd *cobra.Command) {
 		date := e.Timestamp.Format("2006-01-02")
 
 		// Format duration in hours as decimal
-		durationHours := strconv.FormatFloat(float64(e.DurationMinutes)/60.0, 'f', 2, 64)
+		durationHours := timeutil.FormatDurationDecimal(e.DurationMinutes)
 
 		// Format tags as semicolon-separated string
-		tagsStr := strings.Join(e.Tags, ";")
+		tagsStr := strings.Join(e.Tags, tagSeparator)
 
 		// Create row
 		row := []string{
@@ -418,10 +676,11 @@ func exportCSV(cmd *cobra.Command) {
 			durationHours,
 			e.Project,
 			tagsStr,
+			e.Client,
 		}
 
 		if err := writeCSVRow(writer, row); err != nil {
-			return
+			return false
 		}
 	}
 
@@ -431,6 +690,319 @@ func exportCSV(cmd *cobra.Command) {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to flush CSV output")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
 		deps.Exit(1)
+		return false
+	}
+	return true
+}
+
+// parseCSVDelimiter returns the field delimiter for a --delimiter value: a
+// single character, or "\t" for a tab. Quotes, line breaks and the Unicode
+// replacement character can't be used as a delimiter.
+func parseCSVDelimiter(value string) (rune, bool) {
+	if value == `\t` {
+		return '\t', true
+	}
+	runes := []rune(value)
+	if len(runes) != 1 {
+		return 0, false
+	}
+	switch r := runes[0]; r {
+	case '"', '\r', '\n', utf8.RuneError:
+		return 0, false
+	default:
+		return r, true
+	}
+}
+
+// exportOutputOptions returns the --output path and the --force and --count flags
+// shared by all export formats
+func exportOutputOptions(cmd *cobra.Command) (string, bool, bool) {
+	flags := cmd.InheritedFlags()
+	outputPath, _ := flags.GetString("output")
+	force, _ := flags.GetBool("force")
+	countOnly, _ := flags.GetBool("count")
+	return outputPath, force, countOnly
+}
+
+// readExportEntries reads the entries matching the date and filter flags of an
+// export command. Exports cover all dates unless a date flag is given and, unlike
+// listings, include soft-deleted entries. The entries are sorted by --order and
+// rounded with --round-display.
+// Returns false after reporting an error.
+func readExportEntries(cmd *cobra.Command) (query.Criteria, []entry.Entry, bool) {
+	c, ok := resolveQuery(cmd)
+	if !ok {
+		return query.Criteria{}, nil, false
+	}
+	desc, ok := descendingOrder(cmd)
+	if !ok {
+		return query.Criteria{}, nil, false
+	}
+	// Exports only round with an explicit --round-display, so backups made
+	// with round_display_minutes configured stay exact
+	roundStep, ok := roundDisplayStep(cmd, 0)
+	if !ok {
+		return query.Criteria{}, nil, false
+	}
+
+	// Get storage path
+	storagePath, err := deps.StoragePath()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return query.Criteria{}, nil, false
+	}
+
+	// Read all entries from storage
+	result, err := storage.ReadEntriesWithWarnings(storagePath)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", storagePath)
+		deps.Exit(1)
+		return query.Criteria{}, nil, false
+	}
+
+	// Display warnings about corrupted lines to stderr
+	printCorruptionWarnings(result.Warnings)
+
+	entries := c.Apply(result.Entries)
+	entry.SortByTimestamp(entries, func(e entry.Entry) time.Time { return e.Timestamp }, desc)
+	return c, roundDisplayEntries(entries, roundStep), true
+}
+
+// printExportCount prints the number of entries an export would contain to
+// stderr, with the date range and filters that were applied (--count)
+func printExportCount(count int, c query.Criteria) {
+	rangeDesc := "all dates"
+	if c.HasPeriod() {
+		rangeDesc = query.FormatDateRange(c.Period.Start, c.Period.End)
+	}
+	if c.Project != "" {
+		rangeDesc += " @" + c.Project
+	}
+	for _, tag := range c.Tags {
+		rangeDesc += " #" + tag
+	}
+	_, _ = fmt.Fprintf(deps.Stderr, "%s match (%s)\n", formatCount(count, "entry", "entries"), rangeDesc)
+}
+
+// checkExportOutputPath refuses to continue when the output file already exists
+// and --force was not given. Returns true if the export may proceed.
+func checkExportOutputPath(outputPath string, force bool) bool {
+	if outputPath == "" || force {
+		return true
+	}
+	if _, err := os.Stat(outputPath); err == nil {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Output file already exists: %s\n", outputPath)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use --force to overwrite it")
+		deps.Exit(1)
+		return false
+	}
+	return true
+}
+
+// writeExportOutput writes the export document to outputPath atomically
+// (temp file + rename) and prints a summary line to stdout.
+// Returns false after reporting an error.
+func writeExportOutput(outputPath string, data []byte, entryCount int) bool {
+	tmpFile := outputPath + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		_ = os.Remove(tmpFile)
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to write export file")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that the directory exists and is writable: %s\n", outputPath)
+		deps.Exit(1)
+		return false
+	}
+
+	if err := os.Rename(tmpFile, outputPath); err != nil {
+		_ = os.Remove(tmpFile)
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to write export file")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		deps.Exit(1)
+		return false
+	}
+
+	_, _ = fmt.Fprintf(deps.Stdout, "Exported %s to %s\n", formatCount(entryCount, "entry", "entries"), outputPath)
+	return true
+}
+
+// exportSplitOptions returns the --split-by grouping ("project", "tag" or
+// "day", "" when not splitting) and the --output-dir of an export. Invalid or
+// conflicting values are reported to stderr; ok is false then.
+func exportSplitOptions(cmd *cobra.Command, outputPath string) (string, string, bool) {
+	flags := cmd.InheritedFlags()
+	splitBy, _ := flags.GetString("split-by")
+	outputDir, _ := flags.GetString("output-dir")
+	splitBy = strings.ToLower(strings.TrimSpace(splitBy))
+
+	switch {
+	case splitBy != "" && splitBy != "project" && splitBy != "tag" && splitBy != "day":
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --split-by value '%s'\n", splitBy)
+		_, _ = fmt.Fprintln(deps.Stderr, "Valid values: project, tag, day")
+	case splitBy != "" && outputDir == "":
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: --split-by requires --output-dir")
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Name the directory for the files, e.g. --output-dir invoices")
+	case splitBy == "" && outputDir != "":
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: --output-dir requires --split-by")
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use --output (-o) to write a single file")
+	case splitBy != "" && outputPath != "":
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: --output cannot be combined with --split-by")
+	default:
+		return splitBy, outputDir, true
+	}
+	deps.Exit(1)
+	return "", "", false
+}
+
+// exportGroup is the entries of one file written with --split-by
+type exportGroup struct {
+	Name    string        // Project, tag or date ("" for entries without a project or tag)
+	File    string        // File name without extension
+	Entries []entry.Entry // Entries in export order
+}
+
+// splitExportEntries groups entries by project, tag or day for --split-by,
+// ordered by file name. An entry with several tags is in the group of each of
+// them. Names differing only in case share a group, so the files don't
+// overwrite each other on case-insensitive file systems. Different names
+// whose file names are the same (e.g. "acme.co" and "acme_co") get a numbered
+// suffix instead of sharing a file, e.g. acme_co-2.
+func splitExportEntries(entries []entry.Entry, splitBy string) []exportGroup {
+	var groups []exportGroup
+	index := make(map[string]int)
+	for _, e := range entries {
+		var names []string
+		switch splitBy {
+		case "tag":
+			names = e.Tags
+			if len(names) == 0 {
+				names = []string{""}
+			}
+		case "day":
+			names = []string{e.Timestamp.Format("2006-01-02")}
+		default:
+			names = []string{e.Project}
+		}
+
+		for _, name := range names {
+			key := strings.ToLower(name)
+			i, ok := index[key]
+			if !ok {
+				i = len(groups)
+				index[key] = i
+				groups = append(groups, exportGroup{Name: name})
+			}
+			groups[i].Entries = append(groups[i].Entries, e)
+		}
+	}
+
+	assignExportFileNames(groups, splitBy)
+	sort.Slice(groups, func(i, j int) bool { return groups[i].File < groups[j].File })
+	return groups
+}
+
+// assignExportFileNames sets the file name of each group. Where several
+// groups have the same file name (ignoring case), the group without a name
+// keeps it, then the group whose name is the file name (acme_co over
+// acme.co); the others get the first free suffix -2, -3, ...
+func assignExportFileNames(groups []exportGroup, splitBy string) {
+	order := make([]int, len(groups))
+	for i := range groups {
+		groups[i].File = exportFileName(groups[i].Name, splitBy)
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		ga, gb := groups[order[a]], groups[order[b]]
+		if fa, fb := strings.ToLower(ga.File), strings.ToLower(gb.File); fa != fb {
+			return fa < fb
+		}
+		if ra, rb := exportFileNameRank(ga), exportFileNameRank(gb); ra != rb {
+			return ra < rb
+		}
+		return ga.Name < gb.Name
+	})
+
+	used := make(map[string]bool)
+	for _, g := range groups {
+		used[strings.ToLower(g.File)] = true
+	}
+	taken := make(map[string]bool)
+	for _, i := range order {
+		base := groups[i].File
+		file := base
+		// A suffixed name must not be the file name of another group either
+		for n := 2; taken[strings.ToLower(file)] || (file != base && used[strings.ToLower(file)]); n++ {
+			file = fmt.Sprintf("%s-%d", base, n)
+		}
+		groups[i].File = file
+		taken[strings.ToLower(file)] = true
+	}
+}
+
+// exportFileNameRank orders groups sharing a file name: the group without a
+// name first, then the group whose name needed no replacements, then the rest
+func exportFileNameRank(g exportGroup) int {
+	switch {
+	case g.Name == "":
+		return 0
+	case strings.EqualFold(g.Name, g.File):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// exportFileName returns the file name, without extension, for the group
+// name of a --split-by export, e.g. "acme" or "no-project". Characters other
+// than letters, digits, '-' and '_' are replaced with '_'.
+func exportFileName(name, splitBy string) string {
+	if name == "" {
+		if splitBy == "tag" {
+			return "no-tags"
+		}
+		return "no-project"
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// writeSplitExport writes a file per --split-by group of entries into
+// outputDir, rendered by render, and reports each file written. Existing
+// files are only replaced with force; none is written when one would be.
+func writeSplitExport(splitBy, outputDir, ext string, force bool, entries []entry.Entry, render func(out io.Writer, group exportGroup) bool) {
+	groups := splitExportEntries(entries, splitBy)
+	if len(groups) == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, "No entries to export")
+		return
+	}
+
+	paths := make([]string, len(groups))
+	for i, group := range groups {
+		paths[i] = filepath.Join(outputDir, group.File+ext)
+		if !checkExportOutputPath(paths[i], force) {
+			return
+		}
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to create output directory")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		deps.Exit(1)
 		return
 	}
+
+	for i, group := range groups {
+		var buf bytes.Buffer
+		if !render(&buf, group) || !writeExportOutput(paths[i], buf.Bytes(), len(group.Entries)) {
+			return
+		}
+	}
 }
@@ -2,16 +2,21 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/spf13/cobra"
 	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/query"
 	"github.com/xolan/did/internal/storage"
 )
 
@@ -412,8 +417,7 @@ func TestExportJSON_StoragePathError(t *testing.T) {
 }
 
 func TestExportJSON_ReadEntriesError(t *testing.T) {
-	// Use a path to a directory (not a file) to cause read error
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
 	exitCalled := false
 	stderr := &bytes.Buffer{}
@@ -423,7 +427,7 @@ func TestExportJSON_ReadEntriesError(t *testing.T) {
 		Stdin:  strings.NewReader(""),
 		Exit:   func(code int) { exitCalled = true },
 		StoragePath: func() (string, error) {
-			return tmpDir, nil // path to directory, not file
+			return storagePath, nil
 		},
 	}
 	SetDeps(d)
@@ -904,6 +908,38 @@ func TestExportJSON_LastFlag(t *testing.T) {
 	}
 }
 
+func TestExportJSON_LastWeeks(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	createExportTestEntries(t, storagePath)
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	_ = exportJSONCmd.Flags().Set("last", "1w")
+	defer func() { _ = exportJSONCmd.Flags().Set("last", "0") }()
+
+	exportJSON(exportJSONCmd)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr: %s", stderr.String())
+	}
+	var result ExportOutput
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	// The entry from 7 days ago is outside the last week (today and the 6 days before)
+	if len(result.Entries) != 2 {
+		t.Errorf("Expected 2 entries from the last week, got %d", len(result.Entries))
+	}
+	if last := result.Metadata.FilterCriteria["last"]; last != "1w" {
+		t.Errorf("Expected last=1w in filter_criteria, got %v", result.Metadata.FilterCriteria)
+	}
+	if _, ok := result.Metadata.FilterCriteria["last_days"]; ok {
+		t.Errorf("Expected no last_days for a value in weeks, got %v", result.Metadata.FilterCriteria)
+	}
+}
+
 func TestExportJSON_InvalidFromDate(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -1461,6 +1497,42 @@ func TestExportJSON_ProjectShorthand(t *testing.T) {
 	}
 }
 
+func TestExportJSON_ClientFlag(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	now := time.Now()
+	for _, e := range []entry.Entry{
+		{Timestamp: now, Description: "api work", DurationMinutes: 60, Project: "backend", Client: "AcmeCorp"},
+		{Timestamp: now, Description: "standup", DurationMinutes: 15, Project: "backend"},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	resetFilterFlags(exportJSONCmd)
+	defer resetFilterFlags(exportJSONCmd)
+	_ = rootCmd.PersistentFlags().Set("client", "AcmeCorp")
+
+	exportJSON(exportJSONCmd)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr: %s", stderr.String())
+	}
+	var result ExportOutput
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if len(result.Entries) != 1 || result.Entries[0].Client != "AcmeCorp" {
+		t.Errorf("Expected only the AcmeCorp entry, got %+v", result.Entries)
+	}
+	if client := result.Metadata.FilterCriteria["client"]; client != "AcmeCorp" {
+		t.Errorf("Expected client='AcmeCorp' in filter_criteria, got %v", result.Metadata.FilterCriteria)
+	}
+}
+
 func TestExportJSON_TagFlag(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -2756,7 +2828,7 @@ func TestExportCSV_BasicOutput(t *testing.T) {
 	}
 
 	// Verify header
-	expectedHeader := "date,description,duration_minutes,duration_hours,project,tags"
+	expectedHeader := "date,description,duration_minutes,duration_hours,project,tags,client"
 	if lines[0] != expectedHeader {
 		t.Errorf("Expected header:\n%s\nGot:\n%s", expectedHeader, lines[0])
 	}
@@ -2978,7 +3050,7 @@ func TestExportCSV_EmptyResults(t *testing.T) {
 	}
 
 	// Verify header is present
-	expectedHeader := "date,description,duration_minutes,duration_hours,project,tags"
+	expectedHeader := "date,description,duration_minutes,duration_hours,project,tags,client"
 	if lines[0] != expectedHeader {
 		t.Errorf("Expected header:\n%s\nGot:\n%s", expectedHeader, lines[0])
 	}
@@ -3522,6 +3594,28 @@ func TestExportCSV_TagFlag(t *testing.T) {
 	}
 }
 
+func TestExportCSV_ClientColumn(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	e := entry.Entry{Timestamp: time.Now(), Description: "api work", DurationMinutes: 60, Project: "backend", Client: "AcmeCorp"}
+	if err := storage.AppendEntry(storagePath, e); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	exportCSV(exportCSVCmd)
+
+	records, err := csv.NewReader(strings.NewReader(stdout.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(records) != 2 || records[0][6] != "client" || records[1][6] != "AcmeCorp" {
+		t.Errorf("Expected a client column with AcmeCorp, got %v", records)
+	}
+}
+
 func TestExportCSV_TagsSemicolonSeparated(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -3715,8 +3809,7 @@ func TestExportCSV_StoragePathError(t *testing.T) {
 }
 
 func TestExportCSV_ReadEntriesError(t *testing.T) {
-	// Use a path to a directory (not a file) to cause read error
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
 	exitCalled := false
 	stderr := &bytes.Buffer{}
@@ -3726,7 +3819,7 @@ func TestExportCSV_ReadEntriesError(t *testing.T) {
 		Stdin:  strings.NewReader(""),
 		Exit:   func(code int) { exitCalled = true },
 		StoragePath: func() (string, error) {
-			return tmpDir, nil // path to directory, not file
+			return storagePath, nil
 		},
 	}
 	SetDeps(d)
@@ -4138,3 +4231,875 @@ func TestExportCSV_CorruptedStorageWarnings(t *testing.T) {
 		t.Errorf("Expected valid entry in stdout, got: %s", stdoutOutput)
 	}
 }
+
+// setExportOutputFlags sets the --output and --force flags shared by export formats
+// and resets them when the test finishes
+func setExportOutputFlags(t *testing.T, output string, force bool) {
+	t.Helper()
+	_ = exportCmd.PersistentFlags().Set("output", output)
+	_ = exportCmd.PersistentFlags().Set("force", fmt.Sprintf("%t", force))
+	t.Cleanup(func() {
+		_ = exportCmd.PersistentFlags().Set("output", "")
+		_ = exportCmd.PersistentFlags().Set("force", "false")
+	})
+}
+
+func TestExportJSON_OutputFlagWritesFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	createExportTestEntries(t, storagePath)
+	outputPath := filepath.Join(tmpDir, "backup.json")
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	setExportOutputFlags(t, outputPath, false)
+
+	exportJSON(exportJSONCmd)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr: %s", stderr.String())
+	}
+	expected := fmt.Sprintf("Exported 3 entries to %s\n", outputPath)
+	if stdout.String() != expected {
+		t.Errorf("Expected stdout %q, got %q", expected, stdout.String())
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	var result ExportOutput
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Failed to parse exported file: %v", err)
+	}
+	if len(result.Entries) != 3 {
+		t.Errorf("Expected 3 entries in file, got %d", len(result.Entries))
+	}
+	if _, err := os.Stat(outputPath + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Expected temp file to be removed")
+	}
+}
+
+func TestExportCSV_OutputFlagWritesFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	createExportTestEntries(t, storagePath)
+	outputPath := filepath.Join(tmpDir, "backup.csv")
+
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	setExportOutputFlags(t, outputPath, false)
+
+	exportCSV(exportCSVCmd)
+
+	if !strings.Contains(stdout.String(), "Exported 3 entries to") {
+		t.Errorf("Expected summary line, got: %s", stdout.String())
+	}
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 {
+		t.Errorf("Expected header + 3 rows, got %d lines", len(lines))
+	}
+}
+
+func TestExportJSON_OutputFlagExistingFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	createExportTestEntries(t, storagePath)
+	outputPath := filepath.Join(tmpDir, "backup.json")
+	if err := os.WriteFile(outputPath, []byte("keep me"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, stdout, stderr := testDeps(storagePath)
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	setExportOutputFlags(t, outputPath, false)
+
+	exportJSON(exportJSONCmd)
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "Output file already exists") {
+		t.Errorf("Expected existing file error, got: %s", stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "--force") {
+		t.Errorf("Expected --force hint, got: %s", stderr.String())
+	}
+	if stdout.Len() > 0 {
+		t.Errorf("Expected no stdout, got: %s", stdout.String())
+	}
+	data, _ := os.ReadFile(outputPath)
+	if string(data) != "keep me" {
+		t.Errorf("Expected existing file to be untouched, got: %s", string(data))
+	}
+}
+
+func TestExportJSON_OutputFlagForceOverwrites(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	createExportTestEntries(t, storagePath)
+	outputPath := filepath.Join(tmpDir, "backup.json")
+	if err := os.WriteFile(outputPath, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, _, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	setExportOutputFlags(t, outputPath, true)
+
+	exportJSON(exportJSONCmd)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr: %s", stderr.String())
+	}
+	data, _ := os.ReadFile(outputPath)
+	var result ExportOutput
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Expected file to be overwritten with JSON: %v", err)
+	}
+}
+
+func TestExportJSON_OutputFlagPermissionDenied(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	createExportTestEntries(t, storagePath)
+
+	readOnlyDir := filepath.Join(tmpDir, "readonly")
+	if err := os.Mkdir(readOnlyDir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chmod(readOnlyDir, 0755) }()
+	outputPath := filepath.Join(readOnlyDir, "backup.json")
+
+	d, stdout, stderr := testDeps(storagePath)
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	setExportOutputFlags(t, outputPath, false)
+
+	exportJSON(exportJSONCmd)
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "Failed to write export file") {
+		t.Errorf("Expected write error, got: %s", stderr.String())
+	}
+	if stdout.Len() > 0 {
+		t.Errorf("Expected no stdout, got: %s", stdout.String())
+	}
+	if _, err := os.Stat(outputPath + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Expected temp file to be cleaned up")
+	}
+}
+
+func TestExport_Count(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      *cobra.Command
+		flags    map[string]string
+		args     []string
+		expected string
+	}{
+		{"json all", exportJSONCmd, nil, nil, "3 entries match (all dates)\n"},
+		{"csv all", exportCSVCmd, nil, nil, "3 entries match (all dates)\n"},
+		{"json range", exportJSONCmd, map[string]string{"from": "2024-01-15", "to": "2024-01-16"}, nil, "2 entries match (Mon Jan 15 - Tue Jan 16, 2024)\n"},
+		{"csv until", exportCSVCmd, map[string]string{"to": "2024-01-15"}, nil, "1 entry match (until Jan 15, 2024)\n"},
+		{"json filters", exportJSONCmd, nil, []string{"@acme", "#review"}, "1 entry match (all dates @acme #review)\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			storagePath := filepath.Join(tmpDir, "entries.jsonl")
+			for _, e := range []entry.Entry{
+				{Timestamp: time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local), Description: "review", DurationMinutes: 60, Project: "acme", Tags: []string{"review"}},
+				{Timestamp: time.Date(2024, 1, 16, 9, 0, 0, 0, time.Local), Description: "fix", DurationMinutes: 30, Project: "acme"},
+				{Timestamp: time.Date(2024, 1, 20, 9, 0, 0, 0, time.Local), Description: "meeting", DurationMinutes: 45},
+			} {
+				if err := storage.AppendEntry(storagePath, e); err != nil {
+					t.Fatalf("Failed to create test entry: %v", err)
+				}
+			}
+			outputPath := filepath.Join(tmpDir, "export.out")
+
+			d, stdout, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			setExportOutputFlags(t, outputPath, false)
+			_ = exportCmd.PersistentFlags().Set("count", "true")
+			defer func() { _ = exportCmd.PersistentFlags().Set("count", "false") }()
+			resetFilterFlags(tt.cmd)
+			defer resetFilterFlags(tt.cmd)
+			for name, value := range tt.flags {
+				_ = tt.cmd.Flags().Set(name, value)
+				defer func() { _ = tt.cmd.Flags().Set(name, "") }()
+			}
+
+			tt.cmd.Run(tt.cmd, tt.args)
+
+			if stderr.String() != tt.expected {
+				t.Errorf("Expected stderr %q, got %q", tt.expected, stderr.String())
+			}
+			if stdout.Len() > 0 {
+				t.Errorf("Expected no stdout, got %q", stdout.String())
+			}
+			if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+				t.Error("Expected no output file to be written")
+			}
+		})
+	}
+}
+
+func TestExportCSV_NoHeader(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	createExportTestEntries(t, storagePath)
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	_ = exportCSVCmd.Flags().Set("no-header", "true")
+	defer func() { _ = exportCSVCmd.Flags().Set("no-header", "false") }()
+
+	exportCSV(exportCSVCmd)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr: %s", stderr.String())
+	}
+	output := stdout.String()
+	if !strings.HasPrefix(output, time.Now().AddDate(0, 0, -7).Format("2006-01-02")+",Code review for feature X,") {
+		t.Errorf("Expected output to start with the first data row, got:\n%s", output)
+	}
+
+	// The rows still parse with the known header put in front of them
+	header := "date,description,duration_minutes,duration_hours,project,tags,client\n"
+	records, err := csv.NewReader(strings.NewReader(header + output)).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse rows with header: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("Expected header + 3 rows, got %d records", len(records))
+	}
+	if records[2][1] != "Bug fix in authentication" || records[2][2] != "90" || records[2][4] != "client" {
+		t.Errorf("Unexpected second row: %v", records[2])
+	}
+}
+
+// setCSVFormatFlags sets the --bom and --delimiter flags of export csv and
+// resets them when the test finishes
+func setCSVFormatFlags(t *testing.T, bom bool, delimiter string) {
+	t.Helper()
+	_ = exportCSVCmd.Flags().Set("bom", fmt.Sprintf("%t", bom))
+	_ = exportCSVCmd.Flags().Set("delimiter", delimiter)
+	t.Cleanup(func() {
+		_ = exportCSVCmd.Flags().Set("bom", "false")
+		_ = exportCSVCmd.Flags().Set("delimiter", ",")
+	})
+}
+
+func TestExportCSV_Delimiter(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	e := entry.Entry{
+		Timestamp:       time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local),
+		Description:     "review; then merge, \"quickly\"",
+		DurationMinutes: 90,
+		Project:         "acme",
+		Tags:            []string{"review", "urgent"},
+	}
+	if err := storage.AppendEntry(storagePath, e); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		delimiter string
+		comma     rune
+		tags      string
+	}{
+		{"comma", ",", ',', "review;urgent"},
+		{"semicolon", ";", ';', "review,urgent"},
+		{"tab", `\t`, '\t', "review;urgent"},
+		{"pipe", "|", '|', "review;urgent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			setCSVFormatFlags(t, false, tt.delimiter)
+
+			exportCSV(exportCSVCmd)
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr: %s", stderr.String())
+			}
+			reader := csv.NewReader(strings.NewReader(stdout.String()))
+			reader.Comma = tt.comma
+			records, err := reader.ReadAll()
+			if err != nil {
+				t.Fatalf("Failed to parse output: %v\n%s", err, stdout.String())
+			}
+			if len(records) != 2 || len(records[0]) != 7 || len(records[1]) != 7 {
+				t.Fatalf("Expected header and one row of 7 fields, got %q", records)
+			}
+			if records[0][6] != "client" {
+				t.Errorf("Expected the header to use the delimiter, got %q", records[0])
+			}
+			if records[1][1] != e.Description || records[1][4] != "acme" || records[1][5] != tt.tags {
+				t.Errorf("Unexpected row %q", records[1])
+			}
+		})
+	}
+}
+
+func TestExportCSV_BOM(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	createExportTestEntries(t, storagePath)
+	outputPath := filepath.Join(tmpDir, "entries.csv")
+
+	d, _, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	setExportOutputFlags(t, outputPath, false)
+	setCSVFormatFlags(t, true, ";")
+
+	exportCSV(exportCSVCmd)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr: %s", stderr.String())
+	}
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	content, ok := strings.CutPrefix(string(data), "\xEF\xBB\xBF")
+	if !ok {
+		t.Fatalf("Expected the file to start with a UTF-8 BOM, got %q", data[:min(len(data), 8)])
+	}
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.Comma = ';'
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse output: %v", err)
+	}
+	if len(records) != 4 || records[0][0] != "date" {
+		t.Errorf("Expected header + 3 rows starting with 'date', got %q", records)
+	}
+}
+
+func TestExportCSV_InvalidDelimiter(t *testing.T) {
+	for _, delimiter := range []string{"", ";;", "\"", "\n"} {
+		t.Run(fmt.Sprintf("%q", delimiter), func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			createExportTestEntries(t, storagePath)
+
+			exitCode := -1
+			d, stdout, stderr := testDeps(storagePath)
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			setCSVFormatFlags(t, false, delimiter)
+
+			exportCSV(exportCSVCmd)
+
+			if exitCode != 1 {
+				t.Errorf("Expected exit code 1, got %d", exitCode)
+			}
+			if !strings.Contains(stderr.String(), "Invalid --delimiter value") {
+				t.Errorf("Expected invalid delimiter error, got: %s", stderr.String())
+			}
+			if stdout.Len() > 0 {
+				t.Errorf("Expected no output, got %q", stdout.String())
+			}
+		})
+	}
+}
+
+func TestExportJSON_IncludeSummary(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	entries := createExportTestEntries(t, storagePath)
+	deletedAt := time.Now()
+	if err := storage.AppendEntry(storagePath, entry.Entry{Timestamp: time.Now(), Description: "removed", DurationMinutes: 30, Project: "acme", DeletedAt: &deletedAt}); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+
+	// Off by default, so existing consumers see the same document
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	exportJSON(exportJSONCmd)
+	ResetDeps()
+	if strings.Contains(stdout.String(), `"summary"`) {
+		t.Errorf("Expected no summary without --include-summary, got: %s", stdout.String())
+	}
+
+	d, stdout, _ = testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	_ = exportJSONCmd.Flags().Set("include-summary", "true")
+	defer func() { _ = exportJSONCmd.Flags().Set("include-summary", "false") }()
+
+	exportJSON(exportJSONCmd)
+
+	var result struct {
+		Summary exportSummaryJSON `json:"summary"`
+		Entries []entry.Entry     `json:"entries"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to parse JSON: %v\nOutput: %s", err, stdout.String())
+	}
+	summary := result.Summary
+
+	// The deleted entry is exported but not counted
+	if len(result.Entries) != 4 || summary.TotalMinutes != 195 || summary.EntryCount != 3 {
+		t.Errorf("Expected 4 exported entries and 195 minutes in 3 entries, got %d entries, %+v", len(result.Entries), summary)
+	}
+	if summary.FirstTimestamp == nil || !summary.FirstTimestamp.Equal(entries[0].Timestamp) ||
+		summary.LastTimestamp == nil || !summary.LastTimestamp.Equal(entries[2].Timestamp) {
+		t.Errorf("Expected the span of the counted entries, got %v - %v", summary.FirstTimestamp, summary.LastTimestamp)
+	}
+	expectedProjects := []metadataSummary{{"client", 1, 90}, {"acme", 1, 60}, {"", 1, 45}}
+	if !reflect.DeepEqual(summary.Projects, expectedProjects) {
+		t.Errorf("Projects = %+v, expected %+v", summary.Projects, expectedProjects)
+	}
+	expectedTags := []metadataSummary{{"bugfix", 1, 90}, {"review", 1, 60}, {"", 1, 45}}
+	if !reflect.DeepEqual(summary.Tags, expectedTags) {
+		t.Errorf("Tags = %+v, expected %+v", summary.Tags, expectedTags)
+	}
+	if len(summary.Days) != 3 || summary.Days[0].Minutes != 60 || summary.Days[2].CumulativeMinutes != 195 {
+		t.Errorf("Expected the 3 days with entries, got %+v", summary.Days)
+	}
+}
+
+// writeOrderTestEntries writes entries out of chronological order, two of them
+// with the same timestamp
+func writeOrderTestEntries(t *testing.T) string {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	same := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	for _, e := range []entry.Entry{
+		{Timestamp: same.Add(2 * time.Hour), Description: "late", DurationMinutes: 30},
+		{Timestamp: same, Description: "first tie", DurationMinutes: 30},
+		{Timestamp: same.Add(-time.Hour), Description: "early", DurationMinutes: 30},
+		{Timestamp: same, Description: "second tie", DurationMinutes: 30},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	return storagePath
+}
+
+func TestExportJSON_OrderIsStable(t *testing.T) {
+	storagePath := writeOrderTestEntries(t)
+
+	for _, order := range []string{"asc", "desc"} {
+		expected := []string{"early", "first tie", "second tie", "late"}
+		if order == "desc" {
+			expected = []string{"late", "first tie", "second tie", "early"}
+		}
+		// Repeated exports are identical, ties in file order
+		for run := 0; run < 3; run++ {
+			d, stdout, _ := testDeps(storagePath)
+			d.Config.Timezone = "UTC"
+			SetDeps(d)
+			_ = exportJSONCmd.Flags().Set("order", order)
+
+			exportJSON(exportJSONCmd)
+
+			var result ExportOutput
+			if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+				t.Fatalf("Failed to parse JSON output: %v", err)
+			}
+			var got []string
+			for _, e := range result.Entries {
+				got = append(got, e.Description)
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("--order %s: got %v, expected %v", order, got, expected)
+			}
+		}
+	}
+	_ = exportJSONCmd.Flags().Set("order", "asc")
+	ResetDeps()
+}
+
+func TestExportCSV_OrderDesc(t *testing.T) {
+	storagePath := writeOrderTestEntries(t)
+	d, stdout, _ := testDeps(storagePath)
+	d.Config.Timezone = "UTC"
+	SetDeps(d)
+	defer ResetDeps()
+	_ = exportCSVCmd.Flags().Set("order", "desc")
+	defer func() { _ = exportCSVCmd.Flags().Set("order", "asc") }()
+
+	exportCSV(exportCSVCmd)
+
+	rows, err := csv.NewReader(stdout).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+	var got []string
+	for _, row := range rows[1:] {
+		got = append(got, row[1])
+	}
+	expected := []string{"late", "first tie", "second tie", "early"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected rows %v, got %v", expected, got)
+	}
+}
+
+func TestExportJSON_InvalidOrder(t *testing.T) {
+	d, _, stderr := testDeps(writeOrderTestEntries(t))
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	_ = exportJSONCmd.Flags().Set("order", "newest")
+	defer func() { _ = exportJSONCmd.Flags().Set("order", "asc") }()
+
+	exportJSON(exportJSONCmd)
+
+	if exitCode != 1 || !strings.Contains(stderr.String(), "Invalid --order 'newest'") || !strings.Contains(stderr.String(), "Valid orders: asc, desc") {
+		t.Errorf("Expected exit 1 with the valid orders, got exit %d: %s", exitCode, stderr.String())
+	}
+}
+
+// setExportSplitFlags sets the --split-by and --output-dir flags shared by
+// export formats and resets them when the test finishes
+func setExportSplitFlags(t *testing.T, splitBy, outputDir string) {
+	t.Helper()
+	_ = exportCmd.PersistentFlags().Set("split-by", splitBy)
+	_ = exportCmd.PersistentFlags().Set("output-dir", outputDir)
+	t.Cleanup(func() {
+		_ = exportCmd.PersistentFlags().Set("split-by", "")
+		_ = exportCmd.PersistentFlags().Set("output-dir", "")
+	})
+}
+
+// createSplitExportTestEntries creates entries of two projects, with and
+// without tags, on three days
+func createSplitExportTestEntries(t *testing.T, storagePath string) {
+	t.Helper()
+	for _, e := range []entry.Entry{
+		{Timestamp: time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local), Description: "review", DurationMinutes: 60, Project: "acme", Tags: []string{"review", "billable"}},
+		{Timestamp: time.Date(2024, 1, 16, 9, 0, 0, 0, time.Local), Description: "fix", DurationMinutes: 30, Project: "client work", Tags: []string{"billable"}},
+		{Timestamp: time.Date(2024, 1, 16, 14, 0, 0, 0, time.Local), Description: "deploy", DurationMinutes: 15, Project: "Acme"},
+		{Timestamp: time.Date(2024, 1, 20, 9, 0, 0, 0, time.Local), Description: "meeting", DurationMinutes: 45},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+}
+
+func TestExport_SplitBy(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     *cobra.Command
+		splitBy string
+		files   map[string]int // File name to the number of entries in it
+	}{
+		{"csv by project", exportCSVCmd, "project", map[string]int{"acme.csv": 2, "client_work.csv": 1, "no-project.csv": 1}},
+		{"json by tag", exportJSONCmd, "tag", map[string]int{"billable.json": 2, "no-tags.json": 2, "review.json": 1}},
+		{"csv by day", exportCSVCmd, "day", map[string]int{"2024-01-15.csv": 1, "2024-01-16.csv": 2, "2024-01-20.csv": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			storagePath := filepath.Join(tmpDir, "entries.jsonl")
+			createSplitExportTestEntries(t, storagePath)
+			outputDir := filepath.Join(tmpDir, "out")
+
+			d, stdout, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			setExportSplitFlags(t, tt.splitBy, outputDir)
+
+			tt.cmd.Run(tt.cmd, nil)
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr: %s", stderr.String())
+			}
+			written, _ := os.ReadDir(outputDir)
+			if len(written) != len(tt.files) {
+				t.Errorf("Expected %d files, got %d", len(tt.files), len(written))
+			}
+			for name, count := range tt.files {
+				path := filepath.Join(outputDir, name)
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Errorf("Expected %s to be written: %v", name, err)
+					continue
+				}
+				if report := fmt.Sprintf("Exported %s to %s\n", formatCount(count, "entry", "entries"), path); !strings.Contains(stdout.String(), report) {
+					t.Errorf("Expected %q, got:\n%s", report, stdout.String())
+				}
+				if strings.HasSuffix(name, ".csv") {
+					if rows := strings.Count(string(data), "\n") - 1; rows != count {
+						t.Errorf("%s has %d rows, expected %d", name, rows, count)
+					}
+					continue
+				}
+				var doc struct {
+					Metadata struct {
+						TotalEntries int             `json:"total_entries"`
+						Split        exportSplitJSON `json:"split"`
+					} `json:"metadata"`
+				}
+				if err := json.Unmarshal(data, &doc); err != nil {
+					t.Fatalf("%s is not valid JSON: %v", name, err)
+				}
+				if doc.Metadata.TotalEntries != count || doc.Metadata.Split.By != tt.splitBy {
+					t.Errorf("%s metadata = %+v, expected %d entries split by %s", name, doc.Metadata, count, tt.splitBy)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitExportEntries_FileNameCollisions(t *testing.T) {
+	ts := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	var entries []entry.Entry
+	for _, project := range []string{"acme.co", "acme_co", "Acme_Co", "acme_co-2", "a/b", "a_b", "no-project", ""} {
+		entries = append(entries, entry.Entry{Timestamp: ts, Description: "work", DurationMinutes: 30, Project: project})
+	}
+
+	got := make(map[string]string)
+	for _, g := range splitExportEntries(entries, "project") {
+		got[g.File] = fmt.Sprintf("%s/%d", g.Name, len(g.Entries))
+	}
+	expected := map[string]string{
+		"acme_co":      "acme_co/2",
+		"acme_co-2":    "acme_co-2/1",
+		"acme_co-3":    "acme.co/1",
+		"a_b":          "a_b/1",
+		"a_b-2":        "a/b/1",
+		"no-project":   "/1",
+		"no-project-2": "no-project/1",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("splitExportEntries() files = %v, expected %v", got, expected)
+	}
+}
+
+func TestExport_SplitByExistingFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	createSplitExportTestEntries(t, storagePath)
+	if err := os.WriteFile(filepath.Join(tmpDir, "no-project.csv"), []byte("keep"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	exitCode := 0
+	d, _, stderr := testDeps(storagePath)
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	setExportSplitFlags(t, "project", tmpDir)
+
+	exportCSVCmd.Run(exportCSVCmd, nil)
+
+	if exitCode != 1 || !strings.Contains(stderr.String(), "Output file already exists") {
+		t.Errorf("Expected exit 1 for the existing file, got exit %d: %s", exitCode, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "acme.csv")); !os.IsNotExist(err) {
+		t.Error("Expected no file to be written when one already exists")
+	}
+}
+
+func TestExport_SplitByInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		splitBy   string
+		outputDir string
+		output    string
+		expected  string
+	}{
+		{"unknown grouping", "client", "out", "", "Invalid --split-by value 'client'"},
+		{"no directory", "project", "", "", "--split-by requires --output-dir"},
+		{"directory only", "", "out", "", "--output-dir requires --split-by"},
+		{"with output", "project", "out", "export.csv", "--output cannot be combined with --split-by"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			exitCode := 0
+			d, stdout, stderr := testDeps(filepath.Join(tmpDir, "entries.jsonl"))
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			setExportOutputFlags(t, tt.output, false)
+			setExportSplitFlags(t, tt.splitBy, tt.outputDir)
+
+			exportCSVCmd.Run(exportCSVCmd, nil)
+
+			if exitCode != 1 || !strings.Contains(stderr.String(), tt.expected) {
+				t.Errorf("Expected exit 1 with %q, got exit %d: %s", tt.expected, exitCode, stderr.String())
+			}
+			if stdout.Len() > 0 {
+				t.Errorf("Expected no stdout, got %q", stdout.String())
+			}
+		})
+	}
+}
+
+// resetNDJSONFlags restores the flags of export ndjson after a test
+func resetNDJSONFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		_ = exportNDJSONCmd.Flags().Set("follow", "false")
+		_ = exportNDJSONCmd.Flags().Set("order", entry.OrderAsc)
+		resetFilterFlags(exportNDJSONCmd)
+	})
+}
+
+func TestExportNDJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	created := createExportTestEntries(t, storagePath)
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	resetNDJSONFlags(t)
+
+	exportNDJSONCmd.Run(exportNDJSONCmd, []string{"@acme"})
+
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 line for @acme, got %d:\n%s", len(lines), stdout.String())
+	}
+	var e entry.Entry
+	if err := json.Unmarshal([]byte(lines[0]), &e); err != nil {
+		t.Fatalf("Line is not a JSON entry: %v\n%s", err, lines[0])
+	}
+	if e.Description != created[0].Description || e.Project != "acme" {
+		t.Errorf("Exported %+v, expected the @acme entry", e)
+	}
+}
+
+func TestExportNDJSON_Follow(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	createExportTestEntries(t, storagePath)
+	d, stdout, stderr := testDeps(storagePath)
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+
+	tail := storage.NewTail(storagePath)
+	c := query.Criteria{Tags: []string{"review"}}
+	descriptions := func() []string {
+		var got []string
+		for _, line := range strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n") {
+			var e entry.Entry
+			switch {
+			case line == `{"reset":true}`:
+				got = append(got, "reset")
+			case line != "" && json.Unmarshal([]byte(line), &e) == nil:
+				got = append(got, e.Description)
+			}
+		}
+		stdout.Reset()
+		return got
+	}
+
+	emitTailEntries(tail, c, 0)
+	if got := descriptions(); len(got) != 1 || got[0] != "Code review for feature X" {
+		t.Fatalf("Initial export = %v, expected the one #review entry", got)
+	}
+
+	// Only the matching new entries are written, corrupted lines go to stderr
+	for _, e := range []entry.Entry{
+		{Timestamp: time.Now(), Description: "design review", DurationMinutes: 30, Tags: []string{"review"}},
+		{Timestamp: time.Now(), Description: "lunch", DurationMinutes: 30},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("AppendEntry() returned unexpected error: %v", err)
+		}
+	}
+	file, err := os.OpenFile(storagePath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open storage file: %v", err)
+	}
+	_, _ = file.WriteString("not json\n")
+	_ = file.Close()
+	emitTailEntries(tail, c, 0)
+	if got := descriptions(); len(got) != 1 || got[0] != "design review" {
+		t.Errorf("Export after appending = %v, expected only the new #review entry", got)
+	}
+	var warning map[string]interface{}
+	if err := json.Unmarshal(stderr.Bytes(), &warning); err != nil || warning["warning"] != "corrupted line" || warning["content"] != "not json" {
+		t.Errorf("Expected a JSON warning for the corrupted line, got %q", stderr.String())
+	}
+
+	// A rewrite writes a reset marker and all matching entries again
+	if _, err := storage.DeleteEntry(storagePath, 0); err != nil {
+		t.Fatalf("DeleteEntry() returned unexpected error: %v", err)
+	}
+	emitTailEntries(tail, c, 0)
+	if got := descriptions(); len(got) != 2 || got[0] != "reset" || got[1] != "design review" {
+		t.Errorf("Export after the rewrite = %v, expected a reset and the remaining #review entry", got)
+	}
+	if exitCode != 0 {
+		t.Errorf("Expected exit 0, got %d", exitCode)
+	}
+
+	// Stops once interrupted
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	followStorage(ctx, tail, c, 0, time.Millisecond)
+	if stdout.Len() != 0 {
+		t.Errorf("Expected no output without new entries, got %q", stdout.String())
+	}
+}
+
+func TestExportNDJSON_FollowConflicts(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T)
+	}{
+		{"output", func(t *testing.T) { setExportOutputFlags(t, "export.ndjson", false) }},
+		{"split-by", func(t *testing.T) { setExportSplitFlags(t, "project", "out") }},
+		{"order desc", func(t *testing.T) { _ = exportNDJSONCmd.Flags().Set("order", entry.OrderDesc) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode := 0
+			d, stdout, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			resetNDJSONFlags(t)
+			_ = exportNDJSONCmd.Flags().Set("follow", "true")
+			tt.setup(t)
+
+			exportNDJSONCmd.Run(exportNDJSONCmd, nil)
+
+			if exitCode != 1 || !strings.Contains(stderr.String(), "--follow cannot be combined") {
+				t.Errorf("Expected exit 1 with a conflict error, got exit %d: %s", exitCode, stderr.String())
+			}
+			if stdout.Len() > 0 {
+				t.Errorf("Expected no stdout, got %q", stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/entry"
+)
+
+// Output formats of the commands reporting totals (see addFormatFlags)
+const (
+	formatHuman = "human" // Labeled output for reading (default)
+	formatTSV   = "tsv"   // Tab-separated rows, a header row only with --header
+	formatPlain = "plain" // One value per line, no labels
+)
+
+// outputFormats lists the valid --format values in documentation order
+var outputFormats = []string{formatHuman, formatTSV, formatPlain}
+
+// addFormatFlags adds --format and --header to a command reporting totals.
+// Commands write their non-human output with writeRecords, so all of them
+// support the same formats.
+func addFormatFlags(cmd *cobra.Command) {
+	cmd.Flags().String("format", formatHuman, "Output format: human, tsv (tab-separated, durations in minutes) or plain (one value per line)")
+	cmd.Flags().Bool("header", false, "Print a header row with --format tsv")
+}
+
+// outputFormat returns the --format of cmd. Unknown formats, --header without
+// --format tsv and --json with another format are reported to stderr and
+// return false.
+func outputFormat(cmd *cobra.Command) (string, bool) {
+	format, _ := cmd.Flags().GetString("format")
+	format = strings.ToLower(strings.TrimSpace(format))
+	valid := false
+	for _, f := range outputFormats {
+		valid = valid || f == format
+	}
+	if !valid {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Unknown format '%s'\n", format)
+		_, _ = fmt.Fprintf(deps.Stderr, "Valid formats: %s\n", strings.Join(outputFormats, ", "))
+		deps.Exit(1)
+		return "", false
+	}
+	if header, _ := cmd.Flags().GetBool("header"); header && format != formatTSV {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: --header only applies to --format tsv")
+		deps.Exit(1)
+		return "", false
+	}
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON && format != formatHuman {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: --json cannot be combined with --format %s\n", format)
+		deps.Exit(1)
+		return "", false
+	}
+	return format, true
+}
+
+// writeRecords writes rows in the tsv or plain format of cmd: tsv writes each
+// row as tab-separated values, after the columns when --header is given;
+// plain writes every value on its own line, row after row. Durations should
+// be given as whole minutes so the output can be summed losslessly.
+func writeRecords(cmd *cobra.Command, format string, columns []string, rows [][]string) {
+	if format == formatPlain {
+		for _, row := range rows {
+			for _, value := range row {
+				_, _ = fmt.Fprintln(deps.Stdout, value)
+			}
+		}
+		return
+	}
+
+	if header, _ := cmd.Flags().GetBool("header"); header {
+		_, _ = fmt.Fprintln(deps.Stdout, strings.Join(columns, "\t"))
+	}
+	for _, row := range rows {
+		_, _ = fmt.Fprintln(deps.Stdout, strings.Join(row, "\t"))
+	}
+}
+
+// summaryRecords returns the name, total minutes and entry count of each summary
+func summaryRecords(summaries []metadataSummary) [][]string {
+	rows := make([][]string, len(summaries))
+	for i, s := range summaries {
+		rows[i] = []string{s.Name, fmt.Sprint(s.TotalMinutes), fmt.Sprint(s.Count)}
+	}
+	return rows
+}
+
+// summaryColumns are the columns of summaryRecords
+var summaryColumns = []string{"name", "total_minutes", "count"}
+
+// addRoundDisplayFlag adds --round-display to a command showing durations
+func addRoundDisplayFlag(cmd *cobra.Command) {
+	cmd.Flags().Int("round-display", 0, "Show durations rounded to the nearest N minutes, keeping storage exact (0 shows them exactly)")
+}
+
+// roundDisplayStep returns the --round-display step of cmd, or fallback when
+// the flag is not given. An invalid step is reported to stderr; ok is false then.
+func roundDisplayStep(cmd *cobra.Command, fallback int) (int, bool) {
+	if !cmd.Flags().Changed("round-display") {
+		return fallback, true
+	}
+	step, _ := cmd.Flags().GetInt("round-display")
+	if step < 0 || step > entry.MaxDurationMinutes {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --round-display value %d\n", step)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use a number of minutes, e.g. --round-display 15")
+		deps.Exit(1)
+		return 0, false
+	}
+	return step, true
+}
+
+// roundDisplayEntries returns copies of entries with their durations rounded
+// to the nearest multiple of step (see entry.RoundDurationNearest), so that
+// totals computed from them add up the rounded durations
+func roundDisplayEntries(entries []entry.Entry, step int) []entry.Entry {
+	if step <= 0 {
+		return entries
+	}
+	rounded := make([]entry.Entry, len(entries))
+	for i, e := range entries {
+		e.DurationMinutes = entry.RoundDurationNearest(e.DurationMinutes, step)
+		rounded[i] = e
+	}
+	return rounded
+}
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// setFormatFlags sets --format and --header of cmd, resetting them after the test
+func setFormatFlags(t *testing.T, cmd *cobra.Command, format string, header bool) {
+	t.Helper()
+	_ = cmd.Flags().Set("format", format)
+	if header {
+		_ = cmd.Flags().Set("header", "true")
+	}
+	t.Cleanup(func() {
+		_ = cmd.Flags().Set("format", formatHuman)
+		_ = cmd.Flags().Set("header", "false")
+	})
+}
+
+func TestListProjects_Formats(t *testing.T) {
+	tests := []struct {
+		format   string
+		header   bool
+		expected string
+	}{
+		{"tsv", false, "acme\t90\t2\nclient\t90\t1\n"},
+		{"tsv", true, "name\ttotal_minutes\tcount\nacme\t90\t2\nclient\t90\t1\n"},
+		{"plain", false, "acme\n90\n2\nclient\n90\n1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			d, stdout, stderr := testDeps(createMetadataTestEntries(t))
+			SetDeps(d)
+			defer ResetDeps()
+			setFormatFlags(t, projectsCmd, tt.format, tt.header)
+
+			listProjects(projectsCmd)
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr output: %s", stderr.String())
+			}
+			if stdout.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRunStats_Formats(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	for _, e := range []entry.Entry{
+		{Timestamp: time.Now(), Description: "standup", DurationMinutes: 15},
+		{Timestamp: time.Now(), Description: "review", DurationMinutes: 50, Project: "acme"},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	tests := []struct {
+		format   string
+		header   bool
+		expected string
+	}{
+		{"tsv", true, strings.Join(statsColumns, "\t") + "\n65\t2\t1\t65.00\t32.50\t65\n"},
+		{"plain", false, "65\n2\n1\n65.00\n32.50\n65\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			d, stdout, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			resetFilterFlags(statsCmd)
+			setFormatFlags(t, statsCmd, tt.format, tt.header)
+			_ = statsCmd.Flags().Set("date", time.Now().Format("2006-01-02"))
+			defer func() { _ = statsCmd.Flags().Set("date", "") }()
+
+			runStats(statsCmd, nil)
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr output: %s", stderr.String())
+			}
+			if stdout.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, stdout.String())
+			}
+		})
+	}
+}
+
+func TestOutputFormat_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		header   bool
+		json     bool
+		expected string
+	}{
+		{"unknown", "csv", false, false, "Valid formats: human, tsv, plain"},
+		{"header without tsv", "plain", true, false, "--header only applies to --format tsv"},
+		{"json", "tsv", false, true, "--json cannot be combined with --format tsv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, stderr := testDeps(createMetadataTestEntries(t))
+			exitCode := 0
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			setFormatFlags(t, tagsCmd, tt.format, tt.header)
+			if tt.json {
+				_ = tagsCmd.Flags().Set("json", "true")
+				defer func() { _ = tagsCmd.Flags().Set("json", "false") }()
+			}
+
+			listTags(tagsCmd)
+
+			if exitCode != 1 || stdout.Len() > 0 || !strings.Contains(stderr.String(), tt.expected) {
+				t.Errorf("Expected exit 1 and %q, got exit %d, stdout %q, stderr %q", tt.expected, exitCode, stdout.String(), stderr.String())
+			}
+		})
+	}
+}
+
+// createRoundDisplayTestEntries writes two entries of today, 20m and 23m
+func createRoundDisplayTestEntries(t *testing.T) string {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	for _, e := range []entry.Entry{
+		{Timestamp: time.Now(), Description: "standup", DurationMinutes: 20},
+		{Timestamp: time.Now(), Description: "review", DurationMinutes: 23},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	return storagePath
+}
+
+// setRoundDisplay sets --round-display of cmd, resetting it after the test
+func setRoundDisplay(t *testing.T, cmd *cobra.Command, step string) {
+	t.Helper()
+	_ = cmd.Flags().Set("round-display", step)
+	t.Cleanup(func() {
+		_ = cmd.Flags().Set("round-display", "0")
+		cmd.Flags().Lookup("round-display").Changed = false
+	})
+}
+
+func TestRoundDisplay_Listing(t *testing.T) {
+	storagePath := createRoundDisplayTestEntries(t)
+	tests := []struct {
+		name     string
+		flag     string
+		expected []string
+	}{
+		{"configured", "", []string{"standup (15m)", "review (30m)", "Total: 45m"}},
+		{"flag overrides config", "0", []string{"standup (20m)", "review (23m)", "Total: 43m"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.RoundDisplayMinutes = 15
+			d, stdout, stderr := testDepsWithConfig(storagePath, cfg)
+			SetDeps(d)
+			defer ResetDeps()
+			resetTimePeriodFlags(rootCmd)
+			resetFilterFlags(rootCmd)
+			if tt.flag != "" {
+				setRoundDisplay(t, rootCmd, tt.flag)
+			}
+
+			rootCmd.Run(rootCmd, []string{})
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr output: %s", stderr.String())
+			}
+			for _, want := range tt.expected {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("Expected %q in output, got:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+
+	entries, _ := storage.ReadEntries(storagePath)
+	if entries[0].DurationMinutes != 20 || entries[1].DurationMinutes != 23 {
+		t.Errorf("Expected storage to keep the exact durations, got %d and %d", entries[0].DurationMinutes, entries[1].DurationMinutes)
+	}
+}
+
+func TestRoundDisplay_Stats(t *testing.T) {
+	d, stdout, stderr := testDeps(createRoundDisplayTestEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	resetFilterFlags(statsCmd)
+	setFormatFlags(t, statsCmd, formatPlain, false)
+	setRoundDisplay(t, statsCmd, "15")
+	_ = statsCmd.Flags().Set("date", time.Now().Format("2006-01-02"))
+	defer func() { _ = statsCmd.Flags().Set("date", "") }()
+
+	runStats(statsCmd, nil)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	if expected := "45\n2\n1\n45.00\n22.50\n45\n"; stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+}
+
+func TestRoundDisplay_Export(t *testing.T) {
+	storagePath := createRoundDisplayTestEntries(t)
+	for _, tt := range []struct {
+		flag     string
+		expected []int
+	}{
+		{"", []int{20, 23}}, // round_display_minutes does not apply to exports
+		{"15", []int{15, 30}},
+	} {
+		cfg := config.DefaultConfig()
+		cfg.RoundDisplayMinutes = 15
+		d, stdout, _ := testDepsWithConfig(storagePath, cfg)
+		SetDeps(d)
+		if tt.flag != "" {
+			setRoundDisplay(t, exportJSONCmd, tt.flag)
+		}
+
+		exportJSON(exportJSONCmd)
+		ResetDeps()
+
+		var result ExportOutput
+		if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+			t.Fatalf("Failed to parse JSON output: %v", err)
+		}
+		var got []int
+		for _, e := range result.Entries {
+			got = append(got, e.DurationMinutes)
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("--round-display %q: durations %v, expected %v", tt.flag, got, tt.expected)
+		}
+	}
+}
+
+func TestRoundDisplay_Invalid(t *testing.T) {
+	d, _, stderr := testDeps(createRoundDisplayTestEntries(t))
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	setRoundDisplay(t, statsCmd, "-5")
+
+	runStats(statsCmd, nil)
+
+	if exitCode != 1 || !strings.Contains(stderr.String(), "Invalid --round-display value -5") {
+		t.Errorf("Expected exit 1 with an invalid value error, got exit %d: %s", exitCode, stderr.String())
+	}
+}
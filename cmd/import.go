@@ -0,0 +1,652 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/entry"
+)
+
+// importFields are the entry fields a CSV column can be mapped to, in display order
+var importFields = []string{"date", "description", "duration_minutes", "project", "client", "tags"}
+
+// requiredImportFields must be present in every imported CSV
+var requiredImportFields = []string{"date", "description", "duration_minutes"}
+
+// defaultImportDateLayouts are tried in order when no --date-format is given
+var defaultImportDateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// importCmd represents the import parent command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import time entries from other formats",
+	Long: `Import time entries from other formats, e.g. exports of other time trackers.
+
+Available formats:
+  csv     Import entries from CSV
+  json    Import entries from JSON
+
+Every importer reads the file given as argument (or with --input), or stdin
+when no file or '-' is given.
+
+Examples:
+  did import csv entries.csv                    Import a CSV exported by did
+  did import csv --preview 5 < entries.csv      Preview without importing
+  did import json backup.json                   Import a JSON export of did`,
+}
+
+// importCSVCmd represents the import csv command
+var importCSVCmd = &cobra.Command{
+	Use:   "csv [file]",
+	Short: "Import time entries from CSV",
+	Long: `Import time entries from a CSV file, or from stdin when no file or '-' is given.
+
+The first row must be a header. By default the columns are expected to be
+named like the ones written by 'did export csv': date, description,
+duration_minutes, project and tags (tags separated by ';'). Other columns
+are ignored.
+
+Column Mapping:
+  Use --map field=Column to read a field from a differently named column.
+  Fields: date, description, duration_minutes (required), project, tags.
+  Missing required columns are reported before anything is imported.
+
+Date and Duration Formats:
+  --date-format sets the format of the date column using YYYY, MM, DD, HH,
+  mm and ss (e.g. DD.MM.YYYY or MM/DD/YYYY HH:mm). By default ISO dates and
+  timestamps are accepted.
+  --duration-unit hours reads the duration column as decimal hours (1.5 = 1h 30m).
+  Durations longer than max_entry_duration are rejected unless --allow-long
+  is given.
+
+If any row is invalid, nothing is imported and every invalid row is reported.
+Use --preview N to show the first N parsed entries without importing.
+
+Examples:
+  did import csv entries.csv
+  did import csv < entries.csv
+  did import csv --map date=Day --map description=Task --map duration_minutes=Mins < toggl.csv
+  did import csv --date-format DD.MM.YYYY --duration-unit hours < hours.csv
+  did import csv --map description=Task --preview 5 < tracker.csv`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		importCSV(cmd, args)
+	},
+}
+
+// importJSONCmd represents the import json command
+var importJSONCmd = &cobra.Command{
+	Use:   "json [file]",
+	Short: "Import time entries from JSON",
+	Long: `Import time entries from a JSON file, or from stdin when no file or '-' is given.
+
+The input is the output of 'did export json', or just its array of entries.
+Each entry needs a timestamp, a non-empty description and a duration_minutes
+of 1 up to max_entry_duration (1440 by default, no limit with --allow-long);
+project and tags, if set, must be valid names.
+
+Invalid entries are reported with their position in the entries array
+(counting from 1) and skipped; the valid entries are imported. Use --strict
+to import nothing if any entry is invalid. Use --preview N to show the first
+N valid entries without importing.
+
+Examples:
+  did import json backup.json
+  did import json --strict < backup.json
+  did export json --last 7 | did import json --preview 5`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		importJSON(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importCSVCmd)
+	importCmd.AddCommand(importJSONCmd)
+
+	importCmd.PersistentFlags().StringP("input", "i", "", "Read from this file instead of stdin ('-' for stdin), like the file argument")
+	importCmd.PersistentFlags().Bool("allow-long", false, "Import entries longer than max_entry_duration, e.g. ones logged with --allow-long")
+
+	importCSVCmd.Flags().StringArray("map", []string{}, "Map a field to a CSV column as field=Column (can be repeated)")
+	importCSVCmd.Flags().String("date-format", "", "Format of the date column, e.g. DD/MM/YYYY or YYYY-MM-DD HH:mm")
+	importCSVCmd.Flags().String("duration-unit", "minutes", "Unit of the duration column: minutes or hours")
+	importCSVCmd.Flags().Int("preview", 0, "Show the first N parsed entries without importing")
+
+	importJSONCmd.Flags().Bool("strict", false, "Import nothing if any entry is invalid")
+	importJSONCmd.Flags().Int("preview", 0, "Show the first N valid entries without importing")
+}
+
+// csvImportOptions controls how CSV rows are parsed into entries
+type csvImportOptions struct {
+	columns       map[string]string // field -> CSV column name
+	dateLayouts   []string
+	durationHours bool
+	location      *time.Location
+	maxMinutes    int // longest duration allowed, see importMaxMinutes
+}
+
+// openImportInput opens the file given as argument or with --input. Without
+// either, or with "-", it reads deps.Stdin. Returns the input, its name for
+// messages ("stdin" or the path) and a function closing it; reports errors
+// and returns false.
+func openImportInput(cmd *cobra.Command, args []string) (io.Reader, string, func(), bool) {
+	path, _ := cmd.InheritedFlags().GetString("input")
+	if len(args) > 0 {
+		if path != "" {
+			_, _ = fmt.Fprintln(deps.Stderr, "Error: Give the file to import either as argument or with --input, not both")
+			deps.Exit(1)
+			return nil, "", nil, false
+		}
+		path = args[0]
+	}
+	if path == "" || path == "-" {
+		return deps.Stdin, "stdin", func() {}, true
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Failed to open '%s'\n", path)
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that the file exists and is readable")
+		deps.Exit(1)
+		return nil, "", nil, false
+	}
+	return f, path, func() { _ = f.Close() }, true
+}
+
+// importCSV reads CSV from a file or stdin and appends the parsed entries to storage
+func importCSV(cmd *cobra.Command, args []string) {
+	mappings, _ := cmd.Flags().GetStringArray("map")
+	dateFormat, _ := cmd.Flags().GetString("date-format")
+	durationUnit, _ := cmd.Flags().GetString("duration-unit")
+	preview, _ := cmd.Flags().GetInt("preview")
+
+	columns, err := parseImportMappings(mappings)
+	if err != nil {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Use --map field=Column with one of: %s\n", strings.Join(importFields, ", "))
+		deps.Exit(1)
+		return
+	}
+
+	if durationUnit != "minutes" && durationUnit != "hours" {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --duration-unit '%s'\n", durationUnit)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use 'minutes' or 'hours'")
+		deps.Exit(1)
+		return
+	}
+
+	if preview < 0 {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: --preview must be a positive number (got %d)\n", preview)
+		deps.Exit(1)
+		return
+	}
+
+	loc := configuredLocation()
+
+	opts := csvImportOptions{
+		columns:       columns,
+		dateLayouts:   defaultImportDateLayouts,
+		durationHours: durationUnit == "hours",
+		location:      loc,
+		maxMinutes:    importMaxMinutes(cmd),
+	}
+	if dateFormat != "" {
+		opts.dateLayouts = []string{convertDateFormat(dateFormat)}
+	}
+
+	input, source, closeInput, ok := openImportInput(cmd, args)
+	if !ok {
+		return
+	}
+	defer closeInput()
+
+	reader := csv.NewReader(input)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read CSV header")
+		if errors.Is(err, io.EOF) {
+			_, _ = fmt.Fprintln(deps.Stderr, "Details: input is empty")
+		} else {
+			_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		}
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Import a CSV file with a header row, e.g. did import csv entries.csv")
+		deps.Exit(1)
+		return
+	}
+
+	// Resolve the column index of every mapped field and report missing ones upfront
+	indices, missing := resolveImportColumns(header, opts.columns)
+	if len(missing) > 0 {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Missing required columns:")
+		for _, field := range missing {
+			_, _ = fmt.Fprintf(deps.Stderr, "  %s (expected column '%s')\n", field, opts.columns[field])
+		}
+		_, _ = fmt.Fprintf(deps.Stderr, "Found columns: %s\n", strings.Join(header, ", "))
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use --map field=Column to map a field to a column, e.g. --map description=Task")
+		deps.Exit(1)
+		return
+	}
+
+	// Parse every row before writing anything
+	var entries []entry.Entry
+	var rowErrors []string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, err.Error())
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlankRecord(record) {
+			continue
+		}
+
+		e, err := parseImportRecord(record, indices, opts)
+		if err != nil {
+			rowErrors = append(rowErrors, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	if len(rowErrors) > 0 {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Found %d invalid %s, nothing was imported:\n", len(rowErrors), pluralize("row", "rows", len(rowErrors)))
+		for _, rowErr := range rowErrors {
+			_, _ = fmt.Fprintf(deps.Stderr, "  %s\n", rowErr)
+		}
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check --map, --date-format and --duration-unit against the CSV")
+		deps.Exit(1)
+		return
+	}
+
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, "No entries found to import")
+		return
+	}
+
+	if preview > 0 {
+		showImportPreview(entries, preview)
+		return
+	}
+
+	appendImportedEntries(entries, source)
+}
+
+// appendImportedEntries appends entries imported from source to storage, in
+// the file of this user when the storage path is a directory
+func appendImportedEntries(entries []entry.Entry, source string) {
+	store, err := deps.Store()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return
+	}
+
+	if !prepareStorageDir(store.Path()) {
+		return
+	}
+
+	for i := range entries {
+		entries[i].Source = deps.Config.MyFile
+	}
+	// All entries are written at once, so a failed import stores nothing
+	if _, err := store.AppendAll(entries); err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to save imported entries to storage, nothing was imported")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		printStorageWriteHint(store.Path(), err)
+		deps.Exit(1)
+		return
+	}
+
+	_, _ = fmt.Fprintf(deps.Stdout, "Imported %s from %s\n", formatCount(len(entries), "entry", "entries"), source)
+}
+
+// importJSON reads JSON from a file or stdin and appends the valid entries to storage
+func importJSON(cmd *cobra.Command, args []string) {
+	strict, _ := cmd.Flags().GetBool("strict")
+	preview, _ := cmd.Flags().GetInt("preview")
+
+	if preview < 0 {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: --preview must be a positive number (got %d)\n", preview)
+		deps.Exit(1)
+		return
+	}
+
+	input, source, closeInput, ok := openImportInput(cmd, args)
+	if !ok {
+		return
+	}
+	defer closeInput()
+
+	data, err := io.ReadAll(input)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read JSON input")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		deps.Exit(1)
+		return
+	}
+
+	items, err := decodeImportJSON(data)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to parse JSON input")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Import the output of 'did export json', e.g. did import json backup.json")
+		deps.Exit(1)
+		return
+	}
+
+	maxMinutes := importMaxMinutes(cmd)
+	var entries []entry.Entry
+	var entryErrors []string
+	for i, item := range items {
+		e, err := parseImportJSONEntry(item, maxMinutes)
+		if err != nil {
+			entryErrors = append(entryErrors, fmt.Sprintf("entry %d: %v", i+1, err))
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	if len(entryErrors) > 0 {
+		switch {
+		case strict:
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Found %s, nothing was imported:\n", formatCount(len(entryErrors), "invalid entry", "invalid entries"))
+		case len(entries) == 0:
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: %s, nothing was imported:\n",
+				pluralize("The only entry is invalid", fmt.Sprintf("None of the %d entries are valid", len(entryErrors)), len(entryErrors)))
+		default:
+			_, _ = fmt.Fprintf(deps.Stderr, "Warning: Skipping %s:\n", formatCount(len(entryErrors), "invalid entry", "invalid entries"))
+		}
+		for _, entryErr := range entryErrors {
+			_, _ = fmt.Fprintf(deps.Stderr, "  %s\n", entryErr)
+		}
+		if strict || len(entries) == 0 {
+			deps.Exit(1)
+			return
+		}
+	}
+
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, "No entries found to import")
+		return
+	}
+
+	if preview > 0 {
+		showImportPreview(entries, preview)
+		return
+	}
+
+	appendImportedEntries(entries, source)
+}
+
+// decodeImportJSON returns the raw entries of a 'did export json' document or
+// of a bare array of entries
+func decodeImportJSON(data []byte) ([]json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("input is empty")
+	}
+
+	if data[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+
+	var export struct {
+		Entries *[]json.RawMessage `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+	if export.Entries == nil {
+		return nil, errors.New("no \"entries\" array found")
+	}
+	return *export.Entries, nil
+}
+
+// importMaxMinutes returns the longest duration an imported entry may have:
+// max_entry_duration, or with --allow-long the longest duration a logged
+// entry can have
+func importMaxMinutes(cmd *cobra.Command) int {
+	if allowLong, _ := cmd.InheritedFlags().GetBool("allow-long"); allowLong {
+		return entry.MaxParsedMinutes
+	}
+	return deps.Config.MaxEntryMinutes()
+}
+
+// parseImportJSONEntry decodes and validates a single imported entry, up to
+// maxMinutes long
+func parseImportJSONEntry(item json.RawMessage, maxMinutes int) (entry.Entry, error) {
+	var e entry.Entry
+	if err := json.Unmarshal(item, &e); err != nil {
+		return entry.Entry{}, err
+	}
+	if err := e.ValidateWithMaxDuration(maxMinutes); err != nil {
+		return entry.Entry{}, err
+	}
+	if e.RawInput == "" {
+		e.RawInput = formatRawInput(e)
+	}
+	return e, nil
+}
+
+// parseImportMappings parses --map field=Column flags on top of the default
+// column names, which match the fields themselves
+func parseImportMappings(mappings []string) (map[string]string, error) {
+	columns := make(map[string]string, len(importFields))
+	for _, field := range importFields {
+		columns[field] = field
+	}
+
+	mapped := make(map[string]bool)
+	for _, mapping := range mappings {
+		field, column, ok := strings.Cut(mapping, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		column = strings.TrimSpace(column)
+		if !ok || field == "" || column == "" {
+			return nil, fmt.Errorf("invalid mapping '%s', expected field=Column", mapping)
+		}
+		if _, known := columns[field]; !known {
+			return nil, fmt.Errorf("unknown field '%s' in mapping '%s'", field, mapping)
+		}
+		columns[field] = column
+		mapped[field] = true
+	}
+
+	// A column mapped to one field is not also read as the field it is named
+	// after, e.g. with project=Client the Client column is not the client
+	for field, column := range columns {
+		for other := range mapped {
+			if other != field && !mapped[field] && strings.EqualFold(columns[other], column) {
+				columns[field] = ""
+			}
+		}
+	}
+
+	return columns, nil
+}
+
+// resolveImportColumns finds the header index of each mapped column (case-insensitive).
+// Returns the indices of found fields and the required fields whose column is missing.
+func resolveImportColumns(header []string, columns map[string]string) (map[string]int, []string) {
+	indices := make(map[string]int)
+	for _, field := range importFields {
+		if columns[field] == "" {
+			continue
+		}
+		for i, name := range header {
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")), columns[field]) {
+				indices[field] = i
+				break
+			}
+		}
+	}
+
+	var missing []string
+	for _, field := range requiredImportFields {
+		if _, ok := indices[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	return indices, missing
+}
+
+// parseImportRecord converts a CSV record into an entry
+func parseImportRecord(record []string, indices map[string]int, opts csvImportOptions) (entry.Entry, error) {
+	value := func(field string) string {
+		i, ok := indices[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	timestamp, err := parseImportDate(value("date"), opts)
+	if err != nil {
+		return entry.Entry{}, err
+	}
+
+	minutes, err := parseImportDuration(value("duration_minutes"), opts.durationHours, opts.maxMinutes)
+	if err != nil {
+		return entry.Entry{}, err
+	}
+
+	// Inline @project/#tags in the description are honored like when logging
+	description, project, tags := entry.ParseProjectAndTags(value("description"))
+	if p := strings.TrimPrefix(value("project"), "@"); p != "" {
+		project = p
+	}
+	for _, tag := range strings.Split(value("tags"), ";") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if tag != "" && !containsTag(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	e := entry.Entry{
+		Timestamp:       timestamp,
+		Description:     description,
+		DurationMinutes: minutes,
+		Project:         project,
+		Client:          value("client"),
+		Tags:            tags,
+	}
+	if err := e.ValidateWithMaxDuration(opts.maxMinutes); err != nil {
+		return entry.Entry{}, err
+	}
+	e.RawInput = formatRawInput(e)
+	return e, nil
+}
+
+// parseImportDate parses the date column using the configured layouts
+func parseImportDate(value string, opts csvImportOptions) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("date is empty")
+	}
+	for _, layout := range opts.dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, opts.location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse date '%s'", value)
+}
+
+// parseImportDuration parses the duration column as whole minutes or decimal
+// hours, up to maxMinutes
+func parseImportDuration(value string, hours bool, maxMinutes int) (int, error) {
+	if value == "" {
+		return 0, errors.New("duration is empty")
+	}
+
+	var minutes int
+	if hours {
+		h, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+		if err != nil || math.IsNaN(h) {
+			return 0, fmt.Errorf("cannot parse duration '%s' as hours", value)
+		}
+		// Check the bounds before converting, since a huge value overflows int
+		rounded := math.Round(h * 60)
+		if rounded > float64(maxMinutes) {
+			return 0, fmt.Errorf("duration '%s' exceeds the maximum of %s", value, formatDuration(maxMinutes))
+		}
+		minutes = int(rounded)
+	} else {
+		m, err := strconv.Atoi(value)
+		if errors.Is(err, strconv.ErrRange) && !strings.HasPrefix(value, "-") {
+			return 0, fmt.Errorf("duration '%s' exceeds the maximum of %s", value, formatDuration(maxMinutes))
+		}
+		if err != nil {
+			return 0, fmt.Errorf("cannot parse duration '%s' as minutes", value)
+		}
+		minutes = m
+	}
+
+	if minutes <= 0 {
+		return 0, fmt.Errorf("duration '%s' must be positive", value)
+	}
+	if minutes > maxMinutes {
+		return 0, fmt.Errorf("duration '%s' exceeds the maximum of %s", value, formatDuration(maxMinutes))
+	}
+	return minutes, nil
+}
+
+// convertDateFormat converts a format using YYYY, MM, DD, HH, mm and ss
+// tokens into a Go time layout
+func convertDateFormat(format string) string {
+	replacer := strings.NewReplacer(
+		"YYYY", "2006",
+		"MM", "01",
+		"DD", "02",
+		"HH", "15",
+		"mm", "04",
+		"ss", "05",
+	)
+	return replacer.Replace(format)
+}
+
+// isBlankRecord reports whether every field of a CSV record is empty
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// showImportPreview displays the first n parsed entries without importing
+func showImportPreview(entries []entry.Entry, n int) {
+	shown := min(n, len(entries))
+	_, _ = fmt.Fprintf(deps.Stdout, "Preview (%d of %d parsed entries):\n", shown, len(entries))
+	for _, e := range entries[:shown] {
+		_, _ = fmt.Fprintf(deps.Stdout, "  %s  %s (%s)\n",
+			e.Timestamp.Format("2006-01-02 15:04"),
+			formatEntryForLog(e.Description, displayProject(e), e.Tags),
+			formatDuration(e.DurationMinutes))
+	}
+	_, _ = fmt.Fprintln(deps.Stdout)
+	_, _ = fmt.Fprintln(deps.Stdout, "Nothing was imported. Run again without --preview to import.")
+}
@@ -0,0 +1,677 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// setImportFlags sets the import csv flags and resets them when the test finishes
+func setImportFlags(t *testing.T, mappings []string, dateFormat, durationUnit string, preview int) {
+	t.Helper()
+	for _, m := range mappings {
+		_ = importCSVCmd.Flags().Set("map", m)
+	}
+	if dateFormat != "" {
+		_ = importCSVCmd.Flags().Set("date-format", dateFormat)
+	}
+	if durationUnit != "" {
+		_ = importCSVCmd.Flags().Set("duration-unit", durationUnit)
+	}
+	if preview != 0 {
+		_ = importCSVCmd.Flags().Set("preview", strconv.Itoa(preview))
+	}
+	t.Cleanup(func() {
+		f := importCSVCmd.Flags().Lookup("map")
+		if sliceVal, ok := f.Value.(interface{ Replace([]string) error }); ok {
+			_ = sliceVal.Replace([]string{})
+		}
+		f.Changed = false
+		_ = importCSVCmd.Flags().Set("date-format", "")
+		_ = importCSVCmd.Flags().Set("duration-unit", "minutes")
+		_ = importCSVCmd.Flags().Set("preview", "0")
+	})
+}
+
+// runImportTest runs import csv on input and returns the storage path, exit code, stdout and stderr
+func runImportTest(t *testing.T, input string) (string, int, string, string) {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+
+	d, stdout, stderr := testDeps(storagePath)
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	d.Stdin = strings.NewReader(input)
+	SetDeps(d)
+	t.Cleanup(ResetDeps)
+
+	importCSV(importCSVCmd, nil)
+
+	return storagePath, exitCode, stdout.String(), stderr.String()
+}
+
+func TestImportCSV_DefaultColumns(t *testing.T) {
+	setImportFlags(t, nil, "", "", 0)
+	input := "date,description,duration_minutes,duration_hours,project,tags\n" +
+		"2024-01-15,Code review,60,1.00,acme,review;pr\n" +
+		"2024-01-16,Bug fix #urgent,90,1.50,,\n"
+
+	storagePath, exitCode, stdout, stderr := runImportTest(t, input)
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "Imported 2 entries from stdin") {
+		t.Errorf("Expected import summary, got: %s", stdout)
+	}
+
+	entries, err := storage.ReadEntries(storagePath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Description != "Code review" || entries[0].DurationMinutes != 60 || entries[0].Project != "acme" {
+		t.Errorf("Unexpected first entry: %+v", entries[0])
+	}
+	if len(entries[0].Tags) != 2 || entries[0].Tags[0] != "review" || entries[0].Tags[1] != "pr" {
+		t.Errorf("Expected tags [review pr], got %v", entries[0].Tags)
+	}
+	if entries[0].Timestamp.Format("2006-01-02") != "2024-01-15" {
+		t.Errorf("Expected date 2024-01-15, got %s", entries[0].Timestamp.Format("2006-01-02"))
+	}
+	if entries[1].Description != "Bug fix" || len(entries[1].Tags) != 1 || entries[1].Tags[0] != "urgent" {
+		t.Errorf("Expected inline tag to be parsed, got %+v", entries[1])
+	}
+	if entries[0].RawInput == "" {
+		t.Error("Expected RawInput to be set")
+	}
+}
+
+func TestImportCSV_MappedColumns(t *testing.T) {
+	setImportFlags(t, []string{"date=Day", "description=Task", "duration_minutes=Mins", "project=Client"}, "", "", 0)
+	input := "Day,Task,Mins,Client,Billable\n2024-01-15,Planning,45,acme,yes\n"
+
+	storagePath, exitCode, _, stderr := runImportTest(t, input)
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", exitCode, stderr)
+	}
+	entries, _ := storage.ReadEntries(storagePath)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	// The Client column is mapped to the project, so it is not also read as the client
+	if entries[0].Description != "Planning" || entries[0].DurationMinutes != 45 || entries[0].Project != "acme" || entries[0].Client != "" {
+		t.Errorf("Unexpected entry: %+v", entries[0])
+	}
+}
+
+func TestImportCSV_ClientColumn(t *testing.T) {
+	setImportFlags(t, nil, "", "", 0)
+	input := "date,description,duration_minutes,project,tags,client\n" +
+		"2024-01-15,Code review,60,backend,,AcmeCorp\n" +
+		"2024-01-16,Planning,30,,,\n"
+
+	storagePath, exitCode, _, stderr := runImportTest(t, input)
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", exitCode, stderr)
+	}
+	entries, _ := storage.ReadEntries(storagePath)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Client != "AcmeCorp" || entries[0].Project != "backend" || entries[1].Client != "" {
+		t.Errorf("Expected the client column to be imported, got %+v", entries)
+	}
+}
+
+func TestImportCSV_MissingRequiredColumns(t *testing.T) {
+	setImportFlags(t, []string{"description=Task"}, "", "", 0)
+	input := "Day,Task,Hours\n2024-01-15,Planning,1\n"
+
+	storagePath, exitCode, _, stderr := runImportTest(t, input)
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr, "Missing required columns") {
+		t.Errorf("Expected missing columns error, got: %s", stderr)
+	}
+	if !strings.Contains(stderr, "date (expected column 'date')") || !strings.Contains(stderr, "duration_minutes (expected column 'duration_minutes')") {
+		t.Errorf("Expected every missing column to be listed, got: %s", stderr)
+	}
+	if strings.Contains(stderr, "description (") {
+		t.Errorf("Mapped column should not be reported missing, got: %s", stderr)
+	}
+	if _, err := os.Stat(storagePath); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be written")
+	}
+}
+
+func TestImportCSV_InvalidMapping(t *testing.T) {
+	setImportFlags(t, []string{"billable=Yes"}, "", "", 0)
+
+	_, exitCode, _, stderr := runImportTest(t, "date,description,duration_minutes\n")
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr, "unknown field 'billable'") {
+		t.Errorf("Expected unknown field error, got: %s", stderr)
+	}
+}
+
+func TestImportCSV_DateFormatAndHours(t *testing.T) {
+	setImportFlags(t, []string{"duration_minutes=Hours"}, "DD.MM.YYYY", "hours", 0)
+	input := "date,description,Hours\n15.01.2024,Workshop,1.5\n16.01.2024,Support,\"0,25\"\n"
+
+	storagePath, exitCode, _, stderr := runImportTest(t, input)
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", exitCode, stderr)
+	}
+	entries, _ := storage.ReadEntries(storagePath)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].DurationMinutes != 90 || entries[1].DurationMinutes != 15 {
+		t.Errorf("Expected durations 90 and 15, got %d and %d", entries[0].DurationMinutes, entries[1].DurationMinutes)
+	}
+	if entries[0].Timestamp.Format("2006-01-02") != "2024-01-15" {
+		t.Errorf("Expected date 2024-01-15, got %s", entries[0].Timestamp.Format("2006-01-02"))
+	}
+}
+
+func TestImportCSV_InvalidRowsImportNothing(t *testing.T) {
+	setImportFlags(t, nil, "", "", 0)
+	input := "date,description,duration_minutes\n" +
+		"2024-01-15,Good row,30\n" +
+		"15/01/2024,Bad date,30\n" +
+		"2024-01-15,Bad duration,abc\n"
+
+	storagePath, exitCode, _, stderr := runImportTest(t, input)
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr, "Found 2 invalid rows") {
+		t.Errorf("Expected invalid row count, got: %s", stderr)
+	}
+	if !strings.Contains(stderr, "line 3: cannot parse date '15/01/2024'") || !strings.Contains(stderr, "line 4: cannot parse duration 'abc'") {
+		t.Errorf("Expected each invalid row to be reported with its line, got: %s", stderr)
+	}
+	if _, err := os.Stat(storagePath); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be written")
+	}
+}
+
+func TestImportCSV_WriteErrorImportsNothing(t *testing.T) {
+	setImportFlags(t, nil, "", "", 0)
+	// A storage directory without my_file has no file to import into
+	d, stdout, stderr := testDeps(t.TempDir())
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	d.Stdin = strings.NewReader("date,description,duration_minutes\n2024-01-15,First,30\n2024-01-16,Second,45\n")
+	SetDeps(d)
+	defer ResetDeps()
+
+	importCSV(importCSVCmd, nil)
+
+	if exitCode != 1 || !strings.Contains(stderr.String(), "Error: Failed to save imported entries to storage, nothing was imported") {
+		t.Errorf("Expected a save error, got exit %d: %s", exitCode, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "Imported") {
+		t.Errorf("Expected no import summary, got: %s", stdout.String())
+	}
+}
+
+func TestImportCSV_Preview(t *testing.T) {
+	setImportFlags(t, nil, "", "", 2)
+	input := "date,description,duration_minutes\n" +
+		"2024-01-15,First,30\n" +
+		"2024-01-16,Second,45\n" +
+		"2024-01-17,Third,60\n"
+
+	storagePath, exitCode, stdout, stderr := runImportTest(t, input)
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "Preview (2 of 3 parsed entries)") {
+		t.Errorf("Expected preview header, got: %s", stdout)
+	}
+	if !strings.Contains(stdout, "First") || !strings.Contains(stdout, "Second") || strings.Contains(stdout, "Third") {
+		t.Errorf("Expected only the first two entries, got: %s", stdout)
+	}
+	if _, err := os.Stat(storagePath); !os.IsNotExist(err) {
+		t.Error("Expected preview not to write entries")
+	}
+}
+
+func TestImportCSV_EmptyInput(t *testing.T) {
+	setImportFlags(t, nil, "", "", 0)
+
+	_, exitCode, _, stderr := runImportTest(t, "")
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr, "Failed to read CSV header") {
+		t.Errorf("Expected header error, got: %s", stderr)
+	}
+}
+
+// runImportJSONTest runs import json on input with the given flags and returns
+// the storage path, exit code, stdout and stderr
+func runImportJSONTest(t *testing.T, input string, strict bool, preview int) (string, int, string, string) {
+	t.Helper()
+	_ = importJSONCmd.Flags().Set("strict", strconv.FormatBool(strict))
+	_ = importJSONCmd.Flags().Set("preview", strconv.Itoa(preview))
+	t.Cleanup(func() {
+		_ = importJSONCmd.Flags().Set("strict", "false")
+		_ = importJSONCmd.Flags().Set("preview", "0")
+	})
+
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	d, stdout, stderr := testDeps(storagePath)
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	d.Stdin = strings.NewReader(input)
+	SetDeps(d)
+	t.Cleanup(ResetDeps)
+
+	importJSON(importJSONCmd, nil)
+
+	return storagePath, exitCode, stdout.String(), stderr.String()
+}
+
+// mixedImportJSON is an export with two valid and two invalid entries
+const mixedImportJSON = `{
+  "metadata": {"total_entries": 4},
+  "entries": [
+    {"timestamp": "2024-01-15T09:00:00Z", "description": "standup", "duration_minutes": 15, "raw_input": "standup for 15m"},
+    {"timestamp": "2024-01-15T10:00:00Z", "description": "", "duration_minutes": 30},
+    {"timestamp": "2024-01-15T11:00:00Z", "description": "review", "duration_minutes": -45, "tags": ["review"]},
+    {"timestamp": "2024-01-15T13:00:00Z", "description": "fix login", "duration_minutes": 90, "project": "acme", "tags": ["bugfix"]}
+  ]
+}`
+
+func TestImportJSON_SkipsInvalidEntries(t *testing.T) {
+	storagePath, exitCode, stdout, stderr := runImportJSONTest(t, mixedImportJSON, false, 0)
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", exitCode, stderr)
+	}
+	for _, want := range []string{
+		"Warning: Skipping 2 invalid entries:",
+		"entry 2: description is empty",
+		"entry 3: duration must be positive (got -45 minutes)",
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("Expected stderr to contain %q, got: %s", want, stderr)
+		}
+	}
+	if !strings.Contains(stdout, "Imported 2 entries") {
+		t.Errorf("Expected 'Imported 2 entries', got: %s", stdout)
+	}
+
+	entries, err := storage.ReadEntries(storagePath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].RawInput != "standup for 15m" {
+		t.Errorf("Expected the raw input to be kept, got %q", entries[0].RawInput)
+	}
+	if entries[1].Project != "acme" || entries[1].RawInput != "fix login @acme #bugfix for 1h 30m" {
+		t.Errorf("Unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestImport_MaxEntryDuration(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.MaxEntryDuration = "8h"
+
+	t.Run("csv", func(t *testing.T) {
+		storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+		d, _, stderr := testDepsWithConfig(storagePath, cfg)
+		d.Stdin = strings.NewReader("date,description,duration_minutes\n2024-01-15,workshop,480\n2024-01-16,oops,481\n")
+		d.Exit = func(int) {}
+		SetDeps(d)
+		defer ResetDeps()
+
+		importCSV(importCSVCmd, nil)
+
+		if !strings.Contains(stderr.String(), "line 3: duration '481' exceeds the maximum of 8h") {
+			t.Errorf("Expected a row error for line 3, got: %s", stderr.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+		d, stdout, stderr := testDepsWithConfig(storagePath, cfg)
+		d.Stdin = strings.NewReader(`[
+  {"timestamp": "2024-01-15T09:00:00Z", "description": "workshop", "duration_minutes": 480},
+  {"timestamp": "2024-01-16T09:00:00Z", "description": "oops", "duration_minutes": 481}
+]`)
+		SetDeps(d)
+		defer ResetDeps()
+
+		importJSON(importJSONCmd, nil)
+
+		if !strings.Contains(stderr.String(), "entry 2: duration exceeds the maximum of 8 hours (got 481 minutes)") {
+			t.Errorf("Expected entry 2 to be skipped, got: %s", stderr.String())
+		}
+		entries, _ := storage.ReadEntries(storagePath)
+		if !strings.Contains(stdout.String(), "Imported 1 entry") || len(entries) != 1 || entries[0].DurationMinutes != 480 {
+			t.Errorf("Expected only the 8h entry to be imported, got %+v (stdout: %s)", entries, stdout.String())
+		}
+	})
+}
+
+func TestImportJSON_AllowLongRoundTrip(t *testing.T) {
+	// Log an entry past max_entry_duration with --allow-long and export it
+	sourcePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	d, stdout, stderr := testDeps(sourcePath)
+	SetDeps(d)
+	_ = rootCmd.Flags().Set("allow-long", "true")
+	createEntry(rootCmd, []string{"offsite", "for", "30h"})
+	_ = rootCmd.Flags().Set("allow-long", "false")
+	stdout.Reset()
+	exportJSON(exportJSONCmd)
+	ResetDeps()
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected error logging and exporting: %s", stderr.String())
+	}
+	exported := stdout.String()
+
+	for _, allowLong := range []bool{false, true} {
+		t.Run("allow-long="+strconv.FormatBool(allowLong), func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			d, _, stderr := testDeps(storagePath)
+			d.Stdin = strings.NewReader(exported)
+			exitCode := 0
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			_ = importCmd.PersistentFlags().Set("allow-long", strconv.FormatBool(allowLong))
+			defer func() { _ = importCmd.PersistentFlags().Set("allow-long", "false") }()
+
+			importJSON(importJSONCmd, nil)
+
+			entries, _ := storage.ReadEntries(storagePath)
+			if !allowLong {
+				if exitCode != 1 || len(entries) != 0 || !strings.Contains(stderr.String(), "exceeds the maximum of 24 hours") {
+					t.Errorf("Expected the 30h entry to be rejected, got exit %d, %d entries: %s", exitCode, len(entries), stderr.String())
+				}
+				return
+			}
+			if exitCode != 0 || len(entries) != 1 || entries[0].DurationMinutes != 1800 {
+				t.Errorf("Expected the 30h entry to be imported, got exit %d, %+v: %s", exitCode, entries, stderr.String())
+			}
+		})
+	}
+}
+
+func TestImportJSON_StrictImportsNothing(t *testing.T) {
+	storagePath, exitCode, _, stderr := runImportJSONTest(t, mixedImportJSON, true, 0)
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr, "Found 2 invalid entries, nothing was imported") {
+		t.Errorf("Expected strict error, got: %s", stderr)
+	}
+	if _, err := os.Stat(storagePath); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be written")
+	}
+}
+
+func TestImportJSON_BareArray(t *testing.T) {
+	input := `[{"timestamp": "2024-01-15T09:00:00Z", "description": "standup", "duration_minutes": 15}]`
+
+	storagePath, exitCode, stdout, stderr := runImportJSONTest(t, input, true, 0)
+
+	if exitCode != 0 || stderr != "" {
+		t.Fatalf("Expected success, got exit code %d (stderr: %s)", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "Imported 1 entry") {
+		t.Errorf("Expected 'Imported 1 entry', got: %s", stdout)
+	}
+	if entries, _ := storage.ReadEntries(storagePath); len(entries) != 1 || entries[0].RawInput != "standup for 15m" {
+		t.Errorf("Expected 1 entry with a generated raw input, got %+v", entries)
+	}
+}
+
+func TestImportJSON_NoValidEntries(t *testing.T) {
+	input := `[{"description": "no timestamp", "duration_minutes": 15}, {"timestamp": "2024-01-15T09:00:00Z", "description": "bad type", "duration_minutes": "15"}]`
+
+	storagePath, exitCode, _, stderr := runImportJSONTest(t, input, false, 0)
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr, "None of the 2 entries are valid") {
+		t.Errorf("Expected no-valid-entries error, got: %s", stderr)
+	}
+	if !strings.Contains(stderr, "entry 1: timestamp is missing") || !strings.Contains(stderr, "entry 2: json: cannot unmarshal") {
+		t.Errorf("Expected each entry error with its index, got: %s", stderr)
+	}
+	if _, err := os.Stat(storagePath); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be written")
+	}
+}
+
+func TestImportJSON_OnlyEntryInvalid(t *testing.T) {
+	input := `[{"description": "no timestamp", "duration_minutes": 15}]`
+
+	_, exitCode, _, stderr := runImportJSONTest(t, input, false, 0)
+
+	if exitCode != 1 || !strings.Contains(stderr, "Error: The only entry is invalid, nothing was imported:") {
+		t.Errorf("Expected a singular error, got exit %d: %s", exitCode, stderr)
+	}
+}
+
+func TestImportJSON_Preview(t *testing.T) {
+	storagePath, exitCode, stdout, _ := runImportJSONTest(t, mixedImportJSON, false, 1)
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	if !strings.Contains(stdout, "Preview (1 of 2 parsed entries)") {
+		t.Errorf("Expected preview header, got: %s", stdout)
+	}
+	if _, err := os.Stat(storagePath); !os.IsNotExist(err) {
+		t.Error("Expected preview not to write entries")
+	}
+}
+
+func TestImportJSON_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"empty", "  \n", "input is empty"},
+		{"not json", "date,description", "invalid character"},
+		{"no entries array", `{"metadata": {}}`, `no "entries" array found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, exitCode, _, stderr := runImportJSONTest(t, tt.input, false, 0)
+
+			if exitCode != 1 {
+				t.Errorf("Expected exit code 1, got %d", exitCode)
+			}
+			if !strings.Contains(stderr, "Failed to parse JSON input") || !strings.Contains(stderr, tt.wantErr) {
+				t.Errorf("Expected parse error containing %q, got: %s", tt.wantErr, stderr)
+			}
+		})
+	}
+}
+
+func TestImportJSON_EmptyEntries(t *testing.T) {
+	_, exitCode, stdout, _ := runImportJSONTest(t, `{"entries": []}`, false, 0)
+
+	if exitCode != 0 || !strings.Contains(stdout, "No entries found to import") {
+		t.Errorf("Expected 'No entries found to import', got exit code %d: %s", exitCode, stdout)
+	}
+}
+
+func TestImport_InputSources(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "hours.csv")
+	if err := os.WriteFile(csvPath, []byte("date,description,duration_minutes\n2024-01-15,Code review,60\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	jsonPath := filepath.Join(dir, "backup.json")
+	if err := os.WriteFile(jsonPath, []byte(`[{"timestamp": "2024-01-15T09:00:00Z", "description": "standup", "duration_minutes": 15}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdinCSV := "date,description,duration_minutes\n2024-01-16,From stdin,30\n"
+
+	tests := []struct {
+		name     string
+		json     bool
+		args     []string
+		input    string
+		expected string
+		wantErr  string
+	}{
+		{name: "csv argument", args: []string{csvPath}, expected: "Imported 1 entry from " + csvPath},
+		{name: "csv --input", input: csvPath, expected: "Imported 1 entry from " + csvPath},
+		{name: "csv dash reads stdin", args: []string{"-"}, expected: "Imported 1 entry from stdin"},
+		{name: "csv stdin", expected: "Imported 1 entry from stdin"},
+		{name: "json argument", json: true, args: []string{jsonPath}, expected: "Imported 1 entry from " + jsonPath},
+		{name: "json --input dash", json: true, input: "-", expected: "Imported 1 entry from stdin"},
+		{name: "missing file", args: []string{filepath.Join(dir, "missing.csv")}, wantErr: "Error: Failed to open '" + filepath.Join(dir, "missing.csv") + "'"},
+		{name: "argument and --input", args: []string{csvPath}, input: csvPath, wantErr: "either as argument or with --input, not both"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setImportFlags(t, nil, "", "", 0)
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			d, stdout, stderr := testDeps(storagePath)
+			exitCode := 0
+			d.Exit = func(code int) { exitCode = code }
+			d.Stdin = strings.NewReader(stdinCSV)
+			if tt.json {
+				d.Stdin = strings.NewReader(`[{"timestamp": "2024-01-16T09:00:00Z", "description": "from stdin", "duration_minutes": 30}]`)
+			}
+			SetDeps(d)
+			defer ResetDeps()
+			cmd := importCSVCmd
+			if tt.json {
+				cmd = importJSONCmd
+			}
+			_ = importCmd.PersistentFlags().Set("input", tt.input)
+			defer func() { _ = importCmd.PersistentFlags().Set("input", "") }()
+
+			if tt.json {
+				importJSON(cmd, tt.args)
+			} else {
+				importCSV(cmd, tt.args)
+			}
+
+			if tt.wantErr != "" {
+				if exitCode != 1 || !strings.Contains(stderr.String(), tt.wantErr) {
+					t.Errorf("Expected exit 1 with %q, got exit %d: %s", tt.wantErr, exitCode, stderr.String())
+				}
+				return
+			}
+			if exitCode != 0 || stdout.String() != tt.expected+"\n" {
+				t.Errorf("Expected %q, got exit %d, stdout %q, stderr %q", tt.expected, exitCode, stdout.String(), stderr.String())
+			}
+			entries, _ := storage.ReadEntries(storagePath)
+			if len(entries) != 1 {
+				t.Errorf("Expected 1 imported entry, got %d", len(entries))
+			}
+		})
+	}
+}
+
+func TestParseImportDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		hours   bool
+		want    int
+		wantErr bool
+	}{
+		{"90", false, 90, false},
+		{"1.5", true, 90, false},
+		{"0.1", true, 6, false},
+		{"0", false, 0, true},
+		{"1.5", false, 0, true},
+		{"25", true, 0, true},
+		{"", false, 0, true},
+		// The limit itself is allowed, a minute more is not
+		{"1440", false, 1440, false},
+		{"1441", false, 0, true},
+		{"24", true, 1440, false},
+		// Values that would overflow int
+		{"99999999999999999999", false, 0, true},
+		{"1e300", true, 0, true},
+		{"NaN", true, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseImportDuration(tt.value, tt.hours, entry.MaxDurationMinutes)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseImportDuration(%q, %v) error = %v, wantErr %v", tt.value, tt.hours, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseImportDuration(%q, %v) = %d, want %d", tt.value, tt.hours, got, tt.want)
+		}
+	}
+}
+
+func TestConvertDateFormat(t *testing.T) {
+	tests := map[string]string{
+		"DD.MM.YYYY":       "02.01.2006",
+		"MM/DD/YYYY HH:mm": "01/02/2006 15:04",
+		"YYYY-MM-DD":       "2006-01-02",
+		"2006-01-02":       "2006-01-02",
+	}
+	for format, want := range tests {
+		if got := convertDateFormat(format); got != want {
+			t.Errorf("convertDateFormat(%q) = %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestImportJSON_KeepsLoggedAtFromSource(t *testing.T) {
+	input := `[
+  {"timestamp":"2024-01-15T09:00:00Z","description":"old","duration_minutes":30,"raw_input":""},
+  {"timestamp":"2024-01-15T10:00:00Z","description":"exported","duration_minutes":30,"raw_input":"","logged_at":"2024-01-16T08:00:00Z"}
+]`
+	storagePath, exitCode, _, stderr := runImportJSONTest(t, input, false, 0)
+	if exitCode != 0 {
+		t.Fatalf("Unexpected error (exit %d): %s", exitCode, stderr)
+	}
+
+	entries, err := storage.ReadEntries(storagePath)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %+v, %v", entries, err)
+	}
+	// An entry without logged_at must not look like it was logged today
+	if entries[0].LoggedAt != nil {
+		t.Errorf("Expected no LoggedAt for an entry without logged_at, got %v", entries[0].LoggedAt)
+	}
+	if want := time.Date(2024, 1, 16, 8, 0, 0, 0, time.UTC); entries[1].LoggedAt == nil || !entries[1].LoggedAt.Equal(want) {
+		t.Errorf("Expected LoggedAt %v from the source, got %v", want, entries[1].LoggedAt)
+	}
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/storage"
+	"github.com/xolan/did/internal/timeutil"
+)
+
+// noWizardEnv disables the first-run setup wizard when set to any value
+const noWizardEnv = "DID_NO_WIZARD"
+
+// stdinIsTerminal reports whether deps.Stdin is an interactive terminal.
+// Tests replace it to simulate a TTY.
+var stdinIsTerminal = func() bool {
+	f, ok := deps.Stdin.(*os.File)
+	return ok && term.IsTerminal(f.Fd())
+}
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Set up did with a short configuration wizard",
+	Long: `Run the setup wizard to create a configuration file.
+
+The wizard asks for the first day of the week, your timezone (defaulting to
+the detected system timezone) and where to store your entries, then writes
+the answers to the config file. Press Enter to accept the default shown in
+brackets.
+
+The wizard also runs automatically the first time you list entries on an
+interactive terminal without a config file. Set DID_NO_WIZARD to disable that.
+
+Examples:
+  did init                        Run the setup wizard
+  did init --yes                  Write a config file with all defaults`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		acceptDefaults, _ := cmd.Flags().GetBool("yes")
+		runInit(acceptDefaults)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().BoolP("yes", "y", false, "Accept all defaults without prompting")
+}
+
+// runInit runs the setup wizard on demand
+func runInit(acceptDefaults bool) {
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine config file location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return
+	}
+
+	scanner := bufio.NewScanner(deps.Stdin)
+
+	if _, err := os.Stat(configPath); err == nil {
+		if acceptDefaults {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Config file already exists: %s\n", configPath)
+			_, _ = fmt.Fprintln(deps.Stderr, "Hint: Run 'did init' without --yes to overwrite it interactively")
+			deps.Exit(1)
+			return
+		}
+		_, _ = fmt.Fprintf(deps.Stdout, "Config file already exists: %s\n", configPath)
+		answer := wizardPrompt(scanner, "Overwrite existing config file? [y/N]", "n")
+		if answer != "y" && answer != "Y" {
+			_, _ = fmt.Fprintln(deps.Stdout, "Cancelled. Existing config file not modified.")
+			return
+		}
+	}
+
+	if !runSetupWizard(scanner, configPath, acceptDefaults) {
+		deps.Exit(1)
+	}
+}
+
+// maybeRunFirstRunWizard offers the setup wizard before listing entries when no
+// config file exists yet and did is used interactively. It never runs in
+// scripts (stdin or stdout not a terminal, e.g. /dev/null) or when
+// DID_NO_WIZARD is set.
+func maybeRunFirstRunWizard() {
+	if os.Getenv(noWizardEnv) != "" || !stdinIsTerminal() || !stdoutIsTerminal() {
+		return
+	}
+
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return
+	}
+	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+		return
+	}
+
+	_, _ = fmt.Fprintln(deps.Stdout, "Welcome to did! No configuration file was found, so let's set one up.")
+	_, _ = fmt.Fprintf(deps.Stdout, "(Press Enter to accept defaults. Set %s to skip this in the future.)\n", noWizardEnv)
+	_, _ = fmt.Fprintln(deps.Stdout)
+
+	// A failed wizard is reported but must not prevent listing entries
+	runSetupWizard(bufio.NewScanner(deps.Stdin), configPath, false)
+	_, _ = fmt.Fprintln(deps.Stdout)
+}
+
+// runSetupWizard asks for the basic settings, writes them to configPath and
+// applies them to the running command. With acceptDefaults, no questions are
+// asked. Returns false if the config file could not be written.
+func runSetupWizard(scanner *bufio.Scanner, configPath string, acceptDefaults bool) bool {
+	cfg := config.DefaultConfig()
+	cfg.Timezone = timeutil.DetectTimezone()
+
+	defaultStoragePath, err := storage.GetStoragePath()
+	if err != nil {
+		defaultStoragePath = ""
+	}
+
+	if !acceptDefaults {
+		cfg.WeekStartDay = askWeekStartDay(scanner, cfg.WeekStartDay)
+		cfg.Timezone = askTimezone(scanner, cfg.Timezone)
+		cfg.StoragePath = askStoragePath(scanner, defaultStoragePath)
+	}
+	if cfg.StoragePath == defaultStoragePath {
+		// Keep the default location implicit so it follows the data directory
+		cfg.StoragePath = ""
+	}
+
+	if err := writeConfigFile(configPath, config.GenerateConfig(cfg)); err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to create config file")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that the directory is writable: %s\n", filepath.Dir(configPath))
+		return false
+	}
+
+	// Apply the new settings to the current run
+	deps.Config = cfg
+	if cfg.StoragePath != "" {
+		deps.StoragePath = func() (string, error) { return storage.ResolveStoragePath(cfg.StoragePath) }
+	}
+
+	_, _ = fmt.Fprintf(deps.Stdout, "Saved configuration to %s\n", configPath)
+	return true
+}
+
+// askWeekStartDay prompts until a valid week start day is given
+func askWeekStartDay(scanner *bufio.Scanner, def string) string {
+	for {
+		answer := strings.ToLower(wizardPrompt(scanner, fmt.Sprintf("Week starts on (monday/sunday) [%s]", def), def))
+		if answer == "monday" || answer == "sunday" {
+			return answer
+		}
+		_, _ = fmt.Fprintln(deps.Stderr, "Please enter 'monday' or 'sunday'")
+	}
+}
+
+// askTimezone prompts until a valid IANA timezone name (or "Local") is given
+func askTimezone(scanner *bufio.Scanner, def string) string {
+	for {
+		answer := wizardPrompt(scanner, fmt.Sprintf("Timezone [%s]", def), def)
+		if _, err := timeutil.LoadTimezone(answer); err == nil {
+			return answer
+		}
+		if suggestion := timeutil.SuggestTimezone(answer); suggestion != "" {
+			_, _ = fmt.Fprintf(deps.Stderr, "Unknown timezone '%s' (did you mean %s?)\n", answer, suggestion)
+			continue
+		}
+		_, _ = fmt.Fprintf(deps.Stderr, "Unknown timezone '%s' (examples: Local, America/New_York, Europe/London)\n", answer)
+	}
+}
+
+// askStoragePath prompts until an absolute path is given. A leading "~/" is
+// expanded to the home directory. Returns "" only if there is no default.
+func askStoragePath(scanner *bufio.Scanner, def string) string {
+	for {
+		answer := wizardPrompt(scanner, fmt.Sprintf("Storage file [%s]", def), def)
+		if strings.HasPrefix(answer, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				answer = filepath.Join(home, answer[2:])
+			}
+		}
+		if answer == "" || filepath.IsAbs(answer) {
+			return answer
+		}
+		_, _ = fmt.Fprintln(deps.Stderr, "Please enter an absolute path")
+	}
+}
+
+// wizardPrompt prints a question and returns the trimmed answer, or def if the
+// answer is empty or input has ended
+func wizardPrompt(scanner *bufio.Scanner, question, def string) string {
+	_, _ = fmt.Fprintf(deps.Stdout, "%s: ", question)
+	if !scanner.Scan() {
+		_, _ = fmt.Fprintln(deps.Stdout)
+		return def
+	}
+	answer := strings.TrimSpace(scanner.Text())
+	if answer == "" {
+		return def
+	}
+	return answer
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/osutil"
+)
+
+// setupWizardTest points the config directory at a temp dir, simulates a TTY
+// (or not) and feeds the given stdin. Returns the config path and captured output.
+func setupWizardTest(t *testing.T, stdin string, tty bool) (string, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	configDir := t.TempDir()
+	osutil.SetProvider(&configMockPathProvider{
+		userConfigDirFn: func() (string, error) { return configDir, nil },
+		mkdirAllFn:      os.MkdirAll,
+	})
+	t.Cleanup(osutil.ResetProvider)
+
+	origIsTerminal, origStdoutIsTerminal := stdinIsTerminal, stdoutIsTerminal
+	stdinIsTerminal = func() bool { return tty }
+	stdoutIsTerminal = func() bool { return tty }
+	t.Cleanup(func() { stdinIsTerminal, stdoutIsTerminal = origIsTerminal, origStdoutIsTerminal })
+
+	t.Setenv(noWizardEnv, "")
+	_ = os.Unsetenv(noWizardEnv)
+	t.Setenv("TZ", "UTC")
+
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	d, stdout, stderr := testDeps(storagePath)
+	d.Stdin = strings.NewReader(stdin)
+	SetDeps(d)
+	t.Cleanup(ResetDeps)
+
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		t.Fatalf("Failed to get config path: %v", err)
+	}
+	return configPath, stdout, stderr
+}
+
+func TestRunInit_ScriptedAnswers(t *testing.T) {
+	customStorage := filepath.Join(t.TempDir(), "data", "entries.jsonl")
+	configPath, _, stderr := setupWizardTest(t, "funday\nSunday\nMars/Base\nEurope/London\nrelative/path\n"+customStorage+"\n", true)
+
+	runInit(false)
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load written config: %v", err)
+	}
+	if cfg.WeekStartDay != "sunday" {
+		t.Errorf("WeekStartDay = %q, expected %q", cfg.WeekStartDay, "sunday")
+	}
+	if cfg.Timezone != "Europe/London" {
+		t.Errorf("Timezone = %q, expected %q", cfg.Timezone, "Europe/London")
+	}
+	if cfg.StoragePath != customStorage {
+		t.Errorf("StoragePath = %q, expected %q", cfg.StoragePath, customStorage)
+	}
+
+	// Settings apply to the current run
+	if deps.Config.WeekStartDay != "sunday" {
+		t.Errorf("Expected deps config to be updated, got %q", deps.Config.WeekStartDay)
+	}
+	if path, _ := deps.StoragePath(); path != customStorage {
+		t.Errorf("Expected storage path %q, got %q", customStorage, path)
+	}
+	// The storage directory is created with the first entry, not by the wizard
+	if _, err := os.Stat(filepath.Dir(customStorage)); !os.IsNotExist(err) {
+		t.Errorf("Expected storage directory not to be created yet: %v", err)
+	}
+
+	for _, expected := range []string{"Please enter 'monday' or 'sunday'", "Unknown timezone 'Mars/Base'", "Please enter an absolute path"} {
+		if !strings.Contains(stderr.String(), expected) {
+			t.Errorf("Expected re-prompt message %q, got: %s", expected, stderr.String())
+		}
+	}
+}
+
+func TestRunInit_YesAcceptsDefaults(t *testing.T) {
+	configPath, stdout, _ := setupWizardTest(t, "", false)
+
+	runInit(true)
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load written config: %v", err)
+	}
+	if cfg.WeekStartDay != "monday" || cfg.Timezone != "UTC" || cfg.StoragePath != "" {
+		t.Errorf("Expected defaults with detected timezone, got %+v", cfg)
+	}
+	if strings.Contains(stdout.String(), "Week starts on") {
+		t.Errorf("Expected no prompts with --yes, got: %s", stdout.String())
+	}
+}
+
+func TestRunInit_ExistingConfig(t *testing.T) {
+	t.Run("yes refuses to overwrite", func(t *testing.T) {
+		configPath, _, _ := setupWizardTest(t, "", false)
+		if err := writeConfigFile(configPath, "week_start_day = \"sunday\"\n"); err != nil {
+			t.Fatal(err)
+		}
+		exitCode := 0
+		deps.Exit = func(code int) { exitCode = code }
+
+		runInit(true)
+
+		if exitCode != 1 {
+			t.Errorf("Expected exit code 1, got %d", exitCode)
+		}
+		content, _ := os.ReadFile(configPath)
+		if !strings.Contains(string(content), "sunday") {
+			t.Errorf("Expected existing config to be untouched, got: %s", content)
+		}
+	})
+
+	t.Run("declined overwrite", func(t *testing.T) {
+		configPath, stdout, _ := setupWizardTest(t, "n\n", true)
+		if err := writeConfigFile(configPath, "week_start_day = \"sunday\"\n"); err != nil {
+			t.Fatal(err)
+		}
+
+		runInit(false)
+
+		if !strings.Contains(stdout.String(), "Cancelled") {
+			t.Errorf("Expected cancellation message, got: %s", stdout.String())
+		}
+	})
+
+	t.Run("confirmed overwrite", func(t *testing.T) {
+		configPath, _, _ := setupWizardTest(t, "y\nmonday\n\n\n", true)
+		if err := writeConfigFile(configPath, "week_start_day = \"sunday\"\n"); err != nil {
+			t.Fatal(err)
+		}
+
+		runInit(false)
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			t.Fatalf("Failed to load written config: %v", err)
+		}
+		if cfg.WeekStartDay != "monday" {
+			t.Errorf("Expected config to be overwritten, got %q", cfg.WeekStartDay)
+		}
+	})
+}
+
+func TestMaybeRunFirstRunWizard(t *testing.T) {
+	tests := []struct {
+		name       string
+		tty        bool
+		redirected bool
+		noWizard   bool
+		haveConfig bool
+		expectRun  bool
+	}{
+		{"interactive without config", true, false, false, false, true},
+		{"non-interactive", false, false, false, false, false},
+		{"output redirected", true, true, false, false, false},
+		{"disabled by env", true, false, true, false, false},
+		{"config exists", true, false, false, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath, stdout, _ := setupWizardTest(t, "\n\n\n", tt.tty)
+			if tt.redirected {
+				stdoutIsTerminal = func() bool { return false }
+			}
+			if tt.noWizard {
+				t.Setenv(noWizardEnv, "1")
+			}
+			if tt.haveConfig {
+				if err := writeConfigFile(configPath, ""); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			maybeRunFirstRunWizard()
+
+			ran := strings.Contains(stdout.String(), "Welcome to did!")
+			if ran != tt.expectRun {
+				t.Errorf("Expected wizard run=%v, got output: %s", tt.expectRun, stdout.String())
+			}
+			if _, err := os.Stat(configPath); tt.expectRun && err != nil {
+				t.Errorf("Expected config file to be written: %v", err)
+			}
+		})
+	}
+}
+
+func TestRootListing_RunsFirstRunWizard(t *testing.T) {
+	_, stdoutBuf, _ := setupWizardTest(t, "\n\n\n", true)
+	resetFilterFlags(rootCmd)
+	resetTimePeriodFlags(rootCmd)
+
+	rootCmd.Run(rootCmd, []string{})
+
+	stdout := stdoutBuf.String()
+	welcome := strings.Index(stdout, "Welcome to did!")
+	if welcome == -1 {
+		t.Fatalf("Expected wizard before listing, got: %s", stdout)
+	}
+	if !strings.Contains(stdout[welcome:], "No entries found") {
+		t.Errorf("Expected listing to proceed after the wizard, got: %s", stdout)
+	}
+}
+
+func TestRootListing_NoWizardWithFlags(t *testing.T) {
+	for _, flag := range []string{"this-week", "count-only", "project"} {
+		t.Run(flag, func(t *testing.T) {
+			configPath, stdout, _ := setupWizardTest(t, "\n\n\n", true)
+			resetFilterFlags(rootCmd)
+			resetTimePeriodFlags(rootCmd)
+			defer resetFilterFlags(rootCmd)
+			defer resetTimePeriodFlags(rootCmd)
+			if flag == "project" {
+				_ = rootCmd.PersistentFlags().Set("project", "acme")
+			} else {
+				_ = rootCmd.Flags().Set(flag, "true")
+				defer func() { _ = rootCmd.Flags().Set(flag, "false") }()
+			}
+
+			rootCmd.Run(rootCmd, []string{})
+
+			if strings.Contains(stdout.String(), "Welcome to did!") {
+				t.Errorf("Expected no wizard with --%s, got: %s", flag, stdout.String())
+			}
+			if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+				t.Errorf("Expected no config file written with --%s: %v", flag, err)
+			}
+		})
+	}
+}
@@ -2,11 +2,39 @@ package cmd
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
+	"io/fs"
+	"syscall"
 
 	"github.com/xolan/did/internal/timer"
 )
 
+// printStorageWriteHint prints the hint for a failed write to the storage at
+// path. A read-only file system or missing permissions won't go away on a
+// retry, so the hint then points to a writable storage location instead.
+func printStorageWriteHint(path string, err error) {
+	if errors.Is(err, fs.ErrPermission) || errors.Is(err, syscall.EROFS) {
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: The storage location is read-only or not writable: %s\n", path)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Set DID_STORE (or storage_path in the config file) to a writable location")
+		return
+	}
+	_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that the storage location exists and is writable: %s\n", path)
+}
+
+// prepareStorageDir creates the storage directory before entries are added,
+// reporting a failure. Returns false if the command should stop.
+func prepareStorageDir(storagePath string) bool {
+	if err := deps.prepareStorage(storagePath); err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to create storage directory")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		printStorageWriteHint(storagePath, err)
+		deps.Exit(1)
+		return false
+	}
+	return true
+}
+
 func writeCSVHeader(writer *csv.Writer, headers []string) error {
 	if err := writer.Write(headers); err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to write CSV headers")
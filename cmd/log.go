@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/entry"
+)
+
+// logCmd represents the log command
+var logCmd = &cobra.Command{
+	Use:   "log <description> [for <duration>]",
+	Short: "Log an entry, using the default duration when none is given",
+	Long: `Log an entry like 'did <description> for <duration>'. Without a duration
+the configured default_duration_minutes is used, and the confirmation says so;
+an explicit duration always wins. With default_duration_minutes = 0 (the
+default) the duration is required.
+
+'did <description>' without a duration is rejected as missing one, since
+'did' alone lists entries (with @project and #tag filters or a period alias
+such as 'did y'), so use 'did log' to rely on the default duration.
+
+With --at the entry is dated at the given time (RFC3339 or 'YYYY-MM-DD HH:MM'
+in the configured timezone) instead of now; a time in the future is rejected
+unless --allow-future is given.
+
+With --stdin-lines, every non-empty line of stdin is logged as an entry, e.g.
+a scratch file of 'did X for Y' lines (a leading 'did' is ignored). Each line
+is parsed and validated like the arguments of a single entry; the result of
+each line is reported with its line number, followed by the number of
+entries logged. Invalid lines are skipped, or with --strict make the whole
+batch fail without logging anything. The entries are written at once, so a
+failed write logs none of them.
+
+Examples:
+  did log review PR 42 @acme             Logged: review PR 42 @acme (30m, default duration)
+  did log standup for 15m                Logged: standup (15m)
+  did log --stdin-lines < scratch.txt    Log every line of scratch.txt`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if stdinLines, _ := cmd.Flags().GetBool("stdin-lines"); stdinLines {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if stdinLines, _ := cmd.Flags().GetBool("stdin-lines"); stdinLines {
+			logStdinLines(cmd)
+			return
+		}
+		createEntry(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logCmd)
+
+	logCmd.Flags().Bool("allow-long", false, "Allow logging entries longer than max_entry_duration")
+	logCmd.Flags().Bool("allow-future", false, "Allow logging an entry dated in the future (clock skew)")
+	logCmd.Flags().String("at", "", "Date the entry at this time instead of now (RFC3339 or 'YYYY-MM-DD HH:MM')")
+	logCmd.Flags().Bool("no-workspace", false, "Don't infer the project and tags from the working directory")
+	logCmd.Flags().Bool("no-tags", false, "Keep #words in the description instead of parsing them as tags")
+	logCmd.Flags().Bool("stdin-lines", false, "Log an entry for every line of stdin")
+	logCmd.Flags().Bool("strict", false, "With --stdin-lines, log nothing when any line is invalid")
+}
+
+// logStdinLines logs an entry for every non-empty line of stdin, parsed like
+// the arguments of createEntry, and reports the result of each line
+func logStdinLines(cmd *cobra.Command) {
+	strict, _ := cmd.Flags().GetBool("strict")
+
+	var entries []entry.Entry
+	lines, skipped := 0, 0
+	scanner := bufio.NewScanner(deps.Stdin)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "did ") {
+			line = strings.TrimSpace(strings.TrimPrefix(line, "did "))
+		}
+		if line == "" {
+			continue
+		}
+		lines++
+
+		e, _, err := parseNewEntry(cmd, line)
+		if err != nil {
+			skipped++
+			_, _ = fmt.Fprintf(deps.Stderr, "Line %d: ✗ %s  (%v)\n", lineNumber, line, err)
+			continue
+		}
+		if e.Project == "" {
+			applyWorkspace(cmd, &e)
+		}
+		deps.Config.ApplyEntryDefaults(&e)
+		entries = append(entries, e)
+		_, _ = fmt.Fprintf(deps.Stdout, "Line %d: ✓ %s (%s)\n", lineNumber,
+			formatEntryForLog(e.Description, displayProject(e), e.Tags), formatDuration(e.DurationMinutes))
+	}
+	if err := scanner.Err(); err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read from stdin, nothing was logged")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		deps.Exit(1)
+		return
+	}
+
+	switch {
+	case lines == 0:
+		_, _ = fmt.Fprintln(deps.Stdout, "Nothing to log: no non-empty lines found")
+		return
+	case strict && skipped > 0:
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: %s invalid, nothing was logged\n", formatCount(skipped, "line is", "lines are"))
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Fix the lines above, or leave out --strict to skip them")
+		deps.Exit(1)
+		return
+	case len(entries) == 0:
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: None of the lines could be parsed")
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Use one entry per line, e.g. 'feature X %s 2h'\n", deps.Config.EffectiveDurationKeyword())
+		deps.Exit(1)
+		return
+	}
+
+	store, err := deps.Store()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return
+	}
+
+	if !prepareStorageDir(store.Path()) {
+		return
+	}
+
+	// All entries are written at once, so a failed write logs nothing
+	logged, err := store.AppendAll(entries)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to save entries to storage, nothing was logged")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		printStorageWriteHint(store.Path(), err)
+		deps.Exit(1)
+		return
+	}
+
+	summary := "Logged " + formatCount(logged, "entry", "entries")
+	if skipped > 0 {
+		summary += fmt.Sprintf(", skipped %s", formatCount(skipped, "invalid line", "invalid lines"))
+	}
+	_, _ = fmt.Fprintln(deps.Stdout, summary)
+}
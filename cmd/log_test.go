@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xolan/did/internal/storage"
+)
+
+// resetLogFlags resets the flags of the log command
+func resetLogFlags() {
+	_ = logCmd.Flags().Set("stdin-lines", "false")
+	_ = logCmd.Flags().Set("strict", "false")
+}
+
+const stdinLinesInput = "did fix login @acme #bugfix for 1h\n\n  standup for 15m  \nlunch break\nreview for 0m\n"
+
+func TestLog_StdinLines(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	d, stdout, stderr := testDeps(storagePath)
+	d.Stdin = strings.NewReader(stdinLinesInput)
+	SetDeps(d)
+	defer ResetDeps()
+	resetLogFlags()
+	defer resetLogFlags()
+	_ = logCmd.Flags().Set("stdin-lines", "true")
+
+	logCmd.Run(logCmd, nil)
+
+	expectedStdout := "Line 1: ✓ fix login [@acme #bugfix] (1h)\n" +
+		"Line 3: ✓ standup (15m)\n" +
+		"Logged 2 entries, skipped 2 invalid lines\n"
+	if stdout.String() != expectedStdout {
+		t.Errorf("Expected stdout %q, got %q", expectedStdout, stdout.String())
+	}
+	for _, expected := range []string{
+		"Line 4: ✗ lunch break  (Invalid format. Missing 'for <duration>')\n",
+		"Line 5: ✗ review for 0m  (Duration must be greater than 0)\n",
+	} {
+		if !strings.Contains(stderr.String(), expected) {
+			t.Errorf("Expected stderr to contain %q, got %q", expected, stderr.String())
+		}
+	}
+
+	entries, err := storage.ReadEntries(storagePath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Project != "acme" || entries[0].DurationMinutes != 60 || entries[1].Description != "standup" {
+		t.Errorf("Expected the two valid lines logged, got %+v", entries)
+	}
+}
+
+func TestLog_StdinLinesStrict(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	exitCode := 0
+	d, stdout, stderr := testDeps(storagePath)
+	d.Stdin = strings.NewReader(stdinLinesInput)
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	resetLogFlags()
+	defer resetLogFlags()
+	_ = logCmd.Flags().Set("stdin-lines", "true")
+	_ = logCmd.Flags().Set("strict", "true")
+
+	logCmd.Run(logCmd, nil)
+
+	if exitCode != 1 || !strings.Contains(stderr.String(), "Error: 2 lines are invalid, nothing was logged") {
+		t.Errorf("Expected exit 1 with nothing logged, got exit %d: %s", exitCode, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "Logged") {
+		t.Errorf("Expected no summary, got %q", stdout.String())
+	}
+	if entries, _ := storage.ReadEntries(storagePath); len(entries) != 0 {
+		t.Errorf("Expected no entries logged, got %d", len(entries))
+	}
+}
+
+func TestLog_StdinLinesNoArgs(t *testing.T) {
+	resetLogFlags()
+	defer resetLogFlags()
+	if err := logCmd.Args(logCmd, nil); err == nil {
+		t.Error("Expected a description to be required without --stdin-lines")
+	}
+	_ = logCmd.Flags().Set("stdin-lines", "true")
+	if err := logCmd.Args(logCmd, nil); err != nil {
+		t.Errorf("Expected no arguments to be accepted with --stdin-lines, got %v", err)
+	}
+	if err := logCmd.Args(logCmd, []string{"standup"}); err == nil {
+		t.Error("Expected arguments to be rejected with --stdin-lines")
+	}
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// mergeCmd represents the merge command
+var mergeCmd = &cobra.Command{
+	Use:   "merge <index1> <index2> [index...]",
+	Short: "Combine several entries into one",
+	Long: `Combine two or more entries into a single entry.
+
+The merged entry keeps the earliest timestamp of the merged entries and its
+duration is the sum of their durations. The original entries are replaced by
+the merged entry.
+
+All merged entries must have the same description, project and tags. Use
+--force to merge entries that differ; the merged entry then takes its
+description, project and tags from the earliest entry.
+
+The indices refer to the entry numbers shown in list output (starting from 1).
+
+Examples:
+  did merge 3 4                   Merge entries 3 and 4
+  did merge 2 5 7                 Merge three entries
+  did merge 3 4 --force           Merge entries with different descriptions`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		mergeEntries(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+
+	mergeCmd.Flags().Bool("force", false, "Merge entries even if their description, project or tags differ")
+}
+
+// mergeEntries combines the entries at the given 1-based indices into one entry
+func mergeEntries(cmd *cobra.Command, args []string) {
+	force, _ := cmd.Flags().GetBool("force")
+
+	// Parse and validate the index arguments (1-based from user)
+	var userIndices []int
+	for _, arg := range args {
+		userIndex, err := strconv.Atoi(arg)
+		if err != nil {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid index '%s'. Index must be a number\n", arg)
+			_, _ = fmt.Fprintln(deps.Stderr, "Hint: List entries with 'did' to see available indices")
+			deps.Exit(1)
+			return
+		}
+		for _, seen := range userIndices {
+			if seen == userIndex {
+				_, _ = fmt.Fprintf(deps.Stderr, "Error: Index %d is given more than once\n", userIndex)
+				deps.Exit(1)
+				return
+			}
+		}
+		userIndices = append(userIndices, userIndex)
+	}
+
+	// Get storage path
+	storagePath, err := deps.StoragePath()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return
+	}
+
+	// Read all entries
+	result, err := storage.ReadEntriesWithWarnings(storagePath)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", storagePath)
+		deps.Exit(1)
+		return
+	}
+
+	// Filter to active entries only and create index mapping
+	// Users should only be able to merge active (non-deleted) entries
+	var activeEntries []entry.Entry
+	var storageIndices []int // Maps active entry index to storage index
+	for i, e := range result.Entries {
+		if e.DeletedAt == nil {
+			activeEntries = append(activeEntries, e)
+			storageIndices = append(storageIndices, i)
+		}
+	}
+
+	if len(activeEntries) == 0 {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: No entries found to merge")
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Create an entry first with 'did <description> for <duration>'")
+		deps.Exit(1)
+		return
+	}
+
+	// Validate every index before touching storage
+	var toMerge []entry.Entry
+	var toReplace []int
+	for _, userIndex := range userIndices {
+		activeIndex := userIndex - 1
+		if activeIndex < 0 || activeIndex >= len(activeEntries) {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Index %d is out of range\n", userIndex)
+			_, _ = fmt.Fprintf(deps.Stderr, "Valid range: 1-%d (%d %s available)\n", len(activeEntries), len(activeEntries), pluralize("entry", "entries", len(activeEntries)))
+			_, _ = fmt.Fprintln(deps.Stderr, "Hint: List entries with 'did' to see all indices")
+			deps.Exit(1)
+			return
+		}
+		toMerge = append(toMerge, activeEntries[activeIndex])
+		toReplace = append(toReplace, storageIndices[activeIndex])
+	}
+
+	// Earliest entry first; it provides the timestamp (and metadata with --force)
+	sort.SliceStable(toMerge, func(i, j int) bool {
+		return toMerge[i].Timestamp.Before(toMerge[j].Timestamp)
+	})
+	earliest := toMerge[0]
+
+	if !force {
+		for _, e := range toMerge[1:] {
+			if field := mergeMismatch(earliest, e); field != "" {
+				_, _ = fmt.Fprintf(deps.Stderr, "Error: Entries have different %s and cannot be merged\n", field)
+				_, _ = fmt.Fprintf(deps.Stderr, "  %s\n", formatEntryForLog(earliest.Description, displayProject(earliest), earliest.Tags))
+				_, _ = fmt.Fprintf(deps.Stderr, "  %s\n", formatEntryForLog(e.Description, displayProject(e), e.Tags))
+				_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use --force to merge anyway, keeping the earliest entry's description, project and tags")
+				deps.Exit(1)
+				return
+			}
+		}
+	}
+
+	// Sum the durations, respecting the per-entry maximum
+	totalMinutes := 0
+	for _, e := range toMerge {
+		totalMinutes += e.DurationMinutes
+	}
+	if totalMinutes > entry.MaxDurationMinutes {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Merged duration %s exceeds the maximum of 24h per entry\n", formatDuration(totalMinutes))
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Merge fewer entries")
+		deps.Exit(1)
+		return
+	}
+
+	merged := earliest
+	merged.DurationMinutes = totalMinutes
+
+	// Reconstruct RawInput to reflect the merged duration
+	merged.RawInput = formatRawInput(merged)
+
+	// Replace the merged entries with the single merged entry
+	if err := storage.ReplaceEntries(storagePath, toReplace, []entry.Entry{merged}); err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to save merged entry to storage")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		printStorageWriteHint(storagePath, err)
+		deps.Exit(1)
+		return
+	}
+
+	// Display the resulting merged entry
+	_, _ = fmt.Fprintf(deps.Stdout, "Merged %d entries into: %s (%s)\n",
+		len(toMerge),
+		formatEntryForLog(merged.Description, displayProject(merged), merged.Tags),
+		formatDuration(merged.DurationMinutes))
+	_, _ = fmt.Fprintf(deps.Stdout, "  Timestamp: %s\n", merged.Timestamp.Format("2006-01-02 15:04"))
+}
+
+// mergeMismatch returns the name of the first field that differs between two
+// entries ("description", "project" or "tags"), or "" if they can be merged.
+// Tags are compared as sets, ignoring case and order.
+func mergeMismatch(a, b entry.Entry) string {
+	if a.Description != b.Description {
+		return "description"
+	}
+	if !strings.EqualFold(a.Project, b.Project) {
+		return "project"
+	}
+	if len(a.Tags) != len(b.Tags) {
+		return "tags"
+	}
+	for _, tag := range a.Tags {
+		if !containsTag(b.Tags, tag) {
+			return "tags"
+		}
+	}
+	for _, tag := range b.Tags {
+		if !containsTag(a.Tags, tag) {
+			return "tags"
+		}
+	}
+	return ""
+}
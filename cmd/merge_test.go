@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// setMergeForce sets the merge --force flag and resets it when the test finishes
+func setMergeForce(t *testing.T, force bool) {
+	t.Helper()
+	if force {
+		_ = mergeCmd.Flags().Set("force", "true")
+	}
+	t.Cleanup(func() {
+		_ = mergeCmd.Flags().Set("force", "false")
+	})
+}
+
+// createMergeTestEntries writes the given entries and returns the storage path
+func createMergeTestEntries(t *testing.T, entries []entry.Entry) string {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	for _, e := range entries {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	return storagePath
+}
+
+func TestMergeEntries_Success(t *testing.T) {
+	base := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	storagePath := createMergeTestEntries(t, []entry.Entry{
+		{Timestamp: base.Add(2 * time.Hour), Description: "coding", DurationMinutes: 30, RawInput: "coding @acme #dev for 30m", Project: "acme", Tags: []string{"dev"}},
+		{Timestamp: base, Description: "coding", DurationMinutes: 60, RawInput: "coding @acme #dev for 1h", Project: "acme", Tags: []string{"dev"}},
+		{Timestamp: base.Add(time.Hour), Description: "meeting", DurationMinutes: 15, RawInput: "meeting for 15m"},
+		{Timestamp: base.Add(3 * time.Hour), Description: "coding", DurationMinutes: 45, RawInput: "coding @acme #DEV for 45m", Project: "acme", Tags: []string{"DEV"}},
+	})
+
+	d, stdout, stderr := testDeps(storagePath)
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	setMergeForce(t, false)
+
+	mergeEntries(mergeCmd, []string{"1", "2", "4"})
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Merged 3 entries into: coding [@acme #dev] (2h 15m)") {
+		t.Errorf("Expected merged entry summary, got: %s", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "2024-01-15 09:00") {
+		t.Errorf("Expected earliest timestamp in output, got: %s", stdout.String())
+	}
+
+	entries, err := storage.ReadEntries(storagePath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries after merge, got %d", len(entries))
+	}
+	merged := entries[0]
+	if merged.DurationMinutes != 135 {
+		t.Errorf("Expected merged duration 135, got %d", merged.DurationMinutes)
+	}
+	if !merged.Timestamp.Equal(base) {
+		t.Errorf("Expected earliest timestamp %v, got %v", base, merged.Timestamp)
+	}
+	if merged.RawInput != "coding @acme #dev for 2h 15m" {
+		t.Errorf("Unexpected raw input: %q", merged.RawInput)
+	}
+	if entries[1].Description != "meeting" {
+		t.Errorf("Expected unrelated entry to be kept, got %q", entries[1].Description)
+	}
+}
+
+func TestMergeEntries_MismatchRequiresForce(t *testing.T) {
+	base := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	entries := []entry.Entry{
+		{Timestamp: base, Description: "coding", DurationMinutes: 60, RawInput: "coding @acme for 1h", Project: "acme"},
+		{Timestamp: base.Add(time.Hour), Description: "coding", DurationMinutes: 30, RawInput: "coding @other for 30m", Project: "other"},
+	}
+
+	t.Run("without force", func(t *testing.T) {
+		storagePath := createMergeTestEntries(t, entries)
+		d, _, stderr := testDeps(storagePath)
+		exitCode := 0
+		d.Exit = func(code int) { exitCode = code }
+		SetDeps(d)
+		defer ResetDeps()
+		setMergeForce(t, false)
+
+		mergeEntries(mergeCmd, []string{"1", "2"})
+
+		if exitCode != 1 {
+			t.Errorf("Expected exit code 1, got %d", exitCode)
+		}
+		if !strings.Contains(stderr.String(), "different project") {
+			t.Errorf("Expected project mismatch error, got: %s", stderr.String())
+		}
+		if !strings.Contains(stderr.String(), "--force") {
+			t.Errorf("Expected --force hint, got: %s", stderr.String())
+		}
+		stored, _ := storage.ReadEntries(storagePath)
+		if len(stored) != 2 {
+			t.Errorf("Expected storage to be unchanged, got %d entries", len(stored))
+		}
+	})
+
+	t.Run("with force", func(t *testing.T) {
+		storagePath := createMergeTestEntries(t, entries)
+		d, _, stderr := testDeps(storagePath)
+		exitCode := 0
+		d.Exit = func(code int) { exitCode = code }
+		SetDeps(d)
+		defer ResetDeps()
+		setMergeForce(t, true)
+
+		mergeEntries(mergeCmd, []string{"2", "1"})
+
+		if exitCode != 0 {
+			t.Fatalf("Expected exit code 0, got %d (stderr: %s)", exitCode, stderr.String())
+		}
+		stored, _ := storage.ReadEntries(storagePath)
+		if len(stored) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(stored))
+		}
+		if stored[0].Project != "acme" || stored[0].DurationMinutes != 90 {
+			t.Errorf("Expected earliest entry's project and summed duration, got %+v", stored[0])
+		}
+	})
+}
+
+func TestMergeEntries_Errors(t *testing.T) {
+	base := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	deletedAt := base.Add(time.Hour)
+	entries := []entry.Entry{
+		{Timestamp: base, Description: "coding", DurationMinutes: 720, RawInput: "coding for 12h"},
+		{Timestamp: base.Add(time.Hour), Description: "coding", DurationMinutes: 780, RawInput: "coding for 13h"},
+		{Timestamp: base.Add(2 * time.Hour), Description: "coding", DurationMinutes: 30, RawInput: "coding for 30m", DeletedAt: &deletedAt},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"non-numeric index", []string{"1", "abc"}, "Invalid index 'abc'"},
+		{"duplicate index", []string{"1", "1"}, "Index 1 is given more than once"},
+		{"out of range", []string{"1", "5"}, "Index 5 is out of range"},
+		{"deleted entries are not indexed", []string{"1", "3"}, "Index 3 is out of range"},
+		{"zero index", []string{"0", "1"}, "Index 0 is out of range"},
+		{"exceeds max duration", []string{"1", "2"}, "exceeds the maximum of 24h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := createMergeTestEntries(t, entries)
+			d, stdout, stderr := testDeps(storagePath)
+			exitCode := 0
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			setMergeForce(t, false)
+
+			mergeEntries(mergeCmd, tt.args)
+
+			if exitCode != 1 {
+				t.Errorf("Expected exit code 1, got %d", exitCode)
+			}
+			if !strings.Contains(stderr.String(), tt.expected) {
+				t.Errorf("Expected error containing %q, got: %s", tt.expected, stderr.String())
+			}
+			if stdout.Len() > 0 {
+				t.Errorf("Expected no stdout, got: %s", stdout.String())
+			}
+			stored, _ := storage.ReadEntries(storagePath)
+			if len(stored) != 3 {
+				t.Errorf("Expected storage to be unchanged, got %d entries", len(stored))
+			}
+		})
+	}
+}
+
+func TestMergeMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     entry.Entry
+		expected string
+	}{
+		{"identical", entry.Entry{Description: "x", Project: "p", Tags: []string{"a", "b"}}, entry.Entry{Description: "x", Project: "p", Tags: []string{"b", "a"}}, ""},
+		{"description", entry.Entry{Description: "x"}, entry.Entry{Description: "y"}, "description"},
+		{"project", entry.Entry{Description: "x", Project: "p"}, entry.Entry{Description: "x"}, "project"},
+		{"tags", entry.Entry{Description: "x", Tags: []string{"a"}}, entry.Entry{Description: "x", Tags: []string{"b"}}, "tags"},
+		{"duplicate tags", entry.Entry{Description: "x", Tags: []string{"a", "a"}}, entry.Entry{Description: "x", Tags: []string{"a", "b"}}, "tags"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeMismatch(tt.a, tt.b); got != tt.expected {
+				t.Errorf("mergeMismatch() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
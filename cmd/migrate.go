@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/storage"
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Update the storage or config file to the current format",
+	Long: `Update the storage file to the current storage format, or the config file to
+the current config version. Each migration is chosen with a flag.
+
+--normalize-timestamps rewrites timestamps stored with a zone offset by older
+versions of did in UTC, as new entries are written. Only the representation
+changes: every entry keeps its instant, and listings still show times in the
+configured timezone. Other lines, including corrupted ones, are kept
+unchanged. The file is replaced atomically.
+
+Files edited in an editor such as Notepad may have CRLF line endings and a
+byte order mark. did reads them anyway; --normalize-timestamps also writes
+every line back with a plain LF ending and without the byte order mark.
+
+--data-dir moves the storage file, with its backups and metadata, from the
+config directory where earlier versions of did kept it (~/.config/did) to the
+data directory: $XDG_DATA_HOME/did, or ~/.local/share/did when
+$XDG_DATA_HOME is not set. Until it is moved, did keeps using the file in the
+config directory. On macOS and Windows both are the same directory, so there
+is nothing to move.
+
+--config-file sets the version of an older config file to the current one,
+keeping its settings and comments. Other commands only migrate an older
+config file in memory and never write it.
+
+Examples:
+  did migrate --normalize-timestamps             Store all timestamps in UTC
+  did migrate --normalize-timestamps --dry-run   Show how many entries would change
+  did migrate --data-dir                         Move the storage file to the data directory
+  did migrate --config-file                      Update the config file version`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		migrateStorage(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	migrateCmd.Flags().Bool("normalize-timestamps", false, "Rewrite timestamps stored with a zone offset in UTC")
+	migrateCmd.Flags().Bool("data-dir", false, "Move the storage file from the config directory to the data directory")
+	migrateCmd.Flags().Bool("config-file", false, "Update an older config file to the current config version")
+	migrateCmd.Flags().Bool("dry-run", false, "Show what would change without writing")
+}
+
+// migrateStorage runs the migrations selected by the flags of cmd
+func migrateStorage(cmd *cobra.Command) {
+	normalize, _ := cmd.Flags().GetBool("normalize-timestamps")
+	dataDir, _ := cmd.Flags().GetBool("data-dir")
+	configFile, _ := cmd.Flags().GetBool("config-file")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if !normalize && !dataDir && !configFile {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: No migration selected")
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Store all timestamps in UTC with: did migrate --normalize-timestamps")
+		deps.Exit(1)
+		return
+	}
+
+	if configFile && !migrateConfig(dryRun) {
+		return
+	}
+	if dataDir && !moveLegacyStorage(dryRun) {
+		return
+	}
+	if !normalize {
+		return
+	}
+
+	normalizeTimestamps(dryRun)
+}
+
+// normalizeTimestamps rewrites the timestamps stored with a zone offset in UTC
+func normalizeTimestamps(dryRun bool) {
+	result, ok := rewriteStoredTimestamps("normalize", func(storagePath string) (storage.NormalizeResult, error) {
+		return storage.NormalizeTimestamps(storagePath, dryRun)
+	})
+	if !ok {
+		return
+	}
+
+	switch {
+	case result.Changed == 0:
+		_, _ = fmt.Fprintf(deps.Stdout, "All %s already store their timestamps in UTC\n", formatCount(result.Entries, "entry", "entries"))
+	case dryRun:
+		_, _ = fmt.Fprintf(deps.Stdout, "Would rewrite the timestamps of %s of %d in UTC\n", formatCount(result.Changed, "entry", "entries"), result.Entries)
+	default:
+		_, _ = fmt.Fprintf(deps.Stdout, "Rewrote the timestamps of %s of %d in UTC\n", formatCount(result.Changed, "entry", "entries"), result.Entries)
+	}
+	printRewriteNotes(result, dryRun)
+}
+
+// rewriteStoredTimestamps runs rewrite on the storage path; verb names the
+// rewrite in the error reported to stderr when it fails, and ok is false then
+func rewriteStoredTimestamps(verb string, rewrite func(storagePath string) (storage.NormalizeResult, error)) (storage.NormalizeResult, bool) {
+	storagePath, err := deps.StoragePath()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return storage.NormalizeResult{}, false
+	}
+
+	result, err := rewrite(storagePath)
+	if err != nil {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Failed to %s timestamps\n", verb)
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that the file is readable and writable: %s\n", storagePath)
+		deps.Exit(1)
+		return storage.NormalizeResult{}, false
+	}
+	return result, true
+}
+
+// printRewriteNotes prints the line endings cleaned and the corrupted lines
+// kept by a timestamp rewrite
+func printRewriteNotes(result storage.NormalizeResult, dryRun bool) {
+	if result.Cleaned > 0 {
+		verb := "Removed"
+		if dryRun {
+			verb = "Would remove"
+		}
+		_, _ = fmt.Fprintf(deps.Stdout, "%s CRLF line endings or a byte order mark from %s\n", verb, formatCount(result.Cleaned, "line", "lines"))
+	}
+	if result.Corrupted > 0 {
+		_, _ = fmt.Fprintf(deps.Stdout, "Kept %s unchanged\n", formatCount(result.Corrupted, "corrupted line", "corrupted lines"))
+	}
+}
+
+// moveLegacyStorage moves the storage file from the config directory to the
+// data directory. Returns false when the move failed; the error is reported
+// to stderr then.
+func moveLegacyStorage(dryRun bool) bool {
+	if deps.Config.StoragePath != "" {
+		_, _ = fmt.Fprintf(deps.Stdout, "Nothing to move: storage_path is configured (%s)\n", deps.Config.StoragePath)
+		return true
+	}
+
+	move, err := storage.MoveLegacyStorage(dryRun)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to move the storage file to the data directory")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that both directories are writable, or move the files manually")
+		deps.Exit(1)
+		return false
+	}
+
+	switch {
+	case len(move.Files) == 0:
+		_, _ = fmt.Fprintln(deps.Stdout, "No storage file in the config directory to move")
+	case dryRun:
+		_, _ = fmt.Fprintf(deps.Stdout, "Would move %s from %s to %s\n", strings.Join(move.Files, ", "), filepath.Dir(move.From), filepath.Dir(move.To))
+	default:
+		_, _ = fmt.Fprintf(deps.Stdout, "Moved %s from %s to %s\n", strings.Join(move.Files, ", "), filepath.Dir(move.From), filepath.Dir(move.To))
+	}
+	return true
+}
+
+// migrateConfig updates an older config file to the current config version.
+// Returns false when the update failed; the error is reported to stderr then.
+func migrateConfig(dryRun bool) bool {
+	configPath, ok := resolveConfigPath()
+	if !ok {
+		return false
+	}
+
+	from, err := config.FileVersion(configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		_, _ = fmt.Fprintf(deps.Stdout, "No config file to update: %s\n", configPath)
+		return true
+	}
+	if err == nil && from < config.CurrentVersion && !dryRun {
+		_, _, err = config.Migrate(configPath)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Failed to update config file to version %d\n", config.CurrentVersion)
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that the config file is valid TOML and writable: %s\n", configPath)
+		deps.Exit(1)
+		return false
+	}
+
+	switch {
+	case from >= config.CurrentVersion:
+		_, _ = fmt.Fprintf(deps.Stdout, "Config file is already at version %d: %s\n", from, configPath)
+	case dryRun:
+		_, _ = fmt.Fprintf(deps.Stdout, "Would update config file from version %d to %d: %s\n", from, config.CurrentVersion, configPath)
+	default:
+		_, _ = fmt.Fprintf(deps.Stdout, "Updated config file from version %d to %d: %s\n", from, config.CurrentVersion, configPath)
+	}
+	return true
+}
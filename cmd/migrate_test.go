@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/storage"
+)
+
+// resetMigrateFlags clears the flags of the migrate command
+func resetMigrateFlags() {
+	_ = migrateCmd.Flags().Set("normalize-timestamps", "false")
+	_ = migrateCmd.Flags().Set("data-dir", "false")
+	_ = migrateCmd.Flags().Set("config-file", "false")
+	_ = migrateCmd.Flags().Set("dry-run", "false")
+}
+
+func TestMigrateStorage_NormalizeTimestamps(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	content := `{"timestamp":"2024-01-15T09:00:00+01:00","description":"standup","duration_minutes":15,"raw_input":""}
+{"timestamp":"2024-01-15T10:00:00Z","description":"review","duration_minutes":30,"raw_input":""}
+`
+	if err := os.WriteFile(storagePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	for _, tt := range []struct {
+		flags    []string
+		expected string
+	}{
+		{[]string{"normalize-timestamps", "dry-run"}, "Would rewrite the timestamps of 1 entry of 2 in UTC\n"},
+		{[]string{"normalize-timestamps"}, "Rewrote the timestamps of 1 entry of 2 in UTC\n"},
+		{[]string{"normalize-timestamps"}, "All 2 entries already store their timestamps in UTC\n"},
+	} {
+		d, stdout, stderr := testDeps(storagePath)
+		SetDeps(d)
+		resetMigrateFlags()
+		for _, flag := range tt.flags {
+			_ = migrateCmd.Flags().Set(flag, "true")
+		}
+
+		migrateStorage(migrateCmd)
+
+		ResetDeps()
+		resetMigrateFlags()
+		if stderr.Len() > 0 || stdout.String() != tt.expected {
+			t.Errorf("Flags %v: expected %q, got stdout %q, stderr %q", tt.flags, tt.expected, stdout.String(), stderr.String())
+		}
+	}
+
+	data, _ := os.ReadFile(storagePath)
+	if !strings.Contains(string(data), `"timestamp":"2024-01-15T08:00:00Z"`) {
+		t.Errorf("Expected the standup stored at 08:00 UTC, got:\n%s", data)
+	}
+}
+
+func TestMigrateStorage_EditorLineEndings(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	content := "\ufeff" + `{"timestamp":"2024-01-15T10:00:00Z","description":"review","duration_minutes":30,"raw_input":""}` + "\r\n"
+	if err := os.WriteFile(storagePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	resetMigrateFlags()
+	defer resetMigrateFlags()
+	_ = migrateCmd.Flags().Set("normalize-timestamps", "true")
+
+	migrateStorage(migrateCmd)
+
+	expected := "All 1 entry already store their timestamps in UTC\nRemoved CRLF line endings or a byte order mark from 1 line\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+	if data, _ := os.ReadFile(storagePath); strings.ContainsAny(string(data), "\r\ufeff") {
+		t.Errorf("Expected plain LF lines, got %q", data)
+	}
+}
+
+func TestMigrateStorage_DataDir(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("the data directory is the config directory on " + runtime.GOOS)
+	}
+	configDir, dataDir := t.TempDir(), t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("XDG_DATA_HOME", dataDir)
+	legacyPath := filepath.Join(configDir, "did", "entries.jsonl")
+	if err := os.MkdirAll(filepath.Dir(legacyPath), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(legacyPath, []byte(`{"timestamp":"2024-01-15T10:00:00Z","description":"review","duration_minutes":30,"raw_input":""}`+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	for _, tt := range []struct {
+		flags    []string
+		expected string
+	}{
+		{[]string{"data-dir", "dry-run"}, "Would move entries.jsonl from " + filepath.Dir(legacyPath) + " to " + filepath.Join(dataDir, "did") + "\n"},
+		{[]string{"data-dir"}, "Moved entries.jsonl from " + filepath.Dir(legacyPath) + " to " + filepath.Join(dataDir, "did") + "\n"},
+		{[]string{"data-dir"}, "No storage file in the config directory to move\n"},
+	} {
+		d, stdout, stderr := testDeps("")
+		d.StoragePath = func() (string, error) { return storage.ResolveStoragePath("") }
+		SetDeps(d)
+		resetMigrateFlags()
+		for _, flag := range tt.flags {
+			_ = migrateCmd.Flags().Set(flag, "true")
+		}
+
+		migrateStorage(migrateCmd)
+
+		ResetDeps()
+		resetMigrateFlags()
+		if stderr.Len() > 0 || stdout.String() != tt.expected {
+			t.Errorf("Flags %v: expected %q, got stdout %q, stderr %q", tt.flags, tt.expected, stdout.String(), stderr.String())
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dataDir, "did", "entries.jsonl")); err != nil {
+		t.Errorf("Expected the storage file in the data directory: %v", err)
+	}
+}
+
+func TestMigrateStorage_NoMigration(t *testing.T) {
+	d, stdout, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	resetMigrateFlags()
+
+	migrateStorage(migrateCmd)
+
+	if exitCode != 1 || stdout.Len() > 0 || !strings.Contains(stderr.String(), "did migrate --normalize-timestamps") {
+		t.Errorf("Expected an error with a hint, got exit %d, stdout %q, stderr %q", exitCode, stdout.String(), stderr.String())
+	}
+}
+
+func TestMigrateStorage_ConfigFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(configPath, []byte(`week_start_day = "sunday"`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config.SetConfigPath(configPath)
+	defer config.SetConfigPath("")
+
+	for _, tt := range []struct {
+		dryRun  bool
+		want    string
+		version int
+	}{
+		{true, "Would update config file from version 0 to 1", 0},
+		{false, "Updated config file from version 0 to 1", 1},
+		{false, "Config file is already at version 1", 1},
+	} {
+		d, stdout, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+		SetDeps(d)
+		resetMigrateFlags()
+		_ = migrateCmd.Flags().Set("config-file", "true")
+		_ = migrateCmd.Flags().Set("dry-run", fmt.Sprint(tt.dryRun))
+
+		migrateStorage(migrateCmd)
+		ResetDeps()
+
+		if !strings.Contains(stdout.String(), tt.want) || stderr.Len() > 0 {
+			t.Errorf("Expected %q, got stdout %q, stderr %q", tt.want, stdout.String(), stderr.String())
+		}
+		if version, err := config.FileVersion(configPath); err != nil || version != tt.version {
+			t.Errorf("Expected file version %d, got %d (%v)", tt.version, version, err)
+		}
+	}
+	resetMigrateFlags()
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/storage"
+)
+
+// storageNormalizeCmd represents the storage normalize command
+var storageNormalizeCmd = &cobra.Command{
+	Use:   "normalize",
+	Short: "Truncate stored timestamps to whole seconds or minutes",
+	Long: `Truncate the timestamps of all entries, including deleted ones, to the
+configured timestamp_precision ("second" unless configured), or to the
+precision given with --precision.
+
+New entries are stored with timestamp_precision already; entries logged
+before it was set keep their timestamps until they are normalized, so a
+storage file kept under version control has no sub-second clutter. An entry
+moves up to a second (or a minute) earlier. Durations, and the times
+entries were logged and deleted, are unchanged. The lines of changed
+entries are written in UTC, other lines are kept, and the file is replaced
+atomically.
+
+Examples:
+  did storage normalize                      Truncate to the configured precision
+  did storage normalize --precision minute   Truncate to whole minutes
+  did storage normalize --dry-run            Show how many entries would change`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		normalizeStorage(cmd)
+	},
+}
+
+func init() {
+	storageCmd.AddCommand(storageNormalizeCmd)
+
+	storageNormalizeCmd.Flags().String("precision", "", "Truncate to this precision instead of timestamp_precision: minute or second")
+	storageNormalizeCmd.Flags().Bool("dry-run", false, "Show how many entries would change without writing")
+}
+
+// normalizeStorage truncates the timestamps of all entries to the precision
+// selected by the flags of cmd or the config
+func normalizeStorage(cmd *cobra.Command) {
+	precision, _ := cmd.Flags().GetString("precision")
+	precision = strings.ToLower(strings.TrimSpace(precision))
+	if precision == "" {
+		precision = deps.Config.TimestampPrecision
+	}
+	if precision == "" {
+		precision = config.DefaultTimestampPrecision
+	}
+	unit, ok := config.TimestampPrecisions[precision]
+	if !ok || (unit == 0 && cmd.Flags().Changed("precision")) {
+		var valid []string
+		for name, unit := range config.TimestampPrecisions {
+			if unit > 0 {
+				valid = append(valid, name)
+			}
+		}
+		sort.Strings(valid)
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --precision '%s'\n", precision)
+		_, _ = fmt.Fprintf(deps.Stderr, "Valid precisions: %s\n", strings.Join(valid, ", "))
+		deps.Exit(1)
+		return
+	}
+	if unit == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, "Nothing to normalize: timestamp_precision is \"exact\" (choose one with --precision)")
+		return
+	}
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	result, ok := rewriteStoredTimestamps("normalize", func(storagePath string) (storage.NormalizeResult, error) {
+		return storage.TruncateTimestamps(storagePath, unit, dryRun)
+	})
+	if !ok {
+		return
+	}
+
+	switch {
+	case result.Changed == 0:
+		_, _ = fmt.Fprintf(deps.Stdout, "All %s already have whole-%s timestamps\n", formatCount(result.Entries, "entry", "entries"), precision)
+	case dryRun:
+		_, _ = fmt.Fprintf(deps.Stdout, "Would truncate the timestamps of %s of %d to the %s\n", formatCount(result.Changed, "entry", "entries"), result.Entries, precision)
+	default:
+		_, _ = fmt.Fprintf(deps.Stdout, "Truncated the timestamps of %s of %d to the %s\n", formatCount(result.Changed, "entry", "entries"), result.Entries, precision)
+	}
+	printRewriteNotes(result, dryRun)
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// resetNormalizeFlags restores the flags of storage normalize after a test
+func resetNormalizeFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		_ = storageNormalizeCmd.Flags().Set("precision", "")
+		_ = storageNormalizeCmd.Flags().Set("dry-run", "false")
+		storageNormalizeCmd.Flags().Lookup("precision").Changed = false
+	})
+}
+
+// createPreciseEntries writes two entries with fractional seconds
+func createPreciseEntries(t *testing.T) string {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	for _, e := range []entry.Entry{
+		{Timestamp: time.Date(2024, 1, 15, 9, 41, 27, 123456789, time.UTC), Description: "first", DurationMinutes: 30},
+		{Timestamp: time.Date(2024, 1, 15, 10, 0, 12, 0, time.UTC), Description: "second", DurationMinutes: 15},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	return storagePath
+}
+
+func TestStorageNormalize(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision string // timestamp_precision in the config
+		flag      string // --precision
+		expected  string
+		times     []time.Time
+	}{
+		{"configured default", "", "", "Truncated the timestamps of 1 entry of 2 to the second", []time.Time{
+			time.Date(2024, 1, 15, 9, 41, 27, 0, time.UTC), time.Date(2024, 1, 15, 10, 0, 12, 0, time.UTC),
+		}},
+		{"configured minute", "minute", "", "Truncated the timestamps of 2 entries of 2 to the minute", []time.Time{
+			time.Date(2024, 1, 15, 9, 41, 0, 0, time.UTC), time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+		}},
+		{"flag overrides config", "exact", "minute", "Truncated the timestamps of 2 entries of 2 to the minute", []time.Time{
+			time.Date(2024, 1, 15, 9, 41, 0, 0, time.UTC), time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := createPreciseEntries(t)
+			cfg := config.DefaultConfig()
+			cfg.TimestampPrecision = tt.precision
+			d, stdout, stderr := testDepsWithConfig(storagePath, cfg)
+			SetDeps(d)
+			defer ResetDeps()
+			resetNormalizeFlags(t)
+			if tt.flag != "" {
+				_ = storageNormalizeCmd.Flags().Set("precision", tt.flag)
+			}
+
+			storageNormalizeCmd.Run(storageNormalizeCmd, nil)
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr output: %s", stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.expected) {
+				t.Errorf("Expected %q, got: %s", tt.expected, stdout.String())
+			}
+			entries, _ := storage.ReadEntries(storagePath)
+			for i, e := range entries {
+				if !e.Timestamp.Equal(tt.times[i]) {
+					t.Errorf("Entry %d: timestamp %v, expected %v", i, e.Timestamp, tt.times[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStorageNormalize_DryRunAndExact(t *testing.T) {
+	storagePath := createPreciseEntries(t)
+	cfg := config.DefaultConfig()
+	cfg.TimestampPrecision = "exact"
+	d, stdout, _ := testDepsWithConfig(storagePath, cfg)
+	SetDeps(d)
+	defer ResetDeps()
+	resetNormalizeFlags(t)
+
+	storageNormalizeCmd.Run(storageNormalizeCmd, nil)
+	if !strings.Contains(stdout.String(), `Nothing to normalize: timestamp_precision is "exact"`) {
+		t.Errorf("Expected nothing to normalize with exact timestamps, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	_ = storageNormalizeCmd.Flags().Set("precision", "minute")
+	_ = storageNormalizeCmd.Flags().Set("dry-run", "true")
+	storageNormalizeCmd.Run(storageNormalizeCmd, nil)
+	if !strings.Contains(stdout.String(), "Would truncate the timestamps of 2 entries of 2 to the minute") {
+		t.Errorf("Expected a dry run summary, got: %s", stdout.String())
+	}
+	if entries, _ := storage.ReadEntries(storagePath); entries[0].Timestamp.Nanosecond() == 0 {
+		t.Error("Expected the dry run to leave the timestamps unchanged")
+	}
+}
+
+func TestStorageNormalize_InvalidPrecision(t *testing.T) {
+	for _, precision := range []string{"hour", "exact"} {
+		exitCode := 0
+		d, stdout, stderr := testDeps(createPreciseEntries(t))
+		d.Exit = func(code int) { exitCode = code }
+		SetDeps(d)
+		resetNormalizeFlags(t)
+		_ = storageNormalizeCmd.Flags().Set("precision", precision)
+
+		storageNormalizeCmd.Run(storageNormalizeCmd, nil)
+		ResetDeps()
+
+		if exitCode != 1 || !strings.Contains(stderr.String(), "Invalid --precision '"+precision+"'") || !strings.Contains(stderr.String(), "minute, second") {
+			t.Errorf("%s: expected exit 1 with an invalid precision error, got exit %d: %s", precision, exitCode, stderr.String())
+		}
+		if stdout.Len() > 0 {
+			t.Errorf("%s: expected no stdout, got %q", precision, stdout.String())
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+)
+
+// defaultPager is the pager used when $PAGER is not set
+const defaultPager = "less -R"
+
+// stdoutIsTerminal reports whether deps.Stdout is an interactive terminal.
+// Tests replace it to simulate a TTY.
+var stdoutIsTerminal = func() bool {
+	f, ok := deps.Stdout.(*os.File)
+	return ok && term.IsTerminal(f.Fd())
+}
+
+// addPagerFlags adds the --pager and --no-pager flags to a listing command
+func addPagerFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("pager", false, "Show the listing in $PAGER (default \"less -R\") when output is a terminal")
+	cmd.Flags().Bool("no-pager", false, "Never show the listing in a pager, even if the config enables it")
+}
+
+// usePager reports whether cmd asks for paged output: --no-pager wins over
+// --pager, which wins over the config's pager setting
+func usePager(cmd *cobra.Command) bool {
+	if noPager, _ := cmd.Flags().GetBool("no-pager"); noPager {
+		return false
+	}
+	if pager, _ := cmd.Flags().GetBool("pager"); pager {
+		return true
+	}
+	return deps.Config.Pager
+}
+
+// pagerCommand returns the pager program and its arguments from $PAGER,
+// or defaultPager when it is not set
+func pagerCommand() []string {
+	if fields := strings.Fields(os.Getenv("PAGER")); len(fields) > 0 {
+		return fields
+	}
+	return strings.Fields(defaultPager)
+}
+
+// startPager redirects deps.Stdout into a pager when cmd asks for one and
+// stdout is a terminal, like git does. The returned function must be called
+// once the output is written: it closes the pager's input, waits for the user
+// to quit it and restores deps.Stdout. When no pager is used, or it cannot
+// be started, output is written directly and the function does nothing.
+func startPager(cmd *cobra.Command) func() {
+	if !usePager(cmd) || !stdoutIsTerminal() {
+		return func() {}
+	}
+
+	args := pagerCommand()
+	pager := exec.Command(args[0], args[1:]...)
+	pager.Stdout = deps.Stdout
+	pager.Stderr = deps.Stderr
+	// Like git: leave short output on screen and keep colors, unless LESS is set
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		pager.Env = append(os.Environ(), "LESS=FRX")
+	}
+
+	input, err := pager.StdinPipe()
+	if err == nil {
+		err = pager.Start()
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Failed to start pager '%s': %v\n", strings.Join(args, " "), err)
+		return func() {}
+	}
+
+	stdout := deps.Stdout
+	deps.Stdout = input
+	return func() {
+		deps.Stdout = stdout
+		// The user quitting the pager before the end is not a failure
+		_ = input.Close()
+		_ = pager.Wait()
+	}
+}
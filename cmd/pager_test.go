@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/xolan/did/internal/config"
+)
+
+// resetPagerFlags clears the pager flags of the root command
+func resetPagerFlags() {
+	_ = rootCmd.Flags().Set("pager", "false")
+	_ = rootCmd.Flags().Set("no-pager", "false")
+}
+
+// fakeTerminal makes stdoutIsTerminal report a terminal for the rest of the test
+func fakeTerminal(t *testing.T) {
+	t.Helper()
+	original := stdoutIsTerminal
+	stdoutIsTerminal = func() bool { return true }
+	t.Cleanup(func() { stdoutIsTerminal = original })
+}
+
+func TestUsePager(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   bool
+		flags    []string
+		expected bool
+	}{
+		{"default", false, nil, false},
+		{"config", true, nil, true},
+		{"--pager", false, []string{"pager"}, true},
+		{"--no-pager overrides config", true, []string{"no-pager"}, false},
+		{"--no-pager overrides --pager", false, []string{"pager", "no-pager"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Pager = tt.config
+			d, _, _ := testDepsWithConfig(t.TempDir()+"/entries.jsonl", cfg)
+			SetDeps(d)
+			defer ResetDeps()
+			resetPagerFlags()
+			defer resetPagerFlags()
+			for _, flag := range tt.flags {
+				_ = rootCmd.Flags().Set(flag, "true")
+			}
+
+			if got := usePager(rootCmd); got != tt.expected {
+				t.Errorf("usePager() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPagerCommand(t *testing.T) {
+	t.Setenv("PAGER", "")
+	if got := strings.Join(pagerCommand(), " "); got != defaultPager {
+		t.Errorf("pagerCommand() = %q without $PAGER, expected %q", got, defaultPager)
+	}
+
+	t.Setenv("PAGER", "more  -d")
+	if got := pagerCommand(); len(got) != 2 || got[0] != "more" || got[1] != "-d" {
+		t.Errorf("pagerCommand() = %q, expected [more -d]", got)
+	}
+}
+
+func TestListEntries_Pager(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat is not available")
+	}
+	storagePath := createSubtotalTestEntries(t)
+
+	// The listing as written without a pager
+	d, plain, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	rootCmd.Run(rootCmd, []string{})
+
+	// cat passes the listing through to the test's stdout unchanged
+	fakeTerminal(t)
+	t.Setenv("PAGER", "cat")
+	d, paged, stderr := testDeps(storagePath)
+	SetDeps(d)
+	resetPagerFlags()
+	defer resetPagerFlags()
+	_ = rootCmd.Flags().Set("pager", "true")
+
+	rootCmd.Run(rootCmd, []string{})
+
+	if stderr.Len() > 0 {
+		t.Errorf("Unexpected stderr: %s", stderr.String())
+	}
+	if paged.String() != plain.String() {
+		t.Errorf("Expected the paged listing to match the plain one:\n%s\ngot:\n%s", plain.String(), paged.String())
+	}
+	if deps.Stdout != d.Stdout {
+		t.Error("Expected deps.Stdout to be restored after paging")
+	}
+}
+
+func TestListEntries_PagerNotTerminal(t *testing.T) {
+	storagePath := createSubtotalTestEntries(t)
+	t.Setenv("PAGER", "did-test-missing-pager")
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	resetPagerFlags()
+	defer resetPagerFlags()
+	_ = rootCmd.Flags().Set("pager", "true")
+
+	rootCmd.Run(rootCmd, []string{})
+
+	// Output that isn't a terminal is never paged, so the missing pager is never run
+	if stderr.Len() > 0 {
+		t.Errorf("Unexpected stderr: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Total: ") {
+		t.Errorf("Expected the listing on stdout, got: %s", stdout.String())
+	}
+}
+
+func TestListEntries_PagerStartError(t *testing.T) {
+	storagePath := createSubtotalTestEntries(t)
+	fakeTerminal(t)
+	t.Setenv("PAGER", "did-test-missing-pager")
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	resetPagerFlags()
+	defer resetPagerFlags()
+	_ = rootCmd.Flags().Set("pager", "true")
+
+	rootCmd.Run(rootCmd, []string{})
+
+	if !strings.Contains(stderr.String(), "Warning: Failed to start pager 'did-test-missing-pager'") {
+		t.Errorf("Expected a pager warning, got: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Total: ") {
+		t.Errorf("Expected the listing to be written directly, got: %s", stdout.String())
+	}
+}
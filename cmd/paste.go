@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/osutil"
+)
+
+// pasteCmd represents the paste command
+var pasteCmd = &cobra.Command{
+	Use:   "paste",
+	Short: "Log entries from the clipboard, one per line",
+	Long: `Log entries from the text on the system clipboard.
+
+Each non-empty line is an entry in the usual '<description> for <duration>'
+format, e.g. copied from a chat message. The entries are listed first and only
+logged after confirmation. Lines that cannot be parsed are flagged in the list
+and skipped.
+
+The clipboard is read with pbpaste on macOS, PowerShell on Windows and
+wl-paste, xclip or xsel on Linux. Without a clipboard (e.g. over SSH), pipe the
+lines in with --stdin instead; piped lines are logged without a prompt.
+
+With --sticky-project, lines without an @project are logged under that
+project, so a day spent on one project does not need @acme on every line. A
+line with an explicit @project keeps it. A line holding only an @project,
+e.g. '@internal', makes that the sticky project for the lines after it; the
+preview shows each change. Lines without a project and without a sticky
+project get the project of the workspace, unless --no-workspace is given.
+
+Lines are parsed like 'did log' input: a line that is only a duration is
+skipped, a line without a duration gets default_duration_minutes if set,
+entries longer than max_entry_duration need --allow-long, and --no-tags keeps
+#words in the descriptions.
+
+Examples:
+  did paste                       Preview the clipboard lines and confirm
+  did paste --yes                 Log the clipboard lines without confirmation
+  did paste --stdin < notes.txt   Log the lines of a file
+  did paste --sticky-project acme Log the lines without @project under @acme`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		pasteEntries(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pasteCmd)
+
+	pasteCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt")
+	pasteCmd.Flags().Bool("stdin", false, "Read the lines from stdin instead of the clipboard")
+	pasteCmd.Flags().String("sticky-project", "", "Log lines without an @project under this project; a line with only '@name' changes it")
+	pasteCmd.Flags().Bool("allow-long", false, "Allow logging entries longer than max_entry_duration")
+	pasteCmd.Flags().Bool("no-workspace", false, "Don't infer the project and tags from the working directory")
+	pasteCmd.Flags().Bool("no-tags", false, "Keep #words in the description instead of parsing them as tags")
+}
+
+// pastedLine is a line of pasted text and the entry parsed from it, or the
+// sticky project it switches to for the following lines
+type pastedLine struct {
+	text   string
+	entry  entry.Entry
+	err    error
+	sticky string
+}
+
+// pasteEntries logs an entry for each line on the clipboard (or stdin)
+func pasteEntries(cmd *cobra.Command) {
+	fromStdin, _ := cmd.Flags().GetBool("stdin")
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+	sticky, _ := cmd.Flags().GetString("sticky-project")
+	sticky = strings.TrimPrefix(strings.TrimSpace(sticky), "@")
+	if sticky != "" && !entry.IsValidName(sticky) {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid project name '%s'\n", sticky)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Project names can contain letters, digits, hyphens, underscores, and single spaces between words")
+		deps.Exit(1)
+		return
+	}
+
+	text, ok := readPasteText(fromStdin)
+	if !ok {
+		return
+	}
+
+	lines := parsePastedLines(cmd, text, time.Now(), sticky)
+	if len(lines) == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, "Nothing to paste: no non-empty lines found")
+		return
+	}
+
+	valid := printPastePreview(lines, sticky)
+	if valid == 0 {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: None of the lines could be parsed")
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Use one entry per line, e.g. 'feature X %s 2h'\n", deps.Config.EffectiveDurationKeyword())
+		deps.Exit(1)
+		return
+	}
+
+	if !skipConfirm && !fromStdin && !promptPasteConfirmation(valid) {
+		_, _ = fmt.Fprintln(deps.Stdout, "Paste cancelled")
+		return
+	}
+
+	store, err := deps.Store()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return
+	}
+
+	if !prepareStorageDir(store.Path()) {
+		return
+	}
+
+	var entries []entry.Entry
+	for _, line := range lines {
+		if line.err == nil && line.sticky == "" {
+			entries = append(entries, line.entry)
+		}
+	}
+	// All entries are written at once, so a failed paste logs nothing
+	logged, err := store.AppendAll(entries)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to save entries to storage, nothing was logged")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		printStorageWriteHint(store.Path(), err)
+		deps.Exit(1)
+		return
+	}
+
+	_, _ = fmt.Fprintf(deps.Stdout, "Logged %s\n", formatCount(logged, "entry", "entries"))
+}
+
+// readPasteText returns the text to paste from stdin or the clipboard.
+// Reports an unavailable clipboard and returns false.
+func readPasteText(fromStdin bool) (string, bool) {
+	if fromStdin {
+		data, err := io.ReadAll(deps.Stdin)
+		if err != nil {
+			_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read from stdin")
+			_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+			deps.Exit(1)
+			return "", false
+		}
+		return string(data), true
+	}
+
+	err := osutil.ErrNoClipboard
+	var text string
+	if deps.ReadClipboard != nil {
+		text, err = deps.ReadClipboard()
+	}
+	if err != nil {
+		if errors.Is(err, osutil.ErrNoClipboard) {
+			_, _ = fmt.Fprintln(deps.Stderr, "Error: No clipboard available")
+			_, _ = fmt.Fprintln(deps.Stderr, "Hint: Install wl-paste, xclip or xsel, or pipe the lines in instead: did paste --stdin < notes.txt")
+		} else {
+			_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read the clipboard")
+			_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+			_, _ = fmt.Fprintln(deps.Stderr, "Hint: Pipe the lines in instead: did paste --stdin < notes.txt")
+		}
+		deps.Exit(1)
+		return "", false
+	}
+	return text, true
+}
+
+// parsePastedLines parses each non-empty line of text into an entry logged at
+// now, like 'did log' parses its input. Entries without an @project get the
+// sticky project, if any, which a line holding only an @project changes for
+// the lines after it; without one, the project of the workspace applies.
+func parsePastedLines(cmd *cobra.Command, text string, now time.Time, sticky string) []pastedLine {
+	var lines []pastedLine
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if desc, project, tags := entry.ParseProjectAndTags(line); desc == "" && project != "" && len(tags) == 0 {
+			sticky = project
+			lines = append(lines, pastedLine{text: line, sticky: project})
+			continue
+		}
+		e, _, err := parseNewEntry(cmd, line)
+		if err == nil {
+			e.Timestamp = now
+			switch {
+			case e.Project != "":
+			case sticky != "":
+				e.Project = sticky
+			default:
+				applyWorkspace(cmd, &e)
+			}
+			deps.Config.ApplyEntryDefaults(&e)
+		}
+		lines = append(lines, pastedLine{text: line, entry: e, err: err})
+	}
+	return lines
+}
+
+// printPastePreview lists the entries to be logged, the changes of the sticky
+// project and flags the lines that will be skipped. Returns the number of
+// valid entries.
+func printPastePreview(lines []pastedLine, sticky string) int {
+	valid := 0
+	if sticky != "" {
+		_, _ = fmt.Fprintf(deps.Stdout, "Entries to log (sticky project @%s):\n", entry.QuoteName(sticky))
+	} else {
+		_, _ = fmt.Fprintln(deps.Stdout, "Entries to log:")
+	}
+	for _, line := range lines {
+		if line.sticky != "" {
+			_, _ = fmt.Fprintf(deps.Stdout, "  → sticky project @%s\n", entry.QuoteName(line.sticky))
+			continue
+		}
+		if line.err != nil {
+			_, _ = fmt.Fprintf(deps.Stdout, "  ✗ %s  (skipped: %v)\n", line.text, line.err)
+			continue
+		}
+		valid++
+		_, _ = fmt.Fprintf(deps.Stdout, "  ✓ %s (%s)\n",
+			formatEntryForLog(line.entry.Description, displayProject(line.entry), line.entry.Tags),
+			formatDuration(line.entry.DurationMinutes))
+	}
+	return valid
+}
+
+// promptPasteConfirmation asks the user to confirm logging the pasted entries
+// Returns true if user confirms with 'y' or 'Y', false otherwise
+func promptPasteConfirmation(count int) bool {
+	_, _ = fmt.Fprintf(deps.Stdout, "Log %s? [y/N]: ", formatCount(count, "entry", "entries"))
+
+	scanner := bufio.NewScanner(deps.Stdin)
+	if !scanner.Scan() {
+		return false
+	}
+
+	response := strings.TrimSpace(scanner.Text())
+	return response == "y" || response == "Y"
+}
@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/osutil"
+	"github.com/xolan/did/internal/storage"
+)
+
+// resetPasteFlags resets the flags of the paste command
+func resetPasteFlags() {
+	_ = pasteCmd.Flags().Set("yes", "false")
+	_ = pasteCmd.Flags().Set("stdin", "false")
+	_ = pasteCmd.Flags().Set("sticky-project", "")
+	for _, name := range []string{"allow-long", "no-workspace", "no-tags"} {
+		_ = pasteCmd.Flags().Set(name, "false")
+	}
+}
+
+// pasteTestDeps returns test dependencies with the given clipboard text and stdin
+func pasteTestDeps(t *testing.T, clipboard, stdin string) (*Deps, string, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	d, stdout, stderr := testDeps(storagePath)
+	d.Stdin = strings.NewReader(stdin)
+	d.ReadClipboard = func() (string, error) { return clipboard, nil }
+	return d, storagePath, stdout, stderr
+}
+
+func TestPaste_ConfirmLogsValidLines(t *testing.T) {
+	clipboard := "fix login @acme #bugfix for 1h\n\n  standup for 15m  \nlunch break\n"
+	d, storagePath, stdout, stderr := pasteTestDeps(t, clipboard, "y\n")
+	SetDeps(d)
+	defer ResetDeps()
+	resetPasteFlags()
+
+	pasteEntries(pasteCmd)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{
+		"✓ fix login [@acme #bugfix] (1h)",
+		"✓ standup (15m)",
+		"✗ lunch break  (skipped: Invalid format. Missing 'for <duration>')",
+		"Log 2 entries? [y/N]: ",
+		"Logged 2 entries",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	entries, err := storage.ReadEntries(storagePath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Project != "acme" || entries[0].RawInput != "fix login @acme #bugfix for 1h" {
+		t.Errorf("Unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Description != "standup" || entries[1].DurationMinutes != 15 {
+		t.Errorf("Unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestPaste_StickyProject(t *testing.T) {
+	clipboard := "api review for 1h\ncall @client for 30m\n@internal\nplanning #meeting for 15m\n"
+	d, storagePath, stdout, stderr := pasteTestDeps(t, clipboard, "y\n")
+	SetDeps(d)
+	defer ResetDeps()
+	resetPasteFlags()
+	defer resetPasteFlags()
+	_ = pasteCmd.Flags().Set("sticky-project", "@acme")
+
+	pasteEntries(pasteCmd)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{
+		"Entries to log (sticky project @acme):",
+		"✓ api review [@acme] (1h)",
+		"✓ call [@client] (30m)",
+		"→ sticky project @internal",
+		"✓ planning [@internal #meeting] (15m)",
+		"Log 3 entries? [y/N]: ",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	entries, err := storage.ReadEntries(storagePath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	var projects []string
+	for _, e := range entries {
+		projects = append(projects, e.Project)
+	}
+	if strings.Join(projects, ",") != "acme,client,internal" {
+		t.Errorf("Expected projects acme, client and internal, got %v", projects)
+	}
+}
+
+func TestPaste_StickyProjectInvalid(t *testing.T) {
+	d, _, _, stderr := pasteTestDeps(t, "api review for 1h\n", "")
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	resetPasteFlags()
+	defer resetPasteFlags()
+	_ = pasteCmd.Flags().Set("sticky-project", "bad  name")
+
+	pasteEntries(pasteCmd)
+
+	if exitCode != 1 || !strings.Contains(stderr.String(), "Invalid project name 'bad  name'") {
+		t.Errorf("Expected an invalid project error, got exit %d: %s", exitCode, stderr.String())
+	}
+}
+
+func TestPaste_Cancelled(t *testing.T) {
+	d, storagePath, stdout, _ := pasteTestDeps(t, "standup for 15m\n", "n\n")
+	SetDeps(d)
+	defer ResetDeps()
+	resetPasteFlags()
+
+	pasteEntries(pasteCmd)
+
+	if !strings.Contains(stdout.String(), "Paste cancelled") {
+		t.Errorf("Expected cancellation message, got: %s", stdout.String())
+	}
+	if entries, _ := storage.ReadEntries(storagePath); len(entries) != 0 {
+		t.Errorf("Expected no entries after cancelling, got %d", len(entries))
+	}
+}
+
+func TestPaste_YesSkipsPrompt(t *testing.T) {
+	d, storagePath, stdout, _ := pasteTestDeps(t, "standup for 15m", "")
+	SetDeps(d)
+	defer ResetDeps()
+	resetPasteFlags()
+	defer resetPasteFlags()
+	_ = pasteCmd.Flags().Set("yes", "true")
+
+	pasteEntries(pasteCmd)
+
+	if strings.Contains(stdout.String(), "[y/N]") {
+		t.Errorf("Expected no prompt with --yes, got: %s", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "Logged 1 entry") {
+		t.Errorf("Expected 'Logged 1 entry', got: %s", stdout.String())
+	}
+	if entries, _ := storage.ReadEntries(storagePath); len(entries) != 1 {
+		t.Errorf("Expected 1 entry, got %d", len(entries))
+	}
+}
+
+func TestPaste_Stdin(t *testing.T) {
+	d, storagePath, stdout, _ := pasteTestDeps(t, "", "review for 30m\ndeploy @acme for 45m\n")
+	d.ReadClipboard = nil
+	SetDeps(d)
+	defer ResetDeps()
+	resetPasteFlags()
+	defer resetPasteFlags()
+	_ = pasteCmd.Flags().Set("stdin", "true")
+
+	pasteEntries(pasteCmd)
+
+	if !strings.Contains(stdout.String(), "Logged 2 entries") {
+		t.Errorf("Expected 'Logged 2 entries', got: %s", stdout.String())
+	}
+	if entries, _ := storage.ReadEntries(storagePath); len(entries) != 2 {
+		t.Errorf("Expected 2 entries, got %d", len(entries))
+	}
+}
+
+func TestPaste_MaxEntryDuration(t *testing.T) {
+	d, storagePath, stdout, _ := pasteTestDeps(t, "", "workshop for 9h\nreview for 30m\n")
+	d.ReadClipboard = nil
+	d.Config.MaxEntryDuration = "8h"
+	SetDeps(d)
+	defer ResetDeps()
+	resetPasteFlags()
+	defer resetPasteFlags()
+	_ = pasteCmd.Flags().Set("stdin", "true")
+
+	pasteEntries(pasteCmd)
+
+	output := stdout.String()
+	if !strings.Contains(output, "✗ workshop for 9h  (skipped: Duration '9h' (9h) is longer than 8h") {
+		t.Errorf("Expected the 9h line to be skipped, got:\n%s", output)
+	}
+	if entries, _ := storage.ReadEntries(storagePath); len(entries) != 1 || entries[0].Description != "review" {
+		t.Errorf("Expected only the review entry to be logged, got %+v", entries)
+	}
+}
+
+func TestPaste_ParsedLikeLog(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name  string
+		flags []string
+		want  []string
+	}{
+		{"defaults", nil, []string{
+			"✗ for 2h  (skipped: Description cannot be just a duration",
+			"✗ workshop for 30h  (skipped: Duration '30h' (30h) is longer than 24h",
+			"✓ fix [@acme #123 #dev] (1h)",
+			"✓ call [@client] (15m)",
+		}},
+		{"allow-long", []string{"allow-long"}, []string{"✓ workshop [@acme #dev] (30h)"}},
+		{"no-tags", []string{"no-tags"}, []string{"✓ fix #123 [@acme #dev] (1h)"}},
+		{"no-workspace", []string{"no-workspace"}, []string{"✓ fix [#123] (1h)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Workspaces = config.Workspaces{"~/code/acme-app": {Project: "acme", Tags: []string{"dev"}}}
+			d, stdout, _ := testDepsWithConfig(filepath.Join(t.TempDir(), "entries.jsonl"), cfg)
+			d.Stdin = strings.NewReader("for 2h\nworkshop for 30h\nfix #123 for 1h\ncall @client for 15m\n")
+			d.Getwd = func() (string, error) { return filepath.Join(home, "code", "acme-app"), nil }
+			SetDeps(d)
+			defer ResetDeps()
+			resetPasteFlags()
+			defer resetPasteFlags()
+			_ = pasteCmd.Flags().Set("stdin", "true")
+			for _, name := range tt.flags {
+				_ = pasteCmd.Flags().Set(name, "true")
+			}
+
+			pasteEntries(pasteCmd)
+
+			for _, want := range tt.want {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestPaste_NoValidLines(t *testing.T) {
+	exitCode := 0
+	d, storagePath, _, stderr := pasteTestDeps(t, "lunch\nfor 2h\n", "y\n")
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	resetPasteFlags()
+
+	pasteEntries(pasteCmd)
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "None of the lines could be parsed") {
+		t.Errorf("Expected parse error, got: %s", stderr.String())
+	}
+	if entries, _ := storage.ReadEntries(storagePath); len(entries) != 0 {
+		t.Errorf("Expected no entries, got %d", len(entries))
+	}
+}
+
+func TestPaste_EmptyClipboard(t *testing.T) {
+	d, _, stdout, _ := pasteTestDeps(t, "\n  \n", "")
+	SetDeps(d)
+	defer ResetDeps()
+	resetPasteFlags()
+
+	pasteEntries(pasteCmd)
+
+	if !strings.Contains(stdout.String(), "Nothing to paste") {
+		t.Errorf("Expected 'Nothing to paste', got: %s", stdout.String())
+	}
+}
+
+func TestPaste_ClipboardErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		clipboard func() (string, error)
+		wantErr   string
+	}{
+		{"no clipboard reader", nil, "Error: No clipboard available"},
+		{"headless", func() (string, error) { return "", osutil.ErrNoClipboard }, "Error: No clipboard available"},
+		{"tool fails", func() (string, error) { return "", errors.New("xclip: exit status 1") }, "Error: Failed to read the clipboard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode := 0
+			d, _, _, stderr := pasteTestDeps(t, "", "")
+			d.ReadClipboard = tt.clipboard
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			resetPasteFlags()
+
+			pasteEntries(pasteCmd)
+
+			if exitCode != 1 {
+				t.Errorf("Expected exit code 1, got %d", exitCode)
+			}
+			if !strings.Contains(stderr.String(), tt.wantErr) {
+				t.Errorf("Expected %q, got: %s", tt.wantErr, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), "did paste --stdin") {
+				t.Errorf("Expected a hint to use --stdin, got: %s", stderr.String())
+			}
+		})
+	}
+}
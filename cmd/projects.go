@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/didlib"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/query"
+	"github.com/xolan/did/internal/storage"
+)
+
+// projectsCmd represents the projects command
+var projectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "List all projects with their totals",
+	Long: `List every project used in your entries with the number of entries and
+the total time logged, most time first.
+
+Use --json for machine-readable output, e.g. for editor plugins:
+  [{"name": "acme", "count": 12, "total_minutes": 540}, ...]
+
+Use --format tsv for a name, total minutes and count row per project (column
+names first with --header), or --format plain for one value per line.
+
+Use --rename old=new to rename a project in every entry, including deleted
+ones (the old name matches regardless of case). Renaming to an existing
+project merges the two. The storage file is rewritten atomically; --dry-run
+only reports how many entries would change.
+
+Examples:
+  did projects                    List all projects
+  did projects --json             List all projects as JSON
+  did projects --format tsv       acme<TAB>540<TAB>12
+  did projects --rename acme=acme-corp --dry-run`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listProjects(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(projectsCmd)
+
+	projectsCmd.Flags().Bool("json", false, "Output projects as JSON")
+	addFormatFlags(projectsCmd)
+	addRenameFlags(projectsCmd, "project")
+}
+
+// listProjects prints all projects with their entry counts and totals
+func listProjects(cmd *cobra.Command) {
+	if renameRequested(cmd) {
+		renameMetadata(cmd, "project", "@", storage.RenameProject)
+		return
+	}
+
+	asJSON, _ := cmd.Flags().GetBool("json")
+	format, ok := outputFormat(cmd)
+	if !ok {
+		return
+	}
+
+	totals, ok := readTotalsForMetadata()
+	if !ok {
+		return
+	}
+
+	summaries := sortedSummaries(projectSummaries(totals.Projects, false))
+
+	if asJSON {
+		writeJSONOutput(summaries)
+		return
+	}
+	if format != formatHuman {
+		writeRecords(cmd, format, summaryColumns, summaryRecords(summaries))
+		return
+	}
+
+	if len(summaries) == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, "No projects found")
+		_, _ = fmt.Fprintln(deps.Stdout, "Hint: Assign a project with @project, e.g. 'did fix bug @acme for 1h'")
+		return
+	}
+
+	for _, s := range summaries {
+		_, _ = fmt.Fprintf(deps.Stdout, "  %-28s  %10s  (%d %s)\n",
+			"@"+s.Name,
+			formatDuration(s.TotalMinutes),
+			s.Count,
+			pluralize("entry", "entries", s.Count))
+	}
+}
+
+// readTotalsForMetadata reads the totals of all active entries for the
+// projects and tags commands, reporting errors and corrupted lines to stderr.
+// Returns false if the entries could not be read.
+func readTotalsForMetadata() (didlib.Totals, bool) {
+	store, err := deps.Store()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return didlib.Totals{}, false
+	}
+
+	totals, err := store.Totals(query.Criteria{})
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", store.Path())
+		deps.Exit(1)
+		return didlib.Totals{}, false
+	}
+
+	// Display warnings about corrupted lines to stderr
+	printCorruptionWarnings(totals.Warnings)
+	printSuspectWarnings(len(totals.Suspect))
+	return totals, true
+}
+
+// addRenameFlags adds --rename and --dry-run to the projects or tags command,
+// kind naming what is renamed
+func addRenameFlags(cmd *cobra.Command, kind string) {
+	cmd.Flags().String("rename", "", fmt.Sprintf("Rename a %s in every entry (old=new)", kind))
+	cmd.Flags().Bool("dry-run", false, "With --rename, show how many entries would change without writing")
+}
+
+// renameRequested reports whether --rename (or --dry-run, which needs it) was given
+func renameRequested(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed("rename") || cmd.Flags().Changed("dry-run")
+}
+
+// renameMetadata renames the project or tag given by --rename old=new in every
+// entry with rename (storage.RenameProject or storage.RenameTag). kind and
+// prefix ("@" or "#") describe the name in messages.
+func renameMetadata(cmd *cobra.Command, kind, prefix string, rename func(storagePath, from, to string, dryRun bool) (int, error)) {
+	value, _ := cmd.Flags().GetString("rename")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	from, to, found := strings.Cut(value, "=")
+	from = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(from), prefix))
+	to = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(to), prefix))
+	if !found || from == "" || to == "" {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --rename '%s'\n", value)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Give the old and new name, e.g. did %s --rename old=new\n", cmd.Name())
+		deps.Exit(1)
+		return
+	}
+	if !entry.IsValidName(to) {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid %s name '%s'\n", kind, to)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Names may only contain letters, digits, hyphens, underscores, and single spaces between words")
+		deps.Exit(1)
+		return
+	}
+
+	storagePath, err := deps.StoragePath()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return
+	}
+
+	changed, err := rename(storagePath, from, to, dryRun)
+	if err != nil {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Failed to rename %s\n", kind)
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that the file is readable and writable: %s\n", storagePath)
+		deps.Exit(1)
+		return
+	}
+
+	switch {
+	case changed == 0:
+		_, _ = fmt.Fprintf(deps.Stdout, "No entries to rename: no %s %s%s other than %s%s\n", kind, prefix, from, prefix, to)
+	case dryRun:
+		_, _ = fmt.Fprintf(deps.Stdout, "Would rename %s%s to %s%s in %s\n", prefix, from, prefix, to, formatCount(changed, "entry", "entries"))
+	default:
+		_, _ = fmt.Fprintf(deps.Stdout, "Renamed %s%s to %s%s in %s\n", prefix, from, prefix, to, formatCount(changed, "entry", "entries"))
+	}
+}
+
+// sortedSummaries sorts summaries by total time (descending), then by name
+func sortedSummaries(summaries []metadataSummary) []metadataSummary {
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].TotalMinutes != summaries[j].TotalMinutes {
+			return summaries[i].TotalMinutes > summaries[j].TotalMinutes
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries
+}
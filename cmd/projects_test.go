@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// createMetadataTestEntries writes entries with a mix of projects and tags for
+// the projects and tags commands
+func createMetadataTestEntries(t *testing.T) string {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+
+	now := time.Now()
+	deletedAt := now
+	for _, e := range []entry.Entry{
+		{Timestamp: now.AddDate(0, -2, 0), Description: "fix", DurationMinutes: 60, Project: "acme", Tags: []string{"bugfix"}},
+		{Timestamp: now, Description: "review", DurationMinutes: 30, Project: "acme", Tags: []string{"review", "bugfix"}},
+		{Timestamp: now, Description: "call", DurationMinutes: 90, Project: "client"},
+		{Timestamp: now, Description: "email", DurationMinutes: 15},
+		{Timestamp: now, Description: "gone", DurationMinutes: 600, Project: "old", Tags: []string{"old"}, DeletedAt: &deletedAt},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	return storagePath
+}
+
+func TestListProjects(t *testing.T) {
+	d, stdout, stderr := testDeps(createMetadataTestEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+
+	listProjects(projectsCmd)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 projects, got: %s", stdout.String())
+	}
+	if !strings.Contains(lines[0], "@acme") || !strings.Contains(lines[0], "1h 30m") || !strings.Contains(lines[0], "(2 entries)") {
+		t.Errorf("Expected acme first with 1h 30m, got: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "@client") {
+		t.Errorf("Expected client second, got: %s", lines[1])
+	}
+	if strings.Contains(stdout.String(), "old") || strings.Contains(stdout.String(), "no project") {
+		t.Errorf("Expected deleted entries and entries without project to be left out, got: %s", stdout.String())
+	}
+}
+
+func TestListProjects_JSON(t *testing.T) {
+	d, stdout, _ := testDeps(createMetadataTestEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	_ = projectsCmd.Flags().Set("json", "true")
+	defer func() { _ = projectsCmd.Flags().Set("json", "false") }()
+
+	listProjects(projectsCmd)
+
+	var got []metadataSummary
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("Expected valid JSON, got error %v for: %s", err, stdout.String())
+	}
+	// acme and client tie at 90 minutes and are ordered by name
+	expected := []metadataSummary{{Name: "acme", Count: 2, TotalMinutes: 90}, {Name: "client", Count: 1, TotalMinutes: 90}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestListProjects_Empty(t *testing.T) {
+	d, stdout, _ := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	SetDeps(d)
+	defer ResetDeps()
+
+	listProjects(projectsCmd)
+	if !strings.Contains(stdout.String(), "No projects found") {
+		t.Errorf("Expected no projects message, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	_ = projectsCmd.Flags().Set("json", "true")
+	defer func() { _ = projectsCmd.Flags().Set("json", "false") }()
+	listProjects(projectsCmd)
+	if strings.TrimSpace(stdout.String()) != "[]" {
+		t.Errorf("Expected empty JSON array, got: %s", stdout.String())
+	}
+}
+
+func TestListProjects_StoragePathError(t *testing.T) {
+	d, _, stderr := testDeps("")
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	d.StoragePath = func() (string, error) { return "", os.ErrPermission }
+	SetDeps(d)
+	defer ResetDeps()
+
+	listProjects(projectsCmd)
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "Failed to determine storage location") {
+		t.Errorf("Expected storage location error, got: %s", stderr.String())
+	}
+}
+
+// setRenameFlags sets --rename and --dry-run of cmd and returns a func clearing them
+func setRenameFlags(cmd *cobra.Command, rename string, dryRun bool) func() {
+	_ = cmd.Flags().Set("rename", rename)
+	if dryRun {
+		_ = cmd.Flags().Set("dry-run", "true")
+	}
+	return func() {
+		_ = cmd.Flags().Set("rename", "")
+		_ = cmd.Flags().Set("dry-run", "false")
+		cmd.Flags().Lookup("rename").Changed = false
+		cmd.Flags().Lookup("dry-run").Changed = false
+	}
+}
+
+func TestListProjects_Rename(t *testing.T) {
+	storagePath := createMetadataTestEntries(t)
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	clear := setRenameFlags(projectsCmd, "@ACME=client", true)
+	listProjects(projectsCmd)
+	clear()
+	if !strings.Contains(stdout.String(), "Would rename @ACME to @client in 2 entries") {
+		t.Errorf("Expected dry run count, got: %s", stdout.String())
+	}
+	entries, _ := storage.ReadEntries(storagePath)
+	if entries[0].Project != "acme" {
+		t.Fatalf("Expected the dry run to write nothing, got project %q", entries[0].Project)
+	}
+
+	stdout.Reset()
+	defer setRenameFlags(projectsCmd, "acme=client", false)()
+	listProjects(projectsCmd)
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Renamed @acme to @client in 2 entries") {
+		t.Errorf("Expected rename count, got: %s", stdout.String())
+	}
+	totals, ok := readTotalsForMetadata()
+	if !ok {
+		t.Fatalf("Failed to read entries: %s", stderr.String())
+	}
+	expected := []metadataSummary{{Name: "client", Count: 3, TotalMinutes: 180}}
+	if got := sortedSummaries(projectSummaries(totals.Projects, false)); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected the projects merged into %v, got %v", expected, got)
+	}
+
+	stdout.Reset()
+	listProjects(projectsCmd)
+	if !strings.Contains(stdout.String(), "No entries to rename") {
+		t.Errorf("Expected nothing left to rename, got: %s", stdout.String())
+	}
+}
+
+func TestListProjects_RenameInvalid(t *testing.T) {
+	tests := []struct {
+		rename   string
+		expected string
+	}{
+		{"acme", "Invalid --rename 'acme'"},
+		{"acme=", "Invalid --rename 'acme='"},
+		{"acme=acme corp!", "Invalid project name 'acme corp!'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rename, func(t *testing.T) {
+			storagePath := createMetadataTestEntries(t)
+			d, _, stderr := testDeps(storagePath)
+			exitCode := 0
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			defer setRenameFlags(projectsCmd, tt.rename, false)()
+
+			listProjects(projectsCmd)
+
+			if exitCode != 1 || !strings.Contains(stderr.String(), tt.expected) {
+				t.Errorf("Expected exit 1 with %q, got exit %d: %s", tt.expected, exitCode, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/filter"
+	"github.com/xolan/did/internal/storage"
+)
+
+// defaultRecentLimit is the number of descriptions shown by did recent
+const defaultRecentLimit = 10
+
+// recentCmd represents the recent command
+var recentCmd = &cobra.Command{
+	Use:   "recent [@project]",
+	Short: "Show recently used descriptions",
+	Long: `Show the most recently logged distinct descriptions, newest first.
+
+Each description is shown once, with its project and tags, so you can reuse
+it when logging new entries. The output has one description per line, which
+makes it easy to feed into shell history or a fuzzy finder.
+
+Examples:
+  did recent                      Last 10 distinct descriptions
+  did recent -n 20                Last 20 distinct descriptions
+  did recent @acme                Only descriptions logged for project 'acme'
+  did recent --project acme       Alternative syntax for the project filter`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Parse shorthand filters (@project)
+		parseShorthandFilters(cmd, args)
+		showRecent(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(recentCmd)
+
+	recentCmd.Flags().IntP("limit", "n", defaultRecentLimit, "Number of descriptions to show")
+
+	// Note: --project is inherited from root command's PersistentFlags
+}
+
+// showRecent prints the most recent distinct descriptions, newest first
+func showRecent(cmd *cobra.Command) {
+	limit, _ := cmd.Flags().GetInt("limit")
+	projectFilter, _ := cmd.Root().PersistentFlags().GetString("project")
+
+	if limit < 1 {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: --limit must be at least 1 (got %d)\n", limit)
+		deps.Exit(1)
+		return
+	}
+
+	// Get storage path
+	storagePath, err := deps.StoragePath()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return
+	}
+
+	// Read all entries from storage
+	result, err := storage.ReadEntriesWithWarnings(storagePath)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", storagePath)
+		deps.Exit(1)
+		return
+	}
+
+	// Filter out soft-deleted entries
+	var activeEntries []entry.Entry
+	for _, e := range result.Entries {
+		if e.DeletedAt == nil {
+			activeEntries = append(activeEntries, e)
+		}
+	}
+
+	filtered := filter.FilterEntries(activeEntries, filter.NewFilter("", projectFilter, nil))
+
+	descriptions := recentDescriptions(filtered, limit)
+	if len(descriptions) == 0 {
+		if projectFilter != "" {
+			_, _ = fmt.Fprintf(deps.Stdout, "No entries found for project '@%s'\n", entry.QuoteName(projectFilter))
+		} else {
+			_, _ = fmt.Fprintln(deps.Stdout, "No entries found")
+		}
+		return
+	}
+
+	for _, description := range descriptions {
+		_, _ = fmt.Fprintln(deps.Stdout, description)
+	}
+}
+
+// recentDescriptions returns up to limit distinct formatted descriptions
+// (description with project and tags), newest first
+func recentDescriptions(entries []entry.Entry, limit int) []string {
+	// Reverse first so entries with equal timestamps keep the last logged first
+	sorted := make([]entry.Entry, len(entries))
+	for i, e := range entries {
+		sorted[len(entries)-1-i] = e
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.After(sorted[j].Timestamp)
+	})
+
+	seen := make(map[string]bool)
+	var descriptions []string
+	for _, e := range sorted {
+		description := formatEntryForLog(e.Description, displayProject(e), e.Tags)
+		if seen[description] {
+			continue
+		}
+		seen[description] = true
+		descriptions = append(descriptions, description)
+		if len(descriptions) == limit {
+			break
+		}
+	}
+	return descriptions
+}
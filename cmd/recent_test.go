@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+func createRecentTestEntries(t *testing.T) string {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+
+	base := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	deletedAt := base
+	entries := []entry.Entry{
+		{Timestamp: base, Description: "standup", DurationMinutes: 15, Project: "acme", Tags: []string{"daily"}},
+		{Timestamp: base.Add(1 * time.Hour), Description: "code review", DurationMinutes: 60, Project: "acme"},
+		{Timestamp: base.Add(2 * time.Hour), Description: "email", DurationMinutes: 30},
+		{Timestamp: base.Add(3 * time.Hour), Description: "secret", DurationMinutes: 30, DeletedAt: &deletedAt},
+		{Timestamp: base.AddDate(0, 0, 1), Description: "standup", DurationMinutes: 15, Project: "acme", Tags: []string{"daily"}},
+		{Timestamp: base.AddDate(0, 0, 1).Add(time.Hour), Description: "planning", DurationMinutes: 45, Project: "client"},
+	}
+	for _, e := range entries {
+		e.RawInput = formatRawInput(e)
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	return storagePath
+}
+
+func TestShowRecent(t *testing.T) {
+	storagePath := createRecentTestEntries(t)
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	resetFilterFlags(recentCmd)
+
+	showRecent(recentCmd)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	expected := "planning [@client]\nstandup [@acme #daily]\nemail\ncode review [@acme]\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, stdout.String())
+	}
+}
+
+func TestShowRecent_Limit(t *testing.T) {
+	storagePath := createRecentTestEntries(t)
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	resetFilterFlags(recentCmd)
+	_ = recentCmd.Flags().Set("limit", "2")
+	defer func() { _ = recentCmd.Flags().Set("limit", "10") }()
+
+	showRecent(recentCmd)
+
+	expected := "planning [@client]\nstandup [@acme #daily]\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, stdout.String())
+	}
+}
+
+func TestShowRecent_ProjectFilter(t *testing.T) {
+	storagePath := createRecentTestEntries(t)
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	resetFilterFlags(recentCmd)
+	defer resetFilterFlags(recentCmd)
+
+	parseShorthandFilters(recentCmd, []string{"@acme"})
+	showRecent(recentCmd)
+
+	expected := "standup [@acme #daily]\ncode review [@acme]\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, stdout.String())
+	}
+}
+
+func TestShowRecent_NoEntries(t *testing.T) {
+	d, stdout, _ := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	SetDeps(d)
+	defer ResetDeps()
+	resetFilterFlags(recentCmd)
+
+	showRecent(recentCmd)
+
+	if !strings.Contains(stdout.String(), "No entries found") {
+		t.Errorf("Expected no entries message, got: %s", stdout.String())
+	}
+}
+
+func TestShowRecent_InvalidLimit(t *testing.T) {
+	d, _, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	_ = recentCmd.Flags().Set("limit", "0")
+	defer func() { _ = recentCmd.Flags().Set("limit", "10") }()
+
+	showRecent(recentCmd)
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "--limit must be at least 1") {
+		t.Errorf("Expected limit error, got: %s", stderr.String())
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/xolan/did/internal/entry"
 	"github.com/xolan/did/internal/filter"
+	"github.com/xolan/did/internal/query"
+	"github.com/xolan/did/internal/stats"
 	"github.com/xolan/did/internal/storage"
 	"github.com/xolan/did/internal/timeutil"
 )
@@ -36,10 +38,47 @@ Report Types:
     did report --by project        Show hours grouped by all projects
     did report --by tag            Show hours grouped by all tags
 
+  Text Digest:
+    A fixed plain-text recap for email or pipes: entries per day,
+    totals per project and a grand total. Covers this week unless
+    --from/--to or --last is given; --project/--tag narrow it down.
+
+    did report --text              This week's digest
+    did report --text --last 30    Digest of the last 30 days
+
+  Email Digest:
+    A ready-to-send email body: a greeting with the date range, a
+    short paragraph with total time, busiest day, top project and
+    top tags, the entries per day and a sign-off.
+
+    did report --format email --this-week    This week's email
+    did report --format email --prev-week    Last week's email
+
 Date Filtering:
   Use --from and --to to filter by date range
   Use --last to filter by relative days (e.g., 'last 7 days')
 
+Client Filtering:
+  Use --client to report only the entries of one client, with any of
+  the report types above (e.g., did report --by project --client AcmeCorp)
+
+Entries Past Midnight:
+  An entry counts on the day it starts, so 5 hours logged at 22:00 all
+  count on that day. Use --split-days (or set split_at_midnight in the
+  config) to apportion it to each day it covers: 2h on the first day and
+  3h on the next. Storage is not changed.
+
+Storage Files:
+  Use --show-source to show the storage file each listed entry comes
+  from, in single project/tag reports and digests (useful with a shared
+  storage directory).
+
+Rounding:
+  Use --round N to round durations to multiples of N minutes for billing.
+  Entries and projects are rounded so that they add up exactly to the
+  rounded total (largest remainder method). Tags can overlap, so each tag
+  is rounded on its own.
+
 Examples:
 
   Single Project/Tag Reports:
@@ -54,6 +93,7 @@ Examples:
     did report --by project              Show hours by all projects
     did report --by tag                  Show hours by all tags
     did report --by project --last 30    Project breakdown for last 30 days
+    did report --by project --round 15   Project breakdown in 15 minute increments
     did report --by tag --from 2024-01-01 --to 2024-01-31    Tag breakdown for date range`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Parse shorthand filters (@project, #tag) and remove them from args
@@ -73,6 +113,22 @@ func init() {
 	reportCmd.Flags().String("to", "", "End date for filtering (YYYY-MM-DD or DD/MM/YYYY)")
 	reportCmd.Flags().Int("last", 0, "Filter by last N days (e.g., --last 7 for last 7 days)")
 
+	// Rounding flag for billing
+	reportCmd.Flags().Int("round", 0, "Round durations to multiples of N minutes, keeping the total exact (e.g., --round 15)")
+
+	// Plain-text digest
+	reportCmd.Flags().Bool("text", false, "Print a plain-text digest (per day, per project, total) suitable for email")
+	reportCmd.Flags().String("format", "", "Digest format: 'text' (same as --text) or 'email' (ready-to-send email body)")
+	reportCmd.Flags().Bool("this-week", false, "Digest of the current week (default for --text and --format)")
+	reportCmd.Flags().Bool("prev-week", false, "Digest of the previous week")
+	addPeriodFlagAliases(reportCmd)
+
+	// Day attribution of entries running past midnight
+	reportCmd.Flags().Bool("split-days", false, "Apportion entries running past midnight to each day they cover (default: split_at_midnight)")
+
+	// Storage file of each entry, for a storage directory
+	addShowSourceFlag(reportCmd)
+
 	// Note: --project and --tag flags are inherited from root command's PersistentFlags
 }
 
@@ -93,6 +149,14 @@ func runReport(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	// Validate --round flag value
+	if roundStep, _ := cmd.Flags().GetInt("round"); roundStep < 0 || roundStep > entry.MaxDurationMinutes {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --round value %d\n", roundStep)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use a number of minutes, e.g. --round 15")
+		deps.Exit(1)
+		return
+	}
+
 	// Validate flag combinations
 	if groupBy != "" && (projectFilter != "" || len(tagFilters) > 0) {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Cannot use --by with --project or --tag filters")
@@ -103,7 +167,45 @@ func runReport(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// Determine report mode
+	// Determine report mode: --text is short for --format text
+	format, _ := cmd.Flags().GetString("format")
+	flagName := "--format"
+	if textDigest, _ := cmd.Flags().GetBool("text"); textDigest {
+		if format != "" && format != "text" {
+			_, _ = fmt.Fprintln(deps.Stderr, "Error: Cannot use --text with --format "+format)
+			deps.Exit(1)
+			return
+		}
+		format, flagName = "text", "--text"
+	}
+	if format != "" && format != "text" && format != "email" {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --format value '%s'. Must be 'text' or 'email'\n", format)
+		deps.Exit(1)
+		return
+	}
+	if format != "" {
+		if roundStep, _ := cmd.Flags().GetInt("round"); groupBy != "" || roundStep != 0 {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Cannot use %s with --by or --round\n", flagName)
+			_, _ = fmt.Fprintln(deps.Stderr, "Hint: The digest always groups entries by day and project")
+			deps.Exit(1)
+			return
+		}
+		if format == "email" {
+			runEmailReport(cmd, flagName, projectFilter, tagFilters)
+		} else {
+			runTextReport(cmd, flagName, projectFilter, tagFilters)
+		}
+		return
+	}
+	thisWeek, _ := cmd.Flags().GetBool("this-week")
+	prevWeek, _ := cmd.Flags().GetBool("prev-week")
+	if thisWeek || prevWeek {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: --this-week and --prev-week only apply to --text and --format")
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use --last or --from/--to with other reports")
+		deps.Exit(1)
+		return
+	}
+
 	if groupBy == "project" {
 		// Grouped by project report (subtask 3.1)
 		runGroupByProjectReport(cmd)
@@ -162,7 +264,7 @@ func runSingleProjectReport(cmd *cobra.Command, projectFilter string) {
 
 	if lastDays > 0 {
 		// Use relative days
-		now := time.Now()
+		now := configuredNow()
 		endDate = timeutil.EndOfDay(now)
 		startDate = timeutil.StartOfDay(now.AddDate(0, 0, -(lastDays - 1)))
 		hasDateFilter = true
@@ -173,7 +275,7 @@ func runSingleProjectReport(cmd *cobra.Command, projectFilter string) {
 		// Parse from date
 		if fromStr != "" {
 			var err error
-			startDate, err = timeutil.ParseDate(fromStr)
+			startDate, err = parseDateFlag(fromStr)
 			if err != nil {
 				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --from date: %v\n", err)
 				deps.Exit(1)
@@ -187,7 +289,7 @@ func runSingleProjectReport(cmd *cobra.Command, projectFilter string) {
 		// Parse to date
 		if toStr != "" {
 			var err error
-			toDate, err := timeutil.ParseDate(toStr)
+			toDate, err := parseDateFlag(toStr)
 			if err != nil {
 				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --to date: %v\n", err)
 				deps.Exit(1)
@@ -196,7 +298,7 @@ func runSingleProjectReport(cmd *cobra.Command, projectFilter string) {
 			endDate = timeutil.EndOfDay(toDate)
 		} else {
 			// No to date: use now
-			endDate = timeutil.EndOfDay(time.Now())
+			endDate = timeutil.EndOfDay(configuredNow())
 		}
 	}
 
@@ -221,24 +323,24 @@ func runSingleProjectReport(cmd *cobra.Command, projectFilter string) {
 	}
 
 	// Display warnings about corrupted lines to stderr
-	if len(result.Warnings) > 0 {
-		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Found %d corrupted line(s) in storage file:\n", len(result.Warnings))
-		for _, warning := range result.Warnings {
-			_, _ = fmt.Fprintln(deps.Stderr, formatCorruptionWarning(warning))
-		}
-		_, _ = fmt.Fprintln(deps.Stderr)
-	}
+	printCorruptionWarnings(result.Warnings)
 
-	// Filter out soft-deleted entries
+	// Filter out soft-deleted entries and entries with a zero or negative duration
 	var activeEntries []entry.Entry
 	for _, e := range result.Entries {
-		if e.DeletedAt == nil {
+		if e.DeletedAt == nil && e.HasValidDuration() {
 			activeEntries = append(activeEntries, e)
 		}
 	}
+	activeEntries = splitDays(cmd, activeEntries)
+	client := reportClient(cmd)
 
 	// Create filter with project
 	f := filter.NewFilter("", projectFilter, nil)
+	f.Client = client
+	printSuspectWarnings(countSuspect(result.Entries, func(e entry.Entry) bool {
+		return f.Matches(e) && (!hasDateFilter || timeutil.IsInRange(e.Timestamp, startDate, endDate))
+	}))
 
 	// Filter entries by project
 	filtered := filter.FilterEntries(activeEntries, f)
@@ -257,26 +359,29 @@ func runSingleProjectReport(cmd *cobra.Command, projectFilter string) {
 	// Check if any results found
 	if len(filtered) == 0 {
 		if hasDateFilter {
-			_, _ = fmt.Fprintf(deps.Stdout, "No entries found for project '@%s' in the specified date range\n", projectFilter)
+			_, _ = fmt.Fprintf(deps.Stdout, "No entries found for project '@%s'%s in the specified date range\n", entry.QuoteName(projectFilter), clientSuffix(client))
 		} else {
-			_, _ = fmt.Fprintf(deps.Stdout, "No entries found for project '@%s'\n", projectFilter)
+			_, _ = fmt.Fprintf(deps.Stdout, "No entries found for project '@%s'%s\n", entry.QuoteName(projectFilter), clientSuffix(client))
 		}
 		return
 	}
 
-	// Calculate total duration
-	totalMinutes := 0
-	for _, e := range filtered {
-		totalMinutes += e.DurationMinutes
+	// Calculate total duration, rounding each entry so they add up to the total
+	roundStep, _ := cmd.Flags().GetInt("round")
+	durations := make([]int, len(filtered))
+	for i, e := range filtered {
+		durations[i] = e.DurationMinutes
 	}
+	durations, totalMinutes := stats.RoundToTotal(durations, roundStep)
 
 	// Display results
-	resultHeader := fmt.Sprintf("Report for project '@%s'", projectFilter)
+	resultHeader := fmt.Sprintf("Report for project '@%s'", entry.QuoteName(projectFilter))
+	resultHeader += clientSuffix(client)
 	if hasDateFilter {
 		if lastDays > 0 {
-			resultHeader += fmt.Sprintf(" (last %d %s)", lastDays, pluralize("day", lastDays))
+			resultHeader += fmt.Sprintf(" (last %s)", formatCount(lastDays, "day", "days"))
 		} else {
-			resultHeader += fmt.Sprintf(" (%s)", formatDateRangeForDisplay(startDate, endDate))
+			resultHeader += fmt.Sprintf(" (%s)", query.FormatDateRange(startDate, endDate))
 		}
 	}
 	_, _ = fmt.Fprintln(deps.Stdout, resultHeader)
@@ -286,19 +391,21 @@ func runSingleProjectReport(cmd *cobra.Command, projectFilter string) {
 	// Calculate width for right-aligned indices
 	maxIndexWidth := len(fmt.Sprintf("%d", len(filtered)))
 
+	sourceOf := sourceColumn(cmd, filtered)
 	for i, e := range filtered {
-		_, _ = fmt.Fprintf(deps.Stdout, "  [%*d] %s %s  %s  (%s)\n",
+		_, _ = fmt.Fprintf(deps.Stdout, "  [%*d] %s %s  %s%s  (%s)\n",
 			maxIndexWidth,
 			i+1, // 1-based index for user reference
 			e.Timestamp.Format("2006-01-02"),
 			e.Timestamp.Format("15:04"),
-			formatEntryForLog(e.Description, e.Project, e.Tags),
-			formatDuration(e.DurationMinutes))
+			sourceOf(e),
+			formatEntryForLog(e.Description, displayProject(e), e.Tags),
+			formatDuration(durations[i]))
 	}
 
 	_, _ = fmt.Fprintln(deps.Stdout)
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 60))
-	_, _ = fmt.Fprintf(deps.Stdout, "Total: %s (%d %s)\n", formatDuration(totalMinutes), len(filtered), pluralize("entry", len(filtered)))
+	_, _ = fmt.Fprintf(deps.Stdout, "Total: %s (%d %s%s)\n", formatDuration(totalMinutes), len(filtered), pluralize("entry", "entries", len(filtered)), reportRoundingNote(roundStep))
 }
 
 // runSingleTagReport generates a report for one or more tags (ANDed together)
@@ -322,7 +429,7 @@ func runSingleTagReport(cmd *cobra.Command, tagFilters []string) {
 
 	if lastDays > 0 {
 		// Use relative days
-		now := time.Now()
+		now := configuredNow()
 		endDate = timeutil.EndOfDay(now)
 		startDate = timeutil.StartOfDay(now.AddDate(0, 0, -(lastDays - 1)))
 		hasDateFilter = true
@@ -333,7 +440,7 @@ func runSingleTagReport(cmd *cobra.Command, tagFilters []string) {
 		// Parse from date
 		if fromStr != "" {
 			var err error
-			startDate, err = timeutil.ParseDate(fromStr)
+			startDate, err = parseDateFlag(fromStr)
 			if err != nil {
 				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --from date: %v\n", err)
 				deps.Exit(1)
@@ -346,7 +453,7 @@ func runSingleTagReport(cmd *cobra.Command, tagFilters []string) {
 
 		if toStr != "" {
 			var err error
-			toDate, err := timeutil.ParseDate(toStr)
+			toDate, err := parseDateFlag(toStr)
 			if err != nil {
 				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --to date: %v\n", err)
 				deps.Exit(1)
@@ -354,7 +461,7 @@ func runSingleTagReport(cmd *cobra.Command, tagFilters []string) {
 			}
 			endDate = timeutil.EndOfDay(toDate)
 		} else {
-			endDate = timeutil.EndOfDay(time.Now())
+			endDate = timeutil.EndOfDay(configuredNow())
 		}
 	}
 
@@ -379,24 +486,24 @@ func runSingleTagReport(cmd *cobra.Command, tagFilters []string) {
 	}
 
 	// Display warnings about corrupted lines to stderr
-	if len(result.Warnings) > 0 {
-		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Found %d corrupted line(s) in storage file:\n", len(result.Warnings))
-		for _, warning := range result.Warnings {
-			_, _ = fmt.Fprintln(deps.Stderr, formatCorruptionWarning(warning))
-		}
-		_, _ = fmt.Fprintln(deps.Stderr)
-	}
+	printCorruptionWarnings(result.Warnings)
 
-	// Filter out soft-deleted entries
+	// Filter out soft-deleted entries and entries with a zero or negative duration
 	var activeEntries []entry.Entry
 	for _, e := range result.Entries {
-		if e.DeletedAt == nil {
+		if e.DeletedAt == nil && e.HasValidDuration() {
 			activeEntries = append(activeEntries, e)
 		}
 	}
+	activeEntries = splitDays(cmd, activeEntries)
+	client := reportClient(cmd)
 
 	// Create filter with tags (multiple tags are ANDed together)
 	f := filter.NewFilter("", "", tagFilters)
+	f.Client = client
+	printSuspectWarnings(countSuspect(result.Entries, func(e entry.Entry) bool {
+		return f.Matches(e) && (!hasDateFilter || timeutil.IsInRange(e.Timestamp, startDate, endDate))
+	}))
 
 	// Filter entries by tags
 	filtered := filter.FilterEntries(activeEntries, f)
@@ -415,12 +522,12 @@ func runSingleTagReport(cmd *cobra.Command, tagFilters []string) {
 	// Format tag list for display
 	var tagDisplay string
 	if len(tagFilters) == 1 {
-		tagDisplay = fmt.Sprintf("tag '#%s'", tagFilters[0])
+		tagDisplay = fmt.Sprintf("tag '#%s'", entry.QuoteName(tagFilters[0]))
 	} else {
 		// Multiple tags - format as '#tag1, #tag2'
 		tagStrs := make([]string, len(tagFilters))
 		for i, tag := range tagFilters {
-			tagStrs[i] = "#" + tag
+			tagStrs[i] = "#" + entry.QuoteName(tag)
 		}
 		tagDisplay = fmt.Sprintf("tags '%s'", strings.Join(tagStrs, ", "))
 	}
@@ -428,26 +535,29 @@ func runSingleTagReport(cmd *cobra.Command, tagFilters []string) {
 	// Check if any results found
 	if len(filtered) == 0 {
 		if hasDateFilter {
-			_, _ = fmt.Fprintf(deps.Stdout, "No entries found for %s in the specified date range\n", tagDisplay)
+			_, _ = fmt.Fprintf(deps.Stdout, "No entries found for %s%s in the specified date range\n", tagDisplay, clientSuffix(client))
 		} else {
-			_, _ = fmt.Fprintf(deps.Stdout, "No entries found for %s\n", tagDisplay)
+			_, _ = fmt.Fprintf(deps.Stdout, "No entries found for %s%s\n", tagDisplay, clientSuffix(client))
 		}
 		return
 	}
 
-	// Calculate total duration
-	totalMinutes := 0
-	for _, e := range filtered {
-		totalMinutes += e.DurationMinutes
+	// Calculate total duration, rounding each entry so they add up to the total
+	roundStep, _ := cmd.Flags().GetInt("round")
+	durations := make([]int, len(filtered))
+	for i, e := range filtered {
+		durations[i] = e.DurationMinutes
 	}
+	durations, totalMinutes := stats.RoundToTotal(durations, roundStep)
 
 	// Display results
 	resultHeader := fmt.Sprintf("Report for %s", tagDisplay)
+	resultHeader += clientSuffix(client)
 	if hasDateFilter {
 		if lastDays > 0 {
-			resultHeader += fmt.Sprintf(" (last %d %s)", lastDays, pluralize("day", lastDays))
+			resultHeader += fmt.Sprintf(" (last %s)", formatCount(lastDays, "day", "days"))
 		} else {
-			resultHeader += fmt.Sprintf(" (%s)", formatDateRangeForDisplay(startDate, endDate))
+			resultHeader += fmt.Sprintf(" (%s)", query.FormatDateRange(startDate, endDate))
 		}
 	}
 	_, _ = fmt.Fprintln(deps.Stdout, resultHeader)
@@ -457,19 +567,21 @@ func runSingleTagReport(cmd *cobra.Command, tagFilters []string) {
 	// Calculate width for right-aligned indices
 	maxIndexWidth := len(fmt.Sprintf("%d", len(filtered)))
 
+	sourceOf := sourceColumn(cmd, filtered)
 	for i, e := range filtered {
-		_, _ = fmt.Fprintf(deps.Stdout, "  [%*d] %s %s  %s  (%s)\n",
+		_, _ = fmt.Fprintf(deps.Stdout, "  [%*d] %s %s  %s%s  (%s)\n",
 			maxIndexWidth,
 			i+1, // 1-based index for user reference
 			e.Timestamp.Format("2006-01-02"),
 			e.Timestamp.Format("15:04"),
-			formatEntryForLog(e.Description, e.Project, e.Tags),
-			formatDuration(e.DurationMinutes))
+			sourceOf(e),
+			formatEntryForLog(e.Description, displayProject(e), e.Tags),
+			formatDuration(durations[i]))
 	}
 
 	_, _ = fmt.Fprintln(deps.Stdout)
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 60))
-	_, _ = fmt.Fprintf(deps.Stdout, "Total: %s (%d %s)\n", formatDuration(totalMinutes), len(filtered), pluralize("entry", len(filtered)))
+	_, _ = fmt.Fprintf(deps.Stdout, "Total: %s (%d %s%s)\n", formatDuration(totalMinutes), len(filtered), pluralize("entry", "entries", len(filtered)), reportRoundingNote(roundStep))
 }
 
 // runGroupByProjectReport generates a report showing hours grouped by all projects
@@ -493,7 +605,7 @@ func runGroupByProjectReport(cmd *cobra.Command) {
 
 	if lastDays > 0 {
 		// Use relative days
-		now := time.Now()
+		now := configuredNow()
 		endDate = timeutil.EndOfDay(now)
 		startDate = timeutil.StartOfDay(now.AddDate(0, 0, -(lastDays - 1)))
 		hasDateFilter = true
@@ -504,7 +616,7 @@ func runGroupByProjectReport(cmd *cobra.Command) {
 		// Parse from date
 		if fromStr != "" {
 			var err error
-			startDate, err = timeutil.ParseDate(fromStr)
+			startDate, err = parseDateFlag(fromStr)
 			if err != nil {
 				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --from date: %v\n", err)
 				deps.Exit(1)
@@ -518,7 +630,7 @@ func runGroupByProjectReport(cmd *cobra.Command) {
 		// Parse to date
 		if toStr != "" {
 			var err error
-			toDate, err := timeutil.ParseDate(toStr)
+			toDate, err := parseDateFlag(toStr)
 			if err != nil {
 				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --to date: %v\n", err)
 				deps.Exit(1)
@@ -527,7 +639,7 @@ func runGroupByProjectReport(cmd *cobra.Command) {
 			endDate = timeutil.EndOfDay(toDate)
 		} else {
 			// No to date: use now
-			endDate = timeutil.EndOfDay(time.Now())
+			endDate = timeutil.EndOfDay(configuredNow())
 		}
 	}
 
@@ -552,21 +664,22 @@ func runGroupByProjectReport(cmd *cobra.Command) {
 	}
 
 	// Display warnings about corrupted lines to stderr
-	if len(result.Warnings) > 0 {
-		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Found %d corrupted line(s) in storage file:\n", len(result.Warnings))
-		for _, warning := range result.Warnings {
-			_, _ = fmt.Fprintln(deps.Stderr, formatCorruptionWarning(warning))
-		}
-		_, _ = fmt.Fprintln(deps.Stderr)
-	}
+	printCorruptionWarnings(result.Warnings)
 
-	// Filter out soft-deleted entries
+	// Filter out soft-deleted entries and entries with a zero or negative duration
 	var activeEntries []entry.Entry
 	for _, e := range result.Entries {
-		if e.DeletedAt == nil {
+		if e.DeletedAt == nil && e.HasValidDuration() {
 			activeEntries = append(activeEntries, e)
 		}
 	}
+	activeEntries = splitDays(cmd, activeEntries)
+	client := reportClient(cmd)
+	f := clientFilter(client)
+	activeEntries = filter.FilterEntries(activeEntries, f)
+	printSuspectWarnings(countSuspect(result.Entries, func(e entry.Entry) bool {
+		return f.Matches(e) && (!hasDateFilter || timeutil.IsInRange(e.Timestamp, startDate, endDate))
+	}))
 
 	// Apply date filtering if specified
 	filtered := activeEntries
@@ -583,9 +696,9 @@ func runGroupByProjectReport(cmd *cobra.Command) {
 	// Check if any results found
 	if len(filtered) == 0 {
 		if hasDateFilter {
-			_, _ = fmt.Fprintln(deps.Stdout, "No entries found in the specified date range")
+			_, _ = fmt.Fprintf(deps.Stdout, "No entries found%s in the specified date range\n", clientSuffix(client))
 		} else {
-			_, _ = fmt.Fprintln(deps.Stdout, "No entries found")
+			_, _ = fmt.Fprintf(deps.Stdout, "No entries found%s\n", clientSuffix(client))
 		}
 		return
 	}
@@ -624,49 +737,52 @@ func runGroupByProjectReport(cmd *cobra.Command) {
 		return groups[i].TotalMinutes > groups[j].TotalMinutes
 	})
 
-	// Calculate grand totals
-	grandTotalMinutes := 0
+	// Calculate grand totals, rounding each project so they add up to the grand total
+	roundStep, _ := cmd.Flags().GetInt("round")
+	groupMinutes := make([]int, len(groups))
 	grandTotalEntries := 0
-	for _, group := range groups {
-		grandTotalMinutes += group.TotalMinutes
+	for i, group := range groups {
+		groupMinutes[i] = group.TotalMinutes
 		grandTotalEntries += group.EntryCount
 	}
+	groupMinutes, grandTotalMinutes := stats.RoundToTotal(groupMinutes, roundStep)
 
 	// Display results
-	reportHeader := "Report grouped by project"
+	reportHeader := "Report grouped by project" + clientSuffix(client)
 	if hasDateFilter {
 		if lastDays > 0 {
-			reportHeader += fmt.Sprintf(" (last %d %s)", lastDays, pluralize("day", lastDays))
+			reportHeader += fmt.Sprintf(" (last %s)", formatCount(lastDays, "day", "days"))
 		} else {
-			reportHeader += fmt.Sprintf(" (%s)", formatDateRangeForDisplay(startDate, endDate))
+			reportHeader += fmt.Sprintf(" (%s)", query.FormatDateRange(startDate, endDate))
 		}
 	}
 	_, _ = fmt.Fprintln(deps.Stdout, reportHeader)
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 60))
 	_, _ = fmt.Fprintln(deps.Stdout)
 
-	for _, group := range groups {
+	for i, group := range groups {
 		// Format project name with special handling for "(no project)"
 		projectDisplay := group.Name
 		if group.Name != "(no project)" {
-			projectDisplay = "@" + group.Name
+			projectDisplay = "@" + entry.QuoteName(group.Name)
 		}
 
 		_, _ = fmt.Fprintf(deps.Stdout, "  %-28s  %10s  (%d %s)\n",
 			projectDisplay,
-			formatDuration(group.TotalMinutes),
+			formatDuration(groupMinutes[i]),
 			group.EntryCount,
-			pluralize("entry", group.EntryCount))
+			pluralize("entry", "entries", group.EntryCount))
 	}
 
 	_, _ = fmt.Fprintln(deps.Stdout)
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 60))
-	_, _ = fmt.Fprintf(deps.Stdout, "Grand Total: %s (%d %s across %d %s)\n",
+	_, _ = fmt.Fprintf(deps.Stdout, "Grand Total: %s (%d %s across %d %s%s)\n",
 		formatDuration(grandTotalMinutes),
 		grandTotalEntries,
-		pluralize("entry", grandTotalEntries),
+		pluralize("entry", "entries", grandTotalEntries),
 		len(groups),
-		pluralize("project", len(groups)))
+		pluralize("project", "projects", len(groups)),
+		reportRoundingNote(roundStep))
 }
 
 // runGroupByTagReport generates a report showing hours grouped by all tags
@@ -690,7 +806,7 @@ func runGroupByTagReport(cmd *cobra.Command) {
 
 	if lastDays > 0 {
 		// Use relative days
-		now := time.Now()
+		now := configuredNow()
 		endDate = timeutil.EndOfDay(now)
 		startDate = timeutil.StartOfDay(now.AddDate(0, 0, -(lastDays - 1)))
 		hasDateFilter = true
@@ -701,7 +817,7 @@ func runGroupByTagReport(cmd *cobra.Command) {
 		// Parse from date
 		if fromStr != "" {
 			var err error
-			startDate, err = timeutil.ParseDate(fromStr)
+			startDate, err = parseDateFlag(fromStr)
 			if err != nil {
 				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --from date: %v\n", err)
 				deps.Exit(1)
@@ -715,7 +831,7 @@ func runGroupByTagReport(cmd *cobra.Command) {
 		// Parse to date
 		if toStr != "" {
 			var err error
-			toDate, err := timeutil.ParseDate(toStr)
+			toDate, err := parseDateFlag(toStr)
 			if err != nil {
 				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --to date: %v\n", err)
 				deps.Exit(1)
@@ -724,7 +840,7 @@ func runGroupByTagReport(cmd *cobra.Command) {
 			endDate = timeutil.EndOfDay(toDate)
 		} else {
 			// No to date: use now
-			endDate = timeutil.EndOfDay(time.Now())
+			endDate = timeutil.EndOfDay(configuredNow())
 		}
 	}
 
@@ -749,21 +865,22 @@ func runGroupByTagReport(cmd *cobra.Command) {
 	}
 
 	// Display warnings about corrupted lines to stderr
-	if len(result.Warnings) > 0 {
-		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Found %d corrupted line(s) in storage file:\n", len(result.Warnings))
-		for _, warning := range result.Warnings {
-			_, _ = fmt.Fprintln(deps.Stderr, formatCorruptionWarning(warning))
-		}
-		_, _ = fmt.Fprintln(deps.Stderr)
-	}
+	printCorruptionWarnings(result.Warnings)
 
-	// Filter out soft-deleted entries
+	// Filter out soft-deleted entries and entries with a zero or negative duration
 	var activeEntries []entry.Entry
 	for _, e := range result.Entries {
-		if e.DeletedAt == nil {
+		if e.DeletedAt == nil && e.HasValidDuration() {
 			activeEntries = append(activeEntries, e)
 		}
 	}
+	activeEntries = splitDays(cmd, activeEntries)
+	client := reportClient(cmd)
+	f := clientFilter(client)
+	activeEntries = filter.FilterEntries(activeEntries, f)
+	printSuspectWarnings(countSuspect(result.Entries, func(e entry.Entry) bool {
+		return f.Matches(e) && (!hasDateFilter || timeutil.IsInRange(e.Timestamp, startDate, endDate))
+	}))
 
 	// Apply date filtering if specified
 	filtered := activeEntries
@@ -780,9 +897,9 @@ func runGroupByTagReport(cmd *cobra.Command) {
 	// Check if any results found
 	if len(filtered) == 0 {
 		if hasDateFilter {
-			_, _ = fmt.Fprintln(deps.Stdout, "No entries found in the specified date range")
+			_, _ = fmt.Fprintf(deps.Stdout, "No entries found%s in the specified date range\n", clientSuffix(client))
 		} else {
-			_, _ = fmt.Fprintln(deps.Stdout, "No entries found")
+			_, _ = fmt.Fprintf(deps.Stdout, "No entries found%s\n", clientSuffix(client))
 		}
 		return
 	}
@@ -838,13 +955,17 @@ func runGroupByTagReport(cmd *cobra.Command) {
 		grandTotalMinutes += e.DurationMinutes
 	}
 
+	// Tag groups overlap, so they can't add up to the grand total; round each on its own
+	roundStep, _ := cmd.Flags().GetInt("round")
+	_, grandTotalMinutes = stats.RoundToTotal([]int{grandTotalMinutes}, roundStep)
+
 	// Display results
-	reportHeader := "Report grouped by tag"
+	reportHeader := "Report grouped by tag" + clientSuffix(client)
 	if hasDateFilter {
 		if lastDays > 0 {
-			reportHeader += fmt.Sprintf(" (last %d %s)", lastDays, pluralize("day", lastDays))
+			reportHeader += fmt.Sprintf(" (last %s)", formatCount(lastDays, "day", "days"))
 		} else {
-			reportHeader += fmt.Sprintf(" (%s)", formatDateRangeForDisplay(startDate, endDate))
+			reportHeader += fmt.Sprintf(" (%s)", query.FormatDateRange(startDate, endDate))
 		}
 	}
 	_, _ = fmt.Fprintln(deps.Stdout, reportHeader)
@@ -855,22 +976,352 @@ func runGroupByTagReport(cmd *cobra.Command) {
 		// Format tag name with special handling for "(no tags)"
 		tagDisplay := group.Name
 		if group.Name != "(no tags)" {
-			tagDisplay = "#" + group.Name
+			tagDisplay = "#" + entry.QuoteName(group.Name)
 		}
 
+		_, groupMinutes := stats.RoundToTotal([]int{group.TotalMinutes}, roundStep)
 		_, _ = fmt.Fprintf(deps.Stdout, "  %-28s  %10s  (%d %s)\n",
 			tagDisplay,
-			formatDuration(group.TotalMinutes),
+			formatDuration(groupMinutes),
 			group.EntryCount,
-			pluralize("entry", group.EntryCount))
+			pluralize("entry", "entries", group.EntryCount))
 	}
 
 	_, _ = fmt.Fprintln(deps.Stdout)
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 60))
-	_, _ = fmt.Fprintf(deps.Stdout, "Grand Total: %s (%d %s across %d %s)\n",
+	_, _ = fmt.Fprintf(deps.Stdout, "Grand Total: %s (%d %s across %d %s%s)\n",
 		formatDuration(grandTotalMinutes),
 		grandTotalEntries,
-		pluralize("entry", grandTotalEntries),
+		pluralize("entry", "entries", grandTotalEntries),
 		len(groups),
-		pluralize("tag", len(groups)))
+		pluralize("tag", "tags", len(groups)),
+		reportRoundingNote(roundStep))
+}
+
+// reportRoundingNote returns the suffix added to report totals when --round is used
+func reportRoundingNote(roundStep int) string {
+	if roundStep <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(", rounded to %s", formatDuration(roundStep))
+}
+
+// reportClient returns the --client filter of a report, empty when not given
+func reportClient(cmd *cobra.Command) string {
+	client, _ := cmd.Root().PersistentFlags().GetString("client")
+	return strings.TrimSpace(client)
+}
+
+// clientFilter returns a filter matching the entries of client, or all
+// entries when client is empty
+func clientFilter(client string) *filter.Filter {
+	f := filter.NewFilter("", "", nil)
+	f.Client = client
+	return f
+}
+
+// clientSuffix names the client filter of a report header, e.g. " (client AcmeCorp)"
+func clientSuffix(client string) string {
+	if client == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (client %s)", client)
+}
+
+// splitDays splits entries running past midnight into a part per day they
+// cover (see stats.SplitAtMidnight) when --split-days or the split_at_midnight
+// setting asks for it; the flag takes precedence. Days are those of the
+// configured timezone. Storage is never changed.
+func splitDays(cmd *cobra.Command, entries []entry.Entry) []entry.Entry {
+	split := deps.Config.SplitAtMidnight
+	if cmd.Flags().Changed("split-days") {
+		split, _ = cmd.Flags().GetBool("split-days")
+	}
+	if !split {
+		return entries
+	}
+
+	loc := configuredLocation()
+	local := make([]entry.Entry, len(entries))
+	for i, e := range entries {
+		e.Timestamp = e.Timestamp.In(loc)
+		local[i] = e
+	}
+	return stats.SplitAtMidnight(local)
+}
+
+// configuredLocation returns the location of the configured timezone
+func configuredLocation() *time.Location {
+	return timeutil.MustLoadTimezone(deps.Config.Timezone)
+}
+
+// configuredNow returns the current time in the configured timezone
+func configuredNow() time.Time {
+	return time.Now().In(configuredLocation())
+}
+
+// digestRange returns the period covered by a digest (--text or --format):
+// this week by default, or the one selected by --prev-week, --last or --from/--to.
+// flagName is the flag that selected the digest, used in error messages.
+func digestRange(cmd *cobra.Command, flagName string) (time.Time, time.Time, bool) {
+	fromStr, _ := cmd.Flags().GetString("from")
+	toStr, _ := cmd.Flags().GetString("to")
+	lastDays, _ := cmd.Flags().GetInt("last")
+	thisWeek, _ := cmd.Flags().GetBool("this-week")
+	prevWeek, _ := cmd.Flags().GetBool("prev-week")
+
+	if lastDays > 0 && (fromStr != "" || toStr != "") {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Cannot use --last with --from or --to")
+		_, _ = fmt.Fprintln(deps.Stderr, "Use either --last N or --from/--to, not both")
+		deps.Exit(1)
+		return time.Time{}, time.Time{}, false
+	}
+	if (thisWeek || prevWeek) && (thisWeek == prevWeek || lastDays > 0 || fromStr != "" || toStr != "") {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Time period flags are mutually exclusive")
+		_, _ = fmt.Fprintln(deps.Stderr, "Use only one of: --this-week, --prev-week, --last, --from/--to")
+		deps.Exit(1)
+		return time.Time{}, time.Time{}, false
+	}
+
+	// Default to the current week
+	now := configuredNow()
+	if prevWeek {
+		now = now.AddDate(0, 0, -7)
+	}
+	startDate := timeutil.StartOfWeekWithConfig(now, deps.Config.WeekStartDay)
+	endDate := timeutil.EndOfWeekWithConfig(now, deps.Config.WeekStartDay)
+	if lastDays > 0 {
+		endDate = timeutil.EndOfDay(now)
+		startDate = timeutil.StartOfDay(now.AddDate(0, 0, -(lastDays - 1)))
+	} else if fromStr != "" || toStr != "" {
+		if fromStr == "" || toStr == "" {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: %s needs both --from and --to\n", flagName)
+			_, _ = fmt.Fprintf(deps.Stderr, "Example: did report %s --from 2024-01-01 --to 2024-01-31\n", flagName)
+			deps.Exit(1)
+			return time.Time{}, time.Time{}, false
+		}
+		from, err := parseDateFlag(fromStr)
+		if err != nil {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --from date: %v\n", err)
+			deps.Exit(1)
+			return time.Time{}, time.Time{}, false
+		}
+		to, err := parseDateFlag(toStr)
+		if err != nil {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --to date: %v\n", err)
+			deps.Exit(1)
+			return time.Time{}, time.Time{}, false
+		}
+		startDate, endDate = from, timeutil.EndOfDay(to)
+	}
+
+	return startDate, endDate, true
+}
+
+// digestEntries reads the active entries between start and end that match the
+// filters, sorted by timestamp and split at midnight if asked for (see splitDays)
+func digestEntries(cmd *cobra.Command, start, end time.Time, projectFilter string, tagFilters []string) ([]entry.Entry, bool) {
+	// Get storage path
+	storagePath, err := deps.StoragePath()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return nil, false
+	}
+
+	// Read all entries from storage
+	result, err := storage.ReadEntriesWithWarnings(storagePath)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", storagePath)
+		deps.Exit(1)
+		return nil, false
+	}
+
+	// Display warnings about corrupted lines to stderr
+	printCorruptionWarnings(result.Warnings)
+
+	// Keep active entries in the range that match the filters
+	f := filter.NewFilter("", projectFilter, tagFilters)
+	f.Client = reportClient(cmd)
+	printSuspectWarnings(countSuspect(result.Entries, func(e entry.Entry) bool {
+		return f.Matches(e) && timeutil.IsInRange(e.Timestamp, start, end)
+	}))
+	var entries []entry.Entry
+	for _, e := range splitDays(cmd, result.Entries) {
+		if e.DeletedAt == nil && e.HasValidDuration() && timeutil.IsInRange(e.Timestamp, start, end) && f.Matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	return entries, true
+}
+
+// printDigestDays prints a section per day with a bullet per entry.
+// Returns the total minutes of all entries.
+func printDigestDays(cmd *cobra.Command, entries []entry.Entry) int {
+	sourceOf := sourceColumn(cmd, entries)
+	totalMinutes := 0
+	for i := 0; i < len(entries); {
+		day := entries[i].Timestamp.Format("2006-01-02")
+		j, dayMinutes := i, 0
+		for ; j < len(entries) && entries[j].Timestamp.Format("2006-01-02") == day; j++ {
+			dayMinutes += entries[j].DurationMinutes
+		}
+
+		_, _ = fmt.Fprintln(deps.Stdout)
+		_, _ = fmt.Fprintf(deps.Stdout, "%s (%s)\n", entries[i].Timestamp.Format("Monday, Jan 2"), formatDuration(dayMinutes))
+		for _, e := range entries[i:j] {
+			_, _ = fmt.Fprintf(deps.Stdout, "  - %s%s (%s)\n", sourceOf(e), formatEntryForLog(e.Description, displayProject(e), e.Tags), formatDuration(e.DurationMinutes))
+		}
+
+		totalMinutes += dayMinutes
+		i = j
+	}
+	return totalMinutes
+}
+
+// runTextReport prints a plain-text digest of the selected period: a header with
+// the date range, a bullet list of entries per day, totals per project and a
+// grand total. The format is fixed so it can be piped into an email.
+func runTextReport(cmd *cobra.Command, flagName, projectFilter string, tagFilters []string) {
+	startDate, endDate, ok := digestRange(cmd, flagName)
+	if !ok {
+		return
+	}
+	entries, ok := digestEntries(cmd, startDate, endDate, projectFilter, tagFilters)
+	if !ok {
+		return
+	}
+
+	filters := query.Criteria{Project: projectFilter, Client: reportClient(cmd), Tags: tagFilters}
+	header := filters.Describe("Time report: " + query.FormatDateRange(startDate, endDate))
+	_, _ = fmt.Fprintln(deps.Stdout, header)
+	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", len(header)))
+
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout)
+		_, _ = fmt.Fprintln(deps.Stdout, "No entries logged.")
+		return
+	}
+
+	// Entries per day
+	totalMinutes := printDigestDays(cmd, entries)
+
+	// Totals per project
+	_, _ = fmt.Fprintln(deps.Stdout)
+	_, _ = fmt.Fprintln(deps.Stdout, "Projects")
+	for _, p := range sortedSummaries(projectSummaries(stats.CalculateProjectBreakdown(entries, startDate, endDate), true)) {
+		name := "(no project)"
+		if p.Name != "" {
+			name = "@" + entry.QuoteName(p.Name)
+		}
+		_, _ = fmt.Fprintf(deps.Stdout, "  - %s: %s\n", name, formatDuration(p.TotalMinutes))
+	}
+
+	_, _ = fmt.Fprintln(deps.Stdout)
+	_, _ = fmt.Fprintf(deps.Stdout, "Total: %s (%d %s)\n", formatDuration(totalMinutes), len(entries), pluralize("entry", "entries", len(entries)))
+}
+
+// emailTemplate holds every sentence of the email digest, so the wording can
+// be changed (or translated) in one place
+var emailTemplate = struct {
+	Greeting   string // date range
+	NoEntries  string
+	Summary    string // total duration, entry count, "entry"/"entries"
+	BusiestDay string // day, duration
+	TopProject string // project, duration
+	NoProject  string
+	TopTags    string // comma-separated "#tag (duration)" list
+	SignOff    string // did version
+}{
+	Greeting:   "Hi,\n\nhere is my time report for %s.",
+	NoEntries:  "No time was logged in this period.",
+	Summary:    "I logged %s in %d %s.",
+	BusiestDay: "The busiest day was %s with %s.",
+	TopProject: "Most of the time went to %s (%s).",
+	NoProject:  "entries without a project",
+	TopTags:    "Top tags: %s.",
+	SignOff:    "-- \nSent with did %s",
+}
+
+// emailTopTagCount is the number of tags listed in the email digest summary
+const emailTopTagCount = 3
+
+// runEmailReport prints a ready-to-send email body for the selected period:
+// a greeting with the date range, a short stats paragraph, the entries per day
+// and a sign-off. Like --text, the output is plain text without colors.
+func runEmailReport(cmd *cobra.Command, flagName, projectFilter string, tagFilters []string) {
+	startDate, endDate, ok := digestRange(cmd, flagName)
+	if !ok {
+		return
+	}
+	entries, ok := digestEntries(cmd, startDate, endDate, projectFilter, tagFilters)
+	if !ok {
+		return
+	}
+
+	filters := query.Criteria{Project: projectFilter, Client: reportClient(cmd), Tags: tagFilters}
+	period := filters.Describe(query.FormatDateRange(startDate, endDate))
+	_, _ = fmt.Fprintf(deps.Stdout, emailTemplate.Greeting+"\n", period)
+	_, _ = fmt.Fprintln(deps.Stdout)
+
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, emailTemplate.NoEntries)
+	} else {
+		_, _ = fmt.Fprintln(deps.Stdout, emailSummary(entries, startDate, endDate))
+		printDigestDays(cmd, entries)
+	}
+
+	_, _ = fmt.Fprintln(deps.Stdout)
+	_, _ = fmt.Fprintf(deps.Stdout, emailTemplate.SignOff+"\n", buildInfo.Version)
+}
+
+// emailSummary returns the stats paragraph of the email digest: total time,
+// busiest day, top project and top tags
+func emailSummary(entries []entry.Entry, start, end time.Time) string {
+	totalMinutes := 0
+	dayMinutes := make(map[string]int)
+	busiestDay := ""
+	for _, e := range entries {
+		totalMinutes += e.DurationMinutes
+		day := e.Timestamp.Format("2006-01-02")
+		dayMinutes[day] += e.DurationMinutes
+		if busiestDay == "" || dayMinutes[day] > dayMinutes[busiestDay] {
+			busiestDay = day
+		}
+	}
+	busiest, _ := time.ParseInLocation("2006-01-02", busiestDay, entries[0].Timestamp.Location())
+
+	sentences := []string{
+		fmt.Sprintf(emailTemplate.Summary, formatDuration(totalMinutes), len(entries), pluralize("entry", "entries", len(entries))),
+		fmt.Sprintf(emailTemplate.BusiestDay, busiest.Format("Monday, Jan 2"), formatDuration(dayMinutes[busiestDay])),
+	}
+
+	projects := sortedSummaries(projectSummaries(stats.CalculateProjectBreakdown(entries, start, end), true))
+	topProject := emailTemplate.NoProject
+	if projects[0].Name != "" {
+		topProject = "@" + entry.QuoteName(projects[0].Name)
+	}
+	sentences = append(sentences, fmt.Sprintf(emailTemplate.TopProject, topProject, formatDuration(projects[0].TotalMinutes)))
+
+	tags := sortedSummaries(tagSummaries(stats.CalculateTagBreakdown(entries, start, end), false))
+	if len(tags) > emailTopTagCount {
+		tags = tags[:emailTopTagCount]
+	}
+	if len(tags) > 0 {
+		var parts []string
+		for _, t := range tags {
+			parts = append(parts, fmt.Sprintf("#%s (%s)", entry.QuoteName(t.Name), formatDuration(t.TotalMinutes)))
+		}
+		sentences = append(sentences, fmt.Sprintf(emailTemplate.TopTags, strings.Join(parts, ", ")))
+	}
+
+	return strings.Join(sentences, " ")
 }
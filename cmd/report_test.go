@@ -9,8 +9,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/spf13/cobra"
 	"github.com/xolan/did/internal/entry"
 	"github.com/xolan/did/internal/storage"
+	"github.com/xolan/did/internal/timeutil"
 )
 
 // Helper function to create test entries for report testing
@@ -130,8 +132,8 @@ func TestReport_SingleProject_Basic(t *testing.T) {
 	if !strings.Contains(output, "Total: 5h") {
 		t.Errorf("Expected 'Total: 5h', got: %s", output)
 	}
-	if !strings.Contains(output, "3 entrys") {
-		t.Errorf("Expected '3 entrys', got: %s", output)
+	if !strings.Contains(output, "3 entries") {
+		t.Errorf("Expected '3 entries', got: %s", output)
 	}
 
 	resetFilterFlags(reportCmd)
@@ -215,6 +217,41 @@ func TestReport_SingleProject_NoResults(t *testing.T) {
 	resetFilterFlags(reportCmd)
 }
 
+func TestReport_MultiWordNames(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	e := entry.Entry{Timestamp: time.Now().Add(-time.Hour), Description: "planning", DurationMinutes: 60, Project: "Acme North America", Tags: []string{"big tag"}}
+	if err := storage.AppendEntry(storagePath, e); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		flag     string
+		value    string
+		expected string
+	}{
+		{"project", "project", "Acme North America", `Report for project '@"Acme North America"'`},
+		{"tag", "tag", "big tag", `Report for tag '#"big tag"'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, _ := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			resetFilterFlags(reportCmd)
+			defer resetFilterFlags(reportCmd)
+			_ = reportCmd.Root().PersistentFlags().Set(tt.flag, tt.value)
+
+			runReport(reportCmd, []string{})
+
+			if !strings.Contains(stdout.String(), tt.expected) {
+				t.Errorf("Expected %q, got: %s", tt.expected, stdout.String())
+			}
+		})
+	}
+}
+
 // Tests for single tag report (did report #tag)
 
 func TestReport_SingleTag_Basic(t *testing.T) {
@@ -256,8 +293,8 @@ func TestReport_SingleTag_Basic(t *testing.T) {
 	if !strings.Contains(output, "Total: 3h") {
 		t.Errorf("Expected 'Total: 3h', got: %s", output)
 	}
-	if !strings.Contains(output, "2 entrys") {
-		t.Errorf("Expected '2 entrys', got: %s", output)
+	if !strings.Contains(output, "2 entries") {
+		t.Errorf("Expected '2 entries', got: %s", output)
 	}
 
 	resetFilterFlags(reportCmd)
@@ -431,14 +468,71 @@ func TestReport_GroupByProject_Basic(t *testing.T) {
 	if !strings.Contains(output, "Grand Total: 9h 30m") {
 		t.Errorf("Expected 'Grand Total: 9h 30m', got: %s", output)
 	}
-	if !strings.Contains(output, "7 entrys") {
-		t.Errorf("Expected '7 entrys' in grand total, got: %s", output)
+	if !strings.Contains(output, "7 entries") {
+		t.Errorf("Expected '7 entries' in grand total, got: %s", output)
 	}
 	if !strings.Contains(output, "3 projects") {
 		t.Errorf("Expected '3 projects' in grand total, got: %s", output)
 	}
 }
 
+func TestReport_ClientFilter(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	now := time.Now()
+	for _, e := range []entry.Entry{
+		{Timestamp: now.AddDate(0, 0, -2), Description: "api work", DurationMinutes: 90, Project: "backend", Client: "AcmeCorp"},
+		{Timestamp: now.AddDate(0, 0, -1), Description: "audit", DurationMinutes: 60, Project: "backend", Client: "Globex"},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		setup   func()
+		cleanup func()
+	}{
+		{
+			name:    "single project",
+			setup:   func() { _ = rootCmd.PersistentFlags().Set("project", "backend") },
+			cleanup: func() { _ = rootCmd.PersistentFlags().Set("project", "") },
+		},
+		{
+			name:    "grouped by project",
+			setup:   func() { _ = reportCmd.Flags().Set("by", "project") },
+			cleanup: func() { _ = reportCmd.Flags().Set("by", "") },
+		},
+		{
+			name:    "text digest",
+			setup:   func() { _ = reportCmd.Flags().Set("text", "true"); _ = reportCmd.Flags().Set("last", "7") },
+			cleanup: func() { _ = reportCmd.Flags().Set("text", "false"); _ = reportCmd.Flags().Set("last", "0") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			_ = rootCmd.PersistentFlags().Set("client", "AcmeCorp")
+			defer func() { _ = rootCmd.PersistentFlags().Set("client", "") }()
+			tt.setup()
+			defer tt.cleanup()
+
+			runReport(reportCmd, []string{})
+
+			output := stdout.String()
+			if !strings.Contains(output, "api work") && !strings.Contains(output, "1h 30m") {
+				t.Errorf("Expected the entry of client AcmeCorp, got: %s%s", output, stderr.String())
+			}
+			if strings.Contains(output, "audit") || strings.Contains(output, "2h 30m") {
+				t.Errorf("Expected the entry of client Globex to be left out, got: %s", output)
+			}
+		})
+	}
+}
+
 func TestReport_GroupByProject_Sorted(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -613,8 +707,8 @@ func TestReport_GroupByTag_Basic(t *testing.T) {
 	if !strings.Contains(output, "Grand Total:") {
 		t.Error("Expected grand total")
 	}
-	if !strings.Contains(output, "7 entrys") {
-		t.Errorf("Expected '7 entrys' in grand total, got: %s", output)
+	if !strings.Contains(output, "7 entries") {
+		t.Errorf("Expected '7 entries' in grand total, got: %s", output)
 	}
 }
 
@@ -649,7 +743,7 @@ func TestReport_GroupByTag_MultipleTagsPerEntry(t *testing.T) {
 	// but grand total should show unique entry count
 
 	// Check that grand total shows unique entries (7), not sum of group counts
-	if !strings.Contains(output, "7 entrys") {
+	if !strings.Contains(output, "7 entries") {
 		t.Errorf("Expected grand total to show 7 unique entries, got: %s", output)
 	}
 }
@@ -988,8 +1082,7 @@ func TestReport_StoragePathError(t *testing.T) {
 }
 
 func TestReport_ReadEntriesError(t *testing.T) {
-	// Use a path to a directory (not a file) to cause read error
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
 	exitCalled := false
 	stderr := &bytes.Buffer{}
@@ -999,7 +1092,7 @@ func TestReport_ReadEntriesError(t *testing.T) {
 		Stdin:  strings.NewReader(""),
 		Exit:   func(code int) { exitCalled = true },
 		StoragePath: func() (string, error) {
-			return tmpDir, nil // path to directory, not file
+			return storagePath, nil
 		},
 	}
 	SetDeps(d)
@@ -1111,7 +1204,7 @@ func TestReport_GroupedOutputFormatting(t *testing.T) {
 		t.Error("Expected @acme in grouped output")
 	}
 	// Should show entry counts for each group
-	if !strings.Contains(output, "entrys") {
+	if !strings.Contains(output, "entries") {
 		t.Error("Expected entry count in grouped output")
 	}
 }
@@ -1314,7 +1407,7 @@ func TestReport_SingleTag_StoragePathError(t *testing.T) {
 }
 
 func TestReport_SingleTag_ReadEntriesError(t *testing.T) {
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
 	exitCalled := false
 	stderr := &bytes.Buffer{}
@@ -1324,7 +1417,7 @@ func TestReport_SingleTag_ReadEntriesError(t *testing.T) {
 		Stdin:  strings.NewReader(""),
 		Exit:   func(code int) { exitCalled = true },
 		StoragePath: func() (string, error) {
-			return tmpDir, nil // path to directory, not file
+			return storagePath, nil
 		},
 	}
 	SetDeps(d)
@@ -1494,7 +1587,7 @@ func TestReport_GroupByProject_StoragePathError(t *testing.T) {
 }
 
 func TestReport_GroupByProject_ReadEntriesError(t *testing.T) {
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
 	exitCalled := false
 	stderr := &bytes.Buffer{}
@@ -1504,7 +1597,7 @@ func TestReport_GroupByProject_ReadEntriesError(t *testing.T) {
 		Stdin:  strings.NewReader(""),
 		Exit:   func(code int) { exitCalled = true },
 		StoragePath: func() (string, error) {
-			return tmpDir, nil // path to directory, not file
+			return storagePath, nil
 		},
 	}
 	SetDeps(d)
@@ -1669,7 +1762,7 @@ func TestReport_GroupByTag_StoragePathError(t *testing.T) {
 }
 
 func TestReport_GroupByTag_ReadEntriesError(t *testing.T) {
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
 	exitCalled := false
 	stderr := &bytes.Buffer{}
@@ -1679,7 +1772,7 @@ func TestReport_GroupByTag_ReadEntriesError(t *testing.T) {
 		Stdin:  strings.NewReader(""),
 		Exit:   func(code int) { exitCalled = true },
 		StoragePath: func() (string, error) {
-			return tmpDir, nil // path to directory, not file
+			return storagePath, nil
 		},
 	}
 	SetDeps(d)
@@ -2306,3 +2399,516 @@ func TestReport_GroupByTag_CorruptedStorageWarnings(t *testing.T) {
 	_ = reportCmd.Flags().Set("by", "")
 	resetFilterFlags(reportCmd)
 }
+
+func TestReport_RoundKeepsItemsSummingToTotal(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	now := time.Now()
+	for i, minutes := range []int{8, 8, 8} {
+		e := entry.Entry{
+			Timestamp:       now.Add(time.Duration(i) * time.Minute),
+			Description:     fmt.Sprintf("task %d", i+1),
+			DurationMinutes: minutes,
+			RawInput:        fmt.Sprintf("task %d @acme for %dm", i+1, minutes),
+			Project:         "acme",
+		}
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	resetFilterFlags(reportCmd)
+	defer resetFilterFlags(reportCmd)
+	_ = reportCmd.Root().PersistentFlags().Set("project", "acme")
+	_ = reportCmd.Flags().Set("round", "15")
+	defer func() { _ = reportCmd.Flags().Set("round", "0") }()
+
+	runReport(reportCmd, []string{})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	output := stdout.String()
+	// 24 minutes rounds to 30; rounding each entry on its own would give 45
+	if !strings.Contains(output, "Total: 30m (3 entries, rounded to 15m)") {
+		t.Errorf("Expected rounded total, got: %s", output)
+	}
+	if strings.Count(output, "(15m)") != 2 || strings.Count(output, "(0m)") != 1 {
+		t.Errorf("Expected entries rounded to 15m, 15m and 0m, got: %s", output)
+	}
+}
+
+func TestReport_RoundGroupByProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	now := time.Now()
+	for i, p := range []struct {
+		project string
+		minutes int
+	}{{"acme", 50}, {"client", 50}, {"internal", 50}} {
+		e := entry.Entry{
+			Timestamp:       now.Add(time.Duration(i) * time.Minute),
+			Description:     "work",
+			DurationMinutes: p.minutes,
+			RawInput:        fmt.Sprintf("work @%s for %dm", p.project, p.minutes),
+			Project:         p.project,
+		}
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	resetFilterFlags(reportCmd)
+	_ = reportCmd.Flags().Set("by", "project")
+	_ = reportCmd.Flags().Set("round", "60")
+	defer func() {
+		_ = reportCmd.Flags().Set("by", "")
+		_ = reportCmd.Flags().Set("round", "0")
+	}()
+
+	runReport(reportCmd, []string{})
+
+	output := stdout.String()
+	// 150 minutes rounds to 3h; each 50m project rounds to 1h
+	if !strings.Contains(output, "Grand Total: 3h (3 entries across 3 projects, rounded to 1h)") {
+		t.Errorf("Expected rounded grand total, got: %s", output)
+	}
+	if strings.Count(output, " 1h  (1 entry)") != 3 {
+		t.Errorf("Expected each project rounded to 1h, got: %s", output)
+	}
+}
+
+func TestReport_RoundInvalid(t *testing.T) {
+	d, _, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+
+	resetFilterFlags(reportCmd)
+	_ = reportCmd.Flags().Set("round", "-5")
+	defer func() { _ = reportCmd.Flags().Set("round", "0") }()
+
+	runReport(reportCmd, []string{"@acme"})
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "Invalid --round value -5") {
+		t.Errorf("Expected invalid round error, got: %s", stderr.String())
+	}
+}
+
+// Tests for the plain-text digest (did report --text)
+
+func resetTextReportFlags() {
+	_ = reportCmd.Flags().Set("text", "false")
+	_ = reportCmd.Flags().Set("format", "")
+	_ = reportCmd.Flags().Set("this-week", "false")
+	_ = reportCmd.Flags().Set("prev-week", "false")
+	_ = reportCmd.Flags().Set("by", "")
+	_ = reportCmd.Flags().Set("round", "0")
+	_ = reportCmd.Flags().Set("from", "")
+	_ = reportCmd.Flags().Set("to", "")
+	_ = reportCmd.Flags().Set("last", "0")
+	_ = reportCmd.Flags().Set("show-source", "false")
+	resetFilterFlags(reportCmd)
+}
+
+func createTextReportEntries(t *testing.T) string {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	entries := []entry.Entry{
+		{Timestamp: time.Date(2024, 1, 16, 9, 0, 0, 0, time.Local), Description: "standup", DurationMinutes: 15},
+		{Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local), Description: "fix login", DurationMinutes: 60, Project: "acme", Tags: []string{"bugfix"}},
+		{Timestamp: time.Date(2024, 1, 15, 14, 0, 0, 0, time.Local), Description: "api work", DurationMinutes: 90, Project: "client"},
+		{Timestamp: time.Date(2024, 1, 16, 11, 0, 0, 0, time.Local), Description: "deploy", DurationMinutes: 45, Project: "acme"},
+		{Timestamp: time.Date(2024, 1, 25, 11, 0, 0, 0, time.Local), Description: "out of range", DurationMinutes: 30},
+	}
+	for _, e := range entries {
+		e.RawInput = formatRawInput(e)
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	return storagePath
+}
+
+func TestReport_Text(t *testing.T) {
+	d, stdout, stderr := testDeps(createTextReportEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	resetTextReportFlags()
+	defer resetTextReportFlags()
+	_ = reportCmd.Flags().Set("text", "true")
+	_ = reportCmd.Flags().Set("from", "2024-01-15")
+	_ = reportCmd.Flags().Set("to", "2024-01-21")
+
+	runReport(reportCmd, []string{})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	expected := `Time report: Mon Jan 15 - Sun Jan 21, 2024
+==========================================
+
+Monday, Jan 15 (2h 30m)
+  - fix login [@acme #bugfix] (1h)
+  - api work [@client] (1h 30m)
+
+Tuesday, Jan 16 (1h)
+  - standup (15m)
+  - deploy [@acme] (45m)
+
+Projects
+  - @acme: 1h 45m
+  - @client: 1h 30m
+  - (no project): 15m
+
+Total: 3h 30m (4 ` + pluralize("entry", "entries", 4) + `)
+`
+	if stdout.String() != expected {
+		t.Errorf("Unexpected digest:\n%s\nwant:\n%s", stdout.String(), expected)
+	}
+	if strings.Contains(stdout.String(), "\x1b[") {
+		t.Error("Digest should not contain ANSI escape codes")
+	}
+}
+
+func TestReport_TextWithFilter(t *testing.T) {
+	d, stdout, _ := testDeps(createTextReportEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	resetTextReportFlags()
+	defer resetTextReportFlags()
+	_ = reportCmd.Flags().Set("text", "true")
+	_ = reportCmd.Flags().Set("from", "2024-01-15")
+	_ = reportCmd.Flags().Set("to", "2024-01-21")
+
+	runReport(reportCmd, parseShorthandFilters(reportCmd, []string{"@acme"}))
+
+	output := stdout.String()
+	if !strings.HasPrefix(output, "Time report: Mon Jan 15 - Sun Jan 21, 2024 (@acme)\n") {
+		t.Errorf("Expected filters in the header, got:\n%s", output)
+	}
+	if strings.Contains(output, "api work") || strings.Contains(output, "standup") {
+		t.Errorf("Expected only @acme entries, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Total: 1h 45m") {
+		t.Errorf("Expected @acme total, got:\n%s", output)
+	}
+}
+
+// setSplitDaysFlag sets --split-days on cmd and resets it when the test finishes
+func setSplitDaysFlag(t *testing.T, cmd *cobra.Command, value string) {
+	t.Helper()
+	_ = cmd.Flags().Set("split-days", value)
+	t.Cleanup(func() {
+		_ = cmd.Flags().Set("split-days", "false")
+		cmd.Flags().Lookup("split-days").Changed = false
+	})
+}
+
+// createMidnightEntries stores an entry running from 22:00 on Jan 15 to 03:00 on Jan 16
+func createMidnightEntries(t *testing.T) string {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	entries := []entry.Entry{
+		{Timestamp: time.Date(2024, 1, 15, 22, 0, 0, 0, time.Local), Description: "incident response", DurationMinutes: 300, Project: "ops"},
+		{Timestamp: time.Date(2024, 1, 16, 9, 0, 0, 0, time.Local), Description: "standup", DurationMinutes: 15},
+	}
+	for _, e := range entries {
+		e.RawInput = formatRawInput(e)
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	return storagePath
+}
+
+func TestReport_TextSplitDays(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    bool
+		flag      string
+		wantSplit bool
+	}{
+		{"default", false, "", false},
+		{"flag", false, "true", true},
+		{"config", true, "", true},
+		{"flag overrides config", true, "false", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, stderr := testDeps(createMidnightEntries(t))
+			d.Config.SplitAtMidnight = tt.config
+			SetDeps(d)
+			defer ResetDeps()
+			resetTextReportFlags()
+			defer resetTextReportFlags()
+			_ = reportCmd.Flags().Set("text", "true")
+			_ = reportCmd.Flags().Set("from", "2024-01-15")
+			_ = reportCmd.Flags().Set("to", "2024-01-21")
+			if tt.flag != "" {
+				setSplitDaysFlag(t, reportCmd, tt.flag)
+			}
+
+			runReport(reportCmd, []string{})
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr output: %s", stderr.String())
+			}
+			output := stdout.String()
+			wantDays := []string{"Monday, Jan 15 (5h)", "Tuesday, Jan 16 (15m)"}
+			if tt.wantSplit {
+				wantDays = []string{"Monday, Jan 15 (2h)", "Tuesday, Jan 16 (3h 15m)", "  - incident response [@ops] (3h)"}
+			}
+			for _, want := range append(wantDays, "Total: 5h 15m") {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
+
+func TestReport_GroupByProjectSplitDays(t *testing.T) {
+	d, stdout, _ := testDeps(createMidnightEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	resetTextReportFlags()
+	defer resetTextReportFlags()
+	_ = reportCmd.Flags().Set("by", "project")
+	_ = reportCmd.Flags().Set("from", "2024-01-16")
+	_ = reportCmd.Flags().Set("to", "2024-01-16")
+	setSplitDaysFlag(t, reportCmd, "true")
+
+	runReport(reportCmd, []string{})
+
+	// Only the part after midnight falls on Jan 16
+	if !strings.Contains(stdout.String(), "@ops") || !strings.Contains(stdout.String(), "3h") {
+		t.Errorf("Expected 3h for @ops on Jan 16, got:\n%s", stdout.String())
+	}
+	if strings.Contains(stdout.String(), "5h") {
+		t.Errorf("Expected the part before midnight to be excluded, got:\n%s", stdout.String())
+	}
+}
+
+func TestReport_TextNoEntries(t *testing.T) {
+	d, stdout, _ := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	SetDeps(d)
+	defer ResetDeps()
+	resetTextReportFlags()
+	defer resetTextReportFlags()
+	_ = reportCmd.Flags().Set("text", "true")
+
+	runReport(reportCmd, []string{})
+
+	output := stdout.String()
+	if !strings.HasPrefix(output, "Time report: ") || !strings.Contains(output, "No entries logged.") {
+		t.Errorf("Expected an empty digest for this week, got:\n%s", output)
+	}
+}
+
+func TestReport_TextInvalidFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		wantErr string
+	}{
+		{"with --by", map[string]string{"by": "project"}, "Cannot use --text with --by or --round"},
+		{"with --round", map[string]string{"round": "15"}, "Cannot use --text with --by or --round"},
+		{"last and from", map[string]string{"last": "7", "from": "2024-01-01"}, "Cannot use --last with --from or --to"},
+		{"from without to", map[string]string{"from": "2024-01-01"}, "--text needs both --from and --to"},
+		{"this-week and prev-week", map[string]string{"this-week": "true", "prev-week": "true"}, "Time period flags are mutually exclusive"},
+		{"prev-week and last", map[string]string{"prev-week": "true", "last": "7"}, "Time period flags are mutually exclusive"},
+		{"with --format email", map[string]string{"format": "email"}, "Cannot use --text with --format email"},
+		{"invalid from", map[string]string{"from": "nope", "to": "2024-01-31"}, "Invalid --from date"},
+		{"invalid to", map[string]string{"from": "2024-01-01", "to": "nope"}, "Invalid --to date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode := 0
+			d, _, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			resetTextReportFlags()
+			defer resetTextReportFlags()
+			_ = reportCmd.Flags().Set("text", "true")
+			for name, value := range tt.flags {
+				_ = reportCmd.Flags().Set(name, value)
+			}
+
+			runReport(reportCmd, []string{})
+
+			if exitCode != 1 || !strings.Contains(stderr.String(), tt.wantErr) {
+				t.Errorf("Expected exit 1 with %q, got exit %d: %s", tt.wantErr, exitCode, stderr.String())
+			}
+		})
+	}
+}
+
+// Tests for the email digest (did report --format email)
+
+func TestReport_Email(t *testing.T) {
+	d, stdout, stderr := testDeps(createTextReportEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	resetTextReportFlags()
+	defer resetTextReportFlags()
+	_ = reportCmd.Flags().Set("format", "email")
+	_ = reportCmd.Flags().Set("from", "2024-01-15")
+	_ = reportCmd.Flags().Set("to", "2024-01-21")
+
+	runReport(reportCmd, []string{})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	expected := `Hi,
+
+here is my time report for Mon Jan 15 - Sun Jan 21, 2024.
+
+I logged 3h 30m in 4 ` + pluralize("entry", "entries", 4) + `. The busiest day was Monday, Jan 15 with 2h 30m. Most of the time went to @acme (1h 45m). Top tags: #bugfix (1h).
+
+Monday, Jan 15 (2h 30m)
+  - fix login [@acme #bugfix] (1h)
+  - api work [@client] (1h 30m)
+
+Tuesday, Jan 16 (1h)
+  - standup (15m)
+  - deploy [@acme] (45m)
+
+-- 
+Sent with did ` + buildInfo.Version + `
+`
+	if stdout.String() != expected {
+		t.Errorf("Unexpected email:\n%s\nwant:\n%s", stdout.String(), expected)
+	}
+	if strings.Contains(stdout.String(), "\x1b[") {
+		t.Error("Email should not contain ANSI escape codes")
+	}
+}
+
+func TestReport_EmailNoProjectOrTags(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	e := entry.Entry{Timestamp: time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local), Description: "reading", DurationMinutes: 30}
+	if err := storage.AppendEntry(storagePath, e); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	resetTextReportFlags()
+	defer resetTextReportFlags()
+	_ = reportCmd.Flags().Set("format", "email")
+	_ = reportCmd.Flags().Set("from", "2024-01-15")
+	_ = reportCmd.Flags().Set("to", "2024-01-15")
+
+	runReport(reportCmd, []string{})
+
+	output := stdout.String()
+	if !strings.Contains(output, "Most of the time went to entries without a project (30m).") {
+		t.Errorf("Expected the no-project wording, got:\n%s", output)
+	}
+	if strings.Contains(output, "Top tags") {
+		t.Errorf("Expected no top tags without tagged entries, got:\n%s", output)
+	}
+}
+
+func TestReport_EmailNoEntries(t *testing.T) {
+	d, stdout, _ := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	SetDeps(d)
+	defer ResetDeps()
+	resetTextReportFlags()
+	defer resetTextReportFlags()
+	_ = reportCmd.Flags().Set("format", "email")
+	_ = reportCmd.Flags().Set("prev-week", "true")
+
+	runReport(reportCmd, []string{})
+
+	now := time.Now()
+	lastWeek := timeutil.StartOfWeekWithConfig(now.AddDate(0, 0, -7), d.Config.WeekStartDay)
+	output := stdout.String()
+	if !strings.Contains(output, "here is my time report for "+lastWeek.Format("Mon Jan 2")) {
+		t.Errorf("Expected the previous week in the greeting, got:\n%s", output)
+	}
+	if !strings.Contains(output, "No time was logged in this period.") || !strings.Contains(output, "Sent with did ") {
+		t.Errorf("Expected an empty email with sign-off, got:\n%s", output)
+	}
+}
+
+func TestReport_FormatInvalidFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		wantErr string
+	}{
+		{"unknown format", map[string]string{"format": "html"}, "Invalid --format value 'html'"},
+		{"email with --by", map[string]string{"format": "email", "by": "project"}, "Cannot use --format with --by or --round"},
+		{"email from without to", map[string]string{"format": "email", "to": "2024-01-31"}, "--format needs both --from and --to"},
+		{"this-week without digest", map[string]string{"this-week": "true", "by": "project"}, "--this-week and --prev-week only apply to --text and --format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode := 0
+			d, _, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			resetTextReportFlags()
+			defer resetTextReportFlags()
+			for name, value := range tt.flags {
+				_ = reportCmd.Flags().Set(name, value)
+			}
+
+			runReport(reportCmd, []string{})
+
+			if exitCode != 1 || !strings.Contains(stderr.String(), tt.wantErr) {
+				t.Errorf("Expected exit 1 with %q, got exit %d: %s", tt.wantErr, exitCode, stderr.String())
+			}
+		})
+	}
+}
+
+func TestReport_ShowSource(t *testing.T) {
+	for _, flags := range [][]string{{"text"}, {"format", "email"}} {
+		t.Run(flags[0], func(t *testing.T) {
+			d, stdout, _ := testDeps(createSourceTestDir(t))
+			SetDeps(d)
+			defer ResetDeps()
+			resetTextReportFlags()
+			defer resetTextReportFlags()
+			if len(flags) == 1 {
+				_ = reportCmd.Flags().Set(flags[0], "true")
+			} else {
+				_ = reportCmd.Flags().Set(flags[0], flags[1])
+			}
+			_ = reportCmd.Flags().Set("last", "1")
+			_ = reportCmd.Flags().Set("show-source", "true")
+
+			runReport(reportCmd, []string{})
+
+			output := stdout.String()
+			for _, want := range []string{"  - alice  alice work (1h)", "  - bob    bob work (30m)"} {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
@@ -1,14 +1,24 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"sort"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/didlib"
 	"github.com/xolan/did/internal/entry"
 	"github.com/xolan/did/internal/filter"
+	"github.com/xolan/did/internal/query"
+	"github.com/xolan/did/internal/stats"
 	"github.com/xolan/did/internal/storage"
 	"github.com/xolan/did/internal/timeutil"
 )
@@ -24,22 +34,42 @@ Usage:
 
 Time Period Flags (mutually exclusive):
   -y, --yesterday                     List yesterday's entries
-  -w, --this-week                     List current week's entries
-      --prev-week                     List previous week's entries
-  -m, --this-month                    List current month's entries
-      --prev-month                    List previous month's entries
-  -l, --last <n>                      List entries from last N days
+  -w, --this-week                     List current week's entries (or --week)
+      --prev-week                     List previous week's entries (or --last-week)
+  -m, --this-month                    List current month's entries (or --month)
+      --prev-month                    List previous month's entries (or --last-month)
+  -l, --last <n>                      List entries from last N days (or 2w, 3m)
       --from <date> --to <date>       List entries in date range
   -d, --date <date>                   List entries for a specific date
 
 Filter Options:
   --project <name>                    Filter entries by project
   --tag <name>                        Filter entries by tag (can be repeated)
+  --client <name>                     Filter entries by client
   @project                            Shorthand for --project
   #tag                                Shorthand for --tag
 
+Output Options:
+  --subtotals                         Show per-project subtotals before the total
+  --subtotals-by <project|tag>        Group subtotals by project (default) or tag
+  --show-source                       Show the storage file each entry comes from
+  --no-index                          Hide the [index] column (--index shows it again)
+  --order <asc|desc>                  List oldest (default) or newest entries first
+  --round-display <n>                 Show durations rounded to the nearest N minutes
+                                      (storage stays exact)
+  --count-only                        Print only the number of matching entries
+  --minutes                           Print only the total minutes of matching entries
+  --explain                           Show why each entry matched the filters
+  --explain-all                       Also list the excluded entries and why
+  --verbose                           Show stored details under each entry, and always
+                                      show corrupted-line warnings (else once a day)
+  --pager, --no-pager                 Show long listings in $PAGER (default "less -R")
+                                      when output is a terminal, or never
+  --config <path>                     Use this config file (or set DID_CONFIG)
+
 Examples:
   did feature X for 2h                Log a new entry
+  did standup for 15m --at '2024-01-15 09:00'   Log an entry dated at 09:00
   did                                 List today's entries
   did -y                              List yesterday's entries
   did -w                              List this week's entries
@@ -47,24 +77,56 @@ Examples:
   did -m                              List this month's entries
   did --prev-month                    List last month's entries
   did -l 7                            List last 7 days
+  did -l 2w                           List last 2 weeks
   did --from 2024-01-01 --to 2024-01-31   List entries in date range
   did -d 2024-01-15                   List entries for specific date
+  did -d "last friday"                List entries for last Friday
   did -w @acme                        This week's entries for project 'acme'
   did -l 30 #bugfix                   Last 30 days tagged 'bugfix'
   did --prev-week @client #urgent     Last week's entries with filters
+  did -w --subtotals                  This week's entries with per-project subtotals
+  did -w --subtotals-by tag           This week's entries with per-tag subtotals
+
+Views:
+  did today [@project] [#tag]         List today's entries
+  did yesterday [@project] [#tag]     List yesterday's entries
+  did week [prev] [@project] [#tag]   List this (or the previous) week's entries
+  did month [prev] [@project] [#tag]  List this (or the previous) month's entries
+  did y | w | m | lw | pm [@project] [#tag]
+                                      Short for yesterday, this week, this month,
+                                      the previous week and the previous month
+  Other words without "for <duration>" are an entry missing its duration.
 
 Other Commands:
+  did log <description> [for <duration>]  Log an entry, with the default duration if none
   did edit <index> --description 'text'   Edit entry description
   did edit <index> --duration 2h          Edit entry duration
+  did merge <index> <index>...            Combine entries into one
+  did duplicate-check [--exact]           Find entries that look like duplicates
+  did split <index> --part 'x for 1h'...  Break an entry into several
+  did paste                               Log entries from the clipboard, one per line
+  did log --stdin-lines < file            Log an entry for every line of a file
   did delete <index>                      Delete an entry (with confirmation)
   did undo                                Restore the most recently deleted entry
   did purge                               Permanently remove all soft-deleted entries
-  did validate                            Check storage file health
+  did validate [--json|--compare <file>]  Check storage file health, or diff two files
+  did storage sort                        Rewrite the storage file in chronological order
+  did storage normalize                   Truncate stored timestamps to whole seconds or minutes
+  did migrate --normalize-timestamps      Store all timestamps in UTC
   did restore [n]                         Restore from backup (default: most recent)
   did search <keyword>                    Search entries by keyword
+  did recent [-n N]                       Show recently used descriptions
+  did since [--suggest]                   Show how long ago the last entry ended
   did export json|csv                     Export entries to JSON or CSV
+  did import csv|json < file              Import entries from CSV or JSON
   did report @project|#tag|--by <type>    Generate reports
-  did stats [--month]                     Show statistics
+  did stats [time-flag] [@project] [#tag] Show statistics
+  did projects|tags [--json]              List projects or tags with totals
+  did projects|tags --rename old=new      Rename a project or tag in every entry
+  did tag add|remove <tag> --filter text  Tag or untag matching entries
+  did deficit [--this-week|--this-month]  Compare logged time to working hours
+  did version [--json]                    Show version and build information
+  did init [--yes]                        Run the setup wizard
 
 Timer Mode:
   did start <description>             Start a timer for a task
@@ -73,25 +135,56 @@ Timer Mode:
 
 Duration format: Yh (hours), Ym (minutes), or YhYm (combined)
 Examples: 2h, 30m, 1h30m
+Durations over 24h (max_entry_duration) are rejected unless --allow-long is given.
 
-Date formats: YYYY-MM-DD or DD/MM/YYYY
-Examples: 2024-01-15 or 15/01/2024
+Date formats: YYYY-MM-DD, DD/MM/YYYY, or a relative date
+Examples: 2024-01-15, 15/01/2024, yesterday, monday, last friday, 3 days ago
 
 Projects and Tags:
   Optionally categorize entries with @project and #tags in descriptions.
   did fix login bug @acme for 1h      Assign entry to project 'acme'
   did code review #review for 30m     Add tag 'review' to entry
-  did API work @client #backend for 2h    Combine project with multiple tags`,
+  did API work @client #backend for 2h    Combine project with multiple tags
+  Without an @project, a directory in the config's [workspaces] table sets the
+  project and tags of entries logged inside it (skip with --no-workspace).
+  To keep # text in the description, as in "fixed issue #1234", escape it as
+  \# or pass --no-tags to log the description without any #tags.
+
+Reading the entry text from stdin:
+  With --stdin (or a lone '-'), a single line of stdin is the whole entry
+  text, so quotes and #tags need no shell quoting:
+  echo 'fixed "login" bug #urgent for 1h' | did --stdin
+  did - < entry.txt`,
 	Args: cobra.ArbitraryArgs,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		checkStorageWriterVersion()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// Check for --tui flag
 		if CheckTUIFlag(cmd) {
 			return
 		}
 
+		// The entry text comes from stdin with --stdin or a lone '-'
+		if stdin, _ := cmd.Flags().GetBool("stdin"); stdin || (len(args) == 1 && args[0] == "-") {
+			createEntryFromStdin(cmd, args)
+			return
+		}
+
 		// Parse shorthand filters (@project, #tag) and remove them from args
 		args = parseShorthandFilters(cmd, args)
 
+		// A bare period alias such as 'did y' lists like its time period flag
+		args, ok := applyPeriodArgAlias(cmd, args)
+		if !ok {
+			return
+		}
+
+		// Offer the setup wizard on the first interactive listing
+		if offersFirstRunWizard(cmd, args) {
+			maybeRunFirstRunWizard()
+		}
+
 		// Check for time period flags and handle listing
 		if handled := handleTimePeriodFlags(cmd, args); handled {
 			return
@@ -105,7 +198,7 @@ Projects and Tags:
 		}
 
 		// With args: create a new entry
-		createEntry(args)
+		createEntry(cmd, args)
 	},
 }
 
@@ -113,15 +206,35 @@ Projects and Tags:
 var editCmd = &cobra.Command{
 	Use:   "edit <index>",
 	Short: "Edit an existing entry",
-	Long: `Edit the description or duration of an existing time tracking entry.
+	Long: `Edit the description, duration, time, project, client or tags of an existing time tracking entry.
 
 Usage:
   did edit <index> --description 'new text'    Update entry description
   did edit <index> --duration 2h               Update entry duration
   did edit <index> --description 'text' --duration 2h    Update both
+  did edit <index> --project acme              Set the entry's project
+  did edit <index> --project ''                Clear the entry's project
+  did edit <index> --client AcmeCorp           Set the entry's client ('' clears it)
+  did edit <index> --append-tag review         Add a tag (can be repeated)
+  did edit <index> --remove-tag urgent         Remove a tag (can be repeated)
+  did edit <index> --duration 30h --allow-long Set a duration over 24h
+  did edit <index> --timestamp '2024-01-15 15:00'  Correct when the entry happened
 
 The index refers to the entry number shown in list output (starting from 1).
-At least one flag (--description or --duration) is required.`,
+At least one flag (--description, --duration, --timestamp, --project,
+--client, --append-tag or --remove-tag) is required.
+
+--timestamp accepts RFC3339 (2024-01-15T15:00:00Z) or YYYY-MM-DD HH:MM, which
+is interpreted in the configured timezone. Timestamps in the future are
+rejected unless --allow-future is given. Without --timestamp the entry keeps
+its original time.
+
+The raw input of the entry is rebuilt from the edited fields, with a new
+duration as typed. The confirmation shows every field that changed, e.g.
+"description: 'original' → 'updated'" and "duration: 1h → 2h".
+
+A description containing @project cannot be combined with --project, and a
+description containing #tags cannot be combined with --append-tag/--remove-tag.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		editEntry(cmd, args)
@@ -130,11 +243,63 @@ At least one flag (--description or --duration) is required.`,
 
 // validateCmd represents the validate command
 var validateCmd = &cobra.Command{
-	Use:   "validate",
-	Short: "Check storage file health",
-	Long:  `Validate the storage file and report on its health status, including any corrupted entries.`,
+	Use:     "validate",
+	Aliases: []string{"doctor"},
+	Short:   "Check storage file health",
+	Long: `Validate the storage file and report on its health status, including any corrupted entries.
+
+When --project or --tag filters are given (or @project/#tag shorthand), the
+report additionally breaks down the valid entries matching the filters: how
+many match, their date span, and their total duration. Corruption counts always
+cover the whole file, since corrupted lines have no parseable project or tags.
+
+Entries dated more than a day in the future, e.g. logged while the system
+clock was wrong, are listed with their index so they can be fixed with
+did edit <index> --timestamp.
+
+Entries that are not in chronological order in the file, e.g. after an
+import or a backdated entry, are counted, and each entry dated before the one
+on the previous line is listed with its line number; 'did storage sort'
+rewrites the file in order.
+
+Entries with a zero or negative duration, e.g. from a faulty import, are
+suspect: listings and totals leave them out. They are listed with their index
+and line number so they can be fixed with did edit <index> --duration or
+removed with did delete <index>.
+
+The checksum covers the active entries regardless of their order in the
+file, so two copies of the storage (e.g. a sync conflict) with the same
+checksum have the same entries. --compare lists the entries that are only in
+one of two storage files, matching entries by time, description and
+duration, and those that differ in other fields.
+
+The report also shows the timezone dates are resolved in, with its current
+UTC offset, e.g. "Timezone: Europe/Oslo (UTC+02:00)", so you can check that
+week and day boundaries are where you expect them.
+
+--json prints the valid and corrupted entry counts and the corrupted lines as
+JSON, for CI and monitoring. With --strict the command exits with status 1
+when the file has corrupted lines.
+
+Examples:
+  did validate                    Check storage file health
+  did validate --json --strict    Machine-readable health, failing on corruption
+  did validate --compare other.jsonl   Compare the entries with another file
+  did validate --project acme     Also summarize valid entries for project 'acme'
+  did validate @acme #review      Same, using shorthand syntax
+  did doctor                      Same as did validate`,
 	Run: func(cmd *cobra.Command, args []string) {
-		validateStorage()
+		_ = parseShorthandFilters(cmd, args)
+		if other, _ := cmd.Flags().GetString("compare"); other != "" {
+			if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+				_, _ = fmt.Fprintln(deps.Stderr, "Error: --json cannot be combined with --compare")
+				deps.Exit(1)
+				return
+			}
+			compareStorage(other)
+			return
+		}
+		validateStorage(cmd)
 	},
 }
 
@@ -151,234 +316,282 @@ func init() {
 
 	// Add persistent filter flags (apply to all commands)
 	rootCmd.PersistentFlags().String("project", "", "Filter entries by project")
+	rootCmd.PersistentFlags().String("client", "", "Filter entries by client; when logging, the client of the new entry")
 	rootCmd.PersistentFlags().StringSlice("tag", []string{}, "Filter entries by tag (can be repeated)")
+	rootCmd.PersistentFlags().BoolVar(&verboseFlag, "verbose", false, "Show stored entry details in listings and always show corrupted-line warnings")
+	// Read before the commands run, in ValidateConfigOnStartup
+	rootCmd.PersistentFlags().String("config", "", "Use this config file instead of the default one (overrides DID_CONFIG)")
 
 	// Add time period flags to root command
-	rootCmd.Flags().BoolP("yesterday", "y", false, "List yesterday's entries")
-	rootCmd.Flags().BoolP("this-week", "w", false, "List current week's entries")
-	rootCmd.Flags().Bool("prev-week", false, "List previous week's entries")
-	rootCmd.Flags().BoolP("this-month", "m", false, "List current month's entries")
-	rootCmd.Flags().Bool("prev-month", false, "List previous month's entries")
-	rootCmd.Flags().IntP("last", "l", 0, "List entries from last N days")
-	rootCmd.Flags().String("from", "", "Start date for date range (YYYY-MM-DD or DD/MM/YYYY)")
-	rootCmd.Flags().String("to", "", "End date for date range (YYYY-MM-DD or DD/MM/YYYY)")
-	rootCmd.Flags().StringP("date", "d", "", "List entries for a specific date (YYYY-MM-DD or DD/MM/YYYY)")
+	addTimePeriodFlags(rootCmd, "List")
+
+	// Allow entries longer than max_entry_duration when logging
+	rootCmd.Flags().Bool("allow-long", false, "Allow logging entries longer than max_entry_duration")
+	rootCmd.Flags().Bool("allow-future", false, "Allow logging an entry dated in the future (clock skew)")
+	rootCmd.Flags().String("at", "", "Date the entry at this time instead of now (RFC3339 or 'YYYY-MM-DD HH:MM')")
+	rootCmd.Flags().Bool("no-workspace", false, "Don't infer the project and tags from the working directory")
+	rootCmd.Flags().Bool("no-tags", false, "Keep #words in the description instead of parsing them as tags")
+	rootCmd.Flags().Bool("stdin", false, "Read the entry text from a line of stdin instead of the arguments (also 'did -')")
+
+	// Add subtotal flags for listings
+	rootCmd.Flags().Bool("subtotals", false, "Show per-project subtotals before the total")
+	rootCmd.Flags().String("subtotals-by", "", "Group subtotals by project or tag (implies --subtotals)")
+	addShowSourceFlag(rootCmd)
+	rootCmd.Flags().Bool("count-only", false, "Print only the number of matching entries (e.g. for a shell prompt)")
+	rootCmd.Flags().Bool("minutes", false, "Print only the total minutes of the matching entries (implies --count-only)")
+	rootCmd.Flags().Bool("explain", false, "Show why each listed entry matched the period and filters")
+	rootCmd.Flags().Bool("explain-all", false, "Like --explain, and also list the excluded entries with why they didn't match")
+	addIndexFlags(rootCmd)
+	addOrderFlag(rootCmd)
+	addRoundDisplayFlag(rootCmd)
+	addPagerFlags(rootCmd)
 
 	// Add flags to edit command
 	editCmd.Flags().String("description", "", "New description for the entry")
 	editCmd.Flags().String("duration", "", "New duration for the entry (e.g., 2h, 30m)")
+	editCmd.Flags().String("project", "", "Set the entry's project (empty string clears it)")
+	editCmd.Flags().String("client", "", "Set the entry's client (empty string clears it)")
+	editCmd.Flags().StringSlice("append-tag", []string{}, "Add a tag to the entry (can be repeated)")
+	editCmd.Flags().StringSlice("remove-tag", []string{}, "Remove a tag from the entry (can be repeated)")
+	editCmd.Flags().Bool("allow-long", false, "Allow durations longer than max_entry_duration")
+	editCmd.Flags().String("timestamp", "", "New time for the entry (RFC3339 or 'YYYY-MM-DD HH:MM')")
+	editCmd.Flags().Bool("allow-future", false, "Allow a --timestamp in the future")
+
+	validateCmd.Flags().String("compare", "", "Compare the entries with another storage file instead of checking health")
+	validateCmd.Flags().Bool("json", false, "Output the entry counts and corrupted lines as JSON")
+	validateCmd.Flags().Bool("strict", false, "Exit with status 1 when the storage has corrupted lines")
 }
 
-// handleTimePeriodFlags checks for time period flags and lists entries accordingly.
-// Returns true if a time period flag was handled, false otherwise.
-func handleTimePeriodFlags(cmd *cobra.Command, args []string) bool {
-	// Get all time period flag values
-	yesterday, _ := cmd.Flags().GetBool("yesterday")
-	thisWeek, _ := cmd.Flags().GetBool("this-week")
-	prevWeek, _ := cmd.Flags().GetBool("prev-week")
-	thisMonth, _ := cmd.Flags().GetBool("this-month")
-	prevMonth, _ := cmd.Flags().GetBool("prev-month")
-	lastDays, _ := cmd.Flags().GetInt("last")
-	fromStr, _ := cmd.Flags().GetString("from")
-	toStr, _ := cmd.Flags().GetString("to")
-	dateStr, _ := cmd.Flags().GetString("date")
-
-	// Count how many time period options are set
-	count := 0
-	if yesterday {
-		count++
-	}
-	if thisWeek {
-		count++
-	}
-	if prevWeek {
-		count++
-	}
-	if thisMonth {
-		count++
-	}
-	if prevMonth {
-		count++
-	}
-	if lastDays > 0 {
-		count++
-	}
-	if fromStr != "" || toStr != "" {
-		count++
+// addTimePeriodFlags registers the mutually exclusive time period flags on cmd.
+// action starts each flag's help text, e.g. "List" gives "List yesterday's entries".
+func addTimePeriodFlags(cmd *cobra.Command, action string) {
+	cmd.Flags().BoolP("yesterday", "y", false, action+" yesterday's entries")
+	cmd.Flags().BoolP("this-week", "w", false, action+" current week's entries")
+	cmd.Flags().Bool("prev-week", false, action+" previous week's entries")
+	cmd.Flags().BoolP("this-month", "m", false, action+" current month's entries")
+	cmd.Flags().Bool("prev-month", false, action+" previous month's entries")
+	cmd.Flags().StringP("last", "l", "", action+" entries from the last N days, or Nw weeks / Nm months (e.g. 7, 2w, 3m)")
+	cmd.Flags().String("from", "", "Start date for date range (YYYY-MM-DD or DD/MM/YYYY)")
+	cmd.Flags().String("to", "", "End date for date range (YYYY-MM-DD or DD/MM/YYYY)")
+	cmd.Flags().StringP("date", "d", "", action+" entries for a specific date (YYYY-MM-DD or DD/MM/YYYY)")
+	addPeriodFlagAliases(cmd)
+}
+
+// periodFlagAliases maps alternative names of the week and month flags to
+// the flags they stand for
+var periodFlagAliases = map[string]string{
+	"week":       "this-week",
+	"month":      "this-month",
+	"last-week":  "prev-week",
+	"last-month": "prev-month",
+}
+
+// addPeriodFlagAliases lets the week and month flags of cmd also be given by
+// the names in periodFlagAliases. The alias is normalized to the flag's name
+// when parsing, so an alias is the same flag: it never conflicts with the
+// flag it stands for, and help and completion only show the real flags, with
+// the alias noted in their usage.
+// Aliases whose name cmd already uses for a flag of its own are skipped. Call
+// it after defining the flags of cmd.
+func addPeriodFlagAliases(cmd *cobra.Command) {
+	aliases := make(map[string]string)
+	for alias, name := range periodFlagAliases {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || cmd.Flags().Lookup(alias) != nil {
+			continue
+		}
+		aliases[alias] = name
+		flag.Usage += " (alias: --" + alias + ")"
 	}
-	if dateStr != "" {
-		count++
+	cmd.Flags().SetNormalizeFunc(func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+		if flag, ok := aliases[name]; ok {
+			name = flag
+		}
+		return pflag.NormalizedName(name)
+	})
+}
+
+// resolveQuery resolves the time period and filter flags of cmd. Invalid flags
+// are reported to stderr; ok is false then.
+func resolveQuery(cmd *cobra.Command) (query.Criteria, bool) {
+	c, err := query.Resolve(cmd, deps.Config)
+	if err != nil {
+		printQueryError(err)
+		return query.Criteria{}, false
 	}
+	return c, true
+}
 
-	// Check for mutual exclusivity
-	if count > 1 {
+// printQueryError reports invalid time period flags with a hint on how to fix them
+func printQueryError(err error) {
+	var dateErr *query.DateError
+	var lastErr *query.LastError
+	switch {
+	case errors.Is(err, query.ErrConflictingPeriods):
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Time period flags are mutually exclusive")
 		_, _ = fmt.Fprintln(deps.Stderr, "Use only one of: --yesterday, --this-week, --prev-week, --this-month, --prev-month, --last, --from/--to, --date")
-		deps.Exit(1)
-		return true
-	}
-
-	// If no time period flags, return false to continue normal processing
-	if count == 0 {
-		// Check if this looks like shorthand filters only (no 'for' keyword)
-		// In this case, treat as listing command
-		if len(args) > 0 {
-			rawInput := strings.Join(args, " ")
-			if !strings.Contains(strings.ToLower(rawInput), " for ") {
-				// No 'for' keyword - likely shorthand filters for listing
-				listEntries(cmd, "today", timeutil.Today)
-				return true
-			}
+	case errors.Is(err, query.ErrLastWithRange):
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Cannot use --last with --from or --to")
+		_, _ = fmt.Fprintln(deps.Stderr, "Use either --last N or --from/--to, not both")
+	case errors.As(err, &lastErr):
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --last value: %q\n", lastErr.Value)
+		_, _ = fmt.Fprintln(deps.Stderr, "Use a number of days (7), weeks (2w) or calendar months (3m)")
+	case errors.As(err, &dateErr):
+		if dateErr.Flag == "date" {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --date value: %v\n", dateErr.Err)
+		} else {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --%s date: %v\n", dateErr.Flag, dateErr.Err)
 		}
-		return false
+		_, _ = fmt.Fprintln(deps.Stderr, "Use format YYYY-MM-DD, DD/MM/YYYY, or a relative date like 'yesterday' or '3 days ago'")
+	default:
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: %v\n", err)
 	}
+	deps.Exit(1)
+}
 
-	// Check if args contain entry creation (has 'for' keyword) - time flags shouldn't be used with entry creation
-	if len(args) > 0 {
-		rawInput := strings.Join(args, " ")
-		if strings.Contains(strings.ToLower(rawInput), " for ") {
-			_, _ = fmt.Fprintln(deps.Stderr, "Error: Time period flags cannot be used when creating entries")
-			_, _ = fmt.Fprintln(deps.Stderr, "To create an entry: did <description> for <duration>")
-			_, _ = fmt.Fprintln(deps.Stderr, "To list entries: did [time-flag] [@project] [#tag]")
-			deps.Exit(1)
-			return true
-		}
-	}
+// periodArgAliases maps the bare arguments listing a period, as in 'did y',
+// to the time period flags they stand for
+var periodArgAliases = map[string]string{
+	"y":  "yesterday",
+	"w":  "this-week",
+	"m":  "this-month",
+	"lw": "prev-week",
+	"pm": "prev-month",
+}
 
-	// Handle each time period flag
-	if yesterday {
-		listEntries(cmd, "yesterday", timeutil.Yesterday)
-		return true
+// applyPeriodArgAlias sets the time period flag of a bare period alias (see
+// periodArgAliases) when it is the only argument besides @project and #tag
+// shorthand, and returns the arguments without it. Other arguments are
+// returned unchanged. Setting the flag is what makes the alias conflict with
+// the other time period flags; ok is false when it cannot be set.
+func applyPeriodArgAlias(cmd *cobra.Command, args []string) ([]string, bool) {
+	var rest []string
+	alias := ""
+	for _, arg := range args {
+		if isShorthandArg(arg) {
+			rest = append(rest, arg)
+		} else if alias == "" && periodArgAliases[strings.ToLower(arg)] != "" {
+			alias = strings.ToLower(arg)
+		} else {
+			return args, true
+		}
 	}
-
-	if thisWeek {
-		now := time.Now()
-		start := timeutil.StartOfWeekWithConfig(now, deps.Config.WeekStartDay)
-		end := timeutil.EndOfWeekWithConfig(now, deps.Config.WeekStartDay)
-		dateRange := formatDateRangeForDisplay(start, end)
-		period := fmt.Sprintf("this week (%s)", dateRange)
-		listEntriesForRange(cmd, period, start, end)
-		return true
+	if alias == "" {
+		return args, true
 	}
 
-	if prevWeek {
-		lastWeek := time.Now().AddDate(0, 0, -7)
-		start := timeutil.StartOfWeekWithConfig(lastWeek, deps.Config.WeekStartDay)
-		end := timeutil.EndOfWeekWithConfig(lastWeek, deps.Config.WeekStartDay)
-		dateRange := formatDateRangeForDisplay(start, end)
-		period := fmt.Sprintf("previous week (%s)", dateRange)
-		listEntriesForRange(cmd, period, start, end)
-		return true
+	// Set like the flag, the alias conflicts with other time period flags
+	if err := cmd.Flags().Set(periodArgAliases[alias], "true"); err != nil {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: %v\n", err)
+		deps.Exit(1)
+		return nil, false
 	}
+	return rest, true
+}
 
-	if thisMonth {
-		now := time.Now()
-		start := timeutil.StartOfMonth(now)
-		end := timeutil.EndOfMonth(now)
-		dateRange := formatDateRangeForDisplay(start, end)
-		period := fmt.Sprintf("this month (%s)", dateRange)
-		listEntriesForRange(cmd, period, start, end)
-		return true
-	}
+// isShorthandArg reports whether arg is an @project or #tag shorthand filter
+func isShorthandArg(arg string) bool {
+	return strings.HasPrefix(arg, "@") || strings.HasPrefix(arg, "#")
+}
 
-	if prevMonth {
-		lastMonth := time.Now().AddDate(0, -1, 0)
-		start := timeutil.StartOfMonth(lastMonth)
-		end := timeutil.EndOfMonth(lastMonth)
-		dateRange := formatDateRangeForDisplay(start, end)
-		period := fmt.Sprintf("previous month (%s)", dateRange)
-		listEntriesForRange(cmd, period, start, end)
-		return true
+// offersFirstRunWizard reports whether the listing of cmd may offer the setup
+// wizard: only a plain 'did', without arguments, time period or filter flags,
+// or --count-only and --minutes for scripts
+func offersFirstRunWizard(cmd *cobra.Command, args []string) bool {
+	if len(args) > 0 || isCountOnly(cmd) {
+		return false
 	}
+	c, err := query.Resolve(cmd, deps.Config)
+	return err == nil && !c.HasPeriod() && c.Project == "" && c.Client == "" && len(c.Tags) == 0
+}
 
-	if lastDays > 0 {
-		now := time.Now()
-		end := timeutil.EndOfDay(now)
-		start := timeutil.StartOfDay(now.AddDate(0, 0, -(lastDays - 1)))
-		dateRange := formatDateRangeForDisplay(start, end)
-		period := fmt.Sprintf("last %d %s (%s)", lastDays, pluralize("day", lastDays), dateRange)
-		listEntriesForRange(cmd, period, start, end)
+// handleTimePeriodFlags checks for time period flags and lists entries accordingly.
+// Returns true if a time period flag was handled, false otherwise.
+func handleTimePeriodFlags(cmd *cobra.Command, args []string) bool {
+	c, ok := resolveQuery(cmd)
+	if !ok {
 		return true
 	}
 
-	if fromStr != "" || toStr != "" {
-		var startDate, endDate time.Time
-		var err error
-
-		// Parse from date
-		if fromStr != "" {
-			startDate, err = timeutil.ParseDate(fromStr)
-			if err != nil {
-				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --from date: %v\n", err)
-				_, _ = fmt.Fprintln(deps.Stderr, "Use format YYYY-MM-DD or DD/MM/YYYY")
-				deps.Exit(1)
-				return true
-			}
-		} else {
-			// No from date: use beginning of time
-			startDate = time.Time{}
-		}
-
-		// Parse to date
-		if toStr != "" {
-			toDate, err := timeutil.ParseDate(toStr)
-			if err != nil {
-				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --to date: %v\n", err)
-				_, _ = fmt.Fprintln(deps.Stderr, "Use format YYYY-MM-DD or DD/MM/YYYY")
-				deps.Exit(1)
+	// If no time period flags, return false to continue normal processing
+	keyword := deps.Config.EffectiveDurationKeyword()
+	if !c.HasPeriod() {
+		if len(args) > 0 {
+			rawInput := strings.Join(quoteShorthandArgs(args), " ")
+			if _, _, ok := entry.SplitAtDurationKeyword(rawInput, keyword); !ok {
+				// Shorthand filters alone list today's entries; any other
+				// words without a duration are an entry missing one
+				for _, arg := range args {
+					if !isShorthandArg(arg) {
+						printInputError(missingDurationError(true))
+						return true
+					}
+				}
+				c.Period = query.Today(configuredNow())
+				listMatchingEntries(cmd, c)
 				return true
 			}
-			endDate = timeutil.EndOfDay(toDate)
-		} else {
-			// No to date: use end of today
-			endDate = timeutil.EndOfDay(time.Now())
 		}
-
-		// Validate that start is not after end
-		if !startDate.IsZero() && startDate.After(endDate) {
-			_, _ = fmt.Fprintf(deps.Stderr, "Error: --from date (%s) is after --to date (%s)\n",
-				startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
-			deps.Exit(1)
-			return true
-		}
-
-		period := formatDateRangeForDisplay(startDate, endDate)
-		listEntriesForRange(cmd, period, startDate, endDate)
-		return true
+		return false
 	}
 
-	if dateStr != "" {
-		date, err := timeutil.ParseDate(dateStr)
-		if err != nil {
-			_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --date value: %v\n", err)
-			_, _ = fmt.Fprintln(deps.Stderr, "Use format YYYY-MM-DD or DD/MM/YYYY")
-			deps.Exit(1)
+	// Check if args contain entry creation (has 'for' keyword) - time flags shouldn't be used with entry creation
+	if len(args) > 0 {
+		rawInput := strings.Join(quoteShorthandArgs(args), " ")
+		if _, _, ok := entry.SplitAtDurationKeyword(rawInput, keyword); ok {
+			printPeriodEntryCreationError()
 			return true
 		}
-		endDate := timeutil.EndOfDay(date)
-		period := formatDateRangeForDisplay(date, endDate)
-		listEntriesForRange(cmd, period, date, endDate)
-		return true
 	}
 
-	return false
+	listMatchingEntries(cmd, c)
+	return true
+}
+
+// listWeek lists the entries of the current week, or of the previous week when prev is set
+func listWeek(cmd *cobra.Command, prev bool) {
+	c := query.ResolveFilters(cmd)
+	c.Period = query.Week(configuredNow(), deps.Config.WeekStartDay, prev)
+	listMatchingEntries(cmd, c)
+}
+
+// listMonth lists the entries of the current month, or of the previous month when prev is set
+func listMonth(cmd *cobra.Command, prev bool) {
+	c := query.ResolveFilters(cmd)
+	c.Period = query.Month(configuredNow(), deps.Config.MonthStartDay, prev)
+	listMatchingEntries(cmd, c)
 }
 
-// SetVersionInfo sets the version information for the CLI
-func SetVersionInfo(version, commit, date string) {
-	rootCmd.Version = version
-	rootCmd.SetVersionTemplate(
-		"did version {{.Version}}\n" +
-			"commit: " + commit + "\n" +
-			"built: " + date + "\n",
-	)
+// printPeriodEntryCreationError reports an entry description given together with
+// a time period, which only makes sense for listing
+func printPeriodEntryCreationError() {
+	_, _ = fmt.Fprintln(deps.Stderr, "Error: Time period flags cannot be used when creating entries")
+	_, _ = fmt.Fprintf(deps.Stderr, "To create an entry: did <description> %s <duration>\n", deps.Config.EffectiveDurationKeyword())
+	_, _ = fmt.Fprintln(deps.Stderr, "To list entries: did [time-flag] [@project] [#tag]")
+	deps.Exit(1)
 }
 
 // Execute runs the root command
 func Execute() error {
+	describeMaxEntryDuration()
 	return rootCmd.Execute()
 }
 
+// maxEntryDurationUsage matches max_entry_duration in the help of a flag,
+// with the limit added by describeMaxEntryDuration if any
+var maxEntryDurationUsage = regexp.MustCompile(`max_entry_duration( \([^)]*\))?`)
+
+// describeMaxEntryDuration adds the configured max_entry_duration to the help
+// of the --allow-long flags, which are defined before the config is loaded
+func describeMaxEntryDuration() {
+	limit := fmt.Sprintf("max_entry_duration (%s)", formatDuration(deps.Config.MaxEntryMinutes()))
+	for _, flags := range []*pflag.FlagSet{rootCmd.Flags(), logCmd.Flags(), editCmd.Flags(), pasteCmd.Flags(), importCmd.PersistentFlags()} {
+		if f := flags.Lookup("allow-long"); f != nil {
+			f.Usage = maxEntryDurationUsage.ReplaceAllLiteralString(f.Usage, limit)
+		}
+	}
+}
+
 // parseShorthandFilters parses @project and #tag shorthand syntax from args.
 // It sets the corresponding flags for filtering, but does NOT modify the args
 // so that project/tags can be parsed later for entry creation.
@@ -386,12 +599,12 @@ func Execute() error {
 func parseShorthandFilters(cmd *cobra.Command, args []string) []string {
 	for _, arg := range args {
 		if strings.HasPrefix(arg, "@") {
-			project := strings.TrimPrefix(arg, "@")
+			project := shorthandName(arg)
 			if project != "" {
 				_ = cmd.Root().PersistentFlags().Set("project", project)
 			}
 		} else if strings.HasPrefix(arg, "#") {
-			tag := strings.TrimPrefix(arg, "#")
+			tag := shorthandName(arg)
 			if tag != "" {
 				_ = cmd.Root().PersistentFlags().Set("tag", tag)
 			}
@@ -400,63 +613,126 @@ func parseShorthandFilters(cmd *cobra.Command, args []string) []string {
 	return args
 }
 
+// shorthandName returns the name of an @project or #tag argument. The shell
+// already removed the quotes of @"Big Client", but they are also accepted when
+// passed through, e.g. from '@"Big Client"'.
+func shorthandName(arg string) string {
+	name := strings.TrimSpace(arg[1:])
+	if len(name) >= 2 && strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`) {
+		name = name[1 : len(name)-1]
+	}
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// quoteShorthandArgs restores the quotes the shell removed from multi-word
+// @project and #tag arguments, so @"Big Client" keeps its words together
+// when the arguments are joined for parsing
+func quoteShorthandArgs(args []string) []string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = arg
+		if (strings.HasPrefix(arg, "@") || strings.HasPrefix(arg, "#")) && strings.ContainsAny(arg, " \t") {
+			if name := shorthandName(arg); name != "" {
+				quoted[i] = arg[:1] + entry.QuoteName(name)
+			}
+		}
+	}
+	return quoted
+}
+
 // createEntry parses arguments and creates a new time tracking entry
-func createEntry(args []string) {
+func createEntry(cmd *cobra.Command, args []string) {
 	// Join all arguments to form the raw input
-	rawInput := strings.Join(args, " ")
-
-	// Parse the input: expected format "<description> for <duration>"
-	// Find the last "for" in the input to extract duration
-	lastForIdx := strings.LastIndex(strings.ToLower(rawInput), " for ")
-	if lastForIdx == -1 {
-		_, _ = fmt.Fprintln(deps.Stderr, "Error: Invalid format. Missing 'for <duration>'")
-		_, _ = fmt.Fprintln(deps.Stderr, "Usage: did <description> for <duration>")
-		_, _ = fmt.Fprintln(deps.Stderr, "Example: did feature X for 2h")
+	logEntry(cmd, strings.Join(quoteShorthandArgs(args), " "))
+}
+
+// createEntryFromStdin creates an entry from the line read from stdin, for
+// --stdin and 'did -'. The line is the whole entry text, untouched by the
+// shell, and is parsed like the arguments of createEntry. Entries have no
+// notes, so further non-empty lines are rejected; args may only be the '-'.
+func createEntryFromStdin(cmd *cobra.Command, args []string) {
+	if len(args) > 1 || (len(args) == 1 && args[0] != "-") {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: The entry text cannot be given both as arguments and on stdin")
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Leave out the arguments, e.g. echo 'feature X for 2h' | did --stdin")
 		deps.Exit(1)
 		return
 	}
-
-	description := strings.TrimSpace(rawInput[:lastForIdx])
-	durationStr := strings.TrimSpace(rawInput[lastForIdx+5:]) // +5 for " for "
-
-	if description == "" {
-		_, _ = fmt.Fprintln(deps.Stderr, "Error: Description cannot be empty")
-		deps.Exit(1)
+	c, ok := resolveQuery(cmd)
+	if !ok {
 		return
 	}
-
-	// Parse project and tags from description
-	cleanDesc, project, tags := entry.ParseProjectAndTags(description)
-
-	// Check that cleaned description is not empty (in case it was only @project/#tags)
-	if cleanDesc == "" {
-		_, _ = fmt.Fprintln(deps.Stderr, "Error: Description cannot be empty (only project/tags provided)")
-		deps.Exit(1)
+	if c.HasPeriod() {
+		printPeriodEntryCreationError()
 		return
 	}
 
-	// Parse the duration
-	minutes, err := entry.ParseDuration(durationStr)
+	data, err := io.ReadAll(deps.Stdin)
 	if err != nil {
-		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid duration '%s'\n", durationStr)
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read from stdin")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
-		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use format like '2h' (hours) or '30m' (minutes), max 24h")
 		deps.Exit(1)
 		return
 	}
+	lines := strings.Split(strings.TrimPrefix(string(data), "\ufeff"), "\n")
+	rawInput := strings.TrimSpace(lines[0])
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) != "" {
+			_, _ = fmt.Fprintln(deps.Stderr, "Error: Stdin has more than one line, but an entry is a single line")
+			_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use 'did log --stdin-lines' to log an entry for every line")
+			deps.Exit(1)
+			return
+		}
+	}
+	if rawInput == "" {
+		printInputError(&inputError{message: "Description cannot be empty"})
+		return
+	}
+	logEntry(cmd, rawInput)
+}
 
-	// Create the entry
-	e := entry.Entry{
-		Timestamp:       time.Now(),
-		Description:     cleanDesc,
-		DurationMinutes: minutes,
-		RawInput:        rawInput,
-		Project:         project,
-		Tags:            tags,
+// logEntry parses rawInput and creates a new time tracking entry, confirming
+// it on stdout
+func logEntry(cmd *cobra.Command, rawInput string) {
+	e, defaulted, err := parseNewEntry(cmd, rawInput)
+	if err != nil {
+		printInputError(err)
+		return
 	}
+	// The confirmation echoes the description as typed
+	description := rawInput
+	if !defaulted {
+		description, _, _ = entry.SplitAtDurationKeyword(rawInput, deps.Config.EffectiveDurationKeyword())
+	}
+	project := e.Project
 
-	// Get storage path
-	storagePath, err := deps.StoragePath()
+	// An explicit @project always wins over the workspace
+	var workspaceDir string
+	var inferred config.Workspace
+	if project == "" {
+		workspaceDir, inferred = applyWorkspace(cmd, &e)
+	}
+	deps.Config.ApplyEntryDefaults(&e)
+
+	// --at dates the entry at the given time instead of now
+	if at, _ := cmd.Flags().GetString("at"); at != "" {
+		t, err := timeutil.ParseTimestamp(at, configuredLocation())
+		if err != nil {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --at time '%s'\n", at)
+			_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+			_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use RFC3339 or YYYY-MM-DD HH:MM, e.g., --at '2024-01-15 15:00'")
+			deps.Exit(1)
+			return
+		}
+		e.Timestamp = t
+	}
+
+	// Refuse times in the future, given with --at or from a skewed system clock
+	allowFuture, _ := cmd.Flags().GetBool("allow-future")
+	if !checkEntryNotInFuture(e.Timestamp, allowFuture) {
+		return
+	}
+
+	store, err := deps.Store()
 	if err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
@@ -465,29 +741,247 @@ func createEntry(args []string) {
 		return
 	}
 
+	if !prepareStorageDir(store.Path()) {
+		return
+	}
+
 	// Append the entry to storage
-	if err := storage.AppendEntry(storagePath, e); err != nil {
+	if err := store.Append(e); err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to save entry to storage")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
-		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that directory exists and is writable: %s\n", storagePath)
+		printStorageWriteHint(store.Path(), err)
 		deps.Exit(1)
 		return
 	}
 
 	// Display success message
-	_, _ = fmt.Fprintf(deps.Stdout, "Logged: %s (%s)\n", description, formatDuration(minutes))
+	if project == "" && e.Project != "" {
+		description += " @" + e.Project
+	}
+	for _, tag := range inferred.Tags {
+		description += " #" + tag
+	}
+	if e.Client != "" {
+		description += " [client " + e.Client + "]"
+	}
+	if defaulted {
+		_, _ = fmt.Fprintf(deps.Stdout, "Logged: %s (%s, default duration)\n", description, formatDuration(e.DurationMinutes))
+	} else {
+		_, _ = fmt.Fprintf(deps.Stdout, "Logged: %s (%s)\n", description, formatDuration(e.DurationMinutes))
+	}
+	if workspaceDir != "" {
+		_, _ = fmt.Fprintf(deps.Stdout, "Note: %s inferred from workspace %s (use --no-workspace to skip)\n",
+			formatProjectAndTags(inferred.Project, inferred.Tags), workspaceDir)
+	}
+}
+
+// inputError is entry input that was rejected: a message, the details of
+// the underlying error if any, and the lines helping to fix it. The message
+// is printed after label, "Error" unless set.
+type inputError struct {
+	label   string
+	message string
+	details error
+	help    []string
+}
+
+func (e *inputError) Error() string {
+	if e.details != nil {
+		return fmt.Sprintf("%s: %v", e.message, e.details)
+	}
+	return e.message
+}
+
+// printInputError reports err, an *inputError or any other error, to stderr
+// and exits with status 1
+func printInputError(err error) {
+	var inputErr *inputError
+	if !errors.As(err, &inputErr) {
+		inputErr = &inputError{message: err.Error()}
+	}
+	label := inputErr.label
+	if label == "" {
+		label = "Error"
+	}
+	_, _ = fmt.Fprintf(deps.Stderr, "%s: %s\n", label, inputErr.message)
+	if inputErr.details != nil {
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", inputErr.details)
+	}
+	for _, line := range inputErr.help {
+		_, _ = fmt.Fprintln(deps.Stderr, line)
+	}
+	deps.Exit(1)
+}
+
+// parseNewEntry parses rawInput in the '<description> for <duration>' format
+// ("for" being the configured duration keyword) into a new entry logged now,
+// honoring --no-tags, --allow-long and --client. Without a duration the
+// configured default duration is used and defaulted is set. The workspace
+// and config defaults are not applied yet. Rejected input is returned as an
+// *inputError.
+func parseNewEntry(cmd *cobra.Command, rawInput string) (e entry.Entry, defaulted bool, err error) {
+	// The last keyword in the input separates the description from the duration
+	keyword := deps.Config.EffectiveDurationKeyword()
+	usage := []string{
+		fmt.Sprintf("Usage: did <description> %s <duration>", keyword),
+		fmt.Sprintf("Example: did feature X %s 2h", keyword),
+	}
+	missingDescription := &inputError{message: "Description cannot be just a duration. Did you forget the description?", help: usage}
+
+	description, durationStr, found := entry.SplitAtDurationKeyword(rawInput, keyword)
+	if !found && entry.IsDurationOnly(rawInput, keyword) {
+		// e.g. "did for 2h"
+		return e, false, missingDescription
+	}
+	// Without a duration, fall back to the configured default duration
+	defaulted = !found && deps.Config.DefaultDurationMinutes > 0
+	if defaulted {
+		description = rawInput
+	}
+	if !found && !defaulted {
+		return e, false, missingDurationError(false)
+	}
+
+	if description == "" {
+		return e, false, &inputError{message: "Description cannot be empty"}
+	}
+
+	// Parse project and tags from description; with --no-tags #words stay in it
+	var cleanDesc, project string
+	var tags []string
+	if noTags, _ := cmd.Flags().GetBool("no-tags"); noTags {
+		cleanDesc, project = entry.ParseProject(description)
+	} else {
+		cleanDesc, project, tags = entry.ParseProjectAndTags(description)
+	}
+
+	// Check that cleaned description is not empty (in case it was only @project/#tags)
+	if cleanDesc == "" {
+		return e, false, &inputError{message: "Description cannot be empty (only project/tags provided)"}
+	}
+
+	// Reject descriptions that are only a duration, e.g. "did 2h for 2h" or "did for 2h for 2h"
+	cleanDesc = entry.TrimLeadingDurationClauses(cleanDesc, keyword)
+	if cleanDesc == "" || entry.IsDurationOnly(cleanDesc, keyword) {
+		return e, false, missingDescription
+	}
+
+	// Parse the duration
+	minutes := deps.Config.DefaultDurationMinutes
+	if !defaulted {
+		allowLong, _ := cmd.Flags().GetBool("allow-long")
+		if minutes, err = checkEntryDuration(durationStr, allowLong); err != nil {
+			return e, false, err
+		}
+	}
+
+	client, _ := cmd.Root().PersistentFlags().GetString("client")
+	if client != "" && !entry.IsValidName(client) {
+		return e, false, invalidClientError(client)
+	}
+
+	now := time.Now()
+	return entry.Entry{
+		Timestamp:       now,
+		Description:     cleanDesc,
+		DurationMinutes: minutes,
+		RawInput:        rawInput,
+		Project:         project,
+		Client:          client,
+		Tags:            tags,
+		LoggedAt:        &now,
+	}, defaulted, nil
+}
+
+// missingDurationError returns the error for entry input without a
+// '<keyword> <duration>' clause. The hint points to the default duration,
+// which 'did' itself only applies through 'did log' when listing is set.
+func missingDurationError(listing bool) *inputError {
+	keyword := deps.Config.EffectiveDurationKeyword()
+	help := []string{
+		fmt.Sprintf("Usage: did <description> %s <duration>", keyword),
+		fmt.Sprintf("Example: did feature X %s 2h", keyword),
+	}
+	switch {
+	case listing && deps.Config.DefaultDurationMinutes > 0:
+		help = append(help, "Hint: Use 'did log <description>' to log it with the default duration")
+	case listing:
+		help = append(help, "Hint: To list entries, use only @project and #tag filters, or a period such as 'did y' or 'did w'")
+	default:
+		help = append(help, "Hint: Set default_duration_minutes in the config to log without a duration")
+	}
+	return &inputError{message: fmt.Sprintf("Invalid format. Missing '%s <duration>'", keyword), help: help}
+}
+
+// applyWorkspace gives e the project of the configured workspace containing
+// the working directory and adds its tags, unless --no-workspace is set. It
+// returns the workspace directory and the project and tags it added, or ""
+// when no workspace applies.
+func applyWorkspace(cmd *cobra.Command, e *entry.Entry) (string, config.Workspace) {
+	noWorkspace, _ := cmd.Flags().GetBool("no-workspace")
+	if noWorkspace || deps.Getwd == nil || len(deps.Config.Workspaces) == 0 {
+		return "", config.Workspace{}
+	}
+	dir, err := deps.Getwd()
+	if err != nil {
+		return "", config.Workspace{}
+	}
+	// Without a home directory, only absolute workspace paths can match
+	home, _ := os.UserHomeDir()
+	workspaceDir, ws, ok := deps.Config.Workspaces.Match(dir, home)
+	if !ok {
+		return "", config.Workspace{}
+	}
+
+	inferred := config.Workspace{Project: ws.Project}
+	e.Project = ws.Project
+	for _, tag := range ws.Tags {
+		if !containsTag(e.Tags, tag) {
+			e.Tags = append(e.Tags, tag)
+			inferred.Tags = append(inferred.Tags, tag)
+		}
+	}
+	if inferred.Project == "" && len(inferred.Tags) == 0 {
+		return "", config.Workspace{}
+	}
+	return workspaceDir, inferred
 }
 
 // listEntries reads and displays entries filtered by the given time range.
-// This function accepts a function that returns start/end times.
-func listEntries(cmd *cobra.Command, period string, timeRangeFunc func() (time.Time, time.Time)) {
-	start, end := timeRangeFunc()
+// This function accepts a function that returns the start/end times for the
+// current time in the configured timezone.
+func listEntries(cmd *cobra.Command, period string, timeRangeFunc func(now time.Time) (time.Time, time.Time)) {
+	start, end := timeRangeFunc(configuredNow())
 	listEntriesForRange(cmd, period, start, end)
 }
 
 // listEntriesForRange reads and displays entries filtered by explicit start/end times and optional filters
 func listEntriesForRange(cmd *cobra.Command, period string, start, end time.Time) {
-	storagePath, err := deps.StoragePath()
+	c := query.ResolveFilters(cmd)
+	c.Period = query.Period{Name: period, Label: period, Start: start, End: end}
+	listMatchingEntries(cmd, c)
+}
+
+// listMatchingEntries reads and displays the entries matching the criteria
+func listMatchingEntries(cmd *cobra.Command, c query.Criteria) {
+	subtotalsBy, ok := subtotalGrouping(cmd)
+	if !ok {
+		return
+	}
+	desc, ok := descendingOrder(cmd)
+	if !ok {
+		return
+	}
+	roundStep, ok := roundDisplayStep(cmd, deps.Config.RoundDisplayMinutes)
+	if !ok {
+		return
+	}
+	explain, explainAll, ok := explainFlags(cmd)
+	if !ok {
+		return
+	}
+
+	store, err := deps.Store()
 	if err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
@@ -495,8 +989,9 @@ func listEntriesForRange(cmd *cobra.Command, period string, start, end time.Time
 		deps.Exit(1)
 		return
 	}
+	storagePath := store.Path()
 
-	result, err := storage.ReadEntriesWithWarnings(storagePath)
+	result, err := store.ListEntries(c)
 	if err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
@@ -506,135 +1001,521 @@ func listEntriesForRange(cmd *cobra.Command, period string, start, end time.Time
 	}
 
 	// Display warnings about corrupted lines to stderr
-	if len(result.Warnings) > 0 {
-		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Found %d corrupted line(s) in storage file:\n", len(result.Warnings))
-		for _, warning := range result.Warnings {
-			_, _ = fmt.Fprintln(deps.Stderr, formatCorruptionWarning(warning))
-		}
-		_, _ = fmt.Fprintln(deps.Stderr)
-	}
+	printCorruptionWarnings(result.Warnings)
+	printSuspectWarnings(len(result.Suspect))
 
-	type indexedEntry struct {
-		entry.Entry
-		activeIndex int
+	filtered := result.Entries
+	if desc {
+		entry.SortByTimestamp(filtered, func(ie didlib.IndexedEntry) time.Time { return ie.Timestamp }, true)
 	}
+	period := c.HeaderString()
 
-	var activeEntries []indexedEntry
-	activeIdx := 0
-	for _, e := range result.Entries {
-		if e.DeletedAt == nil {
-			activeIdx++
-			activeEntries = append(activeEntries, indexedEntry{Entry: e, activeIndex: activeIdx})
-		}
+	// Durations are shown rounded with --round-display, the total adds up the
+	// rounded durations; --verbose still shows the stored minutes
+	shownMinutes := func(ie didlib.IndexedEntry) int {
+		return entry.RoundDurationNearest(ie.DurationMinutes, roundStep)
 	}
-
-	var filtered []indexedEntry
-	for _, ie := range activeEntries {
-		if timeutil.IsInRange(ie.Timestamp, start, end) {
-			filtered = append(filtered, ie)
-		}
+	totalMinutes := 0
+	for _, ie := range filtered {
+		totalMinutes += shownMinutes(ie)
 	}
 
-	projectFilter, _ := cmd.Root().PersistentFlags().GetString("project")
-	tagFilters, _ := cmd.Root().PersistentFlags().GetStringSlice("tag")
-
-	f := filter.NewFilter("", projectFilter, tagFilters)
-	if !f.IsEmpty() {
-		var projectTagFiltered []indexedEntry
-		for _, ie := range filtered {
-			if f.Matches(ie.Entry) {
-				projectTagFiltered = append(projectTagFiltered, ie)
-			}
-		}
-		filtered = projectTagFiltered
-		period = buildPeriodWithFilters(period, projectFilter, tagFilters)
+	// Bare numbers for scripts and shell prompts
+	if minutesOnly, _ := cmd.Flags().GetBool("minutes"); minutesOnly {
+		_, _ = fmt.Fprintln(deps.Stdout, totalMinutes)
+		return
+	}
+	if isCountOnly(cmd) {
+		_, _ = fmt.Fprintln(deps.Stdout, len(filtered))
+		return
 	}
 
 	if len(filtered) == 0 {
 		_, _ = fmt.Fprintf(deps.Stdout, "No entries found for %s\n", period)
+		if explainAll {
+			printExcludedEntries(store, c, withIndexWidth(cmd, result.Count), roundStep)
+		}
 		return
 	}
 
-	sort.Slice(filtered, func(i, j int) bool {
-		return filtered[i].Timestamp.Before(filtered[j].Timestamp)
-	})
+	// Decided before the pager takes over stdout
+	showTrend := c.Period.Name == "today" && deps.Config.Sparkline && stdoutIsTerminal()
 
-	totalMinutes := 0
-	for _, ie := range filtered {
-		totalMinutes += ie.DurationMinutes
-	}
+	defer startPager(cmd)()
 
 	_, _ = fmt.Fprintf(deps.Stdout, "Entries for %s:\n", period)
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("-", 50))
 
-	maxIndex := activeEntries[len(activeEntries)-1].activeIndex
-	maxIndexWidth := len(fmt.Sprintf("%d", maxIndex))
+	// Indices are those of the whole file even when hidden, so edit and
+	// delete keep addressing the same entries
+	maxIndexWidth := len(fmt.Sprintf("%d", result.Count))
+	withIndex := showIndex(cmd)
+	detailsIndent := strings.Repeat(" ", maxIndexWidth+3)
+	if !withIndex {
+		detailsIndent = "  "
+	}
 
 	entriesForDateCheck := make([]entry.Entry, len(filtered))
 	for i, ie := range filtered {
 		entriesForDateCheck[i] = ie.Entry
+		entriesForDateCheck[i].DurationMinutes = shownMinutes(ie)
 	}
 	showDate := spansMultipleDays(entriesForDateCheck)
 
+	sourceOf := sourceColumn(cmd, entriesForDateCheck)
+
 	for _, ie := range filtered {
+		source := sourceOf(ie.Entry)
+		if withIndex {
+			_, _ = fmt.Fprintf(deps.Stdout, "[%*d] ", maxIndexWidth, ie.Index)
+		}
 		if showDate {
-			_, _ = fmt.Fprintf(deps.Stdout, "[%*d] %s %s  %s (%s)\n",
-				maxIndexWidth,
-				ie.activeIndex,
-				ie.Timestamp.Format("2006-01-02"),
+			_, _ = fmt.Fprintf(deps.Stdout, "%s %s  %s%s (%s)\n",
+				ie.Timestamp.Format("Mon 2006-01-02"),
 				ie.Timestamp.Format("15:04"),
-				formatEntryForLog(ie.Description, ie.Project, ie.Tags),
-				formatDuration(ie.DurationMinutes))
+				source,
+				formatEntryForLog(ie.Description, displayProject(ie.Entry), ie.Tags),
+				formatDuration(shownMinutes(ie)))
 		} else {
-			_, _ = fmt.Fprintf(deps.Stdout, "[%*d] %s  %s (%s)\n",
-				maxIndexWidth,
-				ie.activeIndex,
+			_, _ = fmt.Fprintf(deps.Stdout, "%s  %s%s (%s)\n",
 				ie.Timestamp.Format("15:04"),
-				formatEntryForLog(ie.Description, ie.Project, ie.Tags),
-				formatDuration(ie.DurationMinutes))
+				source,
+				formatEntryForLog(ie.Description, displayProject(ie.Entry), ie.Tags),
+				formatDuration(shownMinutes(ie)))
+		}
+		if verboseFlag {
+			printEntryDetails(ie.Entry, detailsIndent)
+		}
+		if explain {
+			_, _ = fmt.Fprintf(deps.Stdout, "%smatched: %s\n", detailsIndent, explainDecisions(c.Explain(ie.Entry), true))
 		}
 	}
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("-", 50))
-	_, _ = fmt.Fprintf(deps.Stdout, "Total: %s\n", formatDuration(totalMinutes))
+	if subtotalsBy != "" {
+		displaySubtotals(entriesForDateCheck, subtotalsBy, c.Period.Start, c.Period.End)
+		_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("-", 50))
+	}
+	total := "Total: " + formatDuration(totalMinutes)
+	if deps.Config.FooterBreakdown && subtotalsBy == "" {
+		total += footerBreakdown(entriesForDateCheck, c.Period.Start, c.Period.End)
+	}
+	_, _ = fmt.Fprintln(deps.Stdout, total)
+	if showTrend {
+		if trend := trendSparkline(store, c, roundStep, time.Now()); trend != "" {
+			_, _ = fmt.Fprintln(deps.Stdout, trend)
+		}
+	}
+	if explainAll {
+		printExcludedEntries(store, c, withIndexWidth(cmd, result.Count), roundStep)
+	}
 }
 
-// formatDateRangeForDisplay formats a date range for human-readable display.
-// Used for custom date range queries to generate appropriate period descriptions.
-func formatDateRangeForDisplay(start, end time.Time) string {
-	// If same day, show single date
-	if start.Format("2006-01-02") == end.Format("2006-01-02") {
-		return start.Format("Mon, Jan 2, 2006")
+// explainFlags returns whether --explain or --explain-all (which implies
+// --explain) are given. They explain a listing, so combining them with
+// --count-only or --minutes is reported to stderr; ok is false then.
+func explainFlags(cmd *cobra.Command) (explain, explainAll, ok bool) {
+	explain, _ = cmd.Flags().GetBool("explain")
+	explainAll, _ = cmd.Flags().GetBool("explain-all")
+	if (explain || explainAll) && isCountOnly(cmd) {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: --explain and --explain-all cannot be combined with --count-only or --minutes")
+		deps.Exit(1)
+		return false, false, false
 	}
+	return explain || explainAll, explainAll, true
+}
 
-	// If same year, don't repeat the year
-	if start.Year() == end.Year() {
-		return fmt.Sprintf("%s - %s",
-			start.Format("Jan 2"),
-			end.Format("Jan 2, 2006"))
+// explainDecisions joins the reasons of the decisions that matched, or of
+// those that didn't when matched is false, e.g. "project \"acme\" is @acme;
+// has the #review tag"
+func explainDecisions(decisions []query.Decision, matched bool) string {
+	var reasons []string
+	for _, d := range decisions {
+		if d.Matched == matched {
+			reasons = append(reasons, d.Reason)
+		}
+	}
+	if len(reasons) == 0 {
+		return "no period or filters set"
 	}
+	return strings.Join(reasons, "; ")
+}
 
-	// Different years, show both
-	return fmt.Sprintf("%s - %s",
-		start.Format("Jan 2, 2006"),
-		end.Format("Jan 2, 2006"))
+// withIndexWidth returns the width of the [index] column of a listing of
+// count entries, or 0 when the column is hidden (see showIndex)
+func withIndexWidth(cmd *cobra.Command, count int) int {
+	if !showIndex(cmd) {
+		return 0
+	}
+	return len(fmt.Sprintf("%d", count))
 }
 
-// buildPeriodWithFilters appends filter information to the period description.
-// Example: "today" -> "today (@acme #bugfix)"
-func buildPeriodWithFilters(period, project string, tags []string) string {
-	if project == "" && len(tags) == 0 {
-		return period
+// printExcludedEntries lists the active entries that don't match the criteria
+// with the reasons they were left out, for --explain-all. indexWidth is the
+// width of the [index] column, 0 to leave it out.
+func printExcludedEntries(store *didlib.Store, c query.Criteria, indexWidth, roundStep int) {
+	explained, err := store.Explain(c)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		deps.Exit(1)
+		return
 	}
 
-	var filters []string
-	if project != "" {
-		filters = append(filters, "@"+project)
+	var excluded []didlib.ExplainedEntry
+	for _, e := range explained {
+		if !e.Matched() {
+			excluded = append(excluded, e)
+		}
 	}
-	for _, tag := range tags {
-		filters = append(filters, "#"+tag)
+	_, _ = fmt.Fprintf(deps.Stdout, "\nExcluded: %s\n", formatCount(len(excluded), "entry", "entries"))
+	if len(excluded) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("-", 50))
+	detailsIndent := "  "
+	if indexWidth > 0 {
+		detailsIndent = strings.Repeat(" ", indexWidth+3)
+	}
+	for _, e := range excluded {
+		if indexWidth > 0 {
+			_, _ = fmt.Fprintf(deps.Stdout, "[%*d] ", indexWidth, e.Index)
+		}
+		_, _ = fmt.Fprintf(deps.Stdout, "%s  %s (%s)\n",
+			e.Timestamp.Format("Mon 2006-01-02 15:04"),
+			formatEntryForLog(e.Description, displayProject(e.Entry), e.Tags),
+			formatDuration(entry.RoundDurationNearest(e.DurationMinutes, roundStep)))
+		_, _ = fmt.Fprintf(deps.Stdout, "%sexcluded: %s\n", detailsIndent, explainDecisions(e.Decisions, false))
+	}
+}
+
+// sparklineDays is the number of days, up to today, in the sparkline under
+// today's entries
+const sparklineDays = 14
+
+// minSparklineDays is the number of those days that must have entries for the
+// sparkline to be shown
+const minSparklineDays = 3
+
+// trendSparkline returns the time logged on each of the last sparklineDays
+// days up to now as a sparkline with the average per day, e.g.
+// "Last 14 days: ▁▂▄▁▇▃▅▁▁▆▃▄▂▅ (avg 4h 10m/day)", for the entries matching
+// the project, client and tag filters of c. Days are those of the configured
+// timezone. Returns "" with fewer than minSparklineDays days with entries.
+func trendSparkline(store *didlib.Store, c query.Criteria, roundStep int, now time.Time) string {
+	result, err := store.ListEntries(query.Criteria{Project: c.Project, Client: c.Client, Tags: c.Tags})
+	if err != nil {
+		return ""
+	}
+	entries := make([]entry.Entry, len(result.Entries))
+	for i, ie := range result.Entries {
+		entries[i] = ie.Entry
+	}
+
+	loc := configuredLocation()
+	today := timeutil.StartOfDay(now.In(loc))
+	days := stats.DailyTotals(roundDisplayEntries(entries, roundStep), today.AddDate(0, 0, 1-sparklineDays), timeutil.EndOfDay(today), loc)
+	minutes := make([]int, len(days))
+	total, logged := 0, 0
+	for i, day := range days {
+		minutes[i] = day.Minutes
+		total += day.Minutes
+		if day.Minutes > 0 {
+			logged++
+		}
+	}
+	if logged < minSparklineDays {
+		return ""
+	}
+	average := int(math.Round(float64(total) / float64(len(days))))
+	return fmt.Sprintf("Last %d days: %s (avg %s/day)", len(days), renderSparkline(minutes), formatDuration(average))
+}
+
+// addIndexFlags adds the --index and --no-index flags to a listing command
+func addIndexFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("no-index", false, "Hide the [index] column used by edit and delete")
+	cmd.Flags().Bool("index", false, "Show the [index] column even with --no-index (e.g. from an alias)")
+}
+
+// addOrderFlag adds the --order flag to a command listing or exporting entries
+func addOrderFlag(cmd *cobra.Command) {
+	cmd.Flags().String("order", entry.OrderAsc, "Order entries by timestamp: asc (oldest first) or desc (newest first)")
+}
+
+// descendingOrder reports whether --order desc was given. An invalid --order is
+// reported to stderr; ok is false then.
+func descendingOrder(cmd *cobra.Command) (desc bool, ok bool) {
+	order, _ := cmd.Flags().GetString("order")
+	switch strings.ToLower(strings.TrimSpace(order)) {
+	case entry.OrderAsc, "":
+		return false, true
+	case entry.OrderDesc:
+		return true, true
+	}
+	_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --order '%s'\n", order)
+	_, _ = fmt.Fprintf(deps.Stderr, "Valid orders: %s, %s\n", entry.OrderAsc, entry.OrderDesc)
+	deps.Exit(1)
+	return false, false
+}
+
+// showIndex reports whether a listing shows entry indices: they are shown
+// unless --no-index is given, and --index wins over --no-index
+func showIndex(cmd *cobra.Command) bool {
+	if index, _ := cmd.Flags().GetBool("index"); index {
+		return true
+	}
+	noIndex, _ := cmd.Flags().GetBool("no-index")
+	return !noIndex
+}
+
+// maxFooterProjects is the number of projects named on the Total line
+const maxFooterProjects = 5
+
+// footerBreakdown returns the time per project for the Total line of a
+// listing, e.g. " (@acme 4h, no project 30m, +2 more)", or "" when the
+// entries belong to a single project. The breakdown is the one stats and
+// --subtotals show, so the numbers agree.
+func footerBreakdown(entries []entry.Entry, start, end time.Time) string {
+	summaries := sortedSummaries(projectSummaries(stats.CalculateProjectBreakdown(entries, start, end), true))
+	if len(summaries) < 2 {
+		return ""
+	}
+
+	var parts []string
+	for _, s := range summaries[:min(len(summaries), maxFooterProjects)] {
+		label := "no project"
+		if s.Name != "" {
+			label = "@" + entry.QuoteName(s.Name)
+		}
+		parts = append(parts, label+" "+formatDuration(s.TotalMinutes))
+	}
+	if more := len(summaries) - maxFooterProjects; more > 0 {
+		parts = append(parts, fmt.Sprintf("+%d more", more))
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// printEntryDetails prints an entry as it is stored, indented under its listing line
+func printEntryDetails(e entry.Entry, indent string) {
+	project := e.Project
+	if project == "" {
+		project = "(none)"
+	}
+	tags := strings.Join(e.Tags, ", ")
+	if tags == "" {
+		tags = "(none)"
+	}
+	_, _ = fmt.Fprintf(deps.Stdout, "%sTimestamp: %s\n", indent, e.Timestamp.Format(time.RFC3339))
+	_, _ = fmt.Fprintf(deps.Stdout, "%sRaw input: %s\n", indent, e.RawInput)
+	_, _ = fmt.Fprintf(deps.Stdout, "%sProject:   %s\n", indent, project)
+	_, _ = fmt.Fprintf(deps.Stdout, "%sTags:      %s\n", indent, tags)
+	_, _ = fmt.Fprintf(deps.Stdout, "%sDuration:  %d minutes\n", indent, e.DurationMinutes)
+}
+
+// addShowSourceFlag adds the --show-source flag to a command listing entries
+func addShowSourceFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("show-source", false, "Show the storage file each entry comes from")
+}
+
+// sourceColumn returns the column --show-source adds to the lines of entries:
+// the name of the storage file an entry comes from, padded to the longest name
+// among entries. Without --show-source the column is empty.
+func sourceColumn(cmd *cobra.Command, entries []entry.Entry) func(entry.Entry) string {
+	if showSource, _ := cmd.Flags().GetBool("show-source"); !showSource {
+		return func(entry.Entry) string { return "" }
+	}
+	// Listings have read the entries from the storage path already
+	storagePath, _ := deps.StoragePath()
+	width := 0
+	for _, e := range entries {
+		width = max(width, len(entrySourceName(e, storagePath)))
+	}
+	return func(e entry.Entry) string {
+		return fmt.Sprintf("%-*s  ", width, entrySourceName(e, storagePath))
+	}
+}
+
+// entrySourceName returns the name of the storage file an entry comes from,
+// without the .jsonl extension. Entries read from a single storage file have
+// no Source and are named after that file.
+func entrySourceName(e entry.Entry, storagePath string) string {
+	source := e.Source
+	if source == "" {
+		source = filepath.Base(storagePath)
+	}
+	return strings.TrimSuffix(source, ".jsonl")
+}
+
+// isCountOnly reports whether --count-only or --minutes asks for a bare number
+// instead of the listing
+func isCountOnly(cmd *cobra.Command) bool {
+	countOnly, _ := cmd.Flags().GetBool("count-only")
+	minutesOnly, _ := cmd.Flags().GetBool("minutes")
+	return countOnly || minutesOnly
+}
+
+// subtotalGrouping returns how listings should group subtotals ("project" or
+// "tag"), or "" when --subtotals and --subtotals-by are not set.
+// Reports an invalid --subtotals-by value and returns false.
+func subtotalGrouping(cmd *cobra.Command) (string, bool) {
+	subtotals, _ := cmd.Flags().GetBool("subtotals")
+	subtotalsBy, _ := cmd.Flags().GetString("subtotals-by")
+
+	switch strings.ToLower(strings.TrimSpace(subtotalsBy)) {
+	case "":
+		if subtotals {
+			return "project", true
+		}
+		return "", true
+	case "project":
+		return "project", true
+	case "tag":
+		return "tag", true
+	default:
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --subtotals-by value '%s'\n", subtotalsBy)
+		_, _ = fmt.Fprintln(deps.Stderr, "Valid values: project, tag")
+		deps.Exit(1)
+		return "", false
+	}
+}
+
+// displaySubtotals prints the time per project or tag for the listed entries,
+// most time first. Entries with several tags count towards each of them.
+func displaySubtotals(entries []entry.Entry, groupBy string, start, end time.Time) {
+	var summaries []metadataSummary
+	var prefix, none string
+	if groupBy == "tag" {
+		summaries = tagSummaries(stats.CalculateTagBreakdown(entries, start, end), true)
+		prefix, none = "#", "(no tags)"
+	} else {
+		summaries = projectSummaries(stats.CalculateProjectBreakdown(entries, start, end), true)
+		prefix, none = "@", "(no project)"
+	}
+
+	for _, s := range sortedSummaries(summaries) {
+		label := none
+		if s.Name != "" {
+			label = prefix + s.Name
+		}
+		_, _ = fmt.Fprintf(deps.Stdout, "  %-28s  %10s  (%d %s)\n",
+			label,
+			formatDuration(s.TotalMinutes),
+			s.Count,
+			pluralize("entry", "entries", s.Count))
+	}
+}
+
+// parseEntryDuration parses the duration of a new or edited entry, reporting
+// invalid input to stderr (see checkEntryDuration).
+// Returns false if the duration was rejected.
+func parseEntryDuration(input string, allowLong bool) (int, bool) {
+	minutes, err := checkEntryDuration(input, allowLong)
+	if err != nil {
+		printInputError(err)
+		return 0, false
+	}
+	return minutes, true
+}
+
+// checkEntryDuration parses the duration of a new or edited entry. Durations
+// must be greater than zero, and durations over max_entry_duration (24 hours
+// by default) are only accepted with allowLong (--allow-long). Rejected input
+// is returned as an *inputError.
+func checkEntryDuration(input string, allowLong bool) (int, error) {
+	formatHint := fmt.Sprintf("Hint: Use format like '2h' (hours) or '30m' (minutes), max %s", formatDuration(deps.Config.MaxEntryMinutes()))
+	minutes, err := entry.ParseDurationMinutes(input)
+	if err != nil {
+		return 0, &inputError{message: fmt.Sprintf("Invalid duration '%s'", input), details: err, help: []string{formatHint}}
+	}
+
+	if minutes <= 0 {
+		return 0, &inputError{message: "Duration must be greater than 0", help: []string{formatHint}}
+	}
+
+	if maxMinutes := deps.Config.MaxEntryMinutes(); minutes > maxMinutes && !allowLong {
+		return 0, &inputError{
+			label:   "Warning",
+			message: fmt.Sprintf("Duration '%s' (%s) is longer than %s, which is probably a typo", input, formatDuration(minutes), formatDuration(maxMinutes)),
+			help:    []string{"Hint: Use --allow-long to log it anyway, or raise max_entry_duration in the config"},
+		}
 	}
 
-	return fmt.Sprintf("%s (%s)", period, strings.Join(filters, " "))
+	return minutes, nil
+}
+
+// parseDateFlag parses a --date/--from/--to value, resolving relative dates
+// such as "yesterday" or "last friday" against now in the configured timezone.
+func parseDateFlag(input string) (time.Time, error) {
+	return timeutil.ParseDateAt(input, configuredNow())
+}
+
+// corruptionWarningInterval is how long an unchanged set of corrupted-line
+// warnings stays hidden after it was shown
+const corruptionWarningInterval = 24 * time.Hour
+
+// verboseFlag always shows corrupted-line warnings and adds the stored details
+// of each entry to listings
+var verboseFlag bool
+
+// printCorruptionWarnings prints the corrupted-line warnings of a storage read to
+// stderr. An unchanged set of warnings is only shown once per day unless
+// --verbose is set; 'did validate' reports corrupted lines itself and is never throttled.
+func printCorruptionWarnings(warnings []storage.ParseWarning) {
+	if len(warnings) == 0 || (!verboseFlag && corruptionWarningsShownRecently(warnings)) {
+		return
+	}
+
+	_, _ = fmt.Fprintf(deps.Stderr, "Warning: Found %s in storage file:\n", formatCount(len(warnings), "corrupted line", "corrupted lines"))
+	for _, warning := range warnings {
+		_, _ = fmt.Fprintln(deps.Stderr, formatCorruptionWarning(warning))
+	}
+	_, _ = fmt.Fprintln(deps.Stderr)
+}
+
+// printSuspectWarnings reports the number of matching entries with a zero or
+// negative duration, which are left out of listings and totals, to stderr
+func printSuspectWarnings(count int) {
+	if count == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(deps.Stderr, "Warning: Left out %s with a zero or negative duration\n", formatCount(count, "entry", "entries"))
+	_, _ = fmt.Fprintln(deps.Stderr, "Hint: Run 'did validate' to list them, then fix them with 'did edit <index> --duration'")
+	_, _ = fmt.Fprintln(deps.Stderr)
+}
+
+// countSuspect returns the number of active entries with a zero or negative
+// duration that match, i.e. those a command leaves out of what it totals
+func countSuspect(entries []entry.Entry, matches func(entry.Entry) bool) int {
+	count := 0
+	for _, e := range entries {
+		if e.DeletedAt == nil && !e.HasValidDuration() && matches(e) {
+			count++
+		}
+	}
+	return count
+}
+
+// corruptionWarningsShownRecently reports whether the same set of warnings was
+// shown within corruptionWarningInterval, and otherwise records that it is shown now.
+// The state file is best-effort: when it can't be read or written, warnings are shown.
+func corruptionWarningsShownRecently(warnings []storage.ParseWarning) bool {
+	if deps.WarningStatePath == nil {
+		return false
+	}
+	statePath, err := deps.WarningStatePath()
+	if err != nil {
+		return false
+	}
+
+	now := time.Now()
+	hash := storage.WarningsHash(warnings)
+	state, err := storage.ReadWarningState(statePath)
+	if err == nil && state.Hash == hash && now.Sub(state.ShownAt) >= 0 && now.Sub(state.ShownAt) < corruptionWarningInterval {
+		return true
+	}
+
+	_ = storage.WriteWarningState(statePath, storage.WarningState{Hash: hash, ShownAt: now})
+	return false
 }
 
 // formatCorruptionWarning formats a ParseWarning into a human-readable string
@@ -645,11 +1526,62 @@ func formatCorruptionWarning(warning storage.ParseWarning) string {
 	if len(content) > 50 {
 		content = content[:47] + "..."
 	}
+	if warning.File != "" {
+		return fmt.Sprintf("  %s line %d: %s (error: %s)", warning.File, warning.LineNumber, content, warning.Error)
+	}
 	return fmt.Sprintf("  Line %d: %s (error: %s)", warning.LineNumber, content, warning.Error)
 }
 
-// validateStorage checks the storage file health and reports status
-func validateStorage() {
+// validationJSON is the output of did validate --json
+type validationJSON struct {
+	ValidEntries     int                     `json:"valid_entries"`
+	CorruptedEntries int                     `json:"corrupted_entries"`
+	Warnings         []validationWarningJSON `json:"warnings"`
+}
+
+// validationWarningJSON is a corrupted line in the output of did validate --json
+type validationWarningJSON struct {
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line"`
+	Content string `json:"content"`
+	Error   string `json:"error"`
+}
+
+// newValidationJSON converts the storage health for did validate --json
+func newValidationJSON(health storage.StorageHealth) validationJSON {
+	out := validationJSON{
+		ValidEntries:     health.ValidEntries,
+		CorruptedEntries: health.CorruptedEntries,
+		Warnings:         make([]validationWarningJSON, 0, len(health.Warnings)),
+	}
+	for _, w := range health.Warnings {
+		out.Warnings = append(out.Warnings, validationWarningJSON{File: w.File, Line: w.LineNumber, Content: w.Content, Error: w.Error})
+	}
+	return out
+}
+
+// describeTimezone returns the timezone tz as resolved for dates, with its
+// UTC offset at now, e.g. "Europe/Oslo (UTC+02:00)". The local timezone is
+// shown with its detected name, e.g. "Local: Europe/Oslo (UTC+02:00)".
+func describeTimezone(tz string, now time.Time) string {
+	loc := timeutil.MustLoadTimezone(tz)
+	name := loc.String()
+	if loc == time.Local {
+		if detected := timeutil.DetectTimezone(); detected != "Local" {
+			name = "Local: " + detected
+		}
+	}
+	return fmt.Sprintf("%s (UTC%s)", name, now.In(loc).Format("-07:00"))
+}
+
+// validateStorage checks the storage file health and reports status.
+// A storage directory also gets a breakdown per file.
+// Active --project/--tag filters add a breakdown of the matching valid entries.
+// With --strict, corrupted lines make the command exit with status 1.
+func validateStorage(cmd *cobra.Command) {
+	asJSON, _ := cmd.Flags().GetBool("json")
+	strict, _ := cmd.Flags().GetBool("strict")
+
 	storagePath, err := deps.StoragePath()
 	if err != nil {
 		_, _ = fmt.Fprintf(deps.Stderr, "Error: Failed to get storage path: %v\n", err)
@@ -664,8 +1596,21 @@ func validateStorage() {
 		return
 	}
 
+	if asJSON {
+		writeJSONOutput(newValidationJSON(health))
+		if strict && health.CorruptedEntries > 0 {
+			deps.Exit(1)
+		}
+		return
+	}
+
 	// Display storage path
-	_, _ = fmt.Fprintf(deps.Stdout, "Storage file: %s\n", storagePath)
+	isDir := storage.IsDirectory(storagePath)
+	if isDir {
+		_, _ = fmt.Fprintf(deps.Stdout, "Storage directory: %s\n", storagePath)
+	} else {
+		_, _ = fmt.Fprintf(deps.Stdout, "Storage file: %s\n", storagePath)
+	}
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
 
 	// Display health metrics
@@ -673,6 +1618,20 @@ func validateStorage() {
 	_, _ = fmt.Fprintf(deps.Stdout, "Valid entries:     %d\n", health.ValidEntries)
 	_, _ = fmt.Fprintf(deps.Stdout, "Corrupted entries: %d\n", health.CorruptedEntries)
 
+	activeEntries, err := storage.ReadActiveEntries(storagePath)
+	if err != nil {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Failed to read entries: %v\n", err)
+		deps.Exit(1)
+		return
+	}
+	_, _ = fmt.Fprintf(deps.Stdout, "Checksum:          %s (%s)\n", storage.Checksum(activeEntries), formatCount(len(activeEntries), "active entry", "active entries"))
+	_, _ = fmt.Fprintf(deps.Stdout, "Timezone:          %s\n", describeTimezone(deps.Config.Timezone, time.Now()))
+
+	// Display per-file metrics of a storage directory
+	if isDir {
+		displayFileHealth(health.Files)
+	}
+
 	// Display corrupted line details if any
 	if len(health.Warnings) > 0 {
 		_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
@@ -682,26 +1641,246 @@ func validateStorage() {
 		}
 	}
 
+	// Display the number of entries out of chronological order, e.g. after an import
+	if health.OutOfOrder > 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
+		_, _ = fmt.Fprintf(deps.Stdout, "Entries out of chronological order: %d\n", health.OutOfOrder)
+		for _, w := range health.Backdated {
+			_, _ = fmt.Fprintf(deps.Stdout, "  %s: %s\n", warningLocation(w), w.Error)
+		}
+		_, _ = fmt.Fprintln(deps.Stdout, "Hint: Run 'did storage sort' to rewrite the file in order (--dry-run to preview)")
+	}
+
+	// Display entries dated in the future, e.g. logged while the clock was wrong
+	futureIndices := futureEntryIndices(activeEntries, time.Now().Add(futureEntryThreshold))
+	if len(futureIndices) > 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
+		_, _ = fmt.Fprintf(deps.Stdout, "Entries dated in the future: %d\n", len(futureIndices))
+		for _, idx := range futureIndices {
+			e := activeEntries[idx-1]
+			_, _ = fmt.Fprintf(deps.Stdout, "  [%d] %s  %s\n", idx, e.Timestamp.Format("2006-01-02 15:04"), formatEntryForLog(e.Description, displayProject(e), e.Tags))
+		}
+		_, _ = fmt.Fprintf(deps.Stdout, "Hint: Fix the time with did edit %d --timestamp 'YYYY-MM-DD HH:MM'\n", futureIndices[0])
+	}
+
+	// Display entries with a zero or negative duration, e.g. from a faulty import
+	suspectIndices := suspectEntryIndices(activeEntries)
+	if len(suspectIndices) > 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
+		_, _ = fmt.Fprintf(deps.Stdout, "Suspect entries (zero or negative duration): %d\n", len(suspectIndices))
+		for i, idx := range suspectIndices {
+			e := activeEntries[idx-1]
+			location := ""
+			if i < len(health.Suspect) {
+				location = warningLocation(health.Suspect[i]) + ": "
+			}
+			_, _ = fmt.Fprintf(deps.Stdout, "  [%d] %s%s  %s (%d minutes)\n", idx, location, e.Timestamp.Format("2006-01-02 15:04"), formatEntryForLog(e.Description, displayProject(e), e.Tags), e.DurationMinutes)
+		}
+		_, _ = fmt.Fprintf(deps.Stdout, "Hint: Fix the duration with did edit %d --duration 30m, or remove the entry with did delete %d\n", suspectIndices[0], suspectIndices[0])
+	}
+
+	// Display a storage file still in the config directory, where earlier versions kept it
+	if legacyPath, err := storage.LegacyStoragePath(); err == nil && deps.Config.StoragePath == "" && storagePath == legacyPath && storage.UsesLegacyStorage() {
+		dataPath, _ := storage.DataStoragePath()
+		_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
+		_, _ = fmt.Fprintln(deps.Stdout, "The storage file is in the config directory, where earlier versions of did kept it")
+		_, _ = fmt.Fprintf(deps.Stdout, "Hint: Run 'did migrate --data-dir' to move it to %s\n", dataPath)
+	}
+
+	// Display breakdown of valid entries matching the active filters
+	projectFilter, _ := cmd.Root().PersistentFlags().GetString("project")
+	tagFilters, _ := cmd.Root().PersistentFlags().GetStringSlice("tag")
+	f := filter.NewFilter("", projectFilter, tagFilters)
+	if !f.IsEmpty() {
+		entries, err := storage.ReadEntries(storagePath)
+		if err != nil {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Failed to read entries: %v\n", err)
+			deps.Exit(1)
+			return
+		}
+		displayFilteredHealth(filter.FilterEntries(entries, f), projectFilter, tagFilters)
+	}
+
 	// Overall status message
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
-	if health.CorruptedEntries == 0 {
+	if health.CorruptedEntries == 0 && len(futureIndices) == 0 && len(suspectIndices) == 0 {
 		_, _ = fmt.Fprintln(deps.Stdout, "Status: ✓ Storage file is healthy")
-	} else {
-		_, _ = fmt.Fprintf(deps.Stderr, "Status: ⚠ Storage file has %d corrupted line(s)\n", health.CorruptedEntries)
+	}
+	if health.CorruptedEntries > 0 {
+		_, _ = fmt.Fprintf(deps.Stderr, "Status: ⚠ Storage file has %s\n", formatCount(health.CorruptedEntries, "corrupted line", "corrupted lines"))
+	}
+	if len(futureIndices) > 0 {
+		_, _ = fmt.Fprintf(deps.Stderr, "Status: ⚠ Storage file has %s dated in the future\n", formatCount(len(futureIndices), "entry", "entries"))
+	}
+	if len(suspectIndices) > 0 {
+		_, _ = fmt.Fprintf(deps.Stderr, "Status: ⚠ Storage file has %s with a zero or negative duration\n", formatCount(len(suspectIndices), "entry", "entries"))
+	}
+	if strict && health.CorruptedEntries > 0 {
+		deps.Exit(1)
 	}
 }
 
-// formatDuration formats minutes as a human-readable string
-func formatDuration(minutes int) string {
-	if minutes < 60 {
-		return fmt.Sprintf("%dm", minutes)
+// compareStorage compares the active entries of the storage with those of
+// the storage file at otherPath
+func compareStorage(otherPath string) {
+	storagePath, err := deps.StoragePath()
+	if err != nil {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Failed to get storage path: %v\n", err)
+		deps.Exit(1)
+		return
+	}
+
+	// A missing storage file reads as empty, which is not what a typo in --compare should mean
+	if _, err := os.Stat(otherPath); err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read the file to compare with")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", otherPath)
+		deps.Exit(1)
+		return
+	}
+
+	var sides [2][]entry.Entry
+	for i, path := range []string{storagePath, otherPath} {
+		sides[i], err = storage.ReadActiveEntries(path)
+		if err != nil {
+			_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
+			_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+			_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", path)
+			deps.Exit(1)
+			return
+		}
+	}
+
+	_, _ = fmt.Fprintf(deps.Stdout, "Storage: %s\n", storagePath)
+	_, _ = fmt.Fprintf(deps.Stdout, "  %s, %s\n", formatCount(len(sides[0]), "active entry", "active entries"), storage.Checksum(sides[0]))
+	_, _ = fmt.Fprintf(deps.Stdout, "Other:   %s\n", otherPath)
+	_, _ = fmt.Fprintf(deps.Stdout, "  %s, %s\n", formatCount(len(sides[1]), "active entry", "active entries"), storage.Checksum(sides[1]))
+
+	c := storage.CompareEntries(sides[0], sides[1])
+	printComparedEntries("Only in storage", c.OnlyA)
+	printComparedEntries("Only in other", c.OnlyB)
+	if len(c.Changed) > 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
+		_, _ = fmt.Fprintf(deps.Stdout, "Different in each file: %d\n", len(c.Changed))
+		for _, change := range c.Changed {
+			_, _ = fmt.Fprintf(deps.Stdout, "  storage: %s\n", formatComparedEntry(change.A))
+			_, _ = fmt.Fprintf(deps.Stdout, "  other:   %s\n", formatComparedEntry(change.B))
+		}
+	}
+
+	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
+	if c.Equal() {
+		_, _ = fmt.Fprintln(deps.Stdout, "Status: ✓ Both files have the same entries")
+		return
+	}
+	_, _ = fmt.Fprintf(deps.Stderr, "Status: ⚠ The files differ: %d only in storage, %d only in other, %d different\n",
+		len(c.OnlyA), len(c.OnlyB), len(c.Changed))
+}
+
+// printComparedEntries prints a section of entries found in only one of the compared files
+func printComparedEntries(heading string, entries []entry.Entry) {
+	if len(entries) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
+	_, _ = fmt.Fprintf(deps.Stdout, "%s: %d\n", heading, len(entries))
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(deps.Stdout, "  %s\n", formatComparedEntry(e))
+	}
+}
+
+// formatComparedEntry formats an entry of a storage comparison with its date
+func formatComparedEntry(e entry.Entry) string {
+	return fmt.Sprintf("%s  %s  (%s)", e.Timestamp.Format("2006-01-02 15:04"), formatEntryForLog(e.Description, displayProject(e), e.Tags), formatDuration(e.DurationMinutes))
+}
+
+// futureEntryThreshold is how far ahead of now an entry must be dated for
+// 'did validate' to report it
+const futureEntryThreshold = 24 * time.Hour
+
+// futureEntryIndices returns the 1-based indices of the active entries dated after limit
+func futureEntryIndices(activeEntries []entry.Entry, limit time.Time) []int {
+	var indices []int
+	for i, e := range activeEntries {
+		if e.Timestamp.After(limit) {
+			indices = append(indices, i+1)
+		}
+	}
+	return indices
+}
+
+// suspectEntryIndices returns the 1-based indices of the active entries with a
+// zero or negative duration
+func suspectEntryIndices(activeEntries []entry.Entry) []int {
+	var indices []int
+	for i, e := range activeEntries {
+		if !e.HasValidDuration() {
+			indices = append(indices, i+1)
+		}
 	}
-	hours := minutes / 60
-	mins := minutes % 60
-	if mins == 0 {
-		return fmt.Sprintf("%dh", hours)
+	return indices
+}
+
+// warningLocation returns where the line of a health warning is stored, e.g.
+// "line 3" or "alice.jsonl line 3" in a storage directory
+func warningLocation(warning storage.ParseWarning) string {
+	if warning.File != "" {
+		return fmt.Sprintf("%s line %d", warning.File, warning.LineNumber)
 	}
-	return fmt.Sprintf("%dh %dm", hours, mins)
+	return fmt.Sprintf("line %d", warning.LineNumber)
+}
+
+// displayFileHealth shows the line counts of each file in a storage directory
+func displayFileHealth(files []storage.FileHealth) {
+	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
+	if len(files) == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, "Files: none (no *.jsonl files in the directory)")
+		return
+	}
+
+	nameWidth := 0
+	for _, f := range files {
+		if len(f.Name) > nameWidth {
+			nameWidth = len(f.Name)
+		}
+	}
+
+	_, _ = fmt.Fprintln(deps.Stdout, "Files:")
+	for _, f := range files {
+		_, _ = fmt.Fprintf(deps.Stdout, "  %-*s  %d valid, %d corrupted (%s)\n",
+			nameWidth, f.Name, f.ValidEntries, f.CorruptedEntries, formatCount(f.TotalLines, "line", "lines"))
+	}
+}
+
+// displayFilteredHealth shows how many valid entries match the active filters,
+// the date span they cover, and their total duration.
+func displayFilteredHealth(matching []entry.Entry, project string, tags []string) {
+	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
+	_, _ = fmt.Fprintln(deps.Stdout, query.Criteria{Project: project, Tags: tags}.Describe("Filtered")+":")
+	_, _ = fmt.Fprintf(deps.Stdout, "Matching entries:  %d\n", len(matching))
+	if len(matching) == 0 {
+		return
+	}
+
+	first, last := matching[0].Timestamp, matching[0].Timestamp
+	totalMinutes := 0
+	for _, e := range matching {
+		if e.Timestamp.Before(first) {
+			first = e.Timestamp
+		}
+		if e.Timestamp.After(last) {
+			last = e.Timestamp
+		}
+		totalMinutes += e.DurationMinutes
+	}
+
+	_, _ = fmt.Fprintf(deps.Stdout, "Date span:         %s\n", query.FormatDateRange(first, last))
+	_, _ = fmt.Fprintf(deps.Stdout, "Total time:        %s (%d minutes)\n", formatDuration(totalMinutes), totalMinutes)
+}
+
+// formatDuration formats minutes as a human-readable string
+func formatDuration(minutes int) string {
+	return timeutil.FormatDuration(minutes)
 }
 
 // formatProjectAndTags formats project and tags for display.
@@ -714,15 +1893,41 @@ func formatProjectAndTags(project string, tags []string) string {
 
 	var parts []string
 	if project != "" {
-		parts = append(parts, "@"+project)
+		parts = append(parts, "@"+entry.QuoteName(project))
 	}
 	for _, tag := range tags {
-		parts = append(parts, "#"+tag)
+		parts = append(parts, "#"+entry.QuoteName(tag))
 	}
 
 	return strings.Join(parts, " ")
 }
 
+// displayProject returns the project of e for display, prefixed with the
+// entry's client as "client/project" when it has one, or the client alone
+// for an entry without a project
+func displayProject(e entry.Entry) string {
+	if e.Client == "" {
+		return e.Project
+	}
+	if e.Project == "" {
+		return e.Client
+	}
+	return e.Client + "/" + e.Project
+}
+
+// printInvalidClientError reports a --client value that is not a valid name
+func printInvalidClientError(client string) {
+	printInputError(invalidClientError(client))
+}
+
+// invalidClientError returns the *inputError for the invalid client name client
+func invalidClientError(client string) error {
+	return &inputError{
+		message: fmt.Sprintf("Invalid client name '%s'", client),
+		help:    []string{"Hint: Client names can contain letters, digits, hyphens, underscores, and single spaces between words"},
+	}
+}
+
 // formatEntryForLog formats a description with optional project and tags for display.
 // Returns format like: "description" or "description [@project]" or "description [#tag1 #tag2]"
 // or "description [@project #tag1 #tag2]"
@@ -734,6 +1939,16 @@ func formatEntryForLog(description, project string, tags []string) string {
 	return fmt.Sprintf("%s [%s]", description, metadata)
 }
 
+// formatRawInput reconstructs the raw input of an entry from its fields,
+// using the configured duration keyword
+func formatRawInput(e entry.Entry) string {
+	descWithMeta := e.Description
+	if e.Project != "" || len(e.Tags) > 0 {
+		descWithMeta = fmt.Sprintf("%s %s", e.Description, formatProjectAndTags(e.Project, e.Tags))
+	}
+	return fmt.Sprintf("%s %s %s", descWithMeta, deps.Config.EffectiveDurationKeyword(), formatDuration(e.DurationMinutes))
+}
+
 // editEntry modifies an existing time tracking entry
 func editEntry(cmd *cobra.Command, args []string) {
 	// Parse the index argument (1-based from user)
@@ -748,20 +1963,85 @@ func editEntry(cmd *cobra.Command, args []string) {
 	// Get flag values
 	newDescription, _ := cmd.Flags().GetString("description")
 	newDuration, _ := cmd.Flags().GetString("duration")
+	newProject, _ := cmd.Flags().GetString("project")
+	setProject := cmd.Flags().Changed("project")
+	newClient, _ := cmd.Flags().GetString("client")
+	setClient := cmd.Flags().Changed("client")
+	appendTags, _ := cmd.Flags().GetStringSlice("append-tag")
+	removeTags, _ := cmd.Flags().GetStringSlice("remove-tag")
+	newTimestamp, _ := cmd.Flags().GetString("timestamp")
 
 	// Check that at least one flag is provided
-	if newDescription == "" && newDuration == "" {
-		_, _ = fmt.Fprintln(deps.Stderr, "Error: At least one flag (--description or --duration) is required")
+	if newDescription == "" && newDuration == "" && newTimestamp == "" && !setProject && !setClient && len(appendTags) == 0 && len(removeTags) == 0 {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: At least one flag (--description, --duration, --timestamp, --project, --client, --append-tag or --remove-tag) is required")
 		_, _ = fmt.Fprintln(deps.Stderr, "Usage:")
 		_, _ = fmt.Fprintln(deps.Stderr, "  did edit <index> --description 'new text'")
 		_, _ = fmt.Fprintln(deps.Stderr, "  did edit <index> --duration 2h")
+		_, _ = fmt.Fprintln(deps.Stderr, "  did edit <index> --timestamp '2024-01-15 15:00'")
 		_, _ = fmt.Fprintln(deps.Stderr, "  did edit <index> --description 'new text' --duration 2h")
+		_, _ = fmt.Fprintln(deps.Stderr, "  did edit <index> --project acme --append-tag review")
 		deps.Exit(1)
 		return
 	}
 
-	// Get storage path
-	storagePath, err := deps.StoragePath()
+	// Validate structured project/tag flags before touching storage
+	newProject = strings.TrimPrefix(newProject, "@")
+	if setProject && newProject != "" && !entry.IsValidName(newProject) {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid project name '%s'\n", newProject)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Project names can contain letters, digits, hyphens, underscores, and single spaces between words")
+		deps.Exit(1)
+		return
+	}
+	if setClient && newClient != "" && !entry.IsValidName(newClient) {
+		printInvalidClientError(newClient)
+		return
+	}
+	appendTags = trimTagPrefixes(appendTags)
+	removeTags = trimTagPrefixes(removeTags)
+	for _, tag := range append(append([]string{}, appendTags...), removeTags...) {
+		if !entry.IsValidName(tag) {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid tag name '%s'\n", tag)
+			_, _ = fmt.Fprintln(deps.Stderr, "Hint: Tag names can contain letters, digits, hyphens, underscores, and single spaces between words")
+			deps.Exit(1)
+			return
+		}
+	}
+	for _, tag := range appendTags {
+		if containsTag(removeTags, tag) {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Tag '%s' cannot be both appended and removed\n", tag)
+			deps.Exit(1)
+			return
+		}
+	}
+
+	// Parse the new timestamp before touching storage
+	var timestamp time.Time
+	if newTimestamp != "" {
+		allowFuture, _ := cmd.Flags().GetBool("allow-future")
+		var ok bool
+		if timestamp, ok = parseEntryTimestamp(newTimestamp, allowFuture); !ok {
+			return
+		}
+	}
+
+	// Reject descriptions whose inline @project/#tags would conflict with the structured flags
+	if newDescription != "" {
+		_, descProject, descTags := entry.ParseProjectAndTags(newDescription)
+		if descProject != "" && setProject {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Conflicting project: --description contains '@%s' and --project is also set\n", descProject)
+			_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use either @project in the description or --project, not both")
+			deps.Exit(1)
+			return
+		}
+		if len(descTags) > 0 && (len(appendTags) > 0 || len(removeTags) > 0) {
+			_, _ = fmt.Fprintln(deps.Stderr, "Error: Conflicting tags: --description contains #tags and --append-tag/--remove-tag is also set")
+			_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use either #tags in the description or --append-tag/--remove-tag, not both")
+			deps.Exit(1)
+			return
+		}
+	}
+
+	store, err := deps.Store()
 	if err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
@@ -770,55 +2050,44 @@ func editEntry(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// Read all entries
-	result, err := storage.ReadEntriesWithWarnings(storagePath)
+	// Take the revision before reading, so that the save fails instead of
+	// overwriting whatever another process writes in between
+	rev, err := store.Revision()
 	if err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
-		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", storagePath)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", store.Path())
 		deps.Exit(1)
 		return
 	}
 
-	allEntries := result.Entries
-
-	// Filter to active entries only and create index mapping
-	// Users should only be able to edit active (non-deleted) entries
-	var activeEntries []entry.Entry
-	var storageIndices []int // Maps active entry index to storage index
-	for i, e := range allEntries {
-		if e.DeletedAt == nil {
-			activeEntries = append(activeEntries, e)
-			storageIndices = append(storageIndices, i)
-		}
-	}
-
-	// Check if any active entries exist
-	if len(activeEntries) == 0 {
+	// Get the entry to modify; users can only edit active (non-deleted) entries
+	e, err := store.Entry(userIndex)
+	var indexErr *didlib.IndexError
+	switch {
+	case errors.Is(err, didlib.ErrNoEntries):
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: No entries found to edit")
 		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Create an entry first with 'did <description> for <duration>'")
 		_, _ = fmt.Fprintln(deps.Stderr, "Example: did feature X for 2h")
 		deps.Exit(1)
 		return
-	}
-
-	// Convert 1-based user index to 0-based active entry index
-	activeIndex := userIndex - 1
-
-	// Validate index is in range of active entries
-	if activeIndex < 0 || activeIndex >= len(activeEntries) {
+	case errors.As(err, &indexErr):
 		_, _ = fmt.Fprintf(deps.Stderr, "Error: Index %d is out of range\n", userIndex)
-		_, _ = fmt.Fprintf(deps.Stderr, "Valid range: 1-%d (%d %s available)\n", len(activeEntries), len(activeEntries), pluralize("entry", len(activeEntries)))
+		_, _ = fmt.Fprintf(deps.Stderr, "Valid range: 1-%d (%d %s available)\n", indexErr.Count, indexErr.Count, pluralize("entry", "entries", indexErr.Count))
 		_, _ = fmt.Fprintln(deps.Stderr, "Hint: List entries with 'did' to see all indices")
 		deps.Exit(1)
 		return
+	case err != nil:
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", store.Path())
+		deps.Exit(1)
+		return
 	}
 
-	// Get the entry to modify (from active entries)
-	e := activeEntries[activeIndex]
-
-	// Get the actual storage index for this entry
-	storageIndex := storageIndices[activeIndex]
+	// Keep the entry as it was, to show what the edit changed
+	before := e
+	before.Tags = append([]string(nil), e.Tags...)
 
 	// Update description if provided
 	if newDescription != "" {
@@ -837,57 +2106,185 @@ func editEntry(cmd *cobra.Command, args []string) {
 		e.Tags = tags
 	}
 
+	// Update project and client if provided (empty values clear them)
+	if setProject {
+		e.Project = newProject
+	}
+	if setClient {
+		e.Client = newClient
+	}
+
+	// Apply tag additions and removals
+	for _, tag := range removeTags {
+		e.Tags = removeTag(e.Tags, tag)
+	}
+	for _, tag := range appendTags {
+		if !containsTag(e.Tags, tag) {
+			e.Tags = append(e.Tags, tag)
+		}
+	}
+
 	// Update duration if provided
 	if newDuration != "" {
-		minutes, err := entry.ParseDuration(newDuration)
-		if err != nil {
-			_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid duration '%s'\n", newDuration)
-			_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
-			_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use format like '2h' (hours) or '30m' (minutes), max 24h")
-			deps.Exit(1)
+		allowLong, _ := cmd.Flags().GetBool("allow-long")
+		minutes, ok := parseEntryDuration(newDuration, allowLong)
+		if !ok {
 			return
 		}
 		e.DurationMinutes = minutes
 	}
 
-	// Update RawInput field to reflect changes
-	// Include project/tags in raw input reconstruction
-	descWithMeta := e.Description
-	if e.Project != "" || len(e.Tags) > 0 {
-		descWithMeta = fmt.Sprintf("%s %s", e.Description, formatProjectAndTags(e.Project, e.Tags))
-	}
-	if newDescription != "" && newDuration != "" {
-		// Both updated
-		e.RawInput = fmt.Sprintf("%s for %s", descWithMeta, newDuration)
-	} else if newDescription != "" {
-		// Only description updated - reconstruct with existing duration
-		e.RawInput = fmt.Sprintf("%s for %s", descWithMeta, formatDuration(e.DurationMinutes))
-	} else if newDuration != "" {
-		// Only duration updated - reconstruct with existing description and project/tags
-		e.RawInput = fmt.Sprintf("%s for %s", descWithMeta, newDuration)
+	// Always regenerate RawInput from the edited fields, so exports don't show
+	// the input the entry was originally logged with. A new duration is kept
+	// as typed.
+	e.RawInput = formatRawInput(e)
+	if newDuration != "" {
+		e.RawInput = strings.TrimSuffix(e.RawInput, formatDuration(e.DurationMinutes)) + newDuration
 	}
 
-	// Preserve original timestamp (already unchanged in e)
+	// Update timestamp if provided, otherwise the original is preserved
+	if newTimestamp != "" {
+		e.Timestamp = timestamp
+	}
 
 	// Save the updated entry
-	if err := storage.UpdateEntry(storagePath, storageIndex, e); err != nil {
+	err = store.UpdateIfUnchanged(userIndex, e, rev)
+	if errors.Is(err, storage.ErrStorageChanged) {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Storage changed since read, please retry")
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Another did process or an editor wrote to the storage while the entry was edited; nothing was saved")
+		deps.Exit(1)
+		return
+	}
+	if err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to save updated entry to storage")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
-		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file is writable: %s\n", storagePath)
+		printStorageWriteHint(store.Path(), err)
 		deps.Exit(1)
 		return
 	}
 
-	// Display success message with project/tags
-	_, _ = fmt.Fprintf(deps.Stdout, "Updated entry %d: %s (%s)\n", userIndex, formatEntryForLog(e.Description, e.Project, e.Tags), formatDuration(e.DurationMinutes))
+	// Display success message with project/tags and what changed
+	_, _ = fmt.Fprintf(deps.Stdout, "Updated entry %d: %s (%s)\n", userIndex, formatEntryForLog(e.Description, displayProject(e), e.Tags), formatDuration(e.DurationMinutes))
+	changes := entryChanges(before, e)
+	if len(changes) == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, "  (no changes)")
+	}
+	for _, change := range changes {
+		_, _ = fmt.Fprintf(deps.Stdout, "  %s\n", change)
+	}
+}
+
+// entryChanges describes each field that differs between before and after,
+// e.g. "description: 'original' → 'updated'" or "duration: 1h → 2h". RawInput
+// is left out: it follows from the other fields.
+func entryChanges(before, after entry.Entry) []string {
+	var changes []string
+	change := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", field, from, to))
+		}
+	}
+	orNone := func(s string) string {
+		if s == "" {
+			return "(none)"
+		}
+		return s
+	}
+
+	change("description", "'"+before.Description+"'", "'"+after.Description+"'")
+	change("project", orNone(formatProjectAndTags(before.Project, nil)), orNone(formatProjectAndTags(after.Project, nil)))
+	change("client", orNone(before.Client), orNone(after.Client))
+	change("tags", orNone(formatProjectAndTags("", before.Tags)), orNone(formatProjectAndTags("", after.Tags)))
+	change("duration", formatDuration(before.DurationMinutes), formatDuration(after.DurationMinutes))
+	if !before.Timestamp.Equal(after.Timestamp) {
+		loc := configuredLocation()
+		change("timestamp", before.Timestamp.In(loc).Format("2006-01-02 15:04"), after.Timestamp.In(loc).Format("2006-01-02 15:04"))
+	}
+	return changes
+}
+
+// checkEntryNotInFuture rejects the timestamp of a new entry that lies more than
+// future_margin_minutes ahead of now, e.g. a mistyped --at time.
+// Returns true if the timestamp is acceptable or allowFuture is set.
+func checkEntryNotInFuture(timestamp time.Time, allowFuture bool) bool {
+	margin := time.Duration(deps.Config.FutureMarginMinutes) * time.Minute
+	if allowFuture || !timestamp.After(time.Now().Add(margin)) {
+		return true
+	}
+
+	_, _ = fmt.Fprintf(deps.Stderr, "Error: Entry would be dated %s, which is in the future\n", timestamp.Format("2006-01-02 15:04"))
+	_, _ = fmt.Fprintln(deps.Stderr, "Details: Check the --at time and that the system clock is correct")
+	_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use --allow-future to log it anyway")
+	deps.Exit(1)
+	return false
 }
 
-// pluralize returns the singular or plural form of a word based on count
-func pluralize(word string, count int) string {
+// parseEntryTimestamp parses a --timestamp value in the configured timezone.
+// Reports an invalid value, or a time in the future unless allowFuture is set,
+// and returns false.
+func parseEntryTimestamp(input string, allowFuture bool) (time.Time, bool) {
+	now := configuredNow()
+	t, err := timeutil.ParseTimestamp(input, now.Location())
+	if err != nil {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid timestamp '%s'\n", input)
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use RFC3339 or YYYY-MM-DD HH:MM, e.g., --timestamp '2024-01-15 15:00'")
+		deps.Exit(1)
+		return time.Time{}, false
+	}
+
+	if t.After(now) && !allowFuture {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Timestamp '%s' is in the future\n", input)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use --allow-future to set it anyway")
+		deps.Exit(1)
+		return time.Time{}, false
+	}
+
+	return t.In(now.Location()), true
+}
+
+// trimTagPrefixes strips an optional leading '#' from each tag name
+func trimTagPrefixes(tags []string) []string {
+	trimmed := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		trimmed = append(trimmed, strings.TrimPrefix(tag, "#"))
+	}
+	return trimmed
+}
+
+// containsTag reports whether tags contains tag (case-insensitive)
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeTag returns tags without any occurrence of tag (case-insensitive)
+func removeTag(tags []string, tag string) []string {
+	var kept []string
+	for _, t := range tags {
+		if !strings.EqualFold(t, tag) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// pluralize returns singular when count is 1 and plural otherwise,
+// e.g. pluralize("entry", "entries", n)
+func pluralize(singular, plural string, count int) string {
 	if count == 1 {
-		return word
+		return singular
 	}
-	return word + "s"
+	return plural
+}
+
+// formatCount formats count with the matching form of a noun, e.g. "1 entry" or "12 entries"
+func formatCount(count int, singular, plural string) string {
+	return fmt.Sprintf("%d %s", count, pluralize(singular, plural, count))
 }
 
 func spansMultipleDays(entries []entry.Entry) bool {
@@ -2,18 +2,25 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/xolan/did/internal/config"
 	"github.com/xolan/did/internal/entry"
 	"github.com/xolan/did/internal/osutil"
+	"github.com/xolan/did/internal/query"
 	"github.com/xolan/did/internal/storage"
 	"github.com/xolan/did/internal/timeutil"
 )
@@ -50,11 +57,23 @@ func testDepsWithConfig(storagePath string, cfg config.Config) (*Deps, *bytes.Bu
 	}, stdout, stderr
 }
 
+// unreadableStoragePath returns a storage path below a regular file, so every
+// attempt to read it fails
+func unreadableStoragePath(t *testing.T) string {
+	t.Helper()
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(blocker, "entries.jsonl")
+}
+
 // resetFilterFlags clears all persistent filter flags to avoid test contamination
 // Note: StringSlice flags are difficult to reset cleanly in pflag, so we just mark them as unchanged
 func resetFilterFlags(cmd *cobra.Command) {
-	// Reset project flag
+	// Reset project and client flags
 	_ = cmd.Root().PersistentFlags().Set("project", "")
+	_ = cmd.Root().PersistentFlags().Set("client", "")
 
 	// For StringSlice tag flag, we need to get the current value and replace it
 	// The pflag library accumulates StringSlice values, so we use Replace method if available
@@ -88,28 +107,47 @@ func resetTimePeriodFlags(cmd *cobra.Command) {
 func TestPluralize(t *testing.T) {
 	tests := []struct {
 		name     string
-		word     string
+		singular string
+		plural   string
 		count    int
 		expected string
 	}{
-		{"singular entry", "entry", 1, "entry"},
-		{"plural entries", "entry", 0, "entrys"},
-		{"plural entries 2", "entry", 2, "entrys"},
-		{"plural entries 10", "entry", 10, "entrys"},
-		{"singular item", "item", 1, "item"},
-		{"plural items", "item", 5, "items"},
+		{"singular entry", "entry", "entries", 1, "entry"},
+		{"plural entries", "entry", "entries", 0, "entries"},
+		{"plural entries 2", "entry", "entries", 2, "entries"},
+		{"plural entries 10", "entry", "entries", 10, "entries"},
+		{"singular item", "item", "items", 1, "item"},
+		{"plural items", "item", "items", 5, "items"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := pluralize(tt.word, tt.count)
+			result := pluralize(tt.singular, tt.plural, tt.count)
 			if result != tt.expected {
-				t.Errorf("pluralize(%q, %d) = %q, expected %q", tt.word, tt.count, result, tt.expected)
+				t.Errorf("pluralize(%q, %q, %d) = %q, expected %q", tt.singular, tt.plural, tt.count, result, tt.expected)
 			}
 		})
 	}
 }
 
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		count    int
+		expected string
+	}{
+		{0, "0 entries"},
+		{1, "1 entry"},
+		{12, "12 entries"},
+	}
+
+	for _, tt := range tests {
+		result := formatCount(tt.count, "entry", "entries")
+		if result != tt.expected {
+			t.Errorf("formatCount(%d) = %q, expected %q", tt.count, result, tt.expected)
+		}
+	}
+}
+
 func TestFormatCorruptionWarning(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -163,7 +201,7 @@ func TestCreateEntry_Success(t *testing.T) {
 	SetDeps(d)
 	defer ResetDeps()
 
-	createEntry([]string{"test", "task", "for", "2h"})
+	createEntry(rootCmd, []string{"test", "task", "for", "2h"})
 
 	if stderr.Len() > 0 {
 		t.Errorf("Unexpected stderr output: %s", stderr.String())
@@ -187,6 +225,58 @@ func TestCreateEntry_Success(t *testing.T) {
 	}
 }
 
+func TestCreateEntry_Client(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	resetFilterFlags(rootCmd)
+	defer resetFilterFlags(rootCmd)
+	_ = rootCmd.PersistentFlags().Set("client", "AcmeCorp")
+
+	createEntry(rootCmd, []string{"api", "work", "@backend", "for", "2h"})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	if expected := "Logged: api work @backend [client AcmeCorp] (2h)\n"; stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+	entries, err := storage.ReadEntries(storagePath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Client != "AcmeCorp" || entries[0].Project != "backend" {
+		t.Errorf("Expected an entry for client AcmeCorp and project backend, got %+v", entries)
+	}
+}
+
+func TestCreateEntry_InvalidClient(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+
+	exitCalled := false
+	d, _, stderr := testDeps(storagePath)
+	d.Exit = func(code int) { exitCalled = true }
+	SetDeps(d)
+	defer ResetDeps()
+	resetFilterFlags(rootCmd)
+	defer resetFilterFlags(rootCmd)
+	_ = rootCmd.PersistentFlags().Set("client", "Acme.Corp")
+
+	createEntry(rootCmd, []string{"api", "work", "for", "2h"})
+
+	if !exitCalled {
+		t.Error("Expected exit to be called for an invalid client")
+	}
+	if !strings.Contains(stderr.String(), "Error: Invalid client name 'Acme.Corp'") {
+		t.Errorf("Expected invalid client error, got: %s", stderr.String())
+	}
+	if _, err := os.Stat(storagePath); !os.IsNotExist(err) {
+		t.Error("Expected no entry to be written")
+	}
+}
+
 func TestCreateEntry_MissingFor(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -197,7 +287,7 @@ func TestCreateEntry_MissingFor(t *testing.T) {
 	SetDeps(d)
 	defer ResetDeps()
 
-	createEntry([]string{"test", "task", "2h"})
+	createEntry(rootCmd, []string{"test", "task", "2h"})
 
 	if !exitCalled {
 		t.Error("Expected exit to be called")
@@ -218,7 +308,7 @@ func TestCreateEntry_EmptyDescription(t *testing.T) {
 	defer ResetDeps()
 
 	// Input " for 2h" - note leading space so rawInput contains " for "
-	createEntry([]string{"", "for", "2h"})
+	createEntry(rootCmd, []string{"", "for", "2h"})
 
 	if !exitCalled {
 		t.Error("Expected exit to be called")
@@ -228,6 +318,82 @@ func TestCreateEntry_EmptyDescription(t *testing.T) {
 	}
 }
 
+func TestCreateEntry_Stdin(t *testing.T) {
+	tests := []struct {
+		name        string
+		flag        bool
+		args        []string
+		input       string
+		wantStdout  string
+		wantStderr  string
+		wantEntries int
+	}{
+		{"flag", true, nil, `fixed "login" bug #urgent @acme for 1h` + "\n", `Logged: fixed "login" bug #urgent @acme (1h)`, "", 1},
+		{"dash", false, []string{"-"}, "\ufeffstandup for 15m\r\n\n", "Logged: standup (15m)", "", 1},
+		{"empty", true, nil, "  \n", "", "Error: Description cannot be empty", 0},
+		{"no input", false, []string{"-"}, "", "", "Error: Description cannot be empty", 0},
+		{"invalid", true, nil, "standup for 0m\n", "", "Duration must be greater than 0", 0},
+		{"several lines", true, nil, "standup for 15m\nreview for 30m\n", "", "Error: Stdin has more than one line", 0},
+		{"with arguments", true, []string{"standup"}, "standup for 15m\n", "", "Error: The entry text cannot be given both as arguments and on stdin", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			exitCode := 0
+			d, stdout, stderr := testDeps(storagePath)
+			d.Stdin = strings.NewReader(tt.input)
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			resetTimePeriodFlags(rootCmd)
+			resetFilterFlags(rootCmd)
+			defer resetFilterFlags(rootCmd)
+			_ = rootCmd.Flags().Set("stdin", fmt.Sprint(tt.flag))
+			defer func() { _ = rootCmd.Flags().Set("stdin", "false") }()
+
+			rootCmd.Run(rootCmd, tt.args)
+
+			if tt.wantStderr == "" && (exitCode != 0 || stderr.Len() > 0) {
+				t.Fatalf("Unexpected error (exit %d): %s", exitCode, stderr.String())
+			}
+			if tt.wantStderr != "" && (exitCode != 1 || !strings.Contains(stderr.String(), tt.wantStderr)) {
+				t.Errorf("Expected exit 1 with %q, got exit %d: %s", tt.wantStderr, exitCode, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("Expected stdout to contain %q, got %q", tt.wantStdout, stdout.String())
+			}
+			entries, _ := storage.ReadEntries(storagePath)
+			if len(entries) != tt.wantEntries {
+				t.Fatalf("Expected %d entries, got %+v", tt.wantEntries, entries)
+			}
+			if tt.name == "flag" && (entries[0].Description != `fixed "login" bug` || entries[0].Project != "acme" || len(entries[0].Tags) != 1) {
+				t.Errorf("Expected the line parsed like arguments, got %+v", entries[0])
+			}
+		})
+	}
+}
+
+func TestCreateEntry_StdinWithPeriod(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	d, _, stderr := testDeps(storagePath)
+	d.Stdin = strings.NewReader("standup for 15m\n")
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	defer resetTimePeriodFlags(rootCmd)
+	_ = rootCmd.Flags().Set("yesterday", "true")
+
+	rootCmd.Run(rootCmd, []string{"-"})
+
+	if !strings.Contains(stderr.String(), "Time period flags cannot be used when creating entries") {
+		t.Errorf("Expected a period error, got: %s", stderr.String())
+	}
+	if entries, _ := storage.ReadEntries(storagePath); len(entries) != 0 {
+		t.Errorf("Expected nothing logged, got %d entries", len(entries))
+	}
+}
+
 func TestCreateEntry_InvalidDuration(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -238,7 +404,7 @@ func TestCreateEntry_InvalidDuration(t *testing.T) {
 	SetDeps(d)
 	defer ResetDeps()
 
-	createEntry([]string{"test", "for", "invalid"})
+	createEntry(rootCmd, []string{"test", "for", "invalid"})
 
 	if !exitCalled {
 		t.Error("Expected exit to be called")
@@ -256,7 +422,7 @@ func TestListEntries_NoEntries(t *testing.T) {
 	SetDeps(d)
 	defer ResetDeps()
 
-	listEntries(rootCmd, "today", func() (time.Time, time.Time) {
+	listEntries(rootCmd, "today", func(time.Time) (time.Time, time.Time) {
 		now := time.Now()
 		return now, now.Add(24 * time.Hour)
 	})
@@ -285,7 +451,7 @@ func TestListEntries_WithEntries(t *testing.T) {
 	SetDeps(d)
 	defer ResetDeps()
 
-	listEntries(rootCmd, "today", func() (time.Time, time.Time) {
+	listEntries(rootCmd, "today", func(time.Time) (time.Time, time.Time) {
 		now := time.Now()
 		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 		end := start.Add(24 * time.Hour)
@@ -312,6 +478,7 @@ func TestValidateStorage_Healthy(t *testing.T) {
 		DurationMinutes: 60,
 		RawInput:        "valid entry for 1h",
 	}
+	testEntry.LoggedAt = &testEntry.Timestamp
 	if err := storage.AppendEntry(storagePath, testEntry); err != nil {
 		t.Fatalf("Failed to create test entry: %v", err)
 	}
@@ -320,7 +487,7 @@ func TestValidateStorage_Healthy(t *testing.T) {
 	SetDeps(d)
 	defer ResetDeps()
 
-	validateStorage()
+	validateStorage(validateCmd)
 
 	output := stdout.String()
 	if !strings.Contains(output, "Storage file is healthy") {
@@ -329,6 +496,199 @@ func TestValidateStorage_Healthy(t *testing.T) {
 	if !strings.Contains(output, "Valid entries:     1") {
 		t.Errorf("Expected 'Valid entries:     1', got: %s", output)
 	}
+	if expected := "Checksum:          " + storage.Checksum([]entry.Entry{testEntry}) + " (1 active entry)"; !strings.Contains(output, expected) {
+		t.Errorf("Expected %q, got: %s", expected, output)
+	}
+	if !strings.Contains(output, "Timezone:          ") {
+		t.Errorf("Expected the resolved timezone, got: %s", output)
+	}
+}
+
+func TestValidateStorage_LegacyLocation(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("the data directory is the config directory on " + runtime.GOOS)
+	}
+	configDir, dataDir := t.TempDir(), t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("XDG_DATA_HOME", dataDir)
+	legacyPath := filepath.Join(configDir, "did", "entries.jsonl")
+	if err := os.MkdirAll(filepath.Dir(legacyPath), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := storage.AppendEntry(legacyPath, entry.Entry{Timestamp: time.Now(), Description: "old", DurationMinutes: 30}); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+
+	d, stdout, _ := testDeps(legacyPath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	validateStorage(validateCmd)
+
+	expected := "Hint: Run 'did migrate --data-dir' to move it to " + filepath.Join(dataDir, "did", "entries.jsonl")
+	if !strings.Contains(stdout.String(), expected) {
+		t.Errorf("Expected %q, got: %s", expected, stdout.String())
+	}
+}
+
+func TestDescribeTimezone(t *testing.T) {
+	summer := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	winter := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		tz       string
+		now      time.Time
+		expected string
+	}{
+		{"Europe/Oslo", summer, "Europe/Oslo (UTC+02:00)"},
+		{"Europe/Oslo", winter, "Europe/Oslo (UTC+01:00)"},
+		{"America/New_York", winter, "America/New_York (UTC-05:00)"},
+		{"UTC", summer, "UTC (UTC+00:00)"},
+	}
+	for _, tt := range tests {
+		if got := describeTimezone(tt.tz, tt.now); got != tt.expected {
+			t.Errorf("describeTimezone(%q) = %q, expected %q", tt.tz, got, tt.expected)
+		}
+	}
+	if got := describeTimezone("Local", summer); !strings.HasPrefix(got, "Local") {
+		t.Errorf("describeTimezone(\"Local\") = %q, expected the local timezone", got)
+	}
+}
+
+func TestValidateStorage_FutureEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	now := time.Now()
+	for _, e := range []entry.Entry{
+		{Timestamp: now.Add(-time.Hour), Description: "past", DurationMinutes: 60},
+		{Timestamp: now.AddDate(11, 0, 0), Description: "skewed", DurationMinutes: 30, Project: "acme"},
+		{Timestamp: now.Add(time.Hour), Description: "slightly ahead", DurationMinutes: 15},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	validateStorage(validateCmd)
+
+	output := stdout.String()
+	if !strings.Contains(output, "Entries dated in the future: 1") {
+		t.Errorf("Expected one future entry, got: %s", output)
+	}
+	if !strings.Contains(output, "[2] "+now.AddDate(11, 0, 0).Format("2006-01-02 15:04")+"  skewed [@acme]") {
+		t.Errorf("Expected the future entry to be listed with its index, got: %s", output)
+	}
+	if strings.Contains(output, "slightly ahead") {
+		t.Errorf("Entries less than a day ahead should not be reported, got: %s", output)
+	}
+	if !strings.Contains(output, "did edit 2 --timestamp") {
+		t.Errorf("Expected an edit hint, got: %s", output)
+	}
+	if strings.Contains(output, "Storage file is healthy") {
+		t.Errorf("Storage with future entries should not be reported healthy, got: %s", output)
+	}
+	if !strings.Contains(stderr.String(), "1 entry dated in the future") {
+		t.Errorf("Expected a future entry status, got: %s", stderr.String())
+	}
+}
+
+func TestCheckEntryNotInFuture(t *testing.T) {
+	tests := []struct {
+		name        string
+		offset      time.Duration
+		margin      int
+		allowFuture bool
+		wantOK      bool
+	}{
+		{"now", 0, 5, false, true},
+		{"within margin", 4 * time.Minute, 5, false, true},
+		{"beyond margin", 10 * time.Minute, 5, false, false},
+		{"beyond margin allowed", 10 * time.Minute, 5, true, true},
+		{"years ahead", 11 * 365 * 24 * time.Hour, 5, false, false},
+		{"larger margin", time.Hour, 90, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.FutureMarginMinutes = tt.margin
+			d, _, stderr := testDepsWithConfig(filepath.Join(t.TempDir(), "entries.jsonl"), cfg)
+			exitCode := 0
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+
+			ok := checkEntryNotInFuture(time.Now().Add(tt.offset), tt.allowFuture)
+			if ok != tt.wantOK {
+				t.Errorf("checkEntryNotInFuture() = %v, expected %v", ok, tt.wantOK)
+			}
+			if !tt.wantOK && (exitCode != 1 || !strings.Contains(stderr.String(), "--allow-future")) {
+				t.Errorf("Expected exit 1 with an --allow-future hint, got %d: %s", exitCode, stderr.String())
+			}
+		})
+	}
+}
+
+func TestCreateEntry_At(t *testing.T) {
+	loc := time.Now().Location()
+	past := time.Now().Add(-2 * time.Hour).In(loc).Truncate(time.Minute)
+	future := time.Now().Add(2 * time.Hour).In(loc).Truncate(time.Minute)
+	tests := []struct {
+		name        string
+		at          string
+		allowFuture bool
+		want        time.Time
+		wantErr     string
+	}{
+		{"backdated", past.Format("2006-01-02 15:04"), false, past, ""},
+		{"future rejected", future.Format("2006-01-02 15:04"), false, time.Time{}, "in the future"},
+		{"future allowed", future.Format(time.RFC3339), true, future, ""},
+		{"invalid", "yesterday-ish", false, time.Time{}, "Invalid --at time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			exitCode := 0
+			d, _, stderr := testDeps(storagePath)
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			_ = rootCmd.Flags().Set("at", tt.at)
+			_ = rootCmd.Flags().Set("allow-future", strconv.FormatBool(tt.allowFuture))
+			defer func() {
+				_ = rootCmd.Flags().Set("at", "")
+				_ = rootCmd.Flags().Set("allow-future", "false")
+			}()
+
+			createEntry(rootCmd, []string{"standup", "for", "15m"})
+
+			entries, _ := storage.ReadEntries(storagePath)
+			if tt.wantErr != "" {
+				if exitCode != 1 || !strings.Contains(stderr.String(), tt.wantErr) {
+					t.Errorf("Expected exit 1 with %q, got %d: %s", tt.wantErr, exitCode, stderr.String())
+				}
+				if len(entries) != 0 {
+					t.Errorf("Expected no entry to be written, got %d", len(entries))
+				}
+				return
+			}
+			if exitCode != 0 {
+				t.Fatalf("Unexpected error (exit %d): %s", exitCode, stderr.String())
+			}
+			if len(entries) != 1 || !entries[0].Timestamp.Equal(tt.want) {
+				t.Fatalf("Expected one entry dated %v, got %+v", tt.want, entries)
+			}
+			// Logged now, whatever --at says
+			if loggedAt := entries[0].LoggedAt; loggedAt == nil || time.Since(*loggedAt) > time.Minute {
+				t.Errorf("Expected the entry to record being logged now, got %v", loggedAt)
+			}
+		})
+	}
 }
 
 func TestValidateStorage_WithCorruption(t *testing.T) {
@@ -347,7 +707,7 @@ invalid json line
 	SetDeps(d)
 	defer ResetDeps()
 
-	validateStorage()
+	validateStorage(validateCmd)
 
 	output := stdout.String()
 	if !strings.Contains(output, "Corrupted entries: 1") {
@@ -358,6 +718,184 @@ invalid json line
 	}
 }
 
+func TestValidateStorage_JSON(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	content := `{"timestamp":"2024-01-15T10:00:00Z","description":"valid","duration_minutes":60,"raw_input":"valid for 1h"}
+invalid json line
+`
+	if err := os.WriteFile(storagePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	for _, strict := range []bool{false, true} {
+		d, stdout, _ := testDeps(storagePath)
+		exitCode := 0
+		d.Exit = func(code int) { exitCode = code }
+		SetDeps(d)
+		_ = validateCmd.Flags().Set("json", "true")
+		_ = validateCmd.Flags().Set("strict", strconv.FormatBool(strict))
+
+		validateStorage(validateCmd)
+
+		ResetDeps()
+		_ = validateCmd.Flags().Set("json", "false")
+		_ = validateCmd.Flags().Set("strict", "false")
+
+		var got struct {
+			ValidEntries     *int `json:"valid_entries"`
+			CorruptedEntries *int `json:"corrupted_entries"`
+			Warnings         []struct {
+				Line    int    `json:"line"`
+				Content string `json:"content"`
+				Error   string `json:"error"`
+			} `json:"warnings"`
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+			t.Fatalf("Expected JSON output, got %q: %v", stdout.String(), err)
+		}
+		if got.ValidEntries == nil || *got.ValidEntries != 1 || got.CorruptedEntries == nil || *got.CorruptedEntries != 1 {
+			t.Errorf("Expected 1 valid and 1 corrupted entry, got %s", stdout.String())
+		}
+		if len(got.Warnings) != 1 || got.Warnings[0].Line != 2 || got.Warnings[0].Content != "invalid json line" || got.Warnings[0].Error == "" {
+			t.Errorf("Expected a warning for line 2, got %s", stdout.String())
+		}
+		if wantExit := map[bool]int{false: 0, true: 1}[strict]; exitCode != wantExit {
+			t.Errorf("strict=%v: expected exit %d, got %d", strict, wantExit, exitCode)
+		}
+	}
+}
+
+func TestValidateStorage_SuspectEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	content := `{"timestamp":"2024-01-15T09:00:00Z","description":"valid","duration_minutes":60}
+{"timestamp":"2024-01-15T10:00:00Z","description":"imported","duration_minutes":-30}
+`
+	if err := os.WriteFile(storagePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	d, stdout, stderr := testDeps(storagePath)
+	d.Config.Timezone = "UTC"
+	SetDeps(d)
+	defer ResetDeps()
+
+	validateStorage(validateCmd)
+
+	output := stdout.String()
+	for _, expected := range []string{
+		"Suspect entries (zero or negative duration): 1",
+		"  [2] line 2: 2024-01-15 10:00  imported (-30 minutes)",
+		"Hint: Fix the duration with did edit 2 --duration 30m, or remove the entry with did delete 2",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "Storage file is healthy") {
+		t.Errorf("Expected no healthy status with a suspect entry, got: %s", output)
+	}
+	if !strings.Contains(stderr.String(), "Status: ⚠ Storage file has 1 entry with a zero or negative duration") {
+		t.Errorf("Expected a suspect status in stderr, got: %s", stderr.String())
+	}
+}
+
+func TestListEntries_SuspectEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	now := time.Now()
+	for _, e := range []entry.Entry{
+		{Timestamp: now.Add(-2 * time.Minute), Description: "valid task", DurationMinutes: 60},
+		{Timestamp: now.Add(-time.Minute), Description: "broken task", DurationMinutes: 0},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	rootCmd.Run(rootCmd, []string{})
+
+	output := stdout.String()
+	if strings.Contains(output, "broken task") {
+		t.Errorf("Expected the zero-duration entry to be left out, got: %s", output)
+	}
+	if !strings.Contains(output, "valid task") || !strings.Contains(output, "Total: 1h") {
+		t.Errorf("Expected the valid entry and a 1h total, got: %s", output)
+	}
+	if !strings.Contains(stderr.String(), "Warning: Left out 1 entry with a zero or negative duration") {
+		t.Errorf("Expected a suspect warning in stderr, got: %s", stderr.String())
+	}
+
+	// The entry keeps its index, so it can be fixed with edit
+	resetEditFlags()
+	defer resetEditFlags()
+	_ = editCmd.Flags().Set("duration", "30m")
+	editEntry(editCmd, []string{"2"})
+
+	entries, _ := storage.ReadEntries(storagePath)
+	if len(entries) != 2 || entries[1].DurationMinutes != 30 {
+		t.Errorf("Expected entry 2 to be fixed to 30 minutes, got: %+v", entries)
+	}
+}
+
+func TestSuspectWarnings_OnlyMatchingEntries(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	now := time.Now()
+	for _, e := range []entry.Entry{
+		{Timestamp: now.Add(-time.Minute), Description: "valid task", DurationMinutes: 60, Project: "acme"},
+		{Timestamp: now.AddDate(0, 0, -40), Description: "old broken task", DurationMinutes: 0, Project: "acme"},
+		{Timestamp: now.Add(-2 * time.Minute), Description: "other broken task", DurationMinutes: 0, Project: "globex"},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		run  func()
+	}{
+		{"listing", func() { rootCmd.Run(rootCmd, []string{"@acme"}) }},
+		{"stats", func() {
+			_ = rootCmd.PersistentFlags().Set("project", "acme")
+			runStats(statsCmd, []string{})
+		}},
+		{"report", func() {
+			_ = rootCmd.PersistentFlags().Set("project", "acme")
+			_ = reportCmd.Flags().Set("last", "7")
+			defer func() {
+				_ = rootCmd.PersistentFlags().Set("project", "")
+				_ = reportCmd.Flags().Set("last", "0")
+			}()
+			runReport(reportCmd, []string{})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			defer resetFilterFlags(rootCmd)
+
+			tt.run()
+
+			if !strings.Contains(stdout.String(), "valid task") && !strings.Contains(stdout.String(), "1h") {
+				t.Errorf("Expected the valid entry, got: %s", stdout.String())
+			}
+			if strings.Contains(stderr.String(), "Left out") {
+				t.Errorf("Expected no warning for suspect entries outside the matched set, got: %s", stderr.String())
+			}
+		})
+	}
+}
+
 func TestEditEntry_Success(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -569,6 +1107,45 @@ func TestEditEntry_InvalidDuration(t *testing.T) {
 	}
 }
 
+func TestEditEntry_NonPositiveDuration(t *testing.T) {
+	tests := []struct {
+		duration string
+		wantErr  string
+	}{
+		{"0m", "Duration must be greater than 0"},
+		{"-30m", "Invalid duration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.duration, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			testEntry := entry.Entry{Timestamp: time.Now(), Description: "test", DurationMinutes: 60, RawInput: "test for 1h"}
+			if err := storage.AppendEntry(storagePath, testEntry); err != nil {
+				t.Fatalf("Failed to create test entry: %v", err)
+			}
+
+			exitCode := 0
+			d, _, stderr := testDeps(storagePath)
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			resetEditFlags()
+			defer resetEditFlags()
+			_ = editCmd.Flags().Set("duration", tt.duration)
+
+			editEntry(editCmd, []string{"1"})
+
+			if exitCode != 1 || !strings.Contains(stderr.String(), tt.wantErr) {
+				t.Errorf("Expected exit 1 with %q, got exit %d: %s", tt.wantErr, exitCode, stderr.String())
+			}
+			entries, _ := storage.ReadEntries(storagePath)
+			if entries[0].DurationMinutes != 60 {
+				t.Errorf("Expected the duration to stay 60, got %d", entries[0].DurationMinutes)
+			}
+		})
+	}
+}
+
 func TestEditEntry_BothFlags(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -625,7 +1202,7 @@ func TestCreateEntry_StoragePathError(t *testing.T) {
 	SetDeps(d)
 	defer ResetDeps()
 
-	createEntry([]string{"test", "for", "1h"})
+	createEntry(rootCmd, []string{"test", "for", "1h"})
 
 	if !exitCalled {
 		t.Error("Expected exit to be called")
@@ -635,17 +1212,167 @@ func TestCreateEntry_StoragePathError(t *testing.T) {
 	}
 }
 
-func TestCreateEntry_AppendError(t *testing.T) {
-	// Use a path that will fail to write
-	storagePath := "/nonexistent/path/entries.jsonl"
-
-	exitCalled := false
-	d, _, stderr := testDeps(storagePath)
-	d.Exit = func(code int) { exitCalled = true }
-	SetDeps(d)
-	defer ResetDeps()
+// readOnlyDir returns a temp directory with mode 0555, made writable again
+// when the test finishes so it can be removed
+func readOnlyDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(dir, 0755) })
+	return dir
+}
 
-	createEntry([]string{"test", "for", "1h"})
+func TestListing_ReadOnlyStorageDirectory(t *testing.T) {
+	dir := t.TempDir()
+	storagePath := filepath.Join(dir, "entries.jsonl")
+	e := entry.Entry{Timestamp: time.Now(), Description: "api work", DurationMinutes: 60}
+	if err := storage.AppendEntry(storagePath, e); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+	_ = os.Remove(storage.GetLockPath(storagePath))
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(dir, 0755) })
+
+	d, stdout, stderr := testDeps(storagePath)
+	d.PrepareStorage = func(string) error {
+		t.Error("Listing should not prepare the storage for writing")
+		return nil
+	}
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+	_ = rootCmd.Flags().Set("this-week", "true")
+	defer resetTimePeriodFlags(rootCmd)
+
+	rootCmd.Run(rootCmd, []string{})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "api work (1h)") {
+		t.Errorf("Expected the entry to be listed, got: %s", stdout.String())
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("Expected listing to leave only the storage file, got %d files", len(files))
+	}
+}
+
+func TestListing_MissingStorageDirectory(t *testing.T) {
+	storagePath := filepath.Join(readOnlyDir(t), "did", "entries.jsonl")
+
+	d, stdout, stderr := testDeps(storagePath)
+	d.PrepareStorage = storage.EnsureStorageDir
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+
+	rootCmd.Run(rootCmd, []string{})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "No entries found for today") {
+		t.Errorf("Expected no entries, got: %s", stdout.String())
+	}
+	if _, err := os.Stat(filepath.Dir(storagePath)); !os.IsNotExist(err) {
+		t.Errorf("Expected the storage directory not to be created: %v", err)
+	}
+}
+
+func TestCreateEntry_CreatesStorageDirectory(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "nested", "did", "entries.jsonl")
+
+	d, stdout, stderr := testDeps(storagePath)
+	d.PrepareStorage = storage.EnsureStorageDir
+	SetDeps(d)
+	defer ResetDeps()
+
+	createEntry(rootCmd, []string{"api", "work", "for", "1h"})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Logged: api work (1h)") {
+		t.Errorf("Expected the entry to be logged, got: %s", stdout.String())
+	}
+	if entries, err := storage.ReadEntries(storagePath); err != nil || len(entries) != 1 {
+		t.Errorf("Expected 1 stored entry, got %d (%v)", len(entries), err)
+	}
+}
+
+func TestCreateEntry_PrepareStorageError(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "did", "entries.jsonl")
+
+	exitCode := 0
+	d, _, stderr := testDeps(storagePath)
+	d.Exit = func(code int) { exitCode = code }
+	d.PrepareStorage = func(path string) error {
+		return &os.PathError{Op: "mkdir", Path: filepath.Dir(path), Err: syscall.EROFS}
+	}
+	SetDeps(d)
+	defer ResetDeps()
+
+	createEntry(rootCmd, []string{"api", "work", "for", "1h"})
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	for _, expected := range []string{
+		"Error: Failed to create storage directory",
+		"read-only file system",
+		"not writable: " + storagePath,
+		"Set DID_STORE",
+	} {
+		if !strings.Contains(stderr.String(), expected) {
+			t.Errorf("Expected %q in stderr, got: %s", expected, stderr.String())
+		}
+	}
+}
+
+func TestPrintStorageWriteHint(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"read-only file system", &os.PathError{Op: "open", Path: "/data", Err: syscall.EROFS}, "Set DID_STORE"},
+		{"permission denied", &os.PathError{Op: "open", Path: "/data", Err: syscall.EACCES}, "Set DID_STORE"},
+		{"other error", errors.New("disk full"), "Check that the storage location exists and is writable: /data/entries.jsonl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _, stderr := testDeps("/data/entries.jsonl")
+			SetDeps(d)
+			defer ResetDeps()
+
+			printStorageWriteHint("/data/entries.jsonl", tt.err)
+
+			if !strings.Contains(stderr.String(), tt.expected) {
+				t.Errorf("Expected %q in hint, got: %s", tt.expected, stderr.String())
+			}
+		})
+	}
+}
+
+func TestCreateEntry_AppendError(t *testing.T) {
+	// Use a path that will fail to write
+	storagePath := "/nonexistent/path/entries.jsonl"
+
+	exitCalled := false
+	d, _, stderr := testDeps(storagePath)
+	d.Exit = func(code int) { exitCalled = true }
+	SetDeps(d)
+	defer ResetDeps()
+
+	createEntry(rootCmd, []string{"test", "for", "1h"})
 
 	if !exitCalled {
 		t.Error("Expected exit to be called")
@@ -671,7 +1398,7 @@ func TestListEntries_StoragePathError(t *testing.T) {
 	SetDeps(d)
 	defer ResetDeps()
 
-	listEntries(rootCmd, "today", func() (time.Time, time.Time) {
+	listEntries(rootCmd, "today", func(time.Time) (time.Time, time.Time) {
 		now := time.Now()
 		return now, now.Add(24 * time.Hour)
 	})
@@ -701,7 +1428,7 @@ invalid json line
 	SetDeps(d)
 	defer ResetDeps()
 
-	listEntries(rootCmd, "today", func() (time.Time, time.Time) {
+	listEntries(rootCmd, "today", func(time.Time) (time.Time, time.Time) {
 		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 		end := start.Add(24 * time.Hour)
 		return start, end
@@ -733,7 +1460,7 @@ func TestValidateStorage_StoragePathError(t *testing.T) {
 	SetDeps(d)
 	defer ResetDeps()
 
-	validateStorage()
+	validateStorage(validateCmd)
 
 	if !exitCalled {
 		t.Error("Expected exit to be called")
@@ -788,16 +1515,15 @@ func TestExecute(t *testing.T) {
 }
 
 func TestListEntries_ReadEntriesError(t *testing.T) {
-	// Use a directory instead of a file to cause read error
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
 	exitCalled := false
-	d, _, stderr := testDeps(tmpDir)
+	d, _, stderr := testDeps(storagePath)
 	d.Exit = func(code int) { exitCalled = true }
 	SetDeps(d)
 	defer ResetDeps()
 
-	listEntries(rootCmd, "today", func() (time.Time, time.Time) {
+	listEntries(rootCmd, "today", func(time.Time) (time.Time, time.Time) {
 		now := time.Now()
 		return now, now.Add(24 * time.Hour)
 	})
@@ -811,16 +1537,15 @@ func TestListEntries_ReadEntriesError(t *testing.T) {
 }
 
 func TestValidateStorage_ValidateError(t *testing.T) {
-	// Use a directory instead of a file to cause validation error
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
 	exitCalled := false
-	d, _, stderr := testDeps(tmpDir)
+	d, _, stderr := testDeps(storagePath)
 	d.Exit = func(code int) { exitCalled = true }
 	SetDeps(d)
 	defer ResetDeps()
 
-	validateStorage()
+	validateStorage(validateCmd)
 
 	if !exitCalled {
 		t.Error("Expected exit to be called")
@@ -871,11 +1596,10 @@ func TestEditEntry_UpdateError(t *testing.T) {
 }
 
 func TestEditEntry_ReadEntriesWithWarningsError(t *testing.T) {
-	// Use a directory instead of a file to cause read error
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
 	exitCalled := false
-	d, _, stderr := testDeps(tmpDir)
+	d, _, stderr := testDeps(storagePath)
 	d.Exit = func(code int) { exitCalled = true }
 	SetDeps(d)
 	defer ResetDeps()
@@ -1693,7 +2417,7 @@ func TestPrevWeek_WithProjectAndTagFilters(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Create entries for last week
-	lastWeekStart, _ := timeutil.LastWeek()
+	lastWeekStart, _ := timeutil.LastWeek(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       lastWeekStart.Add(24 * time.Hour),
@@ -1775,7 +2499,7 @@ func TestPrevWeek_WithShorthandProjectAndTagFilters(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Create entries for last week
-	lastWeekStart, _ := timeutil.LastWeek()
+	lastWeekStart, _ := timeutil.LastWeek(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       lastWeekStart.Add(24 * time.Hour),
@@ -1929,6 +2653,187 @@ func TestValidate_Command(t *testing.T) {
 	}
 }
 
+func TestValidate_Compare(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2024, 1, 15, hour, 0, 0, 0, time.UTC)
+	}
+	standup := entry.Entry{Timestamp: at(9), Description: "standup", DurationMinutes: 15}
+	review := entry.Entry{Timestamp: at(10), Description: "review", DurationMinutes: 30, Project: "acme"}
+	reviewTagged := review
+	reviewTagged.Tags = []string{"pr"}
+	deploy := entry.Entry{Timestamp: at(11), Description: "deploy", DurationMinutes: 20}
+
+	write := func(entries ...entry.Entry) string {
+		path := filepath.Join(t.TempDir(), "entries.jsonl")
+		if err := storage.WriteEntries(path, entries); err != nil {
+			t.Fatalf("Failed to create test entries: %v", err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name     string
+		storage  []entry.Entry
+		other    []entry.Entry
+		expected []string
+		status   string
+	}{
+		{
+			name:     "same entries",
+			storage:  []entry.Entry{standup, review},
+			other:    []entry.Entry{review, standup},
+			expected: []string{"  2 active entries, " + storage.Checksum([]entry.Entry{standup, review}), "Status: ✓ Both files have the same entries"},
+		},
+		{
+			name:    "different entries",
+			storage: []entry.Entry{standup, review},
+			other:   []entry.Entry{reviewTagged, deploy},
+			expected: []string{
+				"Only in storage: 1\n  2024-01-15 09:00  standup  (15m)\n",
+				"Only in other: 1\n  2024-01-15 11:00  deploy  (20m)\n",
+				"Different in each file: 1\n  storage: 2024-01-15 10:00  review [@acme]  (30m)\n  other:   2024-01-15 10:00  review [@acme #pr]  (30m)\n",
+			},
+			status: "Status: ⚠ The files differ: 1 only in storage, 1 only in other, 1 different",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, stderr := testDeps(write(tt.storage...))
+			d.Config.Timezone = "UTC"
+			SetDeps(d)
+			defer ResetDeps()
+
+			compareStorage(write(tt.other...))
+
+			for _, expected := range tt.expected {
+				if !strings.Contains(stdout.String(), expected) {
+					t.Errorf("Expected %q in output, got: %s", expected, stdout.String())
+				}
+			}
+			if !strings.Contains(stderr.String(), tt.status) {
+				t.Errorf("Expected %q on stderr, got: %s", tt.status, stderr.String())
+			}
+		})
+	}
+}
+
+func TestValidate_CompareMissingFile(t *testing.T) {
+	d, _, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+
+	compareStorage(filepath.Join(t.TempDir(), "missing.jsonl"))
+
+	if exitCode != 1 || !strings.Contains(stderr.String(), "Error: Failed to read the file to compare with") {
+		t.Errorf("Expected an error for a missing file, got exit %d: %s", exitCode, stderr.String())
+	}
+}
+
+func TestValidateStorage_WithProjectFilter(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	entries := []entry.Entry{
+		{Timestamp: time.Date(2024, 3, 1, 9, 0, 0, 0, time.Local), Description: "a", DurationMinutes: 60, RawInput: "a @acme for 1h", Project: "acme"},
+		{Timestamp: time.Date(2024, 3, 5, 9, 0, 0, 0, time.Local), Description: "b", DurationMinutes: 30, RawInput: "b @acme for 30m", Project: "acme"},
+		{Timestamp: time.Date(2024, 3, 3, 9, 0, 0, 0, time.Local), Description: "c", DurationMinutes: 45, RawInput: "c @other for 45m", Project: "other"},
+	}
+	for _, e := range entries {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	f, _ := os.OpenFile(storagePath, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = f.WriteString("corrupted line\n")
+	_ = f.Close()
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	resetFilterFlags(rootCmd)
+	defer resetFilterFlags(rootCmd)
+	_ = rootCmd.PersistentFlags().Set("project", "acme")
+
+	validateStorage(validateCmd)
+
+	output := stdout.String()
+	expected := []string{
+		"Valid entries:     3",
+		"Corrupted entries: 1",
+		"Filtered (@acme):",
+		"Matching entries:  2",
+		"Date span:         Fri Mar 1 - Tue Mar 5, 2024",
+		"Total time:        1h 30m (90 minutes)",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q, got: %s", exp, output)
+		}
+	}
+	if !strings.Contains(stderr.String(), "1 corrupted line") {
+		t.Errorf("Expected global corruption status, got: %s", stderr.String())
+	}
+}
+
+func TestValidateStorage_WithTagFilterNoMatches(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	testEntry := entry.Entry{Timestamp: time.Now(), Description: "test", DurationMinutes: 60, RawInput: "test for 1h"}
+	if err := storage.AppendEntry(storagePath, testEntry); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	resetFilterFlags(rootCmd)
+	defer resetFilterFlags(rootCmd)
+
+	validateCmd.Run(validateCmd, []string{"#missing"})
+
+	output := stdout.String()
+	if !strings.Contains(output, "Filtered (#missing):") {
+		t.Errorf("Expected filtered subsection header, got: %s", output)
+	}
+	if !strings.Contains(output, "Matching entries:  0") {
+		t.Errorf("Expected zero matching entries, got: %s", output)
+	}
+	if strings.Contains(output, "Date span:") {
+		t.Errorf("Should not show date span without matches, got: %s", output)
+	}
+	if !strings.Contains(output, "Storage file is healthy") {
+		t.Errorf("Expected healthy status, got: %s", output)
+	}
+}
+
+func TestValidateStorage_NoFiltersOmitsSubsection(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	testEntry := entry.Entry{Timestamp: time.Now(), Description: "test", DurationMinutes: 60, RawInput: "test for 1h", Project: "acme"}
+	if err := storage.AppendEntry(storagePath, testEntry); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	resetFilterFlags(rootCmd)
+
+	validateStorage(validateCmd)
+
+	if strings.Contains(stdout.String(), "Filtered") {
+		t.Errorf("Should not show filtered subsection without filters, got: %s", stdout.String())
+	}
+}
+
 func TestFormatProjectAndTags(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1978,6 +2883,12 @@ func TestFormatProjectAndTags(t *testing.T) {
 			tags:     []string{"bugfix", "urgent", "frontend"},
 			expected: "@acme #bugfix #urgent #frontend",
 		},
+		{
+			name:     "multi-word names are quoted",
+			project:  "Big Client",
+			tags:     []string{"code review", "urgent"},
+			expected: `@"Big Client" #"code review" #urgent`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -2038,6 +2949,30 @@ func TestFormatEntryForLog(t *testing.T) {
 	}
 }
 
+func TestDisplayProject(t *testing.T) {
+	tests := []struct {
+		name     string
+		e        entry.Entry
+		expected string
+	}{
+		{"none", entry.Entry{}, ""},
+		{"project", entry.Entry{Project: "backend"}, "backend"},
+		{"client and project", entry.Entry{Project: "backend", Client: "AcmeCorp"}, "AcmeCorp/backend"},
+		{"client only", entry.Entry{Client: "AcmeCorp"}, "AcmeCorp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := displayProject(tt.e); got != tt.expected {
+				t.Errorf("displayProject() = %q, expected %q", got, tt.expected)
+			}
+			if got := formatEntryForLog("api work", displayProject(tt.e), nil); strings.Contains(got, "/]") {
+				t.Errorf("formatEntryForLog() = %q, expected no dangling separator", got)
+			}
+		})
+	}
+}
+
 func TestEditEntry_DescriptionWithProjectAndTags(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -2186,6 +3121,43 @@ func TestEditEntry_RemoveProjectAndTags(t *testing.T) {
 	}
 }
 
+func TestEditEntry_Client(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	testEntry := entry.Entry{Timestamp: time.Now(), Description: "api work", DurationMinutes: 60, Project: "backend"}
+	if err := storage.AppendEntry(storagePath, testEntry); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+
+	for _, tt := range []struct {
+		client   string
+		expected string
+	}{
+		{"AcmeCorp", "Updated entry 1: api work [@AcmeCorp/backend] (1h)\n  client: (none) → AcmeCorp\n"},
+		{"", "Updated entry 1: api work [@backend] (1h)\n  client: AcmeCorp → (none)\n"},
+	} {
+		d, stdout, stderr := testDeps(storagePath)
+		SetDeps(d)
+		_ = editCmd.Flags().Set("client", tt.client)
+
+		editEntry(editCmd, []string{"1"})
+
+		_ = editCmd.Flags().Set("client", "")
+		editCmd.Flags().Lookup("client").Changed = false
+		ResetDeps()
+
+		if stderr.Len() > 0 {
+			t.Fatalf("Unexpected stderr: %s", stderr.String())
+		}
+		if stdout.String() != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, stdout.String())
+		}
+		entries, _ := storage.ReadEntries(storagePath)
+		if entries[0].Client != tt.client || entries[0].Project != "backend" {
+			t.Errorf("Expected client %q and project backend, got %+v", tt.client, entries[0])
+		}
+	}
+}
+
 func TestEditEntry_AddProjectAndTagsToExisting(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -2274,7 +3246,7 @@ func TestCreateEntry_WithProject(t *testing.T) {
 	SetDeps(d)
 	defer ResetDeps()
 
-	createEntry([]string{"fix", "bug", "@acme", "for", "2h"})
+	createEntry(rootCmd, []string{"fix", "bug", "@acme", "for", "2h"})
 
 	if stderr.Len() > 0 {
 		t.Errorf("Unexpected stderr output: %s", stderr.String())
@@ -2321,7 +3293,7 @@ func TestCreateEntry_WithTags(t *testing.T) {
 	SetDeps(d)
 	defer ResetDeps()
 
-	createEntry([]string{"fix", "bug", "#bugfix", "#urgent", "for", "1h30m"})
+	createEntry(rootCmd, []string{"fix", "bug", "#bugfix", "#urgent", "for", "1h30m"})
 
 	if stderr.Len() > 0 {
 		t.Errorf("Unexpected stderr output: %s", stderr.String())
@@ -2375,7 +3347,7 @@ func TestCreateEntry_WithProjectAndTags(t *testing.T) {
 	SetDeps(d)
 	defer ResetDeps()
 
-	createEntry([]string{"implement", "feature", "@clientco", "#feature", "#priority", "for", "3h"})
+	createEntry(rootCmd, []string{"implement", "feature", "@clientco", "#feature", "#priority", "for", "3h"})
 
 	if stderr.Len() > 0 {
 		t.Errorf("Unexpected stderr output: %s", stderr.String())
@@ -2424,63 +3396,143 @@ func TestCreateEntry_WithProjectAndTags(t *testing.T) {
 	}
 }
 
-func TestCreateEntry_WithoutProjectOrTags_BackwardCompat(t *testing.T) {
-	tmpDir := t.TempDir()
-	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+func TestCreateEntry_MultiWordProjectAndTag(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
 
 	d, stdout, stderr := testDeps(storagePath)
 	SetDeps(d)
 	defer ResetDeps()
 
-	createEntry([]string{"simple", "task", "for", "45m"})
+	// The shell has removed the quotes of: did sync @"Big Client" #"time for fun" for 1h
+	createEntry(rootCmd, []string{"sync", "@Big Client", "#time for fun", "for", "1h"})
 
 	if stderr.Len() > 0 {
-		t.Errorf("Unexpected stderr output: %s", stderr.String())
-	}
-
-	output := stdout.String()
-	if !strings.Contains(output, "Logged:") {
-		t.Errorf("Expected 'Logged:' in output, got: %s", output)
-	}
-	if !strings.Contains(output, "simple task") {
-		t.Errorf("Expected 'simple task' in output, got: %s", output)
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
 	}
-	// Output should NOT contain project/tag brackets when neither is present
-	if strings.Contains(output, "[@") || strings.Contains(output, "[#") {
-		t.Errorf("Did not expect project/tag brackets in output, got: %s", output)
+	if !strings.Contains(stdout.String(), `Logged: sync @"Big Client" #"time for fun" (1h)`) {
+		t.Errorf("Expected the quoted names in output, got: %s", stdout.String())
 	}
 
-	// Verify entry was written without project or tags
 	entries, err := storage.ReadEntries(storagePath)
 	if err != nil {
 		t.Fatalf("Failed to read entries: %v", err)
 	}
 	if len(entries) != 1 {
-		t.Errorf("Expected 1 entry, got %d", len(entries))
-	}
-	if entries[0].Description != "simple task" {
-		t.Errorf("Expected description 'simple task', got: %s", entries[0].Description)
-	}
-	if entries[0].Project != "" {
-		t.Errorf("Expected empty project, got: %s", entries[0].Project)
-	}
-	if len(entries[0].Tags) != 0 {
-		t.Errorf("Expected no tags, got: %v", entries[0].Tags)
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
 	}
-	if entries[0].DurationMinutes != 45 {
-		t.Errorf("Expected duration 45 minutes, got: %d", entries[0].DurationMinutes)
+	e := entries[0]
+	if e.Description != "sync" || e.Project != "Big Client" || len(e.Tags) != 1 || e.Tags[0] != "time for fun" || e.DurationMinutes != 60 {
+		t.Errorf("Expected sync @Big Client #time for fun (60m), got %+v", e)
 	}
 }
 
-func TestCreateEntry_VerifyJSONLStorage(t *testing.T) {
-	tmpDir := t.TempDir()
-	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+func TestCreateEntry_LiteralHash(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		noTags      bool
+		description string
+		tags        []string
+	}{
+		{"no tags flag", []string{"fixed", "issue", "#1234", "@acme", "for", "1h"}, true, "fixed issue #1234", nil},
+		{"escaped hash", []string{"fixed", "issue", `\#1234`, "#bugfix", "for", "1h"}, false, "fixed issue #1234", []string{"bugfix"}},
+		{"parsed as tag", []string{"fixed", "issue", "#1234", "for", "1h"}, false, "fixed issue", []string{"1234"}},
+	}
 
-	d, _, _ := testDeps(storagePath)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			d, _, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			defer func() { _ = rootCmd.Flags().Set("no-tags", "false") }()
+			_ = rootCmd.Flags().Set("no-tags", strconv.FormatBool(tt.noTags))
+
+			createEntry(rootCmd, tt.args)
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr output: %s", stderr.String())
+			}
+			entries, err := storage.ReadEntries(storagePath)
+			if err != nil || len(entries) != 1 {
+				t.Fatalf("Expected 1 entry, got %d (%v)", len(entries), err)
+			}
+			e := entries[0]
+			if e.Description != tt.description || !reflect.DeepEqual(e.Tags, tt.tags) || e.DurationMinutes != 60 {
+				t.Errorf("Expected %q with tags %v (60m), got %+v", tt.description, tt.tags, e)
+			}
+			if tt.noTags && e.Project != "acme" {
+				t.Errorf("Expected --no-tags to still parse the project, got %q", e.Project)
+			}
+		})
+	}
+}
+
+func TestQuoteShorthandArgs(t *testing.T) {
+	got := quoteShorthandArgs([]string{"review", "@acme", "@Big  Client", `#"code review"`, "#", "for", "1h"})
+	expected := []string{"review", "@acme", `@"Big Client"`, `#"code review"`, "#", "for", "1h"}
+	if strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("quoteShorthandArgs() = %q, expected %q", got, expected)
+	}
+}
+
+func TestCreateEntry_WithoutProjectOrTags_BackwardCompat(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	createEntry(rootCmd, []string{"simple", "task", "for", "45m"})
+
+	if stderr.Len() > 0 {
+		t.Errorf("Unexpected stderr output: %s", stderr.String())
+	}
+
+	output := stdout.String()
+	if !strings.Contains(output, "Logged:") {
+		t.Errorf("Expected 'Logged:' in output, got: %s", output)
+	}
+	if !strings.Contains(output, "simple task") {
+		t.Errorf("Expected 'simple task' in output, got: %s", output)
+	}
+	// Output should NOT contain project/tag brackets when neither is present
+	if strings.Contains(output, "[@") || strings.Contains(output, "[#") {
+		t.Errorf("Did not expect project/tag brackets in output, got: %s", output)
+	}
+
+	// Verify entry was written without project or tags
+	entries, err := storage.ReadEntries(storagePath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Description != "simple task" {
+		t.Errorf("Expected description 'simple task', got: %s", entries[0].Description)
+	}
+	if entries[0].Project != "" {
+		t.Errorf("Expected empty project, got: %s", entries[0].Project)
+	}
+	if len(entries[0].Tags) != 0 {
+		t.Errorf("Expected no tags, got: %v", entries[0].Tags)
+	}
+	if entries[0].DurationMinutes != 45 {
+		t.Errorf("Expected duration 45 minutes, got: %d", entries[0].DurationMinutes)
+	}
+}
+
+func TestCreateEntry_VerifyJSONLStorage(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	d, _, _ := testDeps(storagePath)
 	SetDeps(d)
 	defer ResetDeps()
 
-	createEntry([]string{"review", "code", "@acme", "#code-review", "for", "1h"})
+	createEntry(rootCmd, []string{"review", "code", "@acme", "#code-review", "for", "1h"})
 
 	// Read raw JSONL file to verify correct JSON encoding
 	content, err := os.ReadFile(storagePath)
@@ -2514,7 +3566,7 @@ func TestCreateEntry_OnlyProjectTagsError(t *testing.T) {
 	defer ResetDeps()
 
 	// Create entry with only @project/#tags (no actual description)
-	createEntry([]string{"@acme", "#bugfix", "for", "1h"})
+	createEntry(rootCmd, []string{"@acme", "#bugfix", "for", "1h"})
 
 	if !exitCalled {
 		t.Error("Expected exit to be called for empty description")
@@ -2533,7 +3585,7 @@ func TestCreateEntry_ProjectAndTagsInMiddle(t *testing.T) {
 	defer ResetDeps()
 
 	// Test with @project and #tags in the middle of description
-	createEntry([]string{"fix", "@acme", "bug", "#bugfix", "in", "login", "for", "2h"})
+	createEntry(rootCmd, []string{"fix", "@acme", "bug", "#bugfix", "in", "login", "for", "2h"})
 
 	if stderr.Len() > 0 {
 		t.Errorf("Unexpected stderr output: %s", stderr.String())
@@ -2572,7 +3624,7 @@ func TestCreateEntry_MultipleTags(t *testing.T) {
 	SetDeps(d)
 	defer ResetDeps()
 
-	createEntry([]string{"deploy", "app", "#deploy", "#production", "#release-v1", "for", "30m"})
+	createEntry(rootCmd, []string{"deploy", "app", "#deploy", "#production", "#release-v1", "for", "30m"})
 
 	if stderr.Len() > 0 {
 		t.Errorf("Unexpected stderr output: %s", stderr.String())
@@ -2631,7 +3683,7 @@ func TestListEntries_WithProject(t *testing.T) {
 	// Reset filter flags to avoid contamination from other tests
 	resetFilterFlags(rootCmd)
 
-	listEntries(rootCmd, "today", func() (time.Time, time.Time) {
+	listEntries(rootCmd, "today", func(time.Time) (time.Time, time.Time) {
 		now := time.Now()
 		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 		end := start.Add(24 * time.Hour)
@@ -2675,7 +3727,7 @@ func TestListEntries_WithTags(t *testing.T) {
 	// Reset filter flags to avoid contamination from other tests
 	resetFilterFlags(rootCmd)
 
-	listEntries(rootCmd, "today", func() (time.Time, time.Time) {
+	listEntries(rootCmd, "today", func(time.Time) (time.Time, time.Time) {
 		now := time.Now()
 		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 		end := start.Add(24 * time.Hour)
@@ -2723,7 +3775,7 @@ func TestListEntries_WithProjectAndTags(t *testing.T) {
 	// Reset filter flags to avoid contamination from other tests
 	resetFilterFlags(rootCmd)
 
-	listEntries(rootCmd, "today", func() (time.Time, time.Time) {
+	listEntries(rootCmd, "today", func(time.Time) (time.Time, time.Time) {
 		now := time.Now()
 		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 		end := start.Add(24 * time.Hour)
@@ -2773,7 +3825,7 @@ func TestListEntries_BackwardCompatibility_NoProjectOrTags(t *testing.T) {
 	// Reset filter flags to avoid contamination from other tests
 	resetFilterFlags(rootCmd)
 
-	listEntries(rootCmd, "today", func() (time.Time, time.Time) {
+	listEntries(rootCmd, "today", func(time.Time) (time.Time, time.Time) {
 		now := time.Now()
 		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 		end := start.Add(24 * time.Hour)
@@ -2848,7 +3900,7 @@ func TestListEntries_MixedEntriesWithAndWithoutProjectTags(t *testing.T) {
 	// Reset filter flags to avoid contamination from other tests
 	resetFilterFlags(rootCmd)
 
-	listEntries(rootCmd, "today", func() (time.Time, time.Time) {
+	listEntries(rootCmd, "today", func(time.Time) (time.Time, time.Time) {
 		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 		end := start.Add(24 * time.Hour)
 		return start, end
@@ -3231,12 +4283,12 @@ func TestEditEntry_Integration_ChangeProjectAndTags(t *testing.T) {
 	if !strings.Contains(output, "#priority") {
 		t.Errorf("Expected '#priority' in output, got: %s", output)
 	}
-	// Old values should not appear
-	if strings.Contains(output, "@oldproject") {
-		t.Errorf("Did not expect '@oldproject' in output after change, got: %s", output)
+	// Old values only appear in the diff
+	if !strings.Contains(output, "  project: @oldproject → @newclient\n") {
+		t.Errorf("Expected the project change in output, got: %s", output)
 	}
-	if strings.Contains(output, "#oldtag") {
-		t.Errorf("Did not expect '#oldtag' in output after change, got: %s", output)
+	if !strings.Contains(output, "  tags: #oldtag → #feature #priority\n") {
+		t.Errorf("Expected the tag change in output, got: %s", output)
 	}
 
 	// Verify JSONL storage contains new values
@@ -3524,6 +4576,13 @@ func TestParseShorthandFilters(t *testing.T) {
 			expectedTags:      []string{},
 			expectedRemaining: []string{"@project1", "@project2"},
 		},
+		{
+			name:              "multi-word names",
+			args:              []string{"@Big Client", `#"code review"`},
+			expectedProject:   "Big Client",
+			expectedTags:      []string{"code review"},
+			expectedRemaining: []string{"@Big Client", `#"code review"`},
+		},
 	}
 
 	for _, tt := range tests {
@@ -3737,6 +4796,52 @@ func TestRootCommand_WithShorthandProjectFilter(t *testing.T) {
 	}
 }
 
+func TestRootCommand_WithMultiWordProjectFilter(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	today := time.Now()
+	for _, e := range []entry.Entry{
+		{Timestamp: today, Description: "planning", DurationMinutes: 60, Project: "time for fun"},
+		{Timestamp: today, Description: "work on other", DurationMinutes: 30},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+	defer resetFilterFlags(rootCmd)
+
+	// The "for" inside the project name doesn't make this an entry to create
+	rootCmd.Run(rootCmd, []string{"@time for fun"})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	output := stdout.String()
+	if !strings.Contains(output, "planning") || strings.Contains(output, "work on other") {
+		t.Errorf("Expected only the 'time for fun' entry, got: %s", output)
+	}
+	if !strings.Contains(output, `planning [@"time for fun"]`) {
+		t.Errorf("Expected the project quoted in the listing, got: %s", output)
+	}
+
+	// The same with --project
+	d, stdout, _ = testDeps(storagePath)
+	SetDeps(d)
+	resetFilterFlags(rootCmd)
+	_ = rootCmd.PersistentFlags().Set("project", "time for fun")
+
+	rootCmd.Run(rootCmd, []string{})
+
+	if output := stdout.String(); !strings.Contains(output, "planning") || strings.Contains(output, "work on other") {
+		t.Errorf("Expected only the 'time for fun' entry with --project, got: %s", output)
+	}
+}
+
 func TestRootCommand_WithTagFilter(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -4186,6 +5291,91 @@ func TestLastFlag_ValidDays(t *testing.T) {
 	}
 }
 
+func TestLastFlag_Suffixes(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	now := time.Now()
+	for _, e := range []entry.Entry{
+		{Timestamp: now, Description: "today work", DurationMinutes: 30},
+		{Timestamp: now.AddDate(0, 0, -10), Description: "ten days ago", DurationMinutes: 60},
+		{Timestamp: now.AddDate(0, 0, -20), Description: "twenty days ago", DurationMinutes: 90},
+		{Timestamp: now.AddDate(0, -2, -5), Description: "over two months ago", DurationMinutes: 120},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	tests := []struct {
+		last     string
+		header   string
+		included []string
+		excluded []string
+	}{
+		{"7", "last 7 days", []string{"today work"}, []string{"ten days ago"}},
+		{"2w", "last 2 weeks", []string{"today work", "ten days ago"}, []string{"twenty days ago"}},
+		{"1w", "last 1 week", []string{"today work"}, []string{"ten days ago"}},
+		{"2m", "last 2 months", []string{"ten days ago", "twenty days ago"}, []string{"over two months ago"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.last, func(t *testing.T) {
+			d, stdout, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			resetTimePeriodFlags(rootCmd)
+			resetFilterFlags(rootCmd)
+			defer resetTimePeriodFlags(rootCmd)
+			_ = rootCmd.Flags().Set("last", tt.last)
+
+			rootCmd.Run(rootCmd, []string{})
+
+			output := stdout.String()
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr: %s", stderr.String())
+			}
+			if !strings.HasPrefix(output, "Entries for "+tt.header+" (") {
+				t.Errorf("Expected header for %q, got: %s", tt.header, output)
+			}
+			for _, desc := range tt.included {
+				if !strings.Contains(output, desc) {
+					t.Errorf("Expected %q in output, got: %s", desc, output)
+				}
+			}
+			for _, desc := range tt.excluded {
+				if strings.Contains(output, desc) {
+					t.Errorf("Should not show %q, got: %s", desc, output)
+				}
+			}
+		})
+	}
+}
+
+func TestLastFlag_InvalidValue(t *testing.T) {
+	exitCalled := false
+	d, stdout, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	d.Exit = func(code int) { exitCalled = true }
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+	defer resetTimePeriodFlags(rootCmd)
+
+	_ = rootCmd.Flags().Set("last", "2d")
+	rootCmd.Run(rootCmd, []string{})
+
+	if !exitCalled {
+		t.Error("Expected exit to be called for an invalid --last value")
+	}
+	expected := "Error: Invalid --last value: \"2d\"\nUse a number of days (7), weeks (2w) or calendar months (3m)\n"
+	if stderr.String() != expected {
+		t.Errorf("Expected stderr %q, got %q", expected, stderr.String())
+	}
+	if stdout.Len() > 0 {
+		t.Errorf("Expected no output, got %q", stdout.String())
+	}
+}
+
 func TestDateFlag_SpecificDate(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -4315,6 +5505,99 @@ func TestFromToFlags_DateRange(t *testing.T) {
 	}
 }
 
+func TestFromToFlags_Header(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	for _, e := range []entry.Entry{
+		{Timestamp: time.Date(2023, 12, 30, 10, 0, 0, 0, time.Local), Description: "year end", DurationMinutes: 60, Project: "acme", Tags: []string{"urgent"}},
+		{Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local), Description: "fix login", DurationMinutes: 90, Project: "acme", Tags: []string{"urgent"}},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		args     []string
+		expected string
+	}{
+		{"same year with filters", "2024-01-14", "2024-01-21", []string{"@acme", "#urgent"}, "Entries for Sun Jan 14 - Sun Jan 21, 2024 (@acme #urgent):"},
+		{"across years", "2023-12-28", "2024-01-21", nil, "Entries for Thu Dec 28, 2023 - Sun Jan 21, 2024:"},
+		{"single day", "2024-01-15", "2024-01-15", nil, "Entries for Mon, Jan 15, 2024:"},
+		{"to only", "", "2024-01-21", []string{"@acme"}, "Entries for until Jan 21, 2024 (@acme):"},
+		{"no entries", "2024-02-01", "2024-02-07", []string{"#urgent"}, "No entries found for Thu Feb 1 - Wed Feb 7, 2024 (#urgent)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			resetTimePeriodFlags(rootCmd)
+			resetFilterFlags(rootCmd)
+			defer resetTimePeriodFlags(rootCmd)
+			defer resetFilterFlags(rootCmd)
+			_ = rootCmd.Flags().Set("from", tt.from)
+			_ = rootCmd.Flags().Set("to", tt.to)
+
+			rootCmd.Run(rootCmd, tt.args)
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr: %s", stderr.String())
+			}
+			if header, _, _ := strings.Cut(stdout.String(), "\n"); header != tt.expected {
+				t.Errorf("Expected header %q, got %q", tt.expected, header)
+			}
+		})
+	}
+}
+
+func TestFromToFlags_WeekdayPrefix(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+	for _, e := range []entry.Entry{
+		{Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local), Description: "fix login", DurationMinutes: 90},
+		{Timestamp: time.Date(2024, 1, 16, 9, 30, 0, 0, time.Local), Description: "standup", DurationMinutes: 15},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected []string
+	}{
+		{"multiple days", "2024-01-14", "2024-01-21", []string{"] Mon 2024-01-15 10:00  fix login (1h 30m)", "] Tue 2024-01-16 09:30  standup (15m)"}},
+		{"single day", "2024-01-15", "2024-01-15", []string{"] 10:00  fix login (1h 30m)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, _ := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			resetTimePeriodFlags(rootCmd)
+			defer resetTimePeriodFlags(rootCmd)
+			_ = rootCmd.Flags().Set("from", tt.from)
+			_ = rootCmd.Flags().Set("to", tt.to)
+
+			rootCmd.Run(rootCmd, nil)
+
+			for _, line := range tt.expected {
+				if !strings.Contains(stdout.String(), line+"\n") {
+					t.Errorf("Expected %q, got:\n%s", line, stdout.String())
+				}
+			}
+		})
+	}
+}
+
 func TestTimePeriodFlags_MutualExclusivity(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -4342,6 +5625,72 @@ func TestTimePeriodFlags_MutualExclusivity(t *testing.T) {
 	}
 }
 
+func TestTimePeriodFlags_Aliases(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		err      error
+	}{
+		{"--last-week", []string{"--last-week"}, "previous week", nil},
+		{"--last-month", []string{"--last-month"}, "previous month", nil},
+		{"--week", []string{"--week"}, "this week", nil},
+		{"--month", []string{"--month"}, "this month", nil},
+		{"alias and flag", []string{"--prev-week", "--last-week"}, "previous week", nil},
+		{"alias and other period", []string{"--week", "--last-month"}, "", query.ErrConflictingPeriods},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test"}
+			addTimePeriodFlags(cmd, "List")
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags(%v) returned unexpected error: %v", tt.args, err)
+			}
+
+			c, err := query.Resolve(cmd, config.DefaultConfig())
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Resolve() error = %v, expected %v", err, tt.err)
+			}
+			if c.Period.Name != tt.expected {
+				t.Errorf("Period = %q, expected %q", c.Period.Name, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAddPeriodFlagAliases_KeepsOwnFlags(t *testing.T) {
+	// Like stats, which has a --month flag of its own
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().Bool("month", false, "Own month flag")
+	addTimePeriodFlags(cmd, "List")
+
+	if err := cmd.ParseFlags([]string{"--month"}); err != nil {
+		t.Fatalf("ParseFlags() returned unexpected error: %v", err)
+	}
+	if month, _ := cmd.Flags().GetBool("month"); !month {
+		t.Error("Expected --month to set the command's own flag")
+	}
+	if thisMonth, _ := cmd.Flags().GetBool("this-month"); thisMonth {
+		t.Error("Expected --this-month to be left unset")
+	}
+
+	// Help and completion only show the real flags
+	defined := make(map[string]bool)
+	cmd.Flags().VisitAll(func(f *pflag.Flag) { defined[f.Name] = true })
+	for _, alias := range []string{"week", "last-week", "last-month"} {
+		if defined[alias] {
+			t.Errorf("Expected no %q flag", alias)
+		}
+	}
+	if usage := cmd.Flags().Lookup("prev-week").Usage; !strings.HasSuffix(usage, "(alias: --last-week)") {
+		t.Errorf("Expected the alias in the --prev-week usage, got %q", usage)
+	}
+	if usage := cmd.Flags().Lookup("this-month").Usage; strings.Contains(usage, "alias") {
+		t.Errorf("Expected no alias in the --this-month usage, got %q", usage)
+	}
+}
+
 func TestTimePeriodFlags_CannotCreateEntryWithTimeFlag(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -4368,6 +5717,41 @@ func TestTimePeriodFlags_CannotCreateEntryWithTimeFlag(t *testing.T) {
 	}
 }
 
+func TestDateFlag_RelativeDate(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	yesterday := timeutil.StartOfDay(time.Now()).AddDate(0, 0, -1).Add(10 * time.Hour)
+	entries := []entry.Entry{
+		{Timestamp: yesterday, Description: "yesterday work", DurationMinutes: 60, RawInput: "yesterday work for 1h"},
+		{Timestamp: time.Now(), Description: "today work", DurationMinutes: 30, RawInput: "today work for 30m"},
+	}
+	for _, e := range entries {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+	defer resetTimePeriodFlags(rootCmd)
+
+	_ = rootCmd.Flags().Set("date", "yesterday")
+	rootCmd.Run(rootCmd, []string{})
+
+	output := stdout.String()
+	if !strings.Contains(output, "yesterday work") {
+		t.Errorf("Expected 'yesterday work' in output, got: %s (stderr: %s)", output, stderr.String())
+	}
+	if strings.Contains(output, "today work") {
+		t.Errorf("Should not show 'today work', got: %s", output)
+	}
+}
+
 func TestDateFlag_InvalidFormat(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -4489,46 +5873,312 @@ func TestSetVersionInfo(t *testing.T) {
 	}
 }
 
-func TestBuildPeriodWithFilters_EmptyFilters(t *testing.T) {
-	// Test with no filters - should return period unchanged
-	result := buildPeriodWithFilters("today", "", []string{})
-	if result != "today" {
-		t.Errorf("Expected 'today', got '%s'", result)
-	}
-
-	// Test with only project
-	result = buildPeriodWithFilters("today", "acme", []string{})
-	if result != "today (@acme)" {
-		t.Errorf("Expected 'today (@acme)', got '%s'", result)
+func TestListing_CountOnly(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	now := time.Now()
+	for _, e := range []entry.Entry{
+		{Timestamp: now, Description: "standup", DurationMinutes: 15},
+		{Timestamp: now, Description: "fix login", DurationMinutes: 90, Project: "acme"},
+		{Timestamp: now, Description: "review", DurationMinutes: 30, Project: "acme", Tags: []string{"review"}},
+		{Timestamp: now.AddDate(0, 0, -1), Description: "yesterday", DurationMinutes: 60, Project: "acme"},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
 	}
 
-	// Test with only tags
-	result = buildPeriodWithFilters("today", "", []string{"bug", "urgent"})
-	if result != "today (#bug #urgent)" {
-		t.Errorf("Expected 'today (#bug #urgent)', got '%s'", result)
+	tests := []struct {
+		name     string
+		flags    []string
+		args     []string
+		expected string
+	}{
+		{"count today", []string{"count-only"}, nil, "3\n"},
+		{"count with filter", []string{"count-only"}, []string{"@acme"}, "2\n"},
+		{"count with period", []string{"count-only", "yesterday"}, nil, "1\n"},
+		{"count without matches", []string{"count-only"}, []string{"#missing"}, "0\n"},
+		{"minutes", []string{"minutes"}, nil, "135\n"},
+		{"minutes with count-only", []string{"count-only", "minutes"}, []string{"@acme"}, "120\n"},
+		{"minutes without matches", []string{"minutes", "yesterday"}, []string{"#review"}, "0\n"},
 	}
 
-	// Test with both project and tags
-	result = buildPeriodWithFilters("today", "acme", []string{"bug"})
-	if result != "today (@acme #bug)" {
-		t.Errorf("Expected 'today (@acme #bug)', got '%s'", result)
-	}
-}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			resetTimePeriodFlags(rootCmd)
+			resetFilterFlags(rootCmd)
+			defer resetTimePeriodFlags(rootCmd)
+			defer resetFilterFlags(rootCmd)
+			for _, name := range tt.flags {
+				_ = rootCmd.Flags().Set(name, "true")
+				defer func() { _ = rootCmd.Flags().Set(name, "false") }()
+			}
 
-// mockPathProvider is a test helper for mocking osutil.PathProvider
-type mockPathProvider struct {
-	userConfigDirFn func() (string, error)
-	mkdirAllFn      func(path string, perm os.FileMode) error
-}
+			rootCmd.Run(rootCmd, tt.args)
 
-func (m *mockPathProvider) UserConfigDir() (string, error) {
-	if m.userConfigDirFn != nil {
-		return m.userConfigDirFn()
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr: %s", stderr.String())
+			}
+			if stdout.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, stdout.String())
+			}
+		})
 	}
-	return "", nil
 }
 
-func (m *mockPathProvider) MkdirAll(path string, perm os.FileMode) error {
+func TestListing_Explain(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	day := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	for _, e := range []entry.Entry{
+		{Timestamp: day, Description: "fix login", DurationMinutes: 90, Project: "acme", Tags: []string{"bugfix"}},
+		{Timestamp: day.Add(time.Hour), Description: "standup", DurationMinutes: 15},
+		{Timestamp: day.AddDate(0, 0, 1), Description: "review", DurationMinutes: 30, Project: "acme", Tags: []string{"bugfix"}},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	run := func(t *testing.T, flag string, args ...string) (string, string) {
+		t.Helper()
+		d, stdout, stderr := testDeps(storagePath)
+		SetDeps(d)
+		defer ResetDeps()
+		resetTimePeriodFlags(rootCmd)
+		resetFilterFlags(rootCmd)
+		defer resetTimePeriodFlags(rootCmd)
+		defer resetFilterFlags(rootCmd)
+		_ = rootCmd.Flags().Set("date", "2024-01-15")
+		_ = rootCmd.Flags().Set(flag, "true")
+		defer func() { _ = rootCmd.Flags().Set(flag, "false") }()
+
+		rootCmd.Run(rootCmd, args)
+		return stdout.String(), stderr.String()
+	}
+
+	t.Run("explain", func(t *testing.T) {
+		output, stderr := run(t, "explain", "@acme", "#bugfix")
+		if stderr != "" {
+			t.Fatalf("Unexpected stderr: %s", stderr)
+		}
+		expected := `matched: Mon 2024-01-15 09:00 is in Mon, Jan 15, 2024; project "acme" is @acme; has the #bugfix tag`
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected the reasons %q, got:\n%s", expected, output)
+		}
+		if strings.Contains(output, "Excluded") || strings.Contains(output, "standup") {
+			t.Errorf("Expected no excluded entries without --explain-all, got:\n%s", output)
+		}
+	})
+
+	t.Run("explain all", func(t *testing.T) {
+		output, stderr := run(t, "explain-all", "@acme")
+		if stderr != "" {
+			t.Fatalf("Unexpected stderr: %s", stderr)
+		}
+		for _, expected := range []string{
+			"matched: Mon 2024-01-15 09:00 is in",
+			"Excluded: 2 entries",
+			"[2] Mon 2024-01-15 10:00  standup (15m)",
+			"excluded: has no project, expected @acme",
+			"[3] Tue 2024-01-16 09:00  review [@acme #bugfix] (30m)",
+			"excluded: Tue 2024-01-16 09:00 is after Mon, Jan 15, 2024",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected %q in:\n%s", expected, output)
+			}
+		}
+	})
+
+	t.Run("explain all without matches", func(t *testing.T) {
+		output, _ := run(t, "explain-all", "@other")
+		if !strings.Contains(output, "No entries found") || !strings.Contains(output, "Excluded: 3 entries") {
+			t.Errorf("Expected all entries listed as excluded, got:\n%s", output)
+		}
+	})
+
+	t.Run("with count-only", func(t *testing.T) {
+		_ = rootCmd.Flags().Set("count-only", "true")
+		defer func() { _ = rootCmd.Flags().Set("count-only", "false") }()
+		output, stderr := run(t, "explain")
+		if output != "" || !strings.Contains(stderr, "cannot be combined with --count-only") {
+			t.Errorf("Expected an error, got stdout %q, stderr %q", output, stderr)
+		}
+	})
+}
+
+func TestListing_PeriodArgAliases(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	if err := storage.AppendEntry(storagePath, entry.Entry{Timestamp: time.Now(), Description: "standup", DurationMinutes: 15}); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+	cfg := config.DefaultConfig()
+
+	tests := []struct {
+		name       string
+		flag       string
+		args       []string
+		wantPeriod string
+		wantError  string
+		wantLogged bool
+	}{
+		{"yesterday", "", []string{"y"}, query.Yesterday(time.Now()).Label, "", false},
+		{"this week", "", []string{"w"}, query.Week(time.Now(), cfg.WeekStartDay, false).Label, "", false},
+		{"this month", "", []string{"m"}, query.Month(time.Now(), cfg.MonthStartDay, false).Label, "", false},
+		{"previous week", "", []string{"lw"}, query.Week(time.Now(), cfg.WeekStartDay, true).Label, "", false},
+		{"previous month", "", []string{"pm"}, query.Month(time.Now(), cfg.MonthStartDay, true).Label, "", false},
+		{"upper case", "", []string{"Y"}, query.Yesterday(time.Now()).Label, "", false},
+		{"with filters", "", []string{"w", "@acme", "#bugfix"}, query.Week(time.Now(), cfg.WeekStartDay, false).Label + " (@acme #bugfix)", "", false},
+		{"after filters", "", []string{"@acme", "y"}, query.Yesterday(time.Now()).Label + " (@acme)", "", false},
+		{"same as its flag", "this-week", []string{"w"}, query.Week(time.Now(), cfg.WeekStartDay, false).Label, "", false},
+		{"filters only", "", []string{"@acme"}, "today (@acme)", "", false},
+		{"other word", "", []string{"standup"}, "", "Invalid format. Missing 'for <duration>'", false},
+		{"alias and word", "", []string{"y", "standup"}, "", "Invalid format. Missing 'for <duration>'", false},
+		{"two aliases", "", []string{"y", "w"}, "", "Invalid format. Missing 'for <duration>'", false},
+		{"alias and other flag", "this-month", []string{"y"}, "", "Time period flags are mutually exclusive", false},
+		{"alias with a duration", "", []string{"y", "for", "1h"}, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode := 0
+			d, stdout, stderr := testDeps(storagePath)
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			resetTimePeriodFlags(rootCmd)
+			resetFilterFlags(rootCmd)
+			defer resetTimePeriodFlags(rootCmd)
+			defer resetFilterFlags(rootCmd)
+			if tt.flag != "" {
+				_ = rootCmd.Flags().Set(tt.flag, "true")
+			}
+			before, _ := storage.ReadEntries(storagePath)
+
+			rootCmd.Run(rootCmd, tt.args)
+
+			after, _ := storage.ReadEntries(storagePath)
+			if logged := len(after) > len(before); logged != tt.wantLogged {
+				t.Errorf("Logged an entry = %t, expected %t", logged, tt.wantLogged)
+			}
+			if tt.wantError != "" {
+				if exitCode != 1 || !strings.Contains(stderr.String(), tt.wantError) {
+					t.Errorf("Expected exit 1 with %q, got exit %d: %s", tt.wantError, exitCode, stderr.String())
+				}
+				return
+			}
+			if exitCode != 0 || stderr.Len() > 0 {
+				t.Fatalf("Unexpected error (exit %d): %s", exitCode, stderr.String())
+			}
+			if tt.wantPeriod != "" && !strings.HasPrefix(stdout.String(), "Entries for "+tt.wantPeriod+":") &&
+				!strings.HasPrefix(stdout.String(), "No entries found for "+tt.wantPeriod+"\n") {
+				t.Errorf("Expected a listing of %s, got:\n%s", tt.wantPeriod, stdout.String())
+			}
+		})
+	}
+}
+
+func TestListing_Client(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	now := time.Now()
+	for _, e := range []entry.Entry{
+		{Timestamp: now, Description: "api work", DurationMinutes: 60, Project: "backend", Client: "AcmeCorp"},
+		{Timestamp: now, Description: "styling", DurationMinutes: 30, Client: "AcmeCorp"},
+		{Timestamp: now, Description: "other work", DurationMinutes: 45, Project: "backend", Client: "Globex"},
+		{Timestamp: now, Description: "standup", DurationMinutes: 15, Project: "internal"},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+	defer resetFilterFlags(rootCmd)
+	_ = rootCmd.PersistentFlags().Set("client", "acmecorp")
+
+	rootCmd.Run(rootCmd, []string{})
+
+	output := stdout.String()
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr: %s", stderr.String())
+	}
+	if !strings.HasPrefix(output, "Entries for today (@acmecorp):") {
+		t.Errorf("Expected the client in the header, got: %s", output)
+	}
+	for _, expected := range []string{"api work [@AcmeCorp/backend] (1h)", "styling [@AcmeCorp] (30m)", "Total: 1h 30m"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+	for _, unexpected := range []string{"other work", "standup"} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("Should not show %q, got: %s", unexpected, output)
+		}
+	}
+}
+
+func TestListing_PeriodFilterDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		period   string // time period flag, or "" for today
+		args     []string
+		flags    map[string]string
+		expected string
+	}{
+		{"bare project shorthand", "", []string{"@"}, nil, "No entries found for today\n"},
+		{"bare tag shorthand", "yesterday", []string{"#"}, nil, "No entries found for yesterday\n"},
+		{"project from flag and shorthand", "yesterday", []string{"@acme"}, map[string]string{"project": "acme"}, "No entries found for yesterday (@acme)\n"},
+		{"tag from flag and shorthand", "", []string{"#bug"}, map[string]string{"tag": "bug"}, "No entries found for today (#bug)\n"},
+		{"multiple tags", "yesterday", []string{"@acme", "#bug", "#urgent", "#bug"}, nil, "No entries found for yesterday (@acme #bug #urgent)\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+			SetDeps(d)
+			defer ResetDeps()
+			resetTimePeriodFlags(rootCmd)
+			resetFilterFlags(rootCmd)
+			defer resetTimePeriodFlags(rootCmd)
+			defer resetFilterFlags(rootCmd)
+			if tt.period != "" {
+				_ = rootCmd.Flags().Set(tt.period, "true")
+			}
+			for name, value := range tt.flags {
+				_ = rootCmd.PersistentFlags().Set(name, value)
+			}
+
+			rootCmd.Run(rootCmd, tt.args)
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr: %s", stderr.String())
+			}
+			if stdout.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, stdout.String())
+			}
+		})
+	}
+}
+
+// mockPathProvider is a test helper for mocking osutil.PathProvider
+type mockPathProvider struct {
+	userConfigDirFn func() (string, error)
+	mkdirAllFn      func(path string, perm os.FileMode) error
+}
+
+func (m *mockPathProvider) UserConfigDir() (string, error) {
+	if m.userConfigDirFn != nil {
+		return m.userConfigDirFn()
+	}
+	return "", nil
+}
+
+func (m *mockPathProvider) MkdirAll(path string, perm os.FileMode) error {
 	if m.mkdirAllFn != nil {
 		return m.mkdirAllFn(path, perm)
 	}
@@ -4587,3 +6237,1708 @@ func TestValidateConfigOnStartup_InvalidConfig(t *testing.T) {
 		t.Error("ValidateConfigOnStartup() should return false for invalid config file")
 	}
 }
+
+func TestValidateConfigForArgs_ExplicitPath(t *testing.T) {
+	defer config.SetConfigPath("")
+	defer ResetDeps()
+
+	configPath := filepath.Join(t.TempDir(), "work.toml")
+	missing := filepath.Join(t.TempDir(), "missing.toml")
+	if err := os.WriteFile(configPath, []byte(`default_project = "acme"`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	// --config loads the given file into deps
+	if !validateConfigForArgs([]string{"--config", configPath, "list"}) {
+		t.Fatal("validateConfigForArgs() = false for an existing --config file")
+	}
+	if deps.Config.DefaultProject != "acme" {
+		t.Errorf("Expected deps to use the --config file, got default project %q", deps.Config.DefaultProject)
+	}
+
+	// A missing file given explicitly is an error, unless it is being created
+	config.SetConfigPath("")
+	if validateConfigForArgs([]string{"--config=" + missing, "list"}) {
+		t.Error("validateConfigForArgs() = true for a missing --config file")
+	}
+	config.SetConfigPath("")
+	t.Setenv(config.ConfigPathEnv, missing)
+	if validateConfigForArgs([]string{"list"}) {
+		t.Error("validateConfigForArgs() = true for a missing DID_CONFIG file")
+	}
+	if !validateConfigForArgs([]string{"config", "init"}) {
+		t.Error("validateConfigForArgs() = false for config init of a missing DID_CONFIG file")
+	}
+}
+
+func TestValidateConfigForArgs_InvalidConfigCanBeReplaced(t *testing.T) {
+	defer config.SetConfigPath("")
+	defer ResetDeps()
+
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(configPath, []byte(`week_start_day = "invalid"`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if validateConfigForArgs([]string{"--config", configPath, "list"}) {
+		t.Error("validateConfigForArgs() = true for an invalid config file")
+	}
+	for _, args := range [][]string{
+		{"--config", configPath, "config", "init", "--force"},
+		{"--config", configPath, "init"},
+	} {
+		if !validateConfigForArgs(args) {
+			t.Errorf("validateConfigForArgs(%v) = false, expected the invalid config to be replaceable", args)
+		}
+	}
+}
+
+func TestConfigFlagValue(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"list"}, ""},
+		{[]string{"--config", "a.toml", "list"}, "a.toml"},
+		{[]string{"list", "--config=b.toml"}, "b.toml"},
+		{[]string{"fix", "bug", "--", "--config", "c.toml"}, ""},
+		{[]string{"list", "--config"}, ""},
+	}
+	for _, tt := range tests {
+		if got := configFlagValue(tt.args); got != tt.expected {
+			t.Errorf("configFlagValue(%q) = %q, expected %q", tt.args, got, tt.expected)
+		}
+	}
+}
+
+func TestCheckConfigVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"versionless file", `week_start_day = "sunday"` + "\n", ""},
+		{"current version", "version = 1\n", ""},
+		{"newer version", "version = 99\n", "Warning: Config file version 99 is newer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.toml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			checkConfigVersion(configPath, &out)
+
+			if tt.want == "" && out.Len() > 0 {
+				t.Errorf("Expected no output, got: %s", out.String())
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("Expected output to contain %q, got: %s", tt.want, out.String())
+			}
+
+			// Only 'did migrate --config-file' writes the config file
+			if data, err := os.ReadFile(configPath); err != nil || string(data) != tt.content {
+				t.Errorf("Expected the config file to be unchanged, got %q (%v)", data, err)
+			}
+		})
+	}
+}
+
+// resetEditFlags clears the structured edit flags to avoid test contamination
+func resetEditFlags() {
+	for _, name := range []string{"description", "duration", "project", "timestamp"} {
+		_ = editCmd.Flags().Set(name, "")
+		editCmd.Flags().Lookup(name).Changed = false
+	}
+	for _, name := range []string{"append-tag", "remove-tag"} {
+		f := editCmd.Flags().Lookup(name)
+		if sliceVal, ok := f.Value.(interface{ Replace([]string) error }); ok {
+			_ = sliceVal.Replace([]string{})
+		}
+		f.Changed = false
+	}
+	for _, name := range []string{"allow-long", "allow-future"} {
+		_ = editCmd.Flags().Set(name, "false")
+		editCmd.Flags().Lookup(name).Changed = false
+	}
+}
+
+func TestEditEntry_StructuredProjectAndTags(t *testing.T) {
+	tests := []struct {
+		name            string
+		flags           map[string]string
+		expectedProject string
+		expectedTags    []string
+		expectedRaw     string
+	}{
+		{
+			name:            "set project",
+			flags:           map[string]string{"project": "newproj"},
+			expectedProject: "newproj",
+			expectedTags:    []string{"bugfix", "urgent"},
+			expectedRaw:     "task @newproj #bugfix #urgent for 1h",
+		},
+		{
+			name:            "set project with @ prefix",
+			flags:           map[string]string{"project": "@newproj"},
+			expectedProject: "newproj",
+			expectedTags:    []string{"bugfix", "urgent"},
+			expectedRaw:     "task @newproj #bugfix #urgent for 1h",
+		},
+		{
+			name:            "clear project",
+			flags:           map[string]string{"project": ""},
+			expectedProject: "",
+			expectedTags:    []string{"bugfix", "urgent"},
+			expectedRaw:     "task #bugfix #urgent for 1h",
+		},
+		{
+			name:            "append tag",
+			flags:           map[string]string{"append-tag": "review"},
+			expectedProject: "acme",
+			expectedTags:    []string{"bugfix", "urgent", "review"},
+			expectedRaw:     "task @acme #bugfix #urgent #review for 1h",
+		},
+		{
+			name:            "append existing tag is a no-op",
+			flags:           map[string]string{"append-tag": "#BugFix"},
+			expectedProject: "acme",
+			expectedTags:    []string{"bugfix", "urgent"},
+			expectedRaw:     "task @acme #bugfix #urgent for 1h",
+		},
+		{
+			name:            "remove tag case-insensitive",
+			flags:           map[string]string{"remove-tag": "URGENT"},
+			expectedProject: "acme",
+			expectedTags:    []string{"bugfix"},
+			expectedRaw:     "task @acme #bugfix for 1h",
+		},
+		{
+			name:            "project with description",
+			flags:           map[string]string{"description": "renamed", "project": "other"},
+			expectedProject: "other",
+			expectedTags:    nil,
+			expectedRaw:     "renamed @other for 1h",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			testEntry := entry.Entry{
+				Timestamp:       time.Now(),
+				Description:     "task",
+				DurationMinutes: 60,
+				RawInput:        "task @acme #bugfix #urgent for 1h",
+				Project:         "acme",
+				Tags:            []string{"bugfix", "urgent"},
+			}
+			if err := storage.AppendEntry(storagePath, testEntry); err != nil {
+				t.Fatalf("Failed to create test entry: %v", err)
+			}
+
+			d, stdout, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+
+			resetEditFlags()
+			defer resetEditFlags()
+			for name, value := range tt.flags {
+				_ = editCmd.Flags().Set(name, value)
+			}
+
+			editEntry(editCmd, []string{"1"})
+
+			if !strings.Contains(stdout.String(), "Updated entry 1") {
+				t.Fatalf("Expected 'Updated entry 1', got: %s (stderr: %s)", stdout.String(), stderr.String())
+			}
+
+			entries, _ := storage.ReadEntries(storagePath)
+			got := entries[0]
+			if got.Project != tt.expectedProject {
+				t.Errorf("Expected project %q, got %q", tt.expectedProject, got.Project)
+			}
+			if strings.Join(got.Tags, ",") != strings.Join(tt.expectedTags, ",") {
+				t.Errorf("Expected tags %v, got %v", tt.expectedTags, got.Tags)
+			}
+			if got.RawInput != tt.expectedRaw {
+				t.Errorf("Expected raw input %q, got %q", tt.expectedRaw, got.RawInput)
+			}
+		})
+	}
+}
+
+func TestEditEntry_StructuredFlagErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		flags         map[string]string
+		expectedError string
+	}{
+		{"project conflicts with description", map[string]string{"description": "new @inline", "project": "flag"}, "Conflicting project"},
+		{"tags conflict with description", map[string]string{"description": "new #inline", "append-tag": "flag"}, "Conflicting tags"},
+		{"remove conflicts with description tags", map[string]string{"description": "new #inline", "remove-tag": "flag"}, "Conflicting tags"},
+		{"append and remove same tag", map[string]string{"append-tag": "x", "remove-tag": "X"}, "cannot be both appended and removed"},
+		{"invalid project name", map[string]string{"project": "bad.name"}, "Invalid project name"},
+		{"invalid tag name", map[string]string{"append-tag": "bad.tag"}, "Invalid tag name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			testEntry := entry.Entry{Timestamp: time.Now(), Description: "task", DurationMinutes: 60, RawInput: "task for 1h"}
+			if err := storage.AppendEntry(storagePath, testEntry); err != nil {
+				t.Fatalf("Failed to create test entry: %v", err)
+			}
+
+			exitCalled := false
+			d, _, stderr := testDeps(storagePath)
+			d.Exit = func(code int) { exitCalled = true }
+			SetDeps(d)
+			defer ResetDeps()
+
+			resetEditFlags()
+			defer resetEditFlags()
+			for name, value := range tt.flags {
+				_ = editCmd.Flags().Set(name, value)
+			}
+
+			editEntry(editCmd, []string{"1"})
+
+			if !exitCalled {
+				t.Error("Expected exit to be called")
+			}
+			if !strings.Contains(stderr.String(), tt.expectedError) {
+				t.Errorf("Expected error containing %q, got: %s", tt.expectedError, stderr.String())
+			}
+
+			entries, _ := storage.ReadEntries(storagePath)
+			if entries[0].RawInput != "task for 1h" {
+				t.Errorf("Entry should be unchanged, got raw input %q", entries[0].RawInput)
+			}
+		})
+	}
+}
+
+func TestCreateEntry_DefaultProjectAndRounding(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	cfg := config.DefaultConfig()
+	cfg.DefaultProject = "acme"
+	cfg.RoundMinutes = 15
+	d, stdout, stderr := testDepsWithConfig(storagePath, cfg)
+	SetDeps(d)
+	defer ResetDeps()
+
+	createEntry(rootCmd, []string{"standup", "for", "20m"})
+	createEntry(rootCmd, []string{"review", "@client", "for", "30m"})
+
+	if stderr.Len() > 0 {
+		t.Errorf("Unexpected stderr output: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Logged: standup @acme (30m)") {
+		t.Errorf("Expected default project and rounded duration in output, got: %s", stdout.String())
+	}
+
+	entries, err := storage.ReadEntries(storagePath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Project != "acme" || entries[0].DurationMinutes != 30 {
+		t.Errorf("Expected project acme and 30 minutes, got %q and %d", entries[0].Project, entries[0].DurationMinutes)
+	}
+	if entries[1].Project != "client" || entries[1].DurationMinutes != 30 {
+		t.Errorf("Expected explicit project client and 30 minutes, got %q and %d", entries[1].Project, entries[1].DurationMinutes)
+	}
+}
+
+func TestCreateEntry_TimestampPrecision(t *testing.T) {
+	for _, precision := range []string{"second", "minute"} {
+		t.Run(precision, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			cfg := config.DefaultConfig()
+			cfg.TimestampPrecision = precision
+			d, _, stderr := testDepsWithConfig(storagePath, cfg)
+			SetDeps(d)
+			defer ResetDeps()
+
+			createEntry(rootCmd, []string{"standup", "for", "20m"})
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr output: %s", stderr.String())
+			}
+			entries, err := storage.ReadEntries(storagePath)
+			if err != nil || len(entries) != 1 {
+				t.Fatalf("Expected 1 entry, got %d: %v", len(entries), err)
+			}
+			unit := config.TimestampPrecisions[precision]
+			ts := entries[0].Timestamp
+			if !ts.Equal(ts.Truncate(unit)) {
+				t.Errorf("Timestamp %v is not truncated to the %s", ts, precision)
+			}
+			data, _ := os.ReadFile(storagePath)
+			if stored := `"timestamp":"` + ts.UTC().Format(time.RFC3339) + `"`; !strings.Contains(string(data), stored) {
+				t.Errorf("Expected the timestamp stored as %s, without fractional seconds:\n%s", stored, data)
+			}
+		})
+	}
+}
+
+func TestCreateEntry_DefaultDuration(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+
+	cfg := config.DefaultConfig()
+	cfg.DefaultDurationMinutes = 30
+	d, stdout, stderr := testDepsWithConfig(storagePath, cfg)
+	SetDeps(d)
+	defer ResetDeps()
+
+	logCmd.Run(logCmd, []string{"review", "PR", "42", "@acme"})
+	logCmd.Run(logCmd, []string{"standup", "for", "15m"})
+
+	if stderr.Len() > 0 {
+		t.Errorf("Unexpected stderr output: %s", stderr.String())
+	}
+	expected := "Logged: review PR 42 @acme (30m, default duration)\nLogged: standup (15m)\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+
+	entries, err := storage.ReadEntries(storagePath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Description != "review PR 42" || entries[0].Project != "acme" || entries[0].DurationMinutes != 30 {
+		t.Fatalf("Expected the default duration entry, got %+v", entries)
+	}
+	if entries[1].DurationMinutes != 15 {
+		t.Errorf("Expected the explicit duration to win, got %d minutes", entries[1].DurationMinutes)
+	}
+}
+
+func TestCreateEntry_Workspace(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name        string
+		args        []string
+		noWorkspace bool
+		expected    string
+		project     string
+		tags        []string
+	}{
+		{"inferred", []string{"fix", "login", "for", "1h"}, false,
+			"Logged: fix login @acme #dev (1h)\nNote: @acme #dev inferred from workspace ~/code/acme-app (use --no-workspace to skip)\n",
+			"acme", []string{"dev"}},
+		{"explicit project wins", []string{"fix", "login", "@other", "#dev", "for", "1h"}, false,
+			"Logged: fix login @other #dev (1h)\n", "other", []string{"dev"}},
+		{"no workspace", []string{"fix", "login", "for", "1h"}, true,
+			"Logged: fix login (1h)\n", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			cfg := config.DefaultConfig()
+			cfg.Workspaces = config.Workspaces{
+				"~/code":          {Project: "code"},
+				"~/code/acme-app": {Project: "acme", Tags: []string{"dev"}},
+			}
+			d, stdout, stderr := testDepsWithConfig(storagePath, cfg)
+			d.Getwd = func() (string, error) { return filepath.Join(home, "code", "acme-app", "cmd"), nil }
+			SetDeps(d)
+			defer ResetDeps()
+			defer func() { _ = rootCmd.Flags().Set("no-workspace", "false") }()
+			_ = rootCmd.Flags().Set("no-workspace", strconv.FormatBool(tt.noWorkspace))
+
+			createEntry(rootCmd, tt.args)
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr output: %s", stderr.String())
+			}
+			if stdout.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, stdout.String())
+			}
+			entries, err := storage.ReadEntries(storagePath)
+			if err != nil {
+				t.Fatalf("Failed to read entries: %v", err)
+			}
+			if len(entries) != 1 || entries[0].Project != tt.project || !reflect.DeepEqual(entries[0].Tags, tt.tags) {
+				t.Errorf("Expected project %q and tags %v, got %+v", tt.project, tt.tags, entries)
+			}
+		})
+	}
+}
+
+func TestCreateEntry_DurationKeyword(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	cfg := config.DefaultConfig()
+	cfg.DurationKeyword = "für"
+	d, stdout, stderr := testDepsWithConfig(storagePath, cfg)
+	SetDeps(d)
+	defer ResetDeps()
+
+	createEntry(rootCmd, []string{"Warten", "for", "Review", "@acme", "FÜR", "2h"})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Logged: Warten for Review @acme (2h)") {
+		t.Errorf("Expected the entry split at the configured keyword, got: %s", stdout.String())
+	}
+
+	entries, err := storage.ReadEntries(storagePath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Description != "Warten for Review" || entries[0].Project != "acme" || entries[0].DurationMinutes != 120 {
+		t.Errorf("Unexpected entry: %+v", entries[0])
+	}
+	if entries[0].RawInput != "Warten for Review @acme FÜR 2h" {
+		t.Errorf("Expected RawInput as typed, got %q", entries[0].RawInput)
+	}
+
+	// Editing rebuilds the raw input with the configured keyword
+	_ = editCmd.Flags().Set("duration", "90m")
+	defer resetEditFlags()
+	editEntry(editCmd, []string{"1"})
+
+	entries, err = storage.ReadEntries(storagePath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if entries[0].DurationMinutes != 90 || entries[0].RawInput != "Warten for Review @acme für 90m" {
+		t.Errorf("Expected edited entry with the configured keyword, got %d minutes and %q", entries[0].DurationMinutes, entries[0].RawInput)
+	}
+}
+
+func TestCreateEntry_DurationKeywordMissing(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DurationKeyword = "für"
+	d, _, stderr := testDepsWithConfig(filepath.Join(t.TempDir(), "entries.jsonl"), cfg)
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+
+	createEntry(rootCmd, []string{"fix", "bug", "for", "2h"})
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "Missing 'für <duration>'") || !strings.Contains(stderr.String(), "did feature X für 2h") {
+		t.Errorf("Expected the error to echo the configured keyword, got: %s", stderr.String())
+	}
+}
+
+// resetSubtotalFlags clears the listing subtotal flags
+func resetSubtotalFlags(cmd *cobra.Command) {
+	_ = cmd.Flags().Set("subtotals", "false")
+	_ = cmd.Flags().Set("subtotals-by", "")
+}
+
+func createSubtotalTestEntries(t *testing.T) string {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+
+	now := time.Now()
+	entries := []entry.Entry{
+		{Timestamp: now, Description: "fix login", DurationMinutes: 60, Project: "acme", Tags: []string{"bugfix"}},
+		{Timestamp: now, Description: "standup", DurationMinutes: 15},
+		{Timestamp: now, Description: "api work", DurationMinutes: 90, Project: "client", Tags: []string{"backend", "bugfix"}},
+		{Timestamp: now, Description: "deploy", DurationMinutes: 45, Project: "acme"},
+	}
+	for _, e := range entries {
+		e.RawInput = formatRawInput(e)
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	return storagePath
+}
+
+func TestListEntries_Subtotals(t *testing.T) {
+	d, stdout, stderr := testDeps(createSubtotalTestEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+	resetSubtotalFlags(rootCmd)
+	defer resetSubtotalFlags(rootCmd)
+	_ = rootCmd.Flags().Set("subtotals", "true")
+
+	rootCmd.Run(rootCmd, []string{})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	output := stdout.String()
+	subtotals := output[strings.Index(output, "deploy"):]
+	acme := strings.Index(subtotals, "@acme")
+	client := strings.Index(subtotals, "@client")
+	none := strings.Index(subtotals, "(no project)")
+	total := strings.Index(subtotals, "Total: 3h 30m")
+	if acme < 0 || client < 0 || none < 0 || total < 0 {
+		t.Fatalf("Expected project subtotals and total after the entries, got:\n%s", output)
+	}
+	if !(acme < client && client < none && none < total) {
+		t.Errorf("Expected subtotals sorted by time before the total, got:\n%s", output)
+	}
+	if !strings.Contains(subtotals, "1h 45m  (2 entries)") {
+		t.Errorf("Expected 1h 45m for @acme, got:\n%s", output)
+	}
+}
+
+func TestListEntries_SubtotalsByTag(t *testing.T) {
+	d, stdout, stderr := testDeps(createSubtotalTestEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+	resetSubtotalFlags(rootCmd)
+	defer resetSubtotalFlags(rootCmd)
+	_ = rootCmd.Flags().Set("subtotals-by", "tag")
+
+	rootCmd.Run(rootCmd, []string{})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{"#bugfix", "2h 30m  (2 entries)", "#backend", "(no tags)", "Total: 3h 30m"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "(no project)") {
+		t.Errorf("Expected tag subtotals only, got:\n%s", output)
+	}
+}
+
+func TestListEntries_SubtotalsRespectFilters(t *testing.T) {
+	d, stdout, _ := testDeps(createSubtotalTestEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+	defer resetFilterFlags(rootCmd)
+	resetSubtotalFlags(rootCmd)
+	defer resetSubtotalFlags(rootCmd)
+	_ = rootCmd.Flags().Set("subtotals-by", "tag")
+
+	rootCmd.Run(rootCmd, []string{"@acme"})
+
+	output := stdout.String()
+	if strings.Contains(output, "#backend") {
+		t.Errorf("Expected subtotals for @acme entries only, got:\n%s", output)
+	}
+	if !strings.Contains(output, "#bugfix") || !strings.Contains(output, "Total: 1h 45m") {
+		t.Errorf("Expected #bugfix subtotal and filtered total, got:\n%s", output)
+	}
+}
+
+func TestListEntries_NoSubtotalsByDefault(t *testing.T) {
+	d, stdout, _ := testDeps(createSubtotalTestEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+	resetSubtotalFlags(rootCmd)
+
+	rootCmd.Run(rootCmd, []string{})
+
+	if strings.Contains(stdout.String(), "(no project)") || strings.Count(stdout.String(), strings.Repeat("-", 50)) != 2 {
+		t.Errorf("Expected no subtotals without --subtotals, got:\n%s", stdout.String())
+	}
+}
+
+func TestListEntries_FooterBreakdown(t *testing.T) {
+	storagePath := createSubtotalTestEntries(t)
+
+	tests := []struct {
+		name     string
+		disabled bool
+		args     []string
+		expected string
+	}{
+		{"several projects", false, nil, "Total: 3h 30m (@acme 1h 45m, @client 1h 30m, no project 15m)\n"},
+		{"single project", false, []string{"@acme"}, "Total: 1h 45m\n"},
+		{"disabled in config", true, nil, "Total: 3h 30m\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.FooterBreakdown = !tt.disabled
+			d, stdout, stderr := testDepsWithConfig(storagePath, cfg)
+			SetDeps(d)
+			defer ResetDeps()
+			resetTimePeriodFlags(rootCmd)
+			resetFilterFlags(rootCmd)
+			defer resetFilterFlags(rootCmd)
+			resetSubtotalFlags(rootCmd)
+
+			rootCmd.Run(rootCmd, tt.args)
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr output: %s", stderr.String())
+			}
+			if !strings.HasSuffix(stdout.String(), tt.expected) {
+				t.Errorf("Expected the listing to end with %q, got:\n%s", tt.expected, stdout.String())
+			}
+		})
+	}
+}
+
+func TestFooterBreakdown(t *testing.T) {
+	now := time.Now()
+	start, end := now.Add(-time.Hour), now.Add(time.Hour)
+	var entries []entry.Entry
+	for i, project := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		entries = append(entries, entry.Entry{Timestamp: now, Description: "work", DurationMinutes: 70 - i*10, Project: project})
+	}
+
+	expected := " (@a 1h 10m, @b 1h, @c 50m, @d 40m, @e 30m, +2 more)"
+	if got := footerBreakdown(entries, start, end); got != expected {
+		t.Errorf("footerBreakdown() = %q, expected %q", got, expected)
+	}
+	if got := footerBreakdown(entries[:1], start, end); got != "" {
+		t.Errorf("footerBreakdown() of a single project = %q, expected none", got)
+	}
+
+	// Multi-word projects are quoted as they are written
+	entries = []entry.Entry{
+		{Timestamp: now, Description: "work", DurationMinutes: 60, Project: "Big Client"},
+		{Timestamp: now, Description: "work", DurationMinutes: 30, Project: "acme"},
+	}
+	expected = ` (@"Big Client" 1h, @acme 30m)`
+	if got := footerBreakdown(entries, start, end); got != expected {
+		t.Errorf("footerBreakdown() = %q, expected %q", got, expected)
+	}
+}
+
+func TestListEntries_InvalidSubtotalsBy(t *testing.T) {
+	d, stdout, stderr := testDeps(createSubtotalTestEntries(t))
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+	resetSubtotalFlags(rootCmd)
+	defer resetSubtotalFlags(rootCmd)
+	_ = rootCmd.Flags().Set("subtotals-by", "day")
+
+	rootCmd.Run(rootCmd, []string{})
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if stdout.Len() > 0 {
+		t.Errorf("Expected no listing, got: %s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "Invalid --subtotals-by value 'day'") {
+		t.Errorf("Expected invalid value error, got: %s", stderr.String())
+	}
+}
+
+func TestCreateEntry_DurationOnlyDescription(t *testing.T) {
+	inputs := [][]string{
+		{"2h", "for", "2h"},
+		{"for", "2h", "for", "2h"},
+		{"for", "for", "2h"},
+		{"for", "2h"},
+		{"1h30m", "for", "1h30m"},
+		{"2h", "@acme", "#bugfix", "for", "2h"},
+	}
+
+	for _, args := range inputs {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			exitCode := 0
+			d, _, stderr := testDeps(storagePath)
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+
+			createEntry(rootCmd, args)
+
+			if exitCode != 1 {
+				t.Errorf("Expected exit code 1, got %d", exitCode)
+			}
+			if !strings.Contains(stderr.String(), "Did you forget the description?") {
+				t.Errorf("Expected missing description error, got: %s", stderr.String())
+			}
+			if _, err := os.Stat(storagePath); !os.IsNotExist(err) {
+				t.Errorf("Expected no entry to be written, stat returned: %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateEntry_DescriptionsContainingFor(t *testing.T) {
+	tests := []struct {
+		args        []string
+		description string
+		minutes     int
+	}{
+		{[]string{"shopping", "for", "groceries", "for", "1h"}, "shopping for groceries", 60},
+		{[]string{"waited", "for", "2h", "on", "CI", "for", "30m"}, "waited for 2h on CI", 30},
+		{[]string{"prep", "for", "1h", "meeting", "for", "15m"}, "prep for 1h meeting", 15},
+		{[]string{"fix", "bug", "for", "2h", "for", "2h"}, "fix bug for 2h", 120},
+		{[]string{"for", "2h", "fix", "bug", "for", "2h"}, "fix bug", 120},
+		{[]string{"review", "2h", "recording", "for", "45m"}, "review 2h recording", 45},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			d, _, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+
+			createEntry(rootCmd, tt.args)
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr output: %s", stderr.String())
+			}
+			entries, err := storage.ReadEntries(storagePath)
+			if err != nil {
+				t.Fatalf("Failed to read entries: %v", err)
+			}
+			if len(entries) != 1 {
+				t.Fatalf("Expected 1 entry, got %d", len(entries))
+			}
+			if entries[0].Description != tt.description {
+				t.Errorf("Expected description %q, got %q", tt.description, entries[0].Description)
+			}
+			if entries[0].DurationMinutes != tt.minutes {
+				t.Errorf("Expected %d minutes, got %d", tt.minutes, entries[0].DurationMinutes)
+			}
+		})
+	}
+}
+
+func TestCreateEntry_DurationBounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		allowLong bool
+		wantErr   string
+		minutes   int
+	}{
+		{"zero minutes", []string{"task", "for", "0m"}, false, "Duration must be greater than 0", 0},
+		{"zero combined", []string{"task", "for", "0h0m"}, false, "Duration must be greater than 0", 0},
+		{"negative", []string{"task", "for", "-30m"}, false, "Invalid duration '-30m'", 0},
+		{"30 hours", []string{"task", "for", "30h"}, false, "longer than 24h", 0},
+		{"30 hours allowed", []string{"task", "for", "30h"}, true, "", 1800},
+		{"exactly 24 hours", []string{"task", "for", "24h"}, false, "", 1440},
+		{"zero with allow-long", []string{"task", "for", "0m"}, true, "Duration must be greater than 0", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			exitCode := 0
+			d, _, stderr := testDeps(storagePath)
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			_ = rootCmd.Flags().Set("allow-long", fmt.Sprintf("%t", tt.allowLong))
+			defer func() { _ = rootCmd.Flags().Set("allow-long", "false") }()
+
+			createEntry(rootCmd, tt.args)
+
+			entries, _ := storage.ReadEntries(storagePath)
+			if tt.wantErr != "" {
+				if exitCode != 1 {
+					t.Errorf("Expected exit code 1, got %d", exitCode)
+				}
+				if !strings.Contains(stderr.String(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got: %s", tt.wantErr, stderr.String())
+				}
+				if len(entries) != 0 {
+					t.Errorf("Expected no entry to be written, got %d", len(entries))
+				}
+				return
+			}
+			if exitCode != 0 || stderr.Len() > 0 {
+				t.Fatalf("Unexpected error (exit %d): %s", exitCode, stderr.String())
+			}
+			if len(entries) != 1 || entries[0].DurationMinutes != tt.minutes {
+				t.Errorf("Expected one entry of %d minutes, got %+v", tt.minutes, entries)
+			}
+		})
+	}
+}
+
+func TestCheckEntryDuration_HintNamesMaxEntryDuration(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.MaxEntryDuration = "8h"
+	d, _, _ := testDepsWithConfig(filepath.Join(t.TempDir(), "entries.jsonl"), cfg)
+	SetDeps(d)
+	defer ResetDeps()
+
+	_, err := checkEntryDuration("2x", false)
+	var inputErr *inputError
+	if !errors.As(err, &inputErr) || len(inputErr.help) != 1 || !strings.HasSuffix(inputErr.help[0], "max 8h") {
+		t.Errorf("Expected a hint naming the configured max 8h, got %#v", err)
+	}
+}
+
+func TestDescribeMaxEntryDuration(t *testing.T) {
+	defer describeMaxEntryDuration()
+
+	for _, limit := range []string{"8h", "12h"} {
+		cfg := config.DefaultConfig()
+		cfg.MaxEntryDuration = limit
+		d, _, _ := testDepsWithConfig(filepath.Join(t.TempDir(), "entries.jsonl"), cfg)
+		SetDeps(d)
+
+		describeMaxEntryDuration()
+
+		ResetDeps()
+		for _, f := range []*pflag.Flag{rootCmd.Flags().Lookup("allow-long"), editCmd.Flags().Lookup("allow-long"), importCmd.PersistentFlags().Lookup("allow-long")} {
+			if want := "max_entry_duration (" + limit + ")"; !strings.Contains(f.Usage, want) || strings.Count(f.Usage, "(") != 1 {
+				t.Errorf("Expected the --allow-long help to name %q once, got %q", want, f.Usage)
+			}
+		}
+	}
+}
+
+func TestCreateEntry_MaxEntryDuration(t *testing.T) {
+	tests := []struct {
+		duration  string
+		allowLong bool
+		wantErr   bool
+	}{
+		{"8h", false, false},
+		{"8h1m", false, true},
+		{"8h1m", true, false},
+	}
+
+	for _, tt := range tests {
+		storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+		cfg := config.DefaultConfig()
+		cfg.MaxEntryDuration = "8h"
+		d, _, stderr := testDepsWithConfig(storagePath, cfg)
+		exitCode := 0
+		d.Exit = func(code int) { exitCode = code }
+		SetDeps(d)
+		_ = rootCmd.Flags().Set("allow-long", strconv.FormatBool(tt.allowLong))
+
+		createEntry(rootCmd, []string{"workshop", "for", tt.duration})
+
+		ResetDeps()
+		_ = rootCmd.Flags().Set("allow-long", "false")
+		entries, _ := storage.ReadEntries(storagePath)
+		if tt.wantErr {
+			if exitCode != 1 || len(entries) != 0 || !strings.Contains(stderr.String(), "longer than 8h") {
+				t.Errorf("%s: expected rejection above max_entry_duration, got exit %d, %d entries, stderr %q", tt.duration, exitCode, len(entries), stderr.String())
+			}
+		} else if exitCode != 0 || len(entries) != 1 {
+			t.Errorf("%s (allow-long %v): expected the entry to be logged, got exit %d, stderr %q", tt.duration, tt.allowLong, exitCode, stderr.String())
+		}
+	}
+}
+
+func TestEditEntry_DurationBounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		duration  string
+		allowLong bool
+		wantErr   string
+		minutes   int
+	}{
+		{"zero minutes", "0m", false, "Duration must be greater than 0", 60},
+		{"30 hours", "30h", false, "longer than 24h", 60},
+		{"30 hours allowed", "30h", true, "", 1800},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			testEntry := entry.Entry{
+				Timestamp:       time.Now(),
+				Description:     "test",
+				DurationMinutes: 60,
+				RawInput:        "test for 1h",
+			}
+			if err := storage.AppendEntry(storagePath, testEntry); err != nil {
+				t.Fatalf("Failed to create test entry: %v", err)
+			}
+
+			exitCode := 0
+			d, _, stderr := testDeps(storagePath)
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			resetEditFlags()
+			defer resetEditFlags()
+			_ = editCmd.Flags().Set("duration", tt.duration)
+			_ = editCmd.Flags().Set("allow-long", fmt.Sprintf("%t", tt.allowLong))
+
+			editEntry(editCmd, []string{"1"})
+
+			if tt.wantErr != "" {
+				if exitCode != 1 || !strings.Contains(stderr.String(), tt.wantErr) {
+					t.Errorf("Expected exit 1 with %q, got exit %d: %s", tt.wantErr, exitCode, stderr.String())
+				}
+			} else if exitCode != 0 {
+				t.Fatalf("Unexpected error: %s", stderr.String())
+			}
+			entries, _ := storage.ReadEntries(storagePath)
+			if entries[0].DurationMinutes != tt.minutes {
+				t.Errorf("Expected duration %d, got %d", tt.minutes, entries[0].DurationMinutes)
+			}
+		})
+	}
+}
+
+func createSourceTestDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	now := time.Now()
+	files := map[string][]entry.Entry{
+		"alice.jsonl": {{Timestamp: now, Description: "alice work", DurationMinutes: 60}},
+		"bob.jsonl":   {{Timestamp: now, Description: "bob work", DurationMinutes: 30}},
+	}
+	for name, entries := range files {
+		for _, e := range entries {
+			e.RawInput = formatRawInput(e)
+			e.Source = name
+			if err := storage.AppendEntry(dir, e); err != nil {
+				t.Fatalf("Failed to create test entry: %v", err)
+			}
+		}
+	}
+	return dir
+}
+
+func TestListEntries_ShowSourceDirectory(t *testing.T) {
+	dir := createSourceTestDir(t)
+	f, err := os.OpenFile(filepath.Join(dir, "bob.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("not json\n")
+	_ = f.Close()
+
+	d, stdout, stderr := testDeps(dir)
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+	_ = rootCmd.Flags().Set("show-source", "true")
+	defer func() { _ = rootCmd.Flags().Set("show-source", "false") }()
+
+	rootCmd.Run(rootCmd, []string{})
+
+	output := stdout.String()
+	for _, want := range []string{"alice  alice work (1h)", "bob    bob work (30m)", "Total: 1h 30m"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if !strings.Contains(stderr.String(), "bob.jsonl line 2: not json") {
+		t.Errorf("Expected corruption warning naming bob.jsonl, got: %s", stderr.String())
+	}
+}
+
+func TestListEntries_ShowSourceSingleFile(t *testing.T) {
+	d, stdout, _ := testDeps(createSubtotalTestEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+	_ = rootCmd.Flags().Set("show-source", "true")
+	defer func() { _ = rootCmd.Flags().Set("show-source", "false") }()
+
+	rootCmd.Run(rootCmd, []string{})
+
+	if !strings.Contains(stdout.String(), "entries  standup (15m)") {
+		t.Errorf("Expected the storage file name as source, got:\n%s", stdout.String())
+	}
+}
+
+func TestListEntries_NoIndex(t *testing.T) {
+	storagePath := createSubtotalTestEntries(t)
+	clock := time.Now().Format("15:04")
+	resetIndexFlags := func() {
+		_ = rootCmd.Flags().Set("no-index", "false")
+		_ = rootCmd.Flags().Set("index", "false")
+	}
+
+	tests := []struct {
+		name     string
+		flags    []string
+		expected string
+		absent   string
+	}{
+		{"default", nil, "[3] " + clock + "  api work", ""},
+		{"no-index", []string{"no-index"}, "\n" + clock + "  api work", "[3]"},
+		{"index wins over no-index", []string{"no-index", "index"}, "[3] " + clock + "  api work", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, _ := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			resetTimePeriodFlags(rootCmd)
+			resetFilterFlags(rootCmd)
+			defer resetFilterFlags(rootCmd)
+			resetIndexFlags()
+			defer resetIndexFlags()
+			for _, flag := range tt.flags {
+				_ = rootCmd.Flags().Set(flag, "true")
+			}
+
+			// Filtered to the third entry, which keeps its index in the file
+			rootCmd.Run(rootCmd, []string{"@client"})
+
+			output := stdout.String()
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected %q in output, got:\n%s", tt.expected, output)
+			}
+			if tt.absent != "" && strings.Contains(output, tt.absent) {
+				t.Errorf("Expected no %q in output, got:\n%s", tt.absent, output)
+			}
+		})
+	}
+}
+
+func TestListEntries_OrderDesc(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	now := time.Now()
+	for i, description := range []string{"first", "second", "third"} {
+		e := entry.Entry{Timestamp: now.AddDate(0, 0, -i), Description: description, DurationMinutes: 30}
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	defer resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+	_ = rootCmd.Flags().Set("last", "7")
+	_ = rootCmd.Flags().Set("order", "desc")
+	defer func() { _ = rootCmd.Flags().Set("order", "asc") }()
+
+	rootCmd.Run(rootCmd, []string{})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	output := stdout.String()
+	first, third := strings.Index(output, "first"), strings.Index(output, "third")
+	if first < 0 || third < 0 || first > third {
+		t.Errorf("Expected the newest entry first, got:\n%s", output)
+	}
+	// Indices stay those of the file
+	if !strings.Contains(output, "[1]") || !strings.Contains(output, "[3]") {
+		t.Errorf("Expected the file indices, got:\n%s", output)
+	}
+}
+
+func TestListEntries_DirectoryIndicesFollowTime(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	// alice.jsonl is read first, but bob logged the earlier entry
+	for _, e := range []entry.Entry{
+		{Timestamp: now, Description: "alice work", DurationMinutes: 60, Source: "alice.jsonl"},
+		{Timestamp: now.Add(-time.Minute), Description: "bob work", DurationMinutes: 30, Source: "bob.jsonl"},
+	} {
+		if err := storage.AppendEntry(dir, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, stdout, _ := testDeps(dir)
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+
+	rootCmd.Run(rootCmd, []string{})
+
+	output := stdout.String()
+	bob, alice := strings.Index(output, "[1] "), strings.Index(output, "[2] ")
+	if bob < 0 || alice < bob || !strings.Contains(output[bob:alice], "bob work") {
+		t.Fatalf("Expected bob's earlier entry listed first as [1], got:\n%s", output)
+	}
+
+	// The listed index addresses the same entry
+	yesFlag = true
+	defer func() { yesFlag = false }()
+	deleteEntry("1")
+	if !strings.Contains(stdout.String(), "Deleted: bob work") {
+		t.Errorf("Expected index 1 to delete bob's entry, got:\n%s", stdout.String())
+	}
+}
+
+func TestListEntries_DirectoryWithoutShowSource(t *testing.T) {
+	d, stdout, _ := testDeps(createSourceTestDir(t))
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+
+	rootCmd.Run(rootCmd, []string{})
+
+	output := stdout.String()
+	if !strings.Contains(output, "alice work") || !strings.Contains(output, "bob work") {
+		t.Errorf("Expected entries from every file, got:\n%s", output)
+	}
+	if strings.Contains(output, "alice  alice work") {
+		t.Errorf("Expected no source column without --show-source, got:\n%s", output)
+	}
+}
+
+func TestListEntries_Verbose(t *testing.T) {
+	d, stdout, _ := testDeps(createSubtotalTestEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+	_ = rootCmd.PersistentFlags().Set("project", "client")
+	verboseFlag = true
+	defer func() { verboseFlag = false }()
+
+	rootCmd.Run(rootCmd, []string{})
+
+	output := stdout.String()
+	for _, want := range []string{
+		"api work [@client #backend #bugfix] (1h 30m)\n    Timestamp: ",
+		"    Raw input: api work @client #backend #bugfix for 1h 30m\n",
+		"    Project:   client\n",
+		"    Tags:      backend, bugfix\n",
+		"    Duration:  90 minutes\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "fix login") {
+		t.Errorf("Expected --verbose to keep the project filter, got:\n%s", output)
+	}
+}
+
+func TestListEntries_VerboseWithoutProjectOrTags(t *testing.T) {
+	d, stdout, _ := testDeps(createSubtotalTestEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+
+	rootCmd.Run(rootCmd, []string{})
+	if strings.Contains(stdout.String(), "Raw input:") {
+		t.Errorf("Expected no details without --verbose, got:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	verboseFlag = true
+	defer func() { verboseFlag = false }()
+	rootCmd.Run(rootCmd, []string{})
+
+	output := stdout.String()
+	if !strings.Contains(output, "standup (15m)\n    Timestamp: ") {
+		t.Errorf("Expected details under the standup entry, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Project:   (none)") || !strings.Contains(output, "Tags:      (none)") {
+		t.Errorf("Expected (none) for a missing project and tags, got:\n%s", output)
+	}
+}
+
+func TestCreateEntry_DirectoryWritesMyFile(t *testing.T) {
+	dir := createSourceTestDir(t)
+	cfg := config.DefaultConfig()
+	cfg.MyFile = "carol.jsonl"
+	d, _, stderr := testDepsWithConfig(dir, cfg)
+	SetDeps(d)
+	defer ResetDeps()
+
+	createEntry(rootCmd, []string{"carol", "work", "for", "2h"})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	entries, err := storage.ReadEntries(filepath.Join(dir, "carol.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read carol.jsonl: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Description != "carol work" {
+		t.Errorf("Expected the new entry in carol.jsonl, got %+v", entries)
+	}
+}
+
+func TestCreateEntry_DirectoryWithoutMyFile(t *testing.T) {
+	exitCode := 0
+	d, _, stderr := testDeps(createSourceTestDir(t))
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+
+	createEntry(rootCmd, []string{"carol", "work", "for", "2h"})
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "my_file") {
+		t.Errorf("Expected my_file hint, got: %s", stderr.String())
+	}
+}
+
+func TestValidateStorage_Directory(t *testing.T) {
+	dir := createSourceTestDir(t)
+	if err := os.WriteFile(filepath.Join(dir, "carol.jsonl"), []byte("{broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "dave.jsonl"), []byte("{broken\n{broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, stdout, stderr := testDeps(dir)
+	SetDeps(d)
+	defer ResetDeps()
+	resetFilterFlags(rootCmd)
+
+	validateStorage(validateCmd)
+
+	output := stdout.String()
+	for _, want := range []string{
+		"Storage directory: " + dir,
+		"Valid entries:     2",
+		"Corrupted entries: 3",
+		"alice.jsonl  1 valid, 0 corrupted (1 line)",
+		"carol.jsonl  0 valid, 1 corrupted (1 line)",
+		"dave.jsonl   0 valid, 2 corrupted (2 lines)",
+		"carol.jsonl line 1: {broken",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if !strings.Contains(stderr.String(), "3 corrupted lines") {
+		t.Errorf("Expected corruption status, got: %s", stderr.String())
+	}
+}
+
+func TestEditEntry_RawInputAndTimestamp(t *testing.T) {
+	original := time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC)
+	moved := time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		flags     map[string]string
+		timestamp time.Time
+		raw       string
+		changes   []string
+	}{
+		{
+			name:      "description",
+			flags:     map[string]string{"description": "updated"},
+			timestamp: original,
+			raw:       "updated for 1h",
+			changes:   []string{"description: 'original' → 'updated'", "project: @acme → (none)", "tags: #bugfix → (none)"},
+		},
+		{
+			name:      "duration",
+			flags:     map[string]string{"duration": "2h"},
+			timestamp: original,
+			raw:       "original @acme #bugfix for 2h",
+			changes:   []string{"duration: 1h → 2h"},
+		},
+		{
+			name:      "description and duration",
+			flags:     map[string]string{"description": "updated @other", "duration": "90m"},
+			timestamp: original,
+			raw:       "updated @other for 90m",
+			changes:   []string{"description: 'original' → 'updated'", "project: @acme → @other", "tags: #bugfix → (none)", "duration: 1h → 1h 30m"},
+		},
+		{
+			name:      "project and tags",
+			flags:     map[string]string{"project": "other", "append-tag": "review", "remove-tag": "bugfix"},
+			timestamp: original,
+			raw:       "original @other #review for 1h",
+			changes:   []string{"project: @acme → @other", "tags: #bugfix → #review"},
+		},
+		{
+			name:      "client",
+			flags:     map[string]string{"client": "Initech"},
+			timestamp: original,
+			raw:       "original @acme #bugfix for 1h",
+			changes:   []string{"client: (none) → Initech"},
+		},
+		{
+			name:      "timestamp",
+			flags:     map[string]string{"timestamp": "2024-01-15 09:30"},
+			timestamp: moved,
+			raw:       "original @acme #bugfix for 1h",
+			changes:   []string{"timestamp: 2024-01-15 14:00 → 2024-01-15 09:30"},
+		},
+		{
+			name:      "all fields",
+			flags:     map[string]string{"description": "updated", "duration": "30m", "timestamp": "2024-01-15 09:30"},
+			timestamp: moved,
+			raw:       "updated for 30m",
+			changes:   []string{"description: 'original' → 'updated'", "project: @acme → (none)", "tags: #bugfix → (none)", "duration: 1h → 30m", "timestamp: 2024-01-15 14:00 → 2024-01-15 09:30"},
+		},
+		{
+			name:      "same values",
+			flags:     map[string]string{"project": "acme"},
+			timestamp: original,
+			raw:       "original @acme #bugfix for 1h",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			testEntry := entry.Entry{
+				Timestamp:       original,
+				Description:     "original",
+				DurationMinutes: 60,
+				Project:         "acme",
+				Tags:            []string{"bugfix"},
+				RawInput:        "original @acme #bugfix for 60m",
+			}
+			if err := storage.AppendEntry(storagePath, testEntry); err != nil {
+				t.Fatalf("Failed to create test entry: %v", err)
+			}
+
+			cfg := config.DefaultConfig()
+			cfg.Timezone = "UTC"
+			d, stdout, stderr := testDepsWithConfig(storagePath, cfg)
+			SetDeps(d)
+			defer ResetDeps()
+			resetEditFlags()
+			defer resetEditFlags()
+			for name, value := range tt.flags {
+				_ = editCmd.Flags().Set(name, value)
+			}
+			defer func() {
+				_ = editCmd.Flags().Set("client", "")
+				editCmd.Flags().Lookup("client").Changed = false
+			}()
+
+			editEntry(editCmd, []string{"1"})
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr: %s", stderr.String())
+			}
+			entries, _ := storage.ReadEntries(storagePath)
+			if !entries[0].Timestamp.Equal(tt.timestamp) {
+				t.Errorf("Expected timestamp %v, got %v", tt.timestamp, entries[0].Timestamp)
+			}
+			if entries[0].RawInput != tt.raw {
+				t.Errorf("Expected raw input %q, got %q", tt.raw, entries[0].RawInput)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")[1:]
+			expected := tt.changes
+			if len(expected) == 0 {
+				expected = []string{"(no changes)"}
+			}
+			if len(lines) != len(expected) {
+				t.Fatalf("Expected changes %q, got %q", expected, lines)
+			}
+			for i := range expected {
+				if lines[i] != "  "+expected[i] {
+					t.Errorf("Expected change %q, got %q", "  "+expected[i], lines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEditEntry_Timestamp(t *testing.T) {
+	original := time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC)
+	future := time.Now().Add(48 * time.Hour).Format("2006-01-02 15:04")
+
+	tests := []struct {
+		name        string
+		timestamp   string
+		allowFuture bool
+		wantErr     string
+		expected    time.Time
+	}{
+		{"date and time in timezone", "2024-01-15 15:00", false, "", time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC)},
+		{"RFC3339", "2024-01-15T16:30:00+01:00", false, "", time.Date(2024, 1, 15, 15, 30, 0, 0, time.UTC)},
+		{"invalid", "yesterday 3pm", false, "Invalid timestamp", original},
+		{"future rejected", future, false, "is in the future", original},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			testEntry := entry.Entry{
+				Timestamp:       original,
+				Description:     "meeting",
+				DurationMinutes: 60,
+				RawInput:        "meeting for 1h",
+			}
+			if err := storage.AppendEntry(storagePath, testEntry); err != nil {
+				t.Fatalf("Failed to create test entry: %v", err)
+			}
+
+			cfg := config.DefaultConfig()
+			cfg.Timezone = "UTC"
+			exitCode := 0
+			d, stdout, stderr := testDepsWithConfig(storagePath, cfg)
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			resetEditFlags()
+			defer resetEditFlags()
+			_ = editCmd.Flags().Set("timestamp", tt.timestamp)
+			_ = editCmd.Flags().Set("allow-future", fmt.Sprintf("%t", tt.allowFuture))
+
+			editEntry(editCmd, []string{"1"})
+
+			if tt.wantErr != "" {
+				if exitCode != 1 || !strings.Contains(stderr.String(), tt.wantErr) {
+					t.Errorf("Expected exit 1 with %q, got exit %d: %s", tt.wantErr, exitCode, stderr.String())
+				}
+			} else {
+				if exitCode != 0 {
+					t.Fatalf("Unexpected error: %s", stderr.String())
+				}
+				if !strings.Contains(stdout.String(), "  timestamp: "+original.Format("2006-01-02 15:04")+" → "+tt.expected.Format("2006-01-02 15:04")) {
+					t.Errorf("Expected new timestamp in output, got: %s", stdout.String())
+				}
+			}
+
+			entries, _ := storage.ReadEntries(storagePath)
+			if !entries[0].Timestamp.Equal(tt.expected) {
+				t.Errorf("Expected timestamp %v, got %v", tt.expected, entries[0].Timestamp)
+			}
+			if entries[0].Description != "meeting" || entries[0].DurationMinutes != 60 {
+				t.Errorf("Expected other fields to be unchanged, got %+v", entries[0])
+			}
+		})
+	}
+}
+
+func TestEditEntry_TimestampAllowFuture(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	testEntry := entry.Entry{Timestamp: time.Now(), Description: "planning", DurationMinutes: 30, RawInput: "planning for 30m"}
+	if err := storage.AppendEntry(storagePath, testEntry); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+
+	future := time.Now().Add(48 * time.Hour).Truncate(time.Minute)
+	d, _, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	resetEditFlags()
+	defer resetEditFlags()
+	_ = editCmd.Flags().Set("timestamp", future.Format(time.RFC3339))
+	_ = editCmd.Flags().Set("allow-future", "true")
+
+	editEntry(editCmd, []string{"1"})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	entries, _ := storage.ReadEntries(storagePath)
+	if !entries[0].Timestamp.Equal(future) {
+		t.Errorf("Expected timestamp %v, got %v", future, entries[0].Timestamp)
+	}
+}
+
+// createCorruptedStorage writes a storage file with one valid entry and one corrupted line
+func createCorruptedStorage(t *testing.T) string {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	e := entry.Entry{Timestamp: time.Now(), Description: "work", DurationMinutes: 60, RawInput: "work for 1h"}
+	if err := storage.AppendEntry(storagePath, e); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+	f, err := os.OpenFile(storagePath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("not json\n")
+	_ = f.Close()
+	return storagePath
+}
+
+// listWithWarningState lists today's entries and returns stderr
+func listWithWarningState(storagePath, statePath string) string {
+	d, _, stderr := testDeps(storagePath)
+	d.WarningStatePath = func() (string, error) { return statePath, nil }
+	SetDeps(d)
+	defer ResetDeps()
+	resetTimePeriodFlags(rootCmd)
+	resetFilterFlags(rootCmd)
+
+	rootCmd.Run(rootCmd, []string{})
+	return stderr.String()
+}
+
+func TestCorruptionWarnings_ShownOncePerDay(t *testing.T) {
+	storagePath := createCorruptedStorage(t)
+	statePath := filepath.Join(t.TempDir(), storage.WarningStateFile)
+
+	if !strings.Contains(listWithWarningState(storagePath, statePath), "corrupted line") {
+		t.Fatal("Expected warnings on the first run")
+	}
+	if stderr := listWithWarningState(storagePath, statePath); strings.Contains(stderr, "corrupted line") {
+		t.Errorf("Expected repeated warnings to be suppressed, got: %s", stderr)
+	}
+
+	// A new corrupted line changes the warning set
+	f, _ := os.OpenFile(storagePath, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = f.WriteString("{broken\n")
+	_ = f.Close()
+	if stderr := listWithWarningState(storagePath, statePath); !strings.Contains(stderr, "Found 2 corrupted lines") {
+		t.Errorf("Expected warnings after the corrupted lines changed, got: %s", stderr)
+	}
+}
+
+func TestCorruptionWarnings_ShownAgainAfterADay(t *testing.T) {
+	storagePath := createCorruptedStorage(t)
+	statePath := filepath.Join(t.TempDir(), storage.WarningStateFile)
+	_ = listWithWarningState(storagePath, statePath)
+
+	state, err := storage.ReadWarningState(statePath)
+	if err != nil || state.Hash == "" {
+		t.Fatalf("Expected warning state to be recorded, got %+v (%v)", state, err)
+	}
+	state.ShownAt = state.ShownAt.Add(-25 * time.Hour)
+	if err := storage.WriteWarningState(statePath, state); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(listWithWarningState(storagePath, statePath), "corrupted line") {
+		t.Error("Expected warnings once 24 hours have passed")
+	}
+}
+
+func TestCorruptionWarnings_Verbose(t *testing.T) {
+	storagePath := createCorruptedStorage(t)
+	statePath := filepath.Join(t.TempDir(), storage.WarningStateFile)
+	_ = listWithWarningState(storagePath, statePath)
+
+	verboseFlag = true
+	defer func() { verboseFlag = false }()
+	if !strings.Contains(listWithWarningState(storagePath, statePath), "corrupted line") {
+		t.Error("Expected --verbose to always show warnings")
+	}
+}
+
+func TestCorruptionWarnings_StateFileErrors(t *testing.T) {
+	storagePath := createCorruptedStorage(t)
+
+	// A state path that can't be written falls back to always warning
+	statePath := filepath.Join(t.TempDir(), "missing", storage.WarningStateFile)
+	for i := 0; i < 2; i++ {
+		if !strings.Contains(listWithWarningState(storagePath, statePath), "corrupted line") {
+			t.Errorf("Run %d: expected warnings when the state file can't be written", i+1)
+		}
+	}
+
+	// So does a corrupted state file that can't be replaced
+	dir := t.TempDir()
+	statePath = filepath.Join(dir, storage.WarningStateFile)
+	if err := os.Mkdir(statePath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if !strings.Contains(listWithWarningState(storagePath, statePath), "corrupted line") {
+			t.Errorf("Run %d: expected warnings when the state file can't be read", i+1)
+		}
+	}
+
+	// And an error locating the state file
+	d, _, stderr := testDeps(storagePath)
+	d.WarningStatePath = func() (string, error) { return "", fmt.Errorf("no config dir") }
+	SetDeps(d)
+	defer ResetDeps()
+	printCorruptionWarnings([]storage.ParseWarning{{LineNumber: 1, Content: "x", Error: "bad"}})
+	if !strings.Contains(stderr.String(), "corrupted line") {
+		t.Error("Expected warnings when the state path can't be determined")
+	}
+}
+
+func TestCorruptionWarnings_ValidateAlwaysReports(t *testing.T) {
+	storagePath := createCorruptedStorage(t)
+	statePath := filepath.Join(t.TempDir(), storage.WarningStateFile)
+	_ = listWithWarningState(storagePath, statePath)
+
+	d, stdout, _ := testDeps(storagePath)
+	d.WarningStatePath = func() (string, error) { return statePath, nil }
+	SetDeps(d)
+	defer ResetDeps()
+	resetFilterFlags(rootCmd)
+
+	validateStorage(validateCmd)
+
+	if !strings.Contains(stdout.String(), "Line 2: not json") {
+		t.Errorf("Expected validate to list corrupted lines, got: %s", stdout.String())
+	}
+}
+
+func TestListToday_Sparkline(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	now := time.Now()
+	for _, e := range []entry.Entry{
+		{Timestamp: now.AddDate(0, 0, -3), Description: "planning", DurationMinutes: 60},
+		{Timestamp: now.AddDate(0, 0, -1), Description: "review", DurationMinutes: 120},
+		{Timestamp: now, Description: "deploy", DurationMinutes: 240},
+		{Timestamp: now.AddDate(0, 0, -20), Description: "too old", DurationMinutes: 600},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	const expected = "Last 14 days: ▁▁▁▁▁▁▁▁▁▁▃▁▅█ (avg 30m/day)\n"
+
+	run := func(sparkline bool) string {
+		t.Helper()
+		cfg := config.DefaultConfig()
+		cfg.Sparkline = sparkline
+		d, stdout, _ := testDepsWithConfig(storagePath, cfg)
+		SetDeps(d)
+		defer ResetDeps()
+		resetTimePeriodFlags(rootCmd)
+		resetFilterFlags(rootCmd)
+
+		rootCmd.Run(rootCmd, []string{})
+		return stdout.String()
+	}
+
+	if output := run(true); strings.Contains(output, "Last 14 days") {
+		t.Errorf("Expected no sparkline when output is not a terminal, got:\n%s", output)
+	}
+
+	fakeTerminal(t)
+	if output := run(true); !strings.HasSuffix(output, expected) {
+		t.Errorf("Expected the output to end with %q, got:\n%s", expected, output)
+	}
+	if output := run(false); strings.Contains(output, "Last 14 days") {
+		t.Errorf("Expected no sparkline with sparkline = false, got:\n%s", output)
+	}
+
+	// Fewer than 3 days with entries in the last 14 days
+	if _, err := storage.DeleteEntry(storagePath, 0); err != nil {
+		t.Fatalf("Failed to delete test entry: %v", err)
+	}
+	if output := run(true); strings.Contains(output, "Last 14 days") {
+		t.Errorf("Expected no sparkline with only 2 days of history, got:\n%s", output)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/xolan/did/internal/entry"
 	"github.com/xolan/did/internal/filter"
+	"github.com/xolan/did/internal/query"
 	"github.com/xolan/did/internal/storage"
 	"github.com/xolan/did/internal/timeutil"
 )
@@ -24,13 +25,17 @@ Date Filtering:
   Use --from and --to to filter by date range
   Use --last to filter by relative days (e.g., 'last 7 days')
 
+Use --client to search only the entries of one client, and --show-source to
+show the storage file each entry comes from.
+
 Examples:
   did search meeting                      Search for entries containing 'meeting'
   did search bug                          Search for entries containing 'bug'
   did search "code review"                Search for entries containing 'code review'
   did search meeting --from 2024-01-01    Search from a specific date
   did search bug --from 2024-01-01 --to 2024-01-31    Search within date range
-  did search review --last 7              Search in the last 7 days`,
+  did search review --last 7              Search in the last 7 days
+  did search review --client AcmeCorp     Search the entries of client AcmeCorp`,
 	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		searchEntries(cmd, args)
@@ -44,6 +49,7 @@ func init() {
 	searchCmd.Flags().String("from", "", "Start date for filtering (YYYY-MM-DD or DD/MM/YYYY)")
 	searchCmd.Flags().String("to", "", "End date for filtering (YYYY-MM-DD or DD/MM/YYYY)")
 	searchCmd.Flags().Int("last", 0, "Filter by last N days (e.g., --last 7 for last 7 days)")
+	addShowSourceFlag(searchCmd)
 }
 
 // searchEntries handles the search command logic
@@ -70,7 +76,7 @@ func searchEntries(cmd *cobra.Command, args []string) {
 
 	if lastDays > 0 {
 		// Use relative days
-		now := time.Now()
+		now := configuredNow()
 		endDate = timeutil.EndOfDay(now)
 		startDate = timeutil.StartOfDay(now.AddDate(0, 0, -(lastDays - 1)))
 		hasDateFilter = true
@@ -81,7 +87,7 @@ func searchEntries(cmd *cobra.Command, args []string) {
 		// Parse from date
 		if fromStr != "" {
 			var err error
-			startDate, err = timeutil.ParseDate(fromStr)
+			startDate, err = parseDateFlag(fromStr)
 			if err != nil {
 				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --from date: %v\n", err)
 				deps.Exit(1)
@@ -95,7 +101,7 @@ func searchEntries(cmd *cobra.Command, args []string) {
 		// Parse to date
 		if toStr != "" {
 			var err error
-			toDate, err := timeutil.ParseDate(toStr)
+			toDate, err := parseDateFlag(toStr)
 			if err != nil {
 				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --to date: %v\n", err)
 				deps.Exit(1)
@@ -104,7 +110,7 @@ func searchEntries(cmd *cobra.Command, args []string) {
 			endDate = timeutil.EndOfDay(toDate)
 		} else {
 			// No to date: use now
-			endDate = timeutil.EndOfDay(time.Now())
+			endDate = timeutil.EndOfDay(configuredNow())
 		}
 	}
 
@@ -129,16 +135,12 @@ func searchEntries(cmd *cobra.Command, args []string) {
 	}
 
 	// Display warnings about corrupted lines to stderr
-	if len(result.Warnings) > 0 {
-		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Found %d corrupted line(s) in storage file:\n", len(result.Warnings))
-		for _, warning := range result.Warnings {
-			_, _ = fmt.Fprintln(deps.Stderr, formatCorruptionWarning(warning))
-		}
-		_, _ = fmt.Fprintln(deps.Stderr)
-	}
+	printCorruptionWarnings(result.Warnings)
 
-	// Create filter with keyword
+	// Create filter with keyword, narrowed to the --client if given
 	f := filter.NewFilter(keyword, "", nil)
+	client := query.ResolveFilters(cmd).Client
+	f.Client = client
 
 	// Filter entries by keyword
 	filtered := filter.FilterEntries(result.Entries, f)
@@ -157,9 +159,9 @@ func searchEntries(cmd *cobra.Command, args []string) {
 	// Check if any results found
 	if len(filtered) == 0 {
 		if hasDateFilter {
-			_, _ = fmt.Fprintf(deps.Stdout, "No entries found matching '%s' in the specified date range\n", keyword)
+			_, _ = fmt.Fprintf(deps.Stdout, "No entries found matching '%s'%s in the specified date range\n", keyword, clientSuffix(client))
 		} else {
-			_, _ = fmt.Fprintf(deps.Stdout, "No entries found matching '%s'\n", keyword)
+			_, _ = fmt.Fprintf(deps.Stdout, "No entries found matching '%s'%s\n", keyword, clientSuffix(client))
 		}
 		return
 	}
@@ -171,12 +173,12 @@ func searchEntries(cmd *cobra.Command, args []string) {
 	}
 
 	// Display results
-	resultHeader := fmt.Sprintf("Search results for '%s'", keyword)
+	resultHeader := fmt.Sprintf("Search results for '%s'", keyword) + clientSuffix(client)
 	if hasDateFilter {
 		if lastDays > 0 {
-			resultHeader += fmt.Sprintf(" (last %d %s)", lastDays, pluralize("day", lastDays))
+			resultHeader += fmt.Sprintf(" (last %s)", formatCount(lastDays, "day", "days"))
 		} else {
-			resultHeader += fmt.Sprintf(" (%s)", formatDateRangeForDisplay(startDate, endDate))
+			resultHeader += fmt.Sprintf(" (%s)", query.FormatDateRange(startDate, endDate))
 		}
 	}
 	_, _ = fmt.Fprintf(deps.Stdout, "%s:\n", resultHeader)
@@ -185,15 +187,17 @@ func searchEntries(cmd *cobra.Command, args []string) {
 	// Calculate width for right-aligned indices
 	maxIndexWidth := len(fmt.Sprintf("%d", len(filtered)))
 
+	sourceOf := sourceColumn(cmd, filtered)
 	for i, e := range filtered {
-		_, _ = fmt.Fprintf(deps.Stdout, "[%*d] %s %s  %s (%s)\n",
+		_, _ = fmt.Fprintf(deps.Stdout, "[%*d] %s %s  %s%s (%s)\n",
 			maxIndexWidth,
 			i+1, // 1-based index for user reference
 			e.Timestamp.Format("2006-01-02"),
 			e.Timestamp.Format("15:04"),
-			formatEntryForLog(e.Description, e.Project, e.Tags),
+			sourceOf(e),
+			formatEntryForLog(e.Description, displayProject(e), e.Tags),
 			formatDuration(e.DurationMinutes))
 	}
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("-", 50))
-	_, _ = fmt.Fprintf(deps.Stdout, "Total: %s (%d %s)\n", formatDuration(totalMinutes), len(filtered), pluralize("entry", len(filtered)))
+	_, _ = fmt.Fprintf(deps.Stdout, "Total: %s (%d %s)\n", formatDuration(totalMinutes), len(filtered), pluralize("entry", "entries", len(filtered)))
 }
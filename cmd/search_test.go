@@ -107,8 +107,37 @@ func TestSearchEntries_BasicKeywordSearch(t *testing.T) {
 	if !strings.Contains(output, "Total: 1h 30m") {
 		t.Errorf("Expected 'Total: 1h 30m', got: %s", output)
 	}
-	if !strings.Contains(output, "2 entrys") {
-		t.Errorf("Expected '2 entrys', got: %s", output)
+	if !strings.Contains(output, "2 entries") {
+		t.Errorf("Expected '2 entries', got: %s", output)
+	}
+}
+
+func TestSearchEntries_ClientFilter(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	now := time.Now()
+	for _, e := range []entry.Entry{
+		{Timestamp: now.AddDate(0, 0, -2), Description: "review api", DurationMinutes: 90, Client: "AcmeCorp"},
+		{Timestamp: now.AddDate(0, 0, -1), Description: "review audit", DurationMinutes: 60, Client: "Globex"},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	_ = rootCmd.PersistentFlags().Set("client", "AcmeCorp")
+	defer func() { _ = rootCmd.PersistentFlags().Set("client", "") }()
+
+	searchEntries(searchCmd, []string{"review"})
+
+	output := stdout.String()
+	if !strings.Contains(output, "review api") || strings.Contains(output, "review audit") {
+		t.Errorf("Expected only the entry of client AcmeCorp, got: %s", output)
+	}
+	if !strings.Contains(output, "(client AcmeCorp)") {
+		t.Errorf("Expected the header to name the client, got: %s", output)
 	}
 }
 
@@ -149,8 +178,8 @@ func TestSearchEntries_CaseInsensitiveMatching(t *testing.T) {
 			if !strings.Contains(output, fmt.Sprintf("Search results for '%s'", tt.keyword)) {
 				t.Errorf("Expected search header with keyword '%s', got: %s", tt.keyword, output)
 			}
-			if !strings.Contains(output, fmt.Sprintf("%d entrys", tt.expectedHits)) {
-				t.Errorf("Expected '%d entrys', got: %s", tt.expectedHits, output)
+			if !strings.Contains(output, fmt.Sprintf("%d entries", tt.expectedHits)) {
+				t.Errorf("Expected '%d entries', got: %s", tt.expectedHits, output)
 			}
 		})
 	}
@@ -483,8 +512,8 @@ func TestSearchEntries_TotalDurationCalculation(t *testing.T) {
 	if !strings.Contains(output, "Total: 3h 15m") {
 		t.Errorf("Expected 'Total: 3h 15m', got: %s", output)
 	}
-	if !strings.Contains(output, "3 entrys") {
-		t.Errorf("Expected '3 entrys', got: %s", output)
+	if !strings.Contains(output, "3 entries") {
+		t.Errorf("Expected '3 entries', got: %s", output)
 	}
 }
 
@@ -514,8 +543,7 @@ func TestSearchEntries_StoragePathError(t *testing.T) {
 }
 
 func TestSearchEntries_ReadEntriesError(t *testing.T) {
-	// Use a path to a directory (not a file) to cause read error
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
 	exitCalled := false
 	stderr := &bytes.Buffer{}
@@ -525,7 +553,7 @@ func TestSearchEntries_ReadEntriesError(t *testing.T) {
 		Stdin:  strings.NewReader(""),
 		Exit:   func(code int) { exitCalled = true },
 		StoragePath: func() (string, error) {
-			return tmpDir, nil // path to directory, not file
+			return storagePath, nil
 		},
 	}
 	SetDeps(d)
@@ -734,7 +762,7 @@ func TestIntegration_SearchWithDateFilter(t *testing.T) {
 	if !strings.Contains(output, "Total: 2h 45m") {
 		t.Errorf("Expected total '2h 45m', got: %s", output)
 	}
-	if !strings.Contains(output, "3 entrys") {
+	if !strings.Contains(output, "3 entries") {
 		t.Errorf("Expected 3 entries, got: %s", output)
 	}
 }
@@ -1405,3 +1433,20 @@ func TestSearchEntries_CorruptedStorageWarnings(t *testing.T) {
 		t.Errorf("Expected valid entry in stdout, got: %s", stdoutOutput)
 	}
 }
+
+func TestSearchEntries_ShowSource(t *testing.T) {
+	d, stdout, _ := testDeps(createSourceTestDir(t))
+	SetDeps(d)
+	defer ResetDeps()
+	_ = searchCmd.Flags().Set("show-source", "true")
+	defer func() { _ = searchCmd.Flags().Set("show-source", "false") }()
+
+	searchEntries(searchCmd, []string{"work"})
+
+	output := stdout.String()
+	for _, want := range []string{"alice  alice work (1h)", "bob    bob work (30m)"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
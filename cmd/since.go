@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// sinceSuggestRounding is the granularity of the duration suggested by did since --suggest
+const sinceSuggestRounding = 5
+
+// sinceCmd represents the since command
+var sinceCmd = &cobra.Command{
+	Use:   "since",
+	Short: "Show how long ago the last entry ended",
+	Long: `Show the most recent entry and how long ago it ended (its timestamp plus
+its duration). The gap is usually the duration of the work you are about to
+log.
+
+With --suggest only the gap is printed, rounded to 5 minutes, in the form
+accepted after 'for' (e.g. 1h45m), so it can be used in the shell.
+
+Examples:
+  did since                             Last entry ended 1h 47m ago: 'code review' [@acme]
+  did since --suggest                   1h45m
+  did fix login bug for $(did since --suggest)`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		showSince(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sinceCmd)
+
+	sinceCmd.Flags().Bool("suggest", false, "Print only the gap as a duration rounded to 5 minutes (e.g. 1h45m)")
+}
+
+// showSince prints the last entry and the time since it ended
+func showSince(cmd *cobra.Command) {
+	suggest, _ := cmd.Flags().GetBool("suggest")
+
+	// Get storage path
+	storagePath, err := deps.StoragePath()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return
+	}
+
+	result, err := storage.ReadEntriesWithWarnings(storagePath)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", storagePath)
+		deps.Exit(1)
+		return
+	}
+
+	last, ok := lastEndedEntry(result.Entries)
+	if !ok {
+		// Keep stdout empty for --suggest, so $(did since --suggest) adds nothing
+		out := deps.Stdout
+		if suggest {
+			out = deps.Stderr
+		}
+		_, _ = fmt.Fprintln(out, "No entries yet. Log your first one with: did <description> for <duration>")
+		return
+	}
+
+	gap := int(time.Since(last.EndTime()).Minutes())
+	if suggest {
+		minutes := roundToNearest(gap, sinceSuggestRounding)
+		if minutes <= 0 {
+			_, _ = fmt.Fprintln(deps.Stderr, "Error: The last entry ended just now, so there is no gap to suggest")
+			_, _ = fmt.Fprintln(deps.Stderr, "Hint: Give the duration yourself, e.g. did <description> for 5m")
+			deps.Exit(1)
+			return
+		}
+		_, _ = fmt.Fprintln(deps.Stdout, formatDurationArg(minutes))
+		return
+	}
+
+	description := fmt.Sprintf("'%s'", last.Description)
+	if metadata := formatProjectAndTags(displayProject(last), last.Tags); metadata != "" {
+		description += fmt.Sprintf(" [%s]", metadata)
+	}
+	if gap < 0 {
+		_, _ = fmt.Fprintf(deps.Stdout, "Last entry ends in %s: %s\n", formatDuration(-gap), description)
+	} else {
+		_, _ = fmt.Fprintf(deps.Stdout, "Last entry ended %s ago: %s\n", formatDuration(gap), description)
+	}
+	_, _ = fmt.Fprintf(deps.Stdout, "  %s - %s (%s)\n",
+		last.Timestamp.Format("2006-01-02 15:04"), last.EndTime().Format("15:04"), formatDuration(last.DurationMinutes))
+}
+
+// lastEndedEntry returns the active entry that ends last, or false when there is none
+func lastEndedEntry(entries []entry.Entry) (entry.Entry, bool) {
+	var last entry.Entry
+	found := false
+	for _, e := range entries {
+		if e.DeletedAt != nil || !e.HasValidDuration() {
+			continue
+		}
+		if !found || !e.EndTime().Before(last.EndTime()) {
+			last = e
+			found = true
+		}
+	}
+	return last, found
+}
+
+// roundToNearest rounds minutes to the nearest multiple of step
+func roundToNearest(minutes, step int) int {
+	return (minutes + step/2) / step * step
+}
+
+// formatDurationArg formats minutes in the form accepted after 'for', e.g.
+// "45m", "2h" or "1h45m"
+func formatDurationArg(minutes int) string {
+	hours, mins := minutes/60, minutes%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", mins)
+	case mins == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dm", hours, mins)
+	}
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// resetSinceFlags clears the flags of the since command
+func resetSinceFlags() {
+	_ = sinceCmd.Flags().Set("suggest", "false")
+}
+
+func TestShowSince(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	now := time.Now()
+	deletedAt := now
+	for _, e := range []entry.Entry{
+		{Timestamp: now.Add(-5 * time.Hour), Description: "standup", DurationMinutes: 15},
+		// Started earlier but ended later than the standup
+		{Timestamp: now.Add(-6 * time.Hour), Description: "code review", DurationMinutes: 253, Project: "acme", Tags: []string{"pr"}},
+		{Timestamp: now.Add(-time.Hour), Description: "removed", DurationMinutes: 30, DeletedAt: &deletedAt},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		suggest  bool
+		expected string
+	}{
+		{"gap", false, "Last entry ended 1h 47m ago: 'code review' [@acme #pr]\n"},
+		{"suggest", true, "1h45m\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			resetSinceFlags()
+			defer resetSinceFlags()
+			if tt.suggest {
+				_ = sinceCmd.Flags().Set("suggest", "true")
+			}
+
+			showSince(sinceCmd)
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr: %s", stderr.String())
+			}
+			if !strings.HasPrefix(stdout.String(), tt.expected) {
+				t.Errorf("Expected output starting with %q, got: %q", tt.expected, stdout.String())
+			}
+			if tt.suggest && stdout.String() != tt.expected {
+				t.Errorf("Expected only %q, got: %q", tt.expected, stdout.String())
+			}
+		})
+	}
+}
+
+func TestShowSince_Empty(t *testing.T) {
+	for _, suggest := range []bool{false, true} {
+		d, stdout, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+		exitCode := 0
+		d.Exit = func(code int) { exitCode = code }
+		SetDeps(d)
+		resetSinceFlags()
+		if suggest {
+			_ = sinceCmd.Flags().Set("suggest", "true")
+		}
+
+		showSince(sinceCmd)
+
+		ResetDeps()
+		resetSinceFlags()
+		// With --suggest the message goes to stderr, keeping $(did since --suggest) empty
+		out, other := stdout, stderr
+		if suggest {
+			out, other = stderr, stdout
+		}
+		if exitCode != 0 || !strings.Contains(out.String(), "No entries yet") || other.Len() > 0 {
+			t.Errorf("suggest=%v: expected a friendly message and exit 0, got exit %d, stdout %q, stderr %q", suggest, exitCode, stdout.String(), stderr.String())
+		}
+	}
+}
+
+func TestShowSince_SuggestNoGap(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	if err := storage.AppendEntry(storagePath, entry.Entry{Timestamp: time.Now().Add(-time.Hour), Description: "meeting", DurationMinutes: 60}); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+	d, stdout, stderr := testDeps(storagePath)
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	resetSinceFlags()
+	defer resetSinceFlags()
+	_ = sinceCmd.Flags().Set("suggest", "true")
+
+	showSince(sinceCmd)
+
+	if exitCode != 1 || stdout.Len() > 0 || !strings.Contains(stderr.String(), "no gap to suggest") {
+		t.Errorf("Expected a no-gap error, got exit %d, stdout %q, stderr %q", exitCode, stdout.String(), stderr.String())
+	}
+}
+
+func TestFormatDurationArg(t *testing.T) {
+	for minutes, expected := range map[int]string{5: "5m", 60: "1h", 105: "1h45m", 1500: "25h"} {
+		got := formatDurationArg(minutes)
+		if got != expected {
+			t.Errorf("formatDurationArg(%d) = %q, expected %q", minutes, got, expected)
+		}
+		// The suggestion must parse back to the same duration
+		if parsed, err := entry.ParseDurationMinutes(got); err != nil || parsed != minutes {
+			t.Errorf("ParseDurationMinutes(%q) = %d, %v, expected %d", got, parsed, err, minutes)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/storage"
+)
+
+// sortCmd represents the sort command
+var sortCmd = &cobra.Command{
+	Use:   "sort",
+	Short: "Rewrite the storage file in chronological order",
+	Long: `Rewrite the storage file with its entries sorted by timestamp.
+
+Imports and edits can leave entries out of chronological order, which
+'did validate' reports. Sorting is stable: entries with the same timestamp
+keep their relative order, and each entry line is written back unchanged.
+Corrupted lines are kept and moved to the end of the file, in their original
+order. The file is replaced atomically.
+
+Entry indices used by edit, delete, merge and split follow the file order,
+so they can change after sorting.
+
+Examples:
+  did storage sort                Sort the storage file
+  did storage sort --dry-run      Show how many entries would move
+  did sort                        Same as did storage sort`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		sortStorage(dryRun)
+	},
+}
+
+// storageCmd groups the commands maintaining the storage file
+var storageCmd = &cobra.Command{
+	Use:   "storage",
+	Short: "Maintain the storage file",
+	Long: `Commands maintaining the storage file. See also 'did validate' for its
+health and 'did restore' for its backups.
+
+Examples:
+  did storage sort                Rewrite the storage file in chronological order
+  did storage normalize           Truncate timestamps to whole seconds or minutes`,
+}
+
+// storageSortCmd is did sort as a subcommand of did storage
+var storageSortCmd = &cobra.Command{
+	Use:   "sort",
+	Short: sortCmd.Short,
+	Long:  sortCmd.Long,
+	Args:  cobra.NoArgs,
+	Run:   sortCmd.Run,
+}
+
+func init() {
+	rootCmd.AddCommand(sortCmd)
+	rootCmd.AddCommand(storageCmd)
+	storageCmd.AddCommand(storageSortCmd)
+
+	for _, cmd := range []*cobra.Command{sortCmd, storageSortCmd} {
+		cmd.Flags().Bool("dry-run", false, "Show how many entries would move without writing")
+	}
+}
+
+// sortStorage sorts the storage file by timestamp, or reports what would change
+func sortStorage(dryRun bool) {
+	storagePath, err := deps.StoragePath()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return
+	}
+
+	result, err := storage.SortEntries(storagePath, dryRun)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to sort storage file")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that the file is readable and writable: %s\n", storagePath)
+		deps.Exit(1)
+		return
+	}
+
+	if dryRun {
+		if result.Moved == 0 {
+			_, _ = fmt.Fprintf(deps.Stdout, "All %s are in chronological order\n", formatCount(result.Entries, "entry", "entries"))
+		} else {
+			_, _ = fmt.Fprintf(deps.Stdout, "Would move %s of %d into chronological order\n", formatCount(result.Moved, "entry", "entries"), result.Entries)
+		}
+		if result.Corrupted > 0 {
+			_, _ = fmt.Fprintf(deps.Stdout, "%s would be kept at the end of the file\n", formatCount(result.Corrupted, "corrupted line", "corrupted lines"))
+		}
+		return
+	}
+
+	if !result.Written {
+		_, _ = fmt.Fprintf(deps.Stdout, "Already sorted: all %s are in chronological order\n", formatCount(result.Entries, "entry", "entries"))
+		return
+	}
+	_, _ = fmt.Fprintf(deps.Stdout, "Sorted %s (%d moved)\n", formatCount(result.Entries, "entry", "entries"), result.Moved)
+	if result.Corrupted > 0 {
+		_, _ = fmt.Fprintf(deps.Stdout, "Kept %s at the end of the file\n", formatCount(result.Corrupted, "corrupted line", "corrupted lines"))
+	}
+}
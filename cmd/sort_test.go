@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// createUnsortedEntries writes three entries, the last one dated earliest, and a corrupted line
+func createUnsortedEntries(t *testing.T) string {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	base := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	for i, e := range []entry.Entry{
+		{Timestamp: base.Add(time.Hour), Description: "second", DurationMinutes: 30},
+		{Timestamp: base.Add(2 * time.Hour), Description: "third", DurationMinutes: 15},
+		{Timestamp: base, Description: "first", DurationMinutes: 60},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry %d: %v", i, err)
+		}
+	}
+	f, err := os.OpenFile(storagePath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("not json\n")
+	_ = f.Close()
+	return storagePath
+}
+
+func TestSortStorage(t *testing.T) {
+	storagePath := createUnsortedEntries(t)
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	sortStorage(false)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	for _, want := range []string{"Sorted 3 entries (3 moved)", "Kept 1 corrupted line at the end of the file"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Expected output to contain %q, got: %s", want, stdout.String())
+		}
+	}
+
+	result, err := storage.ReadEntriesWithWarnings(storagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var descriptions []string
+	for _, e := range result.Entries {
+		descriptions = append(descriptions, e.Description)
+	}
+	if strings.Join(descriptions, ",") != "first,second,third" {
+		t.Errorf("Expected entries in chronological order, got %v", descriptions)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].LineNumber != 4 {
+		t.Errorf("Expected the corrupted line to be kept last, got %+v", result.Warnings)
+	}
+}
+
+func TestSortStorage_DryRun(t *testing.T) {
+	storagePath := createUnsortedEntries(t)
+	before, err := os.ReadFile(storagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	sortStorage(true)
+
+	for _, want := range []string{"Would move 3 entries of 3 into chronological order", "1 corrupted line would be kept at the end of the file"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Expected output to contain %q, got: %s", want, stdout.String())
+		}
+	}
+	if after, _ := os.ReadFile(storagePath); string(after) != string(before) {
+		t.Error("Expected --dry-run to leave the file unchanged")
+	}
+}
+
+func TestSortStorage_AlreadySorted(t *testing.T) {
+	storagePath := createUnsortedEntries(t)
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	sortStorage(false)
+	stdout.Reset()
+
+	sortStorage(false)
+	if !strings.Contains(stdout.String(), "Already sorted: all 3 entries are in chronological order") {
+		t.Errorf("Expected already sorted message, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	sortStorage(true)
+	if !strings.Contains(stdout.String(), "All 3 entries are in chronological order") {
+		t.Errorf("Expected in order message for --dry-run, got: %s", stdout.String())
+	}
+}
+
+func TestSortStorage_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		storagePath func() (string, error)
+		wantErr     string
+	}{
+		{"storage path error", func() (string, error) { return "", os.ErrPermission }, "Failed to determine storage location"},
+		{"unreadable storage", func() (string, error) { return unreadableStoragePath(t), nil }, "Failed to sort storage file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode := 0
+			d, _, stderr := testDeps("")
+			d.StoragePath = tt.storagePath
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+
+			sortStorage(false)
+
+			if exitCode != 1 {
+				t.Errorf("Expected exit code 1, got %d", exitCode)
+			}
+			if !strings.Contains(stderr.String(), tt.wantErr) {
+				t.Errorf("Expected %q, got: %s", tt.wantErr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestValidateStorage_OutOfOrder(t *testing.T) {
+	d, stdout, _ := testDeps(createUnsortedEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	resetFilterFlags(rootCmd)
+
+	validateStorage(validateCmd)
+
+	if !strings.Contains(stdout.String(), "Entries out of chronological order: 3") {
+		t.Errorf("Expected the out of order count, got: %s", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "  line 3: dated 2024-01-15 09:00, before the entry on line 2 (2024-01-15 11:00)") {
+		t.Errorf("Expected the backdated line to be listed, got: %s", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "did storage sort") {
+		t.Errorf("Expected a hint to run did storage sort, got: %s", stdout.String())
+	}
+}
+
+func TestStorageSort(t *testing.T) {
+	storagePath := createUnsortedEntries(t)
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	storageSortCmd.Run(storageSortCmd, nil)
+
+	if stderr.Len() > 0 || !strings.Contains(stdout.String(), "Sorted 3 entries (3 moved)") {
+		t.Errorf("Expected did storage sort to sort like did sort, got stdout %q, stderr %q", stdout.String(), stderr.String())
+	}
+}
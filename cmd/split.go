@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// splitCmd represents the split command
+var splitCmd = &cobra.Command{
+	Use:   "split <index>",
+	Short: "Break one entry into several",
+	Long: `Replace one entry with several entries whose durations add up to the original.
+
+Each part uses the same format as logging an entry:
+"<description> [@project] [#tag...] for <duration>". Give the parts with
+repeated --part flags, or omit --part to enter them interactively (one per
+line, empty line to finish).
+
+The first part starts at the original entry's timestamp and each following
+part starts where the previous one ended.
+
+The durations of the parts must add up to the original duration. With
+--allow-remainder the parts may add up to less; the remaining time stays
+with an entry that keeps the original description, project and tags.
+
+The index refers to the entry number shown in list output (starting from 1).
+
+Examples:
+  did split 3 --part "standup @acme for 1h" --part "planning @client for 3h"
+  did split 3 --part "review #pr for 30m" --allow-remainder
+  did split 3                     Enter the parts interactively`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		splitEntry(cmd, args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+
+	splitCmd.Flags().StringArray("part", []string{}, "A part as '<description> for <duration>' (can be repeated)")
+	splitCmd.Flags().Bool("allow-remainder", false, "Keep any unassigned time in an entry with the original description")
+}
+
+// splitEntry replaces the entry at the given 1-based index with several parts
+func splitEntry(cmd *cobra.Command, indexStr string) {
+	partInputs, _ := cmd.Flags().GetStringArray("part")
+	allowRemainder, _ := cmd.Flags().GetBool("allow-remainder")
+
+	// Parse the index argument (1-based from user)
+	userIndex, err := strconv.Atoi(indexStr)
+	if err != nil {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid index '%s'. Index must be a number\n", indexStr)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: List entries with 'did' to see available indices")
+		deps.Exit(1)
+		return
+	}
+
+	// Get storage path
+	storagePath, err := deps.StoragePath()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return
+	}
+
+	// Read all entries
+	result, err := storage.ReadEntriesWithWarnings(storagePath)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to read entries from storage")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that file exists and is readable: %s\n", storagePath)
+		deps.Exit(1)
+		return
+	}
+
+	// Filter to active entries only and create index mapping
+	// Users should only be able to split active (non-deleted) entries
+	var activeEntries []entry.Entry
+	var storageIndices []int // Maps active entry index to storage index
+	for i, e := range result.Entries {
+		if e.DeletedAt == nil {
+			activeEntries = append(activeEntries, e)
+			storageIndices = append(storageIndices, i)
+		}
+	}
+
+	if len(activeEntries) == 0 {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: No entries found to split")
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Create an entry first with 'did <description> for <duration>'")
+		deps.Exit(1)
+		return
+	}
+
+	activeIndex := userIndex - 1
+	if activeIndex < 0 || activeIndex >= len(activeEntries) {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Index %d is out of range\n", userIndex)
+		_, _ = fmt.Fprintf(deps.Stderr, "Valid range: 1-%d (%d %s available)\n", len(activeEntries), len(activeEntries), pluralize("entry", "entries", len(activeEntries)))
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: List entries with 'did' to see all indices")
+		deps.Exit(1)
+		return
+	}
+
+	original := activeEntries[activeIndex]
+	storageIndex := storageIndices[activeIndex]
+
+	// Collect the parts from --part flags or interactively
+	var parts []entry.Entry
+	if len(partInputs) > 0 {
+		for _, input := range partInputs {
+			part, err := parseSplitPart(input)
+			if err != nil {
+				_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid part '%s'\n", input)
+				_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+				_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use the format '<description> for <duration>', e.g. 'standup @acme for 30m'")
+				deps.Exit(1)
+				return
+			}
+			parts = append(parts, part)
+		}
+	} else {
+		parts = promptSplitParts(original)
+	}
+
+	if len(parts) == 0 {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: No parts given")
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use --part '<description> for <duration>' (can be repeated)")
+		deps.Exit(1)
+		return
+	}
+
+	// Check that the parts add up to the original duration
+	totalMinutes := 0
+	for _, part := range parts {
+		totalMinutes += part.DurationMinutes
+	}
+	remainder := original.DurationMinutes - totalMinutes
+	if remainder < 0 {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Parts add up to %s, which is more than the original %s\n",
+			formatDuration(totalMinutes), formatDuration(original.DurationMinutes))
+		deps.Exit(1)
+		return
+	}
+	if remainder > 0 && !allowRemainder {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Parts add up to %s but the original entry is %s (%s unassigned)\n",
+			formatDuration(totalMinutes), formatDuration(original.DurationMinutes), formatDuration(remainder))
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use --allow-remainder to keep the unassigned time in the original entry")
+		deps.Exit(1)
+		return
+	}
+	if remainder > 0 {
+		rest := original
+		rest.DurationMinutes = remainder
+		rest.RawInput = formatRawInput(rest)
+		parts = append(parts, rest)
+	}
+
+	if len(parts) < 2 {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: A split needs at least two parts")
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use 'did edit' to change a single entry")
+		deps.Exit(1)
+		return
+	}
+
+	// The first part starts at the original timestamp; each following part starts where the previous ended.
+	// The parts were logged when the original was.
+	start := original.Timestamp
+	for i := range parts {
+		parts[i].Timestamp = start
+		parts[i].LoggedAt = original.LoggedAt
+		start = parts[i].EndTime()
+	}
+
+	// Replace the original entry with the parts
+	if err := storage.ReplaceEntries(storagePath, []int{storageIndex}, parts); err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to save split entries to storage")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		printStorageWriteHint(storagePath, err)
+		deps.Exit(1)
+		return
+	}
+
+	// Display the resulting entries
+	_, _ = fmt.Fprintf(deps.Stdout, "Split entry %d into %d entries:\n", userIndex, len(parts))
+	for _, part := range parts {
+		_, _ = fmt.Fprintf(deps.Stdout, "  %s  %s (%s)\n",
+			part.Timestamp.Format("2006-01-02 15:04"),
+			formatEntryForLog(part.Description, displayProject(part), part.Tags),
+			formatDuration(part.DurationMinutes))
+	}
+}
+
+// parseSplitPart parses a part in the "<description> for <duration>" format
+// (with the configured duration keyword) into an entry without a timestamp.
+// Durations over max_entry_duration are rejected (see checkEntryDuration).
+func parseSplitPart(input string) (entry.Entry, error) {
+	input = strings.TrimSpace(input)
+
+	keyword := deps.Config.EffectiveDurationKeyword()
+	description, durationStr, ok := entry.SplitAtDurationKeyword(input, keyword)
+	if !ok {
+		return entry.Entry{}, fmt.Errorf("missing '%s <duration>'", keyword)
+	}
+
+	cleanDesc, project, tags := entry.ParseProjectAndTags(description)
+	if cleanDesc == "" {
+		return entry.Entry{}, errors.New("description cannot be empty")
+	}
+
+	minutes, err := checkEntryDuration(durationStr, false)
+	if err != nil {
+		return entry.Entry{}, err
+	}
+
+	return entry.Entry{
+		Description:     cleanDesc,
+		DurationMinutes: minutes,
+		RawInput:        input,
+		Project:         project,
+		Tags:            tags,
+	}, nil
+}
+
+// promptSplitParts reads parts from stdin, one per line, until an empty line
+// or end of input. Invalid lines are reported and can be re-entered.
+func promptSplitParts(original entry.Entry) []entry.Entry {
+	_, _ = fmt.Fprintf(deps.Stdout, "Splitting: %s (%s)\n",
+		formatEntryForLog(original.Description, displayProject(original), original.Tags),
+		formatDuration(original.DurationMinutes))
+	_, _ = fmt.Fprintf(deps.Stdout, "Enter parts as '<description> %s <duration>', empty line to finish.\n", deps.Config.EffectiveDurationKeyword())
+
+	var parts []entry.Entry
+	remaining := original.DurationMinutes
+	scanner := bufio.NewScanner(deps.Stdin)
+	for {
+		_, _ = fmt.Fprintf(deps.Stdout, "Part %d (%s remaining): ", len(parts)+1, formatDuration(remaining))
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			break
+		}
+
+		part, err := parseSplitPart(line)
+		if err != nil {
+			_, _ = fmt.Fprintf(deps.Stderr, "Invalid part: %v\n", err)
+			continue
+		}
+		parts = append(parts, part)
+		remaining -= part.DurationMinutes
+	}
+	_, _ = fmt.Fprintln(deps.Stdout)
+
+	return parts
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// setSplitFlags sets the split flags and resets them when the test finishes
+func setSplitFlags(t *testing.T, parts []string, allowRemainder bool) {
+	t.Helper()
+	for _, part := range parts {
+		_ = splitCmd.Flags().Set("part", part)
+	}
+	if allowRemainder {
+		_ = splitCmd.Flags().Set("allow-remainder", "true")
+	}
+	t.Cleanup(func() {
+		f := splitCmd.Flags().Lookup("part")
+		if sliceVal, ok := f.Value.(interface{ Replace([]string) error }); ok {
+			_ = sliceVal.Replace([]string{})
+		}
+		f.Changed = false
+		_ = splitCmd.Flags().Set("allow-remainder", "false")
+	})
+}
+
+func splitTestEntries() []entry.Entry {
+	base := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	loggedAt := base.Add(4 * time.Hour)
+	return []entry.Entry{
+		{Timestamp: base, Description: "meetings", DurationMinutes: 240, RawInput: "meetings @acme for 4h", Project: "acme", LoggedAt: &loggedAt},
+		{Timestamp: base.Add(5 * time.Hour), Description: "coding", DurationMinutes: 60, RawInput: "coding for 1h"},
+	}
+}
+
+func TestSplitEntry_WithParts(t *testing.T) {
+	storagePath := createMergeTestEntries(t, splitTestEntries())
+
+	d, stdout, stderr := testDeps(storagePath)
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	setSplitFlags(t, []string{"standup @acme #daily for 1h", "planning @client for 3h"}, false)
+
+	splitEntry(splitCmd, "1")
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Split entry 1 into 2 entries") {
+		t.Errorf("Expected split summary, got: %s", stdout.String())
+	}
+
+	entries, err := storage.ReadEntries(storagePath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries after split, got %d", len(entries))
+	}
+
+	base := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	first, second := entries[0], entries[1]
+	if first.Description != "standup" || first.Project != "acme" || len(first.Tags) != 1 || first.DurationMinutes != 60 {
+		t.Errorf("Unexpected first part: %+v", first)
+	}
+	if !first.Timestamp.Equal(base) {
+		t.Errorf("First part should start at the original timestamp, got %v", first.Timestamp)
+	}
+	if second.Description != "planning" || second.Project != "client" || second.DurationMinutes != 180 {
+		t.Errorf("Unexpected second part: %+v", second)
+	}
+	if !second.Timestamp.Equal(base.Add(time.Hour)) {
+		t.Errorf("Second part should start after the first, got %v", second.Timestamp)
+	}
+	for i, part := range []entry.Entry{first, second} {
+		if part.LoggedAt == nil || !part.LoggedAt.Equal(base.Add(4*time.Hour)) {
+			t.Errorf("Part %d should keep the original LoggedAt, got %v", i+1, part.LoggedAt)
+		}
+	}
+	if entries[2].Description != "coding" {
+		t.Errorf("Expected unrelated entry to be kept, got %q", entries[2].Description)
+	}
+}
+
+func TestSplitEntry_AllowRemainder(t *testing.T) {
+	storagePath := createMergeTestEntries(t, splitTestEntries())
+
+	d, _, stderr := testDeps(storagePath)
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	setSplitFlags(t, []string{"review #pr for 30m"}, true)
+
+	splitEntry(splitCmd, "1")
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", exitCode, stderr.String())
+	}
+	entries, _ := storage.ReadEntries(storagePath)
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries after split, got %d", len(entries))
+	}
+	rest := entries[1]
+	if rest.Description != "meetings" || rest.Project != "acme" || rest.DurationMinutes != 210 {
+		t.Errorf("Expected remainder to keep the original metadata, got %+v", rest)
+	}
+	if rest.RawInput != "meetings @acme for 3h 30m" {
+		t.Errorf("Unexpected remainder raw input: %q", rest.RawInput)
+	}
+}
+
+func TestSplitEntry_Interactive(t *testing.T) {
+	storagePath := createMergeTestEntries(t, splitTestEntries())
+
+	d, stdout, stderr := testDeps(storagePath)
+	d.Stdin = strings.NewReader("standup for 1h\nnot a part\nplanning for 3h\n\n")
+	exitCode := 0
+	d.Exit = func(code int) { exitCode = code }
+	SetDeps(d)
+	defer ResetDeps()
+	setSplitFlags(t, nil, false)
+
+	splitEntry(splitCmd, "1")
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Part 2 (3h remaining)") {
+		t.Errorf("Expected remaining time in prompt, got: %s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "Invalid part") {
+		t.Errorf("Expected invalid line to be reported, got: %s", stderr.String())
+	}
+	entries, _ := storage.ReadEntries(storagePath)
+	if len(entries) != 3 {
+		t.Errorf("Expected 3 entries after split, got %d", len(entries))
+	}
+}
+
+func TestSplitEntry_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		index          string
+		parts          []string
+		allowRemainder bool
+		expected       string
+	}{
+		{"non-numeric index", "abc", []string{"a for 1h"}, false, "Invalid index 'abc'"},
+		{"out of range", "5", []string{"a for 1h"}, false, "Index 5 is out of range"},
+		{"invalid part", "1", []string{"no duration"}, false, "Invalid part 'no duration'"},
+		{"parts do not add up", "1", []string{"a for 1h", "b for 2h"}, false, "1h unassigned"},
+		{"parts exceed original", "1", []string{"a for 3h", "b for 2h"}, true, "more than the original 4h"},
+		{"single part", "1", []string{"a for 4h"}, false, "at least two parts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := createMergeTestEntries(t, splitTestEntries())
+			d, stdout, stderr := testDeps(storagePath)
+			exitCode := 0
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			setSplitFlags(t, tt.parts, tt.allowRemainder)
+
+			splitEntry(splitCmd, tt.index)
+
+			if exitCode != 1 {
+				t.Errorf("Expected exit code 1, got %d", exitCode)
+			}
+			if !strings.Contains(stderr.String(), tt.expected) {
+				t.Errorf("Expected error containing %q, got: %s", tt.expected, stderr.String())
+			}
+			if stdout.Len() > 0 {
+				t.Errorf("Expected no stdout, got: %s", stdout.String())
+			}
+			entries, _ := storage.ReadEntries(storagePath)
+			if len(entries) != 2 {
+				t.Errorf("Expected storage to be unchanged, got %d entries", len(entries))
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/xolan/did/internal/entry"
 	"github.com/xolan/did/internal/timer"
+	"github.com/xolan/did/internal/timeutil"
 )
 
 var forceFlag bool
@@ -39,7 +40,7 @@ func init() {
 // startTimer starts a new timer with the given description
 func startTimer(args []string) {
 	// Join all arguments to form the description
-	description := strings.Join(args, " ")
+	description := strings.Join(quoteShorthandArgs(args), " ")
 
 	// Trim whitespace
 	description = strings.TrimSpace(description)
@@ -129,14 +130,5 @@ func startTimer(args []string) {
 // formatElapsedTime formats a duration as human-readable elapsed time
 // Examples: "5m", "1h 23m", "2h"
 func formatElapsedTime(d time.Duration) string {
-	totalMinutes := int(d.Minutes())
-	if totalMinutes < 60 {
-		return fmt.Sprintf("%dm", totalMinutes)
-	}
-	hours := totalMinutes / 60
-	mins := totalMinutes % 60
-	if mins == 0 {
-		return fmt.Sprintf("%dh", hours)
-	}
-	return fmt.Sprintf("%dh %dm", hours, mins)
+	return timeutil.FormatDuration(int(d.Minutes()))
 }
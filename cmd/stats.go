@@ -1,32 +1,82 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"os"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/charmbracelet/x/term"
 	"github.com/spf13/cobra"
 	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/query"
 	"github.com/xolan/did/internal/stats"
 	"github.com/xolan/did/internal/storage"
-	"github.com/xolan/did/internal/timeutil"
 )
 
 // statsCmd represents the stats command
 var statsCmd = &cobra.Command{
-	Use:   "stats",
+	Use:   "stats [@project] [#tag]",
 	Short: "Show summary statistics for time entries",
 	Long: `Show aggregated statistics for your time tracking entries.
 
 Display summary statistics including:
   - Total hours logged
   - Average daily hours
-  - Number of entries
+  - Average entry length and number of entries
+  - How promptly entries were logged: the median time from an entry's
+    timestamp to when it was logged, and the share of entries logged more
+    than a day late (entries logged before this was recorded are left out)
   - Breakdown by project and tag (when available)
   - Comparison to previous period
 
 By default, statistics are shown for the current week (Monday-Sunday).
-Use the --month flag to show statistics for the current month instead.
+Use the --month flag to show statistics for the current month instead, or any
+of the time period flags of the root command (--yesterday, --this-week,
+--prev-week, --this-month, --prev-month, --last, --date, --from/--to) to pick
+another period. The comparison is against the period of the same length just
+before it; a --to without --from has no comparison.
+
+Use --project and --tag (or @project and #tag) to only count matching entries.
+
+Use --chart to show the project and tag breakdowns as horizontal bar charts
+scaled to the terminal width (80 columns when output is not a terminal).
+
+Use --cumulative to show every day of the period with its total and the
+running total so far; days without entries show 0 and keep the running total.
+Days are those of the configured timezone. With --chart the running total is
+drawn as bars.
+
+Use --weekday-profile to show the time logged per day of the week (in the
+configured timezone, from the configured week start): the total and the
+average per occurrence of the weekday, counting the days without entries, e.g.
+to see that you log twice as much on Tuesdays. Pick a long period such as
+--last 3m. With --chart the averages are drawn as bars; with --format tsv or
+plain one row per weekday is written instead of the totals.
+
+Use --hours to show totals as decimal hours (1.50 instead of 1h 30m), the
+same values as the duration_hours column of 'did export csv', e.g. to
+reconcile a timesheet. The decimal separator follows number_format.
+
+Use --round-display N to count every entry rounded to the nearest N minutes
+(never below N), e.g. for billing in quarter hours; round_display_minutes in
+the config sets a default. Stored durations are not changed.
+
+Use --json for machine-readable output, e.g. for editor plugins. Entries
+without a project or tag are reported under an empty name.
+
+Use --format tsv or --format plain to embed the totals in other tools (awk,
+a tmux status line). Both write total minutes, entry count, days tracked,
+average minutes per day and per entry, and the difference in minutes to the
+previous period: tsv as one tab-separated row (with column names on a first
+row with --header), plain one value per line. Durations are whole minutes.
+
+An entry counts on the day it starts. Use --split-days (or set
+split_at_midnight in the config) to apportion an entry running past midnight
+to each day it covers; each part then counts as an entry.
 
 Examples:
 
@@ -36,10 +86,39 @@ Examples:
   Monthly statistics:
     did stats --month                  Show statistics for this month
 
+  Other periods and filters:
+    did stats --yesterday              Show statistics for yesterday
+    did stats --prev-week              Show statistics for the previous week
+    did stats -l 30                    Show statistics for the last 30 days
+    did stats --this-week @acme        This week's statistics for project 'acme'
+
+  Bar charts:
+    did stats --chart                  Show breakdowns as bar charts
+    did stats --month --chart          Monthly breakdowns as bar charts
+
+  Running total:
+    did stats --cumulative             Each day's total and the running total
+    did stats --cumulative --chart     The running total as bar charts
+
+  Weekday profile:
+    did stats --weekday-profile -l 3m  Average time per weekday, last 3 months
+    did stats --weekday-profile --chart -l 3m
+
+  Decimal hours:
+    did stats --hours                  Totals as decimal hours
+
+  JSON output:
+    did stats --json                   Weekly statistics as JSON
+
+  Other tools:
+    did stats --format tsv --header    Totals as tab-separated values
+    did stats --format plain | head -1 This week's total minutes
+
 The stats command provides insights into your productivity patterns and
 time distribution, helping you understand where your time goes.`,
+	ValidArgsFunction: completeEntryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		runStats(cmd, args)
+		runStats(cmd, parseShorthandFilters(cmd, args))
 	},
 }
 
@@ -48,12 +127,147 @@ func init() {
 
 	// Add --month flag to switch from week to month view
 	statsCmd.Flags().Bool("month", false, "Show statistics for current month instead of week")
+	statsCmd.Flags().Bool("chart", false, "Show project and tag breakdowns as bar charts")
+	statsCmd.Flags().Bool("cumulative", false, "Show each day's total and the running total over the period")
+	statsCmd.Flags().Bool("weekday-profile", false, "Show the total and average time logged per day of the week")
+	statsCmd.Flags().Bool("hours", false, "Show durations as decimal hours (e.g. 1.50), matching the duration_hours column of CSV exports")
+	statsCmd.Flags().Bool("json", false, "Output statistics as JSON")
+	addFormatFlags(statsCmd)
+	statsCmd.Flags().Bool("split-days", false, "Apportion entries running past midnight to each day they cover (default: split_at_midnight)")
+
+	// Same time period flags as the root command
+	addTimePeriodFlags(statsCmd, "Show statistics for")
+	addRoundDisplayFlag(statsCmd)
+}
+
+// defaultChartWidth is the chart width used when stdout is not a terminal
+const defaultChartWidth = 80
+
+// chartBlocks are the block characters used to draw bars in eighths of a cell
+var chartBlocks = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉", "█"}
+
+// terminalWidth returns the width of the terminal stdout is attached to,
+// or defaultChartWidth when stdout is not a terminal.
+// Tests replace it to render at a fixed width.
+var terminalWidth = func() int {
+	f, ok := deps.Stdout.(*os.File)
+	if !ok || !term.IsTerminal(f.Fd()) {
+		return defaultChartWidth
+	}
+	width, _, err := term.GetSize(f.Fd())
+	if err != nil || width <= 0 {
+		return defaultChartWidth
+	}
+	return width
+}
+
+// metadataSummary is the JSON form of a project or tag with its totals,
+// shared by the stats, projects and tags commands
+type metadataSummary struct {
+	Name         string `json:"name"`
+	Count        int    `json:"count"`
+	TotalMinutes int    `json:"total_minutes"`
+}
+
+// statsJSON is the JSON form of the stats command output
+type statsJSON struct {
+	Period               string            `json:"period"`
+	Start                time.Time         `json:"start"`
+	End                  time.Time         `json:"end"`
+	TotalMinutes         int               `json:"total_minutes"`
+	AverageMinutesPerDay float64           `json:"average_minutes_per_day"`
+	AverageEntryMinutes  float64           `json:"average_minutes_per_entry"`
+	EntryCount           int               `json:"entry_count"`
+	DaysTracked          int               `json:"days_tracked"`
+	ComparisonMinutes    int               `json:"comparison_minutes"`
+	LoggingLatency       *latencyJSON      `json:"logging_latency,omitempty"`
+	Days                 []dayJSON         `json:"days,omitempty"`
+	Weekdays             []weekdayJSON     `json:"weekdays,omitempty"`
+	Projects             []metadataSummary `json:"projects"`
+	Tags                 []metadataSummary `json:"tags"`
+}
+
+// statsColumns are the columns of stats --format tsv and plain
+var statsColumns = []string{"total_minutes", "entry_count", "days_tracked", "average_minutes_per_day", "average_minutes_per_entry", "comparison_minutes"}
+
+// dayJSON is the JSON form of a day of the --cumulative output
+type dayJSON struct {
+	Date              string `json:"date"`
+	Minutes           int    `json:"minutes"`
+	CumulativeMinutes int    `json:"cumulative_minutes"`
+}
+
+// weekdayJSON is the JSON form of a day of the week of the --weekday-profile output
+type weekdayJSON struct {
+	Weekday        string  `json:"weekday"`
+	Days           int     `json:"days"`
+	TotalMinutes   int     `json:"total_minutes"`
+	AverageMinutes float64 `json:"average_minutes"`
+}
+
+// weekdayColumns are the columns of stats --weekday-profile --format tsv and plain
+var weekdayColumns = []string{"weekday", "days", "total_minutes", "average_minutes"}
+
+// latencyJSON is the JSON form of the logging latency, omitted when no entry
+// records when it was logged
+type latencyJSON struct {
+	EntryCount     int     `json:"entry_count"`
+	MedianMinutes  float64 `json:"median_minutes"`
+	BackdatedShare float64 `json:"backdated_share"`
+}
+
+// chartRow is a single labeled bar in a chart
+type chartRow struct {
+	Label   string
+	Minutes int
 }
 
 // runStats handles the stats command logic
 func runStats(cmd *cobra.Command, args []string) {
 	// Get flag values
 	showMonth, _ := cmd.Flags().GetBool("month")
+	showChart, _ := cmd.Flags().GetBool("chart")
+	showCumulative, _ := cmd.Flags().GetBool("cumulative")
+	showWeekdays, _ := cmd.Flags().GetBool("weekday-profile")
+	asJSON, _ := cmd.Flags().GetBool("json")
+	format := statsDurationFormatter(cmd)
+	outFormat, ok := outputFormat(cmd)
+	if !ok {
+		return
+	}
+	roundStep, ok := roundDisplayStep(cmd, deps.Config.RoundDisplayMinutes)
+	if !ok {
+		return
+	}
+
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "@") && !strings.HasPrefix(arg, "#") {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Unexpected argument '%s'\n", arg)
+			_, _ = fmt.Fprintln(deps.Stderr, "Stats only accept @project and #tag filters")
+			deps.Exit(1)
+			return
+		}
+	}
+
+	// Determine the time period: --month, one of the time period flags, or this week
+	c, ok := resolveQuery(cmd)
+	if !ok {
+		return
+	}
+	switch {
+	case c.HasPeriod() && showMonth:
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: --month cannot be combined with time period flags")
+		_, _ = fmt.Fprintln(deps.Stderr, "Use --this-month instead")
+		deps.Exit(1)
+		return
+	case showMonth:
+		c.Period = query.Month(configuredNow(), deps.Config.MonthStartDay, false)
+	case !c.HasPeriod():
+		// Use configured week_start_day for weekly statistics
+		c.Period = query.Week(configuredNow(), deps.Config.WeekStartDay, false)
+	}
+	start, end := c.Period.Start, c.Period.End
+	previous, hasPrevious := c.Period.Previous()
 
 	// Get storage path
 	storagePath, err := deps.StoragePath()
@@ -76,51 +290,102 @@ func runStats(cmd *cobra.Command, args []string) {
 	}
 
 	// Display warnings about corrupted lines to stderr
-	if len(result.Warnings) > 0 {
-		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Found %d corrupted line(s) in storage file:\n", len(result.Warnings))
-		for _, warning := range result.Warnings {
-			_, _ = fmt.Fprintln(deps.Stderr, formatCorruptionWarning(warning))
-		}
-		_, _ = fmt.Fprintln(deps.Stderr)
-	}
+	printCorruptionWarnings(result.Warnings)
+	printSuspectWarnings(countSuspect(result.Entries, c.Matches))
 
-	// Filter out soft-deleted entries
+	// Filter out soft-deleted entries and entries not matching --project/--client/--tag.
+	// The period is applied by the statistics, which also cover the previous period.
+	filters := query.Criteria{Project: c.Project, Client: c.Client, Tags: c.Tags}
 	var activeEntries []entry.Entry
-	for _, e := range result.Entries {
+	for _, e := range filters.Apply(result.Entries) {
 		if e.DeletedAt == nil {
 			activeEntries = append(activeEntries, e)
 		}
 	}
-
-	// Determine the time period based on --month flag
-	var start, end time.Time
-	var prevStart, prevEnd time.Time
-	var periodName string
-	var comparisonPeriod string
-	if showMonth {
-		start, end = timeutil.ThisMonth()
-		prevStart, prevEnd = timeutil.LastMonth()
-		periodName = "this month"
-		comparisonPeriod = "month"
-	} else {
-		// Use configured week_start_day for weekly statistics
-		now := time.Now()
-		start = timeutil.StartOfWeekWithConfig(now, deps.Config.WeekStartDay)
-		end = timeutil.EndOfWeekWithConfig(now, deps.Config.WeekStartDay)
-
-		lastWeek := now.AddDate(0, 0, -7)
-		prevStart = timeutil.StartOfWeekWithConfig(lastWeek, deps.Config.WeekStartDay)
-		prevEnd = timeutil.EndOfWeekWithConfig(lastWeek, deps.Config.WeekStartDay)
-
-		periodName = "this week"
-		comparisonPeriod = "week"
-	}
+	// Latency is measured from the start of each entry, before it is split
+	latency := stats.CalculateLoggingLatency(activeEntries, start, end)
+	activeEntries = splitDays(cmd, activeEntries)
+	activeEntries = roundDisplayEntries(activeEntries, roundStep)
+	periodName := c.Describe(c.Period.Name)
 
 	// Calculate statistics for current period
 	statistics := stats.CalculateStatistics(activeEntries, start, end)
+	var days []stats.DayTotal
+	var weekdays []stats.WeekdayTotal
+	if showCumulative || showWeekdays {
+		days = stats.DailyTotals(activeEntries, start, end, configuredLocation())
+	}
+	if showWeekdays {
+		weekdays = stats.WeekdayProfile(days, weekStartWeekday())
+	}
 
 	// Calculate statistics for previous period for comparison
-	previousStatistics := stats.CalculateStatistics(activeEntries, prevStart, prevEnd)
+	previousStatistics := statistics
+	if hasPrevious {
+		previousStatistics = stats.CalculateStatistics(activeEntries, previous.Start, previous.End)
+	}
+
+	if asJSON {
+		output := statsJSON{
+			Period:               periodName,
+			Start:                start,
+			End:                  end,
+			TotalMinutes:         statistics.TotalMinutes,
+			AverageMinutesPerDay: statistics.AverageMinutesPerDay,
+			AverageEntryMinutes:  statistics.AverageMinutesPerEntry,
+			EntryCount:           statistics.EntryCount,
+			DaysTracked:          statistics.DaysWithEntries,
+			ComparisonMinutes:    stats.CompareStatistics(statistics, previousStatistics),
+			Projects:             sortedSummaries(projectSummaries(stats.CalculateProjectBreakdown(activeEntries, start, end), true)),
+			Tags:                 sortedSummaries(tagSummaries(stats.CalculateTagBreakdown(activeEntries, start, end), true)),
+		}
+		for _, w := range weekdays {
+			output.Weekdays = append(output.Weekdays, weekdayJSON{
+				Weekday:        w.Weekday.String(),
+				Days:           w.Days,
+				TotalMinutes:   w.Minutes,
+				AverageMinutes: w.AverageMinutes(),
+			})
+		}
+		if !showCumulative {
+			days = nil
+		}
+		for _, day := range days {
+			output.Days = append(output.Days, dayJSON{
+				Date:              day.Date.Format("2006-01-02"),
+				Minutes:           day.Minutes,
+				CumulativeMinutes: day.CumulativeMinutes,
+			})
+		}
+		if latency.EntryCount > 0 {
+			output.LoggingLatency = &latencyJSON{
+				EntryCount:     latency.EntryCount,
+				MedianMinutes:  latency.Median.Minutes(),
+				BackdatedShare: latency.BackdatedShare(),
+			}
+		}
+		writeJSONOutput(output)
+		return
+	}
+	if outFormat != formatHuman && showWeekdays {
+		rows := make([][]string, len(weekdays))
+		for i, w := range weekdays {
+			rows[i] = []string{w.Weekday.String()[:3], fmt.Sprint(w.Days), fmt.Sprint(w.Minutes), fmt.Sprintf("%.2f", w.AverageMinutes())}
+		}
+		writeRecords(cmd, outFormat, weekdayColumns, rows)
+		return
+	}
+	if outFormat != formatHuman {
+		writeRecords(cmd, outFormat, statsColumns, [][]string{{
+			fmt.Sprint(statistics.TotalMinutes),
+			fmt.Sprint(statistics.EntryCount),
+			fmt.Sprint(statistics.DaysWithEntries),
+			fmt.Sprintf("%.2f", statistics.AverageMinutesPerDay),
+			fmt.Sprintf("%.2f", statistics.AverageMinutesPerEntry),
+			fmt.Sprint(stats.CompareStatistics(statistics, previousStatistics)),
+		}})
+		return
+	}
 
 	// Display header
 	_, _ = fmt.Fprintf(deps.Stdout, "Statistics for %s\n", periodName)
@@ -128,47 +393,111 @@ func runStats(cmd *cobra.Command, args []string) {
 	_, _ = fmt.Fprintln(deps.Stdout)
 
 	// Display statistics
-	displayStatistics(statistics)
+	displayStatistics(statistics, format)
+	displayLoggingLatency(latency)
 
 	// Display comparison to previous period
-	diffMinutes := stats.CompareStatistics(statistics, previousStatistics)
-	comparison := stats.FormatComparison(diffMinutes, comparisonPeriod)
-	_, _ = fmt.Fprintf(deps.Stdout, "Comparison:      %s\n", comparison)
-	_, _ = fmt.Fprintln(deps.Stdout)
+	if hasPrevious {
+		diffMinutes := stats.CompareStatistics(statistics, previousStatistics)
+		comparison := stats.FormatComparison(diffMinutes, c.Period.Unit)
+		_, _ = fmt.Fprintf(deps.Stdout, "Comparison:      %s\n", comparison)
+		_, _ = fmt.Fprintln(deps.Stdout)
+	}
+
+	// Display the time logged per day of the week
+	if showWeekdays {
+		if showChart {
+			displayWeekdayChart(weekdays, format)
+		} else {
+			displayWeekdayProfile(weekdays, format)
+		}
+	}
+
+	// Display the running total per day
+	if showCumulative && len(days) > 0 {
+		if showChart {
+			displayCumulativeChart(days, format)
+		} else {
+			displayCumulative(days, format)
+		}
+	}
 
 	// Calculate and display project breakdown if projects exist
 	projectBreakdown := stats.CalculateProjectBreakdown(activeEntries, start, end)
 	if len(projectBreakdown) > 0 {
-		displayProjectBreakdown(projectBreakdown)
+		if showChart {
+			displayProjectChart(projectBreakdown, statistics.TotalMinutes, format)
+		} else {
+			displayProjectBreakdown(projectBreakdown, format)
+		}
 	}
 
 	// Calculate and display tag breakdown if tags exist
 	tagBreakdown := stats.CalculateTagBreakdown(activeEntries, start, end)
 	if len(tagBreakdown) > 0 {
-		displayTagBreakdown(tagBreakdown)
+		if showChart {
+			displayTagChart(tagBreakdown, statistics.TotalMinutes, format)
+		} else {
+			displayTagBreakdown(tagBreakdown, format)
+		}
+	}
+}
+
+// statsDurationFormatter returns how stats shows durations: like "1h 30m", or
+// as decimal hours like "1.50" with --hours
+func statsDurationFormatter(cmd *cobra.Command) func(minutes int) string {
+	if hours, _ := cmd.Flags().GetBool("hours"); hours {
+		return deps.Config.EffectiveNumberFormat().FormatDuration
 	}
+	return formatDuration
 }
 
-// displayStatistics formats and displays statistics to stdout
-func displayStatistics(stats stats.Statistics) {
+// displayStatistics formats and displays statistics to stdout, with durations
+// written by format
+func displayStatistics(stats stats.Statistics, format func(minutes int) string) {
 	// Display total hours
-	_, _ = fmt.Fprintf(deps.Stdout, "Total Hours:     %s\n", formatDuration(stats.TotalMinutes))
+	_, _ = fmt.Fprintf(deps.Stdout, "Total Hours:     %s\n", format(stats.TotalMinutes))
 
 	// Display average daily hours
 	avgHours := stats.AverageMinutesPerDay / 60.0
-	_, _ = fmt.Fprintf(deps.Stdout, "Average/Day:     %.1fh\n", avgHours)
+	_, _ = fmt.Fprintf(deps.Stdout, "Average/Day:     %sh\n", deps.Config.EffectiveNumberFormat().Format(avgHours, 1))
 
 	// Display entry count
-	_, _ = fmt.Fprintf(deps.Stdout, "Entries:         %d %s\n", stats.EntryCount, pluralize("entry", stats.EntryCount))
+	_, _ = fmt.Fprintf(deps.Stdout, "Entries:         %s\n", formatCount(stats.EntryCount, "entry", "entries"))
+
+	// Display average entry length
+	if stats.EntryCount > 0 {
+		_, _ = fmt.Fprintf(deps.Stdout, "Average/Entry:   %s\n", format(int(math.Round(stats.AverageMinutesPerEntry))))
+	}
 
 	// Display days with entries (useful context)
-	_, _ = fmt.Fprintf(deps.Stdout, "Days Tracked:    %d %s\n", stats.DaysWithEntries, pluralize("day", stats.DaysWithEntries))
+	_, _ = fmt.Fprintf(deps.Stdout, "Days Tracked:    %s\n", formatCount(stats.DaysWithEntries, "day", "days"))
 
 	_, _ = fmt.Fprintln(deps.Stdout)
 }
 
+// displayLoggingLatency shows how promptly entries were logged, if any entry
+// records when it was logged
+func displayLoggingLatency(latency stats.LoggingLatency) {
+	if latency.EntryCount == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(deps.Stdout, "Log Latency:     %s median (%s)\n", formatLatency(latency.Median), formatCount(latency.EntryCount, "entry", "entries"))
+	_, _ = fmt.Fprintf(deps.Stdout, "Backdated >1d:   %.0f%%\n", latency.BackdatedShare()*100)
+	_, _ = fmt.Fprintln(deps.Stdout)
+}
+
+// formatLatency formats a logging latency, showing "<1m" for entries logged
+// within the minute
+func formatLatency(d time.Duration) string {
+	if d < time.Minute {
+		return "<1m"
+	}
+	return formatDuration(int(d / time.Minute))
+}
+
 // displayProjectBreakdown formats and displays project breakdown to stdout
-func displayProjectBreakdown(breakdowns []stats.ProjectBreakdown) {
+func displayProjectBreakdown(breakdowns []stats.ProjectBreakdown, format func(minutes int) string) {
 	_, _ = fmt.Fprintln(deps.Stdout, "By Project:")
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("-", 60))
 	_, _ = fmt.Fprintln(deps.Stdout)
@@ -177,21 +506,21 @@ func displayProjectBreakdown(breakdowns []stats.ProjectBreakdown) {
 		// Format project name with special handling for "(no project)"
 		projectDisplay := breakdown.Project
 		if breakdown.Project != "(no project)" {
-			projectDisplay = "@" + breakdown.Project
+			projectDisplay = "@" + entry.QuoteName(breakdown.Project)
 		}
 
 		_, _ = fmt.Fprintf(deps.Stdout, "  %-28s  %10s  (%d %s)\n",
 			projectDisplay,
-			formatDuration(breakdown.TotalMinutes),
+			format(breakdown.TotalMinutes),
 			breakdown.EntryCount,
-			pluralize("entry", breakdown.EntryCount))
+			pluralize("entry", "entries", breakdown.EntryCount))
 	}
 
 	_, _ = fmt.Fprintln(deps.Stdout)
 }
 
 // displayTagBreakdown formats and displays tag breakdown to stdout
-func displayTagBreakdown(breakdowns []stats.TagBreakdown) {
+func displayTagBreakdown(breakdowns []stats.TagBreakdown, format func(minutes int) string) {
 	_, _ = fmt.Fprintln(deps.Stdout, "By Tag:")
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("-", 60))
 	_, _ = fmt.Fprintln(deps.Stdout)
@@ -200,15 +529,231 @@ func displayTagBreakdown(breakdowns []stats.TagBreakdown) {
 		// Format tag name with special handling for "(no tags)"
 		tagDisplay := breakdown.Tag
 		if breakdown.Tag != "(no tags)" {
-			tagDisplay = "#" + breakdown.Tag
+			tagDisplay = "#" + entry.QuoteName(breakdown.Tag)
 		}
 
 		_, _ = fmt.Fprintf(deps.Stdout, "  %-28s  %10s  (%d %s)\n",
 			tagDisplay,
-			formatDuration(breakdown.TotalMinutes),
+			format(breakdown.TotalMinutes),
 			breakdown.EntryCount,
-			pluralize("entry", breakdown.EntryCount))
+			pluralize("entry", "entries", breakdown.EntryCount))
 	}
 
 	_, _ = fmt.Fprintln(deps.Stdout)
 }
+
+// displayCumulative displays each day's total and the running total
+func displayCumulative(days []stats.DayTotal, format func(minutes int) string) {
+	_, _ = fmt.Fprintln(deps.Stdout, "By Day:")
+	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("-", 60))
+	_, _ = fmt.Fprintln(deps.Stdout)
+
+	_, _ = fmt.Fprintf(deps.Stdout, "  %-10s  %10s  %10s\n", "", "Day", "Cumulative")
+	for _, day := range days {
+		_, _ = fmt.Fprintf(deps.Stdout, "  %-10s  %10s  %10s\n",
+			day.Date.Format("Mon Jan 02"),
+			format(day.Minutes),
+			format(day.CumulativeMinutes))
+	}
+
+	_, _ = fmt.Fprintln(deps.Stdout)
+}
+
+// displayCumulativeChart displays the running total per day as a bar chart,
+// with percentages of the period total
+func displayCumulativeChart(days []stats.DayTotal, format func(minutes int) string) {
+	rows := make([]chartRow, 0, len(days))
+	for _, day := range days {
+		rows = append(rows, chartRow{Label: day.Date.Format("Mon Jan 02"), Minutes: day.CumulativeMinutes})
+	}
+	displayBarChart("By Day (cumulative):", rows, days[len(days)-1].CumulativeMinutes, terminalWidth(), format)
+}
+
+// weekStartWeekday returns the configured first day of the week
+func weekStartWeekday() time.Weekday {
+	if deps.Config.WeekStartDay == "sunday" {
+		return time.Sunday
+	}
+	return time.Monday
+}
+
+// displayWeekdayProfile displays the total and average time logged per day of
+// the week; the average counts the days without entries
+func displayWeekdayProfile(weekdays []stats.WeekdayTotal, format func(minutes int) string) {
+	_, _ = fmt.Fprintln(deps.Stdout, "By Weekday:")
+	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("-", 60))
+	_, _ = fmt.Fprintln(deps.Stdout)
+
+	_, _ = fmt.Fprintf(deps.Stdout, "  %-3s  %10s  %10s  %s\n", "", "Total", "Average", "Days")
+	for _, w := range weekdays {
+		_, _ = fmt.Fprintf(deps.Stdout, "  %-3s  %10s  %10s  %d\n",
+			w.Weekday.String()[:3],
+			format(w.Minutes),
+			format(int(math.Round(w.AverageMinutes()))),
+			w.Days)
+	}
+
+	_, _ = fmt.Fprintln(deps.Stdout)
+}
+
+// displayWeekdayChart displays the average time logged per day of the week as
+// a bar chart, with percentages of an average week
+func displayWeekdayChart(weekdays []stats.WeekdayTotal, format func(minutes int) string) {
+	rows := make([]chartRow, 0, len(weekdays))
+	weekMinutes := 0
+	for _, w := range weekdays {
+		average := int(math.Round(w.AverageMinutes()))
+		rows = append(rows, chartRow{Label: w.Weekday.String()[:3], Minutes: average})
+		weekMinutes += average
+	}
+	displayBarChart("By Weekday (average):", rows, weekMinutes, terminalWidth(), format)
+}
+
+// displayProjectChart displays the project breakdown as a bar chart
+func displayProjectChart(breakdowns []stats.ProjectBreakdown, totalMinutes int, format func(minutes int) string) {
+	rows := make([]chartRow, 0, len(breakdowns))
+	for _, breakdown := range breakdowns {
+		label := breakdown.Project
+		if breakdown.Project != "(no project)" {
+			label = "@" + entry.QuoteName(breakdown.Project)
+		}
+		rows = append(rows, chartRow{Label: label, Minutes: breakdown.TotalMinutes})
+	}
+	displayBarChart("By Project:", rows, totalMinutes, terminalWidth(), format)
+}
+
+// displayTagChart displays the tag breakdown as a bar chart.
+// Percentages are relative to the period total, so they may add up to more
+// than 100% when entries have several tags.
+func displayTagChart(breakdowns []stats.TagBreakdown, totalMinutes int, format func(minutes int) string) {
+	rows := make([]chartRow, 0, len(breakdowns))
+	for _, breakdown := range breakdowns {
+		label := breakdown.Tag
+		if breakdown.Tag != "(no tags)" {
+			label = "#" + entry.QuoteName(breakdown.Tag)
+		}
+		rows = append(rows, chartRow{Label: label, Minutes: breakdown.TotalMinutes})
+	}
+	displayBarChart("By Tag:", rows, totalMinutes, terminalWidth(), format)
+}
+
+// displayBarChart renders one horizontal bar per row, scaled so the largest row
+// fills the space left after the label and the trailing duration and percent
+func displayBarChart(title string, rows []chartRow, totalMinutes, width int, format func(minutes int) string) {
+	_, _ = fmt.Fprintln(deps.Stdout, title)
+	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("-", 60))
+	_, _ = fmt.Fprintln(deps.Stdout)
+
+	labelWidth := 0
+	maxMinutes := 0
+	for _, row := range rows {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(row.Label))
+		maxMinutes = max(maxMinutes, row.Minutes)
+	}
+	labelWidth = min(labelWidth, 28)
+
+	// "  <label>  <bar> <duration> (<percent>)"
+	const suffixWidth = len(" 999h 59m (100%)")
+	barWidth := max(width-2-labelWidth-2-suffixWidth, 10)
+
+	for _, row := range rows {
+		label := row.Label
+		if utf8.RuneCountInString(label) > labelWidth {
+			label = string([]rune(label)[:labelWidth-1]) + "…"
+		}
+
+		percent := 0
+		if totalMinutes > 0 {
+			percent = (row.Minutes*100 + totalMinutes/2) / totalMinutes
+		}
+
+		_, _ = fmt.Fprintf(deps.Stdout, "  %-*s  %s %s (%d%%)\n",
+			labelWidth+len(label)-utf8.RuneCountInString(label), label,
+			renderBar(row.Minutes, maxMinutes, barWidth),
+			format(row.Minutes),
+			percent)
+	}
+
+	_, _ = fmt.Fprintln(deps.Stdout)
+}
+
+// renderBar draws a bar of up to width cells for value relative to maxValue,
+// using eighth-cell block characters for the fractional end. Non-zero values
+// always get at least a sliver so they remain visible.
+func renderBar(value, maxValue, width int) string {
+	if value <= 0 || maxValue <= 0 {
+		return ""
+	}
+
+	eighths := value * width * 8 / maxValue
+	if eighths == 0 {
+		eighths = 1
+	}
+	return strings.Repeat(chartBlocks[8], eighths/8) + chartBlocks[eighths%8]
+}
+
+// sparkBlocks are the block characters of a sparkline, from lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// renderSparkline draws each value as a block scaled to the largest value;
+// zero values get the lowest block
+func renderSparkline(values []int) string {
+	maxValue := 0
+	for _, v := range values {
+		maxValue = max(maxValue, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if maxValue > 0 && v > 0 {
+			level = int(math.Round(float64(v) / float64(maxValue) * float64(len(sparkBlocks)-1)))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// projectSummaries converts a project breakdown to its JSON form. Entries
+// without a project get an empty name, or are left out unless includeNone is set.
+func projectSummaries(breakdowns []stats.ProjectBreakdown, includeNone bool) []metadataSummary {
+	summaries := []metadataSummary{}
+	for _, b := range breakdowns {
+		name := b.Project
+		if name == "(no project)" {
+			if !includeNone {
+				continue
+			}
+			name = ""
+		}
+		summaries = append(summaries, metadataSummary{Name: name, Count: b.EntryCount, TotalMinutes: b.TotalMinutes})
+	}
+	return summaries
+}
+
+// tagSummaries converts a tag breakdown to its JSON form. Entries without
+// tags get an empty name, or are left out unless includeNone is set.
+func tagSummaries(breakdowns []stats.TagBreakdown, includeNone bool) []metadataSummary {
+	summaries := []metadataSummary{}
+	for _, b := range breakdowns {
+		name := b.Tag
+		if name == "(no tags)" {
+			if !includeNone {
+				continue
+			}
+			name = ""
+		}
+		summaries = append(summaries, metadataSummary{Name: name, Count: b.EntryCount, TotalMinutes: b.TotalMinutes})
+	}
+	return summaries
+}
+
+// writeJSONOutput writes v to stdout as indented JSON
+func writeJSONOutput(v interface{}) {
+	encoder := json.NewEncoder(deps.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to encode JSON output")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		deps.Exit(1)
+	}
+}
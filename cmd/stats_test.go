@@ -2,14 +2,18 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
+	"github.com/xolan/did/internal/config"
 	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/stats"
 	"github.com/xolan/did/internal/storage"
 	"github.com/xolan/did/internal/timeutil"
 )
@@ -20,7 +24,7 @@ func createStatsTestEntries(t *testing.T, storagePath string) []entry.Entry {
 
 	now := time.Now()
 	// Get the start of this week to ensure entries are in current week
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 
 	entries := []entry.Entry{
 		{
@@ -103,8 +107,8 @@ func TestStats_DefaultWeek_Basic(t *testing.T) {
 	if !strings.Contains(output, "Entries:") {
 		t.Error("Expected 'Entries:' label in output")
 	}
-	if !strings.Contains(output, "3 entrys") {
-		t.Errorf("Expected '3 entrys', got: %s", output)
+	if !strings.Contains(output, "3 entries") {
+		t.Errorf("Expected '3 entries', got: %s", output)
 	}
 	// Should show days tracked
 	if !strings.Contains(output, "Days Tracked:") {
@@ -145,8 +149,8 @@ func TestStats_DefaultWeek_EmptyStorage(t *testing.T) {
 	if !strings.Contains(output, "Total Hours:     0m") {
 		t.Errorf("Expected 'Total Hours: 0m', got: %s", output)
 	}
-	if !strings.Contains(output, "0 entrys") {
-		t.Errorf("Expected '0 entrys', got: %s", output)
+	if !strings.Contains(output, "0 entries") {
+		t.Errorf("Expected '0 entries', got: %s", output)
 	}
 	if !strings.Contains(output, "0 days") {
 		t.Errorf("Expected '0 days', got: %s", output)
@@ -190,7 +194,7 @@ func TestStats_DefaultWeek_NoEntriesInPeriod(t *testing.T) {
 	if !strings.Contains(output, "Total Hours:     0m") {
 		t.Errorf("Expected zero total hours, got: %s", output)
 	}
-	if !strings.Contains(output, "0 entrys") {
+	if !strings.Contains(output, "0 entries") {
 		t.Errorf("Expected zero entries, got: %s", output)
 	}
 }
@@ -202,7 +206,7 @@ func TestStats_Month_Basic(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Create entries in current month
-	startOfMonth, _ := timeutil.ThisMonth()
+	startOfMonth, _ := timeutil.ThisMonth(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       startOfMonth,
@@ -253,8 +257,8 @@ func TestStats_Month_Basic(t *testing.T) {
 		t.Errorf("Expected '5h' in output, got: %s", output)
 	}
 	// Should show entries count
-	if !strings.Contains(output, "2 entrys") {
-		t.Errorf("Expected '2 entrys', got: %s", output)
+	if !strings.Contains(output, "2 entries") {
+		t.Errorf("Expected '2 entries', got: %s", output)
 	}
 	// Should show days tracked
 	if !strings.Contains(output, "2 days") {
@@ -302,7 +306,7 @@ func TestStats_Month_EmptyPeriod(t *testing.T) {
 	if !strings.Contains(output, "Total Hours:     0m") {
 		t.Errorf("Expected zero total hours, got: %s", output)
 	}
-	if !strings.Contains(output, "0 entrys") {
+	if !strings.Contains(output, "0 entries") {
 		t.Errorf("Expected zero entries, got: %s", output)
 	}
 }
@@ -355,7 +359,7 @@ func TestStats_DurationFormatting(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Create entries with specific durations to test formatting
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       startOfWeek,
@@ -410,12 +414,26 @@ func TestStats_DurationFormatting(t *testing.T) {
 	}
 }
 
+func TestDisplayStatistics_NumberFormat(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.NumberFormat = "de"
+	d, stdout, _ := testDepsWithConfig(filepath.Join(t.TempDir(), "entries.jsonl"), cfg)
+	SetDeps(d)
+	defer ResetDeps()
+
+	displayStatistics(stats.Statistics{TotalMinutes: 900, AverageMinutesPerDay: 150, EntryCount: 3, DaysWithEntries: 6}, formatDuration)
+
+	if !strings.Contains(stdout.String(), "Average/Day:     2,5h") {
+		t.Errorf("Expected a decimal comma in the average, got: %s", stdout.String())
+	}
+}
+
 func TestStats_AverageCalculation(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Create entries totaling 7 hours (420 minutes) over a week
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       startOfWeek,
@@ -481,7 +499,7 @@ func TestStats_SoftDeletedEntriesExcluded(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	now := time.Now()
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       startOfWeek,
@@ -537,7 +555,7 @@ func TestStats_EntriesOutsideRangeExcluded(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	now := time.Now()
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       startOfWeek,
@@ -597,7 +615,7 @@ func TestStats_CorruptedStorageWarnings(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Create a valid entry
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 	validEntry := entry.Entry{
 		Timestamp:       startOfWeek,
 		Description:     "Valid entry",
@@ -686,8 +704,7 @@ func TestStats_StoragePathError(t *testing.T) {
 }
 
 func TestStats_ReadEntriesError(t *testing.T) {
-	// Use a path to a directory (not a file) to cause read error
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
 	exitCalled := false
 	stderr := &bytes.Buffer{}
@@ -697,7 +714,7 @@ func TestStats_ReadEntriesError(t *testing.T) {
 		Stdin:  strings.NewReader(""),
 		Exit:   func(code int) { exitCalled = true },
 		StoragePath: func() (string, error) {
-			return tmpDir, nil // path to directory, not file
+			return storagePath, nil
 		},
 	}
 	SetDeps(d)
@@ -763,7 +780,7 @@ func TestStats_Pluralization(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Create exactly 1 entry
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 	singleEntry := entry.Entry{
 		Timestamp:       startOfWeek,
 		Description:     "Single entry",
@@ -791,7 +808,7 @@ func TestStats_Pluralization(t *testing.T) {
 	runStats(statsCmd, []string{})
 
 	output := stdout.String()
-	// Should show "1 entry" (singular), not "1 entrys"
+	// Should show "1 entry" (singular), not "1 entries"
 	if !strings.Contains(output, "1 entry") {
 		t.Errorf("Expected '1 entry' (singular), got: %s", output)
 	}
@@ -807,7 +824,7 @@ func TestStats_ProjectBreakdown_ShowsWhenProjectsExist(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       startOfWeek,
@@ -869,11 +886,63 @@ func TestStats_ProjectBreakdown_ShowsWhenProjectsExist(t *testing.T) {
 	}
 }
 
+func TestStats_ClientFilter(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
+	for _, e := range []entry.Entry{
+		{Timestamp: startOfWeek, Description: "api work", DurationMinutes: 180, Project: "projectA", Client: "AcmeCorp"},
+		{Timestamp: startOfWeek.Add(time.Hour), Description: "other work", DurationMinutes: 120, Project: "projectB", Client: "Globex"},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	_ = rootCmd.PersistentFlags().Set("client", "AcmeCorp")
+	defer func() { _ = rootCmd.PersistentFlags().Set("client", "") }()
+
+	runStats(statsCmd, []string{})
+
+	output := stdout.String()
+	if !strings.Contains(output, "projectA") || strings.Contains(output, "projectB") {
+		t.Errorf("Expected only the entries of client AcmeCorp, got: %s", output)
+	}
+	if !strings.Contains(output, "Entries:         1 entry") {
+		t.Errorf("Expected the one entry of client AcmeCorp, got: %s", output)
+	}
+}
+
+func TestStats_ProjectBreakdown_MultiWordNames(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
+	e := entry.Entry{Timestamp: startOfWeek, Description: "planning", DurationMinutes: 60, Project: "Big Client", Tags: []string{"big tag"}}
+	if err := storage.AppendEntry(storagePath, e); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	runStats(statsCmd, []string{})
+
+	output := stdout.String()
+	for _, expected := range []string{`@"Big Client"`, `#"big tag"`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %s in output, got: %s", expected, output)
+		}
+	}
+}
+
 func TestStats_ProjectBreakdown_HiddenWhenNoProjects(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       startOfWeek,
@@ -963,7 +1032,7 @@ func TestStats_ProjectBreakdown_Sorting(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       startOfWeek,
@@ -1036,7 +1105,7 @@ func TestStats_TagBreakdown_ShowsWhenTagsExist(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       startOfWeek,
@@ -1102,7 +1171,7 @@ func TestStats_TagBreakdown_HiddenWhenNoTags(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       startOfWeek,
@@ -1192,7 +1261,7 @@ func TestStats_TagBreakdown_MultipleTagsPerEntry(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       startOfWeek,
@@ -1253,7 +1322,7 @@ func TestStats_TagBreakdown_Sorting(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       startOfWeek,
@@ -1326,7 +1395,7 @@ func TestStats_BothProjectAndTagBreakdown(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 	entries := []entry.Entry{
 		{
 			Timestamp:       startOfWeek,
@@ -1395,8 +1464,8 @@ func TestStats_Comparison_Increase(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Get current and last week
-	startOfWeek, _ := timeutil.ThisWeek()
-	lastWeekStart, _ := timeutil.LastWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
+	lastWeekStart, _ := timeutil.LastWeek(time.Now())
 
 	// Add entries for last week (2h)
 	lastWeekEntries := []entry.Entry{
@@ -1457,8 +1526,8 @@ func TestStats_Comparison_Decrease(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Get current and last week
-	startOfWeek, _ := timeutil.ThisWeek()
-	lastWeekStart, _ := timeutil.LastWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
+	lastWeekStart, _ := timeutil.LastWeek(time.Now())
 
 	// Add entries for last week (5h)
 	lastWeekEntries := []entry.Entry{
@@ -1519,8 +1588,8 @@ func TestStats_Comparison_Same(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Get current and last week
-	startOfWeek, _ := timeutil.ThisWeek()
-	lastWeekStart, _ := timeutil.LastWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
+	lastWeekStart, _ := timeutil.LastWeek(time.Now())
 
 	// Add entries for last week (3h)
 	lastWeekEntries := []entry.Entry{
@@ -1581,7 +1650,7 @@ func TestStats_Comparison_NoPreviousData(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Get current week
-	startOfWeek, _ := timeutil.ThisWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
 
 	// Add entries only for this week (no previous week entries)
 	thisWeekEntries := []entry.Entry{
@@ -1630,7 +1699,7 @@ func TestStats_Comparison_NoCurrentData(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Get last week
-	lastWeekStart, _ := timeutil.LastWeek()
+	lastWeekStart, _ := timeutil.LastWeek(time.Now())
 
 	// Add entries only for last week (no current week entries)
 	lastWeekEntries := []entry.Entry{
@@ -1679,8 +1748,8 @@ func TestStats_Comparison_WeekPeriod(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Get current and last week
-	startOfWeek, _ := timeutil.ThisWeek()
-	lastWeekStart, _ := timeutil.LastWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
+	lastWeekStart, _ := timeutil.LastWeek(time.Now())
 
 	// Add entries for both weeks
 	entries := []entry.Entry{
@@ -1736,8 +1805,8 @@ func TestStats_Comparison_MonthPeriod(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Get current and last month
-	startOfMonth, _ := timeutil.ThisMonth()
-	lastMonthStart, _ := timeutil.LastMonth()
+	startOfMonth, _ := timeutil.ThisMonth(time.Now())
+	lastMonthStart, _ := timeutil.LastMonth(time.Now())
 
 	// Add entries for both months
 	entries := []entry.Entry{
@@ -1796,8 +1865,8 @@ func TestStats_Comparison_DurationFormatting(t *testing.T) {
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 
 	// Get current and last week
-	startOfWeek, _ := timeutil.ThisWeek()
-	lastWeekStart, _ := timeutil.LastWeek()
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
+	lastWeekStart, _ := timeutil.LastWeek(time.Now())
 
 	// Add entries with a difference of 2h 30m (150 minutes)
 	entries := []entry.Entry{
@@ -1843,3 +1912,586 @@ func TestStats_Comparison_DurationFormatting(t *testing.T) {
 		t.Errorf("Expected 'up 2h 30m from last week' in output, got: %s", output)
 	}
 }
+
+func TestStats_Chart(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
+	entries := []entry.Entry{
+		{Timestamp: startOfWeek, Description: "feature", DurationMinutes: 180, RawInput: "feature @projectA #dev for 3h", Project: "projectA", Tags: []string{"dev"}},
+		{Timestamp: startOfWeek.Add(time.Hour), Description: "support", DurationMinutes: 60, RawInput: "support @projectB for 1h", Project: "projectB"},
+	}
+	for _, e := range entries {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	_ = statsCmd.Flags().Set("chart", "true")
+	defer func() { _ = statsCmd.Flags().Set("chart", "false") }()
+
+	runStats(statsCmd, []string{})
+
+	output := stdout.String()
+	var projectA, projectB string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "@projectA") {
+			projectA = line
+		}
+		if strings.Contains(line, "@projectB") {
+			projectB = line
+		}
+	}
+	if !strings.HasSuffix(projectA, "3h (75%)") {
+		t.Errorf("Expected projectA bar to end with duration and percent, got %q", projectA)
+	}
+	if !strings.HasSuffix(projectB, "1h (25%)") {
+		t.Errorf("Expected projectB bar to end with duration and percent, got %q", projectB)
+	}
+	if strings.Count(projectA, "█") <= strings.Count(projectB, "█") {
+		t.Errorf("Expected projectA bar to be longer than projectB bar:\n%s\n%s", projectA, projectB)
+	}
+	if utf8.RuneCountInString(projectA) > defaultChartWidth {
+		t.Errorf("Expected bar line to fit in %d columns, got %d: %q", defaultChartWidth, utf8.RuneCountInString(projectA), projectA)
+	}
+	if !strings.Contains(output, "#dev") || !strings.Contains(output, "By Tag:") {
+		t.Errorf("Expected tag chart in output, got: %s", output)
+	}
+}
+
+func TestStats_ChartUsesTerminalWidth(t *testing.T) {
+	origWidth := terminalWidth
+	defer func() { terminalWidth = origWidth }()
+
+	rows := []chartRow{{Label: "@acme", Minutes: 120}}
+	for _, width := range []int{60, 120} {
+		terminalWidth = func() int { return width }
+
+		d, stdout, _ := testDeps("")
+		SetDeps(d)
+		displayBarChart("By Project:", rows, 120, terminalWidth(), formatDuration)
+		ResetDeps()
+
+		var bar string
+		for _, line := range strings.Split(stdout.String(), "\n") {
+			if strings.Contains(line, "@acme") {
+				bar = line
+			}
+		}
+		if got := utf8.RuneCountInString(bar); got > width || got < width-len(" 999h 59m (100%)") {
+			t.Errorf("Expected bar line to span about %d columns, got %d: %q", width, got, bar)
+		}
+	}
+}
+
+func TestRenderBar(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    int
+		maxValue int
+		width    int
+		expected string
+	}{
+		{"full", 100, 100, 4, "████"},
+		{"half", 50, 100, 4, "██"},
+		{"fraction", 3, 16, 4, "▊"},
+		{"tiny value shows sliver", 1, 1000, 4, "▏"},
+		{"zero", 0, 100, 4, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderBar(tt.value, tt.maxValue, tt.width); got != tt.expected {
+				t.Errorf("renderBar(%d, %d, %d) = %q, expected %q", tt.value, tt.maxValue, tt.width, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderSparkline(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []int
+		expected string
+	}{
+		{"scaled to the largest", []int{0, 60, 120, 240}, "▁▃▅█"},
+		{"all zero", []int{0, 0, 0}, "▁▁▁"},
+		{"equal", []int{30, 30}, "██"},
+		{"empty", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderSparkline(tt.values); got != tt.expected {
+				t.Errorf("renderSparkline(%v) = %q, expected %q", tt.values, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStats_JSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
+	for _, e := range []entry.Entry{
+		{Timestamp: startOfWeek, Description: "a", DurationMinutes: 120, Project: "acme", Tags: []string{"review"}},
+		{Timestamp: startOfWeek.Add(time.Hour), Description: "b", DurationMinutes: 30},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	_ = statsCmd.Flags().Set("json", "true")
+	defer func() { _ = statsCmd.Flags().Set("json", "false") }()
+
+	runStats(statsCmd, []string{})
+
+	var output statsJSON
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("Expected valid JSON, got error %v for: %s", err, stdout.String())
+	}
+	if output.Period != "this week" || output.TotalMinutes != 150 || output.EntryCount != 2 || output.DaysTracked != 1 {
+		t.Errorf("Unexpected statistics: %+v", output)
+	}
+	expectedProjects := []metadataSummary{{Name: "acme", Count: 1, TotalMinutes: 120}, {Name: "", Count: 1, TotalMinutes: 30}}
+	if fmt.Sprint(output.Projects) != fmt.Sprint(expectedProjects) {
+		t.Errorf("Expected projects %v, got %v", expectedProjects, output.Projects)
+	}
+	expectedTags := []metadataSummary{{Name: "review", Count: 1, TotalMinutes: 120}, {Name: "", Count: 1, TotalMinutes: 30}}
+	if fmt.Sprint(output.Tags) != fmt.Sprint(expectedTags) {
+		t.Errorf("Expected tags %v, got %v", expectedTags, output.Tags)
+	}
+}
+
+func TestStats_SplitDays(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+
+	// Runs from 22:00 before the week starts into its first day
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
+	e := entry.Entry{Timestamp: startOfWeek.Add(-2 * time.Hour), Description: "incident", DurationMinutes: 300}
+	if err := storage.AppendEntry(storagePath, e); err != nil {
+		t.Fatalf("Failed to create test entry: %v", err)
+	}
+
+	for _, split := range []bool{false, true} {
+		d, stdout, _ := testDeps(storagePath)
+		d.Config.SplitAtMidnight = split
+		SetDeps(d)
+		_ = statsCmd.Flags().Set("json", "true")
+
+		runStats(statsCmd, []string{})
+
+		_ = statsCmd.Flags().Set("json", "false")
+		ResetDeps()
+
+		var output statsJSON
+		if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+			t.Fatalf("Expected valid JSON, got error %v for: %s", err, stdout.String())
+		}
+		want := 0
+		if split {
+			want = 180
+		}
+		if output.TotalMinutes != want {
+			t.Errorf("split_at_midnight = %t: TotalMinutes = %d, expected %d", split, output.TotalMinutes, want)
+		}
+	}
+}
+
+func TestStats_LoggingLatency(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+
+	startOfWeek, _ := timeutil.ThisWeek(time.Now())
+	at := func(d time.Duration) *time.Time {
+		loggedAt := startOfWeek.Add(d)
+		return &loggedAt
+	}
+	for _, e := range []entry.Entry{
+		{Timestamp: startOfWeek, Description: "a", DurationMinutes: 60, LoggedAt: at(90 * time.Minute)},
+		{Timestamp: startOfWeek, Description: "b", DurationMinutes: 30, LoggedAt: at(30 * time.Hour)},
+		{Timestamp: startOfWeek, Description: "c", DurationMinutes: 30, LoggedAt: at(30 * time.Minute)},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	// An entry written before logged_at was recorded is left out
+	f, err := os.OpenFile(storagePath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open storage: %v", err)
+	}
+	_, _ = fmt.Fprintf(f, `{"timestamp":%q,"description":"old","duration_minutes":120,"raw_input":""}`+"\n", startOfWeek.Format(time.RFC3339))
+	_ = f.Close()
+
+	d, stdout, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	runStats(statsCmd, []string{})
+
+	for _, expected := range []string{
+		"Average/Entry:   1h",
+		"Log Latency:     1h 30m median (3 entries)",
+		"Backdated >1d:   33%",
+	} {
+		if !strings.Contains(stdout.String(), expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, stdout.String())
+		}
+	}
+
+	d, stdout, _ = testDeps(storagePath)
+	SetDeps(d)
+	_ = statsCmd.Flags().Set("json", "true")
+	defer func() { _ = statsCmd.Flags().Set("json", "false") }()
+
+	runStats(statsCmd, []string{})
+
+	var output statsJSON
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("Expected valid JSON, got error %v for: %s", err, stdout.String())
+	}
+	if output.AverageEntryMinutes != 60 {
+		t.Errorf("AverageEntryMinutes = %v, expected 60", output.AverageEntryMinutes)
+	}
+	if l := output.LoggingLatency; l == nil || l.EntryCount != 3 || l.MedianMinutes != 90 || l.BackdatedShare != 1.0/3 {
+		t.Errorf("Unexpected logging latency: %+v", l)
+	}
+}
+
+func TestStats_JSON_Empty(t *testing.T) {
+	d, stdout, _ := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	SetDeps(d)
+	defer ResetDeps()
+	_ = statsCmd.Flags().Set("json", "true")
+	defer func() { _ = statsCmd.Flags().Set("json", "false") }()
+
+	runStats(statsCmd, []string{})
+
+	if !strings.Contains(stdout.String(), `"projects": []`) || !strings.Contains(stdout.String(), `"tags": []`) {
+		t.Errorf("Expected empty arrays rather than null, got: %s", stdout.String())
+	}
+	if strings.Contains(stdout.String(), "logging_latency") {
+		t.Errorf("Expected no logging latency without entries, got: %s", stdout.String())
+	}
+}
+
+func TestStats_TimePeriodFlags(t *testing.T) {
+	tmpDir := t.TempDir()
+	storagePath := filepath.Join(tmpDir, "entries.jsonl")
+
+	now := time.Now()
+	for _, e := range []entry.Entry{
+		{Timestamp: timeutil.StartOfDay(now.AddDate(0, 0, -1)).Add(10 * time.Hour), Description: "yesterday", DurationMinutes: 90, Project: "acme"},
+		{Timestamp: timeutil.StartOfDay(now.AddDate(0, 0, -1)).Add(11 * time.Hour), Description: "yesterday other", DurationMinutes: 30, Project: "other"},
+		{Timestamp: timeutil.StartOfDay(now.AddDate(0, 0, -2)).Add(10 * time.Hour), Description: "day before", DurationMinutes: 60, Project: "acme"},
+		{Timestamp: now.AddDate(0, 0, -40), Description: "old", DurationMinutes: 45},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		flags    map[string]string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "yesterday",
+			flags:    map[string]string{"yesterday": "true"},
+			expected: []string{"Statistics for yesterday", "Total Hours:     2h", "up 1h from last day"},
+		},
+		{
+			name:     "yesterday with project filter",
+			flags:    map[string]string{"yesterday": "true"},
+			args:     []string{"@acme"},
+			expected: []string{"Statistics for yesterday (@acme)", "Total Hours:     1h 30m", "up 30m from last day"},
+		},
+		{
+			name:     "last days",
+			flags:    map[string]string{"last": "3"},
+			expected: []string{"Statistics for last 3 days", "Total Hours:     3h", "up 3h from last period"},
+		},
+		{
+			name:     "date",
+			flags:    map[string]string{"date": now.AddDate(0, 0, -2).Format("2006-01-02")},
+			expected: []string{"Total Hours:     1h", "up 1h from last day"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+			defer resetTimePeriodFlags(statsCmd)
+			defer resetFilterFlags(statsCmd)
+
+			for name, value := range tt.flags {
+				_ = statsCmd.Flags().Set(name, value)
+			}
+			runStats(statsCmd, parseShorthandFilters(statsCmd, tt.args))
+
+			output := stdout.String()
+			for _, want := range tt.expected {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected %q in output, got: %s", want, output)
+				}
+			}
+			if stderr.Len() > 0 {
+				t.Errorf("Expected no errors, got: %s", stderr.String())
+			}
+		})
+	}
+}
+
+func TestStats_Cumulative(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	day := func(d, hour int) time.Time {
+		return time.Date(2024, time.January, d, hour, 0, 0, 0, time.Local)
+	}
+	for _, e := range []entry.Entry{
+		{Timestamp: day(15, 9), Description: "standup", DurationMinutes: 60},
+		{Timestamp: day(15, 14), Description: "review", DurationMinutes: 60},
+		{Timestamp: day(17, 10), Description: "deploy", DurationMinutes: 120},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	run := func(flags ...string) string {
+		t.Helper()
+		d, stdout, stderr := testDeps(storagePath)
+		SetDeps(d)
+		defer ResetDeps()
+		defer resetTimePeriodFlags(statsCmd)
+		_ = statsCmd.Flags().Set("from", "2024-01-15")
+		_ = statsCmd.Flags().Set("to", "2024-01-18")
+		for _, flag := range flags {
+			_ = statsCmd.Flags().Set(flag, "true")
+		}
+		defer func() {
+			for _, flag := range flags {
+				_ = statsCmd.Flags().Set(flag, "false")
+			}
+		}()
+
+		runStats(statsCmd, []string{})
+
+		if stderr.Len() > 0 {
+			t.Errorf("Unexpected stderr: %s", stderr.String())
+		}
+		return stdout.String()
+	}
+
+	output := run("cumulative")
+	expected := "  Mon Jan 15          2h          2h\n" +
+		"  Tue Jan 16          0m          2h\n" +
+		"  Wed Jan 17          2h          4h\n" +
+		"  Thu Jan 18          0m          4h\n"
+	if !strings.Contains(output, "By Day:") || !strings.Contains(output, expected) {
+		t.Errorf("Expected the days with running totals:\n%s\ngot:\n%s", expected, output)
+	}
+	if output := run(); strings.Contains(output, "By Day") {
+		t.Errorf("Expected no days without --cumulative, got:\n%s", output)
+	}
+
+	output = run("cumulative", "chart")
+	if !strings.Contains(output, "By Day (cumulative):") || !strings.Contains(output, "Tue Jan 16  ") || !strings.Contains(output, "2h (50%)") || !strings.Contains(output, "4h (100%)") {
+		t.Errorf("Expected a cumulative chart, got:\n%s", output)
+	}
+
+	var result statsJSON
+	if err := json.Unmarshal([]byte(run("cumulative", "json")), &result); err != nil {
+		t.Fatalf("Expected valid JSON: %v", err)
+	}
+	if len(result.Days) != 4 || result.Days[1] != (dayJSON{Date: "2024-01-16", Minutes: 0, CumulativeMinutes: 120}) || result.Days[3].CumulativeMinutes != 240 {
+		t.Errorf("Unexpected days: %+v", result.Days)
+	}
+}
+
+func TestStats_WeekdayProfile(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	day := func(d, hour int) time.Time {
+		return time.Date(2024, time.January, d, hour, 0, 0, 0, time.Local)
+	}
+	for _, e := range []entry.Entry{
+		{Timestamp: day(15, 9), Description: "standup", DurationMinutes: 60},
+		{Timestamp: day(16, 9), Description: "planning", DurationMinutes: 120},
+		{Timestamp: day(23, 9), Description: "planning", DurationMinutes: 180},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	run := func(weekStart string, flags ...string) string {
+		t.Helper()
+		cfg := config.DefaultConfig()
+		cfg.WeekStartDay = weekStart
+		d, stdout, stderr := testDepsWithConfig(storagePath, cfg)
+		SetDeps(d)
+		defer ResetDeps()
+		defer resetTimePeriodFlags(statsCmd)
+		// Monday to the Tuesday of the next week: two Mondays and Tuesdays
+		_ = statsCmd.Flags().Set("from", "2024-01-15")
+		_ = statsCmd.Flags().Set("to", "2024-01-23")
+		flags = append(flags, "weekday-profile")
+		for _, flag := range flags {
+			_ = statsCmd.Flags().Set(flag, "true")
+		}
+		defer func() {
+			for _, flag := range flags {
+				_ = statsCmd.Flags().Set(flag, "false")
+			}
+		}()
+
+		runStats(statsCmd, []string{})
+
+		if stderr.Len() > 0 {
+			t.Errorf("Unexpected stderr: %s", stderr.String())
+		}
+		return stdout.String()
+	}
+
+	output := run("monday")
+	expected := "  Mon          1h         30m  2\n" +
+		"  Tue          5h      2h 30m  2\n" +
+		"  Wed          0m          0m  1\n"
+	if !strings.Contains(output, "By Weekday:") || !strings.Contains(output, expected) || !strings.Contains(output, "  Sun          0m          0m  1\n\n") {
+		t.Errorf("Expected the weekday totals and averages:\n%s\ngot:\n%s", expected, output)
+	}
+	if strings.Contains(output, "By Day") {
+		t.Errorf("Expected no days without --cumulative, got:\n%s", output)
+	}
+	if output := run("sunday"); strings.Index(output, "  Sun ") > strings.Index(output, "  Mon ") {
+		t.Errorf("Expected Sunday first with week_start_day sunday, got:\n%s", output)
+	}
+
+	output = run("monday", "chart")
+	if !strings.Contains(output, "By Weekday (average):") || !strings.Contains(output, "2h 30m (83%)") {
+		t.Errorf("Expected a chart of the averages, got:\n%s", output)
+	}
+
+	var result statsJSON
+	if err := json.Unmarshal([]byte(run("monday", "json")), &result); err != nil {
+		t.Fatalf("Expected valid JSON: %v", err)
+	}
+	if len(result.Weekdays) != 7 || result.Weekdays[1] != (weekdayJSON{Weekday: "Tuesday", Days: 2, TotalMinutes: 300, AverageMinutes: 150}) || len(result.Days) != 0 {
+		t.Errorf("Unexpected weekdays: %+v, days: %+v", result.Weekdays, result.Days)
+	}
+
+	setFormatFlags(t, statsCmd, formatTSV, true)
+	output = run("monday")
+	if !strings.HasPrefix(output, "weekday\tdays\ttotal_minutes\taverage_minutes\nMon\t2\t60\t30.00\nTue\t2\t300\t150.00\n") {
+		t.Errorf("Expected one tsv row per weekday, got:\n%s", output)
+	}
+}
+
+func TestStats_OpenEndedPeriodHasNoComparison(t *testing.T) {
+	d, stdout, _ := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	SetDeps(d)
+	defer ResetDeps()
+	defer resetTimePeriodFlags(statsCmd)
+
+	_ = statsCmd.Flags().Set("to", "2024-01-31")
+	runStats(statsCmd, []string{})
+
+	if strings.Contains(stdout.String(), "Comparison:") {
+		t.Errorf("Expected no comparison for a period without start, got: %s", stdout.String())
+	}
+}
+
+func TestStats_TimePeriodFlagErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		flags    map[string]string
+		args     []string
+		expected string
+	}{
+		{"mutually exclusive", map[string]string{"yesterday": "true", "this-week": "true"}, nil, "mutually exclusive"},
+		{"month with period flag", map[string]string{"month": "true", "prev-week": "true"}, nil, "--month cannot be combined"},
+		{"invalid date", map[string]string{"date": "not-a-date"}, nil, "Invalid --date value"},
+		{"unexpected argument", nil, []string{"acme"}, "Unexpected argument 'acme'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+			exitCode := 0
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			defer resetTimePeriodFlags(statsCmd)
+			defer func() { _ = statsCmd.Flags().Set("month", "false") }()
+
+			for name, value := range tt.flags {
+				_ = statsCmd.Flags().Set(name, value)
+			}
+			runStats(statsCmd, tt.args)
+
+			if exitCode != 1 {
+				t.Errorf("Expected exit code 1, got %d", exitCode)
+			}
+			if !strings.Contains(stderr.String(), tt.expected) {
+				t.Errorf("Expected %q in stderr, got: %s", tt.expected, stderr.String())
+			}
+			if stdout.Len() > 0 {
+				t.Errorf("Expected no output, got: %s", stdout.String())
+			}
+		})
+	}
+}
+
+func TestStats_Hours(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	at := time.Date(2024, time.January, 15, 9, 0, 0, 0, time.Local)
+	for _, e := range []entry.Entry{
+		{Timestamp: at, Description: "review", DurationMinutes: 90, Project: "acme", Tags: []string{"pr"}},
+		{Timestamp: at.Add(2 * time.Hour), Description: "email", DurationMinutes: 20},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	defer resetTimePeriodFlags(statsCmd)
+	_ = statsCmd.Flags().Set("from", "2024-01-15")
+	_ = statsCmd.Flags().Set("to", "2024-01-15")
+	_ = statsCmd.Flags().Set("hours", "true")
+	defer func() { _ = statsCmd.Flags().Set("hours", "false") }()
+
+	runStats(statsCmd, []string{})
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr: %s", stderr.String())
+	}
+	output := stdout.String()
+	// The same value as the duration_hours column of a CSV export
+	acme := fmt.Sprintf("  %-28s  %10s  (1 entry)\n", "@acme", timeutil.FormatDurationDecimal(90))
+	for _, expected := range []string{"Total Hours:     1.83\n", acme, "  #pr                                 1.50  (1 entry)\n", "  (no project)                        0.33  (1 entry)\n"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, output)
+		}
+	}
+	if !strings.Contains(acme, "1.50") {
+		t.Errorf("Expected the 90-minute group as 1.50, got %q", acme)
+	}
+	if strings.Contains(output, "1h 30m") {
+		t.Errorf("Expected no human durations with --hours, got:\n%s", output)
+	}
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/xolan/did/internal/entry"
-	"github.com/xolan/did/internal/storage"
 	"github.com/xolan/did/internal/timer"
 )
 
@@ -61,8 +60,7 @@ func stopTimer() {
 	elapsed := time.Since(state.StartedAt)
 	durationMinutes := calculateDurationMinutes(elapsed)
 
-	// Get storage path
-	storagePath, err := deps.StoragePath()
+	store, err := deps.Store()
 	if err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to get storage path")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
@@ -76,15 +74,22 @@ func stopTimer() {
 		Timestamp:       now,
 		Description:     state.Description,
 		DurationMinutes: durationMinutes,
-		RawInput:        fmt.Sprintf("%s for %s", state.Description, formatDuration(durationMinutes)),
 		Project:         state.Project,
 		Tags:            state.Tags,
+		LoggedAt:        &now,
+	}
+	deps.Config.ApplyEntryDefaults(&e)
+	e.RawInput = fmt.Sprintf("%s for %s", state.Description, formatDuration(e.DurationMinutes))
+
+	if !prepareStorageDir(store.Path()) {
+		return
 	}
 
 	// Append entry to storage
-	if err := storage.AppendEntry(storagePath, e); err != nil {
+	if err := store.Append(e); err != nil {
 		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to save entry")
 		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		printStorageWriteHint(store.Path(), err)
 		deps.Exit(1)
 		return
 	}
@@ -94,8 +99,8 @@ func stopTimer() {
 	}
 
 	// Display success message
-	formattedDesc := formatEntryForLog(state.Description, state.Project, state.Tags)
-	_, _ = fmt.Fprintf(deps.Stdout, "Stopped: %s (%s)\n", formattedDesc, formatDuration(durationMinutes))
+	formattedDesc := formatEntryForLog(e.Description, displayProject(e), e.Tags)
+	_, _ = fmt.Fprintf(deps.Stdout, "Stopped: %s (%s)\n", formattedDesc, formatDuration(e.DurationMinutes))
 }
 
 // calculateDurationMinutes calculates duration in minutes from a time.Duration.
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// tagCmd groups the commands adding or removing a tag on many entries at once
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Add or remove a tag on matching entries",
+	Long: `Add a tag to, or remove a tag from, every entry matching a description
+filter, a time period and the --project, --client and --tag filters, e.g. to
+tag meetings logged before you started tagging them. Deleted entries are left
+alone.
+
+--filter matches a case-insensitive substring of the description, or a
+regular expression with --regex (case-sensitive unless it starts with (?i)).
+Without any filter the commands refuse to run; give --all to change every
+entry. The storage file is rewritten atomically; --dry-run lists the entries
+that would change.
+
+Examples:
+  did tag add meeting --filter meeting --dry-run     List the entries to tag
+  did tag add meeting --filter meeting               Tag all meetings
+  did tag add standup --filter '^daily( sync)?$' --regex
+  did tag remove review --project acme --prev-month  Untag last month's @acme entries
+  did tag remove wip --all                           Remove #wip everywhere`,
+}
+
+// tagAddCmd represents the tag add command
+var tagAddCmd = &cobra.Command{
+	Use:   "add <tag>",
+	Short: "Add a tag to every matching entry",
+	Long: `Add a tag to every matching entry that does not have it yet.
+See 'did tag --help' for the filters.
+
+Examples:
+  did tag add meeting --filter meeting --dry-run
+  did tag add meeting --filter meeting --last 6m`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		retagEntries(cmd, args[0], false)
+	},
+}
+
+// tagRemoveCmd represents the tag remove command
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove <tag>",
+	Short: "Remove a tag from every matching entry",
+	Long: `Remove a tag from every matching entry that has it.
+See 'did tag --help' for the filters.
+
+Examples:
+  did tag remove wip --filter release --dry-run
+  did tag remove wip --all`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		retagEntries(cmd, args[0], true)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRemoveCmd)
+
+	for _, cmd := range []*cobra.Command{tagAddCmd, tagRemoveCmd} {
+		cmd.Flags().String("filter", "", "Only change entries whose description contains this text (case-insensitive)")
+		cmd.Flags().Bool("regex", false, "Match --filter as a regular expression")
+		cmd.Flags().Bool("all", false, "Change every entry when no filter is given")
+		cmd.Flags().Bool("dry-run", false, "List the entries that would change without writing")
+	}
+	addTimePeriodFlags(tagAddCmd, "Tag")
+	addTimePeriodFlags(tagRemoveCmd, "Untag")
+}
+
+// retagEntries adds tag to the entries matching the flags of cmd, or removes
+// it from them when remove is set
+func retagEntries(cmd *cobra.Command, tag string, remove bool) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	if !entry.IsValidName(tag) {
+		_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid tag name '%s'\n", tag)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Tag names can contain letters, digits, hyphens, underscores, and single spaces between words")
+		deps.Exit(1)
+		return
+	}
+
+	match, ok := retagMatcher(cmd)
+	if !ok {
+		return
+	}
+
+	storagePath, err := deps.StoragePath()
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to determine storage location")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Check that your home directory is accessible")
+		deps.Exit(1)
+		return
+	}
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	action, done, planned := storage.AddTag, "Added", "Would add"
+	preposition := "to"
+	if remove {
+		action, done, planned = storage.RemoveTag, "Removed", "Would remove"
+		preposition = "from"
+	}
+	changed, err := action(storagePath, tag, match, dryRun)
+	if err != nil {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to update entries")
+		_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "Hint: Check that the file is readable and writable: %s\n", storagePath)
+		deps.Exit(1)
+		return
+	}
+
+	switch {
+	case len(changed) == 0:
+		_, _ = fmt.Fprintln(deps.Stdout, "No matching entries to change")
+	case dryRun:
+		_, _ = fmt.Fprintf(deps.Stdout, "%s #%s %s %s:\n", planned, tag, preposition, formatCount(len(changed), "entry", "entries"))
+		for _, e := range changed {
+			_, _ = fmt.Fprintf(deps.Stdout, "  %s\n", formatComparedEntry(e))
+		}
+	default:
+		_, _ = fmt.Fprintf(deps.Stdout, "%s #%s %s %s\n", done, tag, preposition, formatCount(len(changed), "entry", "entries"))
+	}
+}
+
+// retagMatcher returns a func matching the active entries selected by the
+// --filter, --regex and time period flags of cmd and the --project, --client
+// and --tag filters. Invalid flags, and no filter at all without --all, are
+// reported to stderr; ok is false then.
+func retagMatcher(cmd *cobra.Command) (func(entry.Entry) bool, bool) {
+	c, ok := resolveQuery(cmd)
+	if !ok {
+		return nil, false
+	}
+	text, _ := cmd.Flags().GetString("filter")
+	useRegex, _ := cmd.Flags().GetBool("regex")
+	all, _ := cmd.Flags().GetBool("all")
+
+	if useRegex && text == "" {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: --regex requires --filter")
+		deps.Exit(1)
+		return nil, false
+	}
+	if text == "" && !c.HasPeriod() && c.Filter().IsEmpty() && !all {
+		_, _ = fmt.Fprintln(deps.Stderr, "Error: No filter given")
+		_, _ = fmt.Fprintln(deps.Stderr, "Hint: Select entries with --filter, a time period, --project or --tag, or give --all to change every entry")
+		deps.Exit(1)
+		return nil, false
+	}
+
+	matchesDescription := func(description string) bool {
+		return strings.Contains(strings.ToLower(description), strings.ToLower(text))
+	}
+	if useRegex {
+		re, err := regexp.Compile(text)
+		if err != nil {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid --filter regular expression '%s'\n", text)
+			_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+			deps.Exit(1)
+			return nil, false
+		}
+		matchesDescription = re.MatchString
+	}
+
+	return func(e entry.Entry) bool {
+		return e.DeletedAt == nil && c.Matches(e) && matchesDescription(e.Description)
+	}, true
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// resetTagFlags clears the flags of the tag add and remove commands
+func resetTagFlags(cmd *cobra.Command) {
+	_ = cmd.Flags().Set("filter", "")
+	for _, name := range []string{"regex", "all", "dry-run"} {
+		_ = cmd.Flags().Set(name, "false")
+	}
+	resetTimePeriodFlags(cmd)
+	resetFilterFlags(cmd)
+}
+
+// createRetagTestEntries writes meetings and other entries, one of them
+// already tagged and one deleted
+func createRetagTestEntries(t *testing.T) string {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	now := time.Now()
+	deletedAt := now
+	for _, e := range []entry.Entry{
+		{Timestamp: now.AddDate(0, -6, 0), Description: "Team meeting", DurationMinutes: 60},
+		{Timestamp: now, Description: "client meeting", DurationMinutes: 30, Project: "acme", Tags: []string{"Meeting"}},
+		{Timestamp: now, Description: "meeting notes", DurationMinutes: 15, Project: "acme"},
+		{Timestamp: now, Description: "code review", DurationMinutes: 45, Tags: []string{"review"}},
+		{Timestamp: now, Description: "old meeting", DurationMinutes: 30, DeletedAt: &deletedAt},
+	} {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	return storagePath
+}
+
+func TestRetagEntries_Add(t *testing.T) {
+	storagePath := createRetagTestEntries(t)
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	defer resetTagFlags(tagAddCmd)
+
+	resetTagFlags(tagAddCmd)
+	_ = tagAddCmd.Flags().Set("filter", "MEETING")
+	_ = tagAddCmd.Flags().Set("dry-run", "true")
+	retagEntries(tagAddCmd, "#meeting", false)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	output := stdout.String()
+	if !strings.Contains(output, "Would add #meeting to 2 entries:") || !strings.Contains(output, "Team meeting") || !strings.Contains(output, "meeting notes") {
+		t.Errorf("Expected the two untagged meetings listed, got: %s", output)
+	}
+	if strings.Contains(output, "client meeting") || strings.Contains(output, "old meeting") {
+		t.Errorf("Expected tagged and deleted entries to be left out, got: %s", output)
+	}
+	entries, _ := storage.ReadEntries(storagePath)
+	if len(entries[0].Tags) != 0 {
+		t.Fatalf("Expected the dry run to write nothing, got tags %v", entries[0].Tags)
+	}
+
+	stdout.Reset()
+	_ = tagAddCmd.Flags().Set("dry-run", "false")
+	retagEntries(tagAddCmd, "meeting", false)
+	if !strings.Contains(stdout.String(), "Added #meeting to 2 entries") {
+		t.Errorf("Expected the count of tagged entries, got: %s", stdout.String())
+	}
+	entries, _ = storage.ReadEntries(storagePath)
+	expected := [][]string{{"meeting"}, {"Meeting"}, {"meeting"}, {"review"}, nil}
+	for i, e := range entries {
+		if !reflect.DeepEqual(e.Tags, expected[i]) {
+			t.Errorf("Entry %d: tags %v, expected %v", i, e.Tags, expected[i])
+		}
+	}
+}
+
+func TestRetagEntries_AddWithPeriodAndRegex(t *testing.T) {
+	storagePath := createRetagTestEntries(t)
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	defer resetTagFlags(tagAddCmd)
+
+	resetTagFlags(tagAddCmd)
+	_ = tagAddCmd.Flags().Set("filter", "^(meeting|code)")
+	_ = tagAddCmd.Flags().Set("regex", "true")
+	_ = tagAddCmd.Flags().Set("this-week", "true")
+	retagEntries(tagAddCmd, "internal", false)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Added #internal to 2 entries") {
+		t.Errorf("Expected 2 entries tagged, got: %s", stdout.String())
+	}
+	entries, _ := storage.ReadEntries(storagePath)
+	if !containsTag(entries[2].Tags, "internal") || !containsTag(entries[3].Tags, "internal") || containsTag(entries[0].Tags, "internal") {
+		t.Errorf("Expected only this week's matching entries tagged, got %v", entries)
+	}
+}
+
+func TestRetagEntries_Remove(t *testing.T) {
+	storagePath := createRetagTestEntries(t)
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	defer resetTagFlags(tagRemoveCmd)
+
+	resetTagFlags(tagRemoveCmd)
+	_ = tagRemoveCmd.Flags().Set("all", "true")
+	retagEntries(tagRemoveCmd, "meeting", true)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Removed #meeting from 1 entry") {
+		t.Errorf("Expected 1 entry untagged, got: %s", stdout.String())
+	}
+	entries, _ := storage.ReadEntries(storagePath)
+	if len(entries[1].Tags) != 0 {
+		t.Errorf("Expected #Meeting removed ignoring case, got %v", entries[1].Tags)
+	}
+
+	stdout.Reset()
+	retagEntries(tagRemoveCmd, "meeting", true)
+	if !strings.Contains(stdout.String(), "No matching entries to change") {
+		t.Errorf("Expected nothing left to change, got: %s", stdout.String())
+	}
+}
+
+func TestRetagEntries_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      string
+		flags    map[string]string
+		expected string
+	}{
+		{"no filter", "meeting", nil, "Error: No filter given"},
+		{"invalid tag", "bad!", map[string]string{"all": "true"}, "Invalid tag name 'bad!'"},
+		{"regex without filter", "meeting", map[string]string{"regex": "true", "all": "true"}, "--regex requires --filter"},
+		{"invalid regex", "meeting", map[string]string{"filter": "(", "regex": "true"}, "Invalid --filter regular expression"},
+		{"conflicting periods", "meeting", map[string]string{"yesterday": "true", "this-week": "true"}, "mutually exclusive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := createRetagTestEntries(t)
+			d, _, stderr := testDeps(storagePath)
+			exitCode := 0
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			defer resetTagFlags(tagAddCmd)
+			resetTagFlags(tagAddCmd)
+			for name, value := range tt.flags {
+				_ = tagAddCmd.Flags().Set(name, value)
+			}
+
+			retagEntries(tagAddCmd, tt.tag, false)
+
+			if exitCode != 1 || !strings.Contains(stderr.String(), tt.expected) {
+				t.Errorf("Expected exit 1 with %q, got exit %d: %s", tt.expected, exitCode, stderr.String())
+			}
+			entries, _ := storage.ReadEntries(storagePath)
+			if len(entries[0].Tags) != 0 {
+				t.Errorf("Expected nothing written, got tags %v", entries[0].Tags)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/storage"
+)
+
+// tagsCmd represents the tags command
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "List all tags with their totals",
+	Long: `List every tag used in your entries with the number of entries and the
+total time logged, most time first. Entries with several tags count towards
+each of them.
+
+Use --json for machine-readable output, e.g. for editor plugins:
+  [{"name": "review", "count": 8, "total_minutes": 240}, ...]
+
+Use --format tsv for a name, total minutes and count row per tag (column
+names first with --header), or --format plain for one value per line.
+
+Use --rename old=new to rename a tag in every entry, including deleted ones
+(the old name matches regardless of case). An entry that already has the new
+tag keeps it once. The storage file is rewritten atomically; --dry-run only
+reports how many entries would change.
+
+Examples:
+  did tags                        List all tags
+  did tags --json                 List all tags as JSON
+  did tags --format tsv           review<TAB>240<TAB>8
+  did tags --rename bug=bugfix --dry-run`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listTags(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+
+	tagsCmd.Flags().Bool("json", false, "Output tags as JSON")
+	addFormatFlags(tagsCmd)
+	addRenameFlags(tagsCmd, "tag")
+}
+
+// listTags prints all tags with their entry counts and totals
+func listTags(cmd *cobra.Command) {
+	if renameRequested(cmd) {
+		renameMetadata(cmd, "tag", "#", storage.RenameTag)
+		return
+	}
+
+	asJSON, _ := cmd.Flags().GetBool("json")
+	format, ok := outputFormat(cmd)
+	if !ok {
+		return
+	}
+
+	totals, ok := readTotalsForMetadata()
+	if !ok {
+		return
+	}
+
+	summaries := sortedSummaries(tagSummaries(totals.Tags, false))
+
+	if asJSON {
+		writeJSONOutput(summaries)
+		return
+	}
+	if format != formatHuman {
+		writeRecords(cmd, format, summaryColumns, summaryRecords(summaries))
+		return
+	}
+
+	if len(summaries) == 0 {
+		_, _ = fmt.Fprintln(deps.Stdout, "No tags found")
+		_, _ = fmt.Fprintln(deps.Stdout, "Hint: Add tags with #tag, e.g. 'did code review #review for 30m'")
+		return
+	}
+
+	for _, s := range summaries {
+		_, _ = fmt.Fprintf(deps.Stdout, "  %-28s  %10s  (%d %s)\n",
+			"#"+s.Name,
+			formatDuration(s.TotalMinutes),
+			s.Count,
+			pluralize("entry", "entries", s.Count))
+	}
+}
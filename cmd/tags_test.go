@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xolan/did/internal/storage"
+)
+
+func TestListTags(t *testing.T) {
+	d, stdout, stderr := testDeps(createMetadataTestEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+
+	listTags(tagsCmd)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 tags, got: %s", stdout.String())
+	}
+	if !strings.Contains(lines[0], "#bugfix") || !strings.Contains(lines[0], "1h 30m") {
+		t.Errorf("Expected bugfix first with 1h 30m, got: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "#review") || !strings.Contains(lines[1], "30m") {
+		t.Errorf("Expected review second with 30m, got: %s", lines[1])
+	}
+	if strings.Contains(stdout.String(), "old") || strings.Contains(stdout.String(), "no tags") {
+		t.Errorf("Expected deleted entries and entries without tags to be left out, got: %s", stdout.String())
+	}
+}
+
+func TestListTags_JSON(t *testing.T) {
+	d, stdout, _ := testDeps(createMetadataTestEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+	_ = tagsCmd.Flags().Set("json", "true")
+	defer func() { _ = tagsCmd.Flags().Set("json", "false") }()
+
+	listTags(tagsCmd)
+
+	var got []metadataSummary
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("Expected valid JSON, got error %v for: %s", err, stdout.String())
+	}
+	expected := []metadataSummary{{Name: "bugfix", Count: 2, TotalMinutes: 90}, {Name: "review", Count: 1, TotalMinutes: 30}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestListTags_Empty(t *testing.T) {
+	d, stdout, _ := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	SetDeps(d)
+	defer ResetDeps()
+
+	listTags(tagsCmd)
+
+	if !strings.Contains(stdout.String(), "No tags found") {
+		t.Errorf("Expected no tags message, got: %s", stdout.String())
+	}
+}
+
+func TestListTags_Rename(t *testing.T) {
+	storagePath := createMetadataTestEntries(t)
+	d, stdout, stderr := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	defer setRenameFlags(tagsCmd, "#bugfix=review", false)()
+
+	listTags(tagsCmd)
+
+	if stderr.Len() > 0 {
+		t.Fatalf("Unexpected stderr output: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Renamed #bugfix to #review in 2 entries") {
+		t.Errorf("Expected rename count, got: %s", stdout.String())
+	}
+	entries, err := storage.ReadEntries(storagePath)
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if !reflect.DeepEqual(entries[1].Tags, []string{"review"}) {
+		t.Errorf("Expected the tags of an entry with both to be merged, got %v", entries[1].Tags)
+	}
+}
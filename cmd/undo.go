@@ -51,7 +51,7 @@ func undoDelete() {
 
 	// Show success message with entry details
 	_, _ = fmt.Fprintf(deps.Stdout, "Restored: %s (%s)\n",
-		formatEntryForLog(restoredEntry.Description, restoredEntry.Project, restoredEntry.Tags),
+		formatEntryForLog(restoredEntry.Description, displayProject(restoredEntry), restoredEntry.Tags),
 		formatDuration(restoredEntry.DurationMinutes))
 	_, _ = fmt.Fprintf(deps.Stdout, "  Timestamp: %s\n", restoredEntry.Timestamp.Format("2006-01-02 15:04"))
 }
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/storage"
+)
+
+// BuildInfo holds the version and build information of the running binary.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+// buildInfo is populated by SetVersionInfo from main (via ldflags)
+var buildInfo = BuildInfo{
+	Version:   "dev",
+	Commit:    "none",
+	Date:      "unknown",
+	GoVersion: runtime.Version(),
+	OS:        runtime.GOOS,
+	Arch:      runtime.GOARCH,
+}
+
+// versionCmd represents the version command
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show version and build information",
+	Long: `Show the version and build information of did.
+
+Use --json for machine-readable output including the Go version and
+target platform.
+
+Examples:
+  did version                     Show version information
+  did version --json              Show version information as JSON`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		showVersion(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+
+	versionCmd.Flags().Bool("json", false, "Output version information as JSON")
+
+	storage.SetAfterWrite(recordStorageWriter)
+}
+
+// SetVersionInfo sets the version information for the CLI
+func SetVersionInfo(version, commit, date string) {
+	buildInfo.Version = version
+	buildInfo.Commit = commit
+	buildInfo.Date = date
+
+	rootCmd.Version = version
+	rootCmd.SetVersionTemplate(
+		"did version {{.Version}}\n" +
+			"commit: " + commit + "\n" +
+			"built: " + date + "\n",
+	)
+}
+
+// GetBuildInfo returns the version and build information of the running binary
+func GetBuildInfo() BuildInfo {
+	return buildInfo
+}
+
+// showVersion displays the build information as text or JSON
+func showVersion(cmd *cobra.Command) {
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	if asJSON {
+		encoder := json.NewEncoder(deps.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(buildInfo); err != nil {
+			_, _ = fmt.Fprintln(deps.Stderr, "Error: Failed to encode JSON output")
+			_, _ = fmt.Fprintf(deps.Stderr, "Details: %v\n", err)
+			deps.Exit(1)
+		}
+		return
+	}
+
+	_, _ = fmt.Fprintf(deps.Stdout, "did version %s\n", buildInfo.Version)
+	_, _ = fmt.Fprintf(deps.Stdout, "commit: %s\n", buildInfo.Commit)
+	_, _ = fmt.Fprintf(deps.Stdout, "built: %s\n", buildInfo.Date)
+	_, _ = fmt.Fprintf(deps.Stdout, "go: %s\n", buildInfo.GoVersion)
+	_, _ = fmt.Fprintf(deps.Stdout, "platform: %s/%s\n", buildInfo.OS, buildInfo.Arch)
+}
+
+// parseVersion parses a release version like "v1.2.3" or "1.2.3-rc1" into
+// its numeric major, minor and patch parts. Returns false for untagged
+// builds such as "dev".
+func parseVersion(version string) ([3]int, bool) {
+	var parts [3]int
+
+	v := strings.TrimPrefix(version, "v")
+	if idx := strings.IndexAny(v, "-+"); idx != -1 {
+		v = v[:idx]
+	}
+
+	fields := strings.Split(v, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
+
+// compareVersions compares two tagged release versions.
+// Returns -1 if a < b, 0 if equal, 1 if a > b. Both versions must be parseable.
+func compareVersions(a, b [3]int) int {
+	for i := 0; i < 3; i++ {
+		if a[i] < b[i] {
+			return -1
+		}
+		if a[i] > b[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// recordStorageWriter persists the running version in the storage metadata file
+// after a write (see storage.SetAfterWrite), so older binaries can detect data
+// written by a newer release.
+// Only tagged releases are recorded, and the recorded version never goes down.
+// Failures are ignored: the metadata is advisory and must not block writes.
+func recordStorageWriter(storagePath string) {
+	current, ok := parseVersion(buildInfo.Version)
+	if !ok {
+		return
+	}
+
+	meta, err := storage.ReadMeta(storagePath)
+	if err != nil {
+		return
+	}
+
+	if recorded, ok := parseVersion(meta.WriterVersion); ok && compareVersions(recorded, current) >= 0 {
+		return
+	}
+
+	meta.WriterVersion = buildInfo.Version
+	_ = storage.WriteMeta(storagePath, meta)
+}
+
+// checkStorageWriterVersion warns when the storage file was last written by a
+// tagged release newer than the running binary. Development builds are treated
+// as older than any tagged release.
+func checkStorageWriterVersion() {
+	storagePath, err := deps.StoragePath()
+	if err != nil {
+		return
+	}
+
+	meta, err := storage.ReadMeta(storagePath)
+	if err != nil {
+		return
+	}
+
+	recorded, ok := parseVersion(meta.WriterVersion)
+	if !ok {
+		return
+	}
+
+	current, isTagged := parseVersion(buildInfo.Version)
+	if isTagged && compareVersions(current, recorded) >= 0 {
+		return
+	}
+
+	if isTagged {
+		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Storage file was last written by did %s, which is newer than this version (%s)\n",
+			meta.WriterVersion, buildInfo.Version)
+	} else {
+		_, _ = fmt.Fprintf(deps.Stderr, "Warning: This is a development build (%s) but the storage file was last written by did %s\n",
+			buildInfo.Version, meta.WriterVersion)
+	}
+	_, _ = fmt.Fprintln(deps.Stderr, "Hint: Use a release at least as new as the one that wrote your data to avoid misreading it")
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+)
+
+// withBuildInfo temporarily replaces the build information for a test
+func withBuildInfo(t *testing.T, version string) {
+	t.Helper()
+	original := buildInfo
+	buildInfo.Version = version
+	t.Cleanup(func() { buildInfo = original })
+}
+
+func TestSetVersionInfo_StoresBuildInfo(t *testing.T) {
+	original := buildInfo
+	defer func() { buildInfo = original }()
+
+	SetVersionInfo("v1.4.0", "abc123", "2024-01-15")
+
+	info := GetBuildInfo()
+	if info.Version != "v1.4.0" || info.Commit != "abc123" || info.Date != "2024-01-15" {
+		t.Errorf("Unexpected build info: %+v", info)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("Expected Go version %q, got %q", runtime.Version(), info.GoVersion)
+	}
+	if info.OS != runtime.GOOS || info.Arch != runtime.GOARCH {
+		t.Errorf("Expected platform %s/%s, got %s/%s", runtime.GOOS, runtime.GOARCH, info.OS, info.Arch)
+	}
+}
+
+func TestShowVersion_Text(t *testing.T) {
+	withBuildInfo(t, "v1.4.0")
+	d, stdout, _ := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	SetDeps(d)
+	defer ResetDeps()
+
+	_ = versionCmd.Flags().Set("json", "false")
+	showVersion(versionCmd)
+
+	output := stdout.String()
+	for _, expected := range []string{"did version v1.4.0", "commit:", "built:", "go: " + runtime.Version(), "platform: " + runtime.GOOS + "/" + runtime.GOARCH} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+}
+
+func TestShowVersion_JSON(t *testing.T) {
+	withBuildInfo(t, "v1.4.0")
+	d, stdout, _ := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+	SetDeps(d)
+	defer ResetDeps()
+
+	_ = versionCmd.Flags().Set("json", "true")
+	defer func() { _ = versionCmd.Flags().Set("json", "false") }()
+	showVersion(versionCmd)
+
+	var info BuildInfo
+	if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, stdout.String())
+	}
+	if info.Version != "v1.4.0" {
+		t.Errorf("Expected version 'v1.4.0', got %q", info.Version)
+	}
+	if info.GoVersion == "" || info.OS == "" || info.Arch == "" {
+		t.Errorf("Expected Go version and platform to be set, got %+v", info)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected [3]int
+		ok       bool
+	}{
+		{"v1.2.3", [3]int{1, 2, 3}, true},
+		{"1.2.3", [3]int{1, 2, 3}, true},
+		{"v2.0.0-rc1", [3]int{2, 0, 0}, true},
+		{"v1.10.0+meta", [3]int{1, 10, 0}, true},
+		{"dev", [3]int{}, false},
+		{"", [3]int{}, false},
+		{"v1.2", [3]int{}, false},
+		{"v1.x.3", [3]int{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := parseVersion(tt.input)
+			if ok != tt.ok {
+				t.Fatalf("parseVersion(%q) ok = %v, expected %v", tt.input, ok, tt.ok)
+			}
+			if ok && got != tt.expected {
+				t.Errorf("parseVersion(%q) = %v, expected %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     [3]int
+		expected int
+	}{
+		{[3]int{1, 2, 3}, [3]int{1, 2, 3}, 0},
+		{[3]int{1, 2, 3}, [3]int{1, 3, 0}, -1},
+		{[3]int{2, 0, 0}, [3]int{1, 9, 9}, 1},
+		{[3]int{1, 2, 10}, [3]int{1, 2, 9}, 1},
+	}
+
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.expected {
+			t.Errorf("compareVersions(%v, %v) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestRecordStorageWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		current  string
+		expected string
+	}{
+		{"dev build records nothing", "", "dev", ""},
+		{"first tagged write", "", "v1.2.0", "v1.2.0"},
+		{"newer release upgrades", "v1.2.0", "v1.3.0", "v1.3.0"},
+		{"older release keeps newer", "v1.3.0", "v1.2.0", "v1.3.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			if tt.existing != "" {
+				if err := storage.WriteMeta(storagePath, storage.Meta{WriterVersion: tt.existing}); err != nil {
+					t.Fatalf("Failed to write meta: %v", err)
+				}
+			}
+			withBuildInfo(t, tt.current)
+
+			recordStorageWriter(storagePath)
+
+			meta, err := storage.ReadMeta(storagePath)
+			if err != nil {
+				t.Fatalf("Failed to read meta: %v", err)
+			}
+			if meta.WriterVersion != tt.expected {
+				t.Errorf("Expected writer version %q, got %q", tt.expected, meta.WriterVersion)
+			}
+		})
+	}
+}
+
+func TestCreateEntry_RecordsWriterVersion(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	withBuildInfo(t, "v1.5.0")
+	d, _, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+
+	createEntry(rootCmd, []string{"work", "for", "1h"})
+
+	meta, _ := storage.ReadMeta(storagePath)
+	if meta.WriterVersion != "v1.5.0" {
+		t.Errorf("Expected writer version 'v1.5.0', got %q", meta.WriterVersion)
+	}
+}
+
+func TestDeleteEntry_RecordsWriterVersion(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	if err := storage.AppendEntry(storagePath, entry.Entry{Timestamp: time.Now(), Description: "work", DurationMinutes: 60}); err != nil {
+		t.Fatalf("Failed to create entry: %v", err)
+	}
+	withBuildInfo(t, "v1.6.0")
+	d, _, _ := testDeps(storagePath)
+	SetDeps(d)
+	defer ResetDeps()
+	yesFlag = true
+	defer func() { yesFlag = false }()
+
+	deleteEntry("1")
+
+	meta, _ := storage.ReadMeta(storagePath)
+	if meta.WriterVersion != "v1.6.0" {
+		t.Errorf("Expected writer version 'v1.6.0', got %q", meta.WriterVersion)
+	}
+}
+
+func TestCheckStorageWriterVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		recorded    string
+		current     string
+		expectedMsg string
+	}{
+		{"no meta file", "", "v1.0.0", ""},
+		{"same version", "v1.2.0", "v1.2.0", ""},
+		{"running newer version", "v1.2.0", "v1.3.0", ""},
+		{"running older version", "v1.3.0", "v1.2.0", "newer than this version (v1.2.0)"},
+		{"dev build reading release data", "v1.3.0", "dev", "development build (dev)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+			if tt.recorded != "" {
+				if err := storage.WriteMeta(storagePath, storage.Meta{WriterVersion: tt.recorded}); err != nil {
+					t.Fatalf("Failed to write meta: %v", err)
+				}
+			}
+			withBuildInfo(t, tt.current)
+			d, _, stderr := testDeps(storagePath)
+			SetDeps(d)
+			defer ResetDeps()
+
+			checkStorageWriterVersion()
+
+			if tt.expectedMsg == "" {
+				if stderr.Len() != 0 {
+					t.Errorf("Expected no warning, got: %s", stderr.String())
+				}
+				return
+			}
+			if !strings.Contains(stderr.String(), tt.expectedMsg) {
+				t.Errorf("Expected warning containing %q, got: %s", tt.expectedMsg, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/timeutil"
+)
+
+// viewsGroupID groups the listing shortcuts in help output
+const viewsGroupID = "views"
+
+// prevArg is the optional literal selecting the previous week or month
+const prevArg = "prev"
+
+// todayCmd represents the today command
+var todayCmd = &cobra.Command{
+	Use:     "today [@project] [#tag]",
+	Short:   "List today's entries",
+	GroupID: viewsGroupID,
+	Long: `List today's entries. Same as running did without arguments.
+
+Examples:
+  did today                    List today's entries
+  did today @acme #urgent      Today's entries for project 'acme' tagged 'urgent'`,
+	ValidArgsFunction: completeEntryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !checkViewArgs(parseShorthandFilters(cmd, args)) {
+			return
+		}
+		listEntries(cmd, "today", timeutil.Today)
+	},
+}
+
+// yesterdayCmd represents the yesterday command
+var yesterdayCmd = &cobra.Command{
+	Use:     "yesterday [@project] [#tag]",
+	Short:   "List yesterday's entries",
+	GroupID: viewsGroupID,
+	Long: `List yesterday's entries. Same as did --yesterday.
+
+Examples:
+  did yesterday                List yesterday's entries
+  did yesterday @acme          Yesterday's entries for project 'acme'`,
+	ValidArgsFunction: completeEntryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !checkViewArgs(parseShorthandFilters(cmd, args)) {
+			return
+		}
+		listEntries(cmd, "yesterday", timeutil.Yesterday)
+	},
+}
+
+// weekCmd represents the week command
+var weekCmd = &cobra.Command{
+	Use:     "week [prev] [@project] [#tag]",
+	Short:   "List this week's (or the previous week's) entries",
+	GroupID: viewsGroupID,
+	Long: `List this week's entries, or the previous week's with 'prev'.
+Same as did --this-week and did --prev-week.
+
+Examples:
+  did week                     List this week's entries
+  did week prev                List the previous week's entries
+  did week @acme #urgent       This week's entries with filters`,
+	ValidArgsFunction: completeViewArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		prev, args := parsePrevArg(parseShorthandFilters(cmd, args))
+		if !checkViewArgs(args) {
+			return
+		}
+		listWeek(cmd, prev)
+	},
+}
+
+// monthCmd represents the month command
+var monthCmd = &cobra.Command{
+	Use:     "month [prev] [@project] [#tag]",
+	Short:   "List this month's (or the previous month's) entries",
+	GroupID: viewsGroupID,
+	Long: `List this month's entries, or the previous month's with 'prev'.
+Same as did --this-month and did --prev-month.
+
+Examples:
+  did month                    List this month's entries
+  did month prev               List the previous month's entries
+  did month prev @client       Last month's entries for project 'client'`,
+	ValidArgsFunction: completeViewArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		prev, args := parsePrevArg(parseShorthandFilters(cmd, args))
+		if !checkViewArgs(args) {
+			return
+		}
+		listMonth(cmd, prev)
+	},
+}
+
+func init() {
+	rootCmd.AddGroup(&cobra.Group{ID: viewsGroupID, Title: "Views:"})
+
+	for _, cmd := range []*cobra.Command{todayCmd, yesterdayCmd, weekCmd, monthCmd} {
+		rootCmd.AddCommand(cmd)
+
+		// Same listing output options as the root command
+		cmd.Flags().Bool("subtotals", false, "Show per-project subtotals before the total")
+		cmd.Flags().String("subtotals-by", "", "Group subtotals by project or tag (implies --subtotals)")
+		addShowSourceFlag(cmd)
+		addIndexFlags(cmd)
+		addPagerFlags(cmd)
+	}
+}
+
+// parsePrevArg strips a leading "prev" argument, reporting whether it was given
+func parsePrevArg(args []string) (bool, []string) {
+	if len(args) > 0 && strings.EqualFold(args[0], prevArg) {
+		return true, args[1:]
+	}
+	return false, args
+}
+
+// checkViewArgs rejects arguments of a view other than @project and #tag filters.
+// Input that looks like a new entry gets the same error as combining a time
+// period flag with entry creation. Returns true if the arguments are valid.
+func checkViewArgs(args []string) bool {
+	rawInput := strings.Join(quoteShorthandArgs(args), " ")
+	if _, _, ok := entry.SplitAtDurationKeyword(rawInput, deps.Config.EffectiveDurationKeyword()); ok {
+		printPeriodEntryCreationError()
+		return false
+	}
+
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "@") && !strings.HasPrefix(arg, "#") {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Unexpected argument '%s'\n", arg)
+			_, _ = fmt.Fprintln(deps.Stderr, "Views only accept @project and #tag filters (and 'prev' for week and month)")
+			deps.Exit(1)
+			return false
+		}
+	}
+	return true
+}
+
+// completeViewArgs completes the optional "prev" literal as the first argument of
+// week and month, and @project and #tag tokens like completeEntryArgs
+func completeViewArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 && strings.HasPrefix(prevArg, strings.ToLower(toComplete)) {
+		return []string{prevArg}, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeEntryArgs(cmd, args, toComplete)
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/storage"
+	"github.com/xolan/did/internal/timeutil"
+)
+
+// createViewTestEntries stores one entry per period the views can select
+func createViewTestEntries(t *testing.T) string {
+	t.Helper()
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	now := time.Now()
+	entries := []entry.Entry{
+		{Timestamp: now, Description: "today task", DurationMinutes: 30, Project: "acme"},
+		{Timestamp: now, Description: "today other", DurationMinutes: 15, Project: "client"},
+		{Timestamp: timeutil.StartOfDay(now.AddDate(0, 0, -1)).Add(12 * time.Hour), Description: "yesterday task", DurationMinutes: 45},
+		{Timestamp: timeutil.StartOfWeekWithConfig(now.AddDate(0, 0, -7), "monday").Add(12 * time.Hour), Description: "last week task", DurationMinutes: 60},
+		{Timestamp: timeutil.StartOfMonth(now).AddDate(0, -1, 0).Add(12 * time.Hour), Description: "last month task", DurationMinutes: 90},
+	}
+	for _, e := range entries {
+		if err := storage.AppendEntry(storagePath, e); err != nil {
+			t.Fatalf("Failed to create test entry: %v", err)
+		}
+	}
+	return storagePath
+}
+
+func TestViews(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      *cobra.Command
+		args     []string
+		contains []string
+		excludes []string
+	}{
+		{"today", todayCmd, nil, []string{"today", "today task", "today other"}, []string{"yesterday task"}},
+		{"today filtered", todayCmd, []string{"@acme"}, []string{"today task"}, []string{"today other"}},
+		{"yesterday", yesterdayCmd, nil, []string{"yesterday", "yesterday task"}, []string{"today task"}},
+		{"week", weekCmd, nil, []string{"this week", "today task"}, []string{"last week task"}},
+		{"week prev", weekCmd, []string{"prev"}, []string{"previous week", "last week task"}, []string{"today task"}},
+		{"month prev", monthCmd, []string{"prev"}, []string{"previous month", "last month task"}, []string{"today task"}},
+		{"month filtered", monthCmd, []string{"@client"}, []string{"this month", "today other"}, []string{"today task"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stdout, stderr := testDeps(createViewTestEntries(t))
+			SetDeps(d)
+			defer ResetDeps()
+			resetFilterFlags(tt.cmd)
+			defer resetFilterFlags(tt.cmd)
+
+			tt.cmd.Run(tt.cmd, tt.args)
+
+			if stderr.Len() > 0 {
+				t.Fatalf("Unexpected stderr output: %s", stderr.String())
+			}
+			output := stdout.String()
+			for _, want := range tt.contains {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, output)
+				}
+			}
+		})
+	}
+}
+
+func TestViews_InvalidArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     *cobra.Command
+		args    []string
+		wantErr string
+	}{
+		{"entry creation", weekCmd, []string{"fix", "bug", "for", "2h"}, "Time period flags cannot be used when creating entries"},
+		{"entry creation after prev", monthCmd, []string{"prev", "fix", "for", "2h"}, "Time period flags cannot be used when creating entries"},
+		{"unexpected word", todayCmd, []string{"yesterday"}, "Unexpected argument 'yesterday'"},
+		{"prev on yesterday", yesterdayCmd, []string{"prev"}, "Unexpected argument 'prev'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode := 0
+			d, stdout, stderr := testDeps(filepath.Join(t.TempDir(), "entries.jsonl"))
+			d.Exit = func(code int) { exitCode = code }
+			SetDeps(d)
+			defer ResetDeps()
+			resetFilterFlags(tt.cmd)
+			defer resetFilterFlags(tt.cmd)
+
+			tt.cmd.Run(tt.cmd, tt.args)
+
+			if exitCode != 1 || !strings.Contains(stderr.String(), tt.wantErr) {
+				t.Errorf("Expected exit 1 with %q, got exit %d: %s", tt.wantErr, exitCode, stderr.String())
+			}
+			if stdout.Len() > 0 {
+				t.Errorf("Expected no listing, got: %s", stdout.String())
+			}
+		})
+	}
+}
+
+func TestCompleteViewArgs(t *testing.T) {
+	d, _, _ := testDeps(createViewTestEntries(t))
+	SetDeps(d)
+	defer ResetDeps()
+
+	tests := []struct {
+		args       []string
+		toComplete string
+		expected   []string
+	}{
+		{nil, "", []string{"prev"}},
+		{nil, "p", []string{"prev"}},
+		{nil, "x", nil},
+		{[]string{"prev"}, "", nil},
+		{[]string{"prev"}, "@a", []string{"@acme"}},
+		{nil, "@c", []string{"@client"}},
+	}
+
+	for _, tt := range tests {
+		got, directive := completeViewArgs(weekCmd, tt.args, tt.toComplete)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("completeViewArgs(%v, %q) = %v, expected %v", tt.args, tt.toComplete, got, tt.expected)
+		}
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Errorf("completeViewArgs(%v, %q) directive = %v, expected NoFileComp", tt.args, tt.toComplete, directive)
+		}
+	}
+}
+
+func TestViews_HelpGroup(t *testing.T) {
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	defer rootCmd.SetOut(nil)
+
+	if err := rootCmd.Usage(); err != nil {
+		t.Fatalf("Usage returned error: %v", err)
+	}
+
+	usage := out.String()
+	viewsIdx := strings.Index(usage, "Views:")
+	if viewsIdx == -1 {
+		t.Fatalf("Expected a Views section in help, got:\n%s", usage)
+	}
+	for _, name := range []string{"today", "yesterday", "week", "month"} {
+		if !strings.Contains(usage[viewsIdx:], "  "+name+" ") {
+			t.Errorf("Expected %q under Views, got:\n%s", name, usage[viewsIdx:])
+		}
+	}
+}
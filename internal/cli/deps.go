@@ -31,7 +31,7 @@ func DefaultDeps() *Deps {
 	cfg := config.DefaultConfig()
 	configPath, err := config.GetConfigPath()
 	if err == nil {
-		if loadedCfg, err := config.LoadOrDefault(configPath); err == nil {
+		if loadedCfg, err := config.LoadWithEnv(configPath); err == nil {
 			cfg = loadedCfg
 		}
 	}
@@ -44,7 +44,7 @@ func DefaultDeps() *Deps {
 		Stdin:       os.Stdin,
 		Exit:        os.Exit,
 		Services:    services,
-		StoragePath: storage.GetStoragePath,
+		StoragePath: func() (string, error) { return storage.ResolveStoragePath(cfg.StoragePath) },
 		TimerPath:   timer.GetTimerPath,
 		Config:      cfg,
 	}
@@ -58,7 +58,7 @@ func NewDeps(services *service.Services, cfg config.Config) *Deps {
 		Stdin:       os.Stdin,
 		Exit:        os.Exit,
 		Services:    services,
-		StoragePath: storage.GetStoragePath,
+		StoragePath: func() (string, error) { return storage.ResolveStoragePath(cfg.StoragePath) },
 		TimerPath:   timer.GetTimerPath,
 		Config:      cfg,
 	}
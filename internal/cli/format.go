@@ -10,20 +10,13 @@ import (
 	"github.com/xolan/did/internal/entry"
 	"github.com/xolan/did/internal/service"
 	"github.com/xolan/did/internal/storage"
+	"github.com/xolan/did/internal/timeutil"
 )
 
 // FormatDuration formats minutes as a human-readable string
 // Examples: "30m", "2h", "1h 30m"
 func FormatDuration(minutes int) string {
-	if minutes < 60 {
-		return fmt.Sprintf("%dm", minutes)
-	}
-	hours := minutes / 60
-	mins := minutes % 60
-	if mins == 0 {
-		return fmt.Sprintf("%dh", hours)
-	}
-	return fmt.Sprintf("%dh %dm", hours, mins)
+	return timeutil.FormatDuration(minutes)
 }
 
 // FormatProjectAndTags formats project and tags for display.
@@ -64,16 +57,7 @@ func FormatEntry(e entry.Entry) string {
 // FormatElapsedTime formats a duration as human-readable elapsed time
 // Examples: "5m", "1h 23m", "2h"
 func FormatElapsedTime(d time.Duration) string {
-	totalMinutes := int(d.Minutes())
-	if totalMinutes < 60 {
-		return fmt.Sprintf("%dm", totalMinutes)
-	}
-	hours := totalMinutes / 60
-	mins := totalMinutes % 60
-	if mins == 0 {
-		return fmt.Sprintf("%dh", hours)
-	}
-	return fmt.Sprintf("%dh %dm", hours, mins)
+	return timeutil.FormatDuration(int(d.Minutes()))
 }
 
 // FormatDateRangeForDisplay formats a date range for human-readable display.
@@ -93,6 +77,9 @@ func FormatCorruptionWarning(warning storage.ParseWarning) string {
 	if len(content) > 50 {
 		content = content[:47] + "..."
 	}
+	if warning.File != "" {
+		return fmt.Sprintf("  %s line %d: %s (error: %s)", warning.File, warning.LineNumber, content, warning.Error)
+	}
 	return fmt.Sprintf("  Line %d: %s (error: %s)", warning.LineNumber, content, warning.Error)
 }
 
@@ -114,12 +101,18 @@ func BuildPeriodWithFilters(period, project string, tags []string) string {
 	return fmt.Sprintf("%s (%s)", period, strings.Join(filters, " "))
 }
 
-// Pluralize returns the singular or plural form of a word based on count
-func Pluralize(word string, count int) string {
+// Pluralize returns singular when count is 1 and plural otherwise,
+// e.g. Pluralize("entry", "entries", n)
+func Pluralize(singular, plural string, count int) string {
 	if count == 1 {
-		return word
+		return singular
 	}
-	return word + "s"
+	return plural
+}
+
+// FormatCount formats count with the matching form of a noun, e.g. "1 entry" or "12 entries"
+func FormatCount(count int, singular, plural string) string {
+	return fmt.Sprintf("%d %s", count, Pluralize(singular, plural, count))
 }
 
 // SpansMultipleDays checks if entries span multiple calendar days
@@ -240,6 +240,20 @@ func TestFormatCorruptionWarning_Truncation(t *testing.T) {
 	}
 }
 
+func TestFormatCorruptionWarning_File(t *testing.T) {
+	warning := storage.ParseWarning{
+		LineNumber: 3,
+		Content:    "not json",
+		Error:      "parse error",
+		File:       "bob.jsonl",
+	}
+
+	result := FormatCorruptionWarning(warning)
+	if result != "  bob.jsonl line 3: not json (error: parse error)" {
+		t.Errorf("unexpected result: %q", result)
+	}
+}
+
 func TestBuildPeriodWithFilters(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -268,27 +282,45 @@ func TestBuildPeriodWithFilters(t *testing.T) {
 
 func TestPluralize(t *testing.T) {
 	tests := []struct {
-		word  string
-		count int
-		want  string
+		singular string
+		plural   string
+		count    int
+		want     string
 	}{
-		{"entry", 0, "entrys"},
-		{"entry", 1, "entry"},
-		{"entry", 2, "entrys"},
-		{"day", 1, "day"},
-		{"day", 5, "days"},
+		{"entry", "entries", 0, "entries"},
+		{"entry", "entries", 1, "entry"},
+		{"entry", "entries", 2, "entries"},
+		{"day", "days", 1, "day"},
+		{"day", "days", 5, "days"},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.word, func(t *testing.T) {
-			result := Pluralize(tt.word, tt.count)
+		t.Run(tt.singular, func(t *testing.T) {
+			result := Pluralize(tt.singular, tt.plural, tt.count)
 			if result != tt.want {
-				t.Errorf("Pluralize(%q, %d) = %q, want %q", tt.word, tt.count, result, tt.want)
+				t.Errorf("Pluralize(%q, %q, %d) = %q, want %q", tt.singular, tt.plural, tt.count, result, tt.want)
 			}
 		})
 	}
 }
 
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		count int
+		want  string
+	}{
+		{0, "0 entries"},
+		{1, "1 entry"},
+		{12, "12 entries"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatCount(tt.count, "entry", "entries"); got != tt.want {
+			t.Errorf("FormatCount(%d) = %q, want %q", tt.count, got, tt.want)
+		}
+	}
+}
+
 func TestSpansMultipleDays(t *testing.T) {
 	now := time.Now()
 	yesterday := now.AddDate(0, 0, -1)
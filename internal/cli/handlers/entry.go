@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -16,13 +17,17 @@ import (
 func CreateEntry(deps *cli.Deps, rawInput string) {
 	entry, err := deps.Services.Entry.Create(rawInput)
 	if err != nil {
-		switch err {
-		case service.ErrMissingDuration:
-			_, _ = fmt.Fprintln(deps.Stderr, "Error: Invalid format. Missing 'for <duration>'")
-			_, _ = fmt.Fprintln(deps.Stderr, "Usage: did <description> for <duration>")
-			_, _ = fmt.Fprintln(deps.Stderr, "Example: did feature X for 2h")
-		case service.ErrEmptyDescription:
+		keyword := deps.Services.Entry.DurationKeyword()
+		switch {
+		case errors.Is(err, service.ErrMissingDuration):
+			_, _ = fmt.Fprintf(deps.Stderr, "Error: Invalid format. Missing '%s <duration>'\n", keyword)
+			_, _ = fmt.Fprintf(deps.Stderr, "Usage: did <description> %s <duration>\n", keyword)
+			_, _ = fmt.Fprintf(deps.Stderr, "Example: did feature X %s 2h\n", keyword)
+		case errors.Is(err, service.ErrEmptyDescription):
 			_, _ = fmt.Fprintln(deps.Stderr, "Error: Description cannot be empty")
+		case errors.Is(err, service.ErrDurationOnly):
+			_, _ = fmt.Fprintln(deps.Stderr, "Error: Description cannot be just a duration. Did you forget the description?")
+			_, _ = fmt.Fprintf(deps.Stderr, "Usage: did <description> %s <duration>\n", keyword)
 		default:
 			_, _ = fmt.Fprintf(deps.Stderr, "Error: %v\n", err)
 		}
@@ -46,7 +51,7 @@ func ListEntries(deps *cli.Deps, dateRange service.DateRangeSpec, f *filter.Filt
 
 	// Display warnings about corrupted lines
 	if len(result.Warnings) > 0 {
-		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Found %d corrupted line(s) in storage file:\n", len(result.Warnings))
+		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Found %s in storage file:\n", cli.FormatCount(len(result.Warnings), "corrupted line", "corrupted lines"))
 		for _, warning := range result.Warnings {
 			_, _ = fmt.Fprintln(deps.Stderr, cli.FormatCorruptionWarning(warning))
 		}
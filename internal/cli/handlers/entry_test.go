@@ -50,14 +50,15 @@ func setupTestDeps(t *testing.T) (*cli.Deps, *bytes.Buffer, *bytes.Buffer, *int)
 func setupBrokenDeps(t *testing.T) (*cli.Deps, *bytes.Buffer, *bytes.Buffer, *int) {
 	t.Helper()
 	tmpDir := t.TempDir()
-	storagePath := filepath.Join(tmpDir, "entries.jsonl")
 	timerPath := filepath.Join(tmpDir, "timer.json")
 	cfg := config.DefaultConfig()
 
-	// Create a directory where the storage file should be
-	if err := os.MkdirAll(storagePath, 0755); err != nil {
+	// Put the storage file below a regular file so it can't be read
+	blocker := filepath.Join(tmpDir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
 		t.Fatal(err)
 	}
+	storagePath := filepath.Join(blocker, "entries.jsonl")
 
 	services := &service.Services{
 		Entry:  service.NewEntryService(storagePath, cfg),
@@ -311,6 +312,19 @@ func TestCreateEntry_EmptyDescription(t *testing.T) {
 	}
 }
 
+func TestCreateEntry_DurationOnlyDescription(t *testing.T) {
+	deps, _, stderr, exitCode := setupTestDeps(t)
+
+	CreateEntry(deps, "2h for 2h")
+
+	if *exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", *exitCode)
+	}
+	if !strings.Contains(stderr.String(), "Did you forget the description?") {
+		t.Errorf("expected missing description error in stderr, got %q", stderr.String())
+	}
+}
+
 func TestListEntries_Empty(t *testing.T) {
 	deps, stdout, _, exitCode := setupTestDeps(t)
 
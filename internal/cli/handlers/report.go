@@ -20,7 +20,7 @@ func ReportByProject(deps *cli.Deps, project string, dateRange service.DateRange
 	_, _ = fmt.Fprintf(deps.Stdout, "Report for @%s (%s):\n", project, result.Period)
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
 	_, _ = fmt.Fprintf(deps.Stdout, "Total time:    %s\n", cli.FormatDuration(result.TotalMinutes))
-	_, _ = fmt.Fprintf(deps.Stdout, "Total entries: %d %s\n", result.EntryCount, cli.Pluralize("entry", result.EntryCount))
+	_, _ = fmt.Fprintf(deps.Stdout, "Total entries: %s\n", cli.FormatCount(result.EntryCount, "entry", "entries"))
 }
 
 // ReportByTags shows a report for specific tags
@@ -36,7 +36,7 @@ func ReportByTags(deps *cli.Deps, tags []string, dateRange service.DateRangeSpec
 	_, _ = fmt.Fprintf(deps.Stdout, "Report for %s (%s):\n", tagStr, result.Period)
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
 	_, _ = fmt.Fprintf(deps.Stdout, "Total time:    %s\n", cli.FormatDuration(result.TotalMinutes))
-	_, _ = fmt.Fprintf(deps.Stdout, "Total entries: %d %s\n", result.EntryCount, cli.Pluralize("entry", result.EntryCount))
+	_, _ = fmt.Fprintf(deps.Stdout, "Total entries: %s\n", cli.FormatCount(result.EntryCount, "entry", "entries"))
 }
 
 // ReportGroupByProject shows entries grouped by project
@@ -65,7 +65,7 @@ func ReportGroupByProject(deps *cli.Deps, dateRange service.DateRangeSpec) {
 			projectDisplay,
 			cli.FormatDuration(group.TotalMinutes),
 			group.EntryCount,
-			cli.Pluralize("entry", group.EntryCount))
+			cli.Pluralize("entry", "entries", group.EntryCount))
 	}
 
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("-", 50))
@@ -73,7 +73,7 @@ func ReportGroupByProject(deps *cli.Deps, dateRange service.DateRangeSpec) {
 		"Total",
 		cli.FormatDuration(result.TotalMinutes),
 		result.EntryCount,
-		cli.Pluralize("entry", result.EntryCount))
+		cli.Pluralize("entry", "entries", result.EntryCount))
 }
 
 // ReportGroupByTag shows entries grouped by tag
@@ -102,7 +102,7 @@ func ReportGroupByTag(deps *cli.Deps, dateRange service.DateRangeSpec) {
 			tagDisplay,
 			cli.FormatDuration(group.TotalMinutes),
 			group.EntryCount,
-			cli.Pluralize("entry", group.EntryCount))
+			cli.Pluralize("entry", "entries", group.EntryCount))
 	}
 
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("-", 50))
@@ -110,5 +110,5 @@ func ReportGroupByTag(deps *cli.Deps, dateRange service.DateRangeSpec) {
 		"Total",
 		cli.FormatDuration(result.TotalMinutes),
 		result.EntryCount,
-		cli.Pluralize("entry", result.EntryCount))
+		cli.Pluralize("entry", "entries", result.EntryCount))
 }
@@ -20,7 +20,7 @@ func Search(deps *cli.Deps, keyword string, dateRange *service.DateRangeSpec, f
 
 	// Display warnings about corrupted lines
 	if len(result.Warnings) > 0 {
-		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Found %d corrupted line(s) in storage file:\n", len(result.Warnings))
+		_, _ = fmt.Fprintf(deps.Stderr, "Warning: Found %s in storage file:\n", cli.FormatCount(len(result.Warnings), "corrupted line", "corrupted lines"))
 		for _, warning := range result.Warnings {
 			_, _ = fmt.Fprintln(deps.Stderr, cli.FormatCorruptionWarning(warning))
 		}
@@ -42,7 +42,7 @@ func Search(deps *cli.Deps, keyword string, dateRange *service.DateRangeSpec, f
 		header = "All entries"
 	}
 
-	_, _ = fmt.Fprintf(deps.Stdout, "%s (%d %s):\n", header, result.Total, cli.Pluralize("result", result.Total))
+	_, _ = fmt.Fprintf(deps.Stdout, "%s (%d %s):\n", header, result.Total, cli.Pluralize("result", "results", result.Total))
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("-", 50))
 
 	// Calculate max index width for alignment
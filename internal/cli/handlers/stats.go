@@ -41,8 +41,8 @@ func displayStats(deps *cli.Deps, totalMinutes, entryCount, daysWithEntries int,
 	_, _ = fmt.Fprintf(deps.Stdout, "Statistics for %s:\n", period)
 	_, _ = fmt.Fprintln(deps.Stdout, strings.Repeat("=", 50))
 	_, _ = fmt.Fprintf(deps.Stdout, "Total time:      %s\n", cli.FormatDuration(totalMinutes))
-	_, _ = fmt.Fprintf(deps.Stdout, "Total entries:   %d %s\n", entryCount, cli.Pluralize("entry", entryCount))
-	_, _ = fmt.Fprintf(deps.Stdout, "Days with work:  %d %s\n", daysWithEntries, cli.Pluralize("day", daysWithEntries))
+	_, _ = fmt.Fprintf(deps.Stdout, "Total entries:   %s\n", cli.FormatCount(entryCount, "entry", "entries"))
+	_, _ = fmt.Fprintf(deps.Stdout, "Days with work:  %s\n", cli.FormatCount(daysWithEntries, "day", "days"))
 	_, _ = fmt.Fprintf(deps.Stdout, "Average per day: %s\n", cli.FormatDuration(int(avgPerDay)))
 
 	if comparison != "" {
@@ -9,91 +9,297 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/xolan/did/internal/app"
+	"github.com/xolan/did/internal/entry"
 	"github.com/xolan/did/internal/osutil"
+	"github.com/xolan/did/internal/timeutil"
 )
 
 const (
 	// ConfigFile is the name of the TOML configuration file
 	ConfigFile = "config.toml"
+
+	// DefaultFutureMarginMinutes is the default future_margin_minutes
+	DefaultFutureMarginMinutes = 5
+
+	// DefaultMaxEntryDuration is the default max_entry_duration
+	DefaultMaxEntryDuration = "24h"
+
+	// DefaultTimestampPrecision is the default timestamp_precision
+	DefaultTimestampPrecision = "second"
 )
 
+// TimestampPrecisions maps the valid timestamp_precision values to the unit
+// the timestamps of new entries are truncated to; "exact" keeps them as read
+// from the clock
+var TimestampPrecisions = map[string]time.Duration{
+	"minute": time.Minute,
+	"second": time.Second,
+	"exact":  0,
+}
+
 // Config represents the application configuration
 type Config struct {
+	// Version is the config file format version (see CurrentVersion); files without it are version 0
+	Version int `toml:"version"`
 	// WeekStartDay defines which day starts the week (monday or sunday)
 	WeekStartDay string `toml:"week_start_day"`
+	// MonthStartDay is the day of the month (1-28) months start on, e.g. 25 for invoicing months from the 25th to the 24th
+	MonthStartDay int `toml:"month_start_day"`
 	// Timezone defines the timezone for time operations (IANA timezone name, e.g., "America/New_York")
 	Timezone string `toml:"timezone"`
 	// DefaultOutputFormat defines the default output format for entries
 	DefaultOutputFormat string `toml:"default_output_format"`
 	// Theme defines the TUI color theme (bubbletint theme name)
 	Theme string `toml:"theme"`
+	// DurableWrites flushes new entries to disk (fsync) before reporting success
+	DurableWrites bool `toml:"durable_writes"`
+	// StoragePath overrides the location of the entries file (absolute path, empty uses the default)
+	StoragePath string `toml:"storage_path"`
+	// MyFile is the file new entries are written to when storage_path is a directory shared by a team
+	MyFile string `toml:"my_file"`
+	// DefaultProject is assigned to new entries logged without an @project
+	DefaultProject string `toml:"default_project"`
+	// RoundMinutes rounds the duration of new entries up to a multiple of this many minutes (0 disables rounding)
+	RoundMinutes int `toml:"round_minutes"`
+	// RoundDisplayMinutes rounds the durations shown by listings, stats and exports to the nearest multiple of this many minutes (0 shows them exactly)
+	RoundDisplayMinutes int `toml:"round_display_minutes"`
+	// DurationKeyword separates the description from the duration when logging ("<description> for <duration>")
+	DurationKeyword string `toml:"duration_keyword"`
+	// DefaultDurationMinutes is the duration of entries logged without "for <duration>" (0 requires the duration)
+	DefaultDurationMinutes int `toml:"default_duration_minutes"`
+	// FutureMarginMinutes is how far in the future a new entry may be dated before it is rejected as clock skew
+	FutureMarginMinutes int `toml:"future_margin_minutes"`
+	// MaxEntryDuration is the longest duration of a new, edited or imported entry (e.g. "24h")
+	MaxEntryDuration string `toml:"max_entry_duration"`
+	// TimestampPrecision is the unit the timestamps of new entries are truncated to (see TimestampPrecisions)
+	TimestampPrecision string `toml:"timestamp_precision"`
+	// SplitAtMidnight apportions entries running past midnight to each day they cover in reports and stats
+	SplitAtMidnight bool `toml:"split_at_midnight"`
+	// FooterBreakdown adds the time per project to the Total line of listings with several projects
+	FooterBreakdown bool `toml:"footer_breakdown"`
+	// Sparkline adds the time logged on each of the last 14 days under today's entries
+	Sparkline bool `toml:"sparkline"`
+	// Pager shows listings in $PAGER when output is a terminal
+	Pager bool `toml:"pager"`
+	// NumberFormat is how decimal numbers are written in human output (see timeutil.NumberFormats)
+	NumberFormat string `toml:"number_format"`
+	// WorkingHours is the weekly schedule of expected hours per day, used by the deficit command
+	WorkingHours WorkingHours `toml:"working_hours"`
+	// Workspaces maps directories to the project and tags of entries logged in them without an @project
+	Workspaces Workspaces `toml:"workspaces"`
 }
 
 // DefaultConfig returns a Config with sensible defaults that match current behavior.
+// - version: CurrentVersion
 // - week_start_day: "monday" (ISO 8601 standard, current behavior)
+// - month_start_day: 1 (calendar months)
 // - timezone: "Local" (use system local timezone)
 // - default_output_format: "" (use current default formatting)
 // - theme: "" (use default TUI theme)
+// - durable_writes: false (rely on the OS to flush writes, fastest)
+// - storage_path: "" (use entries.jsonl in the data directory)
+// - my_file: "" (only needed when storage_path is a directory)
+// - default_project: "" (entries without @project have no project)
+// - round_minutes: 0 (durations are stored as entered)
+// - round_display_minutes: 0 (durations are shown as stored)
+// - duration_keyword: "for" (did <description> for <duration>)
+// - default_duration_minutes: 0 (logging requires "for <duration>")
+// - future_margin_minutes: 5 (new entries dated further ahead are rejected)
+// - max_entry_duration: "24h" (longer entries need --allow-long, also to import them)
+// - timestamp_precision: "second" (new entries are stored without fractions of a second)
+// - split_at_midnight: false (an entry counts on the day it starts)
+// - footer_breakdown: true (the Total line of listings shows the time per project)
+// - sparkline: true (today's listing ends with the trend of the last 14 days)
+// - pager: false (listings are written directly to the terminal)
+// - number_format: "plain" (1234.50, no thousands separator)
+// - working_hours: none (the deficit command is disabled)
+// - workspaces: none (the working directory does not affect new entries)
 func DefaultConfig() Config {
 	return Config{
+		Version:             CurrentVersion,
 		WeekStartDay:        "monday",
+		MonthStartDay:       1,
 		Timezone:            "Local",
 		DefaultOutputFormat: "",
 		Theme:               "",
+		DurableWrites:       false,
+		StoragePath:         "",
+		DefaultProject:      "",
+		RoundMinutes:        0,
+		DurationKeyword:     entry.DefaultDurationKeyword,
+		FutureMarginMinutes: DefaultFutureMarginMinutes,
+		MaxEntryDuration:    DefaultMaxEntryDuration,
+		TimestampPrecision:  DefaultTimestampPrecision,
+		SplitAtMidnight:     false,
+		FooterBreakdown:     true,
+		Sparkline:           true,
+		Pager:               false,
+		NumberFormat:        timeutil.DefaultNumberFormat,
 	}
 }
 
-// GetConfigPath returns the path to the config file.
-// Uses os.UserConfigDir() for cross-platform XDG-compliant config directory.
-// Creates the config directory if it doesn't exist.
-func GetConfigPath() (string, error) {
-	configDir, err := osutil.Provider.UserConfigDir()
-	if err != nil {
-		return "", err
+// ConfigPathEnv is the environment variable that points at another config file
+const ConfigPathEnv = "DID_CONFIG"
+
+// configPathFlag is the config file given with --config (see SetConfigPath)
+var configPathFlag string
+
+// SetConfigPath makes GetConfigPath return path, taking precedence over
+// DID_CONFIG (used for the --config flag). An empty path restores the usual
+// resolution.
+func SetConfigPath(path string) {
+	configPathFlag = path
+}
+
+// ConfigPathSource returns what chose the config file: "--config",
+// ConfigPathEnv, or "" for the default location. A config file chosen
+// explicitly must exist, while a missing file at the default location means
+// the defaults are used.
+func ConfigPathSource() string {
+	switch {
+	case configPathFlag != "":
+		return "--config"
+	case os.Getenv(ConfigPathEnv) != "":
+		return ConfigPathEnv
+	default:
+		return ""
 	}
+}
 
-	appDir := filepath.Join(configDir, app.Name)
+// GetConfigPath returns the path to the config file: the --config path (see
+// SetConfigPath), else DID_CONFIG, else config.toml in the did directory of
+// os.UserConfigDir(), the cross-platform XDG-compliant config directory.
+// The config directory is not created here; commands that write the config
+// file create it, so reading the config works on a read-only file system.
+func GetConfigPath() (string, error) {
+	if configPathFlag != "" {
+		return configPathFlag, nil
+	}
+	if path := os.Getenv(ConfigPathEnv); path != "" {
+		return path, nil
+	}
 
-	// Create config directory if it doesn't exist
-	if err := osutil.Provider.MkdirAll(appDir, 0755); err != nil {
+	configDir, err := osutil.Provider.UserConfigDir()
+	if err != nil {
 		return "", err
 	}
 
-	return filepath.Join(appDir, ConfigFile), nil
+	return filepath.Join(configDir, app.Name, ConfigFile), nil
 }
 
 func (c *Config) Normalize() {
 	c.WeekStartDay = strings.ToLower(strings.TrimSpace(c.WeekStartDay))
 	c.Timezone = strings.TrimSpace(c.Timezone)
 	c.Theme = strings.TrimSpace(c.Theme)
+	c.StoragePath = strings.TrimSpace(c.StoragePath)
+	c.MyFile = strings.TrimSpace(c.MyFile)
+	c.DefaultProject = strings.TrimPrefix(strings.TrimSpace(c.DefaultProject), "@")
+	c.DurationKeyword = strings.ToLower(strings.TrimSpace(c.DurationKeyword))
+	c.NumberFormat = strings.ToLower(strings.TrimSpace(c.NumberFormat))
+	c.MaxEntryDuration = strings.ToLower(strings.TrimSpace(c.MaxEntryDuration))
+	c.TimestampPrecision = strings.ToLower(strings.TrimSpace(c.TimestampPrecision))
+	c.WorkingHours = c.WorkingHours.normalize()
+	c.Workspaces = c.Workspaces.normalize()
 }
 
 func (c *Config) Validate() error {
+	if c.Version < 0 {
+		return fmt.Errorf("invalid version: must not be negative, got %d", c.Version)
+	}
+
 	if c.WeekStartDay != "monday" && c.WeekStartDay != "sunday" {
 		return fmt.Errorf("invalid week_start_day: must be 'monday' or 'sunday', got '%s'", c.WeekStartDay)
 	}
 
+	if c.MonthStartDay < 1 || c.MonthStartDay > 28 {
+		return fmt.Errorf("invalid month_start_day: must be between 1 and 28, got %d", c.MonthStartDay)
+	}
+
 	if c.Timezone != "" && c.Timezone != "Local" {
 		_, err := time.LoadLocation(c.Timezone)
 		if err != nil {
-			return fmt.Errorf("invalid timezone: '%s' is not a valid IANA timezone (e.g., 'America/New_York', 'Europe/London')", c.Timezone)
+			return fmt.Errorf("invalid timezone: %w", timeutil.TimezoneError(c.Timezone))
 		}
 	}
 
+	if c.StoragePath != "" && !filepath.IsAbs(c.StoragePath) {
+		return fmt.Errorf("invalid storage_path: '%s' must be an absolute path", c.StoragePath)
+	}
+
+	if c.MyFile != "" && (filepath.Base(c.MyFile) != c.MyFile || strings.ContainsAny(c.MyFile, `/\`) ||
+		!strings.HasSuffix(c.MyFile, ".jsonl") || c.MyFile == ".jsonl") {
+		return fmt.Errorf("invalid my_file: '%s' must be a file name ending in .jsonl (e.g., 'alice.jsonl')", c.MyFile)
+	}
+
+	if c.DefaultProject != "" && !entry.IsValidName(c.DefaultProject) {
+		return fmt.Errorf("invalid default_project: '%s' may only contain letters, digits, hyphens, underscores, and single spaces between words", c.DefaultProject)
+	}
+
+	if c.RoundMinutes < 0 || c.RoundMinutes > 60 {
+		return fmt.Errorf("invalid round_minutes: must be between 0 and 60, got %d", c.RoundMinutes)
+	}
+
+	if c.RoundDisplayMinutes < 0 || c.RoundDisplayMinutes > 60 {
+		return fmt.Errorf("invalid round_display_minutes: must be between 0 and 60, got %d", c.RoundDisplayMinutes)
+	}
+
+	if c.DurationKeyword != "" && (len(strings.Fields(c.DurationKeyword)) != 1 || strings.ContainsAny(c.DurationKeyword, "@#")) {
+		return fmt.Errorf("invalid duration_keyword: '%s' must be a single word (e.g., 'for', 'für', 'pendant')", c.DurationKeyword)
+	}
+
+	if c.FutureMarginMinutes < 0 || c.FutureMarginMinutes > entry.MaxDurationMinutes {
+		return fmt.Errorf("invalid future_margin_minutes: must be between 0 and %d, got %d", entry.MaxDurationMinutes, c.FutureMarginMinutes)
+	}
+
+	if c.MaxEntryDuration != "" {
+		if minutes, err := entry.ParseDurationMinutes(c.MaxEntryDuration); err != nil || minutes <= 0 {
+			return fmt.Errorf("invalid max_entry_duration: '%s' must be a positive duration (e.g., '24h', '12h', '8h30m')", c.MaxEntryDuration)
+		}
+	}
+
+	if _, ok := TimestampPrecisions[c.TimestampPrecision]; c.TimestampPrecision != "" && !ok {
+		return fmt.Errorf("invalid timestamp_precision: must be 'minute', 'second' or 'exact', got '%s'", c.TimestampPrecision)
+	}
+
+	if c.DefaultDurationMinutes < 0 || c.DefaultDurationMinutes > c.MaxEntryMinutes() {
+		return fmt.Errorf("invalid default_duration_minutes: must be between 0 and %d (max_entry_duration), got %d", c.MaxEntryMinutes(), c.DefaultDurationMinutes)
+	}
+
+	if _, ok := timeutil.NumberFormats[c.NumberFormat]; c.NumberFormat != "" && !ok {
+		return fmt.Errorf("invalid number_format: must be one of '%s', got '%s'", strings.Join(timeutil.NumberFormatNames(), "', '"), c.NumberFormat)
+	}
+
+	if err := c.WorkingHours.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Workspaces.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// Load reads and validates the config file at path. Settings missing from the
+// file keep their DefaultConfig value, and a config of an older version is
+// migrated to CurrentVersion in memory (Migrate updates the file itself).
 func Load(path string) (Config, error) {
 	cfg := DefaultConfig()
 
-	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+	md, err := toml.DecodeFile(path, &cfg)
+	if err != nil {
 		return Config{}, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	// Files written before the version setting existed are version 0
+	if !md.IsDefined("version") {
+		cfg.Version = 0
+	}
 	cfg.Normalize()
 	if err := cfg.Validate(); err != nil {
 		return Config{}, err
 	}
+	cfg.migrate()
 
 	return cfg, nil
 }
@@ -116,6 +322,21 @@ func LoadOrDefault(path string) (Config, error) {
 	return Load(path)
 }
 
+// GenerateConfig returns TOML configuration file content with the given settings.
+// Used by the setup wizard; the storage path is only written when it is set.
+func GenerateConfig(cfg Config) string {
+	var b strings.Builder
+	b.WriteString("# did configuration file\n")
+	b.WriteString("# Run 'did config init' for a sample file documenting every option.\n\n")
+	fmt.Fprintf(&b, "version = %d\n", CurrentVersion)
+	fmt.Fprintf(&b, "week_start_day = %q\n", cfg.WeekStartDay)
+	fmt.Fprintf(&b, "timezone = %q\n", cfg.Timezone)
+	if cfg.StoragePath != "" {
+		fmt.Fprintf(&b, "storage_path = %q\n", cfg.StoragePath)
+	}
+	return b.String()
+}
+
 // GenerateSampleConfig returns a sample TOML configuration file content
 // with all options commented out and documented with explanations and examples.
 func GenerateSampleConfig() string {
@@ -124,6 +345,10 @@ func GenerateSampleConfig() string {
 # All settings have sensible defaults. Uncomment and modify only the
 # settings you want to customize.
 
+# Config file format version. 'did migrate --config-file' updates an older file;
+# do not change it by hand.
+version = 1
+
 # ============================================================================
 # Week Start Day
 # ============================================================================
@@ -139,6 +364,22 @@ func GenerateSampleConfig() string {
 #
 # week_start_day = "monday"
 
+# ============================================================================
+# Month Start Day
+# ============================================================================
+# Defines which day of the month starts the month for monthly views
+# (--this-month, --prev-month, 'did stats --month', 'did deficit'), e.g. for
+# an invoicing month running from the 25th to the 24th. Days after the 28th
+# are not allowed, since not every month has them.
+#
+# Valid values: 1 to 28
+# Default: 1 (calendar months)
+#
+# Examples:
+#   month_start_day = 25         # this month is May 25 - Jun 24
+#
+# month_start_day = 1
+
 # ============================================================================
 # Timezone
 # ============================================================================
@@ -194,5 +435,353 @@ func GenerateSampleConfig() string {
 # You can also change themes within the TUI using [ and ] keys.
 #
 # theme = ""
+
+# ============================================================================
+# Durable Writes
+# ============================================================================
+# Flushes each new entry to disk (fsync) before reporting success, so an
+# entry logged right before a crash or power loss is not lost. This makes
+# logging slightly slower, so it is off by default.
+#
+# Valid values: true, false
+# Default: false
+#
+# Examples:
+#   durable_writes = false         # Let the OS flush writes (default)
+#   durable_writes = true          # Sync every new entry to disk
+#
+# durable_writes = false
+
+# ============================================================================
+# Storage Path
+# ============================================================================
+# Location of the entries file. Must be an absolute path. The directory is
+# created if it doesn't exist.
+#
+# Default: "" (entries.jsonl in the data directory, ~/.local/share/did on Linux)
+#
+# Examples:
+#   storage_path = "/home/me/Dropbox/did/entries.jsonl"
+#
+# storage_path = ""
+
+# ============================================================================
+# Shared Storage Directory
+# ============================================================================
+# storage_path may also point at a directory, e.g. a folder shared by a team.
+# Every *.jsonl file in it is read, and the entries you log are written to
+# the file named here. Files of other team members are only rewritten when
+# you edit or delete one of their entries.
+#
+# Default: "" (required when storage_path is a directory)
+#
+# Examples:
+#   storage_path = "/home/me/Dropbox/team-did"
+#   my_file = "alice.jsonl"
+#
+# my_file = ""
+
+# ============================================================================
+# Default Project
+# ============================================================================
+# Project assigned to new entries that are logged without an @project.
+#
+# Default: "" (no project)
+#
+# Examples:
+#   default_project = "acme"
+#
+# default_project = ""
+
+# ============================================================================
+# Duration Rounding
+# ============================================================================
+# Rounds the duration of new entries (logged or from a stopped timer) up to
+# a multiple of this many minutes, e.g. for billing in 15 minute blocks.
+#
+# Valid values: 0 to 60
+# Default: 0 (no rounding)
+#
+# Examples:
+#   round_minutes = 15             # 20m is stored as 30m
+#
+# round_minutes = 0
+
+# ============================================================================
+# Display Rounding
+# ============================================================================
+# Rounds the durations shown by listings, 'did stats' and exports to the
+# nearest multiple of this many minutes, while storage keeps the exact
+# durations. Each entry is rounded on its own (a short entry never shows as
+# 0) and totals add up the rounded entries. --round-display overrides it.
+#
+# Valid values: 0 to 60
+# Default: 0 (durations are shown as stored)
+#
+# Examples:
+#   round_display_minutes = 15     # 20m is shown as 15m, 23m as 30m
+#
+# round_display_minutes = 0
+
+# ============================================================================
+# Duration Keyword
+# ============================================================================
+# The word between the description and the duration when logging an entry,
+# for use in your own language. Matched case-insensitively; must be a single
+# word.
+#
+# Default: "for" (did fix login bug for 2h)
+#
+# Examples:
+#   duration_keyword = "für"       # did Fehler behoben für 2h
+#   duration_keyword = "pendant"   # did revue de code pendant 30m
+#
+# duration_keyword = "for"
+
+# ============================================================================
+# Default Duration
+# ============================================================================
+# Duration in minutes of entries logged without '<keyword> <duration>', e.g.
+# for tasks that usually take the same time. An explicit duration always
+# wins, and the confirmation notes when the default was used.
+#
+# Valid values: 0 to max_entry_duration in minutes
+# Default: 0 (the duration must be given)
+#
+# Examples:
+#   default_duration_minutes = 30  # did review PR 42 logs 30m
+#
+# default_duration_minutes = 0
+
+# ============================================================================
+# Future Margin
+# ============================================================================
+# New entries are dated with the current time. If that is more than this
+# many minutes in the future, e.g. after the system clock was reset, the
+# entry is rejected unless --allow-future is given. 'did validate' reports
+# existing entries dated more than a day in the future.
+#
+# Valid values: 0 to 1440
+# Default: 5
+#
+# Examples:
+#   future_margin_minutes = 60     # Tolerate a clock up to an hour ahead
+#
+# future_margin_minutes = 5
+
+# ============================================================================
+# Max Entry Duration
+# ============================================================================
+# The longest duration of an entry. Logging or editing an entry longer than
+# this needs --allow-long (it is usually a typo, like 80h for 8h), and
+# imported entries longer than this are reported as invalid unless
+# 'did import' is given --allow-long as well.
+#
+# Format: Xh, Xm or XhYm
+# Default: "24h"
+#
+# Examples:
+#   max_entry_duration = "12h"     # Nobody here works longer in one go
+#   max_entry_duration = "72h"     # Log multi-day events as one entry
+#
+# max_entry_duration = "24h"
+
+# ============================================================================
+# Timestamp Precision
+# ============================================================================
+# New entries are dated with the current time, truncated to this precision,
+# so the storage file has no sub-second clutter and diffs stay clean when you
+# keep it under version control. Existing entries keep their timestamps until
+# 'did storage normalize' truncates them to this precision.
+#
+# Valid values:
+#   "minute"  2024-01-15T09:41:00Z
+#   "second"  2024-01-15T09:41:27Z (default)
+#   "exact"   2024-01-15T09:41:27.123456789Z (as read from the clock)
+#
+# timestamp_precision = "second"
+
+# ============================================================================
+# Split At Midnight
+# ============================================================================
+# Reports and stats attribute an entry to the day it starts, so 5 hours
+# logged at 22:00 all count on that day. When enabled, an entry running past
+# midnight is apportioned to each day it covers (2h and 3h), for display and
+# totals only; the stored entry is not changed. --split-days on report and
+# stats overrides this setting.
+#
+# Valid values: true, false
+# Default: false
+#
+# split_at_midnight = false
+
+# ============================================================================
+# Footer Breakdown
+# ============================================================================
+# When a listing covers several projects, its Total line also shows the time
+# per project, most time first, e.g.:
+#
+#   Total: 6h 30m (@acme 4h, @client 2h, no project 30m)
+#
+# Up to 5 projects are named, the rest are counted as "+N more".
+#
+# Valid values: true, false
+# Default: true
+#
+# footer_breakdown = true
+
+# ============================================================================
+# Sparkline
+# ============================================================================
+# The listing of today's entries ends with the time logged on each of the
+# last 14 days, in the configured timezone, once at least 3 of them have
+# entries, e.g.:
+#
+#   Last 14 days: ▁▂▄▁▇▃▅▁▁▆▃▄▂▅ (avg 4h 10m/day)
+#
+# It is only shown when output is a terminal, never in piped output.
+#
+# Valid values: true, false
+# Default: true
+#
+# sparkline = true
+
+# ============================================================================
+# Pager
+# ============================================================================
+# Show listings in a pager when output is a terminal, like git does. The
+# pager is $PAGER, or "less -R" when it is not set. Output that is piped or
+# redirected is never paged. --pager and --no-pager override this setting.
+#
+# Valid values: true, false
+# Default: false
+#
+# pager = false
+
+# ============================================================================
+# Number Format
+# ============================================================================
+# How decimal numbers, such as the average hours per day in 'did stats', are
+# written. CSV and JSON output always use the plain format so other programs
+# can read it.
+#
+# Valid values:
+#   "plain"   1234.50 (default)
+#   "en"      1,234.50
+#   "de"      1.234,50 (also most of continental Europe)
+#   "fr"      1 234,50
+#   "ch"      1'234.50
+#
+# number_format = "plain"
+
+# ============================================================================
+# Working Hours
+# ============================================================================
+# The number of hours you expect to log on each day of the week. Used by
+# 'did deficit' to show how far ahead or behind you are. Days that are not
+# listed expect no hours. Valid keys are mon, tue, wed, thu, fri, sat, sun.
+#
+# Default: not set (the deficit command is disabled)
+#
+# Examples:
+#   [working_hours]
+#   mon = 8
+#   tue = 8
+#   wed = 8
+#   thu = 8
+#   fri = 6
+#
+# Note: this table must come after all the other settings in the file.
+#
+# [working_hours]
+# mon = 8
+
+# ============================================================================
+# Workspaces
+# ============================================================================
+# The project and tags of entries logged without an @project while the
+# working directory is inside one of these directories (or any of their
+# subdirectories). The most specific directory wins, and an explicit @project
+# always wins over the workspace. Use --no-workspace to skip it once.
+# Directories are absolute or start with ~/.
+#
+# Default: not set (the working directory does not affect new entries)
+#
+# Examples:
+#   [workspaces."~/code/acme-app"]
+#   project = "acme"
+#   tags = ["dev"]
+#
+# Note: like [working_hours], these tables must come after all the other
+# settings in the file.
+#
+# [workspaces."~/code/acme-app"]
+# project = "acme"
+# tags = ["dev"]
+
+# ============================================================================
+# Environment Variables
+# ============================================================================
+# These environment variables override the settings above (useful in
+# containers and CI). Command-line flags take precedence over both.
+#
+#   DID_TIMEZONE          timezone
+#   DID_WEEK_START        week_start_day
+#   DID_DEFAULT_PROJECT   default_project
+#   DID_STORE             storage_path
+#   DID_ROUND             round_minutes
 `
 }
+
+// EffectiveDurationKeyword returns the configured duration keyword, or the
+// default "for" when none is set
+func (c Config) EffectiveDurationKeyword() string {
+	if c.DurationKeyword == "" {
+		return entry.DefaultDurationKeyword
+	}
+	return c.DurationKeyword
+}
+
+// EffectiveNumberFormat returns the configured number format, or the plain
+// default when none is set
+func (c Config) EffectiveNumberFormat() timeutil.NumberFormat {
+	if f, ok := timeutil.NumberFormats[c.NumberFormat]; ok {
+		return f
+	}
+	return timeutil.NumberFormats[timeutil.DefaultNumberFormat]
+}
+
+// MaxEntryMinutes returns the configured max_entry_duration in minutes, or
+// entry.MaxDurationMinutes when none is set
+func (c Config) MaxEntryMinutes() int {
+	if minutes, err := entry.ParseDurationMinutes(c.MaxEntryDuration); err == nil && minutes > 0 {
+		return minutes
+	}
+	return entry.MaxDurationMinutes
+}
+
+// EffectiveTimestampPrecision returns the unit the timestamps of new entries
+// are truncated to, the default second when timestamp_precision is not set,
+// or 0 for "exact"
+func (c Config) EffectiveTimestampPrecision() time.Duration {
+	if precision, ok := TimestampPrecisions[c.TimestampPrecision]; ok {
+		return precision
+	}
+	return TimestampPrecisions[DefaultTimestampPrecision]
+}
+
+// ApplyEntryDefaults fills in the configured default project and file, rounds
+// the duration and truncates the timestamp to timestamp_precision of a newly
+// created entry
+func (c Config) ApplyEntryDefaults(e *entry.Entry) {
+	if e.Project == "" {
+		e.Project = c.DefaultProject
+	}
+	if e.Source == "" {
+		e.Source = c.MyFile
+	}
+	e.DurationMinutes = entry.RoundDuration(e.DurationMinutes, c.RoundMinutes)
+	if precision := c.EffectiveTimestampPrecision(); precision > 0 {
+		e.Timestamp = e.Timestamp.Truncate(precision)
+	}
+}
@@ -3,10 +3,14 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/xolan/did/internal/app"
+	"github.com/xolan/did/internal/entry"
 	"github.com/xolan/did/internal/osutil"
 )
 
@@ -39,6 +43,145 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.DefaultOutputFormat != "" {
 		t.Errorf("DefaultConfig().DefaultOutputFormat = %q, expected %q", cfg.DefaultOutputFormat, "")
 	}
+
+	// Verify durable writes are off by default
+	if cfg.DurableWrites {
+		t.Error("DefaultConfig().DurableWrites = true, expected false")
+	}
+
+	// Verify the default duration keyword
+	if cfg.DurationKeyword != "for" {
+		t.Errorf("DefaultConfig().DurationKeyword = %q, expected %q", cfg.DurationKeyword, "for")
+	}
+
+	// Verify the default future margin
+	if cfg.FutureMarginMinutes != 5 {
+		t.Errorf("DefaultConfig().FutureMarginMinutes = %d, expected %d", cfg.FutureMarginMinutes, 5)
+	}
+
+	// Verify the listing footer shows the project breakdown by default
+	if !cfg.FooterBreakdown {
+		t.Error("DefaultConfig().FooterBreakdown = false, expected true")
+	}
+}
+
+func TestValidate_StoragePath(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.StoragePath = filepath.Join(t.TempDir(), "entries.jsonl")
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() returned unexpected error for absolute storage_path: %v", err)
+	}
+
+	cfg.StoragePath = "relative/entries.jsonl"
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "storage_path") {
+		t.Errorf("Validate() should reject relative storage_path, got: %v", err)
+	}
+}
+
+func TestGenerateConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.WeekStartDay = "sunday"
+	cfg.Timezone = "Europe/London"
+
+	content := GenerateConfig(cfg)
+	if strings.Contains(content, "storage_path") {
+		t.Errorf("GenerateConfig() should omit an empty storage_path, got: %s", content)
+	}
+
+	cfg.StoragePath = filepath.Join(t.TempDir(), "entries.jsonl")
+	tmpFile := createTempConfigFile(t, GenerateConfig(cfg))
+	loaded, err := Load(tmpFile)
+	if err != nil {
+		t.Fatalf("Load() of generated config returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, cfg) {
+		t.Errorf("Generated config round-trip = %+v, expected %+v", loaded, cfg)
+	}
+}
+
+func TestLoad_DurableWrites(t *testing.T) {
+	tmpFile := createTempConfigFile(t, "durable_writes = true\n")
+
+	cfg, err := Load(tmpFile)
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if !cfg.DurableWrites {
+		t.Error("Load().DurableWrites = false, expected true")
+	}
+}
+
+func TestLoad_FooterBreakdown(t *testing.T) {
+	cfg, err := Load(createTempConfigFile(t, "footer_breakdown = false\n"))
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.FooterBreakdown {
+		t.Error("Load().FooterBreakdown = true, expected false")
+	}
+
+	// Files without the setting keep the default
+	cfg, err = Load(createTempConfigFile(t, "week_start_day = \"sunday\"\n"))
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if !cfg.FooterBreakdown {
+		t.Error("Load().FooterBreakdown = false for a file without the setting, expected true")
+	}
+}
+
+func TestLoad_Pager(t *testing.T) {
+	cfg, err := Load(createTempConfigFile(t, "pager = true\n"))
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if !cfg.Pager {
+		t.Error("Load().Pager = false, expected true")
+	}
+}
+
+func TestLoad_NumberFormat(t *testing.T) {
+	cfg, err := Load(createTempConfigFile(t, "number_format = \" DE \"\n"))
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.NumberFormat != "de" {
+		t.Errorf("Load().NumberFormat = %q, expected %q", cfg.NumberFormat, "de")
+	}
+	if got := cfg.EffectiveNumberFormat().Format(1234.5, 2); got != "1.234,50" {
+		t.Errorf("EffectiveNumberFormat().Format() = %q, expected %q", got, "1.234,50")
+	}
+
+	if _, err := Load(createTempConfigFile(t, "number_format = \"klingon\"\n")); err == nil || !strings.Contains(err.Error(), "invalid number_format") {
+		t.Errorf("Expected an invalid number_format error, got: %v", err)
+	}
+	if got := (Config{}).EffectiveNumberFormat().Format(1234.5, 2); got != "1234.50" {
+		t.Errorf("Expected the plain format when unset, got %q", got)
+	}
+}
+
+func TestLoad_MaxEntryDuration(t *testing.T) {
+	cfg, err := Load(createTempConfigFile(t, "max_entry_duration = \" 8H30M \"\n"))
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if got := cfg.MaxEntryMinutes(); got != 510 {
+		t.Errorf("MaxEntryMinutes() = %d, expected 510", got)
+	}
+	if got := DefaultConfig().MaxEntryMinutes(); got != 1440 {
+		t.Errorf("Expected a default of 1440 minutes, got %d", got)
+	}
+	if got := (Config{}).MaxEntryMinutes(); got != 1440 {
+		t.Errorf("Expected 1440 minutes when unset, got %d", got)
+	}
+
+	for _, value := range []string{"0m", "8 hours", "-1h", "999999999h"} {
+		_, err := Load(createTempConfigFile(t, "max_entry_duration = \""+value+"\"\n"))
+		if err == nil || !strings.Contains(err.Error(), "invalid max_entry_duration") {
+			t.Errorf("max_entry_duration = %q: expected an invalid max_entry_duration error, got: %v", value, err)
+		}
+	}
 }
 
 func TestLoad_ValidConfig(t *testing.T) {
@@ -229,6 +372,11 @@ func TestLoad_InvalidTimezone(t *testing.T) {
 			timezone:       "Mars/Olympus",
 			errorSubstring: "invalid timezone",
 		},
+		{
+			name:           "typo",
+			timezone:       "Europe/Olso",
+			errorSubstring: "'Europe/Olso' is not a valid IANA timezone (did you mean 'Europe/Oslo'?)",
+		},
 		{
 			name:           "random string",
 			timezone:       "not_a_timezone",
@@ -354,56 +502,63 @@ func TestValidate_ValidConfigs(t *testing.T) {
 		{
 			name: "monday lowercase",
 			config: Config{
-				WeekStartDay: "monday",
-				Timezone:     "Local",
+				WeekStartDay:  "monday",
+				Timezone:      "Local",
+				MonthStartDay: 1,
 			},
 			wantLower: "monday",
 		},
 		{
 			name: "sunday lowercase",
 			config: Config{
-				WeekStartDay: "sunday",
-				Timezone:     "Local",
+				WeekStartDay:  "sunday",
+				Timezone:      "Local",
+				MonthStartDay: 1,
 			},
 			wantLower: "sunday",
 		},
 		{
 			name: "Monday mixed case normalized to lowercase",
 			config: Config{
-				WeekStartDay: "Monday",
-				Timezone:     "Local",
+				WeekStartDay:  "Monday",
+				Timezone:      "Local",
+				MonthStartDay: 1,
 			},
 			wantLower: "monday",
 		},
 		{
 			name: "SUNDAY uppercase normalized to lowercase",
 			config: Config{
-				WeekStartDay: "SUNDAY",
-				Timezone:     "Local",
+				WeekStartDay:  "SUNDAY",
+				Timezone:      "Local",
+				MonthStartDay: 1,
 			},
 			wantLower: "sunday",
 		},
 		{
 			name: "with whitespace trimmed",
 			config: Config{
-				WeekStartDay: "  monday  ",
-				Timezone:     "Local",
+				WeekStartDay:  "  monday  ",
+				Timezone:      "Local",
+				MonthStartDay: 1,
 			},
 			wantLower: "monday",
 		},
 		{
 			name: "valid timezone",
 			config: Config{
-				WeekStartDay: "monday",
-				Timezone:     "America/New_York",
+				WeekStartDay:  "monday",
+				Timezone:      "America/New_York",
+				MonthStartDay: 1,
 			},
 			wantLower: "monday",
 		},
 		{
 			name: "empty timezone is valid",
 			config: Config{
-				WeekStartDay: "monday",
-				Timezone:     "",
+				WeekStartDay:  "monday",
+				Timezone:      "",
+				MonthStartDay: 1,
 			},
 			wantLower: "monday",
 		},
@@ -440,8 +595,9 @@ func TestValidate_InvalidWeekStartDay(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := Config{
-				WeekStartDay: tt.weekStartDay,
-				Timezone:     "Local",
+				WeekStartDay:  tt.weekStartDay,
+				Timezone:      "Local",
+				MonthStartDay: 1,
 			}
 			err := cfg.Validate()
 			if err == nil {
@@ -468,8 +624,9 @@ func TestValidate_InvalidTimezone(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := Config{
-				WeekStartDay: "monday",
-				Timezone:     tt.timezone,
+				WeekStartDay:  "monday",
+				Timezone:      tt.timezone,
+				MonthStartDay: 1,
 			}
 			err := cfg.Validate()
 			if err == nil {
@@ -654,8 +811,9 @@ func TestValidate_NormalizesWeekStartDay(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := Config{
-				WeekStartDay: tt.input,
-				Timezone:     "Local",
+				WeekStartDay:  tt.input,
+				Timezone:      "Local",
+				MonthStartDay: 1,
 			}
 
 			cfg.Normalize()
@@ -708,6 +866,32 @@ func TestGenerateSampleConfig(t *testing.T) {
 	}
 }
 
+func TestGenerateSampleConfig_DefaultsMatchDefaultConfig(t *testing.T) {
+	// Uncomment the "# key = value" line that closes each section, which
+	// documents the default of that setting; the version is always set
+	var settings []string
+	for _, line := range strings.Split(GenerateSampleConfig(), "\n") {
+		if line == "# [working_hours]" {
+			break
+		}
+		if strings.HasPrefix(line, "version = ") {
+			settings = append(settings, line)
+			continue
+		}
+		if key, _, ok := strings.Cut(strings.TrimPrefix(line, "# "), " = "); ok && !strings.HasPrefix(line, "#  ") && !strings.Contains(key, " ") {
+			settings = append(settings, strings.TrimPrefix(line, "# "))
+		}
+	}
+
+	var cfg Config
+	if _, err := toml.Decode(strings.Join(settings, "\n"), &cfg); err != nil {
+		t.Fatalf("Failed to decode the sample defaults: %v\n%s", err, strings.Join(settings, "\n"))
+	}
+	if !reflect.DeepEqual(cfg, DefaultConfig()) {
+		t.Errorf("Sample config defaults = %+v, want DefaultConfig() = %+v", cfg, DefaultConfig())
+	}
+}
+
 func TestGetConfigPath_UserConfigDirError(t *testing.T) {
 	// Save original provider and ensure cleanup
 	defer osutil.ResetProvider()
@@ -725,30 +909,54 @@ func TestGetConfigPath_UserConfigDirError(t *testing.T) {
 	}
 }
 
-func TestGetConfigPath_MkdirAllError(t *testing.T) {
-	// Save original provider
-	original := osutil.Provider
+func TestGetConfigPath_CreatesNothing(t *testing.T) {
 	defer osutil.ResetProvider()
 
 	tmpDir := t.TempDir()
 
-	// Mock MkdirAll to return an error
+	// The config is read on a read-only file system too
 	osutil.SetProvider(&mockPathProvider{
 		userConfigDirFn: func() (string, error) {
 			return tmpDir, nil
 		},
 		mkdirAllFn: func(path string, perm os.FileMode) error {
+			t.Errorf("GetConfigPath() should not create %s", path)
 			return os.ErrPermission
 		},
 	})
 
-	_, err := GetConfigPath()
-	if err == nil {
-		t.Error("GetConfigPath() should return error when MkdirAll fails")
+	path, err := GetConfigPath()
+	if err != nil {
+		t.Fatalf("GetConfigPath() returned unexpected error: %v", err)
+	}
+	if expected := filepath.Join(tmpDir, "did", ConfigFile); path != expected {
+		t.Errorf("GetConfigPath() = %q, expected %q", path, expected)
+	}
+}
+
+func TestGetConfigPath_Override(t *testing.T) {
+	defer SetConfigPath("")
+
+	if source := ConfigPathSource(); source != "" {
+		t.Fatalf("ConfigPathSource() = %q, expected the default location", source)
+	}
+
+	t.Setenv(ConfigPathEnv, "/tmp/env.toml")
+	if path, err := GetConfigPath(); err != nil || path != "/tmp/env.toml" {
+		t.Errorf("GetConfigPath() = %q, %v, expected the DID_CONFIG path", path, err)
+	}
+	if source := ConfigPathSource(); source != ConfigPathEnv {
+		t.Errorf("ConfigPathSource() = %q, expected %q", source, ConfigPathEnv)
 	}
 
-	// Reset
-	osutil.Provider = original
+	// The --config flag wins over DID_CONFIG
+	SetConfigPath("/tmp/flag.toml")
+	if path, err := GetConfigPath(); err != nil || path != "/tmp/flag.toml" {
+		t.Errorf("GetConfigPath() = %q, %v, expected the --config path", path, err)
+	}
+	if source := ConfigPathSource(); source != "--config" {
+		t.Errorf("ConfigPathSource() = %q, expected --config", source)
+	}
 }
 
 func TestLoadOrDefault_StatError(t *testing.T) {
@@ -794,3 +1002,325 @@ func (m *mockPathProvider) MkdirAll(path string, perm os.FileMode) error {
 	}
 	return nil
 }
+
+func TestValidate_DefaultProjectAndRounding(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DefaultProject = " @acme "
+	cfg.RoundMinutes = 15
+	cfg.Normalize()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected valid config, got: %v", err)
+	}
+	if cfg.DefaultProject != "acme" {
+		t.Errorf("Expected default project 'acme', got '%s'", cfg.DefaultProject)
+	}
+
+	cfg.DefaultProject = "my.project"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "default_project") {
+		t.Errorf("Expected default_project error, got: %v", err)
+	}
+
+	cfg.DefaultProject = ""
+	cfg.RoundMinutes = 90
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "round_minutes") {
+		t.Errorf("Expected round_minutes error, got: %v", err)
+	}
+
+	cfg.RoundMinutes = 0
+	cfg.RoundDisplayMinutes = 61
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "round_display_minutes") {
+		t.Errorf("Expected round_display_minutes error, got: %v", err)
+	}
+
+	cfg.RoundDisplayMinutes = 0
+	cfg.FutureMarginMinutes = -1
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "future_margin_minutes") {
+		t.Errorf("Expected future_margin_minutes error, got: %v", err)
+	}
+}
+
+func TestValidate_DefaultDuration(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DefaultDurationMinutes = 30
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected valid config, got: %v", err)
+	}
+
+	for _, minutes := range []int{-5, 24*60 + 1} {
+		cfg.DefaultDurationMinutes = minutes
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "default_duration_minutes") {
+			t.Errorf("%d minutes: expected default_duration_minutes error, got: %v", minutes, err)
+		}
+	}
+
+	// Bounded by max_entry_duration
+	cfg.DefaultDurationMinutes = 9 * 60
+	cfg.MaxEntryDuration = "8h"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "between 0 and 480") {
+		t.Errorf("Expected the max_entry_duration bound, got: %v", err)
+	}
+}
+
+func TestValidate_MyFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		myFile  string
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"file name", "alice.jsonl", false},
+		{"padded", "  alice.jsonl  ", false},
+		{"missing extension", "alice", true},
+		{"only extension", ".jsonl", true},
+		{"path", "team/alice.jsonl", true},
+		{"absolute path", "/tmp/alice.jsonl", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.MyFile = tt.myFile
+			cfg.Normalize()
+			err := cfg.Validate()
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "my_file")) {
+				t.Errorf("Expected my_file error, got: %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected valid config, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestApplyEntryDefaults(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DefaultProject = "acme"
+	cfg.RoundMinutes = 15
+
+	e := entry.Entry{Description: "work", DurationMinutes: 20}
+	cfg.ApplyEntryDefaults(&e)
+	if e.Project != "acme" || e.DurationMinutes != 30 {
+		t.Errorf("Expected project acme and 30 minutes, got %q and %d", e.Project, e.DurationMinutes)
+	}
+
+	e = entry.Entry{Description: "work", DurationMinutes: 20, Project: "client"}
+	cfg.ApplyEntryDefaults(&e)
+	if e.Project != "client" {
+		t.Errorf("Expected explicit project to be kept, got %q", e.Project)
+	}
+	if e.Source != "" {
+		t.Errorf("Expected no source without my_file, got %q", e.Source)
+	}
+
+	cfg.MyFile = "alice.jsonl"
+	e = entry.Entry{Description: "work", DurationMinutes: 20}
+	cfg.ApplyEntryDefaults(&e)
+	if e.Source != "alice.jsonl" {
+		t.Errorf("Expected source alice.jsonl, got %q", e.Source)
+	}
+}
+
+func TestApplyEntryDefaults_TimestampPrecision(t *testing.T) {
+	logged := time.Date(2024, 1, 15, 9, 41, 27, 123456789, time.UTC)
+	tests := []struct {
+		precision string
+		expected  time.Time
+	}{
+		{"", time.Date(2024, 1, 15, 9, 41, 27, 0, time.UTC)},
+		{"second", time.Date(2024, 1, 15, 9, 41, 27, 0, time.UTC)},
+		{"minute", time.Date(2024, 1, 15, 9, 41, 0, 0, time.UTC)},
+		{"exact", logged},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.TimestampPrecision = tt.precision
+		e := entry.Entry{Timestamp: logged, Description: "work", DurationMinutes: 20}
+		cfg.ApplyEntryDefaults(&e)
+		if !e.Timestamp.Equal(tt.expected) {
+			t.Errorf("timestamp_precision %q: timestamp %v, expected %v", tt.precision, e.Timestamp, tt.expected)
+		}
+	}
+}
+
+func TestValidate_TimestampPrecision(t *testing.T) {
+	for _, precision := range []string{"", "minute", "second", "exact"} {
+		cfg := DefaultConfig()
+		cfg.TimestampPrecision = precision
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("%q: unexpected error: %v", precision, err)
+		}
+	}
+
+	cfg, err := Load(createTempConfigFile(t, "timestamp_precision = \" Minute \"\n"))
+	if err != nil || cfg.TimestampPrecision != "minute" {
+		t.Errorf("Expected ' Minute ' to load as minute, got %q, %v", cfg.TimestampPrecision, err)
+	}
+	if _, err := Load(createTempConfigFile(t, "timestamp_precision = \"hour\"\n")); err == nil || !strings.Contains(err.Error(), "invalid timestamp_precision") {
+		t.Errorf("Expected an invalid timestamp_precision error, got: %v", err)
+	}
+}
+
+func TestValidate_MonthStartDay(t *testing.T) {
+	for _, day := range []int{1, 15, 28} {
+		cfg := DefaultConfig()
+		cfg.MonthStartDay = day
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("%d: unexpected error: %v", day, err)
+		}
+	}
+	for _, day := range []int{-1, 0, 29, 31} {
+		cfg := DefaultConfig()
+		cfg.MonthStartDay = day
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "invalid month_start_day") {
+			t.Errorf("%d: expected an invalid month_start_day error, got: %v", day, err)
+		}
+	}
+
+	cfg, err := Load(createTempConfigFile(t, "month_start_day = 25\n"))
+	if err != nil || cfg.MonthStartDay != 25 {
+		t.Errorf("Expected month_start_day 25 to load, got %d, %v", cfg.MonthStartDay, err)
+	}
+	if _, err := Load(createTempConfigFile(t, "month_start_day = 0\n")); err == nil {
+		t.Error("Expected month_start_day 0 to be rejected")
+	}
+}
+
+// envLookup returns a lookup function backed by a map
+func envLookup(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Timezone = "Europe/London"
+
+	err := ApplyEnv(&cfg, envLookup(map[string]string{
+		"DID_TIMEZONE":        "Asia/Tokyo",
+		"DID_WEEK_START":      "Sunday",
+		"DID_DEFAULT_PROJECT": "@acme",
+		"DID_STORE":           "/data/did/entries.jsonl",
+		"DID_ROUND":           "15",
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Timezone != "Asia/Tokyo" {
+		t.Errorf("Expected env to override timezone, got '%s'", cfg.Timezone)
+	}
+	if cfg.WeekStartDay != "sunday" {
+		t.Errorf("Expected normalized week start 'sunday', got '%s'", cfg.WeekStartDay)
+	}
+	if cfg.DefaultProject != "acme" {
+		t.Errorf("Expected default project 'acme', got '%s'", cfg.DefaultProject)
+	}
+	if cfg.StoragePath != "/data/did/entries.jsonl" {
+		t.Errorf("Expected storage path from env, got '%s'", cfg.StoragePath)
+	}
+	if cfg.RoundMinutes != 15 {
+		t.Errorf("Expected round minutes 15, got %d", cfg.RoundMinutes)
+	}
+}
+
+func TestApplyEnv_UnsetAndEmptyKeepFileValues(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Timezone = "Europe/London"
+
+	if err := ApplyEnv(&cfg, envLookup(map[string]string{"DID_TIMEZONE": ""})); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Timezone != "Europe/London" {
+		t.Errorf("Expected file timezone to be kept, got '%s'", cfg.Timezone)
+	}
+}
+
+func TestApplyEnv_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		contains string
+	}{
+		{"timezone", map[string]string{"DID_TIMEZONE": "Mars/Base"}, "DID_TIMEZONE: invalid timezone"},
+		{"week start", map[string]string{"DID_WEEK_START": "friday"}, "DID_WEEK_START: invalid week_start_day"},
+		{"default project", map[string]string{"DID_DEFAULT_PROJECT": "a.b"}, "DID_DEFAULT_PROJECT: invalid default_project"},
+		{"relative store", map[string]string{"DID_STORE": "entries.jsonl"}, "DID_STORE: invalid storage_path"},
+		{"round not a number", map[string]string{"DID_ROUND": "15m"}, "invalid DID_ROUND"},
+		{"round out of range", map[string]string{"DID_ROUND": "120"}, "DID_ROUND: invalid round_minutes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			err := ApplyEnv(&cfg, envLookup(tt.env))
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestLoadWithEnv_EnvOverridesFile(t *testing.T) {
+	path := createTempConfigFile(t, "timezone = \"Europe/London\"\nweek_start_day = \"monday\"\n")
+	t.Setenv("DID_WEEK_START", "sunday")
+
+	cfg, err := LoadWithEnv(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.WeekStartDay != "sunday" {
+		t.Errorf("Expected env week start 'sunday', got '%s'", cfg.WeekStartDay)
+	}
+	if cfg.Timezone != "Europe/London" {
+		t.Errorf("Expected file timezone 'Europe/London', got '%s'", cfg.Timezone)
+	}
+}
+
+func TestValidate_DurationKeyword(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyword string
+		want    string
+		wantErr bool
+	}{
+		{"default", "for", "for", false},
+		{"localized", "für", "für", false},
+		{"normalized", "  PENDANT ", "pendant", false},
+		{"empty", "", "", false},
+		{"two words", "for a", "", true},
+		{"project marker", "@for", "", true},
+		{"tag marker", "#for", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.DurationKeyword = tt.keyword
+			cfg.Normalize()
+			err := cfg.Validate()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "duration_keyword") {
+					t.Errorf("Expected duration_keyword error, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected valid config, got: %v", err)
+			}
+			if cfg.DurationKeyword != tt.want {
+				t.Errorf("Expected keyword %q, got %q", tt.want, cfg.DurationKeyword)
+			}
+		})
+	}
+}
+
+func TestEffectiveDurationKeyword(t *testing.T) {
+	if got := (Config{}).EffectiveDurationKeyword(); got != "for" {
+		t.Errorf("Expected 'for' when unset, got %q", got)
+	}
+	if got := (Config{DurationKeyword: "für"}).EffectiveDurationKeyword(); got != "für" {
+		t.Errorf("Expected configured keyword, got %q", got)
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// EnvOverride describes an environment variable that overrides a config setting
+type EnvOverride struct {
+	// Name is the environment variable name, e.g. DID_TIMEZONE
+	Name string
+	// Key is the config file key it overrides, e.g. timezone
+	Key string
+
+	apply func(c *Config, value string) error
+}
+
+// EnvOverrides lists the supported environment variables in documentation order.
+// They take precedence over the config file; command-line flags take precedence over both.
+var EnvOverrides = []EnvOverride{
+	{Name: "DID_TIMEZONE", Key: "timezone", apply: func(c *Config, v string) error {
+		c.Timezone = v
+		return nil
+	}},
+	{Name: "DID_WEEK_START", Key: "week_start_day", apply: func(c *Config, v string) error {
+		c.WeekStartDay = v
+		return nil
+	}},
+	{Name: "DID_DEFAULT_PROJECT", Key: "default_project", apply: func(c *Config, v string) error {
+		c.DefaultProject = v
+		return nil
+	}},
+	{Name: "DID_STORE", Key: "storage_path", apply: func(c *Config, v string) error {
+		c.StoragePath = v
+		return nil
+	}},
+	{Name: "DID_ROUND", Key: "round_minutes", apply: func(c *Config, v string) error {
+		minutes, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("'%s' is not a whole number of minutes", v)
+		}
+		c.RoundMinutes = minutes
+		return nil
+	}},
+}
+
+// ApplyEnv overrides cfg with the environment variables in EnvOverrides that
+// are set to a non-empty value, looking them up with lookup (os.LookupEnv in
+// production). Each value goes through the same normalization and validation
+// as the config file.
+func ApplyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	for _, o := range EnvOverrides {
+		value, ok := lookup(o.Name)
+		if !ok || value == "" {
+			continue
+		}
+		if err := o.apply(cfg, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", o.Name, err)
+		}
+		cfg.Normalize()
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", o.Name, err)
+		}
+	}
+	return nil
+}
+
+// LoadWithEnv loads the config like LoadOrDefault and then applies the
+// environment variable overrides from the process environment.
+func LoadWithEnv(path string) (Config, error) {
+	cfg, err := LoadOrDefault(path)
+	if err != nil {
+		return Config{}, err
+	}
+	if err := ApplyEnv(&cfg, os.LookupEnv); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/BurntSushi/toml"
+)
+
+// CurrentVersion is the version of the config file format of this build.
+// Config files without a version setting are version 0.
+const CurrentVersion = 1
+
+// migrations upgrade a loaded config from the version they are keyed by to
+// the next one. Settings missing from an older file already get their
+// DefaultConfig value when loading, so a step is only needed when the meaning
+// of an existing setting changes.
+var migrations = map[int]func(*Config){
+	// Version 0 files predate the version setting; settings added since then
+	// (duration_keyword, future_margin_minutes, ...) use their defaults.
+	0: func(*Config) {},
+}
+
+// versionLine matches the version setting in a config file
+var versionLine = regexp.MustCompile(`(?m)^version\s*=\s*\d+\s*$`)
+
+// migrate upgrades cfg, loaded from a file of cfg.Version, to CurrentVersion.
+// Configs of a newer version are left as they are.
+func (c *Config) migrate() {
+	for c.Version < CurrentVersion {
+		if step, ok := migrations[c.Version]; ok {
+			step(c)
+		}
+		c.Version++
+	}
+}
+
+// FileVersion returns the version of the config file at path, 0 when the file
+// has no version setting
+func FileVersion(path string) (int, error) {
+	var file struct {
+		Version int `toml:"version"`
+	}
+	if _, err := toml.DecodeFile(path, &file); err != nil {
+		return 0, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	return file.Version, nil
+}
+
+// Migrate updates the config file at path to CurrentVersion by setting its
+// version; the rest of the file, including comments, is kept. The file is
+// replaced atomically. Returns the version the file had and whether it was
+// rewritten. Missing files and files of the current or a newer version are
+// left unchanged.
+func Migrate(path string) (from int, migrated bool, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return CurrentVersion, false, nil
+		}
+		return 0, false, err
+	}
+
+	from, err = FileVersion(path)
+	if err != nil || from >= CurrentVersion {
+		return from, false, err
+	}
+
+	setting := "version = " + strconv.Itoa(CurrentVersion)
+	var content string
+	if versionLine.Match(data) {
+		content = versionLine.ReplaceAllString(string(data), setting)
+	} else {
+		content = "# Config file format version, updated by did\n" + setting + "\n\n" + string(data)
+	}
+
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		return from, false, err
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		_ = os.Remove(tmpFile)
+		return from, false, err
+	}
+	return from, true, nil
+}
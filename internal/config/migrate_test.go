@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const v0Config = `# my settings
+week_start_day = "sunday"
+`
+
+func TestLoad_VersionlessConfigGetsDefaults(t *testing.T) {
+	cfg, err := Load(createTempConfigFile(t, v0Config))
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+
+	defaults := DefaultConfig()
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Version = %d, expected %d", cfg.Version, CurrentVersion)
+	}
+	if cfg.WeekStartDay != "sunday" {
+		t.Errorf("WeekStartDay = %q, expected 'sunday'", cfg.WeekStartDay)
+	}
+	if cfg.DurationKeyword != defaults.DurationKeyword {
+		t.Errorf("DurationKeyword = %q, expected default %q", cfg.DurationKeyword, defaults.DurationKeyword)
+	}
+	if cfg.FutureMarginMinutes != defaults.FutureMarginMinutes {
+		t.Errorf("FutureMarginMinutes = %d, expected default %d", cfg.FutureMarginMinutes, defaults.FutureMarginMinutes)
+	}
+	if cfg.Timezone != defaults.Timezone {
+		t.Errorf("Timezone = %q, expected default %q", cfg.Timezone, defaults.Timezone)
+	}
+}
+
+func TestLoad_FutureVersion(t *testing.T) {
+	cfg, err := Load(createTempConfigFile(t, "version = 99\nsome_new_setting = true\n"))
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.Version != 99 {
+		t.Errorf("Version = %d, expected 99", cfg.Version)
+	}
+}
+
+func TestLoad_NegativeVersion(t *testing.T) {
+	if _, err := Load(createTempConfigFile(t, "version = -1\n")); err == nil {
+		t.Error("Expected an error for a negative version")
+	}
+}
+
+func TestMigrate_VersionlessFile(t *testing.T) {
+	path := createTempConfigFile(t, v0Config)
+
+	from, migrated, err := Migrate(path)
+	if err != nil {
+		t.Fatalf("Migrate() returned unexpected error: %v", err)
+	}
+	if from != 0 || !migrated {
+		t.Errorf("Migrate() = (%d, %v), expected (0, true)", from, migrated)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if !strings.Contains(content, "version = 1\n") {
+		t.Errorf("Expected the version to be set, got:\n%s", content)
+	}
+	if !strings.HasSuffix(content, v0Config) {
+		t.Errorf("Expected the original settings and comments to be kept, got:\n%s", content)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Expected the temp file to be renamed")
+	}
+
+	if version, err := FileVersion(path); err != nil || version != CurrentVersion {
+		t.Errorf("FileVersion() = (%d, %v), expected (%d, nil)", version, err, CurrentVersion)
+	}
+
+	// Migrating again leaves the file alone
+	from, migrated, err = Migrate(path)
+	if err != nil || from != CurrentVersion || migrated {
+		t.Errorf("second Migrate() = (%d, %v, %v), expected (%d, false, nil)", from, migrated, err, CurrentVersion)
+	}
+}
+
+func TestMigrate_ReplacesVersionLine(t *testing.T) {
+	path := createTempConfigFile(t, "version = 0\ntimezone = \"UTC\"\n")
+
+	if _, migrated, err := Migrate(path); err != nil || !migrated {
+		t.Fatalf("Migrate() = (%v, %v), expected a migration", migrated, err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "version = 1\ntimezone = \"UTC\"\n" {
+		t.Errorf("Unexpected migrated content:\n%s", data)
+	}
+}
+
+func TestMigrate_FutureVersionUnchanged(t *testing.T) {
+	content := "version = 99\n"
+	path := createTempConfigFile(t, content)
+
+	from, migrated, err := Migrate(path)
+	if err != nil || from != 99 || migrated {
+		t.Errorf("Migrate() = (%d, %v, %v), expected (99, false, nil)", from, migrated, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("Expected a newer config file to be left unchanged, got:\n%s", data)
+	}
+}
+
+func TestMigrate_MissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+
+	from, migrated, err := Migrate(path)
+	if err != nil || from != CurrentVersion || migrated {
+		t.Errorf("Migrate() = (%d, %v, %v), expected (%d, false, nil)", from, migrated, err, CurrentVersion)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected no config file to be created")
+	}
+}
+
+func TestMigrate_InvalidTOML(t *testing.T) {
+	if _, _, err := Migrate(createTempConfigFile(t, "version = [")); err == nil {
+		t.Error("Expected an error for invalid TOML")
+	}
+}
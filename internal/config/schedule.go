@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WorkingHours maps weekdays ("mon" to "sun") to the number of hours expected
+// to be logged on that day. Days that are not listed expect no hours.
+type WorkingHours map[string]float64
+
+// scheduleDays are the working_hours keys indexed by time.Weekday
+var scheduleDays = [7]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// scheduleDayAliases maps full weekday names to their working_hours keys
+var scheduleDayAliases = map[string]string{
+	"sunday":    "sun",
+	"monday":    "mon",
+	"tuesday":   "tue",
+	"wednesday": "wed",
+	"thursday":  "thu",
+	"friday":    "fri",
+	"saturday":  "sat",
+}
+
+// normalize lowercases the day keys and maps full weekday names to their
+// short form. Unknown keys are kept so that Validate can report them.
+func (w WorkingHours) normalize() WorkingHours {
+	if len(w) == 0 {
+		return w
+	}
+	normalized := make(WorkingHours, len(w))
+	for day, hours := range w {
+		key := strings.ToLower(strings.TrimSpace(day))
+		if alias, ok := scheduleDayAliases[key]; ok {
+			key = alias
+		}
+		normalized[key] = hours
+	}
+	return normalized
+}
+
+// Validate checks that every key is a weekday and every value is between 0 and 24 hours
+func (w WorkingHours) Validate() error {
+	days := make([]string, 0, len(w))
+	for day := range w {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	for _, day := range days {
+		if !isScheduleDay(day) {
+			return fmt.Errorf("invalid working_hours: unknown day '%s' (use mon, tue, wed, thu, fri, sat or sun)", day)
+		}
+		hours := w[day]
+		if math.IsNaN(hours) || hours < 0 || hours > 24 {
+			return fmt.Errorf("invalid working_hours: %s must be between 0 and 24 hours, got %g", day, hours)
+		}
+	}
+	return nil
+}
+
+// ExpectedMinutes returns the number of minutes expected to be logged on the given weekday
+func (w WorkingHours) ExpectedMinutes(day time.Weekday) int {
+	return int(math.Round(w[scheduleDays[day]] * 60))
+}
+
+// isScheduleDay reports whether day is a valid working_hours key
+func isScheduleDay(day string) bool {
+	for _, d := range scheduleDays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad_WorkingHours(t *testing.T) {
+	tmpFile := createTempConfigFile(t, `week_start_day = "monday"
+
+[working_hours]
+mon = 8
+tue = 8
+Wednesday = 7.5
+thu = 8
+fri = 6`)
+
+	cfg, err := Load(tmpFile)
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+
+	expected := map[time.Weekday]int{
+		time.Monday:    480,
+		time.Tuesday:   480,
+		time.Wednesday: 450,
+		time.Thursday:  480,
+		time.Friday:    360,
+		time.Saturday:  0,
+		time.Sunday:    0,
+	}
+	for day, minutes := range expected {
+		if got := cfg.WorkingHours.ExpectedMinutes(day); got != minutes {
+			t.Errorf("ExpectedMinutes(%s) = %d, expected %d", day, got, minutes)
+		}
+	}
+}
+
+func TestLoad_WithoutWorkingHours(t *testing.T) {
+	tmpFile := createTempConfigFile(t, `week_start_day = "monday"`)
+
+	cfg, err := Load(tmpFile)
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if len(cfg.WorkingHours) != 0 {
+		t.Errorf("Expected no working hours, got %v", cfg.WorkingHours)
+	}
+	if got := cfg.WorkingHours.ExpectedMinutes(time.Monday); got != 0 {
+		t.Errorf("Expected 0 minutes without a schedule, got %d", got)
+	}
+}
+
+func TestLoad_InvalidWorkingHours(t *testing.T) {
+	tests := []struct {
+		name          string
+		configContent string
+		wantErr       string
+	}{
+		{
+			name:          "unknown day",
+			configContent: "[working_hours]\nmon = 8\nfunday = 4",
+			wantErr:       "unknown day 'funday'",
+		},
+		{
+			name:          "negative hours",
+			configContent: "[working_hours]\ntue = -1",
+			wantErr:       "tue must be between 0 and 24 hours",
+		},
+		{
+			name:          "more than a day",
+			configContent: "[working_hours]\nfri = 25",
+			wantErr:       "fri must be between 0 and 24 hours",
+		},
+		{
+			name:          "not a number",
+			configContent: "[working_hours]\nmon = \"eight\"",
+			wantErr:       "failed to parse config file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile := createTempConfigFile(t, tt.configContent)
+
+			_, err := Load(tmpFile)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWorkingHours_Normalize(t *testing.T) {
+	w := WorkingHours{" MON ": 8, "Friday": 6, "Sat": 2}.normalize()
+
+	expected := WorkingHours{"mon": 8, "fri": 6, "sat": 2}
+	if len(w) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, w)
+	}
+	for day, hours := range expected {
+		if w[day] != hours {
+			t.Errorf("Expected %s = %g, got %g", day, hours, w[day])
+		}
+	}
+}
+
+func TestWorkingHours_Validate(t *testing.T) {
+	valid := []WorkingHours{
+		nil,
+		{},
+		{"mon": 0, "sun": 24},
+		{"wed": 7.25},
+	}
+	for _, w := range valid {
+		if err := w.Validate(); err != nil {
+			t.Errorf("Validate(%v) returned unexpected error: %v", w, err)
+		}
+	}
+
+	invalid := []WorkingHours{
+		{"monday": 8},
+		{"mon": -0.5},
+		{"mon": 24.5},
+		{"mon": math.NaN()},
+	}
+	for _, w := range invalid {
+		if err := w.Validate(); err == nil {
+			t.Errorf("Validate(%v) expected error, got nil", w)
+		}
+	}
+}
+
+func TestWorkingHours_ExpectedMinutesRoundsToMinute(t *testing.T) {
+	w := WorkingHours{"thu": 7.9999}
+	if got := w.ExpectedMinutes(time.Thursday); got != 480 {
+		t.Errorf("ExpectedMinutes = %d, expected 480", got)
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// Workspace is the project and tags given to entries logged from a directory
+type Workspace struct {
+	Project string   `toml:"project"`
+	Tags    []string `toml:"tags"`
+}
+
+// Workspaces maps directories (absolute, or starting with ~/) to the project
+// and tags of entries logged in them or any of their subdirectories
+type Workspaces map[string]Workspace
+
+// normalize trims the directories and strips the @ and # prefixes of the
+// project and tags
+func (w Workspaces) normalize() Workspaces {
+	if len(w) == 0 {
+		return w
+	}
+	normalized := make(Workspaces, len(w))
+	for dir, ws := range w {
+		ws.Project = strings.TrimPrefix(strings.TrimSpace(ws.Project), "@")
+		tags := make([]string, 0, len(ws.Tags))
+		for _, tag := range ws.Tags {
+			tags = append(tags, strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		}
+		ws.Tags = tags
+		normalized[strings.TrimSpace(dir)] = ws
+	}
+	return normalized
+}
+
+// Validate checks that every directory is absolute or starts with ~/ and that
+// every workspace sets a valid project or tags
+func (w Workspaces) Validate() error {
+	dirs := make([]string, 0, len(w))
+	for dir := range w {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		if dir != "~" && !strings.HasPrefix(dir, "~/") && !filepath.IsAbs(dir) {
+			return fmt.Errorf("invalid workspaces: '%s' must be an absolute path or start with ~/", dir)
+		}
+		ws := w[dir]
+		if ws.Project == "" && len(ws.Tags) == 0 {
+			return fmt.Errorf("invalid workspaces: '%s' sets neither a project nor tags", dir)
+		}
+		if ws.Project != "" && !entry.IsValidName(ws.Project) {
+			return fmt.Errorf("invalid workspaces: project '%s' of '%s' may only contain letters, digits, hyphens, underscores, and single spaces between words", ws.Project, dir)
+		}
+		for _, tag := range ws.Tags {
+			if !entry.IsValidName(tag) {
+				return fmt.Errorf("invalid workspaces: tag '%s' of '%s' may only contain letters, digits, hyphens, underscores, and single spaces between words", tag, dir)
+			}
+		}
+	}
+	return nil
+}
+
+// Match returns the configured directory containing dir, and its workspace.
+// A leading ~ is expanded to home; when several directories contain dir, the
+// longest (most specific) one wins.
+func (w Workspaces) Match(dir, home string) (string, Workspace, bool) {
+	dir = filepath.Clean(dir)
+	best, bestLen := "", -1
+	for key := range w {
+		root := key
+		if root == "~" || strings.HasPrefix(root, "~/") {
+			if home == "" {
+				continue
+			}
+			root = filepath.Join(home, root[1:])
+		}
+		root = filepath.Clean(root)
+
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if len(root) > bestLen || (len(root) == bestLen && key < best) {
+			best, bestLen = key, len(root)
+		}
+	}
+	if bestLen < 0 {
+		return "", Workspace{}, false
+	}
+	return best, w[best], true
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoad_Workspaces(t *testing.T) {
+	tmpFile := createTempConfigFile(t, `week_start_day = "monday"
+
+[workspaces."~/code/acme-app"]
+project = "@acme"
+tags = ["#dev"]
+
+[workspaces."/srv/ops"]
+tags = ["ops"]`)
+
+	cfg, err := Load(tmpFile)
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+
+	acme := cfg.Workspaces["~/code/acme-app"]
+	if acme.Project != "acme" || len(acme.Tags) != 1 || acme.Tags[0] != "dev" {
+		t.Errorf("Expected project acme and tag dev without prefixes, got %+v", acme)
+	}
+	if ops := cfg.Workspaces["/srv/ops"]; ops.Project != "" || len(ops.Tags) != 1 {
+		t.Errorf("Expected only the ops tag, got %+v", ops)
+	}
+}
+
+func TestLoad_InvalidWorkspaces(t *testing.T) {
+	tests := []struct {
+		name          string
+		configContent string
+		wantErr       string
+	}{
+		{"relative path", "[workspaces.\"code/acme\"]\nproject = \"acme\"", "must be an absolute path"},
+		{"empty workspace", "[workspaces.\"/srv/acme\"]", "neither a project nor tags"},
+		{"invalid project", "[workspaces.\"/srv/acme\"]\nproject = \"acme.app\"", "project 'acme.app'"},
+		{"invalid tag", "[workspaces.\"/srv/acme\"]\ntags = [\"a/b\"]", "tag 'a/b'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(createTempConfigFile(t, tt.configContent))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, expected it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorkspaces_Match(t *testing.T) {
+	w := Workspaces{
+		"~/code":          {Tags: []string{"dev"}},
+		"~/code/acme-app": {Project: "acme"},
+		"/srv/ops":        {Project: "ops"},
+	}
+
+	tests := []struct {
+		dir      string
+		home     string
+		expected string
+	}{
+		{"/home/ann/code/acme-app", "/home/ann", "~/code/acme-app"},
+		{"/home/ann/code/acme-app/internal/api", "/home/ann", "~/code/acme-app"},
+		{"/home/ann/code/other", "/home/ann", "~/code"},
+		// A common prefix is not enough, the directory must be inside
+		{"/home/ann/code/acme-application", "/home/ann", "~/code"},
+		{"/srv/ops/", "/home/ann", "/srv/ops"},
+		{"/srv/opsx", "/home/ann", ""},
+		{"/home/ann", "/home/ann", ""},
+		// Without a home directory only absolute paths match
+		{"/home/ann/code/acme-app", "", ""},
+	}
+
+	for _, tt := range tests {
+		dir, ws, ok := w.Match(tt.dir, tt.home)
+		if dir != tt.expected || ok != (tt.expected != "") {
+			t.Errorf("Match(%q, %q) = %q, %v, expected %q", tt.dir, tt.home, dir, ok, tt.expected)
+			continue
+		}
+		if ok && ws.Project != w[dir].Project {
+			t.Errorf("Match(%q, %q) returned workspace %+v, expected %+v", tt.dir, tt.home, ws, w[dir])
+		}
+	}
+}
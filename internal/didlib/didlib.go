@@ -0,0 +1,321 @@
+// Package didlib is the programmatic API to did's time entries, for Go
+// programs that embed did data (e.g. a dashboard) instead of running the CLI.
+//
+// A Store reads and writes the same storage file or shared storage directory
+// as the did commands, which are built on this package, so listings, entry
+// indices and totals match what 'did' prints:
+//
+//	store, err := didlib.Open("") // default location, e.g. ~/.local/share/did/entries.jsonl
+//	if err != nil {
+//		return err
+//	}
+//
+//	// Entries of the current week for project acme, in chronological order
+//	c := query.Criteria{Period: query.Week(time.Now(), "monday", false), Project: "acme"}
+//	result, err := store.ListEntries(c)
+//	for _, e := range result.Entries {
+//		fmt.Printf("[%d] %s (%dm)\n", e.Index, e.Description, e.DurationMinutes)
+//	}
+//
+//	// Time logged per project over all entries
+//	totals, err := store.Totals(query.Criteria{})
+//	for _, p := range totals.Projects {
+//		fmt.Printf("%s: %dm\n", p.Project, p.TotalMinutes)
+//	}
+package didlib
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/query"
+	"github.com/xolan/did/internal/stats"
+	"github.com/xolan/did/internal/storage"
+)
+
+// ErrNoEntries is returned by Entry and Update when the store has no active entries
+var ErrNoEntries = errors.New("no entries found")
+
+// IndexError is returned by Entry and Update for an index outside 1-Count
+type IndexError struct {
+	Index int
+	Count int // number of active entries
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("index %d is out of range (valid range: 1-%d)", e.Index, e.Count)
+}
+
+// Options controls how a Store writes entries
+type Options struct {
+	// DurableWrites flushes each appended entry to disk (fsync), see config durable_writes
+	DurableWrites bool
+}
+
+// Store gives access to the entries in a storage file or shared storage directory
+type Store struct {
+	path string
+	opts Options
+}
+
+// Open returns the store at path, a storage file or a shared storage
+// directory. An empty path opens the default storage file in the data
+// directory, or the one left at the legacy location in the config directory
+// until 'did migrate --data-dir' moves it (see storage.GetStoragePath).
+// Nothing is created: a storage file that doesn't exist yet reads as empty
+// and is created by the first Append, whose directory must exist (see
+// storage.EnsureStorageDir).
+func Open(path string) (*Store, error) {
+	return OpenWithOptions(path, Options{})
+}
+
+// OpenWithOptions is like Open, with options for writing entries
+func OpenWithOptions(path string, opts Options) (*Store, error) {
+	if path == "" {
+		defaultPath, err := storage.GetStoragePath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+	return &Store{path: path, opts: opts}, nil
+}
+
+// Path returns the storage file or directory of the store
+func (s *Store) Path() string {
+	return s.path
+}
+
+// IndexedEntry is an active entry with its 1-based index, as shown by 'did'
+// and accepted by Entry, Update and the edit and delete commands. Indices
+// count active (not soft-deleted) entries in storage order.
+type IndexedEntry struct {
+	entry.Entry
+	Index int
+}
+
+// ListResult contains the entries returned by ListEntries
+type ListResult struct {
+	// Entries are the matching active entries, sorted by timestamp
+	Entries []IndexedEntry
+	// Count is the number of active entries in the store, i.e. the highest index
+	Count int
+	// Warnings describe corrupted lines that were skipped
+	Warnings []storage.ParseWarning
+	// Suspect describes the matching entries with a zero or negative duration,
+	// which are left out of Entries but keep their index
+	Suspect []storage.ParseWarning
+}
+
+// ListEntries returns the active entries matching the criteria in
+// chronological order. Criteria without a period match entries of all dates.
+// Entries with a zero or negative duration are left out (see ListResult.Suspect).
+func (s *Store) ListEntries(c query.Criteria) (ListResult, error) {
+	active, read, err := s.readActive()
+	if err != nil {
+		return ListResult{}, err
+	}
+
+	// read.Suspect lists the active entries with an invalid duration in storage order
+	result := ListResult{Entries: []IndexedEntry{}, Count: len(active), Warnings: read.Warnings}
+	suspect := 0
+	for _, ie := range active {
+		switch {
+		case !ie.HasValidDuration():
+			if c.Matches(ie.Entry) && suspect < len(read.Suspect) {
+				result.Suspect = append(result.Suspect, read.Suspect[suspect])
+			}
+			suspect++
+		case c.Matches(ie.Entry):
+			result.Entries = append(result.Entries, ie)
+		}
+	}
+	entry.SortByTimestamp(result.Entries, func(ie IndexedEntry) time.Time { return ie.Timestamp }, false)
+	return result, nil
+}
+
+// ExplainedEntry is an active entry with the decisions that include it in or
+// exclude it from a listing
+type ExplainedEntry struct {
+	IndexedEntry
+	// Decisions are the "duration" check of ListEntries followed by those of
+	// query.Criteria.Explain
+	Decisions []query.Decision
+}
+
+// Matched reports whether every decision matched, i.e. whether ListEntries
+// returns the entry
+func (e ExplainedEntry) Matched() bool {
+	for _, d := range e.Decisions {
+		if !d.Matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Explain returns every active entry in chronological order with the
+// decisions of ListEntries and the criteria on it, matching or not
+func (s *Store) Explain(c query.Criteria) ([]ExplainedEntry, error) {
+	active, _, err := s.readActive()
+	if err != nil {
+		return nil, err
+	}
+
+	explained := make([]ExplainedEntry, 0, len(active))
+	for _, ie := range active {
+		duration := query.Decision{Criterion: "duration", Matched: ie.HasValidDuration()}
+		if duration.Matched {
+			duration.Reason = fmt.Sprintf("duration %dm is positive", ie.DurationMinutes)
+		} else {
+			duration.Reason = fmt.Sprintf("duration %dm is not positive", ie.DurationMinutes)
+		}
+		decisions := append([]query.Decision{duration}, c.Explain(ie.Entry)...)
+		explained = append(explained, ExplainedEntry{IndexedEntry: ie, Decisions: decisions})
+	}
+	entry.SortByTimestamp(explained, func(e ExplainedEntry) time.Time { return e.Timestamp }, false)
+	return explained, nil
+}
+
+// Entry returns the active entry with the given 1-based index
+func (s *Store) Entry(index int) (entry.Entry, error) {
+	active, _, err := s.readActive()
+	if err != nil {
+		return entry.Entry{}, err
+	}
+	if err := checkIndex(index, len(active)); err != nil {
+		return entry.Entry{}, err
+	}
+	return active[index-1].Entry, nil
+}
+
+// Append adds an entry to the store. The entry is stored as given; callers
+// apply config defaults (config.ApplyEntryDefaults) themselves.
+func (s *Store) Append(e entry.Entry) error {
+	return storage.AppendEntryWithOptions(s.path, e, storage.AppendOptions{Sync: s.opts.DurableWrites})
+}
+
+// AppendAll adds several entries to the store in a single write, so a failure
+// leaves none of them stored (see storage.AppendEntriesWithOptions). Returns
+// the number of entries written.
+func (s *Store) AppendAll(entries []entry.Entry) (int, error) {
+	return storage.AppendEntriesWithOptions(s.path, entries, storage.AppendOptions{Sync: s.opts.DurableWrites})
+}
+
+// Update replaces the active entry with the given 1-based index. In a shared
+// storage directory the entry stays in the file it was read from.
+func (s *Store) Update(index int, e entry.Entry) error {
+	storageIndex, err := s.storageIndex(index)
+	if err != nil {
+		return err
+	}
+	return storage.UpdateEntry(s.path, storageIndex, e)
+}
+
+// storageIndex maps the 1-based index of an active entry to its 0-based index
+// among all stored entries, including soft-deleted ones
+func (s *Store) storageIndex(index int) (int, error) {
+	result, err := storage.ReadEntriesWithWarnings(s.path)
+	if err != nil {
+		return 0, err
+	}
+
+	var storageIndices []int
+	for i, stored := range result.Entries {
+		if stored.DeletedAt == nil {
+			storageIndices = append(storageIndices, i)
+		}
+	}
+	if err := checkIndex(index, len(storageIndices)); err != nil {
+		return 0, err
+	}
+	return storageIndices[index-1], nil
+}
+
+// Revision returns the current revision of the storage, to be taken before
+// reading an entry that is updated with UpdateIfUnchanged
+func (s *Store) Revision() (storage.Revision, error) {
+	return storage.StorageRevision(s.path)
+}
+
+// UpdateIfUnchanged replaces the active entry with the given 1-based index
+// like Update, but only while the storage is still at rev. Returns
+// storage.ErrStorageChanged when it was written to since.
+func (s *Store) UpdateIfUnchanged(index int, e entry.Entry, rev storage.Revision) error {
+	storageIndex, err := s.storageIndex(index)
+	if err != nil {
+		return err
+	}
+	return storage.UpdateEntryIfUnchanged(s.path, storageIndex, e, rev)
+}
+
+// Totals summarizes the active entries matching a set of criteria
+type Totals struct {
+	Entries int
+	Minutes int
+	// Projects and Tags break the time down like 'did projects' and 'did tags',
+	// most time first. Entries without a project are grouped as "(no project)",
+	// entries without tags as "(no tags)".
+	Projects []stats.ProjectBreakdown
+	Tags     []stats.TagBreakdown
+	// Warnings describe corrupted lines that were skipped
+	Warnings []storage.ParseWarning
+	// Suspect describes the matching entries with a zero or negative duration, which are not counted
+	Suspect []storage.ParseWarning
+}
+
+// Totals returns the time logged in the active entries matching the criteria
+func (s *Store) Totals(c query.Criteria) (Totals, error) {
+	result, err := s.ListEntries(c)
+	if err != nil {
+		return Totals{}, err
+	}
+
+	entries := make([]entry.Entry, len(result.Entries))
+	totals := Totals{Entries: len(entries), Warnings: result.Warnings, Suspect: result.Suspect}
+	for i, ie := range result.Entries {
+		entries[i] = ie.Entry
+		totals.Minutes += ie.DurationMinutes
+	}
+
+	// The entries already match the criteria, so the breakdowns cover their whole span
+	start, end := allTime()
+	totals.Projects = stats.CalculateProjectBreakdown(entries, start, end)
+	totals.Tags = stats.CalculateTagBreakdown(entries, start, end)
+	return totals, nil
+}
+
+// allTime returns a range that includes every entry
+func allTime() (time.Time, time.Time) {
+	return time.Time{}, time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+}
+
+// readActive reads the active entries of the store with their indices, and
+// the whole read result for its warnings
+func (s *Store) readActive() ([]IndexedEntry, storage.ReadResult, error) {
+	result, err := storage.ReadEntriesWithWarnings(s.path)
+	if err != nil {
+		return nil, result, err
+	}
+
+	var active []IndexedEntry
+	for _, e := range result.Entries {
+		if e.DeletedAt == nil {
+			active = append(active, IndexedEntry{Entry: e, Index: len(active) + 1})
+		}
+	}
+	return active, result, nil
+}
+
+// checkIndex returns an error unless index is a 1-based index of count entries
+func checkIndex(index, count int) error {
+	if count == 0 {
+		return ErrNoEntries
+	}
+	if index < 1 || index > count {
+		return &IndexError{Index: index, Count: count}
+	}
+	return nil
+}
@@ -0,0 +1,361 @@
+package didlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/query"
+	"github.com/xolan/did/internal/storage"
+)
+
+// at returns a time on Jan 15, 2024 at the given hour
+func at(hour int) time.Time {
+	return time.Date(2024, 1, 15, hour, 0, 0, 0, time.UTC)
+}
+
+// openTestStore returns a store in a temp directory holding the given entries
+func openTestStore(t *testing.T, entries ...entry.Entry) *Store {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "entries.jsonl"))
+	if err != nil {
+		t.Fatalf("Open() returned unexpected error: %v", err)
+	}
+	for _, e := range entries {
+		if err := store.Append(e); err != nil {
+			t.Fatalf("Append() returned unexpected error: %v", err)
+		}
+	}
+	return store
+}
+
+func testEntries() []entry.Entry {
+	deletedAt := at(18)
+	return []entry.Entry{
+		{Timestamp: at(11), Description: "review", DurationMinutes: 30, Project: "acme", Tags: []string{"review"}},
+		{Timestamp: at(9), Description: "standup", DurationMinutes: 15},
+		{Timestamp: at(10), Description: "removed", DurationMinutes: 60, Project: "acme", DeletedAt: &deletedAt},
+		{Timestamp: at(14), Description: "fix login", DurationMinutes: 90, Project: "acme", Tags: []string{"bugfix"}},
+	}
+}
+
+func TestOpen_DefaultPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	store, err := Open("")
+	if err != nil {
+		t.Fatalf("Open() returned unexpected error: %v", err)
+	}
+	want, err := storage.GetStoragePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.Path() != want {
+		t.Errorf("Path() = %q, expected %q", store.Path(), want)
+	}
+}
+
+func TestListEntries(t *testing.T) {
+	store := openTestStore(t, testEntries()...)
+
+	result, err := store.ListEntries(query.Criteria{})
+	if err != nil {
+		t.Fatalf("ListEntries() returned unexpected error: %v", err)
+	}
+	if result.Count != 3 {
+		t.Errorf("Count = %d, expected 3 active entries", result.Count)
+	}
+
+	// Sorted by timestamp, indexed by storage order without the deleted entry
+	want := []struct {
+		desc  string
+		index int
+	}{{"standup", 2}, {"review", 1}, {"fix login", 3}}
+	if len(result.Entries) != len(want) {
+		t.Fatalf("Expected %d entries, got %d", len(want), len(result.Entries))
+	}
+	for i, w := range want {
+		if got := result.Entries[i]; got.Description != w.desc || got.Index != w.index {
+			t.Errorf("Entries[%d] = [%d] %s, expected [%d] %s", i, got.Index, got.Description, w.index, w.desc)
+		}
+	}
+}
+
+func TestListEntries_Criteria(t *testing.T) {
+	store := openTestStore(t, testEntries()...)
+
+	tests := []struct {
+		name     string
+		criteria query.Criteria
+		want     []string
+	}{
+		{"project", query.Criteria{Project: "acme"}, []string{"review", "fix login"}},
+		{"tag", query.Criteria{Tags: []string{"bugfix"}}, []string{"fix login"}},
+		{"period", query.Criteria{Period: query.Period{Name: "morning", Start: at(8), End: at(12)}}, []string{"standup", "review"}},
+		{"no match", query.Criteria{Project: "other"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := store.ListEntries(tt.criteria)
+			if err != nil {
+				t.Fatalf("ListEntries() returned unexpected error: %v", err)
+			}
+			if len(result.Entries) != len(tt.want) {
+				t.Fatalf("Expected %d entries, got %d", len(tt.want), len(result.Entries))
+			}
+			for i, desc := range tt.want {
+				if result.Entries[i].Description != desc {
+					t.Errorf("Entries[%d] = %q, expected %q", i, result.Entries[i].Description, desc)
+				}
+			}
+		})
+	}
+}
+
+func TestListEntries_Warnings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.jsonl")
+	content := `{"timestamp":"2024-01-15T09:00:00Z","description":"standup","duration_minutes":15}
+not json
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store, _ := Open(path)
+
+	result, err := store.ListEntries(query.Criteria{})
+	if err != nil {
+		t.Fatalf("ListEntries() returned unexpected error: %v", err)
+	}
+	if len(result.Entries) != 1 || len(result.Warnings) != 1 || result.Warnings[0].LineNumber != 2 {
+		t.Errorf("Expected 1 entry and a warning for line 2, got %+v", result)
+	}
+}
+
+func TestListEntries_Suspect(t *testing.T) {
+	store := openTestStore(t,
+		entry.Entry{Timestamp: at(9), Description: "standup", DurationMinutes: 15},
+		entry.Entry{Timestamp: at(10), Description: "broken", DurationMinutes: 0},
+		entry.Entry{Timestamp: at(11), Description: "review", DurationMinutes: 30},
+	)
+
+	result, err := store.ListEntries(query.Criteria{})
+	if err != nil {
+		t.Fatalf("ListEntries() returned unexpected error: %v", err)
+	}
+	if len(result.Entries) != 2 || result.Entries[1].Description != "review" || result.Entries[1].Index != 3 {
+		t.Errorf("Expected standup and review [3] without the zero-duration entry, got %+v", result.Entries)
+	}
+	if len(result.Suspect) != 1 || result.Suspect[0].LineNumber != 2 {
+		t.Errorf("Suspect = %+v, expected line 2", result.Suspect)
+	}
+
+	// Only the suspect entries matching the criteria are reported
+	result, err = store.ListEntries(query.Criteria{Period: query.Period{Name: "late", Label: "late", Start: at(11), End: at(12)}})
+	if err != nil {
+		t.Fatalf("ListEntries() returned unexpected error: %v", err)
+	}
+	if len(result.Entries) != 1 || len(result.Suspect) != 0 {
+		t.Errorf("Expected review without suspect entries, got %+v", result)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	store := openTestStore(t, append(testEntries(),
+		entry.Entry{Timestamp: at(12), Description: "broken", DurationMinutes: 0, Project: "acme"})...)
+	c := query.Criteria{Project: "acme", Period: query.Period{Name: "morning", Label: "morning", Start: at(8), End: at(12)}}
+
+	explained, err := store.Explain(c)
+	if err != nil {
+		t.Fatalf("Explain() returned unexpected error: %v", err)
+	}
+	listed, _ := store.ListEntries(c)
+	want := map[string]bool{"standup": false, "review": true, "broken": false, "fix login": false}
+	if len(explained) != len(want) {
+		t.Fatalf("Expected %d explained entries, got %d", len(want), len(explained))
+	}
+	for _, e := range explained {
+		if e.Matched() != want[e.Description] {
+			t.Errorf("%s: Matched() = %t, expected %t (%+v)", e.Description, e.Matched(), want[e.Description], e.Decisions)
+		}
+		if len(e.Decisions) != 3 || e.Decisions[0].Criterion != "duration" {
+			t.Errorf("%s: decisions = %+v, expected duration, date and project", e.Description, e.Decisions)
+		}
+	}
+	if explained[2].Description != "broken" || explained[2].Decisions[0].Matched || explained[2].Index != 4 {
+		t.Errorf("Expected the zero-duration entry [4] excluded by its duration, got %+v", explained[2])
+	}
+	if len(listed.Entries) != 1 || listed.Entries[0].Description != "review" {
+		t.Errorf("ListEntries() = %+v, expected only the entry Explain matched", listed.Entries)
+	}
+}
+
+func TestListEntries_MissingFile(t *testing.T) {
+	store := openTestStore(t)
+
+	result, err := store.ListEntries(query.Criteria{})
+	if err != nil {
+		t.Fatalf("ListEntries() returned unexpected error: %v", err)
+	}
+	if len(result.Entries) != 0 || result.Count != 0 {
+		t.Errorf("Expected no entries, got %+v", result)
+	}
+}
+
+func TestEntry(t *testing.T) {
+	store := openTestStore(t, testEntries()...)
+
+	e, err := store.Entry(3)
+	if err != nil {
+		t.Fatalf("Entry() returned unexpected error: %v", err)
+	}
+	if e.Description != "fix login" {
+		t.Errorf("Entry(3) = %q, expected 'fix login' (the deleted entry has no index)", e.Description)
+	}
+}
+
+func TestEntry_IndexErrors(t *testing.T) {
+	store := openTestStore(t, testEntries()...)
+
+	for _, index := range []int{0, 4, -1} {
+		_, err := store.Entry(index)
+		var indexErr *IndexError
+		if !errors.As(err, &indexErr) || indexErr.Index != index || indexErr.Count != 3 {
+			t.Errorf("Entry(%d) error = %v, expected an IndexError with 3 entries", index, err)
+		}
+	}
+
+	if _, err := openTestStore(t).Entry(1); !errors.Is(err, ErrNoEntries) {
+		t.Errorf("Entry() on an empty store error = %v, expected ErrNoEntries", err)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	store := openTestStore(t, testEntries()...)
+
+	e, _ := store.Entry(3)
+	e.DurationMinutes = 120
+	if err := store.Update(3, e); err != nil {
+		t.Fatalf("Update() returned unexpected error: %v", err)
+	}
+
+	// The deleted entry before it is kept and the update lands on the right line
+	all, err := storage.ReadEntries(store.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 || all[2].DeletedAt == nil || all[3].DurationMinutes != 120 {
+		t.Errorf("Unexpected entries after update: %+v", all)
+	}
+
+	var indexErr *IndexError
+	if err := store.Update(4, e); !errors.As(err, &indexErr) {
+		t.Errorf("Update(4) error = %v, expected an IndexError", err)
+	}
+}
+
+func TestAppend_Durable(t *testing.T) {
+	store, err := OpenWithOptions(filepath.Join(t.TempDir(), "entries.jsonl"), Options{DurableWrites: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Append(entry.Entry{Timestamp: at(9), Description: "standup", DurationMinutes: 15}); err != nil {
+		t.Fatalf("Append() returned unexpected error: %v", err)
+	}
+	if result, _ := store.ListEntries(query.Criteria{}); len(result.Entries) != 1 {
+		t.Errorf("Expected 1 entry, got %d", len(result.Entries))
+	}
+}
+
+func TestAppendAll(t *testing.T) {
+	store := openTestStore(t, testEntries()[1])
+
+	written, err := store.AppendAll([]entry.Entry{
+		{Timestamp: at(10), Description: "review", DurationMinutes: 30},
+		{Timestamp: at(11), Description: "fix login", DurationMinutes: 90},
+	})
+	if err != nil || written != 2 {
+		t.Fatalf("AppendAll() = %d, %v, expected 2, nil", written, err)
+	}
+	if result, _ := store.ListEntries(query.Criteria{}); len(result.Entries) != 3 {
+		t.Errorf("Expected 3 entries, got %d", len(result.Entries))
+	}
+}
+
+func TestTotals(t *testing.T) {
+	store := openTestStore(t, testEntries()...)
+
+	totals, err := store.Totals(query.Criteria{})
+	if err != nil {
+		t.Fatalf("Totals() returned unexpected error: %v", err)
+	}
+	if totals.Entries != 3 || totals.Minutes != 135 {
+		t.Errorf("Totals = %d entries, %d minutes, expected 3 entries, 135 minutes", totals.Entries, totals.Minutes)
+	}
+	if len(totals.Projects) != 2 || totals.Projects[0].Project != "acme" || totals.Projects[0].TotalMinutes != 120 {
+		t.Errorf("Unexpected project totals: %+v", totals.Projects)
+	}
+	if len(totals.Tags) != 3 || totals.Tags[0].Tag != "bugfix" || totals.Tags[0].TotalMinutes != 90 {
+		t.Errorf("Unexpected tag totals: %+v", totals.Tags)
+	}
+
+	filtered, err := store.Totals(query.Criteria{Tags: []string{"review"}})
+	if err != nil {
+		t.Fatalf("Totals() returned unexpected error: %v", err)
+	}
+	if filtered.Entries != 1 || filtered.Minutes != 30 {
+		t.Errorf("Filtered totals = %d entries, %d minutes, expected 1 entry, 30 minutes", filtered.Entries, filtered.Minutes)
+	}
+}
+
+func TestStore_ReadError(t *testing.T) {
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	store, _ := Open(filepath.Join(blocker, "entries.jsonl"))
+
+	if _, err := store.ListEntries(query.Criteria{}); err == nil {
+		t.Error("ListEntries() should fail for an unreadable path")
+	}
+	if _, err := store.Totals(query.Criteria{}); err == nil {
+		t.Error("Totals() should fail for an unreadable path")
+	}
+	if _, err := store.Entry(1); err == nil {
+		t.Error("Entry() should fail for an unreadable path")
+	}
+	if err := store.Update(1, entry.Entry{}); err == nil {
+		t.Error("Update() should fail for an unreadable path")
+	}
+	if err := store.Append(entry.Entry{}); err == nil {
+		t.Error("Append() should fail for an unreadable path")
+	}
+	if _, err := store.AppendAll([]entry.Entry{{}}); err == nil {
+		t.Error("AppendAll() should fail for an unreadable path")
+	}
+}
+
+func Example() {
+	dir, _ := os.MkdirTemp("", "didlib")
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	store, _ := Open(filepath.Join(dir, "entries.jsonl"))
+	_ = store.Append(entry.Entry{Timestamp: at(9), Description: "standup", DurationMinutes: 15})
+	_ = store.Append(entry.Entry{Timestamp: at(10), Description: "fix login", DurationMinutes: 90, Project: "acme"})
+
+	result, _ := store.ListEntries(query.Criteria{Project: "acme"})
+	for _, e := range result.Entries {
+		fmt.Printf("[%d] %s (%dm)\n", e.Index, e.Description, e.DurationMinutes)
+	}
+
+	totals, _ := store.Totals(query.Criteria{})
+	fmt.Printf("Total: %dm in %d entries\n", totals.Minutes, totals.Entries)
+	// Output:
+	// [2] fix login (90m)
+	// Total: 105m in 2 entries
+}
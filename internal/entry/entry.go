@@ -1,6 +1,12 @@
 package entry
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
 
 // Entry represents a single time tracking entry
 type Entry struct {
@@ -9,6 +15,115 @@ type Entry struct {
 	DurationMinutes int        `json:"duration_minutes"`
 	RawInput        string     `json:"raw_input"`
 	Project         string     `json:"project,omitempty"`
+	Client          string     `json:"client,omitempty"`
 	Tags            []string   `json:"tags,omitempty"`
 	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+
+	// LoggedAt is when the entry was appended to storage, while Timestamp is
+	// when the work happened. Entries written before it existed have none.
+	LoggedAt *time.Time `json:"logged_at,omitempty"`
+
+	// Source is the file an entry was read from when the storage path is a
+	// directory. It is only kept in memory and never written to storage.
+	Source string `json:"-"`
+}
+
+// HasValidDuration reports whether the entry's duration is positive. Entries
+// with a zero or negative duration (e.g. from a faulty import) are suspect:
+// they are kept in storage but left out of listings and totals.
+func (e Entry) HasValidDuration() bool {
+	return e.DurationMinutes > 0
+}
+
+// Duration returns the duration of the entry
+func (e Entry) Duration() time.Duration {
+	return time.Duration(e.DurationMinutes) * time.Minute
+}
+
+// EndTime returns the time the entry ends, treating Timestamp as its start
+func (e Entry) EndTime() time.Time {
+	return e.Timestamp.Add(e.Duration())
+}
+
+// In returns the entry with its timestamps (Timestamp, DeletedAt and
+// LoggedAt) converted to loc. The instants are unchanged.
+func (e Entry) In(loc *time.Location) Entry {
+	e.Timestamp = e.Timestamp.In(loc)
+	if e.DeletedAt != nil {
+		deletedAt := e.DeletedAt.In(loc)
+		e.DeletedAt = &deletedAt
+	}
+	if e.LoggedAt != nil {
+		loggedAt := e.LoggedAt.In(loc)
+		e.LoggedAt = &loggedAt
+	}
+	return e
+}
+
+// Orders of entries by timestamp, as given to --order
+const (
+	OrderAsc  = "asc"  // Oldest first (default)
+	OrderDesc = "desc" // Newest first
+)
+
+// SortByTimestamp sorts items by the timestamp returned by timestamp, oldest
+// first, or newest first when desc is set. The sort is stable in both orders:
+// items with equal timestamps keep their relative (file) order, so the result
+// is deterministic. Listings and exports share it.
+func SortByTimestamp[T any](items []T, timestamp func(T) time.Time, desc bool) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if desc {
+			return timestamp(items[i]).After(timestamp(items[j]))
+		}
+		return timestamp(items[i]).Before(timestamp(items[j]))
+	})
+}
+
+// Validate checks that the entry could have been logged with did: a
+// non-empty description, a duration of 1 to MaxDurationMinutes minutes, a set
+// timestamp and valid project, client and tag names. It returns the first problem
+// found, e.g. for entries read from an import.
+func (e Entry) Validate() error {
+	return e.ValidateWithMaxDuration(MaxDurationMinutes)
+}
+
+// ValidateWithMaxDuration is Validate with a duration limit of maxMinutes
+// instead of MaxDurationMinutes, e.g. the configured max_entry_duration
+func (e Entry) ValidateWithMaxDuration(maxMinutes int) error {
+	if e.Timestamp.IsZero() {
+		return errors.New("timestamp is missing")
+	}
+	if strings.TrimSpace(e.Description) == "" {
+		return errors.New("description is empty")
+	}
+	if e.DurationMinutes <= 0 {
+		return fmt.Errorf("duration must be positive (got %d minutes)", e.DurationMinutes)
+	}
+	if e.DurationMinutes > maxMinutes {
+		return fmt.Errorf("duration exceeds the maximum of %s (got %d minutes)", describeMinutes(maxMinutes), e.DurationMinutes)
+	}
+	if e.Project != "" && !IsValidName(e.Project) {
+		return fmt.Errorf("invalid project name '%s' (use letters, digits, hyphens, underscores, and single spaces between words)", e.Project)
+	}
+	if e.Client != "" && !IsValidName(e.Client) {
+		return fmt.Errorf("invalid client name '%s' (use letters, digits, hyphens, underscores, and single spaces between words)", e.Client)
+	}
+	for _, tag := range e.Tags {
+		if tag == "" {
+			return errors.New("tag is empty")
+		}
+		if !IsValidName(tag) {
+			return fmt.Errorf("invalid tag name '%s' (use letters, digits, hyphens, underscores, and single spaces between words)", tag)
+		}
+	}
+	return nil
+}
+
+// describeMinutes describes a duration limit in whole hours when possible,
+// e.g. "24 hours" or "90 minutes"
+func describeMinutes(minutes int) string {
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%d hours", minutes/60)
+	}
+	return fmt.Sprintf("%d minutes", minutes)
 }
@@ -2,7 +2,9 @@ package entry
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestEntryBackwardCompatibility(t *testing.T) {
@@ -63,6 +65,74 @@ func TestEntryOmitempty(t *testing.T) {
 	}
 }
 
+func TestEntryDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		minutes int
+		want    time.Duration
+	}{
+		{"zero", 0, 0},
+		{"one hour", 60, time.Hour},
+		{"mixed", 90, 90 * time.Minute},
+		{"max duration", MaxDurationMinutes, 24 * time.Hour},
+		{"large", 100000, 100000 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Entry{DurationMinutes: tt.minutes}
+			if got := e.Duration(); got != tt.want {
+				t.Errorf("Duration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntryEndTime(t *testing.T) {
+	start := time.Date(2024, 1, 15, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		minutes int
+		want    time.Time
+	}{
+		{"zero", 0, start},
+		{"same day", 15, time.Date(2024, 1, 15, 23, 45, 0, 0, time.UTC)},
+		{"crosses midnight", 90, time.Date(2024, 1, 16, 1, 0, 0, 0, time.UTC)},
+		{"large", 3 * 24 * 60, time.Date(2024, 1, 18, 23, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Entry{Timestamp: start, DurationMinutes: tt.minutes}
+			if got := e.EndTime(); !got.Equal(tt.want) {
+				t.Errorf("EndTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntryIn(t *testing.T) {
+	zone := time.FixedZone("UTC+2", 2*3600)
+	deletedAt := time.Date(2024, 1, 16, 8, 0, 0, 0, zone)
+	e := Entry{Timestamp: time.Date(2024, 1, 15, 23, 30, 0, 0, zone), DeletedAt: &deletedAt}
+
+	got := e.In(time.UTC)
+	if got.Timestamp.Location() != time.UTC || !got.Timestamp.Equal(e.Timestamp) || got.Timestamp.Day() != 15 || got.Timestamp.Hour() != 21 {
+		t.Errorf("Timestamp = %v, expected the same instant in UTC", got.Timestamp)
+	}
+	if got.DeletedAt.Location() != time.UTC || !got.DeletedAt.Equal(deletedAt) {
+		t.Errorf("DeletedAt = %v, expected the same instant in UTC", got.DeletedAt)
+	}
+	if got.LoggedAt != nil {
+		t.Errorf("LoggedAt = %v, expected it to stay unset", got.LoggedAt)
+	}
+	// The original entry is unchanged
+	if e.DeletedAt.Location() != zone {
+		t.Errorf("Expected In() to leave the original DeletedAt alone, got %v", e.DeletedAt)
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsAt(s, substr))
 }
@@ -75,3 +145,88 @@ func containsAt(s, substr string) bool {
 	}
 	return false
 }
+
+func TestEntryValidate(t *testing.T) {
+	valid := Entry{
+		Timestamp:       time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+		Description:     "fix login",
+		DurationMinutes: 90,
+		Project:         "acme",
+		Client:          "AcmeCorp",
+		Tags:            []string{"bugfix"},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() returned unexpected error for a valid entry: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(e *Entry)
+		wantErr string
+	}{
+		{"missing timestamp", func(e *Entry) { e.Timestamp = time.Time{} }, "timestamp is missing"},
+		{"empty description", func(e *Entry) { e.Description = "  " }, "description is empty"},
+		{"zero duration", func(e *Entry) { e.DurationMinutes = 0 }, "duration must be positive"},
+		{"negative duration", func(e *Entry) { e.DurationMinutes = -30 }, "duration must be positive (got -30 minutes)"},
+		{"too long", func(e *Entry) { e.DurationMinutes = MaxDurationMinutes + 1 }, "exceeds the maximum"},
+		{"invalid project", func(e *Entry) { e.Project = "acme.corp" }, "invalid project name 'acme.corp'"},
+		{"invalid client", func(e *Entry) { e.Client = "Acme/Corp" }, "invalid client name 'Acme/Corp'"},
+		{"empty tag", func(e *Entry) { e.Tags = []string{"bugfix", ""} }, "tag is empty"},
+		{"invalid tag", func(e *Entry) { e.Tags = []string{"bug!fix"} }, "invalid tag name 'bug!fix'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := valid
+			tt.modify(&e)
+			err := e.Validate()
+			if err == nil || !contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, expected it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEntryValidateWithMaxDuration(t *testing.T) {
+	e := Entry{Timestamp: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), Description: "workshop", DurationMinutes: 480}
+	if err := e.ValidateWithMaxDuration(480); err != nil {
+		t.Errorf("ValidateWithMaxDuration(480) returned unexpected error at the limit: %v", err)
+	}
+
+	e.DurationMinutes = 481
+	if err := e.ValidateWithMaxDuration(480); err == nil || !contains(err.Error(), "exceeds the maximum of 8 hours (got 481 minutes)") {
+		t.Errorf("ValidateWithMaxDuration(480) error = %v, expected the limit to be exceeded", err)
+	}
+	if err := e.ValidateWithMaxDuration(90); err == nil || !contains(err.Error(), "maximum of 90 minutes") {
+		t.Errorf("ValidateWithMaxDuration(90) error = %v, expected the limit in minutes", err)
+	}
+}
+
+func TestSortByTimestamp(t *testing.T) {
+	base := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Timestamp: base.Add(time.Hour), Description: "late"},
+		{Timestamp: base, Description: "tie 1"},
+		{Timestamp: base.Add(-time.Hour), Description: "early"},
+		{Timestamp: base, Description: "tie 2"},
+	}
+	timestamp := func(e Entry) time.Time { return e.Timestamp }
+
+	for _, tt := range []struct {
+		desc     bool
+		expected []string
+	}{
+		{false, []string{"early", "tie 1", "tie 2", "late"}},
+		{true, []string{"late", "tie 1", "tie 2", "early"}},
+	} {
+		sorted := append([]Entry{}, entries...)
+		SortByTimestamp(sorted, timestamp, tt.desc)
+		var got []string
+		for _, e := range sorted {
+			got = append(got, e.Description)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("SortByTimestamp(desc=%v) = %v, expected %v", tt.desc, got, tt.expected)
+		}
+	}
+}
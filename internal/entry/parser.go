@@ -16,92 +16,248 @@ var timePattern = regexp.MustCompile(`^(\d+)(h|m)$`)
 // MaxDurationMinutes is the maximum allowed duration per entry (24 hours)
 const MaxDurationMinutes = 24 * 60
 
+// MaxParsedMinutes bounds ParseDurationMinutes so that huge inputs cannot
+// overflow (about 100 years); it is the longest entry --allow-long accepts
+const MaxParsedMinutes = 100 * 366 * 24 * 60
+
 // ParseDuration parses a time duration string in Yh, Ym, or XhYm format
 // and returns the duration in minutes.
 // Valid inputs: "2h" (returns 120), "30m" (returns 30), "1h30m" (returns 90)
 // Invalid inputs: "invalid", "0h", "0m", "0h0m", values exceeding 24h
 func ParseDuration(input string) (minutes int, err error) {
-	// First try combined pattern (e.g., "1h30m")
-	combinedMatches := combinedTimePattern.FindStringSubmatch(input)
-	if combinedMatches != nil {
-		// Regex guarantees only digits, so Atoi cannot fail
-		hours, _ := strconv.Atoi(combinedMatches[1])
-		mins, _ := strconv.Atoi(combinedMatches[2])
-
-		// Calculate total minutes
-		minutes = hours*60 + mins
-
-		if minutes == 0 {
-			return 0, fmt.Errorf("invalid duration: duration cannot be zero")
-		}
-
-		if minutes > MaxDurationMinutes {
-			return 0, fmt.Errorf("invalid duration: exceeds maximum of 24 hours (%d minutes)", MaxDurationMinutes)
-		}
+	minutes, err = ParseDurationMinutes(input)
+	if err != nil {
+		return 0, err
+	}
 
-		return minutes, nil
+	if minutes == 0 {
+		return 0, fmt.Errorf("invalid duration: duration cannot be zero")
 	}
 
-	// Fall back to simple pattern (e.g., "2h" or "30m")
-	matches := timePattern.FindStringSubmatch(input)
-	if matches == nil {
-		return 0, fmt.Errorf("invalid time format: expected Xh, Xm, or XhYm, got %s", input)
+	if minutes > MaxDurationMinutes {
+		return 0, fmt.Errorf("invalid duration: exceeds maximum of 24 hours (%d minutes)", MaxDurationMinutes)
 	}
 
-	// Regex guarantees only digits, so Atoi cannot fail
-	value, _ := strconv.Atoi(matches[1])
+	return minutes, nil
+}
 
-	unit := matches[2]
-	if unit == "h" {
-		minutes = value * 60
-	} else {
+// ParseDurationMinutes parses a time duration string in Yh, Ym, or XhYm format
+// like ParseDuration, but leaves range checks to the caller: "0m" returns 0 and
+// durations over 24 hours are allowed.
+func ParseDurationMinutes(input string) (minutes int, err error) {
+	// First try combined pattern (e.g., "1h30m"), then the simple pattern (e.g., "2h" or "30m")
+	if combinedMatches := combinedTimePattern.FindStringSubmatch(input); combinedMatches != nil {
+		hours, hoursErr := strconv.Atoi(combinedMatches[1])
+		mins, minsErr := strconv.Atoi(combinedMatches[2])
+		if hoursErr != nil || minsErr != nil || hours > MaxParsedMinutes/60 || mins > MaxParsedMinutes {
+			return 0, fmt.Errorf("invalid duration: %s is too long", input)
+		}
+		minutes = hours*60 + mins
+	} else if matches := timePattern.FindStringSubmatch(input); matches != nil {
+		value, valueErr := strconv.Atoi(matches[1])
+		if valueErr != nil || value > MaxParsedMinutes {
+			return 0, fmt.Errorf("invalid duration: %s is too long", input)
+		}
 		minutes = value
+		if matches[2] == "h" {
+			minutes = value * 60
+		}
+	} else {
+		return 0, fmt.Errorf("invalid time format: expected Xh, Xm, or XhYm, got %s", input)
 	}
 
-	if minutes == 0 {
-		return 0, fmt.Errorf("invalid duration: duration cannot be zero")
+	if minutes > MaxParsedMinutes {
+		return 0, fmt.Errorf("invalid duration: %s is too long", input)
 	}
+	return minutes, nil
+}
 
+// RoundDuration rounds minutes up to the next multiple of step, capped at
+// MaxDurationMinutes unless minutes already exceeds it. A step of 0 or less
+// returns minutes unchanged.
+// Example: RoundDuration(20, 15) returns 30
+func RoundDuration(minutes, step int) int {
+	if step <= 0 {
+		return minutes
+	}
+	rounded := (minutes + step - 1) / step * step
 	if minutes > MaxDurationMinutes {
-		return 0, fmt.Errorf("invalid duration: exceeds maximum of 24 hours (%d minutes)", MaxDurationMinutes)
+		return rounded
 	}
+	return min(rounded, MaxDurationMinutes)
+}
 
-	return minutes, nil
+// RoundDurationNearest rounds minutes to the nearest multiple of step (halves
+// round up), for showing durations rounded without changing what is stored.
+// A positive duration is never rounded down to 0 but to step, so a short entry
+// still counts. A step of 0 or less returns minutes unchanged.
+// Example: RoundDurationNearest(20, 15) returns 15, RoundDurationNearest(5, 15) returns 15
+func RoundDurationNearest(minutes, step int) int {
+	if step <= 0 || minutes <= 0 {
+		return minutes
+	}
+	return max((2*minutes+step)/(2*step)*step, step)
 }
 
-// projectPattern matches @project syntax (e.g., "@acme", "@my-project", "@project123")
+// durationLikePattern matches words that look like a duration, whether or not
+// ParseDuration accepts them (e.g., "2h", "90m", "1h30m", "1.5h", "0m")
+var durationLikePattern = regexp.MustCompile(`(?i)^(\d+(\.\d+)?[hm]|\d+h\d+m)$`)
+
+// DefaultDurationKeyword is the word that separates the description from the
+// duration in "<description> for <duration>"
+const DefaultDurationKeyword = "for"
+
+// SplitAtDurationKeyword splits input at the last occurrence of keyword
+// surrounded by spaces (case-insensitive), e.g. "fix bug for 2h" with "for"
+// gives "fix bug" and "2h". A keyword within a quoted name such as
+// @"time for fun" is not a separator. Returns false if the keyword is missing.
+func SplitAtDurationKeyword(input, keyword string) (description, duration string, ok bool) {
+	sep := " " + keyword + " "
+	for i := len(input) - len(sep); i >= 0; i-- {
+		if strings.EqualFold(input[i:i+len(sep)], sep) && !inQuotedName(input, i) {
+			return strings.TrimSpace(input[:i]), strings.TrimSpace(input[i+len(sep):]), true
+		}
+	}
+	return "", "", false
+}
+
+// IsDurationOnly reports whether description consists solely of duration-like
+// words and the duration keyword, e.g. "2h" or "for 2h". Such a description is
+// almost always a typo like "did 2h for 2h" rather than a real description.
+func IsDurationOnly(description, keyword string) bool {
+	words := strings.Fields(description)
+	if len(words) == 0 {
+		return false
+	}
+	for _, word := range words {
+		if !strings.EqualFold(word, keyword) && !durationLikePattern.MatchString(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// TrimLeadingDurationClauses removes "<keyword> <duration>" clauses from the start of
+// description, which are left over when a duration was typed twice
+// (e.g., "did for 2h fix bug for 2h"). Clauses attached to words, as in
+// "waited for 2h on CI", are kept.
+// Example: "for 2h fix bug" -> "fix bug"
+func TrimLeadingDurationClauses(description, keyword string) string {
+	words := strings.Fields(description)
+	for len(words) >= 2 && strings.EqualFold(words[0], keyword) && durationLikePattern.MatchString(words[1]) {
+		words = words[2:]
+	}
+	return strings.Join(words, " ")
+}
+
+// projectPattern matches @project syntax (e.g., "@acme", "@my-project", "@project123"),
+// or a quoted name of several words (e.g., `@"Big Client"`).
 // Project names can contain alphanumeric characters, hyphens, and underscores
-var projectPattern = regexp.MustCompile(`@([a-zA-Z0-9_-]+)`)
+var projectPattern = regexp.MustCompile(`@(?:"( *[a-zA-Z0-9_-][a-zA-Z0-9_ -]*)"|([a-zA-Z0-9_-]+))`)
 
-// tagPattern matches #tag syntax (e.g., "#bugfix", "#urgent", "#v1-release")
+// tagPattern matches #tag syntax (e.g., "#bugfix", "#urgent", "#v1-release"),
+// or a quoted name of several words (e.g., `#"code review"`).
 // Tag names can contain alphanumeric characters, hyphens, and underscores
-var tagPattern = regexp.MustCompile(`#([a-zA-Z0-9_-]+)`)
+var tagPattern = regexp.MustCompile(`#(?:"( *[a-zA-Z0-9_-][a-zA-Z0-9_ -]*)"|([a-zA-Z0-9_-]+))`)
+
+// namePattern matches a bare project or tag name (without the @ or # prefix):
+// one or more words separated by single spaces
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+( [a-zA-Z0-9_-]+)*$`)
+
+// IsValidName reports whether name is a valid project or tag name.
+// Names can contain alphanumeric characters, hyphens, and underscores, and
+// several such words separated by single spaces (given quoted, as in @"Big Client").
+func IsValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// matchedName returns the name of a projectPattern or tagPattern match,
+// with the spaces of a quoted name normalized
+func matchedName(match []string) string {
+	if match[2] != "" {
+		return match[2]
+	}
+	return strings.Join(strings.Fields(match[1]), " ")
+}
+
+// QuoteName returns name as it is written after @ or #: quoted when it has
+// several words, e.g. `"Big Client"`, and unchanged otherwise
+func QuoteName(name string) string {
+	if strings.Contains(name, " ") {
+		return `"` + name + `"`
+	}
+	return name
+}
+
+// inQuotedName reports whether the byte at offset i of input lies within a
+// quoted @project or #tag name
+func inQuotedName(input string, i int) bool {
+	for _, pattern := range []*regexp.Regexp{projectPattern, tagPattern} {
+		for _, loc := range pattern.FindAllStringSubmatchIndex(input, -1) {
+			if loc[2] >= 0 && i >= loc[2] && i < loc[3] {
+				return true
+			}
+		}
+	}
+	return false
+}
 
 // whitespacePattern matches one or more whitespace characters for normalization
 var whitespacePattern = regexp.MustCompile(`\s+`)
 
+// escapedHash is an escaped # (\#), which stands for a literal # that never
+// starts a tag
+const escapedHash = `\#`
+
+// hashPlaceholder stands in for an escaped # while tags are extracted; NUL
+// cannot occur in command line arguments
+const hashPlaceholder = "\x00"
+
 // ParseProjectAndTags extracts @project and #tags from a description string.
 // Returns the cleaned description (without @project and #tags), the project name (if any),
 // and a slice of tags.
-// If multiple @project tokens are found, the last one wins.
+// If multiple @project tokens are found, the last one wins. A quoted name is
+// returned without its quotes. An escaped \# is kept as a literal # in the
+// description instead of starting a tag.
 // Example: "fix bug @acme #bugfix #urgent" -> ("fix bug", "acme", ["bugfix", "urgent"])
+// Example: `sync @"Big Client" #"code review"` -> ("sync", "Big Client", ["code review"])
+// Example: `fixed issue \#1234 #bugfix` -> ("fixed issue #1234", "", ["bugfix"])
 func ParseProjectAndTags(description string) (cleanDesc string, project string, tags []string) {
+	return parseNames(description, true)
+}
+
+// ParseProject extracts the @project from a description string like
+// ParseProjectAndTags, but keeps #words verbatim in the description instead
+// of extracting them as tags, for text such as "fixed issue #1234".
+// Example: "fixed issue #1234 @acme" -> ("fixed issue #1234", "acme")
+func ParseProject(description string) (cleanDesc string, project string) {
+	cleanDesc, project, _ = parseNames(description, false)
+	return cleanDesc, project
+}
+
+// parseNames extracts the @project and, when withTags is set, the #tags of
+// description; see ParseProjectAndTags
+func parseNames(description string, withTags bool) (cleanDesc string, project string, tags []string) {
+	description = strings.ReplaceAll(description, escapedHash, hashPlaceholder)
+
 	// Extract all projects (last one wins)
 	projectMatches := projectPattern.FindAllStringSubmatch(description, -1)
 	if len(projectMatches) > 0 {
-		project = projectMatches[len(projectMatches)-1][1]
-	}
-
-	// Extract all tags
-	tagMatches := tagPattern.FindAllStringSubmatch(description, -1)
-	for _, match := range tagMatches {
-		tags = append(tags, match[1])
+		project = matchedName(projectMatches[len(projectMatches)-1])
 	}
 
-	// Remove all @project and #tag tokens from the description
+	// Remove all @project tokens from the description
 	cleanDesc = projectPattern.ReplaceAllString(description, "")
-	cleanDesc = tagPattern.ReplaceAllString(cleanDesc, "")
 
+	// Extract all tags, then remove their tokens too
+	if withTags {
+		for _, match := range tagPattern.FindAllStringSubmatch(description, -1) {
+			tags = append(tags, matchedName(match))
+		}
+		cleanDesc = tagPattern.ReplaceAllString(cleanDesc, "")
+	}
+
+	cleanDesc = strings.ReplaceAll(cleanDesc, hashPlaceholder, "#")
 	cleanDesc = strings.TrimSpace(cleanDesc)
 	cleanDesc = whitespacePattern.ReplaceAllString(cleanDesc, " ")
 
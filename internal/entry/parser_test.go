@@ -535,3 +535,289 @@ func TestParseProjectAndTags_CaseSensitivity(t *testing.T) {
 		})
 	}
 }
+
+func TestParseProjectAndTags_QuotedNames(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expectedDesc string
+		expectedProj string
+		expectedTags []string
+	}{
+		{"quoted project", `sync @"Big Client" notes`, "sync notes", "Big Client", nil},
+		{"quoted tag", `fix #"code review" done`, "fix done", "", []string{"code review"}},
+		{"mixed with plain names", `plan @"Big Client" #urgent #"code review" q3`, "plan q3", "Big Client", []string{"urgent", "code review"}},
+		{"single word in quotes", `fix @"acme"`, "fix", "acme", nil},
+		{"spaces normalized", `fix @"  Big   Client "`, "fix", "Big Client", nil},
+		{"last project wins", `fix @acme @"Big Client"`, "fix", "Big Client", nil},
+		{"empty quotes", `fix @"" bug`, `fix @"" bug`, "", nil},
+		{"unterminated quote", `fix @"Big Client bug`, `fix @"Big Client bug`, "", nil},
+		{"invalid characters", `fix @"Big.Client"`, `fix @"Big.Client"`, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desc, proj, tags := ParseProjectAndTags(tt.input)
+			if desc != tt.expectedDesc {
+				t.Errorf("ParseProjectAndTags(%q) desc = %q, expected %q", tt.input, desc, tt.expectedDesc)
+			}
+			if proj != tt.expectedProj {
+				t.Errorf("ParseProjectAndTags(%q) project = %q, expected %q", tt.input, proj, tt.expectedProj)
+			}
+			if !equalStringSlices(tags, tt.expectedTags) {
+				t.Errorf("ParseProjectAndTags(%q) tags = %v, expected %v", tt.input, tags, tt.expectedTags)
+			}
+		})
+	}
+}
+
+func TestParseProjectAndTags_EscapedHash(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expectedDesc string
+		expectedTags []string
+	}{
+		{"escaped hash", `fixed issue \#1234`, "fixed issue #1234", nil},
+		{"escaped and real tag", `fixed issue \#1234 #bugfix`, "fixed issue #1234", []string{"bugfix"}},
+		{"escaped quoted tag", `ship \#"code review"`, `ship #"code review"`, nil},
+		{"unescaped hash", "fixed issue #1234", "fixed issue", []string{"1234"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desc, _, tags := ParseProjectAndTags(tt.input)
+			if desc != tt.expectedDesc {
+				t.Errorf("ParseProjectAndTags(%q) desc = %q, expected %q", tt.input, desc, tt.expectedDesc)
+			}
+			if !equalStringSlices(tags, tt.expectedTags) {
+				t.Errorf("ParseProjectAndTags(%q) tags = %v, expected %v", tt.input, tags, tt.expectedTags)
+			}
+		})
+	}
+}
+
+func TestParseProject(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedDesc string
+		expectedProj string
+	}{
+		{"fixed issue #1234", "fixed issue #1234", ""},
+		{"fixed issue #1234 @acme", "fixed issue #1234", "acme"},
+		{`review #"code review" @"Big Client"`, `review #"code review"`, "Big Client"},
+		{`fixed issue \#1234`, "fixed issue #1234", ""},
+	}
+
+	for _, tt := range tests {
+		desc, proj := ParseProject(tt.input)
+		if desc != tt.expectedDesc || proj != tt.expectedProj {
+			t.Errorf("ParseProject(%q) = (%q, %q), expected (%q, %q)", tt.input, desc, proj, tt.expectedDesc, tt.expectedProj)
+		}
+	}
+}
+
+func TestQuoteName(t *testing.T) {
+	if got := QuoteName("acme"); got != "acme" {
+		t.Errorf("QuoteName(%q) = %q, expected it unchanged", "acme", got)
+	}
+	if got := QuoteName("Big Client"); got != `"Big Client"` {
+		t.Errorf("QuoteName(%q) = %q, expected it quoted", "Big Client", got)
+	}
+}
+
+func TestIsValidName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"simple", "acme", true},
+		{"with hyphen", "my-project", true},
+		{"with underscore", "my_project", true},
+		{"with digits", "v1", true},
+		{"empty", "", false},
+		{"with space", "my project", true},
+		{"with double space", "my  project", false},
+		{"with leading space", " project", false},
+		{"with trailing space", "project ", false},
+		{"with prefix", "@acme", false},
+		{"with dot", "v1.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidName(tt.input); got != tt.expected {
+				t.Errorf("IsValidName(%q) = %v, expected %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRoundDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		minutes  int
+		step     int
+		expected int
+	}{
+		{"no rounding", 20, 0, 20},
+		{"negative step", 20, -5, 20},
+		{"rounds up", 20, 15, 30},
+		{"exact multiple", 30, 15, 30},
+		{"one minute", 1, 15, 15},
+		{"capped at maximum", MaxDurationMinutes - 5, 60, MaxDurationMinutes},
+		{"long entry is not capped", MaxDurationMinutes + 5, 60, MaxDurationMinutes + 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RoundDuration(tt.minutes, tt.step); got != tt.expected {
+				t.Errorf("RoundDuration(%d, %d) = %d, expected %d", tt.minutes, tt.step, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRoundDurationNearest(t *testing.T) {
+	tests := []struct {
+		minutes  int
+		step     int
+		expected int
+	}{
+		{20, 0, 20},
+		{20, 15, 15},
+		{23, 15, 30},
+		{22, 15, 15},
+		{30, 15, 30},
+		{5, 15, 15}, // never rounded to 0
+		{0, 15, 0},
+		{-10, 15, -10},
+		{MaxDurationMinutes + 20, 60, MaxDurationMinutes},
+	}
+
+	for _, tt := range tests {
+		if got := RoundDurationNearest(tt.minutes, tt.step); got != tt.expected {
+			t.Errorf("RoundDurationNearest(%d, %d) = %d, expected %d", tt.minutes, tt.step, got, tt.expected)
+		}
+	}
+}
+
+func TestIsDurationOnly(t *testing.T) {
+	tests := []struct {
+		description string
+		expected    bool
+	}{
+		{"2h", true},
+		{"for 2h", true},
+		{"for", true},
+		{"FOR 1h30m", true},
+		{"2h for 1h", true},
+		{"1.5h", true},
+		{"0m", true},
+		{"", false},
+		{"   ", false},
+		{"fix bug", false},
+		{"for groceries", false},
+		{"waited for 2h", false},
+		{"2 hours", false},
+		{"h", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if got := IsDurationOnly(tt.description, DefaultDurationKeyword); got != tt.expected {
+				t.Errorf("IsDurationOnly(%q) = %v, expected %v", tt.description, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTrimLeadingDurationClauses(t *testing.T) {
+	tests := []struct {
+		description string
+		expected    string
+	}{
+		{"for 2h", ""},
+		{"for 2h fix bug", "fix bug"},
+		{"for 2h for 30m fix bug", "fix bug"},
+		{"For 1h30m fix bug", "fix bug"},
+		{"fix bug", "fix bug"},
+		{"waited for 2h on CI", "waited for 2h on CI"},
+		{"for groceries", "for groceries"},
+		{"for", "for"},
+		{"2h", "2h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if got := TrimLeadingDurationClauses(tt.description, DefaultDurationKeyword); got != tt.expected {
+				t.Errorf("TrimLeadingDurationClauses(%q) = %q, expected %q", tt.description, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseDurationMinutes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+		wantErr  bool
+	}{
+		{"0m", 0, false},
+		{"0h0m", 0, false},
+		{"2h", 120, false},
+		{"1h30m", 90, false},
+		{"30h", 1800, false},
+		{"2000m", 2000, false},
+		{"-30m", 0, true},
+		{"abc", 0, true},
+		{"99999999999999999999h", 0, true},
+		{"9999999999h", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDurationMinutes(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDurationMinutes(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseDurationMinutes(%q) = %d, expected %d", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitAtDurationKeyword(t *testing.T) {
+	tests := []struct {
+		input       string
+		keyword     string
+		description string
+		duration    string
+		ok          bool
+	}{
+		{"fix bug for 2h", "for", "fix bug", "2h", true},
+		{"waited for CI for 30m", "for", "waited for CI", "30m", true},
+		{"fix bug FOR 2h", "for", "fix bug", "2h", true},
+		{"Fehler behoben für 2h", "für", "Fehler behoben", "2h", true},
+		{"Fehler behoben FÜR 2h", "für", "Fehler behoben", "2h", true},
+		{"fix bug for 2h", "für", "", "", false},
+		{"fix bug 2h", "for", "", "", false},
+		{"format 2h", "for", "", "", false},
+		{`sync @"Big Client" for 2h`, "for", `sync @"Big Client"`, "2h", true},
+		{`plan @"time for fun" for 1h`, "for", `plan @"time for fun"`, "1h", true},
+		{`plan #"for good" for 1h`, "for", `plan #"for good"`, "1h", true},
+		{`plan @"made for you"`, "for", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input+"/"+tt.keyword, func(t *testing.T) {
+			description, duration, ok := SplitAtDurationKeyword(tt.input, tt.keyword)
+			if ok != tt.ok || description != tt.description || duration != tt.duration {
+				t.Errorf("SplitAtDurationKeyword(%q, %q) = (%q, %q, %v), expected (%q, %q, %v)",
+					tt.input, tt.keyword, description, duration, ok, tt.description, tt.duration, tt.ok)
+			}
+		})
+	}
+}
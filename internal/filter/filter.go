@@ -11,6 +11,7 @@ import (
 type Filter struct {
 	Keyword string   // Case-insensitive substring search in entry descriptions
 	Project string   // Exact project match (case-insensitive)
+	Client  string   // Exact client match (case-insensitive)
 	Tags    []string // All specified tags must be present (AND logic, case-insensitive)
 }
 
@@ -26,7 +27,7 @@ func NewFilter(keyword, project string, tags []string) *Filter {
 
 // IsEmpty returns true if all filter fields are empty (matches all entries)
 func (f *Filter) IsEmpty() bool {
-	return f.Keyword == "" && f.Project == "" && len(f.Tags) == 0
+	return f.Keyword == "" && f.Project == "" && f.Client == "" && len(f.Tags) == 0
 }
 
 // FilterEntries returns a new slice containing only entries that match the filter criteria.
@@ -63,6 +64,15 @@ func (f *Filter) MatchesProject(e entry.Entry) bool {
 	return strings.EqualFold(e.Project, f.Project)
 }
 
+// MatchesClient returns true if the entry's client exactly matches the filter client (case-insensitive).
+// An empty client filter matches all entries.
+func (f *Filter) MatchesClient(e entry.Entry) bool {
+	if f.Client == "" {
+		return true
+	}
+	return strings.EqualFold(e.Client, f.Client)
+}
+
 // MatchesTags returns true if the entry has ALL specified tags (case-insensitive).
 // An empty tags filter matches all entries.
 func (f *Filter) MatchesTags(e entry.Entry) bool {
@@ -89,5 +99,5 @@ func (f *Filter) MatchesTags(e entry.Entry) bool {
 // Matches returns true if the entry matches ALL non-empty filter criteria (AND logic).
 // An empty filter matches all entries.
 func (f *Filter) Matches(e entry.Entry) bool {
-	return f.MatchesKeyword(e) && f.MatchesProject(e) && f.MatchesTags(e)
+	return f.MatchesKeyword(e) && f.MatchesProject(e) && f.MatchesClient(e) && f.MatchesTags(e)
 }
@@ -386,6 +386,40 @@ func TestMatchesProject_CaseInsensitive(t *testing.T) {
 	}
 }
 
+func TestMatchesClient(t *testing.T) {
+	tests := []struct {
+		name         string
+		filterClient string
+		entryClient  string
+		expected     bool
+	}{
+		{"empty filter", "", "AcmeCorp", true},
+		{"empty filter and client", "", "", true},
+		{"exact match", "AcmeCorp", "AcmeCorp", true},
+		{"case-insensitive", "acmecorp", "AcmeCorp", true},
+		{"no match", "AcmeCorp", "Globex", false},
+		{"entry without client", "AcmeCorp", "", false},
+		{"substring not matched", "Acme", "AcmeCorp", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{Client: tt.filterClient}
+			e := entry.Entry{Description: "work", Project: "backend", Client: tt.entryClient}
+			if got := f.MatchesClient(e); got != tt.expected {
+				t.Errorf("MatchesClient() = %v, expected %v", got, tt.expected)
+			}
+			if got := f.Matches(e); got != tt.expected {
+				t.Errorf("Matches() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+
+	if (&Filter{Client: "AcmeCorp"}).IsEmpty() {
+		t.Error("IsEmpty() = true, expected false for a client filter")
+	}
+}
+
 func TestMatchesTags_EmptyTags(t *testing.T) {
 	f := NewFilter("", "", nil)
 	entries := []entry.Entry{
@@ -0,0 +1,28 @@
+package osutil
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// ErrNoClipboard is returned by ReadClipboard when no clipboard tool is
+// available, e.g. in a headless session
+var ErrNoClipboard = errors.New("no clipboard tool available")
+
+// ReadClipboard returns the text on the system clipboard, read with the first
+// clipboard tool of the platform that is installed (pbpaste on macOS,
+// PowerShell on Windows, wl-paste, xclip or xsel elsewhere).
+func ReadClipboard() (string, error) {
+	for _, command := range clipboardCommands() {
+		if _, err := exec.LookPath(command[0]); err != nil {
+			continue
+		}
+		out, err := exec.Command(command[0], command[1:]...).Output()
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", command[0], err)
+		}
+		return string(out), nil
+	}
+	return "", ErrNoClipboard
+}
@@ -0,0 +1,20 @@
+//go:build !darwin && !windows
+
+package osutil
+
+import "os"
+
+// clipboardCommands returns the commands that print the clipboard, in order of
+// preference. Without a Wayland or X11 display there is no clipboard to read.
+func clipboardCommands() [][]string {
+	var commands [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		commands = append(commands, []string{"wl-paste", "--no-newline"})
+	}
+	if os.Getenv("DISPLAY") != "" {
+		commands = append(commands,
+			[]string{"xclip", "-selection", "clipboard", "-o"},
+			[]string{"xsel", "--clipboard", "--output"})
+	}
+	return commands
+}
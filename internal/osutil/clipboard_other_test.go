@@ -0,0 +1,27 @@
+//go:build !darwin && !windows
+
+package osutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReadClipboard_Headless(t *testing.T) {
+	t.Setenv("WAYLAND_DISPLAY", "")
+	t.Setenv("DISPLAY", "")
+
+	if _, err := ReadClipboard(); !errors.Is(err, ErrNoClipboard) {
+		t.Errorf("ReadClipboard() error = %v, want ErrNoClipboard", err)
+	}
+}
+
+func TestReadClipboard_ToolNotInstalled(t *testing.T) {
+	t.Setenv("WAYLAND_DISPLAY", "wayland-0")
+	t.Setenv("DISPLAY", ":0")
+	t.Setenv("PATH", t.TempDir())
+
+	if _, err := ReadClipboard(); !errors.Is(err, ErrNoClipboard) {
+		t.Errorf("ReadClipboard() error = %v, want ErrNoClipboard", err)
+	}
+}
@@ -0,0 +1,6 @@
+package osutil
+
+// clipboardCommands returns the commands that print the clipboard, in order of preference
+func clipboardCommands() [][]string {
+	return [][]string{{"powershell.exe", "-NoProfile", "-Command", "Get-Clipboard"}}
+}
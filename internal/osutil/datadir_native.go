@@ -0,0 +1,10 @@
+//go:build darwin || windows
+
+package osutil
+
+// UserDataDir returns the default root directory for user-specific data files.
+// macOS and Windows keep data and configuration in the same directory
+// (Application Support and %AppData%), so this is Provider.UserConfigDir().
+func UserDataDir() (string, error) {
+	return Provider.UserConfigDir()
+}
@@ -0,0 +1,23 @@
+//go:build !darwin && !windows
+
+package osutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// UserDataDir returns the default root directory for user-specific data files
+// following the XDG Base Directory Specification: $XDG_DATA_HOME if it is set
+// to an absolute path, ~/.local/share otherwise.
+func UserDataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	home := os.Getenv("HOME")
+	if home == "" {
+		return "", errors.New("neither $XDG_DATA_HOME nor $HOME are defined")
+	}
+	return filepath.Join(home, ".local", "share"), nil
+}
@@ -0,0 +1,54 @@
+package osutil
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// ErrLockTimeout is returned by LockFile when the lock is still held by
+// another process after the timeout
+var ErrLockTimeout = errors.New("timed out waiting for file lock")
+
+// lockRetryMin and lockRetryMax bound the backoff between attempts to take a lock
+const (
+	lockRetryMin = 5 * time.Millisecond
+	lockRetryMax = 100 * time.Millisecond
+)
+
+// LockFile takes an exclusive advisory lock on the file at path, creating it
+// if needed. It retries with a growing backoff and returns ErrLockTimeout when
+// the lock is not free within timeout. The returned function releases the lock.
+func LockFile(path string, timeout time.Duration) (func() error, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	wait := lockRetryMin
+	for {
+		locked, err := tryLock(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			_ = file.Close()
+			return nil, ErrLockTimeout
+		}
+		time.Sleep(wait)
+		wait = min(wait*2, lockRetryMax)
+	}
+
+	return func() error {
+		err := unlock(file)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}, nil
+}
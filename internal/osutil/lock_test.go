@@ -0,0 +1,64 @@
+package osutil
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+
+	release, err := LockFile(path, time.Second)
+	if err != nil {
+		t.Fatalf("LockFile returned error: %v", err)
+	}
+
+	// A second lock on the same file times out while the first is held
+	start := time.Now()
+	if _, err := LockFile(path, 50*time.Millisecond); !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("Expected ErrLockTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected to wait for the timeout, returned after %v", elapsed)
+	}
+
+	if err := release(); err != nil {
+		t.Fatalf("release returned error: %v", err)
+	}
+
+	// After release the lock can be taken again
+	release, err = LockFile(path, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("LockFile after release returned error: %v", err)
+	}
+	_ = release()
+}
+
+func TestLockFile_WaitsForRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+
+	release, err := LockFile(path, time.Second)
+	if err != nil {
+		t.Fatalf("LockFile returned error: %v", err)
+	}
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = release()
+	}()
+
+	second, err := LockFile(path, 2*time.Second)
+	if err != nil {
+		t.Fatalf("Expected the lock once released, got %v", err)
+	}
+	_ = second()
+}
+
+func TestLockFile_OpenError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "test.lock")
+
+	if _, err := LockFile(path, time.Second); err == nil {
+		t.Error("Expected error when the lock file cannot be created")
+	}
+}
@@ -0,0 +1,30 @@
+package osutil
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockRange is the number of bytes locked by LockFileEx; any fixed range works
+// since every process locks the same one
+const lockRange = 1
+
+// tryLock takes an exclusive LockFileEx lock on file without blocking.
+// Returns false if another process holds the lock.
+func tryLock(file *os.File) (bool, error) {
+	ol := new(windows.Overlapped)
+	err := windows.LockFileEx(windows.Handle(file.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, lockRange, 0, ol)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// unlock releases the LockFileEx lock on file
+func unlock(file *os.File) error {
+	ol := new(windows.Overlapped)
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, lockRange, 0, ol)
+}
@@ -0,0 +1,496 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/filter"
+	"github.com/xolan/did/internal/timeutil"
+)
+
+var (
+	// ErrConflictingPeriods is returned when more than one time period flag is set
+	ErrConflictingPeriods = errors.New("time period flags are mutually exclusive")
+	// ErrLastWithRange is returned when --last is combined with --from or --to
+	ErrLastWithRange = errors.New("cannot use --last with --from or --to")
+)
+
+// DateError is returned for a --date, --from or --to value that cannot be parsed
+type DateError struct {
+	Flag string // flag name without dashes, e.g. "from"
+	Err  error
+}
+
+func (e *DateError) Error() string {
+	if e.Flag == "date" {
+		return fmt.Sprintf("invalid --date value: %v", e.Err)
+	}
+	return fmt.Sprintf("invalid --%s date: %v", e.Flag, e.Err)
+}
+
+func (e *DateError) Unwrap() error {
+	return e.Err
+}
+
+// LastError is returned for a --last value that cannot be parsed
+type LastError struct {
+	Value string
+}
+
+func (e *LastError) Error() string {
+	return fmt.Sprintf("invalid --last value %q: use a number of days (7), weeks (2w) or months (3m)", e.Value)
+}
+
+// Period is a named time range
+type Period struct {
+	// Name is the short name, e.g. "this week", "last 7 days" or "Jan 1 - Jan 31, 2024"
+	Name string
+	// Label is Name followed by the date range when the name is relative,
+	// e.g. "this week (Jan 1 - Jan 7, 2024)"
+	Label string
+	// Unit is what the period is compared against: "day", "week", "month" or "period"
+	Unit string
+	// Start and End bound the range (inclusive). A zero Start has no lower bound.
+	Start time.Time
+	End   time.Time
+}
+
+// IsZero reports whether no period is set
+func (p Period) IsZero() bool {
+	return p.Name == ""
+}
+
+// Contains reports whether t lies within the period
+func (p Period) Contains(t time.Time) bool {
+	return timeutil.IsInRange(t, p.Start, p.End)
+}
+
+// Previous returns the period of the same kind just before p: the previous
+// week or month, or as many days as p spans. ok is false for a period
+// without a start, which has nothing before it.
+func (p Period) Previous() (prev Period, ok bool) {
+	switch {
+	case p.IsZero() || p.Start.IsZero():
+		return Period{}, false
+	case p.Unit == "week":
+		return Period{Name: "previous week", Unit: "week", Start: p.Start.AddDate(0, 0, -7), End: p.End.AddDate(0, 0, -7)}, true
+	case p.Unit == "month":
+		// Months start on the day p starts on, see Month
+		day := p.Start.AddDate(0, 0, -1)
+		start := timeutil.StartOfMonthWithConfig(day, p.Start.Day())
+		return Period{Name: "previous month", Unit: "month", Start: start, End: timeutil.EndOfMonthWithConfig(day, p.Start.Day())}, true
+	}
+
+	days := 0
+	for d := p.Start; !d.After(p.End); d = d.AddDate(0, 0, 1) {
+		days++
+	}
+	start := timeutil.StartOfDay(p.Start.AddDate(0, 0, -days))
+	end := timeutil.EndOfDay(p.Start.AddDate(0, 0, -1))
+	return Period{Name: "previous " + p.Unit, Unit: p.Unit, Start: start, End: end}, true
+}
+
+// Today returns the period of the day of now. Like the other relative
+// periods, pass the current time in the configured timezone as now (see
+// timeutil.NowIn), so that days start at midnight in that timezone.
+func Today(now time.Time) Period {
+	start, end := timeutil.Today(now)
+	return Period{Name: "today", Label: "today", Unit: "day", Start: start, End: end}
+}
+
+// Yesterday returns the period of the day before now
+func Yesterday(now time.Time) Period {
+	start, end := timeutil.Yesterday(now)
+	return Period{Name: "yesterday", Label: "yesterday", Unit: "day", Start: start, End: end}
+}
+
+// Week returns the week of now, or the previous week when prev is set,
+// starting on weekStartDay ("monday" or "sunday")
+func Week(now time.Time, weekStartDay string, prev bool) Period {
+	day, name := now, "this week"
+	if prev {
+		day, name = day.AddDate(0, 0, -7), "previous week"
+	}
+	start := timeutil.StartOfWeekWithConfig(day, weekStartDay)
+	end := timeutil.EndOfWeekWithConfig(day, weekStartDay)
+	return relativePeriod(name, "week", start, end)
+}
+
+// Month returns the month of now, or the previous month when prev is set,
+// starting on monthStartDay (1-28, 1 for calendar months)
+func Month(now time.Time, monthStartDay int, prev bool) Period {
+	day, name := now, "this month"
+	if prev {
+		day, name = timeutil.StartOfMonthWithConfig(day, monthStartDay).AddDate(0, 0, -1), "previous month"
+	}
+	start := timeutil.StartOfMonthWithConfig(day, monthStartDay)
+	end := timeutil.EndOfMonthWithConfig(day, monthStartDay)
+	return relativePeriod(name, "month", start, end)
+}
+
+// LastDays returns the period of the last n days, including the day of now
+func LastDays(now time.Time, n int) Period {
+	end := timeutil.EndOfDay(now)
+	start := timeutil.StartOfDay(now.AddDate(0, 0, -(n - 1)))
+	return relativePeriod(fmt.Sprintf("last %d %s", n, pluralUnit(n, "day")), "period", start, end)
+}
+
+// LastWeeks returns the period of the last n weeks (7n days), including the day of now
+func LastWeeks(now time.Time, n int) Period {
+	end := timeutil.EndOfDay(now)
+	start := timeutil.StartOfDay(now.AddDate(0, 0, -(7*n - 1)))
+	return relativePeriod(fmt.Sprintf("last %d %s", n, pluralUnit(n, "week")), "period", start, end)
+}
+
+// LastMonths returns the period of the last n calendar months, including
+// the day of now, see monthsStart
+func LastMonths(now time.Time, n int) Period {
+	return relativePeriod(fmt.Sprintf("last %d %s", n, pluralUnit(n, "month")), "period", monthsStart(now, n), timeutil.EndOfDay(now))
+}
+
+// monthsStart returns the start of the n calendar months ending on day: the
+// day after the same day of the month n months earlier, or the first of the
+// following month when that month is shorter (Mar 1 for one month on Mar 31)
+func monthsStart(day time.Time, n int) time.Time {
+	y, m, d := day.Date()
+	first := time.Date(y, m-time.Month(n), 1, 0, 0, 0, 0, day.Location())
+	if last := timeutil.EndOfMonth(first).Day(); d > last {
+		d = last
+	}
+	return time.Date(first.Year(), first.Month(), d+1, 0, 0, 0, 0, day.Location())
+}
+
+// ParseLast returns the period selected by a --last value: a number of days
+// ("7"), weeks ("2w") or calendar months ("3m"), see LastDays, LastWeeks and
+// LastMonths. An empty value or 0 selects no period.
+func ParseLast(value string, now time.Time) (Period, error) {
+	number, suffix := value, ""
+	if n := len(value); n > 0 && (value[n-1] == 'w' || value[n-1] == 'm') {
+		number, suffix = value[:n-1], value[n-1:]
+	}
+	if value == "" || (number == "0" && suffix == "") {
+		return Period{}, nil
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil || n <= 0 {
+		return Period{}, &LastError{Value: value}
+	}
+
+	switch suffix {
+	case "w":
+		return LastWeeks(now, n), nil
+	case "m":
+		return LastMonths(now, n), nil
+	default:
+		return LastDays(now, n), nil
+	}
+}
+
+// pluralUnit returns unit, with an "s" unless n is 1
+func pluralUnit(n int, unit string) string {
+	if n == 1 {
+		return unit
+	}
+	return unit + "s"
+}
+
+// relativePeriod returns a period whose label adds the date range to the name
+func relativePeriod(name, unit string, start, end time.Time) Period {
+	label := fmt.Sprintf("%s (%s)", name, FormatDateRange(start, end))
+	return Period{Name: name, Label: label, Unit: unit, Start: start, End: end}
+}
+
+// FormatDateRange formats a date range for human-readable display, e.g.
+// "Mon, Jan 15, 2024", "Mon Jan 15 - Sun Jan 21, 2024" or
+// "Fri Dec 1, 2023 - Wed Jan 31, 2024". A range without a start is formatted
+// as "until Jan 31, 2024".
+func FormatDateRange(start, end time.Time) string {
+	if start.IsZero() {
+		return "until " + end.Format("Jan 2, 2006")
+	}
+
+	// If same day, show single date
+	if start.Format("2006-01-02") == end.Format("2006-01-02") {
+		return start.Format("Mon, Jan 2, 2006")
+	}
+
+	// If same year, don't repeat the year
+	if start.Year() == end.Year() {
+		return fmt.Sprintf("%s - %s",
+			start.Format("Mon Jan 2"),
+			end.Format("Mon Jan 2, 2006"))
+	}
+
+	// Different years, show both
+	return fmt.Sprintf("%s - %s",
+		start.Format("Mon Jan 2, 2006"),
+		end.Format("Mon Jan 2, 2006"))
+}
+
+// Criteria selects entries by time period and project/tag filters
+type Criteria struct {
+	// Period is the time range selected by the time period flags; zero when none is set
+	Period Period
+	// Last, From and To are the --last, --from and --to values that selected the period
+	Last string
+	From string
+	To   string
+	// LastDays is the number of days of a --last value given in days, else 0
+	LastDays int
+	// Project, Client and Tags filter entries like filter.Filter (tags use AND logic)
+	Project string
+	Client  string
+	Tags    []string
+}
+
+// ResolveFilters returns the criteria for the --project, --client and --tag
+// flags of the root command of cmd, without a period
+func ResolveFilters(cmd *cobra.Command) Criteria {
+	project, _ := cmd.Root().PersistentFlags().GetString("project")
+	client, _ := cmd.Root().PersistentFlags().GetString("client")
+	tags, _ := cmd.Root().PersistentFlags().GetStringSlice("tag")
+	return Criteria{Project: project, Client: client, Tags: tags}
+}
+
+// Resolve returns the criteria selected by the time period flags of cmd and the
+// --project, --client and --tag flags of its root command. Time period flags cmd does
+// not define are ignored, so commands may support a subset of them. Relative
+// dates are resolved in the configured timezone, weeks and months start on
+// the configured week and month start days.
+func Resolve(cmd *cobra.Command, cfg config.Config) (Criteria, error) {
+	c := ResolveFilters(cmd)
+
+	flags := cmd.Flags()
+	yesterday, _ := flags.GetBool("yesterday")
+	thisWeek, _ := flags.GetBool("this-week")
+	prevWeek, _ := flags.GetBool("prev-week")
+	thisMonth, _ := flags.GetBool("this-month")
+	prevMonth, _ := flags.GetBool("prev-month")
+	now, err := timeutil.NowIn(cfg.Timezone)
+	if err != nil {
+		return Criteria{}, err
+	}
+	last, _ := flags.GetString("last")
+	lastPeriod, err := ParseLast(last, now)
+	if err != nil {
+		return Criteria{}, err
+	}
+	if !lastPeriod.IsZero() {
+		c.Last = last
+		c.LastDays, _ = strconv.Atoi(last)
+	}
+	c.From, _ = flags.GetString("from")
+	c.To, _ = flags.GetString("to")
+	dateStr, _ := flags.GetString("date")
+
+	// Count how many time period options are set
+	hasRange := c.From != "" || c.To != ""
+	count := 0
+	for _, set := range []bool{yesterday, thisWeek, prevWeek, thisMonth, prevMonth, c.Last != "", hasRange, dateStr != ""} {
+		if set {
+			count++
+		}
+	}
+	if count > 1 {
+		if count == 2 && c.Last != "" && hasRange {
+			return Criteria{}, ErrLastWithRange
+		}
+		return Criteria{}, ErrConflictingPeriods
+	}
+
+	switch {
+	case yesterday:
+		c.Period = Yesterday(now)
+	case thisWeek || prevWeek:
+		c.Period = Week(now, cfg.WeekStartDay, prevWeek)
+	case thisMonth || prevMonth:
+		c.Period = Month(now, cfg.MonthStartDay, prevMonth)
+	case c.Last != "":
+		c.Period = lastPeriod
+	case hasRange:
+		var start, end time.Time
+		if c.From != "" {
+			from, err := timeutil.ParseDateAt(c.From, now)
+			if err != nil {
+				return Criteria{}, &DateError{Flag: "from", Err: err}
+			}
+			start = from
+		}
+		if c.To != "" {
+			to, err := timeutil.ParseDateAt(c.To, now)
+			if err != nil {
+				return Criteria{}, &DateError{Flag: "to", Err: err}
+			}
+			end = timeutil.EndOfDay(to)
+		} else {
+			end = timeutil.EndOfDay(now)
+		}
+		if !start.IsZero() && start.After(end) {
+			return Criteria{}, fmt.Errorf("--from date (%s) is after --to date (%s)",
+				start.Format("2006-01-02"), end.Format("2006-01-02"))
+		}
+		label := FormatDateRange(start, end)
+		c.Period = Period{Name: label, Label: label, Unit: "period", Start: start, End: end}
+	case dateStr != "":
+		date, err := timeutil.ParseDateAt(dateStr, now)
+		if err != nil {
+			return Criteria{}, &DateError{Flag: "date", Err: err}
+		}
+		end := timeutil.EndOfDay(date)
+		label := FormatDateRange(date, end)
+		c.Period = Period{Name: label, Label: label, Unit: "day", Start: date, End: end}
+	}
+
+	return c, nil
+}
+
+// HasPeriod reports whether the criteria restrict entries to a time period
+func (c Criteria) HasPeriod() bool {
+	return !c.Period.IsZero()
+}
+
+// Filter returns the project, client and tag filters of the criteria
+func (c Criteria) Filter() *filter.Filter {
+	f := filter.NewFilter("", c.Project, c.Tags)
+	f.Client = c.Client
+	return f
+}
+
+// Matches reports whether e lies within the period and matches the filters
+func (c Criteria) Matches(e entry.Entry) bool {
+	if c.HasPeriod() && !c.Period.Contains(e.Timestamp) {
+		return false
+	}
+	return c.Filter().Matches(e)
+}
+
+// Decision is the outcome of one criterion for an entry, see Explain
+type Decision struct {
+	// Criterion is what was checked: "date", "client", "project" or "tag"
+	Criterion string
+	// Matched reports whether the entry passed the check
+	Matched bool
+	// Reason describes the outcome, e.g. "Mon 2024-01-15 09:00 is in this week
+	// (Jan 15 - Jan 21, 2024)" or "has no #review tag"
+	Reason string
+}
+
+// Explain returns the decision of each set criterion for e: the period, then
+// the client, project and each tag. Criteria that are not set are left out,
+// so criteria without a period or filters return none. e matches the
+// criteria (see Matches) when every decision matched.
+func (c Criteria) Explain(e entry.Entry) []Decision {
+	var decisions []Decision
+	if c.HasPeriod() {
+		when := e.Timestamp.Format("Mon 2006-01-02 15:04")
+		d := Decision{Criterion: "date", Matched: c.Period.Contains(e.Timestamp)}
+		switch {
+		case d.Matched:
+			d.Reason = fmt.Sprintf("%s is in %s", when, c.Period.Label)
+		case e.Timestamp.Before(c.Period.Start):
+			d.Reason = fmt.Sprintf("%s is before %s", when, c.Period.Label)
+		default:
+			d.Reason = fmt.Sprintf("%s is after %s", when, c.Period.Label)
+		}
+		decisions = append(decisions, d)
+	}
+
+	f := c.Filter()
+	if f.Client != "" {
+		decisions = append(decisions, explainValue("client", e.Client, f.Client, f.MatchesClient(e)))
+	}
+	if f.Project != "" {
+		decisions = append(decisions, explainValue("project", e.Project, "@"+strings.TrimPrefix(f.Project, "@"), f.MatchesProject(e)))
+	}
+	for _, tag := range f.Tags {
+		d := Decision{Criterion: "tag", Matched: filter.NewFilter("", "", []string{tag}).MatchesTags(e)}
+		if d.Matched {
+			d.Reason = fmt.Sprintf("has the #%s tag", tag)
+		} else {
+			d.Reason = fmt.Sprintf("has no #%s tag", tag)
+		}
+		decisions = append(decisions, d)
+	}
+	return decisions
+}
+
+// explainValue returns the decision comparing the client or project value of
+// an entry with the wanted one
+func explainValue(criterion, value, want string, matched bool) Decision {
+	d := Decision{Criterion: criterion, Matched: matched}
+	switch {
+	case matched:
+		d.Reason = fmt.Sprintf("%s %q is %s", criterion, value, want)
+	case value == "":
+		d.Reason = fmt.Sprintf("has no %s, expected %s", criterion, want)
+	default:
+		d.Reason = fmt.Sprintf("%s %q is not %s", criterion, value, want)
+	}
+	return d
+}
+
+// Apply returns the entries matching the criteria, in their original order
+func (c Criteria) Apply(entries []entry.Entry) []entry.Entry {
+	matching := make([]entry.Entry, 0)
+	for _, e := range entries {
+		if c.Matches(e) {
+			matching = append(matching, e)
+		}
+	}
+	return matching
+}
+
+// HeaderString describes the criteria for a listing header, e.g.
+// "this week (Jan 1 - Jan 7, 2024) (@acme #bugfix)". Criteria without a
+// period are described as "all dates".
+func (c Criteria) HeaderString() string {
+	if !c.HasPeriod() {
+		return c.Describe("all dates")
+	}
+	return c.Describe(c.Period.Label)
+}
+
+// Describe appends the project, client and tag filters of the criteria to a
+// period description, e.g. "today" -> "today (@acme #bugfix)". A client is
+// shown before the project as "@client/project", or as "@client" without a
+// project. Empty filter values are left
+// out and tags are listed once (case-insensitive), so the period is returned
+// unchanged without filters. A period that already ends with the filters is
+// not given them twice.
+func (c Criteria) Describe(period string) string {
+	var filters []string
+	project := strings.TrimSpace(strings.TrimPrefix(c.Project, "@"))
+	if client := strings.TrimSpace(c.Client); client != "" {
+		if project != "" {
+			client += "/" + project
+		}
+		project = client
+	}
+	if project != "" {
+		filters = append(filters, "@"+entry.QuoteName(project))
+	}
+	seen := make(map[string]bool)
+	for _, tag := range c.Tags {
+		tag = strings.TrimSpace(strings.TrimPrefix(tag, "#"))
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		filters = append(filters, "#"+entry.QuoteName(tag))
+	}
+	if len(filters) == 0 {
+		return period
+	}
+
+	suffix := fmt.Sprintf(" (%s)", strings.Join(filters, " "))
+	if strings.HasSuffix(period, suffix) {
+		return period
+	}
+	return period + suffix
+}
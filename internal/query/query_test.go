@@ -0,0 +1,593 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xolan/did/internal/config"
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/timeutil"
+)
+
+// makeEntry creates a test entry at the given time
+func makeEntry(desc string, ts time.Time, project string, tags ...string) entry.Entry {
+	return entry.Entry{
+		Timestamp:       ts,
+		Description:     desc,
+		DurationMinutes: 60,
+		Project:         project,
+		Tags:            tags,
+	}
+}
+
+// descriptions returns the descriptions of entries, in order
+func descriptions(entries []entry.Entry) []string {
+	descs := make([]string, len(entries))
+	for i, e := range entries {
+		descs[i] = e.Description
+	}
+	return descs
+}
+
+// newTestCommand returns a root command with the filter flags and a child
+// command with the given time period flags
+func newTestCommand(periodFlags ...string) *cobra.Command {
+	root := &cobra.Command{Use: "did"}
+	root.PersistentFlags().String("project", "", "")
+	root.PersistentFlags().String("client", "", "")
+	root.PersistentFlags().StringSlice("tag", []string{}, "")
+
+	cmd := &cobra.Command{Use: "test"}
+	root.AddCommand(cmd)
+	for _, name := range periodFlags {
+		switch name {
+		case "last", "from", "to", "date":
+			cmd.Flags().String(name, "", "")
+		default:
+			cmd.Flags().Bool(name, false, "")
+		}
+	}
+	return cmd
+}
+
+func TestCriteria_Apply(t *testing.T) {
+	day := time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local)
+	entries := []entry.Entry{
+		makeEntry("before", day.AddDate(0, 0, -1), "acme", "review"),
+		makeEntry("acme review", day, "acme", "review", "urgent"),
+		makeEntry("acme", day.Add(time.Hour), "ACME"),
+		makeEntry("other", day.Add(2*time.Hour), "other", "review"),
+		makeEntry("none", day.Add(3*time.Hour), ""),
+		makeEntry("after", day.AddDate(0, 0, 1), "acme"),
+	}
+	period := Period{Name: "day", Label: "day", Start: time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local), End: time.Date(2024, 1, 15, 23, 59, 59, 0, time.Local)}
+
+	tests := []struct {
+		name     string
+		criteria Criteria
+		expected []string
+	}{
+		{"empty criteria", Criteria{}, []string{"before", "acme review", "acme", "other", "none", "after"}},
+		{"period only", Criteria{Period: period}, []string{"acme review", "acme", "other", "none"}},
+		{"project is case-insensitive", Criteria{Project: "acme"}, []string{"before", "acme review", "acme", "after"}},
+		{"period and project", Criteria{Period: period, Project: "acme"}, []string{"acme review", "acme"}},
+		{"tag", Criteria{Period: period, Tags: []string{"review"}}, []string{"acme review", "other"}},
+		{"tags use AND logic", Criteria{Tags: []string{"review", "urgent"}}, []string{"acme review"}},
+		{"no match", Criteria{Period: period, Project: "missing"}, []string{}},
+		{"open start", Criteria{Period: Period{Name: "until", End: period.End}}, []string{"before", "acme review", "acme", "other", "none"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := descriptions(tt.criteria.Apply(entries))
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Apply() = %v, expected %v", got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("Apply() = %v, expected %v", got, tt.expected)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestCriteria_ApplyClient(t *testing.T) {
+	entries := []entry.Entry{
+		{Description: "backend", Project: "backend", Client: "AcmeCorp"},
+		{Description: "frontend", Project: "frontend", Client: "acmecorp"},
+		{Description: "other client", Project: "backend", Client: "Globex"},
+		{Description: "no client", Project: "backend"},
+	}
+
+	got := descriptions(Criteria{Client: "AcmeCorp"}.Apply(entries))
+	if len(got) != 2 || got[0] != "backend" || got[1] != "frontend" {
+		t.Errorf("Apply() with client = %v, expected [backend frontend]", got)
+	}
+	got = descriptions(Criteria{Client: "AcmeCorp", Project: "backend"}.Apply(entries))
+	if len(got) != 1 || got[0] != "backend" {
+		t.Errorf("Apply() with client and project = %v, expected [backend]", got)
+	}
+}
+
+func TestCriteria_ApplyEmptyReturnsNonNil(t *testing.T) {
+	if got := (Criteria{Project: "acme"}).Apply(nil); got == nil {
+		t.Error("Apply() returned nil, expected an empty slice")
+	}
+}
+
+func TestCriteria_HeaderString(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria Criteria
+		expected string
+	}{
+		{"period only", Criteria{Period: Period{Name: "today", Label: "today"}}, "today"},
+		{"no period", Criteria{}, "all dates"},
+		{"project", Criteria{Period: Period{Name: "today", Label: "today"}, Project: "acme"}, "today (@acme)"},
+		{"project and tags", Criteria{Period: Period{Name: "w", Label: "this week (Jan 1 - Jan 7, 2024)"}, Project: "acme", Tags: []string{"a", "b"}}, "this week (Jan 1 - Jan 7, 2024) (@acme #a #b)"},
+		{"tags without period", Criteria{Tags: []string{"review"}}, "all dates (#review)"},
+		{"client and project", Criteria{Period: Period{Name: "today", Label: "today"}, Project: "backend", Client: "AcmeCorp", Tags: []string{"a"}}, "today (@AcmeCorp/backend #a)"},
+		{"client only", Criteria{Period: Period{Name: "today", Label: "today"}, Client: "AcmeCorp"}, "today (@AcmeCorp)"},
+		{"multi-word names", Criteria{Period: Period{Name: "today", Label: "today"}, Project: "Acme North America", Tags: []string{"big tag"}}, `today (@"Acme North America" #"big tag")`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.criteria.HeaderString(); got != tt.expected {
+				t.Errorf("HeaderString() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCriteria_Describe(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	periods := []struct {
+		name   string
+		period string
+	}{
+		{"today", Today(time.Now()).Label},
+		{"yesterday", Yesterday(time.Now()).Label},
+		{"week", Week(time.Now(), "monday", false).Label},
+		{"month", Month(time.Now(), 1, true).Label},
+		{"date", FormatDateRange(day, day)},
+		{"range", FormatDateRange(day, day.AddDate(0, 0, 6))},
+	}
+	filters := []struct {
+		name     string
+		project  string
+		tags     []string
+		expected string // suffix after the period
+	}{
+		{"none", "", nil, ""},
+		{"empty project", "", []string{}, ""},
+		{"empty tag", "", []string{""}, ""},
+		{"bare shorthand", "@", []string{"#", " "}, ""},
+		{"project", "acme", nil, " (@acme)"},
+		{"project from shorthand", "@acme", nil, " (@acme)"},
+		{"tag", "", []string{"bug"}, " (#bug)"},
+		{"multiple tags", "", []string{"bug", "urgent"}, " (#bug #urgent)"},
+		{"tags from flag and shorthand", "", []string{"bug", "#bug", "BUG", "urgent"}, " (#bug #urgent)"},
+		{"project and tags", "acme", []string{"bug", "", "urgent"}, " (@acme #bug #urgent)"},
+	}
+
+	for _, p := range periods {
+		for _, f := range filters {
+			t.Run(p.name+"/"+f.name, func(t *testing.T) {
+				c := Criteria{Project: f.project, Tags: f.tags}
+				expected := p.period + f.expected
+				if got := c.Describe(p.period); got != expected {
+					t.Errorf("Describe(%q) = %q, expected %q", p.period, got, expected)
+				}
+				// Describing an already described period adds nothing
+				if got := c.Describe(expected); got != expected {
+					t.Errorf("Describe(%q) = %q, expected it unchanged", expected, got)
+				}
+			})
+		}
+	}
+}
+
+func TestPeriod_Previous(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.Local) }
+	endOf := func(y int, m time.Month, d int) time.Time { return day(y, m, d).Add(24*time.Hour - time.Nanosecond) }
+
+	tests := []struct {
+		name      string
+		period    Period
+		wantStart time.Time
+		wantEnd   time.Time
+		wantOK    bool
+	}{
+		{"week", Period{Name: "w", Unit: "week", Start: day(2024, 1, 8), End: endOf(2024, 1, 14)}, day(2024, 1, 1), endOf(2024, 1, 7), true},
+		{"month", Period{Name: "m", Unit: "month", Start: day(2024, 3, 1), End: endOf(2024, 3, 31)}, day(2024, 2, 1), endOf(2024, 2, 29), true},
+		{"shifted month", Period{Name: "m", Unit: "month", Start: day(2024, 1, 25), End: endOf(2024, 2, 24)}, day(2023, 12, 25), endOf(2024, 1, 24), true},
+		{"shifted month after February", Period{Name: "m", Unit: "month", Start: day(2023, 3, 28), End: endOf(2023, 4, 27)}, day(2023, 2, 28), endOf(2023, 3, 27), true},
+		{"day", Period{Name: "d", Unit: "day", Start: day(2024, 1, 1), End: endOf(2024, 1, 1)}, day(2023, 12, 31), endOf(2023, 12, 31), true},
+		{"days", Period{Name: "p", Unit: "period", Start: day(2024, 1, 10), End: endOf(2024, 1, 16)}, day(2024, 1, 3), endOf(2024, 1, 9), true},
+		{"open start", Period{Name: "p", Unit: "period", End: endOf(2024, 1, 16)}, time.Time{}, time.Time{}, false},
+		{"zero", Period{}, time.Time{}, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev, ok := tt.period.Previous()
+			if ok != tt.wantOK {
+				t.Fatalf("Previous() ok = %v, expected %v", ok, tt.wantOK)
+			}
+			if ok && (!prev.Start.Equal(tt.wantStart) || !prev.End.Equal(tt.wantEnd)) {
+				t.Errorf("Previous() = %v - %v, expected %v - %v", prev.Start, prev.End, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestCriteria_Explain(t *testing.T) {
+	day := time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local)
+	period := relativePeriod("today", "day", timeutil.StartOfDay(day), timeutil.EndOfDay(day))
+	c := Criteria{Period: period, Client: "AcmeCorp", Project: "acme", Tags: []string{"review", "urgent"}}
+
+	tests := []struct {
+		name    string
+		entry   entry.Entry
+		reasons []string
+	}{
+		{"matching", func() entry.Entry {
+			e := makeEntry("acme review", day, "ACME", "Review", "urgent")
+			e.Client = "acmecorp"
+			return e
+		}(), []string{
+			"+ Mon 2024-01-15 10:00 is in " + period.Label,
+			`+ client "acmecorp" is AcmeCorp`,
+			`+ project "ACME" is @acme`,
+			"+ has the #review tag",
+			"+ has the #urgent tag",
+		}},
+		{"excluded", makeEntry("other", day.AddDate(0, 0, 1), "other", "review"), []string{
+			"- Tue 2024-01-16 10:00 is after " + period.Label,
+			"- has no client, expected AcmeCorp",
+			`- project "other" is not @acme`,
+			"+ has the #review tag",
+			"- has no #urgent tag",
+		}},
+		{"before", makeEntry("early", day.AddDate(0, 0, -1), ""), []string{
+			"- Sun 2024-01-14 10:00 is before " + period.Label,
+			"- has no client, expected AcmeCorp",
+			"- has no project, expected @acme",
+			"- has no #review tag",
+			"- has no #urgent tag",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decisions := c.Explain(tt.entry)
+			var reasons []string
+			allMatched := true
+			for _, d := range decisions {
+				mark := "+ "
+				if !d.Matched {
+					mark, allMatched = "- ", false
+				}
+				reasons = append(reasons, mark+d.Reason)
+			}
+			if strings.Join(reasons, "\n") != strings.Join(tt.reasons, "\n") {
+				t.Errorf("Explain() =\n%s\nexpected\n%s", strings.Join(reasons, "\n"), strings.Join(tt.reasons, "\n"))
+			}
+			if allMatched != c.Matches(tt.entry) {
+				t.Errorf("Explain() all matched = %t, but Matches() = %t", allMatched, c.Matches(tt.entry))
+			}
+		})
+	}
+
+	if decisions := (Criteria{}).Explain(makeEntry("any", day, "acme")); len(decisions) != 0 {
+		t.Errorf("Explain() without criteria = %+v, expected no decisions", decisions)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	allFlags := []string{"yesterday", "this-week", "prev-week", "this-month", "prev-month", "last", "from", "to", "date"}
+
+	tests := []struct {
+		name      string
+		flags     map[string]string
+		wantName  string
+		wantUnit  string
+		wantStart string
+		wantEnd   string
+	}{
+		{"no period", nil, "", "", "", ""},
+		{"yesterday", map[string]string{"yesterday": "true"}, "yesterday", "day", "", ""},
+		{"this week", map[string]string{"this-week": "true"}, "this week", "week", "", ""},
+		{"prev month", map[string]string{"prev-month": "true"}, "previous month", "month", "", ""},
+		{"last", map[string]string{"last": "7"}, "last 7 days", "period", "", ""},
+		{"last one", map[string]string{"last": "1"}, "last 1 day", "period", "", ""},
+		{"last zero", map[string]string{"last": "0"}, "", "", "", ""},
+		{"last weeks", map[string]string{"last": "2w"}, "last 2 weeks", "period", time.Now().AddDate(0, 0, -13).Format("2006-01-02"), time.Now().Format("2006-01-02")},
+		{"last month", map[string]string{"last": "1m"}, "last 1 month", "period", "", time.Now().Format("2006-01-02")},
+		{"from to", map[string]string{"from": "2024-01-01", "to": "2024-01-31"}, "Mon Jan 1 - Wed Jan 31, 2024", "period", "2024-01-01", "2024-01-31"},
+		{"from to across years", map[string]string{"from": "2023-12-28", "to": "2024-01-03"}, "Thu Dec 28, 2023 - Wed Jan 3, 2024", "period", "2023-12-28", "2024-01-03"},
+		{"to only", map[string]string{"to": "2024-01-21"}, "until Jan 21, 2024", "period", "", "2024-01-21"},
+		{"date", map[string]string{"date": "15/01/2024"}, "Mon, Jan 15, 2024", "day", "2024-01-15", "2024-01-15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newTestCommand(allFlags...)
+			for name, value := range tt.flags {
+				_ = cmd.Flags().Set(name, value)
+			}
+
+			c, err := Resolve(cmd, config.DefaultConfig())
+			if err != nil {
+				t.Fatalf("Resolve() returned error: %v", err)
+			}
+			if c.Period.Name != tt.wantName || c.Period.Unit != tt.wantUnit {
+				t.Errorf("Resolve() period = %q (%q), expected %q (%q)", c.Period.Name, c.Period.Unit, tt.wantName, tt.wantUnit)
+			}
+			if tt.wantStart != "" && c.Period.Start.Format("2006-01-02") != tt.wantStart {
+				t.Errorf("Resolve() start = %v, expected %s", c.Period.Start, tt.wantStart)
+			}
+			if tt.wantEnd != "" && c.Period.End.Format("2006-01-02") != tt.wantEnd {
+				t.Errorf("Resolve() end = %v, expected %s", c.Period.End, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestResolve_MonthStartDay(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.MonthStartDay = 25
+	start := timeutil.StartOfMonthWithConfig(time.Now(), 25)
+	end := timeutil.EndOfMonthWithConfig(time.Now(), 25)
+
+	cmd := newTestCommand("this-month", "prev-month")
+	_ = cmd.Flags().Set("this-month", "true")
+	c, err := Resolve(cmd, cfg)
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+	if !c.Period.Start.Equal(start) || !c.Period.End.Equal(end) {
+		t.Errorf("Resolve() period = %v - %v, expected %v - %v", c.Period.Start, c.Period.End, start, end)
+	}
+	if expected := "this month (" + FormatDateRange(start, end) + ")"; c.Period.Label != expected {
+		t.Errorf("Resolve() label = %q, expected %q", c.Period.Label, expected)
+	}
+	if prev, ok := c.Period.Previous(); !ok || !prev.End.Add(time.Nanosecond).Equal(start) || prev.Start.Day() != 25 {
+		t.Errorf("Previous() = %v - %v, expected the month before %v", prev.Start, prev.End, start)
+	}
+}
+
+func TestResolve_Timezone(t *testing.T) {
+	// Kiritimati (UTC+14) and Pago Pago (UTC-11) are on different dates for
+	// most of the day, so periods computed in the host zone would disagree
+	for _, tz := range []string{"Pacific/Kiritimati", "Pacific/Pago_Pago"} {
+		t.Run(tz, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Timezone = tz
+			now, err := timeutil.NowIn(tz)
+			if err != nil {
+				t.Fatalf("NowIn(%q) returned error: %v", tz, err)
+			}
+			today := timeutil.StartOfDay(now)
+
+			cmd := newTestCommand("yesterday", "date")
+			_ = cmd.Flags().Set("yesterday", "true")
+			c, err := Resolve(cmd, cfg)
+			if err != nil {
+				t.Fatalf("Resolve() returned error: %v", err)
+			}
+			if expected := today.AddDate(0, 0, -1); !c.Period.Start.Equal(expected) {
+				t.Errorf("--yesterday start = %v, expected %v", c.Period.Start, expected)
+			}
+
+			cmd = newTestCommand("yesterday", "date")
+			_ = cmd.Flags().Set("date", "yesterday")
+			d, err := Resolve(cmd, cfg)
+			if err != nil {
+				t.Fatalf("Resolve() returned error: %v", err)
+			}
+			if !d.Period.Start.Equal(c.Period.Start) || !d.Period.End.Equal(c.Period.End) {
+				t.Errorf("--date yesterday = %v - %v, expected --yesterday %v - %v", d.Period.Start, d.Period.End, c.Period.Start, c.Period.End)
+			}
+		})
+	}
+}
+
+func TestToday_ConfiguredTimezone(t *testing.T) {
+	kiritimati := timeutil.MustLoadTimezone("Pacific/Kiritimati")
+	now := time.Date(2024, 1, 15, 23, 30, 0, 0, time.UTC).In(kiritimati)
+
+	p := Today(now)
+	if expected := time.Date(2024, 1, 16, 0, 0, 0, 0, kiritimati); !p.Start.Equal(expected) {
+		t.Errorf("Today() start = %v, expected %v", p.Start, expected)
+	}
+	if p.Start.Location() != kiritimati {
+		t.Errorf("Today() location = %v, expected %v", p.Start.Location(), kiritimati)
+	}
+}
+
+func TestResolve_Filters(t *testing.T) {
+	cmd := newTestCommand()
+	_ = cmd.Root().PersistentFlags().Set("project", "acme")
+	_ = cmd.Root().PersistentFlags().Set("client", "AcmeCorp")
+	_ = cmd.Root().PersistentFlags().Set("tag", "review")
+
+	c, err := Resolve(cmd, config.DefaultConfig())
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+	if c.Project != "acme" || c.Client != "AcmeCorp" || len(c.Tags) != 1 || c.Tags[0] != "review" {
+		t.Errorf("Resolve() filters = %q %q %v, expected acme AcmeCorp [review]", c.Project, c.Client, c.Tags)
+	}
+	if c.HasPeriod() {
+		t.Errorf("Resolve() on a command without period flags set a period: %+v", c.Period)
+	}
+}
+
+func TestResolve_Errors(t *testing.T) {
+	allFlags := []string{"yesterday", "this-week", "prev-week", "this-month", "prev-month", "last", "from", "to", "date"}
+
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		wantErr error
+		wantMsg string
+	}{
+		{"conflicting periods", map[string]string{"yesterday": "true", "this-week": "true"}, ErrConflictingPeriods, ""},
+		{"last with from", map[string]string{"last": "7", "from": "2024-01-01"}, ErrLastWithRange, ""},
+		{"last weeks with to", map[string]string{"last": "2w", "to": "2024-01-01"}, ErrLastWithRange, ""},
+		{"last months with yesterday", map[string]string{"last": "3m", "yesterday": "true"}, ErrConflictingPeriods, ""},
+		{"invalid last", map[string]string{"last": "2x"}, nil, `invalid --last value "2x"`},
+		{"last with from and date", map[string]string{"last": "7", "from": "2024-01-01", "date": "2024-01-01"}, ErrConflictingPeriods, ""},
+		{"invalid from", map[string]string{"from": "nope"}, nil, "invalid --from date"},
+		{"invalid to", map[string]string{"to": "nope"}, nil, "invalid --to date"},
+		{"invalid date", map[string]string{"date": "nope"}, nil, "invalid --date value"},
+		{"from after to", map[string]string{"from": "2024-02-01", "to": "2024-01-01"}, nil, "--from date (2024-02-01) is after --to date (2024-01-01)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newTestCommand(allFlags...)
+			for name, value := range tt.flags {
+				_ = cmd.Flags().Set(name, value)
+			}
+
+			_, err := Resolve(cmd, config.DefaultConfig())
+			if err == nil {
+				t.Fatal("Resolve() expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Resolve() error = %v, expected %v", err, tt.wantErr)
+			}
+			if tt.wantMsg != "" && !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Resolve() error = %q, expected it to contain %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestResolve_InvalidTimezone(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Timezone = "Europe/Olso"
+
+	_, err := Resolve(newTestCommand("yesterday"), cfg)
+	if err == nil || !strings.Contains(err.Error(), "'Europe/Olso' is not a valid IANA timezone") {
+		t.Errorf("Resolve() error = %v, expected an invalid timezone error", err)
+	}
+}
+
+func TestResolve_DateErrorUnwraps(t *testing.T) {
+	cmd := newTestCommand("from")
+	_ = cmd.Flags().Set("from", "nope")
+
+	_, err := Resolve(cmd, config.DefaultConfig())
+	var dateErr *DateError
+	if !errors.As(err, &dateErr) || dateErr.Flag != "from" || dateErr.Err == nil {
+		t.Errorf("Resolve() error = %v, expected a DateError for --from", err)
+	}
+}
+
+func TestParseLast(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantName string
+		wantDays int // days covered, 0 to skip the check
+	}{
+		{"", "", 0},
+		{"0", "", 0},
+		{"1", "last 1 day", 1},
+		{"7", "last 7 days", 7},
+		{"+7", "last 7 days", 7},
+		{"1w", "last 1 week", 7},
+		{"2w", "last 2 weeks", 14},
+		{"1m", "last 1 month", 0},
+		{"3m", "last 3 months", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			p, err := ParseLast(tt.value, time.Now())
+			if err != nil {
+				t.Fatalf("ParseLast(%q) returned error: %v", tt.value, err)
+			}
+			if p.Name != tt.wantName {
+				t.Errorf("ParseLast(%q) = %q, expected %q", tt.value, p.Name, tt.wantName)
+			}
+			if tt.wantDays > 0 {
+				if days := int(p.End.Sub(p.Start).Hours()/24 + 0.5); days != tt.wantDays {
+					t.Errorf("ParseLast(%q) covers %d days, expected %d", tt.value, days, tt.wantDays)
+				}
+			}
+		})
+	}
+}
+
+func TestParseLast_Invalid(t *testing.T) {
+	for _, value := range []string{"-3", "0w", "2x", "w", "m", "2 w", "1.5", "2wk", "7d"} {
+		t.Run(value, func(t *testing.T) {
+			_, err := ParseLast(value, time.Now())
+			var lastErr *LastError
+			if !errors.As(err, &lastErr) || lastErr.Value != value {
+				t.Fatalf("ParseLast(%q) error = %v, expected a LastError", value, err)
+			}
+			if !strings.Contains(err.Error(), "(7), weeks (2w) or months (3m)") {
+				t.Errorf("Expected the error to list the accepted forms, got %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestMonthsStart(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 15, 30, 0, 0, time.UTC) }
+	tests := []struct {
+		name     string
+		day      time.Time
+		n        int
+		expected string
+	}{
+		{"mid month", day(2024, 10, 15), 1, "2024-09-16"},
+		{"several months", day(2024, 10, 15), 3, "2024-07-16"},
+		{"across a year", day(2024, 2, 10), 2, "2023-12-11"},
+		{"end of a long month", day(2024, 3, 31), 1, "2024-03-01"},
+		{"end of a short month", day(2024, 2, 29), 1, "2024-01-30"},
+		{"day missing in a leap February", day(2024, 5, 31), 3, "2024-03-01"},
+		{"a year", day(2024, 1, 31), 12, "2023-02-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := monthsStart(tt.day, tt.n)
+			if got.Format("2006-01-02 15:04") != tt.expected+" 00:00" {
+				t.Errorf("monthsStart(%s, %d) = %v, expected %s", tt.day.Format("2006-01-02"), tt.n, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatDateRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    time.Time
+		end      time.Time
+		expected string
+	}{
+		{"same day", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 15, 23, 59, 0, 0, time.UTC), "Mon, Jan 15, 2024"},
+		{"same year", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), "Mon Jan 1 - Wed Jan 31, 2024"},
+		{"different years", time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), "Mon Dec 25, 2023 - Fri Jan 5, 2024"},
+		{"no start", time.Time{}, time.Date(2024, 1, 21, 23, 59, 0, 0, time.UTC), "until Jan 21, 2024"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDateRange(tt.start, tt.end); got != tt.expected {
+				t.Errorf("FormatDateRange() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
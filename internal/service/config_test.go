@@ -65,8 +65,9 @@ func TestConfigService_Update(t *testing.T) {
 
 	// Update config
 	newCfg := config.Config{
-		WeekStartDay: "sunday",
-		Timezone:     "America/New_York",
+		WeekStartDay:  "sunday",
+		Timezone:      "America/New_York",
+		MonthStartDay: 1,
 	}
 
 	err := svc.Update(newCfg)
@@ -104,8 +105,9 @@ func TestConfigService_Update_InvalidConfig(t *testing.T) {
 
 	// Invalid week start day
 	invalidCfg := config.Config{
-		WeekStartDay: "invalid",
-		Timezone:     "Local",
+		WeekStartDay:  "invalid",
+		Timezone:      "Local",
+		MonthStartDay: 1,
 	}
 
 	err := svc.Update(invalidCfg)
@@ -208,8 +210,9 @@ func TestConfigService_writeConfig(t *testing.T) {
 	svc := NewConfigService(configPath, config.DefaultConfig())
 
 	cfg := config.Config{
-		WeekStartDay: "sunday",
-		Timezone:     "Europe/London",
+		WeekStartDay:  "sunday",
+		Timezone:      "Europe/London",
+		MonthStartDay: 1,
 	}
 
 	err := svc.writeConfig(cfg)
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/xolan/did/internal/config"
@@ -16,8 +15,9 @@ import (
 
 // Common errors for the entry service
 var (
-	ErrMissingDuration    = errors.New("missing 'for <duration>' in input")
+	ErrMissingDuration    = errors.New("missing duration in input")
 	ErrEmptyDescription   = errors.New("description cannot be empty")
+	ErrDurationOnly       = errors.New("description cannot be just a duration")
 	ErrInvalidIndex       = errors.New("invalid entry index")
 	ErrIndexOutOfRange    = errors.New("index out of range")
 	ErrNoEntries          = errors.New("no entries found")
@@ -39,17 +39,24 @@ func NewEntryService(storagePath string, cfg config.Config) *EntryService {
 	}
 }
 
+// DurationKeyword returns the configured word separating the description from
+// the duration in the input of Create
+func (s *EntryService) DurationKeyword() string {
+	return s.config.EffectiveDurationKeyword()
+}
+
 // Create creates a new time tracking entry from a raw input string.
 // Input format: "<description> for <duration>" (e.g., "fix bug @acme for 2h")
 func (s *EntryService) Create(rawInput string) (*entry.Entry, error) {
 	// Parse the input: expected format "<description> for <duration>"
-	lastForIdx := strings.LastIndex(strings.ToLower(rawInput), " for ")
-	if lastForIdx == -1 {
-		return nil, ErrMissingDuration
+	keyword := s.DurationKeyword()
+	description, durationStr, found := entry.SplitAtDurationKeyword(rawInput, keyword)
+	if !found && entry.IsDurationOnly(rawInput, keyword) {
+		return nil, ErrDurationOnly
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: use '<description> %s <duration>'", ErrMissingDuration, keyword)
 	}
-
-	description := strings.TrimSpace(rawInput[:lastForIdx])
-	durationStr := strings.TrimSpace(rawInput[lastForIdx+5:]) // +5 for " for "
 
 	if description == "" {
 		return nil, ErrEmptyDescription
@@ -63,24 +70,33 @@ func (s *EntryService) Create(rawInput string) (*entry.Entry, error) {
 		return nil, ErrEmptyDescription
 	}
 
+	// Reject descriptions that are only a duration, e.g. "2h for 2h"
+	cleanDesc = entry.TrimLeadingDurationClauses(cleanDesc, keyword)
+	if cleanDesc == "" || entry.IsDurationOnly(cleanDesc, keyword) {
+		return nil, ErrDurationOnly
+	}
+
 	// Parse the duration
-	minutes, err := entry.ParseDuration(durationStr)
+	minutes, err := s.parseDuration(durationStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid duration '%s': %w", durationStr, err)
 	}
 
 	// Create the entry
+	now := time.Now()
 	e := entry.Entry{
-		Timestamp:       time.Now(),
+		Timestamp:       now,
 		Description:     cleanDesc,
 		DurationMinutes: minutes,
 		RawInput:        rawInput,
 		Project:         project,
 		Tags:            tags,
+		LoggedAt:        &now,
 	}
+	s.config.ApplyEntryDefaults(&e)
 
 	// Append the entry to storage
-	if err := storage.AppendEntry(s.storagePath, e); err != nil {
+	if err := storage.AppendEntryWithOptions(s.storagePath, e, storage.AppendOptions{Sync: s.config.DurableWrites}); err != nil {
 		return nil, fmt.Errorf("failed to save entry: %w", err)
 	}
 
@@ -93,22 +109,25 @@ func (s *EntryService) CreateFromParts(description string, durationMinutes int,
 		return nil, ErrEmptyDescription
 	}
 
-	if durationMinutes <= 0 || durationMinutes > entry.MaxDurationMinutes {
-		return nil, fmt.Errorf("invalid duration: must be 1-%d minutes", entry.MaxDurationMinutes)
+	if maxMinutes := s.config.MaxEntryMinutes(); durationMinutes <= 0 || durationMinutes > maxMinutes {
+		return nil, fmt.Errorf("invalid duration: must be 1-%d minutes", maxMinutes)
 	}
 
 	// Create the entry
+	now := time.Now()
 	e := entry.Entry{
-		Timestamp:       time.Now(),
+		Timestamp:       now,
 		Description:     description,
 		DurationMinutes: durationMinutes,
 		RawInput:        fmt.Sprintf("%s for %dm", description, durationMinutes),
 		Project:         project,
 		Tags:            tags,
+		LoggedAt:        &now,
 	}
+	s.config.ApplyEntryDefaults(&e)
 
 	// Append the entry to storage
-	if err := storage.AppendEntry(s.storagePath, e); err != nil {
+	if err := storage.AppendEntryWithOptions(s.storagePath, e, storage.AppendOptions{Sync: s.config.DurableWrites}); err != nil {
 		return nil, fmt.Errorf("failed to save entry: %w", err)
 	}
 
@@ -118,7 +137,10 @@ func (s *EntryService) CreateFromParts(description string, durationMinutes int,
 // List returns entries for the specified date range and filter
 func (s *EntryService) List(dateRange DateRangeSpec, f *filter.Filter) (*ListResult, error) {
 	// Get the time range
-	start, end, period := s.resolveDateRange(dateRange)
+	start, end, period, err := s.resolveDateRange(dateRange)
+	if err != nil {
+		return nil, err
+	}
 
 	// Read all entries with warnings
 	result, err := storage.ReadEntriesWithWarnings(s.storagePath)
@@ -232,7 +254,7 @@ func (s *EntryService) Edit(userIndex int, newDescription, newDuration string) (
 
 	// Update duration if provided
 	if newDuration != "" {
-		minutes, err := entry.ParseDuration(newDuration)
+		minutes, err := s.parseDuration(newDuration)
 		if err != nil {
 			return nil, fmt.Errorf("invalid duration '%s': %w", newDuration, err)
 		}
@@ -352,15 +374,18 @@ func (s *EntryService) GetActiveCount() (int, error) {
 }
 
 // resolveDateRange converts a DateRangeSpec to concrete start/end times and a period description
-func (s *EntryService) resolveDateRange(spec DateRangeSpec) (start, end time.Time, period string) {
-	now := time.Now()
+func (s *EntryService) resolveDateRange(spec DateRangeSpec) (start, end time.Time, period string, err error) {
+	now, err := timeutil.NowIn(s.config.Timezone)
+	if err != nil {
+		return start, end, period, err
+	}
 
 	switch spec.Type {
 	case DateRangeToday:
-		start, end = timeutil.Today()
+		start, end = timeutil.Today(now)
 		period = "today"
 	case DateRangeYesterday:
-		start, end = timeutil.Yesterday()
+		start, end = timeutil.Yesterday(now)
 		period = "yesterday"
 	case DateRangeThisWeek:
 		start = timeutil.StartOfWeekWithConfig(now, s.config.WeekStartDay)
@@ -372,10 +397,10 @@ func (s *EntryService) resolveDateRange(spec DateRangeSpec) (start, end time.Tim
 		end = timeutil.EndOfWeekWithConfig(start, s.config.WeekStartDay)
 		period = "last week"
 	case DateRangeThisMonth:
-		start, end = timeutil.ThisMonth()
+		start, end = timeutil.ThisMonthWithConfig(now, s.config.MonthStartDay)
 		period = "this month"
 	case DateRangePrevMonth:
-		start, end = timeutil.LastMonth()
+		start, end = timeutil.LastMonthWithConfig(now, s.config.MonthStartDay)
 		period = "last month"
 	case DateRangeLast:
 		end = timeutil.EndOfDay(now)
@@ -386,11 +411,27 @@ func (s *EntryService) resolveDateRange(spec DateRangeSpec) (start, end time.Tim
 		end = spec.To
 		period = formatDateRangeForDisplay(start, end)
 	default:
-		start, end = timeutil.Today()
+		start, end = timeutil.Today(now)
 		period = "today"
 	}
 
-	return start, end, period
+	return start, end, period, nil
+}
+
+// parseDuration parses the duration of a new or edited entry, which must be
+// greater than zero and at most max_entry_duration
+func (s *EntryService) parseDuration(input string) (int, error) {
+	minutes, err := entry.ParseDurationMinutes(input)
+	if err != nil {
+		return 0, err
+	}
+	if minutes == 0 {
+		return 0, errors.New("invalid duration: duration cannot be zero")
+	}
+	if maxMinutes := s.config.MaxEntryMinutes(); minutes > maxMinutes {
+		return 0, fmt.Errorf("invalid duration: exceeds maximum of %s (%d minutes)", timeutil.FormatDuration(maxMinutes), maxMinutes)
+	}
+	return minutes, nil
 }
 
 // buildRawInput reconstructs the raw input string from entry fields
@@ -402,7 +443,7 @@ func (s *EntryService) buildRawInput(e entry.Entry) string {
 	for _, tag := range e.Tags {
 		desc += " #" + tag
 	}
-	return fmt.Sprintf("%s for %s", desc, formatDurationSimple(e.DurationMinutes))
+	return fmt.Sprintf("%s for %s", desc, timeutil.FormatDuration(e.DurationMinutes))
 }
 
 // formatDateRangeForDisplay formats a date range for human-readable display
@@ -415,16 +456,3 @@ func formatDateRangeForDisplay(start, end time.Time) string {
 	}
 	return fmt.Sprintf("%s - %s", start.Format("Jan 2, 2006"), end.Format("Jan 2, 2006"))
 }
-
-// formatDurationSimple formats minutes as a simple duration string
-func formatDurationSimple(minutes int) string {
-	if minutes < 60 {
-		return fmt.Sprintf("%dm", minutes)
-	}
-	hours := minutes / 60
-	mins := minutes % 60
-	if mins == 0 {
-		return fmt.Sprintf("%dh", hours)
-	}
-	return fmt.Sprintf("%dh%dm", hours, mins)
-}
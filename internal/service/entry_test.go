@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -61,6 +62,26 @@ func TestEntryService_Create(t *testing.T) {
 			input:   "@acme #tag for 2h",
 			wantErr: ErrEmptyDescription,
 		},
+		{
+			name:    "duration as description",
+			input:   "2h for 2h",
+			wantErr: ErrDurationOnly,
+		},
+		{
+			name:    "duplicated for clause",
+			input:   "for 2h for 2h",
+			wantErr: ErrDurationOnly,
+		},
+		{
+			name:    "only for clause",
+			input:   "for 2h",
+			wantErr: ErrDurationOnly,
+		},
+		{
+			name:    "for attached to words",
+			input:   "waited for 2h on CI for 30m",
+			wantErr: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -87,6 +108,28 @@ func TestEntryService_Create(t *testing.T) {
 	}
 }
 
+func TestEntryService_Create_DurationKeyword(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DurationKeyword = "pendant"
+	svc := NewEntryService(filepath.Join(t.TempDir(), "entries.jsonl"), cfg)
+
+	if svc.DurationKeyword() != "pendant" {
+		t.Errorf("expected keyword 'pendant', got %q", svc.DurationKeyword())
+	}
+
+	e, err := svc.Create("revue de code @acme pendant 30m")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.Description != "revue de code" || e.Project != "acme" || e.DurationMinutes != 30 {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+
+	if _, err := svc.Create("revue de code for 30m"); !errors.Is(err, ErrMissingDuration) {
+		t.Errorf("expected ErrMissingDuration for the default keyword, got %v", err)
+	}
+}
+
 func TestEntryService_Create_InvalidDuration(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -98,6 +141,31 @@ func TestEntryService_Create_InvalidDuration(t *testing.T) {
 	}
 }
 
+func TestEntryService_MaxEntryDuration(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.MaxEntryDuration = "8h"
+	svc := NewEntryService(filepath.Join(t.TempDir(), "entries.jsonl"), cfg)
+
+	if _, err := svc.Create("workshop for 9h"); err == nil {
+		t.Error("expected error for a duration over max_entry_duration")
+	}
+	if _, err := svc.CreateFromParts("workshop", 9*60, "", nil); err == nil {
+		t.Error("expected error for a duration over max_entry_duration")
+	}
+	if _, err := svc.Create("workshop for 8h"); err != nil {
+		t.Fatalf("unexpected error at max_entry_duration: %v", err)
+	}
+	if _, err := svc.Edit(1, "", "9h"); err == nil {
+		t.Error("expected error when editing to a duration over max_entry_duration")
+	}
+
+	cfg.MaxEntryDuration = "48h"
+	svc = NewEntryService(filepath.Join(t.TempDir(), "entries.jsonl"), cfg)
+	if _, err := svc.Create("hackathon for 30h"); err != nil {
+		t.Errorf("unexpected error under a raised max_entry_duration: %v", err)
+	}
+}
+
 func TestEntryService_CreateFromParts(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -586,28 +654,6 @@ func TestFormatDateRangeForDisplay(t *testing.T) {
 	}
 }
 
-func TestFormatDurationSimple(t *testing.T) {
-	tests := []struct {
-		minutes int
-		want    string
-	}{
-		{30, "30m"},
-		{60, "1h"},
-		{90, "1h30m"},
-		{120, "2h"},
-		{150, "2h30m"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.want, func(t *testing.T) {
-			result := formatDurationSimple(tt.minutes)
-			if result != tt.want {
-				t.Errorf("formatDurationSimple(%d) = %q, want %q", tt.minutes, result, tt.want)
-			}
-		})
-	}
-}
-
 func TestEntryService_List_ReadError(t *testing.T) {
 	// Use invalid path - empty file is created automatically
 	tmpDir := t.TempDir()
@@ -659,7 +705,7 @@ func TestEntryService_buildRawInput(t *testing.T) {
 				DurationMinutes: 90,
 				Tags:            []string{"urgent", "bug"},
 			},
-			want: "task #urgent #bug for 1h30m",
+			want: "task #urgent #bug for 1h 30m",
 		},
 		{
 			name: "with project and tags",
@@ -755,17 +801,12 @@ func TestEntryService_Delete_StorageError(t *testing.T) {
 }
 
 func TestEntryService_GetActiveCount_StorageError(t *testing.T) {
-	// Test with a directory path to cause read error
-	tmpDir := t.TempDir()
-	storagePath := filepath.Join(tmpDir, "entries.jsonl")
-	if err := os.MkdirAll(storagePath, 0755); err != nil {
-		t.Fatal(err)
-	}
+	storagePath := unreadableStoragePath(t)
 
 	svc := NewEntryService(storagePath, config.DefaultConfig())
 	_, err := svc.GetActiveCount()
 	if err == nil {
-		t.Error("expected error when storage path is a directory")
+		t.Error("expected error when storage path cannot be read")
 	}
 }
 
@@ -787,3 +828,14 @@ func contains(s, substr string) bool {
 	}
 	return false
 }
+
+// unreadableStoragePath returns a storage path below a regular file, so every
+// attempt to read it fails
+func unreadableStoragePath(t *testing.T) string {
+	t.Helper()
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(blocker, "entries.jsonl")
+}
@@ -28,7 +28,10 @@ func NewReportService(storagePath string, cfg config.Config) *ReportService {
 
 // ByProject generates a report for a specific project
 func (s *ReportService) ByProject(project string, dateRange DateRangeSpec) (*ReportData, error) {
-	start, end, period := s.resolveDateRange(dateRange)
+	start, end, period, err := s.resolveDateRange(dateRange)
+	if err != nil {
+		return nil, err
+	}
 
 	entries, err := s.loadActiveEntries()
 	if err != nil {
@@ -66,7 +69,10 @@ func (s *ReportService) ByProject(project string, dateRange DateRangeSpec) (*Rep
 
 // ByTags generates a report for entries matching specific tags
 func (s *ReportService) ByTags(tags []string, dateRange DateRangeSpec) (*ReportData, error) {
-	start, end, period := s.resolveDateRange(dateRange)
+	start, end, period, err := s.resolveDateRange(dateRange)
+	if err != nil {
+		return nil, err
+	}
 
 	entries, err := s.loadActiveEntries()
 	if err != nil {
@@ -99,7 +105,10 @@ func (s *ReportService) ByTags(tags []string, dateRange DateRangeSpec) (*ReportD
 
 // GroupByProject generates a report grouped by project
 func (s *ReportService) GroupByProject(dateRange DateRangeSpec) (*ReportData, error) {
-	start, end, period := s.resolveDateRange(dateRange)
+	start, end, period, err := s.resolveDateRange(dateRange)
+	if err != nil {
+		return nil, err
+	}
 
 	entries, err := s.loadActiveEntries()
 	if err != nil {
@@ -154,7 +163,10 @@ func (s *ReportService) GroupByProject(dateRange DateRangeSpec) (*ReportData, er
 
 // GroupByTag generates a report grouped by tag
 func (s *ReportService) GroupByTag(dateRange DateRangeSpec) (*ReportData, error) {
-	start, end, period := s.resolveDateRange(dateRange)
+	start, end, period, err := s.resolveDateRange(dateRange)
+	if err != nil {
+		return nil, err
+	}
 
 	entries, err := s.loadActiveEntries()
 	if err != nil {
@@ -222,15 +234,18 @@ func (s *ReportService) loadActiveEntries() ([]entry.Entry, error) {
 }
 
 // resolveDateRange converts a DateRangeSpec to concrete start/end times
-func (s *ReportService) resolveDateRange(spec DateRangeSpec) (start, end time.Time, period string) {
-	now := time.Now()
+func (s *ReportService) resolveDateRange(spec DateRangeSpec) (start, end time.Time, period string, err error) {
+	now, err := timeutil.NowIn(s.config.Timezone)
+	if err != nil {
+		return start, end, period, err
+	}
 
 	switch spec.Type {
 	case DateRangeToday:
-		start, end = timeutil.Today()
+		start, end = timeutil.Today(now)
 		period = "today"
 	case DateRangeYesterday:
-		start, end = timeutil.Yesterday()
+		start, end = timeutil.Yesterday(now)
 		period = "yesterday"
 	case DateRangeThisWeek:
 		start = timeutil.StartOfWeekWithConfig(now, s.config.WeekStartDay)
@@ -242,10 +257,10 @@ func (s *ReportService) resolveDateRange(spec DateRangeSpec) (start, end time.Ti
 		end = timeutil.EndOfWeekWithConfig(start, s.config.WeekStartDay)
 		period = "last week"
 	case DateRangeThisMonth:
-		start, end = timeutil.ThisMonth()
+		start, end = timeutil.ThisMonthWithConfig(now, s.config.MonthStartDay)
 		period = "this month"
 	case DateRangePrevMonth:
-		start, end = timeutil.LastMonth()
+		start, end = timeutil.LastMonthWithConfig(now, s.config.MonthStartDay)
 		period = "last month"
 	case DateRangeLast:
 		end = timeutil.EndOfDay(now)
@@ -256,9 +271,9 @@ func (s *ReportService) resolveDateRange(spec DateRangeSpec) (start, end time.Ti
 		end = spec.To
 		period = formatDateRangeForDisplay(start, end)
 	default:
-		start, end = timeutil.Today()
+		start, end = timeutil.Today(now)
 		period = "today"
 	}
 
-	return start, end, period
+	return start, end, period, nil
 }
@@ -1,7 +1,6 @@
 package service
 
 import (
-	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -185,58 +184,42 @@ func TestReportService_EmptyStorage(t *testing.T) {
 }
 
 func TestReportService_ByProject_StorageError(t *testing.T) {
-	tmpDir := t.TempDir()
-	storagePath := filepath.Join(tmpDir, "entries.jsonl")
-	if err := os.MkdirAll(storagePath, 0755); err != nil {
-		t.Fatal(err)
-	}
+	storagePath := unreadableStoragePath(t)
 
 	svc := NewReportService(storagePath, config.DefaultConfig())
 	_, err := svc.ByProject("acme", DateRangeSpec{Type: DateRangeToday})
 	if err == nil {
-		t.Error("expected error when storage path is a directory")
+		t.Error("expected error when storage path cannot be read")
 	}
 }
 
 func TestReportService_ByTags_StorageError(t *testing.T) {
-	tmpDir := t.TempDir()
-	storagePath := filepath.Join(tmpDir, "entries.jsonl")
-	if err := os.MkdirAll(storagePath, 0755); err != nil {
-		t.Fatal(err)
-	}
+	storagePath := unreadableStoragePath(t)
 
 	svc := NewReportService(storagePath, config.DefaultConfig())
 	_, err := svc.ByTags([]string{"tag"}, DateRangeSpec{Type: DateRangeToday})
 	if err == nil {
-		t.Error("expected error when storage path is a directory")
+		t.Error("expected error when storage path cannot be read")
 	}
 }
 
 func TestReportService_GroupByProject_StorageError(t *testing.T) {
-	tmpDir := t.TempDir()
-	storagePath := filepath.Join(tmpDir, "entries.jsonl")
-	if err := os.MkdirAll(storagePath, 0755); err != nil {
-		t.Fatal(err)
-	}
+	storagePath := unreadableStoragePath(t)
 
 	svc := NewReportService(storagePath, config.DefaultConfig())
 	_, err := svc.GroupByProject(DateRangeSpec{Type: DateRangeToday})
 	if err == nil {
-		t.Error("expected error when storage path is a directory")
+		t.Error("expected error when storage path cannot be read")
 	}
 }
 
 func TestReportService_GroupByTag_StorageError(t *testing.T) {
-	tmpDir := t.TempDir()
-	storagePath := filepath.Join(tmpDir, "entries.jsonl")
-	if err := os.MkdirAll(storagePath, 0755); err != nil {
-		t.Fatal(err)
-	}
+	storagePath := unreadableStoragePath(t)
 
 	svc := NewReportService(storagePath, config.DefaultConfig())
 	_, err := svc.GroupByTag(DateRangeSpec{Type: DateRangeToday})
 	if err == nil {
-		t.Error("expected error when storage path is a directory")
+		t.Error("expected error when storage path cannot be read")
 	}
 }
 
@@ -244,7 +227,10 @@ func TestReportService_resolveDateRange(t *testing.T) {
 	svc := NewReportService("/tmp/test.jsonl", config.DefaultConfig())
 
 	// Test default case
-	start, end, period := svc.resolveDateRange(DateRangeSpec{Type: DateRange(999)})
+	start, end, period, err := svc.resolveDateRange(DateRangeSpec{Type: DateRange(999)})
+	if err != nil {
+		t.Fatalf("resolveDateRange() returned error: %v", err)
+	}
 	if period != "today" {
 		t.Errorf("expected 'today' for unknown type, got %q", period)
 	}
@@ -50,7 +50,10 @@ func (s *SearchService) Search(keyword string, dateRange *DateRangeSpec, f *filt
 	// Apply date range filter if specified
 	var filtered []IndexedEntry
 	if dateRange != nil {
-		start, end, _ := s.resolveDateRange(*dateRange)
+		start, end, _, err := s.resolveDateRange(*dateRange)
+		if err != nil {
+			return nil, err
+		}
 		for _, ie := range activeEntries {
 			if timeutil.IsInRange(ie.Entry.Timestamp, start, end) {
 				filtered = append(filtered, ie)
@@ -90,15 +93,18 @@ func (s *SearchService) Search(keyword string, dateRange *DateRangeSpec, f *filt
 }
 
 // resolveDateRange converts a DateRangeSpec to concrete start/end times
-func (s *SearchService) resolveDateRange(spec DateRangeSpec) (start, end time.Time, period string) {
-	now := time.Now()
+func (s *SearchService) resolveDateRange(spec DateRangeSpec) (start, end time.Time, period string, err error) {
+	now, err := timeutil.NowIn(s.config.Timezone)
+	if err != nil {
+		return start, end, period, err
+	}
 
 	switch spec.Type {
 	case DateRangeToday:
-		start, end = timeutil.Today()
+		start, end = timeutil.Today(now)
 		period = "today"
 	case DateRangeYesterday:
-		start, end = timeutil.Yesterday()
+		start, end = timeutil.Yesterday(now)
 		period = "yesterday"
 	case DateRangeThisWeek:
 		start = timeutil.StartOfWeekWithConfig(now, s.config.WeekStartDay)
@@ -110,10 +116,10 @@ func (s *SearchService) resolveDateRange(spec DateRangeSpec) (start, end time.Ti
 		end = timeutil.EndOfWeekWithConfig(start, s.config.WeekStartDay)
 		period = "last week"
 	case DateRangeThisMonth:
-		start, end = timeutil.ThisMonth()
+		start, end = timeutil.ThisMonthWithConfig(now, s.config.MonthStartDay)
 		period = "this month"
 	case DateRangePrevMonth:
-		start, end = timeutil.LastMonth()
+		start, end = timeutil.LastMonthWithConfig(now, s.config.MonthStartDay)
 		period = "last month"
 	case DateRangeLast:
 		end = timeutil.EndOfDay(now)
@@ -130,5 +136,5 @@ func (s *SearchService) resolveDateRange(spec DateRangeSpec) (start, end time.Ti
 		period = "all time"
 	}
 
-	return start, end, period
+	return start, end, period, nil
 }
@@ -1,7 +1,6 @@
 package service
 
 import (
-	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -180,16 +179,12 @@ func TestSearchService_Search_DateRanges(t *testing.T) {
 }
 
 func TestSearchService_Search_StorageError(t *testing.T) {
-	tmpDir := t.TempDir()
-	storagePath := filepath.Join(tmpDir, "entries.jsonl")
-	if err := os.MkdirAll(storagePath, 0755); err != nil {
-		t.Fatal(err)
-	}
+	storagePath := unreadableStoragePath(t)
 
 	svc := NewSearchService(storagePath, config.DefaultConfig())
 	_, err := svc.Search("test", nil, nil)
 	if err == nil {
-		t.Error("expected error when storage path is a directory")
+		t.Error("expected error when storage path cannot be read")
 	}
 }
 
@@ -197,7 +192,10 @@ func TestSearchService_resolveDateRange_Default(t *testing.T) {
 	svc := NewSearchService("/tmp/test.jsonl", config.DefaultConfig())
 
 	// Test default case (unknown type)
-	start, end, period := svc.resolveDateRange(DateRangeSpec{Type: DateRange(999)})
+	start, end, period, err := svc.resolveDateRange(DateRangeSpec{Type: DateRange(999)})
+	if err != nil {
+		t.Fatalf("resolveDateRange() returned error: %v", err)
+	}
 	if period != "all time" {
 		t.Errorf("expected 'all time' for unknown type, got %q", period)
 	}
@@ -18,22 +18,24 @@ type Services struct {
 
 // NewServices creates a new Services instance with default paths
 func NewServices() (*Services, error) {
-	storagePath, err := storage.GetStoragePath()
+	configPath, err := config.GetConfigPath()
 	if err != nil {
 		return nil, err
 	}
 
-	timerPath, err := timer.GetTimerPath()
+	cfg, err := config.LoadWithEnv(configPath)
 	if err != nil {
 		return nil, err
 	}
 
-	configPath, err := config.GetConfigPath()
+	storagePath, err := storage.ResolveStoragePath(cfg.StoragePath)
 	if err != nil {
 		return nil, err
 	}
+	// The services add entries; on a read-only file system they can still read them
+	_ = storage.EnsureStorageDir(storagePath)
 
-	cfg, err := config.LoadOrDefault(configPath)
+	timerPath, err := timer.GetTimerPath()
 	if err != nil {
 		return nil, err
 	}
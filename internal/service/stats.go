@@ -26,7 +26,10 @@ func NewStatsService(storagePath string, cfg config.Config) *StatsService {
 
 // Weekly returns weekly statistics with comparison to previous week
 func (s *StatsService) Weekly() (*StatsResult, error) {
-	now := time.Now()
+	now, err := timeutil.NowIn(s.config.Timezone)
+	if err != nil {
+		return nil, err
+	}
 
 	// This week
 	thisWeekStart := timeutil.StartOfWeekWithConfig(now, s.config.WeekStartDay)
@@ -41,18 +44,26 @@ func (s *StatsService) Weekly() (*StatsResult, error) {
 
 // Monthly returns monthly statistics with comparison to previous month
 func (s *StatsService) Monthly() (*StatsResult, error) {
+	now, err := timeutil.NowIn(s.config.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
 	// This month
-	thisMonthStart, thisMonthEnd := timeutil.ThisMonth()
+	thisMonthStart, thisMonthEnd := timeutil.ThisMonthWithConfig(now, s.config.MonthStartDay)
 
 	// Last month
-	lastMonthStart, lastMonthEnd := timeutil.LastMonth()
+	lastMonthStart, lastMonthEnd := timeutil.LastMonthWithConfig(now, s.config.MonthStartDay)
 
 	return s.calculateStats(thisMonthStart, thisMonthEnd, lastMonthStart, lastMonthEnd, "this month", "month")
 }
 
 // ForDateRange returns statistics for a custom date range
 func (s *StatsService) ForDateRange(spec DateRangeSpec) (*StatsResult, error) {
-	start, end, period := s.resolveDateRange(spec)
+	start, end, period, err := s.resolveDateRange(spec)
+	if err != nil {
+		return nil, err
+	}
 
 	entries, err := storage.ReadActiveEntries(s.storagePath)
 	if err != nil {
@@ -110,15 +121,18 @@ func (s *StatsService) calculateStats(
 }
 
 // resolveDateRange converts a DateRangeSpec to concrete start/end times
-func (s *StatsService) resolveDateRange(spec DateRangeSpec) (start, end time.Time, period string) {
-	now := time.Now()
+func (s *StatsService) resolveDateRange(spec DateRangeSpec) (start, end time.Time, period string, err error) {
+	now, err := timeutil.NowIn(s.config.Timezone)
+	if err != nil {
+		return start, end, period, err
+	}
 
 	switch spec.Type {
 	case DateRangeToday:
-		start, end = timeutil.Today()
+		start, end = timeutil.Today(now)
 		period = "today"
 	case DateRangeYesterday:
-		start, end = timeutil.Yesterday()
+		start, end = timeutil.Yesterday(now)
 		period = "yesterday"
 	case DateRangeThisWeek:
 		start = timeutil.StartOfWeekWithConfig(now, s.config.WeekStartDay)
@@ -130,10 +144,10 @@ func (s *StatsService) resolveDateRange(spec DateRangeSpec) (start, end time.Tim
 		end = timeutil.EndOfWeekWithConfig(start, s.config.WeekStartDay)
 		period = "last week"
 	case DateRangeThisMonth:
-		start, end = timeutil.ThisMonth()
+		start, end = timeutil.ThisMonthWithConfig(now, s.config.MonthStartDay)
 		period = "this month"
 	case DateRangePrevMonth:
-		start, end = timeutil.LastMonth()
+		start, end = timeutil.LastMonthWithConfig(now, s.config.MonthStartDay)
 		period = "last month"
 	case DateRangeLast:
 		end = timeutil.EndOfDay(now)
@@ -144,9 +158,9 @@ func (s *StatsService) resolveDateRange(spec DateRangeSpec) (start, end time.Tim
 		end = spec.To
 		period = formatDateRangeForDisplay(start, end)
 	default:
-		start, end = timeutil.Today()
+		start, end = timeutil.Today(now)
 		period = "today"
 	}
 
-	return start, end, period
+	return start, end, period, nil
 }
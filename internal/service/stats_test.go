@@ -1,7 +1,6 @@
 package service
 
 import (
-	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -50,6 +49,22 @@ func TestStatsService_Weekly(t *testing.T) {
 	}
 }
 
+func TestStatsService_InvalidTimezone(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Timezone = "Invalid/Zone"
+	svc := NewStatsService(filepath.Join(t.TempDir(), "entries.jsonl"), cfg)
+
+	if _, err := svc.Weekly(); err == nil {
+		t.Error("expected error for an invalid timezone")
+	}
+	if _, err := svc.Monthly(); err == nil {
+		t.Error("expected error for an invalid timezone")
+	}
+	if _, err := svc.ForDateRange(DateRangeSpec{Type: DateRangeToday}); err == nil {
+		t.Error("expected error for an invalid timezone")
+	}
+}
+
 func TestStatsService_Monthly(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -169,7 +184,10 @@ func TestStatsService_resolveDateRange_Default(t *testing.T) {
 	svc := NewStatsService("/tmp/test.jsonl", config.DefaultConfig())
 
 	// Test default case
-	start, end, period := svc.resolveDateRange(DateRangeSpec{Type: DateRange(999)})
+	start, end, period, err := svc.resolveDateRange(DateRangeSpec{Type: DateRange(999)})
+	if err != nil {
+		t.Fatalf("resolveDateRange() returned error: %v", err)
+	}
 	if period != "today" {
 		t.Errorf("expected 'today' for unknown type, got %q", period)
 	}
@@ -179,44 +197,32 @@ func TestStatsService_resolveDateRange_Default(t *testing.T) {
 }
 
 func TestStatsService_Weekly_StorageError(t *testing.T) {
-	tmpDir := t.TempDir()
-	storagePath := filepath.Join(tmpDir, "entries.jsonl")
-	if err := os.MkdirAll(storagePath, 0755); err != nil {
-		t.Fatal(err)
-	}
+	storagePath := unreadableStoragePath(t)
 
 	svc := NewStatsService(storagePath, config.DefaultConfig())
 	_, err := svc.Weekly()
 	if err == nil {
-		t.Error("expected error when storage path is a directory")
+		t.Error("expected error when storage path cannot be read")
 	}
 }
 
 func TestStatsService_Monthly_StorageError(t *testing.T) {
-	tmpDir := t.TempDir()
-	storagePath := filepath.Join(tmpDir, "entries.jsonl")
-	if err := os.MkdirAll(storagePath, 0755); err != nil {
-		t.Fatal(err)
-	}
+	storagePath := unreadableStoragePath(t)
 
 	svc := NewStatsService(storagePath, config.DefaultConfig())
 	_, err := svc.Monthly()
 	if err == nil {
-		t.Error("expected error when storage path is a directory")
+		t.Error("expected error when storage path cannot be read")
 	}
 }
 
 func TestStatsService_ForDateRange_StorageError(t *testing.T) {
-	tmpDir := t.TempDir()
-	storagePath := filepath.Join(tmpDir, "entries.jsonl")
-	if err := os.MkdirAll(storagePath, 0755); err != nil {
-		t.Fatal(err)
-	}
+	storagePath := unreadableStoragePath(t)
 
 	svc := NewStatsService(storagePath, config.DefaultConfig())
 	_, err := svc.ForDateRange(DateRangeSpec{Type: DateRangeToday})
 	if err == nil {
-		t.Error("expected error when storage path is a directory")
+		t.Error("expected error when storage path cannot be read")
 	}
 }
 
@@ -10,6 +10,7 @@ import (
 	"github.com/xolan/did/internal/entry"
 	"github.com/xolan/did/internal/storage"
 	"github.com/xolan/did/internal/timer"
+	"github.com/xolan/did/internal/timeutil"
 )
 
 // Timer-specific errors
@@ -106,13 +107,15 @@ func (s *TimerService) Stop() (*entry.Entry, *timer.TimerState, error) {
 		Timestamp:       now,
 		Description:     state.Description,
 		DurationMinutes: durationMinutes,
-		RawInput:        fmt.Sprintf("%s for %s", state.Description, formatDurationSimple(durationMinutes)),
 		Project:         state.Project,
 		Tags:            state.Tags,
+		LoggedAt:        &now,
 	}
+	s.config.ApplyEntryDefaults(&e)
+	e.RawInput = fmt.Sprintf("%s for %s", state.Description, timeutil.FormatDuration(e.DurationMinutes))
 
 	// Append entry to storage
-	if err := storage.AppendEntry(s.storagePath, e); err != nil {
+	if err := storage.AppendEntryWithOptions(s.storagePath, e, storage.AppendOptions{Sync: s.config.DurableWrites}); err != nil {
 		return nil, nil, fmt.Errorf("failed to save entry: %w", err)
 	}
 
@@ -0,0 +1,61 @@
+package stats
+
+import "sort"
+
+// RoundToTotal rounds each value (in minutes) to a multiple of step so that the
+// rounded values add up exactly to the rounded total. The total is the sum of
+// all values rounded to the nearest multiple of step (halves round up).
+//
+// It uses the largest remainder method: every value is first rounded down, and
+// the remaining steps needed to reach the rounded total go to the values with
+// the largest remainders. Ties go to the earlier value, so the result is
+// deterministic. A value of zero is never rounded up, and no value moves by a
+// full step or more from its exact duration.
+//
+// Rounding down moves a negative value toward zero, i.e. up, so with negative
+// values the rounded-down values can add up to more than the rounded total.
+// The extra steps are then taken back from the values rounded up the most.
+// With step <= 0 the values are returned unchanged (as a copy) together with
+// their sum.
+//
+// Example: RoundToTotal([]int{8, 8, 8}, 15) returns [15, 15, 0] and 30;
+// rounding each value on its own would give 45.
+func RoundToTotal(values []int, step int) ([]int, int) {
+	rounded := make([]int, len(values))
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+
+	if step <= 0 {
+		copy(rounded, values)
+		return rounded, total
+	}
+
+	roundedTotal := (2*total + step) / (2 * step) * step
+
+	// Round everything down and remember what was cut off
+	floorSum := 0
+	order := make([]int, len(values))
+	for i, v := range values {
+		rounded[i] = v / step * step
+		floorSum += rounded[i]
+		order[i] = i
+	}
+
+	// Hand out the missing steps to the largest remainders
+	sort.SliceStable(order, func(a, b int) bool {
+		return values[order[a]]-rounded[order[a]] > values[order[b]]-rounded[order[b]]
+	})
+	missing := (roundedTotal - floorSum) / step
+	for k := 0; k < missing && k < len(order); k++ {
+		rounded[order[k]] += step
+	}
+
+	// Overshoot: take steps back, starting from the most negative remainder
+	for k := 0; k < -missing && k < len(order); k++ {
+		rounded[order[len(order)-1-k]] -= step
+	}
+
+	return rounded, roundedTotal
+}
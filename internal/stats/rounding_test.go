@@ -0,0 +1,91 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoundToTotal(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []int
+		step      int
+		expected  []int
+		wantTotal int
+	}{
+		{"empty", []int{}, 15, []int{}, 0},
+		{"no rounding", []int{7, 22}, 0, []int{7, 22}, 29},
+		{"negative step", []int{7, 22}, -15, []int{7, 22}, 29},
+		{"exact multiples", []int{15, 30, 60}, 15, []int{15, 30, 60}, 105},
+		{"single value rounds down", []int{37}, 15, []int{30}, 30},
+		{"single value rounds up", []int{38}, 15, []int{45}, 45},
+		{"half rounds up", []int{30}, 60, []int{60}, 60},
+		{"positive drift", []int{10, 10, 10}, 15, []int{15, 15, 0}, 30},
+		{"negative drift", []int{8, 8, 8}, 15, []int{15, 15, 0}, 30},
+		{"largest remainder wins", []int{14, 3, 8}, 15, []int{15, 0, 15}, 30},
+		{"ties go to the earlier value", []int{5, 5, 5}, 15, []int{15, 0, 0}, 15},
+		{"zero values stay zero", []int{0, 20, 0, 25}, 15, []int{0, 15, 0, 30}, 45},
+		{"all zero", []int{0, 0}, 15, []int{0, 0}, 0},
+		{"everything rounds to zero", []int{3, 3}, 15, []int{0, 0}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total := RoundToTotal(tt.values, tt.step)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("RoundToTotal(%v, %d) = %v, expected %v", tt.values, tt.step, got, tt.expected)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("RoundToTotal(%v, %d) total = %d, expected %d", tt.values, tt.step, total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestRoundToTotal_ItemsSumToTotal(t *testing.T) {
+	// A month of entries with awkward durations drifts badly when each is rounded on its own
+	var values []int
+	for i := 0; i < 60; i++ {
+		values = append(values, 7+(i*13)%50)
+	}
+
+	for _, step := range []int{5, 6, 10, 15, 30, 60} {
+		got, total := RoundToTotal(values, step)
+
+		sum, exact := 0, 0
+		for i, v := range got {
+			sum += v
+			exact += values[i]
+			if v%step != 0 {
+				t.Errorf("step %d: value %d is not a multiple of the step", step, v)
+			}
+			if diff := v - values[i]; diff >= step || diff <= -step {
+				t.Errorf("step %d: value %d moved from %d by a full step or more", step, v, values[i])
+			}
+		}
+		if sum != total {
+			t.Errorf("step %d: items sum to %d but total is %d", step, sum, total)
+		}
+		if diff := total - exact; diff*2 > step || diff*2 < -step {
+			t.Errorf("step %d: total %d is not the exact total %d rounded to the nearest step", step, total, exact)
+		}
+	}
+}
+
+func TestRoundToTotal_DoesNotModifyInput(t *testing.T) {
+	values := []int{10, 20}
+	_, _ = RoundToTotal(values, 15)
+	if values[0] != 10 || values[1] != 20 {
+		t.Errorf("Expected input to be unchanged, got %v", values)
+	}
+}
+
+func TestRoundToTotal_Overshoot(t *testing.T) {
+	// -10 rounds down toward zero, so the rounded-down values add up to 15
+	// while the total of 5 rounds to 0
+	got, total := RoundToTotal([]int{-10, -10, 25}, 15)
+
+	if expected := []int{0, -15, 15}; !reflect.DeepEqual(got, expected) || total != 0 {
+		t.Errorf("RoundToTotal() = %v, %d, expected %v, 0", got, total, expected)
+	}
+}
@@ -0,0 +1,64 @@
+package stats
+
+import (
+	"math"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// SplitAtMidnight returns the entries with every entry that runs past
+// midnight split into one part per calendar day it covers, in order. Each part
+// starts at the entry's start or at midnight and gets the minutes of that
+// day, so the parts add up to the entry's duration. Days are calendar days in
+// the location of the entry's timestamp, so a day with a DST transition has
+// 23 or 25 hours. Entries within a single day are returned unchanged.
+//
+// The split is only meant for display and aggregation by day; entry counts
+// taken from the result count every part.
+func SplitAtMidnight(entries []entry.Entry) []entry.Entry {
+	result := make([]entry.Entry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, splitEntry(e)...)
+	}
+	return result
+}
+
+// splitEntry splits a single entry at each midnight it runs past
+func splitEntry(e entry.Entry) []entry.Entry {
+	end := e.EndTime()
+	next := nextMidnight(e.Timestamp)
+	if !end.After(next) {
+		return []entry.Entry{e}
+	}
+
+	// Round the elapsed minutes at each boundary rather than each part, so
+	// seconds in the timestamp never make the parts add up to more or less
+	// than the duration
+	var parts []entry.Entry
+	start, assigned := e.Timestamp, 0
+	for start.Before(end) {
+		boundary := next
+		elapsed := int(math.Round(boundary.Sub(e.Timestamp).Minutes()))
+		if !end.After(boundary) {
+			boundary, elapsed = end, e.DurationMinutes
+		}
+
+		part := e
+		part.Timestamp = start
+		part.DurationMinutes = elapsed - assigned
+		if part.DurationMinutes > 0 {
+			parts = append(parts, part)
+		}
+
+		assigned = elapsed
+		start, next = boundary, nextMidnight(boundary)
+	}
+	return parts
+}
+
+// nextMidnight returns the start of the calendar day after t, in t's location
+func nextMidnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,158 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// wantPart is the expected start and duration of a part of a split entry
+type wantPart struct {
+	start   string // "2006-01-02 15:04" in the entry's location
+	minutes int
+}
+
+func checkParts(t *testing.T, parts []entry.Entry, want []wantPart) {
+	t.Helper()
+	if len(parts) != len(want) {
+		t.Fatalf("Expected %d parts, got %d: %+v", len(want), len(parts), parts)
+	}
+	for i, w := range want {
+		start := parts[i].Timestamp.Format("2006-01-02 15:04")
+		if start != w.start || parts[i].DurationMinutes != w.minutes {
+			t.Errorf("Part %d = %s (%dm), expected %s (%dm)", i, start, parts[i].DurationMinutes, w.start, w.minutes)
+		}
+	}
+}
+
+func TestSplitAtMidnight(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		entry entry.Entry
+		want  []wantPart
+	}{
+		{
+			"within a day",
+			entry.Entry{Timestamp: at(15, 9, 0), DurationMinutes: 120},
+			[]wantPart{{"2024-01-15 09:00", 120}},
+		},
+		{
+			"ends exactly at midnight",
+			entry.Entry{Timestamp: at(15, 22, 0), DurationMinutes: 120},
+			[]wantPart{{"2024-01-15 22:00", 120}},
+		},
+		{
+			"runs past midnight",
+			entry.Entry{Timestamp: at(15, 22, 0), DurationMinutes: 300},
+			[]wantPart{{"2024-01-15 22:00", 120}, {"2024-01-16 00:00", 180}},
+		},
+		{
+			"starts at midnight",
+			entry.Entry{Timestamp: at(16, 0, 0), DurationMinutes: 1440},
+			[]wantPart{{"2024-01-16 00:00", 1440}},
+		},
+		{
+			"covers three days",
+			entry.Entry{Timestamp: at(15, 23, 30), DurationMinutes: 1440 + 60},
+			[]wantPart{{"2024-01-15 23:30", 30}, {"2024-01-16 00:00", 1440}, {"2024-01-17 00:00", 30}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkParts(t, SplitAtMidnight([]entry.Entry{tt.entry}), tt.want)
+		})
+	}
+}
+
+func TestSplitAtMidnight_KeepsFieldsAndOrder(t *testing.T) {
+	entries := []entry.Entry{
+		{Timestamp: time.Date(2024, 1, 15, 23, 0, 0, 0, time.UTC), Description: "incident", DurationMinutes: 120, Project: "ops", Tags: []string{"oncall"}},
+		{Timestamp: time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC), Description: "standup", DurationMinutes: 15},
+	}
+
+	parts := SplitAtMidnight(entries)
+
+	if len(parts) != 3 {
+		t.Fatalf("Expected 3 parts, got %d", len(parts))
+	}
+	for _, p := range parts[:2] {
+		if p.Description != "incident" || p.Project != "ops" || len(p.Tags) != 1 {
+			t.Errorf("Expected the parts to keep the entry's fields, got %+v", p)
+		}
+	}
+	if parts[2].Description != "standup" {
+		t.Errorf("Expected the following entry last, got %+v", parts[2])
+	}
+	if entries[0].DurationMinutes != 120 {
+		t.Error("Expected the input entries to be left unchanged")
+	}
+}
+
+func TestSplitAtMidnight_Seconds(t *testing.T) {
+	// The parts still add up to the duration when the start has seconds
+	e := entry.Entry{Timestamp: time.Date(2024, 1, 15, 23, 59, 40, 0, time.UTC), DurationMinutes: 2}
+
+	checkParts(t, SplitAtMidnight([]entry.Entry{e}), []wantPart{{"2024-01-16 00:00", 2}})
+}
+
+func TestSplitAtMidnight_DST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2024, month, day, hour, 0, 0, 0, berlin)
+	}
+
+	tests := []struct {
+		name  string
+		entry entry.Entry
+		want  []wantPart
+	}{
+		{
+			// Mar 31 has 23 hours: 24h from 23:00 cover 1h of Mar 30 and all of Mar 31
+			"into a 23 hour day",
+			entry.Entry{Timestamp: at(time.March, 30, 23), DurationMinutes: 1440},
+			[]wantPart{{"2024-03-30 23:00", 60}, {"2024-03-31 00:00", 1380}},
+		},
+		{
+			"spanning a 23 hour day",
+			entry.Entry{Timestamp: at(time.March, 31, 0), DurationMinutes: 1440},
+			[]wantPart{{"2024-03-31 00:00", 1380}, {"2024-04-01 00:00", 60}},
+		},
+		{
+			// Oct 27 has 25 hours, so 24h from midnight stay on that day
+			"within a 25 hour day",
+			entry.Entry{Timestamp: at(time.October, 27, 0), DurationMinutes: 1440},
+			[]wantPart{{"2024-10-27 00:00", 1440}},
+		},
+		{
+			"into a 25 hour day",
+			entry.Entry{Timestamp: at(time.October, 26, 22), DurationMinutes: 300},
+			[]wantPart{{"2024-10-26 22:00", 120}, {"2024-10-27 00:00", 180}},
+		},
+		{
+			"past the end of a 25 hour day",
+			entry.Entry{Timestamp: at(time.October, 27, 23), DurationMinutes: 120},
+			[]wantPart{{"2024-10-27 23:00", 60}, {"2024-10-28 00:00", 60}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkParts(t, SplitAtMidnight([]entry.Entry{tt.entry}), tt.want)
+		})
+	}
+}
+
+func TestSplitAtMidnight_Empty(t *testing.T) {
+	if parts := SplitAtMidnight(nil); len(parts) != 0 {
+		t.Errorf("Expected no parts, got %+v", parts)
+	}
+}
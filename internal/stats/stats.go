@@ -6,14 +6,35 @@ import (
 	"time"
 
 	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/timeutil"
 )
 
 // Statistics contains aggregated statistics for a set of entries
 type Statistics struct {
-	TotalMinutes         int
-	AverageMinutesPerDay float64
-	EntryCount           int
-	DaysWithEntries      int
+	TotalMinutes           int
+	AverageMinutesPerDay   float64
+	AverageMinutesPerEntry float64
+	EntryCount             int
+	DaysWithEntries        int
+}
+
+// BackdatedThreshold is the logging latency above which an entry counts as backdated
+const BackdatedThreshold = 24 * time.Hour
+
+// LoggingLatency describes how long after its timestamp entries were logged.
+// Only entries with a LoggedAt are counted.
+type LoggingLatency struct {
+	EntryCount     int           // Entries with a LoggedAt
+	Median         time.Duration // Median time from timestamp to LoggedAt
+	BackdatedCount int           // Entries logged more than BackdatedThreshold after their timestamp
+}
+
+// BackdatedShare returns the share of counted entries that were backdated, from 0 to 1
+func (l LoggingLatency) BackdatedShare() float64 {
+	if l.EntryCount == 0 {
+		return 0
+	}
+	return float64(l.BackdatedCount) / float64(l.EntryCount)
 }
 
 // ProjectBreakdown contains statistics for a single project
@@ -42,8 +63,8 @@ func CalculateStatistics(entries []entry.Entry, start, end time.Time) Statistics
 	daysWithEntries := make(map[string]bool)
 
 	for _, e := range entries {
-		// Skip deleted entries
-		if e.DeletedAt != nil {
+		// Skip deleted entries and entries with a zero or negative duration
+		if e.DeletedAt != nil || !e.HasValidDuration() {
 			continue
 		}
 
@@ -66,10 +87,53 @@ func CalculateStatistics(entries []entry.Entry, start, end time.Time) Statistics
 	if totalDays > 0 {
 		stats.AverageMinutesPerDay = float64(stats.TotalMinutes) / float64(totalDays)
 	}
+	if stats.EntryCount > 0 {
+		stats.AverageMinutesPerEntry = float64(stats.TotalMinutes) / float64(stats.EntryCount)
+	}
 
 	return stats
 }
 
+// CalculateLoggingLatency computes how promptly entries within the given date
+// range were logged: the time from each entry's timestamp to its LoggedAt.
+// Entries without a LoggedAt (written before it was recorded) are skipped, and
+// entries logged before their timestamp count as logged immediately.
+func CalculateLoggingLatency(entries []entry.Entry, start, end time.Time) LoggingLatency {
+	var latencies []time.Duration
+	for _, e := range entries {
+		if e.DeletedAt != nil || !e.HasValidDuration() || e.LoggedAt == nil {
+			continue
+		}
+		if e.Timestamp.Before(start) || e.Timestamp.After(end) {
+			continue
+		}
+		latency := e.LoggedAt.Sub(e.Timestamp)
+		if latency < 0 {
+			latency = 0
+		}
+		latencies = append(latencies, latency)
+	}
+
+	latency := LoggingLatency{EntryCount: len(latencies)}
+	if len(latencies) == 0 {
+		return latency
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	middle := len(latencies) / 2
+	if len(latencies)%2 == 1 {
+		latency.Median = latencies[middle]
+	} else {
+		latency.Median = (latencies[middle-1] + latencies[middle]) / 2
+	}
+	for _, l := range latencies {
+		if l > BackdatedThreshold {
+			latency.BackdatedCount++
+		}
+	}
+	return latency
+}
+
 // CalculateProjectBreakdown groups entries by project and returns breakdown sorted by total minutes
 func CalculateProjectBreakdown(entries []entry.Entry, start, end time.Time) []ProjectBreakdown {
 	if len(entries) == 0 {
@@ -80,8 +144,8 @@ func CalculateProjectBreakdown(entries []entry.Entry, start, end time.Time) []Pr
 	projectMap := make(map[string]*ProjectBreakdown)
 
 	for _, e := range entries {
-		// Skip deleted entries
-		if e.DeletedAt != nil {
+		// Skip deleted entries and entries with a zero or negative duration
+		if e.DeletedAt != nil || !e.HasValidDuration() {
 			continue
 		}
 
@@ -133,8 +197,8 @@ func CalculateTagBreakdown(entries []entry.Entry, start, end time.Time) []TagBre
 	tagMap := make(map[string]*TagBreakdown)
 
 	for _, e := range entries {
-		// Skip deleted entries
-		if e.DeletedAt != nil {
+		// Skip deleted entries and entries with a zero or negative duration
+		if e.DeletedAt != nil || !e.HasValidDuration() {
 			continue
 		}
 
@@ -181,6 +245,77 @@ func CalculateTagBreakdown(entries []entry.Entry, start, end time.Time) []TagBre
 	return breakdowns
 }
 
+// DayTotal is the time logged on a single calendar day
+type DayTotal struct {
+	Date              time.Time // Midnight of the day
+	Minutes           int       // Minutes logged on the day
+	CumulativeMinutes int       // Minutes logged from the first day up to and including this one
+}
+
+// DailyTotals returns the time logged on every calendar day from start to end,
+// including days without entries, with days taken in loc. An entry counts on
+// the day it starts, like in CalculateStatistics. A zero start begins at the
+// first entry up to end, and no entries then give no days.
+func DailyTotals(entries []entry.Entry, start, end time.Time, loc *time.Location) []DayTotal {
+	minutes := make(map[string]int)
+	first := time.Time{}
+	for _, e := range entries {
+		if e.DeletedAt != nil || !e.HasValidDuration() || e.Timestamp.Before(start) || e.Timestamp.After(end) {
+			continue
+		}
+		minutes[e.Timestamp.In(loc).Format("2006-01-02")] += e.DurationMinutes
+		if first.IsZero() || e.Timestamp.Before(first) {
+			first = e.Timestamp
+		}
+	}
+	if start.IsZero() {
+		if first.IsZero() {
+			return nil
+		}
+		start = first
+	}
+
+	var days []DayTotal
+	cumulative := 0
+	for day := timeutil.StartOfDay(start.In(loc)); !day.After(end); day = day.AddDate(0, 0, 1) {
+		logged := minutes[day.Format("2006-01-02")]
+		cumulative += logged
+		days = append(days, DayTotal{Date: day, Minutes: logged, CumulativeMinutes: cumulative})
+	}
+	return days
+}
+
+// WeekdayTotal is the time logged on one day of the week over a period
+type WeekdayTotal struct {
+	Weekday time.Weekday
+	Days    int // Number of times the weekday occurs in the period
+	Minutes int // Minutes logged on all these days
+}
+
+// AverageMinutes returns the minutes logged on an average occurrence of the
+// weekday, counting the days without entries
+func (w WeekdayTotal) AverageMinutes() float64 {
+	if w.Days == 0 {
+		return 0
+	}
+	return float64(w.Minutes) / float64(w.Days)
+}
+
+// WeekdayProfile buckets the days of DailyTotals by day of the week, in week
+// order starting with firstDay. Weekdays not in the period have no days.
+func WeekdayProfile(days []DayTotal, firstDay time.Weekday) []WeekdayTotal {
+	profile := make([]WeekdayTotal, 7)
+	for i := range profile {
+		profile[i].Weekday = (firstDay + time.Weekday(i)) % 7
+	}
+	for _, day := range days {
+		w := &profile[(day.Date.Weekday()-firstDay+7)%7]
+		w.Days++
+		w.Minutes += day.Minutes
+	}
+	return profile
+}
+
 // CompareStatistics computes the difference between current and previous period statistics.
 // Returns the difference in minutes (positive if current > previous, negative if current < previous).
 func CompareStatistics(current, previous Statistics) int {
@@ -202,19 +337,7 @@ func FormatComparison(diffMinutes int, periodName string) string {
 		absDiff = -absDiff
 	}
 
-	// Format duration
-	var duration string
-	if absDiff < 60 {
-		duration = fmt.Sprintf("%dm", absDiff)
-	} else {
-		hours := absDiff / 60
-		mins := absDiff % 60
-		if mins == 0 {
-			duration = fmt.Sprintf("%dh", hours)
-		} else {
-			duration = fmt.Sprintf("%dh %dm", hours, mins)
-		}
-	}
+	duration := timeutil.FormatDuration(absDiff)
 
 	// Format direction
 	direction := "up"
@@ -110,6 +110,135 @@ func TestCalculateStatistics_MultipleEntries(t *testing.T) {
 	if stats.AverageMinutesPerDay != expectedAvg {
 		t.Errorf("AverageMinutesPerDay = %f, expected %f", stats.AverageMinutesPerDay, expectedAvg)
 	}
+	if stats.AverageMinutesPerEntry != 112.5 {
+		t.Errorf("AverageMinutesPerEntry = %f, expected 112.5", stats.AverageMinutesPerEntry)
+	}
+}
+
+func TestDailyTotals(t *testing.T) {
+	start := makeTime(2024, time.January, 15, 0, 0, 0)
+	end := makeTime(2024, time.January, 18, 23, 59, 59)
+	deletedAt := makeTime(2024, time.January, 20, 0, 0, 0)
+	deleted := makeEntry(makeTime(2024, time.January, 16, 9, 0, 0), 600, "deleted")
+	deleted.DeletedAt = &deletedAt
+
+	entries := []entry.Entry{
+		makeEntry(makeTime(2024, time.January, 15, 9, 0, 0), 60, "standup"),
+		makeEntry(makeTime(2024, time.January, 15, 14, 0, 0), 90, "review"),
+		makeEntry(makeTime(2024, time.January, 17, 10, 0, 0), 30, "deploy"),
+		makeEntry(makeTime(2024, time.January, 14, 10, 0, 0), 45, "before range"),
+		deleted,
+	}
+
+	days := DailyTotals(entries, start, end, time.UTC)
+
+	expected := []DayTotal{
+		{Date: makeTime(2024, time.January, 15, 0, 0, 0), Minutes: 150, CumulativeMinutes: 150},
+		{Date: makeTime(2024, time.January, 16, 0, 0, 0), Minutes: 0, CumulativeMinutes: 150},
+		{Date: makeTime(2024, time.January, 17, 0, 0, 0), Minutes: 30, CumulativeMinutes: 180},
+		{Date: makeTime(2024, time.January, 18, 0, 0, 0), Minutes: 0, CumulativeMinutes: 180},
+	}
+	if len(days) != len(expected) {
+		t.Fatalf("DailyTotals() returned %d days, expected %d: %+v", len(days), len(expected), days)
+	}
+	for i := range expected {
+		if !days[i].Date.Equal(expected[i].Date) || days[i].Minutes != expected[i].Minutes || days[i].CumulativeMinutes != expected[i].CumulativeMinutes {
+			t.Errorf("Day %d = %+v, expected %+v", i, days[i], expected[i])
+		}
+	}
+
+	// Days are those of loc: 14:00 UTC on the 15th is already the 16th in UTC+12
+	east := time.FixedZone("UTC+12", 12*3600)
+	if days := DailyTotals(entries[1:2], start, end, east); days[1].Minutes != 90 {
+		t.Errorf("Expected the entry on the second day in UTC+12, got %+v", days)
+	}
+
+	// Without a start, days begin at the first entry
+	if days := DailyTotals(entries[2:3], time.Time{}, end, time.UTC); len(days) != 2 || days[0].Minutes != 30 {
+		t.Errorf("Expected the days from the first entry, got %+v", days)
+	}
+	if days := DailyTotals(nil, time.Time{}, end, time.UTC); days != nil {
+		t.Errorf("Expected no days without entries, got %+v", days)
+	}
+}
+
+func TestWeekdayProfile(t *testing.T) {
+	// Mon Jan 15 to Tue Jan 23, 2024: two Mondays and two Tuesdays
+	start := makeTime(2024, time.January, 15, 0, 0, 0)
+	end := makeTime(2024, time.January, 23, 23, 59, 59)
+	entries := []entry.Entry{
+		makeEntry(makeTime(2024, time.January, 15, 9, 0, 0), 60, "monday"),
+		makeEntry(makeTime(2024, time.January, 16, 9, 0, 0), 120, "tuesday"),
+		makeEntry(makeTime(2024, time.January, 23, 9, 0, 0), 180, "tuesday"),
+		makeEntry(makeTime(2024, time.January, 21, 9, 0, 0), 30, "sunday"),
+	}
+	days := DailyTotals(entries, start, end, time.UTC)
+
+	profile := WeekdayProfile(days, time.Monday)
+	if len(profile) != 7 || profile[0].Weekday != time.Monday || profile[6].Weekday != time.Sunday {
+		t.Fatalf("Expected the weekdays from Monday to Sunday, got %+v", profile)
+	}
+	expected := []struct {
+		days, minutes int
+		average       float64
+	}{{2, 60, 30}, {2, 300, 150}, {1, 0, 0}, {1, 0, 0}, {1, 0, 0}, {1, 0, 0}, {1, 30, 30}}
+	for i, want := range expected {
+		w := profile[i]
+		if w.Days != want.days || w.Minutes != want.minutes || w.AverageMinutes() != want.average {
+			t.Errorf("%s = %d days, %d minutes, average %v; expected %d, %d, %v", w.Weekday, w.Days, w.Minutes, w.AverageMinutes(), want.days, want.minutes, want.average)
+		}
+	}
+
+	// Weeks starting on Sunday list Sunday first
+	if sunday := WeekdayProfile(days, time.Sunday); sunday[0].Weekday != time.Sunday || sunday[0].Minutes != 30 || sunday[1].Minutes != 60 {
+		t.Errorf("Expected Sunday first, got %+v", sunday)
+	}
+	if empty := WeekdayProfile(nil, time.Monday); empty[0].Days != 0 || empty[0].AverageMinutes() != 0 {
+		t.Errorf("Expected no days without daily totals, got %+v", empty)
+	}
+}
+
+func TestCalculateLoggingLatency(t *testing.T) {
+	start := makeTime(2024, time.January, 15, 0, 0, 0)
+	end := makeTime(2024, time.January, 21, 23, 59, 59)
+	loggedAfter := func(e entry.Entry, latency time.Duration) entry.Entry {
+		loggedAt := e.Timestamp.Add(latency)
+		e.LoggedAt = &loggedAt
+		return e
+	}
+	deletedAt := makeTime(2024, time.January, 20, 0, 0, 0)
+	deleted := loggedAfter(makeEntry(makeTime(2024, time.January, 17, 9, 0, 0), 30, "deleted"), 72*time.Hour)
+	deleted.DeletedAt = &deletedAt
+
+	entries := []entry.Entry{
+		loggedAfter(makeEntry(makeTime(2024, time.January, 15, 9, 0, 0), 60, "standup"), 5*time.Minute),
+		loggedAfter(makeEntry(makeTime(2024, time.January, 15, 14, 0, 0), 60, "review"), 15*time.Minute),
+		loggedAfter(makeEntry(makeTime(2024, time.January, 16, 9, 0, 0), 60, "backdated"), 48*time.Hour),
+		loggedAfter(makeEntry(makeTime(2024, time.January, 17, 9, 0, 0), 60, "future"), -time.Hour),
+		makeEntry(makeTime(2024, time.January, 17, 10, 0, 0), 60, "old entry without logged_at"),
+		loggedAfter(makeEntry(makeTime(2024, time.January, 10, 9, 0, 0), 60, "outside range"), 72*time.Hour),
+		deleted,
+	}
+
+	latency := CalculateLoggingLatency(entries, start, end)
+
+	if latency.EntryCount != 4 {
+		t.Errorf("EntryCount = %d, expected 4", latency.EntryCount)
+	}
+	// Latencies 0 (logged before its timestamp), 5m, 15m and 48h
+	if latency.Median != 10*time.Minute {
+		t.Errorf("Median = %v, expected 10m", latency.Median)
+	}
+	if latency.BackdatedCount != 1 || latency.BackdatedShare() != 0.25 {
+		t.Errorf("BackdatedCount = %d, share %v, expected 1 and 0.25", latency.BackdatedCount, latency.BackdatedShare())
+	}
+
+	if odd := CalculateLoggingLatency(entries[:3], start, end); odd.Median != 15*time.Minute {
+		t.Errorf("Median of three = %v, expected 15m", odd.Median)
+	}
+	if none := CalculateLoggingLatency(entries[4:5], start, end); none.EntryCount != 0 || none.BackdatedShare() != 0 {
+		t.Errorf("Expected no latency for entries without logged_at, got %+v", none)
+	}
 }
 
 func TestCalculateStatistics_SingleDay(t *testing.T) {
@@ -198,6 +327,29 @@ func TestCalculateStatistics_SkipsDeletedEntries(t *testing.T) {
 	}
 }
 
+func TestCalculateStatistics_SkipsNonPositiveDurations(t *testing.T) {
+	start := makeTime(2024, time.January, 15, 0, 0, 0)
+	end := makeTime(2024, time.January, 21, 23, 59, 59)
+
+	entries := []entry.Entry{
+		makeEntry(makeTime(2024, time.January, 15, 9, 0, 0), 120, "active entry"),
+		makeEntry(makeTime(2024, time.January, 16, 10, 0, 0), 0, "zero entry"),
+		makeEntry(makeTime(2024, time.January, 17, 11, 0, 0), -30, "negative entry"),
+	}
+
+	stats := CalculateStatistics(entries, start, end)
+
+	if stats.TotalMinutes != 120 {
+		t.Errorf("TotalMinutes = %d, expected 120 (zero and negative durations should be skipped)", stats.TotalMinutes)
+	}
+	if stats.EntryCount != 1 {
+		t.Errorf("EntryCount = %d, expected 1", stats.EntryCount)
+	}
+	if stats.DaysWithEntries != 1 {
+		t.Errorf("DaysWithEntries = %d, expected 1", stats.DaysWithEntries)
+	}
+}
+
 func TestCalculateStatistics_OnlyDeletedEntries(t *testing.T) {
 	start := makeTime(2024, time.January, 15, 0, 0, 0)
 	end := makeTime(2024, time.January, 21, 23, 59, 59)
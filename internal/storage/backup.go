@@ -69,13 +69,17 @@ func rotateBackups(storagePath string) error {
 // Returns an error if backup rotation or file copying fails.
 func CreateBackup(storagePath string) error {
 	// Check if storage file exists
-	if _, err := os.Stat(storagePath); err != nil {
+	info, err := os.Stat(storagePath)
+	if err != nil {
 		if os.IsNotExist(err) {
 			// No file to backup, return without error
 			return nil
 		}
 		return err
 	}
+	if info.IsDir() {
+		return ErrDirectoryBackup
+	}
 
 	// Rotate existing backups to make room for new backup
 	if err := rotateBackups(storagePath); err != nil {
@@ -155,6 +159,7 @@ func RestoreBackup(backupNum int) error {
 
 // RestoreBackupForStorage restores a backup file to the specified storage file.
 // If storagePath is empty, uses GetStoragePath() to get the default storage location.
+// The storage lock is held throughout, like for any other write.
 func RestoreBackupForStorage(storagePath string, backupNum int) error {
 	// Validate backup number
 	if backupNum < 1 || backupNum > MaxBackupCount {
@@ -170,11 +175,23 @@ func RestoreBackupForStorage(storagePath string, backupNum int) error {
 		}
 	}
 
+	if IsDirectory(storagePath) {
+		return ErrDirectoryBackup
+	}
+
 	backupPath, err := getBackupPathWithError(storagePath, backupNum)
 	if err != nil {
 		return err
 	}
 
+	// Hold the lock for the whole restore, so no entry is appended between
+	// the safety backup and overwriting the storage file
+	release, err := lockStorage(storagePath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// Read backup content BEFORE creating safety backup (CreateBackup rotates backups)
 	backupContent, err := os.ReadFile(backupPath)
 	if err != nil {
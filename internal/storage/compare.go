@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// Checksum returns the SHA-256 checksum of entries as "sha256:<hex>". The
+// entries are canonicalized first: timestamps are converted to UTC and the
+// JSON lines sorted, so the same entries give the same checksum whatever
+// their order in the file or the timezone offset they were written with.
+func Checksum(entries []entry.Entry) string {
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = canonicalLine(e)
+	}
+	sort.Strings(lines)
+
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// canonicalLine returns e as a JSON line with its timestamps in UTC, the
+// form entries are written to storage in
+func canonicalLine(e entry.Entry) string {
+	// Entry struct contains only JSON-safe types, so Marshal cannot fail
+	line, _ := json.Marshal(e.In(time.UTC))
+	return string(line)
+}
+
+// EntryKey identifies an entry when comparing storage files: its timestamp,
+// description and duration. Entries have no stable ID, so an entry edited in
+// one of the files is matched as long as these are unchanged.
+func EntryKey(e entry.Entry) string {
+	return fmt.Sprintf("%s|%s|%d", e.Timestamp.UTC().Format(time.RFC3339Nano), e.Description, e.DurationMinutes)
+}
+
+// EntryChange is an entry found in both files under the same EntryKey, but
+// with different other fields (e.g. project or tags)
+type EntryChange struct {
+	A entry.Entry
+	B entry.Entry
+}
+
+// Comparison is the result of comparing the entries of two storage files
+type Comparison struct {
+	OnlyA   []entry.Entry // Entries only in the first file
+	OnlyB   []entry.Entry // Entries only in the second file
+	Changed []EntryChange // Entries in both files that differ beyond their EntryKey
+}
+
+// Equal reports whether both files have the same entries
+func (c Comparison) Equal() bool {
+	return len(c.OnlyA) == 0 && len(c.OnlyB) == 0 && len(c.Changed) == 0
+}
+
+// CompareEntries compares the entries of two storage files. Identical entries
+// are matched first, then entries with the same EntryKey; an entry given
+// twice in one file only matches twice in the other. The results are sorted
+// by timestamp.
+func CompareEntries(a, b []entry.Entry) Comparison {
+	// Match identical entries, keeping the unmatched ones in order
+	identical := make(map[string]int)
+	for _, e := range b {
+		identical[canonicalLine(e)]++
+	}
+	var restA []entry.Entry
+	for _, e := range a {
+		if line := canonicalLine(e); identical[line] > 0 {
+			identical[line]--
+			continue
+		}
+		restA = append(restA, e)
+	}
+	byKey := make(map[string][]entry.Entry)
+	var keys []string
+	for _, e := range b {
+		line := canonicalLine(e)
+		if identical[line] == 0 {
+			continue
+		}
+		identical[line]--
+		key := EntryKey(e)
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] = append(byKey[key], e)
+	}
+
+	// Match the rest by key
+	var c Comparison
+	for _, e := range restA {
+		key := EntryKey(e)
+		if matches := byKey[key]; len(matches) > 0 {
+			c.Changed = append(c.Changed, EntryChange{A: e, B: matches[0]})
+			byKey[key] = matches[1:]
+			continue
+		}
+		c.OnlyA = append(c.OnlyA, e)
+	}
+	for _, key := range keys {
+		c.OnlyB = append(c.OnlyB, byKey[key]...)
+	}
+
+	sortByTimestamp(c.OnlyA)
+	sortByTimestamp(c.OnlyB)
+	sort.SliceStable(c.Changed, func(i, j int) bool {
+		return c.Changed[i].A.Timestamp.Before(c.Changed[j].A.Timestamp)
+	})
+	return c
+}
+
+// sortByTimestamp sorts entries by timestamp, keeping the order of equal timestamps
+func sortByTimestamp(entries []entry.Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+func TestChecksum(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	first := entry.Entry{Timestamp: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), Description: "standup", DurationMinutes: 15}
+	second := entry.Entry{Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), Description: "review", DurationMinutes: 30, Project: "acme"}
+
+	sum := Checksum([]entry.Entry{first, second})
+	if !strings.HasPrefix(sum, "sha256:") || len(sum) != len("sha256:")+64 {
+		t.Fatalf("Checksum() = %q, expected sha256:<64 hex digits>", sum)
+	}
+
+	// Order and timezone offset don't matter
+	moved := first
+	moved.Timestamp = first.Timestamp.In(berlin)
+	if got := Checksum([]entry.Entry{second, moved}); got != sum {
+		t.Errorf("Expected the same checksum for reordered entries in another zone, got %q and %q", got, sum)
+	}
+
+	// Any other change does
+	edited := second
+	edited.Tags = []string{"review"}
+	if got := Checksum([]entry.Entry{first, edited}); got == sum {
+		t.Error("Expected a different checksum after adding a tag")
+	}
+	if got := Checksum([]entry.Entry{first}); got == sum {
+		t.Error("Expected a different checksum without an entry")
+	}
+}
+
+func TestCompareEntries(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2024, 1, 15, hour, 0, 0, 0, time.UTC)
+	}
+	standup := entry.Entry{Timestamp: at(9), Description: "standup", DurationMinutes: 15}
+	review := entry.Entry{Timestamp: at(10), Description: "review", DurationMinutes: 30, Project: "acme"}
+	reviewTagged := review
+	reviewTagged.Tags = []string{"pr"}
+	deploy := entry.Entry{Timestamp: at(11), Description: "deploy", DurationMinutes: 20}
+	lunch := entry.Entry{Timestamp: at(12), Description: "lunch", DurationMinutes: 60}
+
+	c := CompareEntries(
+		[]entry.Entry{lunch, standup, review, standup},
+		[]entry.Entry{reviewTagged, deploy, standup},
+	)
+
+	if len(c.OnlyA) != 2 || c.OnlyA[0].Description != "standup" || c.OnlyA[1].Description != "lunch" {
+		t.Errorf("OnlyA = %+v, expected the second standup and lunch in time order", c.OnlyA)
+	}
+	if len(c.OnlyB) != 1 || c.OnlyB[0].Description != "deploy" {
+		t.Errorf("OnlyB = %+v, expected deploy", c.OnlyB)
+	}
+	if len(c.Changed) != 1 || len(c.Changed[0].A.Tags) != 0 || len(c.Changed[0].B.Tags) != 1 {
+		t.Errorf("Changed = %+v, expected review with and without its tag", c.Changed)
+	}
+	if c.Equal() {
+		t.Error("Equal() = true for different entries")
+	}
+
+	if same := CompareEntries([]entry.Entry{standup, review}, []entry.Entry{review, standup}); !same.Equal() {
+		t.Errorf("Expected reordered entries to compare equal, got %+v", same)
+	}
+}
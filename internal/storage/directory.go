@@ -0,0 +1,272 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// ErrNoWriteFile is returned when an entry is written to directory storage
+// without saying which file in the directory it belongs to.
+var ErrNoWriteFile = errors.New("storage path is a directory: set my_file in the config to choose the file your entries are written to")
+
+// ErrDirectoryBackup is returned when backups are requested for a storage directory
+var ErrDirectoryBackup = errors.New("backups are not supported when the storage path is a directory")
+
+// IsDirectory reports whether the storage path is an existing directory.
+// A directory holds one *.jsonl file per team member; entries from every file
+// are read together and new entries are written to the configured my_file.
+func IsDirectory(storagePath string) bool {
+	info, err := os.Stat(storagePath)
+	return err == nil && info.IsDir()
+}
+
+// IsEntriesFileName reports whether name is a valid storage file name inside
+// a storage directory: a plain file name ending in .jsonl
+func IsEntriesFileName(name string) bool {
+	return name != "" &&
+		filepath.Base(name) == name &&
+		!strings.ContainsAny(name, `/\`) &&
+		strings.HasSuffix(name, ".jsonl") &&
+		name != ".jsonl"
+}
+
+// directoryFiles returns the names of the *.jsonl files in dir, sorted by name
+func directoryFiles(dir string) ([]string, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, de := range dirEntries {
+		if !de.IsDir() && IsEntriesFileName(de.Name()) {
+			names = append(names, de.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// readDirectoryWithWarnings reads every *.jsonl file in dir in name order.
+// Entries get their file name as Source and warnings their file name as File.
+// The entries of the files are merged by timestamp (see mergeByTimestamp), so
+// the indices used by edit and delete follow the time order of listings.
+func readDirectoryWithWarnings(dir string) (ReadResult, error) {
+	result := ReadResult{
+		Entries:  []entry.Entry{},
+		Warnings: []ParseWarning{},
+	}
+
+	names, err := directoryFiles(dir)
+	if err != nil {
+		return result, err
+	}
+
+	var files [][]entry.Entry
+	for _, name := range names {
+		fileResult, err := ReadEntriesWithWarnings(filepath.Join(dir, name))
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", name, err)
+		}
+		for i := range fileResult.Entries {
+			fileResult.Entries[i].Source = name
+		}
+		files = append(files, fileResult.Entries)
+		for _, w := range fileResult.Warnings {
+			w.File = name
+			result.Warnings = append(result.Warnings, w)
+		}
+		for _, w := range fileResult.Suspect {
+			w.File = name
+			result.Suspect = append(result.Suspect, w)
+		}
+	}
+	result.Entries = append(result.Entries, mergeByTimestamp(files)...)
+
+	return result, nil
+}
+
+// mergeByTimestamp merges the entries of several files, repeatedly taking the
+// earliest of the next entries of each file; ties go to the file listed first.
+// The entries of each file keep their order, so writing them back groups them
+// into the same files unchanged.
+func mergeByTimestamp(files [][]entry.Entry) []entry.Entry {
+	var merged []entry.Entry
+	next := make([]int, len(files))
+	for {
+		pick := -1
+		for i, entries := range files {
+			if next[i] < len(entries) && (pick < 0 || entries[next[i]].Timestamp.Before(files[pick][next[pick]].Timestamp)) {
+				pick = i
+			}
+		}
+		if pick < 0 {
+			return merged
+		}
+		merged = append(merged, files[pick][next[pick]])
+		next[pick]++
+	}
+}
+
+// appendPath returns the file AppendEntry writes e to: the storage path
+// itself, or the entry's source file when the storage path is a directory
+func appendPath(storagePath string, e entry.Entry) (string, error) {
+	if !IsDirectory(storagePath) {
+		return storagePath, nil
+	}
+	if !IsEntriesFileName(e.Source) {
+		return "", ErrNoWriteFile
+	}
+	return filepath.Join(storagePath, e.Source), nil
+}
+
+// writeDirectoryEntries writes entries back to the files in dir they came from,
+// grouped by Source. Only files whose entries changed are rewritten, so files
+// of other team members are left alone unless one of their entries was modified.
+func writeDirectoryEntries(dir string, entries []entry.Entry) error {
+	groups := make(map[string][]entry.Entry)
+	for _, e := range entries {
+		if !IsEntriesFileName(e.Source) {
+			return ErrNoWriteFile
+		}
+		groups[e.Source] = append(groups[e.Source], e)
+	}
+
+	names, err := directoryFiles(dir)
+	if err != nil {
+		return err
+	}
+	for name := range groups {
+		if !containsName(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		existing, err := ReadEntries(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if sameEntries(existing, groups[name]) {
+			continue
+		}
+		if err := replaceFileEntries(path, groups[name]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// replaceFileEntries rewrites a single storage file with entries using the
+// atomic write pattern (write to temp file, then rename)
+func replaceFileEntries(path string, entries []entry.Entry) error {
+	return replaceFileEntriesChecked(path, entries, nil)
+}
+
+// replaceFileEntriesChecked is replaceFileEntries calling check, when given,
+// right before the rename; an error from check removes the temp file and
+// leaves the storage file untouched
+func replaceFileEntriesChecked(path string, entries []entry.Entry, check func() error) error {
+	tmpFile := path + ".tmp"
+	file, err := os.OpenFile(tmpFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	if err := writeEntriesToTempFile(file, tmpFile, entries); err != nil {
+		return err
+	}
+
+	if check != nil {
+		if err := check(); err != nil {
+			_ = os.Remove(tmpFile)
+			return err
+		}
+	}
+	return os.Rename(tmpFile, path)
+}
+
+// replaceAllEntries atomically replaces the contents of the storage file, or of
+// the changed files when the storage path is a directory
+func replaceAllEntries(storagePath string, entries []entry.Entry) error {
+	if IsDirectory(storagePath) {
+		return writeDirectoryEntries(storagePath, entries)
+	}
+	return replaceFileEntries(storagePath, entries)
+}
+
+// sameEntries reports whether a and b are stored as the same lines
+func sameEntries(a, b []entry.Entry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if canonicalLine(a[i]) != canonicalLine(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsName reports whether names contains name
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDirectory validates every *.jsonl file in dir, returning the combined
+// health with a per-file breakdown in Files
+func validateDirectory(dir string) (StorageHealth, error) {
+	health := StorageHealth{
+		Warnings: []ParseWarning{},
+		Files:    []FileHealth{},
+	}
+
+	names, err := directoryFiles(dir)
+	if err != nil {
+		return health, err
+	}
+
+	for _, name := range names {
+		fileHealth, err := ValidateStorage(filepath.Join(dir, name))
+		if err != nil {
+			return health, fmt.Errorf("%s: %w", name, err)
+		}
+		health.TotalLines += fileHealth.TotalLines
+		health.ValidEntries += fileHealth.ValidEntries
+		health.CorruptedEntries += fileHealth.CorruptedEntries
+		health.OutOfOrder += fileHealth.OutOfOrder
+		for _, w := range fileHealth.Warnings {
+			w.File = name
+			health.Warnings = append(health.Warnings, w)
+		}
+		for _, w := range fileHealth.Suspect {
+			w.File = name
+			health.Suspect = append(health.Suspect, w)
+		}
+		for _, w := range fileHealth.Backdated {
+			w.File = name
+			health.Backdated = append(health.Backdated, w)
+		}
+		health.Files = append(health.Files, FileHealth{
+			Name:             name,
+			TotalLines:       fileHealth.TotalLines,
+			ValidEntries:     fileHealth.ValidEntries,
+			CorruptedEntries: fileHealth.CorruptedEntries,
+		})
+	}
+
+	return health, nil
+}
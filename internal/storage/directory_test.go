@@ -0,0 +1,399 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// createStorageDir creates a storage directory holding the given files
+func createStorageDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+const (
+	aliceLine = `{"timestamp":"2024-01-15T10:00:00Z","description":"alice work","duration_minutes":60,"raw_input":"alice work for 1h"}`
+	bobLine   = `{"timestamp":"2024-01-15T09:00:00Z","description":"bob work","duration_minutes":30,"raw_input":"bob work for 30m"}`
+)
+
+func TestIsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	file := createTempFile(t, "")
+
+	if !IsDirectory(dir) {
+		t.Error("IsDirectory() = false for a directory")
+	}
+	if IsDirectory(file) {
+		t.Error("IsDirectory() = true for a regular file")
+	}
+	if IsDirectory(filepath.Join(dir, "missing")) {
+		t.Error("IsDirectory() = true for a missing path")
+	}
+}
+
+func TestReadEntriesWithWarnings_Directory(t *testing.T) {
+	dir := createStorageDir(t, map[string]string{
+		"bob.jsonl":   bobLine + "\nnot json\n",
+		"alice.jsonl": aliceLine + "\n",
+		"notes.txt":   "ignored\n",
+	})
+	if err := os.Mkdir(filepath.Join(dir, "archive.jsonl"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ReadEntriesWithWarnings(dir)
+	if err != nil {
+		t.Fatalf("ReadEntriesWithWarnings() error = %v", err)
+	}
+
+	if len(result.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(result.Entries))
+	}
+	// The entries of the files are merged by timestamp
+	if result.Entries[0].Description != "bob work" || result.Entries[0].Source != "bob.jsonl" {
+		t.Errorf("Expected bob's earlier entry first, got %q from %q", result.Entries[0].Description, result.Entries[0].Source)
+	}
+	if result.Entries[1].Description != "alice work" || result.Entries[1].Source != "alice.jsonl" {
+		t.Errorf("Expected alice's entry second, got %q from %q", result.Entries[1].Description, result.Entries[1].Source)
+	}
+
+	if len(result.Warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %d", len(result.Warnings))
+	}
+	if result.Warnings[0].File != "bob.jsonl" || result.Warnings[0].LineNumber != 2 {
+		t.Errorf("Expected warning for bob.jsonl line 2, got %s line %d", result.Warnings[0].File, result.Warnings[0].LineNumber)
+	}
+}
+
+func TestReadEntries_DirectoryMergesByTimestamp(t *testing.T) {
+	line := func(hour int, description string) string {
+		return fmt.Sprintf(`{"timestamp":"2024-01-15T%02d:00:00Z","description":%q,"duration_minutes":30,"raw_input":""}`, hour, description)
+	}
+	// carol.jsonl is out of order, e.g. after logging with --at
+	dir := createStorageDir(t, map[string]string{
+		"alice.jsonl": line(8, "a1") + "\n" + line(11, "a2") + "\n",
+		"bob.jsonl":   line(9, "b1") + "\n" + line(11, "b2") + "\n",
+		"carol.jsonl": line(12, "c1") + "\n" + line(10, "c2") + "\n",
+	})
+
+	entries, err := ReadEntries(dir)
+	if err != nil {
+		t.Fatalf("ReadEntries() error = %v", err)
+	}
+	var order []string
+	for _, e := range entries {
+		order = append(order, e.Description)
+	}
+	// Ties go to the file listed first, and carol's entries keep their order
+	if got := strings.Join(order, " "); got != "a1 b1 a2 b2 c1 c2" {
+		t.Errorf("Expected entries in the order a1 b1 a2 b2 c1 c2, got %s", got)
+	}
+
+	// Writing the merged entries back leaves every file unchanged
+	before, _ := os.ReadFile(filepath.Join(dir, "carol.jsonl"))
+	if err := WriteEntries(dir, entries); err != nil {
+		t.Fatalf("WriteEntries() error = %v", err)
+	}
+	if after, _ := os.ReadFile(filepath.Join(dir, "carol.jsonl")); string(after) != string(before) {
+		t.Errorf("Expected carol.jsonl to be unchanged, got %q", after)
+	}
+}
+
+func TestReadEntries_SingleFileHasNoSource(t *testing.T) {
+	path := createTempFile(t, aliceLine+"\n")
+
+	result, err := ReadEntriesWithWarnings(path)
+	if err != nil {
+		t.Fatalf("ReadEntriesWithWarnings() error = %v", err)
+	}
+	if len(result.Entries) != 1 || result.Entries[0].Source != "" {
+		t.Errorf("Expected 1 entry without source, got %+v", result.Entries)
+	}
+}
+
+func TestReadEntriesWithWarnings_EmptyDirectory(t *testing.T) {
+	result, err := ReadEntriesWithWarnings(t.TempDir())
+	if err != nil {
+		t.Fatalf("ReadEntriesWithWarnings() error = %v", err)
+	}
+	if len(result.Entries) != 0 || len(result.Warnings) != 0 {
+		t.Errorf("Expected no entries or warnings, got %+v", result)
+	}
+}
+
+func TestAppendEntry_Directory(t *testing.T) {
+	dir := createStorageDir(t, map[string]string{
+		"bob.jsonl": bobLine + "\n",
+	})
+
+	e := entry.Entry{
+		Timestamp:       time.Date(2024, 1, 16, 10, 0, 0, 0, time.UTC),
+		Description:     "new work",
+		DurationMinutes: 45,
+		RawInput:        "new work for 45m",
+		Source:          "alice.jsonl",
+	}
+	if err := AppendEntry(dir, e); err != nil {
+		t.Fatalf("AppendEntry() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "alice.jsonl"))
+	if err != nil {
+		t.Fatalf("Expected alice.jsonl to be created: %v", err)
+	}
+	if !strings.Contains(string(data), "new work") {
+		t.Errorf("Expected new entry in alice.jsonl, got %q", data)
+	}
+	if strings.Contains(string(data), "alice.jsonl") {
+		t.Errorf("Source should not be written to storage, got %q", data)
+	}
+
+	bobData, _ := os.ReadFile(filepath.Join(dir, "bob.jsonl"))
+	if string(bobData) != bobLine+"\n" {
+		t.Errorf("Expected bob.jsonl to be unchanged, got %q", bobData)
+	}
+}
+
+func TestAppendEntry_DirectoryWithoutFile(t *testing.T) {
+	dir := t.TempDir()
+
+	err := AppendEntry(dir, entry.Entry{Description: "work", DurationMinutes: 30})
+	if !errors.Is(err, ErrNoWriteFile) {
+		t.Errorf("Expected ErrNoWriteFile, got %v", err)
+	}
+
+	err = AppendEntry(dir, entry.Entry{Description: "work", DurationMinutes: 30, Source: "../alice.jsonl"})
+	if !errors.Is(err, ErrNoWriteFile) {
+		t.Errorf("Expected ErrNoWriteFile for a path, got %v", err)
+	}
+}
+
+func TestAppendEntries_Directory(t *testing.T) {
+	dir := createStorageDir(t, map[string]string{
+		"bob.jsonl": bobLine + "\n",
+	})
+
+	entries := []entry.Entry{
+		{Timestamp: time.Date(2024, 1, 16, 10, 0, 0, 0, time.UTC), Description: "alice 1", DurationMinutes: 30, Source: "alice.jsonl"},
+		{Timestamp: time.Date(2024, 1, 16, 11, 0, 0, 0, time.UTC), Description: "bob 2", DurationMinutes: 30, Source: "bob.jsonl"},
+		{Timestamp: time.Date(2024, 1, 16, 12, 0, 0, 0, time.UTC), Description: "alice 2", DurationMinutes: 30, Source: "alice.jsonl"},
+	}
+	written, err := AppendEntries(dir, entries)
+	if err != nil || written != 3 {
+		t.Fatalf("AppendEntries() = %d, %v, expected 3, nil", written, err)
+	}
+
+	alice, _ := os.ReadFile(filepath.Join(dir, "alice.jsonl"))
+	if strings.Count(string(alice), "\n") != 2 || strings.Index(string(alice), "alice 1") > strings.Index(string(alice), "alice 2") {
+		t.Errorf("Expected both of alice's entries in order, got %q", alice)
+	}
+	bob, _ := os.ReadFile(filepath.Join(dir, "bob.jsonl"))
+	if !strings.HasPrefix(string(bob), bobLine+"\n") || !strings.Contains(string(bob), "bob 2") {
+		t.Errorf("Expected bob's entry appended to bob.jsonl, got %q", bob)
+	}
+}
+
+func TestAppendEntries_DirectoryWithoutFileWritesNothing(t *testing.T) {
+	dir := t.TempDir()
+
+	entries := []entry.Entry{
+		{Description: "work", DurationMinutes: 30, Source: "alice.jsonl"},
+		{Description: "work", DurationMinutes: 30},
+	}
+	written, err := AppendEntries(dir, entries)
+	if !errors.Is(err, ErrNoWriteFile) || written != 0 {
+		t.Errorf("AppendEntries() = %d, %v, expected 0, ErrNoWriteFile", written, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "alice.jsonl")); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be written when any entry has no file")
+	}
+}
+
+func TestUpdateEntry_DirectoryOnlyRewritesChangedFile(t *testing.T) {
+	dir := createStorageDir(t, map[string]string{
+		"alice.jsonl": aliceLine + "\n",
+		"bob.jsonl":   bobLine + "\nnot json\n",
+	})
+
+	entries, err := ReadEntries(dir)
+	if err != nil {
+		t.Fatalf("ReadEntries() error = %v", err)
+	}
+
+	updated := entries[1]
+	updated.Description = "alice review"
+	updated.Source = ""
+	if err := UpdateEntry(dir, 1, updated); err != nil {
+		t.Fatalf("UpdateEntry() error = %v", err)
+	}
+
+	aliceData, _ := os.ReadFile(filepath.Join(dir, "alice.jsonl"))
+	if !strings.Contains(string(aliceData), "alice review") {
+		t.Errorf("Expected update in alice.jsonl, got %q", aliceData)
+	}
+
+	// bob.jsonl keeps its corrupted line because it was not rewritten
+	bobData, _ := os.ReadFile(filepath.Join(dir, "bob.jsonl"))
+	if string(bobData) != bobLine+"\nnot json\n" {
+		t.Errorf("Expected bob.jsonl to be unchanged, got %q", bobData)
+	}
+}
+
+func TestSoftDeleteEntry_Directory(t *testing.T) {
+	dir := createStorageDir(t, map[string]string{
+		"alice.jsonl": aliceLine + "\n",
+		"bob.jsonl":   bobLine + "\n",
+	})
+
+	deleted, err := SoftDeleteEntry(dir, 0)
+	if err != nil {
+		t.Fatalf("SoftDeleteEntry() error = %v", err)
+	}
+	if deleted.Description != "bob work" {
+		t.Errorf("Expected bob's entry to be deleted, got %q", deleted.Description)
+	}
+
+	entries, err := ReadEntries(dir)
+	if err != nil {
+		t.Fatalf("ReadEntries() error = %v", err)
+	}
+	if entries[0].DeletedAt == nil || entries[1].DeletedAt != nil {
+		t.Errorf("Expected only bob's entry to be deleted, got %+v", entries)
+	}
+
+	aliceData, _ := os.ReadFile(filepath.Join(dir, "alice.jsonl"))
+	if string(aliceData) != aliceLine+"\n" {
+		t.Errorf("Expected alice.jsonl to be unchanged, got %q", aliceData)
+	}
+}
+
+func TestReplaceEntries_DirectoryInheritsSource(t *testing.T) {
+	dir := createStorageDir(t, map[string]string{
+		"alice.jsonl": aliceLine + "\n",
+		"bob.jsonl":   bobLine + "\n",
+	})
+
+	parts := []entry.Entry{
+		{Timestamp: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), Description: "part one", DurationMinutes: 15},
+		{Timestamp: time.Date(2024, 1, 15, 9, 15, 0, 0, time.UTC), Description: "part two", DurationMinutes: 15},
+	}
+	if err := ReplaceEntries(dir, []int{0}, parts); err != nil {
+		t.Fatalf("ReplaceEntries() error = %v", err)
+	}
+
+	bobEntries, err := ReadEntries(filepath.Join(dir, "bob.jsonl"))
+	if err != nil {
+		t.Fatalf("ReadEntries() error = %v", err)
+	}
+	if len(bobEntries) != 2 || bobEntries[0].Description != "part one" || bobEntries[1].Description != "part two" {
+		t.Errorf("Expected the parts in bob.jsonl, got %+v", bobEntries)
+	}
+
+	aliceData, _ := os.ReadFile(filepath.Join(dir, "alice.jsonl"))
+	if string(aliceData) != aliceLine+"\n" {
+		t.Errorf("Expected alice.jsonl to be unchanged, got %q", aliceData)
+	}
+}
+
+func TestWriteEntries_DirectoryEmptiesFileWithoutEntries(t *testing.T) {
+	dir := createStorageDir(t, map[string]string{
+		"alice.jsonl": aliceLine + "\n",
+		"bob.jsonl":   bobLine + "\n",
+	})
+
+	entries, err := ReadEntries(dir)
+	if err != nil {
+		t.Fatalf("ReadEntries() error = %v", err)
+	}
+	if err := WriteEntries(dir, entries[1:]); err != nil {
+		t.Fatalf("WriteEntries() error = %v", err)
+	}
+
+	bobData, err := os.ReadFile(filepath.Join(dir, "bob.jsonl"))
+	if err != nil {
+		t.Fatalf("Expected bob.jsonl to be kept: %v", err)
+	}
+	if len(bobData) != 0 {
+		t.Errorf("Expected bob.jsonl to be empty, got %q", bobData)
+	}
+}
+
+func TestWriteEntries_DirectoryWithoutSource(t *testing.T) {
+	dir := t.TempDir()
+
+	err := WriteEntries(dir, []entry.Entry{{Description: "work", DurationMinutes: 30}})
+	if !errors.Is(err, ErrNoWriteFile) {
+		t.Errorf("Expected ErrNoWriteFile, got %v", err)
+	}
+}
+
+func TestValidateStorage_Directory(t *testing.T) {
+	dir := createStorageDir(t, map[string]string{
+		"alice.jsonl": aliceLine + "\n" + aliceLine + "\n",
+		"bob.jsonl":   bobLine + "\n{broken\n",
+	})
+
+	health, err := ValidateStorage(dir)
+	if err != nil {
+		t.Fatalf("ValidateStorage() error = %v", err)
+	}
+
+	if health.TotalLines != 4 || health.ValidEntries != 3 || health.CorruptedEntries != 1 {
+		t.Errorf("Expected 4 lines, 3 valid and 1 corrupted, got %d, %d and %d",
+			health.TotalLines, health.ValidEntries, health.CorruptedEntries)
+	}
+	if len(health.Warnings) != 1 || health.Warnings[0].File != "bob.jsonl" {
+		t.Errorf("Expected 1 warning for bob.jsonl, got %+v", health.Warnings)
+	}
+
+	want := []FileHealth{
+		{Name: "alice.jsonl", TotalLines: 2, ValidEntries: 2, CorruptedEntries: 0},
+		{Name: "bob.jsonl", TotalLines: 2, ValidEntries: 1, CorruptedEntries: 1},
+	}
+	if len(health.Files) != len(want) {
+		t.Fatalf("Expected %d files, got %+v", len(want), health.Files)
+	}
+	for i := range want {
+		if health.Files[i] != want[i] {
+			t.Errorf("Files[%d] = %+v, want %+v", i, health.Files[i], want[i])
+		}
+	}
+}
+
+func TestValidateStorage_SingleFileHasNoFiles(t *testing.T) {
+	path := createTempFile(t, aliceLine+"\n")
+
+	health, err := ValidateStorage(path)
+	if err != nil {
+		t.Fatalf("ValidateStorage() error = %v", err)
+	}
+	if len(health.Files) != 0 {
+		t.Errorf("Expected no per-file breakdown for a single file, got %+v", health.Files)
+	}
+}
+
+func TestGetMetaPath_Directory(t *testing.T) {
+	dir := t.TempDir()
+	if got := GetMetaPath(dir); got != filepath.Join(dir, MetaFile) {
+		t.Errorf("GetMetaPath() = %s, want %s", got, filepath.Join(dir, MetaFile))
+	}
+}
+
+func TestCreateBackup_Directory(t *testing.T) {
+	if err := CreateBackup(t.TempDir()); !errors.Is(err, ErrDirectoryBackup) {
+		t.Errorf("Expected ErrDirectoryBackup, got %v", err)
+	}
+}
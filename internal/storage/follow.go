@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// Tail follows a single storage file as entries are appended to it. Every
+// Read returns the entries of the complete lines written since the previous
+// Read, tracked by byte offset, so an entry is never returned twice while the
+// file only grows. A partial last line is left for the next Read.
+type Tail struct {
+	path     string
+	offset   int64       // Byte offset after the last complete line read
+	line     int         // Line number of the last complete line read
+	lastLine []byte      // The last complete line read, with its newline
+	info     os.FileInfo // The file read last, to notice it being replaced
+}
+
+// TailResult is the outcome of a Tail Read
+type TailResult struct {
+	Entries  []entry.Entry  // Entries of the lines read, in file order
+	Warnings []ParseWarning // Warnings about corrupted lines read
+	// Reset is set when the file was rewritten since the previous Read (it
+	// shrank, was replaced, or no longer has the last line read at the
+	// offset), e.g. by an edit. The file was then read from the start again,
+	// so Entries holds every entry, including those returned before.
+	Reset bool
+}
+
+// NewTail returns a Tail of the storage file at path whose first Read
+// returns every entry in the file
+func NewTail(path string) *Tail {
+	return &Tail{path: path}
+}
+
+// Read returns the entries appended to the file since the previous Read. A
+// file that doesn't exist (yet) has no entries; when a file that was read
+// before is removed, the next Read reports a Reset.
+func (t *Tail) Read() (TailResult, error) {
+	var result TailResult
+
+	file, err := os.Open(t.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return result, err
+		}
+		result.Reset = t.offset > 0
+		t.offset, t.line, t.lastLine, t.info = 0, 0, nil, nil
+		return result, nil
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return result, err
+	}
+	rewritten, err := t.rewritten(file, info)
+	if err != nil {
+		return result, err
+	}
+	if rewritten {
+		result.Reset = true
+		t.offset, t.line, t.lastLine = 0, 0, nil
+	}
+	t.info = info
+
+	if _, err := file.Seek(t.offset, io.SeekStart); err != nil {
+		return result, err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return result, err
+	}
+
+	for {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			break
+		}
+		raw := data[:end+1]
+		data = data[end+1:]
+		t.offset += int64(len(raw))
+		t.line++
+		t.lastLine = append(t.lastLine[:0], raw...)
+
+		lineContent := cleanLine(string(raw[:end]), t.line)
+		var e entry.Entry
+		if err := json.Unmarshal([]byte(lineContent), &e); err != nil {
+			result.Warnings = append(result.Warnings, ParseWarning{
+				LineNumber: t.line,
+				Content:    lineContent,
+				Error:      err.Error(),
+			})
+			continue
+		}
+		result.Entries = append(result.Entries, e.In(displayLocation))
+	}
+	return result, nil
+}
+
+// rewritten reports whether file is no longer the file read up to the offset:
+// another file replaced it, it shrank, or the last line read is gone
+func (t *Tail) rewritten(file *os.File, info os.FileInfo) (bool, error) {
+	if t.offset == 0 {
+		return false, nil
+	}
+	if t.info != nil && !os.SameFile(t.info, info) {
+		return true, nil
+	}
+	if info.Size() < t.offset {
+		return true, nil
+	}
+	last := make([]byte, len(t.lastLine))
+	if _, err := file.ReadAt(last, t.offset-int64(len(last))); err != nil {
+		return false, err
+	}
+	return !bytes.Equal(last, t.lastLine), nil
+}
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// appendRaw appends data to the file at path as is
+func appendRaw(t *testing.T, path, data string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open storage file: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+	if _, err := file.WriteString(data); err != nil {
+		t.Fatalf("Failed to append to storage file: %v", err)
+	}
+}
+
+// readTail reads tail, failing the test on an error
+func readTail(t *testing.T, tail *Tail) TailResult {
+	t.Helper()
+	result, err := tail.Read()
+	if err != nil {
+		t.Fatalf("Read() returned unexpected error: %v", err)
+	}
+	return result
+}
+
+func tailDescriptions(result TailResult) []string {
+	var descriptions []string
+	for _, e := range result.Entries {
+		descriptions = append(descriptions, e.Description)
+	}
+	return descriptions
+}
+
+func TestTail_Appends(t *testing.T) {
+	first := entry.Entry{Timestamp: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), Description: "first", DurationMinutes: 30}
+	second := entry.Entry{Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), Description: "second", DurationMinutes: 15}
+	path := createTempFile(t, utf8BOM+canonicalLine(first)+"\r\n")
+	tail := NewTail(path)
+
+	result := readTail(t, tail)
+	if got := tailDescriptions(result); len(got) != 1 || got[0] != "first" || result.Reset {
+		t.Fatalf("First read = %v (reset %t), expected only the first entry", got, result.Reset)
+	}
+	if result = readTail(t, tail); len(result.Entries) != 0 || result.Reset {
+		t.Errorf("Read without changes = %+v, expected nothing", result)
+	}
+
+	// A partial line is only read once it is complete
+	line := canonicalLine(second)
+	appendRaw(t, path, line[:10])
+	if result = readTail(t, tail); len(result.Entries) != 0 || len(result.Warnings) != 0 {
+		t.Errorf("Read of a partial line = %+v, expected nothing", result)
+	}
+	appendRaw(t, path, line[10:]+"\nnot json\n")
+	result = readTail(t, tail)
+	if got := tailDescriptions(result); len(got) != 1 || got[0] != "second" || result.Reset {
+		t.Errorf("Read after appending = %v (reset %t), expected only the second entry", got, result.Reset)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].LineNumber != 3 || result.Warnings[0].Content != "not json" {
+		t.Errorf("Warnings = %+v, expected line 3 to be reported", result.Warnings)
+	}
+}
+
+func TestTail_Rewrites(t *testing.T) {
+	entries := []entry.Entry{
+		{Timestamp: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), Description: "first", DurationMinutes: 30},
+		{Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), Description: "second", DurationMinutes: 15},
+	}
+	tests := []struct {
+		name    string
+		rewrite func(t *testing.T, path string)
+	}{
+		{"edited", func(t *testing.T, path string) {
+			edited := entries[1]
+			edited.Description = "second, longer"
+			if err := UpdateEntry(path, 1, edited); err != nil {
+				t.Fatalf("UpdateEntry() returned unexpected error: %v", err)
+			}
+		}},
+		{"shrunk", func(t *testing.T, path string) {
+			if _, err := DeleteEntry(path, 0); err != nil {
+				t.Fatalf("DeleteEntry() returned unexpected error: %v", err)
+			}
+		}},
+		{"rewritten in place", func(t *testing.T, path string) {
+			edited := entries[1]
+			edited.Description = "SECOND"
+			if err := writeEntries(path, []entry.Entry{entries[0], edited}); err != nil {
+				t.Fatalf("writeEntries() returned unexpected error: %v", err)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := createTempFile(t, canonicalLine(entries[0])+"\n"+canonicalLine(entries[1])+"\n")
+			tail := NewTail(path)
+			readTail(t, tail)
+
+			tt.rewrite(t, path)
+			result := readTail(t, tail)
+			want, _ := ReadEntries(path)
+			if !result.Reset || len(result.Entries) != len(want) {
+				t.Errorf("Read after the rewrite = %v (reset %t), expected all %d entries again with a reset", tailDescriptions(result), result.Reset, len(want))
+			}
+			if result = readTail(t, tail); len(result.Entries) != 0 || result.Reset {
+				t.Errorf("Read after the reset = %+v, expected nothing", result)
+			}
+		})
+	}
+}
+
+func TestTail_Removed(t *testing.T) {
+	e := entry.Entry{Timestamp: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), Description: "first", DurationMinutes: 30}
+	path := createTempFile(t, "")
+	tail := NewTail(path)
+	if result := readTail(t, tail); len(result.Entries) != 0 || result.Reset {
+		t.Errorf("Read of a missing file = %+v, expected nothing", result)
+	}
+
+	appendRaw(t, path, canonicalLine(e)+"\n")
+	readTail(t, tail)
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Failed to remove storage file: %v", err)
+	}
+	if result := readTail(t, tail); !result.Reset || len(result.Entries) != 0 {
+		t.Errorf("Read after removing the file = %+v, expected a reset without entries", result)
+	}
+}
@@ -3,15 +3,16 @@ package storage
 import (
 	"bufio"
 	"encoding/json"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/xolan/did/internal/entry"
 )
 
 func writeEntriesToFile(file *os.File, entries []entry.Entry) error {
 	for _, e := range entries {
-		line, _ := json.Marshal(e)
-		if _, err := file.WriteString(string(line) + "\n"); err != nil {
+		if _, err := file.WriteString(canonicalLine(e) + "\n"); err != nil {
 			return err
 		}
 	}
@@ -20,8 +21,7 @@ func writeEntriesToFile(file *os.File, entries []entry.Entry) error {
 
 func writeEntriesToTempFile(file *os.File, tmpFile string, entries []entry.Entry) error {
 	for _, e := range entries {
-		line, _ := json.Marshal(e)
-		if _, err := file.WriteString(string(line) + "\n"); err != nil {
+		if _, err := file.WriteString(canonicalLine(e) + "\n"); err != nil {
 			_ = file.Close()
 			_ = os.Remove(tmpFile)
 			return err
@@ -36,9 +36,28 @@ func writeEntriesToTempFile(file *os.File, tmpFile string, entries []entry.Entry
 }
 
 func validateStorageScanAndRead(file *os.File, filepath string, health *StorageHealth) error {
+	var previous time.Time
+	previousLine := 0
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		health.TotalLines++
+		line := cleanLine(scanner.Text(), health.TotalLines)
+
+		// Compare each entry with the one on the previous entry line; corrupted lines are skipped
+		var e entry.Entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			continue
+		}
+		e = e.In(displayLocation)
+		if previousLine > 0 && e.Timestamp.Before(previous) {
+			health.Backdated = append(health.Backdated, ParseWarning{
+				LineNumber: health.TotalLines,
+				Content:    line,
+				Error: fmt.Sprintf("dated %s, before the entry on line %d (%s)",
+					e.Timestamp.Format("2006-01-02 15:04"), previousLine, previous.Format("2006-01-02 15:04")),
+			})
+		}
+		previous, previousLine = e.Timestamp, health.TotalLines
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -52,7 +71,9 @@ func validateStorageScanAndRead(file *os.File, filepath string, health *StorageH
 
 	health.ValidEntries = len(result.Entries)
 	health.CorruptedEntries = len(result.Warnings)
+	health.OutOfOrder = CountOutOfOrder(result.Entries)
 	health.Warnings = result.Warnings
+	health.Suspect = result.Suspect
 	return nil
 }
 
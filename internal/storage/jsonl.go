@@ -2,13 +2,14 @@ package storage
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
-	"github.com/xolan/did/internal/app"
 	"github.com/xolan/did/internal/entry"
 	"github.com/xolan/did/internal/osutil"
 )
@@ -18,11 +19,26 @@ const (
 	EntriesFile = "entries.jsonl"
 )
 
+// displayLocation is the location the timestamps of entries read from storage
+// are converted to (see SetDisplayLocation). Entries are written in UTC.
+var displayLocation = time.Local
+
+// SetDisplayLocation sets the location the timestamps of entries read from
+// storage are converted to, e.g. the configured timezone. A nil loc restores
+// the local timezone.
+func SetDisplayLocation(loc *time.Location) {
+	if loc == nil {
+		loc = time.Local
+	}
+	displayLocation = loc
+}
+
 // ParseWarning represents a warning about a corrupted or malformed entry
 type ParseWarning struct {
 	LineNumber int    // Line number in the file (1-indexed)
 	Content    string // Raw content of the corrupted line
 	Error      string // Description of the parsing error
+	File       string // File name within a storage directory (empty for a single storage file)
 }
 
 // ReadResult contains the results of reading entries from storage,
@@ -31,49 +47,228 @@ type ParseWarning struct {
 type ReadResult struct {
 	Entries  []entry.Entry  // Successfully parsed entries
 	Warnings []ParseWarning // Warnings about corrupted lines
+	// Suspect describes active entries with a zero or negative duration. They
+	// are still in Entries, so indices don't change, but should be left out
+	// of totals (see entry.Entry.HasValidDuration).
+	Suspect []ParseWarning
 }
 
-// GetStoragePath returns the path to the entries storage file.
-// Uses os.UserConfigDir() for cross-platform XDG-compliant config directory.
-// Creates the config directory if it doesn't exist.
+// GetStoragePath returns the path to the entries storage file, which is
+// DataStoragePath() unless a storage file exists only at the legacy location
+// in the config directory (LegacyStoragePath); that one is used until it is
+// moved with MoveLegacyStorage, so upgrading never loses entries.
+// Nothing is created: the directory is created by the first write, so
+// commands that only read work on a read-only file system.
 func GetStoragePath() (string, error) {
-	configDir, err := osutil.Provider.UserConfigDir()
+	legacyPath, err := LegacyStoragePath()
+	if err != nil {
+		return "", err
+	}
+	dataPath, err := DataStoragePath()
 	if err != nil {
 		return "", err
 	}
 
-	appDir := filepath.Join(configDir, app.Name)
+	if isRegularFile(legacyPath) && !isRegularFile(dataPath) {
+		return legacyPath, nil
+	}
+	return dataPath, nil
+}
 
-	// Create config directory if it doesn't exist
-	if err := osutil.Provider.MkdirAll(appDir, 0755); err != nil {
-		return "", err
+// ResolveStoragePath returns customPath when it is set and falls back to
+// GetStoragePath() otherwise. Like GetStoragePath, it creates nothing.
+func ResolveStoragePath(customPath string) (string, error) {
+	if customPath == "" {
+		return GetStoragePath()
+	}
+
+	return customPath, nil
+}
+
+// EnsureStorageDir creates the directory of the storage file at storagePath
+// if it doesn't exist yet. The storage paths are resolved without creating
+// anything, so commands that add entries call this before the first write.
+func EnsureStorageDir(storagePath string) error {
+	if IsDirectory(storagePath) {
+		return nil
 	}
+	return osutil.Provider.MkdirAll(filepath.Dir(storagePath), 0755)
+}
 
-	return filepath.Join(appDir, EntriesFile), nil
+// AppendOptions controls how AppendEntryWithOptions writes to the storage file.
+type AppendOptions struct {
+	// Sync flushes the file to disk (fsync) after writing so the entry survives power loss
+	Sync bool
 }
 
 // AppendEntry appends a single entry to the JSON Lines storage file.
-// Creates the file if it doesn't exist.
+// Creates the file if it doesn't exist. When the storage path is a directory,
+// the entry is appended to its Source file in that directory.
 // Uses O_APPEND for atomic append operations.
 func AppendEntry(filepath string, e entry.Entry) error {
-	file, err := os.OpenFile(filepath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	return AppendEntryWithOptions(filepath, e, AppendOptions{})
+}
+
+// AppendEntryWithOptions appends a single entry to the JSON Lines storage file.
+// Creates the file if it doesn't exist. The line is written with a single Write
+// call on a file opened with O_APPEND. If the file ends with a partial line
+// (e.g. from a crash mid-write), a newline is prefixed so the new entry starts
+// on its own line. With opts.Sync the file is fsynced and any sync error returned.
+// LoggedAt is stored as given: callers creating a new entry set it, while
+// entries from elsewhere (e.g. an import) without one are stored without it.
+// The write holds the storage lock (see GetLockPath).
+func AppendEntryWithOptions(filepath string, e entry.Entry, opts AppendOptions) error {
+	_, err := AppendEntriesWithOptions(filepath, []entry.Entry{e}, opts)
+	return err
+}
+
+// AppendEntries appends several entries to the JSON Lines storage file at
+// once, so a failure leaves none of them stored. Returns the number of
+// entries written. See AppendEntriesWithOptions.
+func AppendEntries(filepath string, entries []entry.Entry) (int, error) {
+	return AppendEntriesWithOptions(filepath, entries, AppendOptions{})
+}
+
+// AppendEntriesWithOptions appends several entries to the JSON Lines storage
+// file with the same partial-line repair and opts.Sync handling as
+// AppendEntryWithOptions. All lines are written with a single Write call, and
+// if the write or sync fails the file is truncated back to its previous size,
+// so either every entry is stored or none is. The storage lock is held for the
+// whole batch. Returns the number of entries written.
+//
+// When the storage path is a directory, each entry goes to its Source file;
+// every Source is checked before anything is written, and each file gets a
+// single write. A failure on a later file leaves the earlier files written,
+// which the returned count reflects.
+func AppendEntriesWithOptions(filepath string, entries []entry.Entry, opts AppendOptions) (int, error) {
+	// Group the entries by target file, in the order the files first appear
+	var paths []string
+	groups := make(map[string][]entry.Entry)
+	for _, e := range entries {
+		path, err := appendPath(filepath, e)
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := groups[path]; !ok {
+			paths = append(paths, path)
+		}
+		groups[path] = append(groups[path], e)
+	}
+	if len(paths) == 0 {
+		return 0, nil
+	}
+
+	release, err := lockStorage(filepath)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	written := 0
+	for _, path := range paths {
+		if err := appendLines(path, groups[path], opts); err != nil {
+			return written, err
+		}
+		written += len(groups[path])
+	}
+	return written, nil
+}
+
+// appendLines appends entries as lines to the file at path with a single
+// write, truncating the file back to its previous size if that fails
+func appendLines(path string, entries []entry.Entry, opts AppendOptions) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = file.Close() }()
 
-	// Entry struct contains only JSON-safe types, so Marshal cannot fail
-	line, _ := json.Marshal(e)
+	var lines []byte
+	for _, e := range entries {
+		lines = append(append(lines, canonicalLine(e)...), '\n')
+	}
 
-	_, err = file.WriteString(string(line) + "\n")
-	return err
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	partial, err := endsWithPartialLine(file)
+	if err != nil {
+		return err
+	}
+	if partial {
+		lines = append([]byte{'\n'}, lines...)
+	}
+
+	_, err = file.Write(lines)
+	if err == nil && opts.Sync {
+		err = file.Sync()
+	}
+	if err != nil {
+		// Don't leave some of the entries, or half a line, behind
+		_ = file.Truncate(info.Size())
+		return err
+	}
+	return nil
+}
+
+// endsWithPartialLine reports whether the file is non-empty and its last byte is not a newline
+func endsWithPartialLine(file *os.File) (bool, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+	if info.Size() == 0 {
+		return false, nil
+	}
+
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, info.Size()-1); err != nil {
+		return false, err
+	}
+	return last[0] != '\n', nil
+}
+
+// utf8BOM is the byte order mark some editors (e.g. Notepad) write at the
+// start of a UTF-8 file
+const utf8BOM = "\ufeff"
+
+// cleanLine strips what editors may add to a line of a storage file: the
+// carriage return of a CRLF line ending and, on the first line, a UTF-8 byte
+// order mark. Lines written by did are returned unchanged.
+func cleanLine(line string, lineNumber int) string {
+	if lineNumber == 1 {
+		line = strings.TrimPrefix(line, utf8BOM)
+	}
+	return strings.TrimSuffix(line, "\r")
+}
+
+// scanRawLines is bufio.ScanLines keeping the carriage return of CRLF line
+// endings, for the rewrites that report the lines cleanLine changes
+func scanRawLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
 }
 
 // ReadEntriesWithWarnings reads all entries from the JSON Lines storage file
 // and returns both successfully parsed entries and warnings about any corrupted lines.
 // Returns an empty ReadResult if the file doesn't exist (graceful handling).
 // Collects detailed warnings for each malformed line including line number, content, and error.
+// A leading byte order mark and CRLF line endings are tolerated (see cleanLine).
+// When the storage path is a directory, every *.jsonl file in it is read (sorted by name).
 func ReadEntriesWithWarnings(filepath string) (ReadResult, error) {
+	if IsDirectory(filepath) {
+		return readDirectoryWithWarnings(filepath)
+	}
+
 	result := ReadResult{
 		Entries:  []entry.Entry{},
 		Warnings: []ParseWarning{},
@@ -92,7 +287,7 @@ func ReadEntriesWithWarnings(filepath string) (ReadResult, error) {
 	lineNumber := 0
 	for scanner.Scan() {
 		lineNumber++
-		lineContent := scanner.Text()
+		lineContent := cleanLine(scanner.Text(), lineNumber)
 
 		var e entry.Entry
 		if err := json.Unmarshal([]byte(lineContent), &e); err != nil {
@@ -104,6 +299,14 @@ func ReadEntriesWithWarnings(filepath string) (ReadResult, error) {
 			})
 			continue
 		}
+		e = e.In(displayLocation)
+		if e.DeletedAt == nil && !e.HasValidDuration() {
+			result.Suspect = append(result.Suspect, ParseWarning{
+				LineNumber: lineNumber,
+				Content:    lineContent,
+				Error:      fmt.Sprintf("duration is not positive (%d minutes)", e.DurationMinutes),
+			})
+		}
 		result.Entries = append(result.Entries, e)
 	}
 
@@ -147,7 +350,24 @@ func ReadActiveEntries(filepath string) ([]entry.Entry, error) {
 // WriteEntries writes all entries to the JSON Lines storage file.
 // Overwrites the file if it exists. Creates the file with 0644 permissions.
 // This is used for operations that modify existing entries (e.g., delete, update).
+// When the storage path is a directory, each entry is written back to its Source file.
+// The write holds the storage lock (see GetLockPath).
 func WriteEntries(filepath string, entries []entry.Entry) error {
+	release, err := lockStorage(filepath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return writeEntries(filepath, entries)
+}
+
+// writeEntries writes all entries to the storage path without taking the lock
+func writeEntries(filepath string, entries []entry.Entry) error {
+	if IsDirectory(filepath) {
+		return writeDirectoryEntries(filepath, entries)
+	}
+
 	file, err := os.OpenFile(filepath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 	if err != nil {
 		return err
@@ -161,6 +381,12 @@ func WriteEntries(filepath string, entries []entry.Entry) error {
 // Index is 0-based. Returns an error if the index is out of bounds.
 // The entry remains in the file but is marked as deleted.
 func SoftDeleteEntry(filepath string, index int) (entry.Entry, error) {
+	release, err := lockStorage(filepath)
+	if err != nil {
+		return entry.Entry{}, err
+	}
+	defer release()
+
 	entries, err := ReadEntries(filepath)
 	if err != nil {
 		return entry.Entry{}, err
@@ -176,7 +402,7 @@ func SoftDeleteEntry(filepath string, index int) (entry.Entry, error) {
 
 	deleted := entries[index]
 
-	if err := WriteEntries(filepath, entries); err != nil {
+	if err := writeEntries(filepath, entries); err != nil {
 		return entry.Entry{}, err
 	}
 
@@ -219,6 +445,12 @@ func GetMostRecentlyDeleted(filepath string) (entry.Entry, int, error) {
 // Index is 0-based. Returns an error if the index is out of bounds.
 // Returns the restored entry for confirmation.
 func RestoreEntry(filepath string, index int) (entry.Entry, error) {
+	release, err := lockStorage(filepath)
+	if err != nil {
+		return entry.Entry{}, err
+	}
+	defer release()
+
 	entries, err := ReadEntries(filepath)
 	if err != nil {
 		return entry.Entry{}, err
@@ -233,7 +465,7 @@ func RestoreEntry(filepath string, index int) (entry.Entry, error) {
 
 	restored := entries[index]
 
-	if err := WriteEntries(filepath, entries); err != nil {
+	if err := writeEntries(filepath, entries); err != nil {
 		return entry.Entry{}, err
 	}
 
@@ -244,6 +476,12 @@ func RestoreEntry(filepath string, index int) (entry.Entry, error) {
 // Returns the count of purged entries.
 // This operation cannot be undone.
 func PurgeDeletedEntries(filepath string) (int, error) {
+	release, err := lockStorage(filepath)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
 	entries, err := ReadEntries(filepath)
 	if err != nil {
 		return 0, err
@@ -262,7 +500,7 @@ func PurgeDeletedEntries(filepath string) (int, error) {
 
 	// Only write back if there were deleted entries to purge
 	if deletedCount > 0 {
-		if err := WriteEntries(filepath, activeEntries); err != nil {
+		if err := writeEntries(filepath, activeEntries); err != nil {
 			return 0, err
 		}
 	}
@@ -275,6 +513,12 @@ func PurgeDeletedEntries(filepath string) (int, error) {
 // Does not affect recently deleted entries (deleted within the last 7 days).
 // This operation cannot be undone.
 func CleanupOldDeleted(filepath string) (int, error) {
+	release, err := lockStorage(filepath)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
 	entries, err := ReadEntries(filepath)
 	if err != nil {
 		return 0, err
@@ -300,7 +544,7 @@ func CleanupOldDeleted(filepath string) (int, error) {
 
 	// Only write back if there were old deleted entries to clean up
 	if cleanedCount > 0 {
-		if err := WriteEntries(filepath, keptEntries); err != nil {
+		if err := writeEntries(filepath, keptEntries); err != nil {
 			return 0, err
 		}
 	}
@@ -312,6 +556,12 @@ func CleanupOldDeleted(filepath string) (int, error) {
 // Index is 0-based. Returns an error if the index is out of bounds.
 // Rewrites the entire file without the deleted entry.
 func DeleteEntry(filepath string, index int) (entry.Entry, error) {
+	release, err := lockStorage(filepath)
+	if err != nil {
+		return entry.Entry{}, err
+	}
+	defer release()
+
 	entries, err := ReadEntries(filepath)
 	if err != nil {
 		return entry.Entry{}, err
@@ -326,7 +576,7 @@ func DeleteEntry(filepath string, index int) (entry.Entry, error) {
 	// Remove the entry by creating a new slice without it
 	newEntries := append(entries[:index], entries[index+1:]...)
 
-	if err := WriteEntries(filepath, newEntries); err != nil {
+	if err := writeEntries(filepath, newEntries); err != nil {
 		return entry.Entry{}, err
 	}
 
@@ -338,6 +588,12 @@ func DeleteEntry(filepath string, index int) (entry.Entry, error) {
 // Returns error if index is out of range.
 // Uses atomic write pattern (write to temp file, then rename) for safety.
 func UpdateEntry(filepath string, index int, e entry.Entry) error {
+	release, err := lockStorage(filepath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// Read all entries
 	entries, err := ReadEntries(filepath)
 	if err != nil {
@@ -349,21 +605,70 @@ func UpdateEntry(filepath string, index int, e entry.Entry) error {
 		return os.ErrInvalid
 	}
 
-	// Update the entry at the specified index
+	// Update the entry at the specified index, keeping it in the file it came from
+	if e.Source == "" {
+		e.Source = entries[index].Source
+	}
 	entries[index] = e
 
-	// Write to temporary file
-	tmpFile := filepath + ".tmp"
-	file, err := os.OpenFile(tmpFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	return replaceAllEntries(filepath, entries)
+}
+
+// ReplaceEntries replaces the entries at the given indices with the replacement entries.
+// Uses 0-based indexing internally (caller handles 1-based conversion).
+// The replacements are inserted, in order, at the position of the lowest index;
+// the other entries at the given indices are removed.
+// Returns error if any index is out of range or repeated.
+// Uses atomic write pattern (write to temp file, then rename) for safety.
+func ReplaceEntries(filepath string, indices []int, replacements []entry.Entry) error {
+	if len(indices) == 0 {
+		return os.ErrInvalid
+	}
+
+	release, err := lockStorage(filepath)
 	if err != nil {
 		return err
 	}
+	defer release()
 
-	if err := writeEntriesToTempFile(file, tmpFile, entries); err != nil {
+	// Read all entries
+	entries, err := ReadEntries(filepath)
+	if err != nil {
 		return err
 	}
 
-	return os.Rename(tmpFile, filepath)
+	// Validate indices
+	remove := make(map[int]bool, len(indices))
+	first := indices[0]
+	for _, index := range indices {
+		if index < 0 || index >= len(entries) || remove[index] {
+			return os.ErrInvalid
+		}
+		remove[index] = true
+		if index < first {
+			first = index
+		}
+	}
+
+	// Build the new entry list with the replacements at the first index.
+	// Replacements without a source go to the file of the entry they replace.
+	newEntries := make([]entry.Entry, 0, len(entries)-len(indices)+len(replacements))
+	for i, existing := range entries {
+		if i == first {
+			for _, r := range replacements {
+				if r.Source == "" {
+					r.Source = existing.Source
+				}
+				newEntries = append(newEntries, r)
+			}
+			continue
+		}
+		if !remove[i] {
+			newEntries = append(newEntries, existing)
+		}
+	}
+
+	return replaceAllEntries(filepath, newEntries)
 }
 
 // StorageHealth contains information about the health status of the storage file.
@@ -373,13 +678,31 @@ type StorageHealth struct {
 	TotalLines       int            // Total number of lines in the storage file
 	ValidEntries     int            // Number of successfully parsed entries
 	CorruptedEntries int            // Number of corrupted/malformed lines
+	OutOfOrder       int            // Number of entries out of chronological order (see CountOutOfOrder)
+	Backdated        []ParseWarning // Entries dated before the entry on the previous line
 	Warnings         []ParseWarning // Detailed information about each corrupted line
+	Suspect          []ParseWarning // Active entries with a zero or negative duration (see ReadResult)
+	Files            []FileHealth   // Per-file breakdown when the storage path is a directory
+}
+
+// FileHealth contains the health metrics of a single file in a storage directory
+type FileHealth struct {
+	Name             string // File name within the storage directory
+	TotalLines       int    // Total number of lines in the file
+	ValidEntries     int    // Number of successfully parsed entries
+	CorruptedEntries int    // Number of corrupted/malformed lines
 }
 
 // ValidateStorage analyzes the storage file and returns health status information.
 // Returns metrics on total lines, valid entries, corrupted entries, and details
 // about each corruption. Returns empty health status if file doesn't exist.
+// When the storage path is a directory, the metrics cover all of its *.jsonl files
+// and Files has a breakdown per file.
 func ValidateStorage(filepath string) (StorageHealth, error) {
+	if IsDirectory(filepath) {
+		return validateDirectory(filepath)
+	}
+
 	health := StorageHealth{
 		TotalLines:       0,
 		ValidEntries:     0,
@@ -1,8 +1,10 @@
 package storage
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,6 +26,17 @@ func createTempFile(t *testing.T, content string) string {
 	return tmpFile
 }
 
+// unreadableStoragePath returns a storage path below a regular file, so every
+// attempt to read it fails
+func unreadableStoragePath(t *testing.T) string {
+	t.Helper()
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(blocker, "entries.jsonl")
+}
+
 func TestAppendEntry(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -192,6 +205,142 @@ func TestAppendEntry_MultipleEntries(t *testing.T) {
 	}
 }
 
+func TestAppendEntry_KeepsLoggedAt(t *testing.T) {
+	tmpFile := createTempFile(t, "")
+	workedAt := time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)
+	loggedAt := time.Date(2024, time.January, 16, 8, 0, 0, 0, time.UTC)
+
+	if err := AppendEntry(tmpFile, entry.Entry{Timestamp: workedAt, Description: "imported", DurationMinutes: 15}); err != nil {
+		t.Fatalf("AppendEntry() returned unexpected error: %v", err)
+	}
+	if err := AppendEntry(tmpFile, entry.Entry{Timestamp: workedAt, Description: "kept", DurationMinutes: 15, LoggedAt: &loggedAt}); err != nil {
+		t.Fatalf("AppendEntry() returned unexpected error: %v", err)
+	}
+
+	entries, err := ReadEntries(tmpFile)
+	if err != nil {
+		t.Fatalf("ReadEntries() returned unexpected error: %v", err)
+	}
+	// An entry from elsewhere without a LoggedAt does not look freshly logged
+	if entries[0].LoggedAt != nil {
+		t.Errorf("Expected no LoggedAt for an entry without one, got %v", entries[0].LoggedAt)
+	}
+	if !entries[0].Timestamp.Equal(workedAt) {
+		t.Errorf("Expected the timestamp to stay %v, got %v", workedAt, entries[0].Timestamp)
+	}
+	if entries[1].LoggedAt == nil || !entries[1].LoggedAt.Equal(loggedAt) {
+		t.Errorf("Expected an existing LoggedAt to be kept, got %v", entries[1].LoggedAt)
+	}
+
+	// Entries written before logged_at existed still read, without one
+	if err := os.WriteFile(tmpFile, []byte(`{"timestamp":"2024-01-15T09:00:00Z","description":"old","duration_minutes":15,"raw_input":""}`+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if entries, err := ReadEntries(tmpFile); err != nil || len(entries) != 1 || entries[0].LoggedAt != nil {
+		t.Errorf("Expected one entry without LoggedAt, got %+v, %v", entries, err)
+	}
+}
+
+func TestAppendEntry_RepairsPartialLine(t *testing.T) {
+	// Simulate a crash that left a partial final line without a newline
+	tmpFile := createTempFile(t, `{"timestamp":"2024-01-15T09:00:00Z","description":"ok","duration_minutes":30,"raw_input":"ok for 30m"}
+{"timestamp":"2024-01-15T10:00:00Z","descr`)
+
+	e := entry.Entry{
+		Timestamp:       time.Date(2024, time.January, 15, 11, 0, 0, 0, time.UTC),
+		Description:     "after crash",
+		DurationMinutes: 60,
+		RawInput:        "after crash for 1h",
+	}
+	if err := AppendEntry(tmpFile, e); err != nil {
+		t.Fatalf("AppendEntry() returned unexpected error: %v", err)
+	}
+
+	result, err := ReadEntriesWithWarnings(tmpFile)
+	if err != nil {
+		t.Fatalf("ReadEntriesWithWarnings() returned unexpected error: %v", err)
+	}
+	if len(result.Entries) != 2 {
+		t.Fatalf("Expected 2 valid entries, got %d", len(result.Entries))
+	}
+	if result.Entries[1].Description != "after crash" {
+		t.Errorf("Appended entry description = %q, expected %q", result.Entries[1].Description, "after crash")
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].LineNumber != 2 {
+		t.Errorf("Expected only the partial line 2 to be reported, got %+v", result.Warnings)
+	}
+}
+
+func TestAppendEntries(t *testing.T) {
+	// A partial final line is repaired once, before the batch
+	tmpFile := createTempFile(t, `{"timestamp":"2024-01-15T09:00:00Z","description":"ok","duration_minutes":30}
+{"timestamp":"2024-01-15T10:00:00Z","descr`)
+
+	base := time.Date(2024, time.January, 15, 11, 0, 0, 0, time.UTC)
+	entries := []entry.Entry{
+		{Timestamp: base, Description: "first", DurationMinutes: 30},
+		{Timestamp: base.Add(time.Hour), Description: "second", DurationMinutes: 45},
+		{Timestamp: base.Add(2 * time.Hour), Description: "third", DurationMinutes: 60},
+	}
+	written, err := AppendEntriesWithOptions(tmpFile, entries, AppendOptions{Sync: true})
+	if err != nil {
+		t.Fatalf("AppendEntriesWithOptions() returned unexpected error: %v", err)
+	}
+	if written != 3 {
+		t.Errorf("AppendEntriesWithOptions() wrote %d entries, expected 3", written)
+	}
+
+	result, err := ReadEntriesWithWarnings(tmpFile)
+	if err != nil {
+		t.Fatalf("ReadEntriesWithWarnings() returned unexpected error: %v", err)
+	}
+	if len(result.Entries) != 4 || len(result.Warnings) != 1 {
+		t.Fatalf("Expected 4 entries and the partial line, got %d entries and %d warnings", len(result.Entries), len(result.Warnings))
+	}
+	for i, e := range entries {
+		if result.Entries[i+1].Description != e.Description {
+			t.Errorf("Entry %d description = %q, expected %q", i+1, result.Entries[i+1].Description, e.Description)
+		}
+	}
+}
+
+func TestAppendEntries_Empty(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "entries.jsonl")
+
+	written, err := AppendEntries(tmpFile, nil)
+	if err != nil || written != 0 {
+		t.Errorf("AppendEntries(nil) = %d, %v, expected 0, nil", written, err)
+	}
+	if _, err := os.Stat(tmpFile); !os.IsNotExist(err) {
+		t.Error("Expected no file to be created for an empty batch")
+	}
+}
+
+func TestAppendEntryWithOptions_Sync(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "entries.jsonl")
+
+	e := entry.Entry{
+		Timestamp:       time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+		Description:     "durable",
+		DurationMinutes: 30,
+		RawInput:        "durable for 30m",
+	}
+	if err := AppendEntryWithOptions(tmpFile, e, AppendOptions{Sync: true}); err != nil {
+		t.Fatalf("AppendEntryWithOptions() returned unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if !strings.HasSuffix(string(data), "\n") || strings.HasPrefix(string(data), "\n") {
+		t.Errorf("Expected a single newline-terminated line, got %q", string(data))
+	}
+	if strings.Count(string(data), "\n") != 1 {
+		t.Errorf("Expected exactly one line, got %q", string(data))
+	}
+}
+
 func TestReadEntries_Empty(t *testing.T) {
 	// Test with non-existent file
 	tmpDir := t.TempDir()
@@ -416,30 +565,63 @@ func TestReadEntries_LargeFile(t *testing.T) {
 }
 
 func TestGetStoragePath(t *testing.T) {
-	// Test that GetStoragePath returns a valid path
+	_, dataDir := setUserDirs(t)
+
 	path, err := GetStoragePath()
 	if err != nil {
 		t.Fatalf("GetStoragePath() returned unexpected error: %v", err)
 	}
-
-	// Path should not be empty
-	if path == "" {
-		t.Errorf("GetStoragePath() returned empty path")
+	if expected := filepath.Join(dataDir, app.Name, EntriesFile); path != expected {
+		t.Errorf("GetStoragePath() = %q, expected %q", path, expected)
 	}
+}
 
-	// Path should end with entries.jsonl
+func TestResolveStoragePath(t *testing.T) {
+	// Empty custom path falls back to the default location
+	path, err := ResolveStoragePath("")
+	if err != nil {
+		t.Fatalf("ResolveStoragePath(\"\") returned unexpected error: %v", err)
+	}
 	if filepath.Base(path) != EntriesFile {
-		t.Errorf("GetStoragePath() path base = %q, expected %q", filepath.Base(path), EntriesFile)
+		t.Errorf("ResolveStoragePath(\"\") path base = %q, expected %q", filepath.Base(path), EntriesFile)
 	}
 
-	// Parent directory should exist
-	parentDir := filepath.Dir(path)
-	info, err := os.Stat(parentDir)
+	// Custom path is returned as-is and nothing is created
+	custom := filepath.Join(t.TempDir(), "nested", "dir", "time.jsonl")
+	path, err = ResolveStoragePath(custom)
 	if err != nil {
-		t.Errorf("GetStoragePath() parent directory does not exist: %v", err)
+		t.Fatalf("ResolveStoragePath() returned unexpected error: %v", err)
+	}
+	if path != custom {
+		t.Errorf("ResolveStoragePath() = %q, expected %q", path, custom)
 	}
-	if !info.IsDir() {
-		t.Errorf("GetStoragePath() parent is not a directory")
+	if _, err := os.Stat(filepath.Dir(custom)); !os.IsNotExist(err) {
+		t.Errorf("ResolveStoragePath() should not create the parent directory: %v", err)
+	}
+}
+
+func TestEnsureStorageDir(t *testing.T) {
+	custom := filepath.Join(t.TempDir(), "nested", "dir", "time.jsonl")
+	if err := EnsureStorageDir(custom); err != nil {
+		t.Fatalf("EnsureStorageDir() returned unexpected error: %v", err)
+	}
+	if info, err := os.Stat(filepath.Dir(custom)); err != nil || !info.IsDir() {
+		t.Errorf("EnsureStorageDir() should create the parent directory: %v", err)
+	}
+
+	// A storage directory already exists, so nothing is created next to it
+	dir := t.TempDir()
+	defer osutil.ResetProvider()
+	osutil.SetProvider(&mockPathProvider{
+		mkdirAllFn: func(path string, perm os.FileMode) error {
+			return os.ErrPermission
+		},
+	})
+	if err := EnsureStorageDir(dir); err != nil {
+		t.Errorf("EnsureStorageDir() on a storage directory returned unexpected error: %v", err)
+	}
+	if err := EnsureStorageDir(filepath.Join(dir, "missing", EntriesFile)); !errors.Is(err, os.ErrPermission) {
+		t.Errorf("EnsureStorageDir() error = %v, expected the MkdirAll error", err)
 	}
 }
 
@@ -790,6 +972,110 @@ func TestUpdateEntry_InvalidIndex(t *testing.T) {
 	}
 }
 
+func TestReplaceEntries(t *testing.T) {
+	initialContent := `{"timestamp":"2024-01-15T09:00:00Z","description":"first","duration_minutes":60,"raw_input":"first for 1h"}
+{"timestamp":"2024-01-15T10:00:00Z","description":"second","duration_minutes":30,"raw_input":"second for 30m"}
+{"timestamp":"2024-01-15T11:00:00Z","description":"third","duration_minutes":15,"raw_input":"third for 15m"}
+`
+	tmpFile := createTempFile(t, initialContent)
+
+	merged := entry.Entry{
+		Timestamp:       time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC),
+		Description:     "merged",
+		DurationMinutes: 45,
+		RawInput:        "merged for 45m",
+	}
+
+	if err := ReplaceEntries(tmpFile, []int{2, 1}, []entry.Entry{merged}); err != nil {
+		t.Fatalf("ReplaceEntries() returned unexpected error: %v", err)
+	}
+
+	entries, err := ReadEntries(tmpFile)
+	if err != nil {
+		t.Fatalf("ReadEntries() returned unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Description != "first" {
+		t.Errorf("First entry description = %q, expected %q", entries[0].Description, "first")
+	}
+	if entries[1].Description != "merged" {
+		t.Errorf("Replacement should take the lowest index, got %q", entries[1].Description)
+	}
+	if _, err := os.Stat(tmpFile + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Temp file should not remain after ReplaceEntries()")
+	}
+}
+
+func TestReplaceEntries_MultipleReplacements(t *testing.T) {
+	initialContent := `{"timestamp":"2024-01-15T09:00:00Z","description":"first","duration_minutes":60,"raw_input":"first for 1h"}
+{"timestamp":"2024-01-15T10:00:00Z","description":"second","duration_minutes":30,"raw_input":"second for 30m"}
+`
+	tmpFile := createTempFile(t, initialContent)
+
+	parts := []entry.Entry{
+		{Description: "part one", DurationMinutes: 40},
+		{Description: "part two", DurationMinutes: 20},
+	}
+	if err := ReplaceEntries(tmpFile, []int{0}, parts); err != nil {
+		t.Fatalf("ReplaceEntries() returned unexpected error: %v", err)
+	}
+
+	entries, err := ReadEntries(tmpFile)
+	if err != nil {
+		t.Fatalf("ReadEntries() returned unexpected error: %v", err)
+	}
+	expected := []string{"part one", "part two", "second"}
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i, desc := range expected {
+		if entries[i].Description != desc {
+			t.Errorf("Entry %d description = %q, expected %q", i, entries[i].Description, desc)
+		}
+	}
+}
+
+func TestReplaceEntries_InvalidIndices(t *testing.T) {
+	initialContent := `{"timestamp":"2024-01-15T09:00:00Z","description":"first","duration_minutes":60,"raw_input":"first for 1h"}
+{"timestamp":"2024-01-15T10:00:00Z","description":"second","duration_minutes":30,"raw_input":"second for 30m"}
+`
+	tmpFile := createTempFile(t, initialContent)
+
+	tests := []struct {
+		name    string
+		indices []int
+	}{
+		{"no indices", nil},
+		{"negative index", []int{0, -1}},
+		{"index too large", []int{0, 2}},
+		{"repeated index", []int{1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ReplaceEntries(tmpFile, tt.indices, []entry.Entry{{Description: "merged"}}); err == nil {
+				t.Errorf("ReplaceEntries(%v) should return error", tt.indices)
+			}
+		})
+	}
+
+	entries, _ := ReadEntries(tmpFile)
+	if len(entries) != 2 {
+		t.Errorf("File should be unchanged after invalid ReplaceEntries(), got %d entries", len(entries))
+	}
+}
+
+func TestReplaceEntries_ReadError(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Reading a directory as a file fails
+	if err := ReplaceEntries(tmpDir, []int{0, 1}, []entry.Entry{{}}); err == nil {
+		t.Error("ReplaceEntries() should return error when the storage file can't be read")
+	}
+}
+
 func TestValidateStorage(t *testing.T) {
 	fileContent := `{"timestamp":"2024-01-15T09:00:00Z","description":"valid one","duration_minutes":60,"raw_input":"valid for 1h"}
 {"timestamp":"2024-01-15T10:00:00Z","description":"valid two","duration_minutes":30,"raw_input":"valid for 30m"}
@@ -891,6 +1177,97 @@ corrupted line here
 	}
 }
 
+func TestReadEntriesWithWarnings_Suspect(t *testing.T) {
+	fileContent := `{"timestamp":"2024-01-15T09:00:00Z","description":"valid","duration_minutes":60}
+{"timestamp":"2024-01-15T10:00:00Z","description":"zero","duration_minutes":0}
+{"timestamp":"2024-01-15T11:00:00Z","description":"negative","duration_minutes":-30}
+{"timestamp":"2024-01-15T12:00:00Z","description":"deleted","duration_minutes":0,"deleted_at":"2024-01-16T09:00:00Z"}
+`
+	tmpFile := createTempFile(t, fileContent)
+
+	result, err := ReadEntriesWithWarnings(tmpFile)
+	if err != nil {
+		t.Fatalf("ReadEntriesWithWarnings() returned unexpected error: %v", err)
+	}
+
+	// Suspect entries are kept so that indices stay stable
+	if len(result.Entries) != 4 {
+		t.Errorf("Entries count = %d, expected 4", len(result.Entries))
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("Warnings count = %d, expected 0", len(result.Warnings))
+	}
+	if len(result.Suspect) != 2 || result.Suspect[0].LineNumber != 2 || result.Suspect[1].LineNumber != 3 {
+		t.Fatalf("Suspect = %+v, expected lines 2 and 3", result.Suspect)
+	}
+	if !strings.Contains(result.Suspect[1].Error, "-30 minutes") {
+		t.Errorf("Suspect error = %q, expected the duration", result.Suspect[1].Error)
+	}
+
+	health, err := ValidateStorage(tmpFile)
+	if err != nil {
+		t.Fatalf("ValidateStorage() returned unexpected error: %v", err)
+	}
+	if len(health.Suspect) != 2 {
+		t.Errorf("health.Suspect count = %d, expected 2", len(health.Suspect))
+	}
+}
+
+func TestReadEntriesWithWarnings_EditorLineEndings(t *testing.T) {
+	first := `{"timestamp":"2024-01-15T09:00:00Z","description":"standup","duration_minutes":15,"raw_input":"standup for 15m"}`
+	second := `{"timestamp":"2024-01-15T10:00:00Z","description":"review","duration_minutes":60,"raw_input":"review for 1h"}`
+	tests := []struct {
+		name      string
+		content   string
+		warnings  int
+		malformed string
+	}{
+		{"byte order mark", "\ufeff" + first + "\n" + second + "\n", 0, ""},
+		{"CRLF", first + "\r\n" + second + "\r\n", 0, ""},
+		{"mixed", "\ufeff" + first + "\r\n" + second + "\n{not json\r\n", 1, "{not json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile := createTempFile(t, tt.content)
+
+			result, err := ReadEntriesWithWarnings(tmpFile)
+			if err != nil {
+				t.Fatalf("ReadEntriesWithWarnings() returned unexpected error: %v", err)
+			}
+			if len(result.Entries) != 2 {
+				t.Fatalf("Entries count = %d, expected 2 (warnings: %+v)", len(result.Entries), result.Warnings)
+			}
+			if result.Entries[0].RawInput != "standup for 15m" || result.Entries[1].RawInput != "review for 1h" {
+				t.Errorf("RawInput = %q, %q, expected no stray characters", result.Entries[0].RawInput, result.Entries[1].RawInput)
+			}
+			if len(result.Warnings) != tt.warnings {
+				t.Fatalf("Warnings = %+v, expected %d", result.Warnings, tt.warnings)
+			}
+			if tt.warnings > 0 && (result.Warnings[0].LineNumber != 3 || result.Warnings[0].Content != tt.malformed) {
+				t.Errorf("Warning = %+v, expected line 3 with %q", result.Warnings[0], tt.malformed)
+			}
+
+			health, err := ValidateStorage(tmpFile)
+			if err != nil {
+				t.Fatalf("ValidateStorage() returned unexpected error: %v", err)
+			}
+			if health.ValidEntries != 2 || health.CorruptedEntries != tt.warnings {
+				t.Errorf("Health = %d valid, %d corrupted; expected 2 and %d", health.ValidEntries, health.CorruptedEntries, tt.warnings)
+			}
+
+			// The next rewrite writes plain LF lines
+			if err := UpdateEntry(tmpFile, 0, result.Entries[0]); err != nil {
+				t.Fatalf("UpdateEntry() returned unexpected error: %v", err)
+			}
+			data, _ := os.ReadFile(tmpFile)
+			if strings.Contains(string(data), "\r") || strings.HasPrefix(string(data), "\ufeff") {
+				t.Errorf("Expected the rewrite to normalize line endings, got %q", data)
+			}
+		})
+	}
+}
+
 func TestReadEntriesWithWarnings_PermissionError(t *testing.T) {
 	tmpDir := t.TempDir()
 	storagePath := filepath.Join(tmpDir, "entries.jsonl")
@@ -1952,25 +2329,30 @@ func TestGetStoragePath_UserConfigDirError(t *testing.T) {
 	}
 }
 
-func TestGetStoragePath_MkdirAllError(t *testing.T) {
+func TestGetStoragePath_CreatesNothing(t *testing.T) {
 	// Save original provider
 	defer osutil.ResetProvider()
 
-	tmpDir := t.TempDir()
+	_, dataDir := setUserDirs(t)
+	configDir := t.TempDir()
 
-	// Mock MkdirAll to return an error
+	// Reading commands resolve the path on a read-only file system too
 	osutil.SetProvider(&mockPathProvider{
 		userConfigDirFn: func() (string, error) {
-			return tmpDir, nil
+			return configDir, nil
 		},
 		mkdirAllFn: func(path string, perm os.FileMode) error {
+			t.Errorf("GetStoragePath() should not create %s", path)
 			return os.ErrPermission
 		},
 	})
 
-	_, err := GetStoragePath()
-	if err == nil {
-		t.Error("GetStoragePath() should return error when MkdirAll fails")
+	path, err := GetStoragePath()
+	if err != nil {
+		t.Fatalf("GetStoragePath() returned unexpected error: %v", err)
+	}
+	if expected := filepath.Join(dataDir, app.Name, EntriesFile); path != expected {
+		t.Errorf("GetStoragePath() = %q, expected %q", path, expected)
 	}
 }
 
@@ -2130,12 +2512,11 @@ func TestValidateStorage_ScannerError(t *testing.T) {
 }
 
 func TestReadActiveEntries_ReadError(t *testing.T) {
-	// Test with a directory path (not a file)
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
-	_, err := ReadActiveEntries(tmpDir)
+	_, err := ReadActiveEntries(storagePath)
 	if err == nil {
-		t.Error("ReadActiveEntries() should return error when reading a directory")
+		t.Error("ReadActiveEntries() should return error when the storage file cannot be read")
 	}
 }
 
@@ -2170,22 +2551,20 @@ func TestRestoreEntry_ReadError(t *testing.T) {
 }
 
 func TestPurgeDeletedEntries_ReadError(t *testing.T) {
-	// Test with a directory path (not a file)
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
-	_, err := PurgeDeletedEntries(tmpDir)
+	_, err := PurgeDeletedEntries(storagePath)
 	if err == nil {
-		t.Error("PurgeDeletedEntries() should return error when reading a directory")
+		t.Error("PurgeDeletedEntries() should return error when the storage file cannot be read")
 	}
 }
 
 func TestCleanupOldDeleted_ReadError(t *testing.T) {
-	// Test with a directory path (not a file)
-	tmpDir := t.TempDir()
+	storagePath := unreadableStoragePath(t)
 
-	_, err := CleanupOldDeleted(tmpDir)
+	_, err := CleanupOldDeleted(storagePath)
 	if err == nil {
-		t.Error("CleanupOldDeleted() should return error when reading a directory")
+		t.Error("CleanupOldDeleted() should return error when the storage file cannot be read")
 	}
 }
 
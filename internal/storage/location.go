@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/xolan/did/internal/app"
+	"github.com/xolan/did/internal/osutil"
+)
+
+// DataStoragePath returns the default location of the entries storage file:
+// entries.jsonl in the did directory of the user data directory
+// (osutil.UserDataDir), e.g. ~/.local/share/did/entries.jsonl on Linux.
+func DataStoragePath() (string, error) {
+	dataDir, err := osutil.UserDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, app.Name, EntriesFile), nil
+}
+
+// LegacyStoragePath returns where earlier versions of did kept the storage
+// file: entries.jsonl in the did directory of the user config directory. On
+// macOS and Windows this is the same as DataStoragePath.
+func LegacyStoragePath() (string, error) {
+	configDir, err := osutil.Provider.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, app.Name, EntriesFile), nil
+}
+
+// UsesLegacyStorage reports whether the default storage file is still at the
+// legacy location (see MoveLegacyStorage)
+func UsesLegacyStorage() bool {
+	path, err := GetStoragePath()
+	if err != nil {
+		return false
+	}
+	dataPath, err := DataStoragePath()
+	return err == nil && path != dataPath
+}
+
+// LegacyMove describes moving the storage file from the legacy location to
+// the data directory
+type LegacyMove struct {
+	From  string   // Legacy storage file
+	To    string   // Storage file in the data directory
+	Files []string // Names of the moved files: the storage file, its backups and metadata
+}
+
+// MoveLegacyStorage moves the storage file at the legacy location, with its
+// backups and metadata, to the data directory while holding the storage lock.
+// The files are copied when the directories are on different file systems;
+// if moving one fails, those already moved are moved back.
+// Returns a zero LegacyMove when there is nothing to move; with dryRun set
+// nothing is moved. Refuses to overwrite a storage file in the data directory.
+func MoveLegacyStorage(dryRun bool) (LegacyMove, error) {
+	from, err := LegacyStoragePath()
+	if err != nil {
+		return LegacyMove{}, err
+	}
+	to, err := DataStoragePath()
+	if err != nil {
+		return LegacyMove{}, err
+	}
+	if from == to || !isRegularFile(from) {
+		return LegacyMove{}, nil
+	}
+	if isRegularFile(to) {
+		return LegacyMove{}, fmt.Errorf("both %s and %s exist; merge them manually", from, to)
+	}
+
+	release, err := lockStorage(from)
+	if err != nil {
+		return LegacyMove{}, err
+	}
+	defer release()
+
+	names := []string{EntriesFile}
+	for n := 1; n <= MaxBackupCount; n++ {
+		names = append(names, fmt.Sprintf("%s%s.%d", EntriesFile, BackupSuffix, n))
+	}
+	names = append(names, MetaFile)
+
+	move := LegacyMove{From: from, To: to}
+	fromDir, toDir := filepath.Dir(from), filepath.Dir(to)
+	for _, name := range names {
+		if !isRegularFile(filepath.Join(fromDir, name)) {
+			continue
+		}
+		move.Files = append(move.Files, name)
+	}
+	if dryRun {
+		return move, nil
+	}
+
+	if err := osutil.Provider.MkdirAll(toDir, 0755); err != nil {
+		return LegacyMove{}, err
+	}
+	for i, name := range move.Files {
+		if err := moveFile(filepath.Join(fromDir, name), filepath.Join(toDir, name)); err != nil {
+			err = fmt.Errorf("failed to move %s: %w", name, err)
+			// Put back the files moved so far, so the legacy storage stays complete
+			for j := i - 1; j >= 0; j-- {
+				moved := move.Files[j]
+				if undoErr := moveFile(filepath.Join(toDir, moved), filepath.Join(fromDir, moved)); undoErr != nil {
+					err = fmt.Errorf("%w; failed to move %s back: %v", err, moved, undoErr)
+				}
+			}
+			return LegacyMove{}, err
+		}
+	}
+	return move, nil
+}
+
+// renameFile renames a file; tests replace it to simulate a move across file systems
+var renameFile = os.Rename
+
+// moveFile moves the file at from to to. Where a rename is impossible because
+// the paths are on different file systems, the file is copied, flushed to
+// disk and then removed.
+func moveFile(from, to string) error {
+	err := renameFile(from, to)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := copyFileSynced(from, to); err != nil {
+		return err
+	}
+	return os.Remove(from)
+}
+
+// copyFileSynced copies the file at from to to, keeping its permissions and
+// modification time, and flushes the copy to disk. A failed copy is removed.
+func copyFileSynced(from, to string) (err error) {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(to, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(to)
+		}
+	}()
+	if _, err = io.Copy(dst, src); err == nil {
+		err = dst.Sync()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(to, info.ModTime(), info.ModTime())
+}
+
+// isRegularFile reports whether a regular file exists at path
+func isRegularFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
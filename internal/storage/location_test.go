@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/xolan/did/internal/app"
+)
+
+// setUserDirs points the XDG config and data directories and $HOME at new
+// temporary directories and returns the config and data directories. macOS
+// and Windows keep data in the config directory, so tests are skipped there.
+func setUserDirs(t *testing.T) (configDir, dataDir string) {
+	t.Helper()
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("the data directory is the config directory on " + runtime.GOOS)
+	}
+	configDir, dataDir = t.TempDir(), t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("XDG_DATA_HOME", dataDir)
+	t.Setenv("HOME", t.TempDir())
+	return configDir, dataDir
+}
+
+// writeStorageFile writes content to name in the did directory of dir and
+// returns the path
+func writeStorageFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, app.Name, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestDataStoragePath(t *testing.T) {
+	_, dataDir := setUserDirs(t)
+	path, err := DataStoragePath()
+	if err != nil {
+		t.Fatalf("DataStoragePath() returned unexpected error: %v", err)
+	}
+	if expected := filepath.Join(dataDir, app.Name, EntriesFile); path != expected {
+		t.Errorf("DataStoragePath() = %q, expected %q", path, expected)
+	}
+
+	// A relative $XDG_DATA_HOME is ignored, as the specification requires
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, value := range []string{"", "relative/data"} {
+		t.Setenv("XDG_DATA_HOME", value)
+		path, err := DataStoragePath()
+		if err != nil {
+			t.Fatalf("DataStoragePath() returned unexpected error: %v", err)
+		}
+		if expected := filepath.Join(home, ".local", "share", app.Name, EntriesFile); path != expected {
+			t.Errorf("XDG_DATA_HOME=%q: DataStoragePath() = %q, expected %q", value, path, expected)
+		}
+	}
+
+	t.Setenv("HOME", "")
+	if _, err := DataStoragePath(); err == nil {
+		t.Error("DataStoragePath() should return error without $XDG_DATA_HOME and $HOME")
+	}
+}
+
+func TestGetStoragePath_Legacy(t *testing.T) {
+	configDir, dataDir := setUserDirs(t)
+	legacyPath := writeStorageFile(t, configDir, EntriesFile, "")
+
+	path, err := GetStoragePath()
+	if err != nil {
+		t.Fatalf("GetStoragePath() returned unexpected error: %v", err)
+	}
+	if path != legacyPath {
+		t.Errorf("GetStoragePath() = %q, expected the legacy file %q", path, legacyPath)
+	}
+	if !UsesLegacyStorage() {
+		t.Error("UsesLegacyStorage() = false, expected true")
+	}
+
+	// Once the data directory has a storage file, it wins
+	dataPath := writeStorageFile(t, dataDir, EntriesFile, "")
+	path, err = GetStoragePath()
+	if err != nil {
+		t.Fatalf("GetStoragePath() returned unexpected error: %v", err)
+	}
+	if path != dataPath {
+		t.Errorf("GetStoragePath() = %q, expected %q", path, dataPath)
+	}
+	if UsesLegacyStorage() {
+		t.Error("UsesLegacyStorage() = true, expected false")
+	}
+}
+
+func TestMoveLegacyStorage(t *testing.T) {
+	configDir, dataDir := setUserDirs(t)
+	line := `{"timestamp":"2024-01-15T09:00:00Z","description":"moved","duration_minutes":15,"raw_input":""}` + "\n"
+	legacyPath := writeStorageFile(t, configDir, EntriesFile, line)
+	writeStorageFile(t, configDir, EntriesFile+BackupSuffix+".1", line)
+	writeStorageFile(t, configDir, MetaFile, `{"writer_version":"v1.0.0"}`)
+	configFile := writeStorageFile(t, configDir, "config.toml", "")
+	dataPath := filepath.Join(dataDir, app.Name, EntriesFile)
+
+	dry, err := MoveLegacyStorage(true)
+	if err != nil {
+		t.Fatalf("MoveLegacyStorage() returned unexpected error: %v", err)
+	}
+	expectedFiles := []string{EntriesFile, EntriesFile + BackupSuffix + ".1", MetaFile}
+	if dry.From != legacyPath || dry.To != dataPath || strings.Join(dry.Files, ",") != strings.Join(expectedFiles, ",") {
+		t.Errorf("Dry run = %+v, expected %v to move from %s to %s", dry, expectedFiles, legacyPath, dataPath)
+	}
+	if !isRegularFile(legacyPath) || isRegularFile(dataPath) {
+		t.Fatal("Dry run should not move anything")
+	}
+
+	move, err := MoveLegacyStorage(false)
+	if err != nil {
+		t.Fatalf("MoveLegacyStorage() returned unexpected error: %v", err)
+	}
+	if len(move.Files) != len(expectedFiles) {
+		t.Errorf("Moved %v, expected %v", move.Files, expectedFiles)
+	}
+	for _, name := range expectedFiles {
+		if isRegularFile(filepath.Join(configDir, app.Name, name)) {
+			t.Errorf("%s is still in the config directory", name)
+		}
+		if !isRegularFile(filepath.Join(dataDir, app.Name, name)) {
+			t.Errorf("%s is missing from the data directory", name)
+		}
+	}
+	if !isRegularFile(configFile) {
+		t.Error("The config file should stay in the config directory")
+	}
+
+	entries, err := ReadEntries(dataPath)
+	if err != nil || len(entries) != 1 || entries[0].Description != "moved" {
+		t.Errorf("ReadEntries() = %v, %v, expected the moved entry", entries, err)
+	}
+	if path, _ := GetStoragePath(); path != dataPath {
+		t.Errorf("GetStoragePath() = %q after moving, expected %q", path, dataPath)
+	}
+
+	again, err := MoveLegacyStorage(false)
+	if err != nil || again.From != "" {
+		t.Errorf("Second move = %+v, %v, expected nothing to move", again, err)
+	}
+}
+
+func TestMoveLegacyStorage_AcrossFileSystems(t *testing.T) {
+	configDir, dataDir := setUserDirs(t)
+	line := `{"timestamp":"2024-01-15T09:00:00Z","description":"copied","duration_minutes":15,"raw_input":""}` + "\n"
+	writeStorageFile(t, configDir, EntriesFile, line)
+	writeStorageFile(t, configDir, MetaFile, `{"writer_version":"v1.0.0"}`)
+
+	renameFile = func(from, to string) error {
+		return &os.LinkError{Op: "rename", Old: from, New: to, Err: syscall.EXDEV}
+	}
+	defer func() { renameFile = os.Rename }()
+
+	if _, err := MoveLegacyStorage(false); err != nil {
+		t.Fatalf("MoveLegacyStorage() returned unexpected error: %v", err)
+	}
+	for _, name := range []string{EntriesFile, MetaFile} {
+		if isRegularFile(filepath.Join(configDir, app.Name, name)) {
+			t.Errorf("%s is still in the config directory", name)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(dataDir, app.Name, EntriesFile))
+	if err != nil || string(data) != line {
+		t.Errorf("Copied storage file = %q, %v, expected %q", data, err, line)
+	}
+}
+
+func TestMoveLegacyStorage_RollsBackOnError(t *testing.T) {
+	configDir, dataDir := setUserDirs(t)
+	writeStorageFile(t, configDir, EntriesFile, "entries\n")
+	writeStorageFile(t, configDir, EntriesFile+BackupSuffix+".1", "backup\n")
+	writeStorageFile(t, configDir, MetaFile, `{}`)
+
+	renameFile = func(from, to string) error {
+		if filepath.Base(from) == MetaFile {
+			return &os.LinkError{Op: "rename", Old: from, New: to, Err: syscall.EACCES}
+		}
+		return os.Rename(from, to)
+	}
+	defer func() { renameFile = os.Rename }()
+
+	if _, err := MoveLegacyStorage(false); err == nil || !strings.Contains(err.Error(), "failed to move "+MetaFile) {
+		t.Fatalf("MoveLegacyStorage() error = %v, expected the failed move", err)
+	}
+	for _, name := range []string{EntriesFile, EntriesFile + BackupSuffix + ".1", MetaFile} {
+		if !isRegularFile(filepath.Join(configDir, app.Name, name)) {
+			t.Errorf("%s should have been moved back to the config directory", name)
+		}
+		if isRegularFile(filepath.Join(dataDir, app.Name, name)) {
+			t.Errorf("%s should not be left in the data directory", name)
+		}
+	}
+}
+
+func TestMoveLegacyStorage_BothExist(t *testing.T) {
+	configDir, dataDir := setUserDirs(t)
+	legacyPath := writeStorageFile(t, configDir, EntriesFile, "legacy\n")
+	dataPath := writeStorageFile(t, dataDir, EntriesFile, "data\n")
+
+	if _, err := MoveLegacyStorage(false); err == nil {
+		t.Fatal("MoveLegacyStorage() should refuse to overwrite the storage file in the data directory")
+	}
+	for path, expected := range map[string]string{legacyPath: "legacy\n", dataPath: "data\n"} {
+		if data, _ := os.ReadFile(path); string(data) != expected {
+			t.Errorf("%s = %q, expected it unchanged", path, data)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/xolan/did/internal/osutil"
+)
+
+const (
+	// LockFileSuffix is appended to the storage file name to get its lock file
+	LockFileSuffix = ".lock"
+
+	// DirectoryLockFile is the lock file inside a storage directory
+	DirectoryLockFile = ".did.lock"
+)
+
+// LockTimeout is how long a write waits for another did process to release
+// the storage lock before giving up
+var LockTimeout = 5 * time.Second
+
+// GetLockPath returns the path of the lock file guarding the storage path.
+// The lock file is kept next to the storage file (or inside a storage
+// directory) and is never removed, so every process locks the same file.
+func GetLockPath(storagePath string) string {
+	if IsDirectory(storagePath) {
+		return filepath.Join(storagePath, DirectoryLockFile)
+	}
+	return storagePath + LockFileSuffix
+}
+
+// afterWrite is called by the storage writes, see SetAfterWrite
+var afterWrite func(storagePath string)
+
+// SetAfterWrite sets a function called with the storage path after each write
+// that changed the storage, e.g. to record the writing version in the metadata
+// file (see Meta). It runs while the storage lock is still held. Pass nil to
+// remove it.
+func SetAfterWrite(fn func(storagePath string)) {
+	afterWrite = fn
+}
+
+// lockStorage takes the storage lock, so appends and rewrites from concurrent
+// did processes cannot interleave. The returned function releases it, calling
+// afterWrite first when the storage changed while the lock was held.
+func lockStorage(storagePath string) (func(), error) {
+	lockPath := GetLockPath(storagePath)
+	release, err := osutil.LockFile(lockPath, LockTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock storage (%s): %w", lockPath, err)
+	}
+	before, _ := StorageRevision(storagePath)
+	return func() {
+		// Storage that no longer exists, e.g. after a move, has no writer to record
+		if after, err := StorageRevision(storagePath); afterWrite != nil && err == nil && after != before && after != (Revision{}) {
+			afterWrite(storagePath)
+		}
+		// The lock is released when the file is closed even if unlocking fails
+		_ = release()
+	}, nil
+}
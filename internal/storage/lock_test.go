@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+	"github.com/xolan/did/internal/osutil"
+)
+
+func TestGetLockPath(t *testing.T) {
+	dir := t.TempDir()
+	storagePath := filepath.Join(dir, "entries.jsonl")
+
+	if got := GetLockPath(storagePath); got != storagePath+".lock" {
+		t.Errorf("GetLockPath(file) = %q, want %q", got, storagePath+".lock")
+	}
+	if got := GetLockPath(dir); got != filepath.Join(dir, DirectoryLockFile) {
+		t.Errorf("GetLockPath(dir) = %q, want %q", got, filepath.Join(dir, DirectoryLockFile))
+	}
+}
+
+func TestAppendEntry_Concurrent(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+
+	const writers, perWriter = 8, 25
+	base := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*perWriter)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				e := entry.Entry{
+					Timestamp:       base.Add(time.Duration(w*perWriter+i) * time.Minute),
+					Description:     fmt.Sprintf("writer %d entry %d", w, i),
+					DurationMinutes: 1 + i,
+				}
+				if err := AppendEntry(storagePath, e); err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+	}
+
+	// Rewrites run alongside the appends and must not lose any of them
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := PurgeDeletedEntries(storagePath); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Concurrent write failed: %v", err)
+	}
+
+	result, err := ReadEntriesWithWarnings(storagePath)
+	if err != nil {
+		t.Fatalf("ReadEntriesWithWarnings returned error: %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("Expected no corrupted lines, got %d: %+v", len(result.Warnings), result.Warnings[0])
+	}
+	if len(result.Entries) != writers*perWriter {
+		t.Fatalf("Expected %d entries, got %d", writers*perWriter, len(result.Entries))
+	}
+
+	seen := make(map[string]bool)
+	for _, e := range result.Entries {
+		seen[e.Description] = true
+	}
+	for w := 0; w < writers; w++ {
+		for i := 0; i < perWriter; i++ {
+			if desc := fmt.Sprintf("writer %d entry %d", w, i); !seen[desc] {
+				t.Errorf("Missing entry %q", desc)
+			}
+		}
+	}
+}
+
+func TestAppendEntry_LockTimeout(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+
+	release, err := osutil.LockFile(GetLockPath(storagePath), time.Second)
+	if err != nil {
+		t.Fatalf("Failed to take lock: %v", err)
+	}
+	defer func() { _ = release() }()
+
+	original := LockTimeout
+	LockTimeout = 20 * time.Millisecond
+	defer func() { LockTimeout = original }()
+
+	e := entry.Entry{Timestamp: time.Now(), Description: "blocked", DurationMinutes: 5}
+	if err := AppendEntry(storagePath, e); !errors.Is(err, osutil.ErrLockTimeout) {
+		t.Errorf("Expected ErrLockTimeout from AppendEntry, got %v", err)
+	}
+	if _, err := AppendEntries(storagePath, []entry.Entry{e, e}); !errors.Is(err, osutil.ErrLockTimeout) {
+		t.Errorf("Expected ErrLockTimeout from AppendEntries, got %v", err)
+	}
+	if err := UpdateEntry(storagePath, 0, e); !errors.Is(err, osutil.ErrLockTimeout) {
+		t.Errorf("Expected ErrLockTimeout from UpdateEntry, got %v", err)
+	}
+	if err := RestoreBackupForStorage(storagePath, 1); !errors.Is(err, osutil.ErrLockTimeout) {
+		t.Errorf("Expected ErrLockTimeout from RestoreBackupForStorage, got %v", err)
+	}
+}
+
+func TestSetAfterWrite(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "entries.jsonl")
+	var calls []string
+	SetAfterWrite(func(path string) { calls = append(calls, path) })
+	defer SetAfterWrite(nil)
+
+	e := entry.Entry{Timestamp: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), Description: "work", DurationMinutes: 60}
+	if err := AppendEntry(storagePath, e); err != nil {
+		t.Fatalf("AppendEntry() returned error: %v", err)
+	}
+	if len(calls) != 1 || calls[0] != storagePath {
+		t.Fatalf("Expected one call for the append, got %v", calls)
+	}
+
+	// A dry run and a rewrite changing nothing are not writes
+	if _, err := RenameProject(storagePath, "acme", "globex", true); err != nil {
+		t.Fatalf("RenameProject() returned error: %v", err)
+	}
+	if _, err := SortEntries(storagePath, false); err != nil {
+		t.Fatalf("SortEntries() returned error: %v", err)
+	}
+	if len(calls) != 1 {
+		t.Errorf("Expected no calls without a change, got %v", calls)
+	}
+
+	e.Description = "review"
+	if err := UpdateEntry(storagePath, 0, e); err != nil {
+		t.Fatalf("UpdateEntry() returned error: %v", err)
+	}
+	if _, err := SoftDeleteEntry(storagePath, 0); err != nil {
+		t.Fatalf("SoftDeleteEntry() returned error: %v", err)
+	}
+	if len(calls) != 3 {
+		t.Errorf("Expected a call for the update and the delete, got %v", calls)
+	}
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// MetaFile is the name of the storage metadata file kept next to the entries file
+	MetaFile = "meta.json"
+)
+
+// Meta holds metadata about the storage file that is not part of any entry.
+type Meta struct {
+	// WriterVersion is the version of the last tagged did release that wrote to the storage file
+	WriterVersion string `json:"writer_version,omitempty"`
+}
+
+// GetMetaPath returns the path to the metadata file for the given storage file.
+// The metadata file lives in the same directory as the storage file, or inside
+// the storage path when it is a directory.
+func GetMetaPath(storagePath string) string {
+	if IsDirectory(storagePath) {
+		return filepath.Join(storagePath, MetaFile)
+	}
+	return filepath.Join(filepath.Dir(storagePath), MetaFile)
+}
+
+// ReadMeta reads the metadata for the given storage file.
+// Returns an empty Meta if the metadata file doesn't exist (graceful handling).
+func ReadMeta(storagePath string) (Meta, error) {
+	var meta Meta
+
+	data, err := os.ReadFile(GetMetaPath(storagePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return meta, nil
+		}
+		return meta, err
+	}
+
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return Meta{}, err
+	}
+
+	return meta, nil
+}
+
+// WriteMeta writes the metadata for the given storage file.
+// Uses atomic write pattern (write to temp file, then rename) for safety.
+func WriteMeta(storagePath string, meta Meta) error {
+	// Meta struct contains only JSON-safe types, so Marshal cannot fail
+	data, _ := json.MarshalIndent(meta, "", "  ")
+
+	metaPath := GetMetaPath(storagePath)
+	tmpFile := metaPath + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpFile, metaPath); err != nil {
+		_ = os.Remove(tmpFile)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetMetaPath(t *testing.T) {
+	storagePath := filepath.Join("some", "dir", EntriesFile)
+	expected := filepath.Join("some", "dir", MetaFile)
+
+	if got := GetMetaPath(storagePath); got != expected {
+		t.Errorf("GetMetaPath() = %q, expected %q", got, expected)
+	}
+}
+
+func TestReadMeta_MissingFile(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), EntriesFile)
+
+	meta, err := ReadMeta(storagePath)
+	if err != nil {
+		t.Fatalf("ReadMeta() returned error: %v", err)
+	}
+	if meta.WriterVersion != "" {
+		t.Errorf("Expected empty WriterVersion, got %q", meta.WriterVersion)
+	}
+}
+
+func TestWriteMeta_RoundTrip(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), EntriesFile)
+
+	if err := WriteMeta(storagePath, Meta{WriterVersion: "v1.2.3"}); err != nil {
+		t.Fatalf("WriteMeta() returned error: %v", err)
+	}
+
+	meta, err := ReadMeta(storagePath)
+	if err != nil {
+		t.Fatalf("ReadMeta() returned error: %v", err)
+	}
+	if meta.WriterVersion != "v1.2.3" {
+		t.Errorf("Expected WriterVersion 'v1.2.3', got %q", meta.WriterVersion)
+	}
+
+	if _, err := os.Stat(GetMetaPath(storagePath) + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Temp file should not remain after WriteMeta()")
+	}
+}
+
+func TestReadMeta_CorruptedFile(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), EntriesFile)
+	if err := os.WriteFile(GetMetaPath(storagePath), []byte("not json"), 0644); err != nil {
+		t.Fatalf("Failed to write meta file: %v", err)
+	}
+
+	if _, err := ReadMeta(storagePath); err == nil {
+		t.Error("ReadMeta() should return error for corrupted meta file")
+	}
+}
+
+func TestWriteMeta_MissingDirectory(t *testing.T) {
+	storagePath := filepath.Join(t.TempDir(), "missing", EntriesFile)
+
+	if err := WriteMeta(storagePath, Meta{WriterVersion: "v1.0.0"}); err == nil {
+		t.Error("WriteMeta() should return error when directory does not exist")
+	}
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// NormalizeResult describes the result of rewriting the timestamps of the
+// storage file in UTC or truncating them
+type NormalizeResult struct {
+	Entries   int // Number of valid entries
+	Changed   int // Number of entries whose timestamps were rewritten
+	Corrupted int // Number of corrupted lines, kept unchanged
+	Cleaned   int // Number of lines with a CRLF line ending or byte order mark, written back without
+	Written   bool
+}
+
+// NormalizeTimestamps rewrites the entries of the storage file whose
+// timestamps were written with a zone offset (by older versions of did) in
+// UTC, the form entries are now written in. Only the representation changes:
+// every timestamp keeps its instant. Other lines, including corrupted ones,
+// are written back in place, only without the CRLF line endings and byte
+// order mark an editor may have added (see cleanLine), which are removed from
+// every line. When the storage path is a
+// directory, each file is normalized on its own. The rewrite is atomic and
+// holds the storage lock; with dryRun set nothing is written and the result
+// only reports what would change.
+func NormalizeTimestamps(storagePath string, dryRun bool) (NormalizeResult, error) {
+	return rewriteTimestamps(storagePath, dryRun, func(e *entry.Entry) bool {
+		return !storedInUTC(*e)
+	})
+}
+
+// TruncateTimestamps truncates the timestamp of every entry of the storage
+// file, including soft-deleted entries, to a multiple of precision (e.g.
+// time.Minute), as new entries are with timestamp_precision configured. The
+// lines of changed entries are written in UTC; other lines are kept like
+// NormalizeTimestamps keeps them. The deleted_at and logged_at times are not
+// changed. The rewrite is atomic and holds the storage lock; with dryRun set
+// nothing is written.
+func TruncateTimestamps(storagePath string, precision time.Duration, dryRun bool) (NormalizeResult, error) {
+	return rewriteTimestamps(storagePath, dryRun, func(e *entry.Entry) bool {
+		truncated := e.Timestamp.Truncate(precision)
+		if truncated.Equal(e.Timestamp) {
+			return false
+		}
+		e.Timestamp = truncated
+		return true
+	})
+}
+
+// rewriteTimestamps applies change to every entry of the storage file, or of
+// each file of a storage directory, and writes the lines of the entries it
+// reports changed back in canonical form (see NormalizeTimestamps)
+func rewriteTimestamps(storagePath string, dryRun bool, change func(e *entry.Entry) bool) (NormalizeResult, error) {
+	release, err := lockStorage(storagePath)
+	if err != nil {
+		return NormalizeResult{}, err
+	}
+	defer release()
+
+	if !IsDirectory(storagePath) {
+		return normalizeFile(storagePath, dryRun, change)
+	}
+
+	names, err := directoryFiles(storagePath)
+	if err != nil {
+		return NormalizeResult{}, err
+	}
+	var total NormalizeResult
+	for _, name := range names {
+		result, err := normalizeFile(filepath.Join(storagePath, name), dryRun, change)
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", name, err)
+		}
+		total.Entries += result.Entries
+		total.Changed += result.Changed
+		total.Corrupted += result.Corrupted
+		total.Cleaned += result.Cleaned
+		total.Written = total.Written || result.Written
+	}
+	return total, nil
+}
+
+// normalizeFile rewrites the timestamps of a single storage file with change,
+// see rewriteTimestamps
+func normalizeFile(path string, dryRun bool, change func(e *entry.Entry) bool) (NormalizeResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NormalizeResult{}, nil
+		}
+		return NormalizeResult{}, err
+	}
+
+	var result NormalizeResult
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Split(scanRawLines)
+	for scanner.Scan() {
+		line := cleanLine(scanner.Text(), len(lines)+1)
+		if line != scanner.Text() {
+			result.Cleaned++
+		}
+		var e entry.Entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			result.Corrupted++
+			lines = append(lines, line)
+			continue
+		}
+		result.Entries++
+		if change(&e) {
+			result.Changed++
+			line = canonicalLine(e)
+		}
+		lines = append(lines, line)
+	}
+	_ = file.Close()
+	if err := scanner.Err(); err != nil {
+		return NormalizeResult{}, err
+	}
+
+	if dryRun || (result.Changed == 0 && result.Cleaned == 0) {
+		return result, nil
+	}
+
+	if err := replaceFileLines(path, lines); err != nil {
+		return result, err
+	}
+	result.Written = true
+	return result, nil
+}
+
+// storedInUTC reports whether all timestamps of an entry as read from its
+// line are in UTC
+func storedInUTC(e entry.Entry) bool {
+	if e.Timestamp.Location() != time.UTC {
+		return false
+	}
+	if e.DeletedAt != nil && e.DeletedAt.Location() != time.UTC {
+		return false
+	}
+	return e.LoggedAt == nil || e.LoggedAt.Location() == time.UTC
+}
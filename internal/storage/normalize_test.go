@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// dstEntries returns entries logged in New York around the 2024 DST
+// transitions, including both 01:30s of the repeated hour in November
+func dstEntries(t *testing.T) []entry.Entry {
+	t.Helper()
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("America/New_York not available: %v", err)
+	}
+	firstHalfPast := time.Date(2024, 11, 3, 1, 30, 0, 0, ny) // EDT, -04:00
+	deletedAt := time.Date(2024, 3, 10, 3, 30, 0, 0, ny)
+	return []entry.Entry{
+		{Timestamp: time.Date(2024, 3, 10, 1, 30, 0, 0, ny), Description: "before spring forward", DurationMinutes: 60},
+		{Timestamp: time.Date(2024, 3, 10, 3, 0, 0, 0, ny), Description: "after spring forward", DurationMinutes: 30, DeletedAt: &deletedAt},
+		{Timestamp: firstHalfPast, Description: "first 01:30", DurationMinutes: 15},
+		{Timestamp: firstHalfPast.Add(time.Hour), Description: "second 01:30", DurationMinutes: 15}, // EST, -05:00
+	}
+}
+
+func TestAppendEntry_StoresUTC(t *testing.T) {
+	defer SetDisplayLocation(nil)
+	entries := dstEntries(t)
+	path := createTempFile(t, "")
+	for _, e := range entries {
+		if err := AppendEntry(path, e); err != nil {
+			t.Fatalf("AppendEntry() returned unexpected error: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read storage file: %v", err)
+	}
+	for _, offset := range []string{"-04:00", "-05:00"} {
+		if strings.Contains(string(data), offset) {
+			t.Errorf("Expected timestamps in UTC, found offset %s in:\n%s", offset, data)
+		}
+	}
+
+	// Read back in another zone, and after an edit rewrite, no instant shifts
+	SetDisplayLocation(time.FixedZone("UTC+9", 9*3600))
+	if err := UpdateEntry(path, 0, entries[0]); err != nil {
+		t.Fatalf("UpdateEntry() returned unexpected error: %v", err)
+	}
+	read, err := ReadEntries(path)
+	if err != nil {
+		t.Fatalf("ReadEntries() returned unexpected error: %v", err)
+	}
+	if len(read) != len(entries) {
+		t.Fatalf("Expected %d entries, got %d", len(entries), len(read))
+	}
+	for i, e := range read {
+		if !e.Timestamp.Equal(entries[i].Timestamp) {
+			t.Errorf("Entry %d: timestamp %v, expected the instant %v", i, e.Timestamp, entries[i].Timestamp)
+		}
+		if e.Timestamp.Location() != displayLocation {
+			t.Errorf("Entry %d: timestamp in %v, expected the display location", i, e.Timestamp.Location())
+		}
+	}
+	if !read[1].DeletedAt.Equal(*entries[1].DeletedAt) {
+		t.Errorf("DeletedAt = %v, expected the instant %v", read[1].DeletedAt, entries[1].DeletedAt)
+	}
+	if read[2].Timestamp.Equal(read[3].Timestamp) {
+		t.Error("Expected the two 01:30s of the repeated hour to stay an hour apart")
+	}
+}
+
+func TestNormalizeTimestamps(t *testing.T) {
+	entries := dstEntries(t)
+	var lines []string
+	for _, e := range entries {
+		// Written as older versions of did did, with the zone offset
+		line := strings.Replace(canonicalLine(e), e.Timestamp.UTC().Format(time.RFC3339Nano), e.Timestamp.Format(time.RFC3339Nano), 1)
+		lines = append(lines, line)
+	}
+	utcLine := `{"timestamp":"2024-01-15T09:00:00Z","description":"already utc","duration_minutes":15,"raw_input":""}`
+	lines = append(lines, "not json", utcLine)
+	path := createTempFile(t, strings.Join(lines, "\n")+"\n")
+
+	dry, err := NormalizeTimestamps(path, true)
+	if err != nil {
+		t.Fatalf("NormalizeTimestamps() returned unexpected error: %v", err)
+	}
+	if dry.Entries != 5 || dry.Changed != 4 || dry.Corrupted != 1 || dry.Written {
+		t.Errorf("Dry run result = %+v, expected 4 of 5 entries to change and nothing written", dry)
+	}
+
+	before, _ := ReadEntries(path)
+	result, err := NormalizeTimestamps(path, false)
+	if err != nil {
+		t.Fatalf("NormalizeTimestamps() returned unexpected error: %v", err)
+	}
+	if result.Changed != 4 || !result.Written {
+		t.Errorf("Result = %+v, expected 4 entries rewritten", result)
+	}
+
+	data, _ := os.ReadFile(path)
+	written := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(written) != 6 || written[4] != "not json" || written[5] != utcLine {
+		t.Errorf("Expected the corrupted and UTC lines unchanged and in place, got:\n%s", data)
+	}
+	for i, line := range written[:4] {
+		if strings.Contains(line, "-04:00") || strings.Contains(line, "-05:00") {
+			t.Errorf("Line %d still has a zone offset: %s", i+1, line)
+		}
+	}
+	after, _ := ReadEntries(path)
+	for i := range before {
+		if !after[i].Timestamp.Equal(before[i].Timestamp) {
+			t.Errorf("Entry %d: timestamp %v after normalizing, expected the instant %v", i, after[i].Timestamp, before[i].Timestamp)
+		}
+	}
+
+	again, err := NormalizeTimestamps(path, false)
+	if err != nil || again.Changed != 0 || again.Written {
+		t.Errorf("Second run = %+v, %v, expected nothing to change", again, err)
+	}
+}
+
+func TestNormalizeTimestamps_EditorLineEndings(t *testing.T) {
+	utcLine := `{"timestamp":"2024-01-15T09:00:00Z","description":"already utc","duration_minutes":15,"raw_input":""}`
+	path := createTempFile(t, "\ufeff"+utcLine+"\r\nnot json\r\n"+utcLine+"\n")
+
+	dry, err := NormalizeTimestamps(path, true)
+	if err != nil {
+		t.Fatalf("NormalizeTimestamps() returned unexpected error: %v", err)
+	}
+	if dry.Changed != 0 || dry.Cleaned != 2 || dry.Written {
+		t.Errorf("Dry run result = %+v, expected 2 lines to clean and nothing written", dry)
+	}
+
+	result, err := NormalizeTimestamps(path, false)
+	if err != nil {
+		t.Fatalf("NormalizeTimestamps() returned unexpected error: %v", err)
+	}
+	if !result.Written {
+		t.Errorf("Result = %+v, expected the file to be rewritten", result)
+	}
+	data, _ := os.ReadFile(path)
+	if expected := utcLine + "\nnot json\n" + utcLine + "\n"; string(data) != expected {
+		t.Errorf("File = %q, expected %q", data, expected)
+	}
+}
+
+func TestTruncateTimestamps(t *testing.T) {
+	deletedAt := time.Date(2024, 1, 16, 8, 0, 0, 999, time.UTC)
+	exact := entry.Entry{Timestamp: time.Date(2024, 1, 15, 9, 41, 27, 123456789, time.UTC), Description: "exact", DurationMinutes: 30, DeletedAt: &deletedAt}
+	whole := entry.Entry{Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), Description: "whole", DurationMinutes: 15}
+	path := createTempFile(t, canonicalLine(exact)+"\nnot json\n"+canonicalLine(whole)+"\n")
+
+	dry, err := TruncateTimestamps(path, time.Minute, true)
+	if err != nil {
+		t.Fatalf("TruncateTimestamps() returned unexpected error: %v", err)
+	}
+	if dry.Entries != 2 || dry.Changed != 1 || dry.Corrupted != 1 || dry.Written {
+		t.Errorf("Dry run result = %+v, expected 1 of 2 entries to change and nothing written", dry)
+	}
+
+	result, err := TruncateTimestamps(path, time.Minute, false)
+	if err != nil || result.Changed != 1 || !result.Written {
+		t.Fatalf("Result = %+v, %v, expected 1 entry rewritten", result, err)
+	}
+	entries, _ := ReadEntries(path)
+	if len(entries) != 2 || !entries[0].Timestamp.Equal(time.Date(2024, 1, 15, 9, 41, 0, 0, time.UTC)) {
+		t.Fatalf("Entries = %+v, expected the first one truncated to 09:41", entries)
+	}
+	if !entries[0].DeletedAt.Equal(deletedAt) || entries[0].DurationMinutes != 30 {
+		t.Errorf("Expected the deletion time and duration unchanged, got %+v", entries[0])
+	}
+	data, _ := os.ReadFile(path)
+	if lines := strings.Split(string(data), "\n"); lines[1] != "not json" || lines[2] != canonicalLine(whole) {
+		t.Errorf("Expected the other lines kept in place, got:\n%s", data)
+	}
+
+	again, err := TruncateTimestamps(path, time.Minute, false)
+	if err != nil || again.Changed != 0 || again.Written {
+		t.Errorf("Second run = %+v, %v, expected nothing to change", again, err)
+	}
+}
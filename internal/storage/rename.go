@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"strings"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// RenameProject renames the project from (matched ignoring case) to to in
+// every entry, including soft-deleted entries, and returns how many entries
+// changed. The storage file is rewritten atomically while holding the storage
+// lock; with dryRun set nothing is written.
+func RenameProject(storagePath, from, to string, dryRun bool) (int, error) {
+	changed, err := rewriteEntries(storagePath, dryRun, func(e *entry.Entry) bool {
+		if !strings.EqualFold(e.Project, from) || e.Project == to {
+			return false
+		}
+		e.Project = to
+		return true
+	})
+	return len(changed), err
+}
+
+// RenameTag renames the tag from (matched ignoring case) to to in every
+// entry, including soft-deleted entries, and returns how many entries
+// changed. An entry that already has the tag to keeps it once. The storage
+// file is rewritten atomically while holding the storage lock; with dryRun
+// set nothing is written.
+func RenameTag(storagePath, from, to string, dryRun bool) (int, error) {
+	changed, err := rewriteEntries(storagePath, dryRun, func(e *entry.Entry) bool {
+		tags := make([]string, 0, len(e.Tags))
+		found, seen := false, false
+		for _, tag := range e.Tags {
+			if strings.EqualFold(tag, from) {
+				found = true
+				tag = to
+			}
+			if strings.EqualFold(tag, to) {
+				if seen {
+					continue
+				}
+				seen = true
+			}
+			tags = append(tags, tag)
+		}
+		if !found || strings.Join(tags, "\x00") == strings.Join(e.Tags, "\x00") {
+			return false
+		}
+		e.Tags = tags
+		return true
+	})
+	return len(changed), err
+}
+
+// rewriteEntries applies change to every entry, holding the storage lock, and
+// writes the entries back when change reported a change for any of them.
+// Returns the changed entries as changed; with dryRun set nothing is written.
+// This is the single code path for bulk changes to the metadata of entries.
+func rewriteEntries(storagePath string, dryRun bool, change func(e *entry.Entry) bool) ([]entry.Entry, error) {
+	release, err := lockStorage(storagePath)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	entries, err := ReadEntries(storagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []entry.Entry
+	for i := range entries {
+		if change(&entries[i]) {
+			changed = append(changed, entries[i])
+		}
+	}
+	if dryRun || len(changed) == 0 {
+		return changed, nil
+	}
+	return changed, replaceAllEntries(storagePath, entries)
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// writeRenameEntries writes entries with projects and tags to rename
+func writeRenameEntries(t *testing.T) string {
+	t.Helper()
+	path := createTempFile(t, "")
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	deletedAt := now
+	for _, e := range []entry.Entry{
+		{Timestamp: now, Description: "a", DurationMinutes: 30, Project: "Acme", Tags: []string{"bug"}},
+		{Timestamp: now, Description: "b", DurationMinutes: 30, Project: "acme", Tags: []string{"bug", "bugfix"}},
+		{Timestamp: now, Description: "c", DurationMinutes: 30, Project: "other", Tags: []string{"review"}},
+		{Timestamp: now, Description: "d", DurationMinutes: 30, Project: "acme", Tags: []string{"BUG"}, DeletedAt: &deletedAt},
+	} {
+		if err := AppendEntry(path, e); err != nil {
+			t.Fatalf("AppendEntry() returned unexpected error: %v", err)
+		}
+	}
+	return path
+}
+
+func TestRenameProject(t *testing.T) {
+	path := writeRenameEntries(t)
+
+	changed, err := RenameProject(path, "acme", "acme-corp", true)
+	if err != nil || changed != 3 {
+		t.Fatalf("Dry run = %d, %v, expected 3 entries", changed, err)
+	}
+	entries, _ := ReadEntries(path)
+	if entries[0].Project != "Acme" {
+		t.Fatalf("Expected the dry run to write nothing, got project %q", entries[0].Project)
+	}
+
+	changed, err = RenameProject(path, "acme", "acme-corp", false)
+	if err != nil || changed != 3 {
+		t.Fatalf("RenameProject() = %d, %v, expected 3 entries", changed, err)
+	}
+	entries, _ = ReadEntries(path)
+	var projects []string
+	for _, e := range entries {
+		projects = append(projects, e.Project)
+	}
+	if expected := []string{"acme-corp", "acme-corp", "other", "acme-corp"}; !reflect.DeepEqual(projects, expected) {
+		t.Errorf("Expected projects %v, got %v", expected, projects)
+	}
+	if entries[3].DeletedAt == nil {
+		t.Error("Expected the deleted entry to stay deleted")
+	}
+
+	changed, err = RenameProject(path, "missing", "x", false)
+	if err != nil || changed != 0 {
+		t.Errorf("RenameProject() of a missing project = %d, %v, expected 0", changed, err)
+	}
+}
+
+func TestRenameTag(t *testing.T) {
+	path := writeRenameEntries(t)
+
+	changed, err := RenameTag(path, "bug", "bugfix", false)
+	if err != nil || changed != 3 {
+		t.Fatalf("RenameTag() = %d, %v, expected 3 entries", changed, err)
+	}
+	entries, _ := ReadEntries(path)
+	expected := [][]string{{"bugfix"}, {"bugfix"}, {"review"}, {"bugfix"}}
+	for i, e := range entries {
+		if !reflect.DeepEqual(e.Tags, expected[i]) {
+			t.Errorf("Entry %d: tags %v, expected %v", i, e.Tags, expected[i])
+		}
+	}
+
+	changed, err = RenameTag(path, "bugfix", "bugfix", false)
+	if err != nil || changed != 0 {
+		t.Errorf("RenameTag() to the same name = %d, %v, expected 0", changed, err)
+	}
+}
+
+func TestRenameProject_Directory(t *testing.T) {
+	dir := t.TempDir()
+	alice := entry.Entry{Timestamp: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), Description: "a", DurationMinutes: 30, Project: "acme", Source: "alice.jsonl"}
+	bob := alice
+	bob.Source = "bob.jsonl"
+	if err := writeDirectoryEntries(dir, []entry.Entry{alice, bob}); err != nil {
+		t.Fatalf("Failed to write directory entries: %v", err)
+	}
+
+	changed, err := RenameProject(dir, "acme", "client", false)
+	if err != nil || changed != 2 {
+		t.Fatalf("RenameProject() = %d, %v, expected 2 entries", changed, err)
+	}
+	entries, _ := ReadEntries(dir)
+	for _, e := range entries {
+		if e.Project != "client" {
+			t.Errorf("Expected project client in %s, got %q", e.Source, e.Project)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"strings"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// AddTag appends tag to every entry for which match returns true and that
+// does not have the tag yet (compared ignoring case), and returns the changed
+// entries. The storage file is rewritten atomically while holding the storage
+// lock; with dryRun set nothing is written.
+func AddTag(storagePath, tag string, match func(entry.Entry) bool, dryRun bool) ([]entry.Entry, error) {
+	return rewriteEntries(storagePath, dryRun, func(e *entry.Entry) bool {
+		if !match(*e) || hasTag(e.Tags, tag) {
+			return false
+		}
+		e.Tags = append(append([]string{}, e.Tags...), tag)
+		return true
+	})
+}
+
+// RemoveTag removes tag (matched ignoring case) from every entry for which
+// match returns true, and returns the changed entries. The storage file is
+// rewritten atomically while holding the storage lock; with dryRun set
+// nothing is written.
+func RemoveTag(storagePath, tag string, match func(entry.Entry) bool, dryRun bool) ([]entry.Entry, error) {
+	return rewriteEntries(storagePath, dryRun, func(e *entry.Entry) bool {
+		if !match(*e) || !hasTag(e.Tags, tag) {
+			return false
+		}
+		var kept []string
+		for _, t := range e.Tags {
+			if !strings.EqualFold(t, tag) {
+				kept = append(kept, t)
+			}
+		}
+		e.Tags = kept
+		return true
+	})
+}
+
+// hasTag reports whether tags contains tag, ignoring case
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+func TestAddTag(t *testing.T) {
+	path := writeRenameEntries(t)
+	acme := func(e entry.Entry) bool { return e.Project == "acme" }
+
+	changed, err := AddTag(path, "Bug", acme, true)
+	if err != nil || len(changed) != 0 {
+		t.Fatalf("AddTag() of a tag the entries have = %v, %v, expected no changes", changed, err)
+	}
+
+	changed, err = AddTag(path, "billable", acme, false)
+	if err != nil || len(changed) != 2 {
+		t.Fatalf("AddTag() = %v, %v, expected 2 changed entries", changed, err)
+	}
+	entries, _ := ReadEntries(path)
+	expected := [][]string{{"bug"}, {"bug", "bugfix", "billable"}, {"review"}, {"BUG", "billable"}}
+	for i, e := range entries {
+		if !reflect.DeepEqual(e.Tags, expected[i]) {
+			t.Errorf("Entry %d: tags %v, expected %v", i, e.Tags, expected[i])
+		}
+	}
+}
+
+func TestRemoveTag(t *testing.T) {
+	path := writeRenameEntries(t)
+	all := func(entry.Entry) bool { return true }
+
+	changed, err := RemoveTag(path, "bug", all, true)
+	if err != nil || len(changed) != 3 {
+		t.Fatalf("Dry run = %v, %v, expected 3 entries", changed, err)
+	}
+	if len(changed[0].Tags) != 0 {
+		t.Errorf("Expected the changed entries with the tag removed, got %v", changed[0].Tags)
+	}
+	entries, _ := ReadEntries(path)
+	if len(entries[0].Tags) != 1 {
+		t.Fatalf("Expected the dry run to write nothing, got tags %v", entries[0].Tags)
+	}
+
+	if _, err := RemoveTag(path, "bug", all, false); err != nil {
+		t.Fatalf("RemoveTag() returned unexpected error: %v", err)
+	}
+	entries, _ = ReadEntries(path)
+	expected := [][]string{nil, {"bugfix"}, {"review"}, nil}
+	for i, e := range entries {
+		if !reflect.DeepEqual(e.Tags, expected[i]) {
+			t.Errorf("Entry %d: tags %v, expected %v", i, e.Tags, expected[i])
+		}
+	}
+}
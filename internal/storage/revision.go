@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// ErrStorageChanged is returned by UpdateEntryIfUnchanged when the storage
+// was written to after the entry being changed was read
+var ErrStorageChanged = errors.New("storage changed since read, please retry")
+
+// Revision identifies the contents of the storage at the time it was read by
+// the size and modification time of its files (see StorageRevision). The zero
+// Revision is that of storage that doesn't exist yet.
+type Revision struct {
+	stamp string
+}
+
+// beforeCheckedReplace runs after UpdateEntryIfUnchanged changed the entries
+// and before it checks the revision again and writes them. Tests replace it
+// to simulate a write racing the update.
+var beforeCheckedReplace = func() {}
+
+// StorageRevision returns the current Revision of the storage file, or of
+// every *.jsonl file when storagePath is a directory
+func StorageRevision(storagePath string) (Revision, error) {
+	paths := []string{storagePath}
+	if IsDirectory(storagePath) {
+		names, err := directoryFiles(storagePath)
+		if err != nil {
+			return Revision{}, err
+		}
+		paths = paths[:0]
+		for _, name := range names {
+			paths = append(paths, filepath.Join(storagePath, name))
+		}
+	}
+
+	var stamps []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return Revision{}, err
+		}
+		stamps = append(stamps, fmt.Sprintf("%s:%d:%d", filepath.Base(path), info.Size(), info.ModTime().UnixNano()))
+	}
+	return Revision{stamp: strings.Join(stamps, "\n")}, nil
+}
+
+// checkRevision returns ErrStorageChanged unless the storage is still at rev
+func checkRevision(storagePath string, rev Revision) error {
+	current, err := StorageRevision(storagePath)
+	if err != nil {
+		return err
+	}
+	if current != rev {
+		return ErrStorageChanged
+	}
+	return nil
+}
+
+// UpdateEntryIfUnchanged replaces the entry at index like UpdateEntry, but
+// only while the storage is at rev, the revision taken before the entry was
+// read. The revision is checked when the storage lock is taken and again just
+// before the file is replaced, so neither another did process nor an editor
+// writing the file in the meantime is overwritten; ErrStorageChanged is
+// returned then and nothing is written.
+func UpdateEntryIfUnchanged(storagePath string, index int, e entry.Entry, rev Revision) error {
+	release, err := lockStorage(storagePath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := checkRevision(storagePath, rev); err != nil {
+		return err
+	}
+	entries, err := ReadEntries(storagePath)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(entries) {
+		return os.ErrInvalid
+	}
+	if e.Source == "" {
+		e.Source = entries[index].Source
+	}
+	entries[index] = e
+
+	beforeCheckedReplace()
+	if IsDirectory(storagePath) {
+		if err := checkRevision(storagePath, rev); err != nil {
+			return err
+		}
+		return writeDirectoryEntries(storagePath, entries)
+	}
+	return replaceFileEntriesChecked(storagePath, entries, func() error {
+		return checkRevision(storagePath, rev)
+	})
+}
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// revisionTestEntries returns two entries for the revision tests
+func revisionTestEntries() []entry.Entry {
+	return []entry.Entry{
+		{Timestamp: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), Description: "standup", DurationMinutes: 15},
+		{Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), Description: "review", DurationMinutes: 30},
+	}
+}
+
+func TestUpdateEntryIfUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EntriesFile)
+	if _, err := AppendEntries(path, revisionTestEntries()); err != nil {
+		t.Fatalf("AppendEntries() returned unexpected error: %v", err)
+	}
+	rev, err := StorageRevision(path)
+	if err != nil {
+		t.Fatalf("StorageRevision() returned unexpected error: %v", err)
+	}
+
+	edited := revisionTestEntries()[1]
+	edited.Description = "code review"
+	if err := UpdateEntryIfUnchanged(path, 1, edited, rev); err != nil {
+		t.Fatalf("UpdateEntryIfUnchanged() returned unexpected error: %v", err)
+	}
+	entries, _ := ReadEntries(path)
+	if len(entries) != 2 || entries[1].Description != "code review" {
+		t.Errorf("Expected the second entry updated, got %+v", entries)
+	}
+
+	// The revision taken before the update is stale now
+	edited.Description = "stale edit"
+	if err := UpdateEntryIfUnchanged(path, 1, edited, rev); !errors.Is(err, ErrStorageChanged) {
+		t.Errorf("UpdateEntryIfUnchanged() with a stale revision = %v, expected ErrStorageChanged", err)
+	}
+	if entries, _ := ReadEntries(path); entries[1].Description != "code review" {
+		t.Errorf("Expected the stale edit not to be saved, got %q", entries[1].Description)
+	}
+}
+
+func TestUpdateEntryIfUnchanged_RacingAppend(t *testing.T) {
+	racing := entry.Entry{Timestamp: time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC), Description: "cron append", DurationMinutes: 5}
+
+	tests := []struct {
+		name string
+		// race writes to the storage at path, while the update holds the
+		// lock when inside is set
+		race   func(t *testing.T, path string)
+		inside bool
+	}{
+		{"before the lock", func(t *testing.T, path string) {
+			if err := AppendEntry(path, racing); err != nil {
+				t.Fatalf("AppendEntry() returned unexpected error: %v", err)
+			}
+		}, false},
+		{"between read and write", func(t *testing.T, path string) {
+			// An editor or a process ignoring the lock appends a line
+			file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				t.Fatalf("Failed to open storage: %v", err)
+			}
+			defer func() { _ = file.Close() }()
+			if _, err := file.WriteString(canonicalLine(racing) + "\n"); err != nil {
+				t.Fatalf("Failed to append: %v", err)
+			}
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), EntriesFile)
+			if _, err := AppendEntries(path, revisionTestEntries()); err != nil {
+				t.Fatalf("AppendEntries() returned unexpected error: %v", err)
+			}
+			rev, err := StorageRevision(path)
+			if err != nil {
+				t.Fatalf("StorageRevision() returned unexpected error: %v", err)
+			}
+
+			if tt.inside {
+				beforeCheckedReplace = func() { tt.race(t, path) }
+				defer func() { beforeCheckedReplace = func() {} }()
+			} else {
+				tt.race(t, path)
+			}
+
+			edited := revisionTestEntries()[0]
+			edited.Description = "daily standup"
+			err = UpdateEntryIfUnchanged(path, 0, edited, rev)
+			if !errors.Is(err, ErrStorageChanged) || !strings.Contains(err.Error(), "please retry") {
+				t.Fatalf("UpdateEntryIfUnchanged() = %v, expected ErrStorageChanged", err)
+			}
+
+			entries, _ := ReadEntries(path)
+			if len(entries) != 3 || entries[0].Description != "standup" || entries[2].Description != "cron append" {
+				t.Errorf("Expected the racing append kept and the edit not saved, got %+v", entries)
+			}
+			if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+				t.Error("Expected the temp file to be removed")
+			}
+		})
+	}
+}
+
+func TestStorageRevision_Directory(t *testing.T) {
+	dir := t.TempDir()
+	empty, err := StorageRevision(dir)
+	if err != nil || empty != (Revision{}) {
+		t.Fatalf("StorageRevision() of an empty directory = %v, %v, expected the zero revision", empty, err)
+	}
+
+	path := filepath.Join(dir, "alice.jsonl")
+	if err := os.WriteFile(path, []byte(canonicalLine(revisionTestEntries()[0])+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	rev, _ := StorageRevision(dir)
+	if rev == empty {
+		t.Error("Expected a new file to change the revision")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "bob.jsonl"), []byte("\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	edited := revisionTestEntries()[0]
+	edited.Source = "alice.jsonl"
+	if err := UpdateEntryIfUnchanged(dir, 0, edited, rev); !errors.Is(err, ErrStorageChanged) {
+		t.Errorf("UpdateEntryIfUnchanged() after another file was added = %v, expected ErrStorageChanged", err)
+	}
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+// SortResult describes the result of sorting the storage file by timestamp
+type SortResult struct {
+	Entries   int // Number of valid entries
+	Moved     int // Number of entries whose position changes (see CountOutOfOrder)
+	Corrupted int // Number of corrupted lines, kept after the entries
+	Written   bool
+}
+
+// CountOutOfOrder returns how many entries are not at the position they have
+// when the entries are sorted by timestamp. The sort is stable, so entries with
+// equal timestamps keep their relative order.
+func CountOutOfOrder(entries []entry.Entry) int {
+	order := sortedOrder(entries)
+	moved := 0
+	for i, original := range order {
+		if i != original {
+			moved++
+		}
+	}
+	return moved
+}
+
+// sortedOrder returns the indices of entries in stable timestamp order
+func sortedOrder(entries []entry.Entry) []int {
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return entries[order[i]].Timestamp.Before(entries[order[j]].Timestamp)
+	})
+	return order
+}
+
+// SortEntries rewrites the storage file with its entries sorted by timestamp.
+// The sort is stable: entries with equal timestamps keep their relative order.
+// Entry lines are written back unchanged. Corrupted lines are kept, moved
+// after all entries in their original order, so they can still be inspected
+// and repaired. When the storage path is a directory, each file is sorted on
+// its own. The rewrite is atomic and holds the storage lock; with dryRun set
+// nothing is written and the result only reports what would change.
+func SortEntries(storagePath string, dryRun bool) (SortResult, error) {
+	release, err := lockStorage(storagePath)
+	if err != nil {
+		return SortResult{}, err
+	}
+	defer release()
+
+	if !IsDirectory(storagePath) {
+		return sortFile(storagePath, dryRun)
+	}
+
+	names, err := directoryFiles(storagePath)
+	if err != nil {
+		return SortResult{}, err
+	}
+	var total SortResult
+	for _, name := range names {
+		result, err := sortFile(filepath.Join(storagePath, name), dryRun)
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", name, err)
+		}
+		total.Entries += result.Entries
+		total.Moved += result.Moved
+		total.Corrupted += result.Corrupted
+		total.Written = total.Written || result.Written
+	}
+	return total, nil
+}
+
+// sortFile sorts the lines of a single storage file, see SortEntries
+func sortFile(path string, dryRun bool) (SortResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return SortResult{}, nil
+		}
+		return SortResult{}, err
+	}
+
+	var entries []entry.Entry
+	var entryLines, corruptedLines []string
+	corruptedAtEnd := true
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := cleanLine(scanner.Text(), lineNumber)
+		var e entry.Entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			corruptedLines = append(corruptedLines, line)
+			continue
+		}
+		if len(corruptedLines) > 0 {
+			corruptedAtEnd = false
+		}
+		entries = append(entries, e)
+		entryLines = append(entryLines, line)
+	}
+	_ = file.Close()
+	if err := scanner.Err(); err != nil {
+		return SortResult{}, err
+	}
+
+	order := sortedOrder(entries)
+	result := SortResult{Entries: len(entries), Corrupted: len(corruptedLines)}
+	lines := make([]string, 0, len(entryLines)+len(corruptedLines))
+	for i, original := range order {
+		if i != original {
+			result.Moved++
+		}
+		lines = append(lines, entryLines[original])
+	}
+	lines = append(lines, corruptedLines...)
+
+	if dryRun || (result.Moved == 0 && corruptedAtEnd) {
+		return result, nil
+	}
+
+	if err := replaceFileLines(path, lines); err != nil {
+		return result, err
+	}
+	result.Written = true
+	return result, nil
+}
+
+// replaceFileLines atomically replaces the contents of the file at path with lines
+func replaceFileLines(path string, lines []string) error {
+	tmpFile := path + ".tmp"
+	file, err := os.OpenFile(tmpFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	for _, line := range lines {
+		_, _ = w.WriteString(line + "\n")
+	}
+	if err := w.Flush(); err != nil {
+		_ = file.Close()
+		_ = os.Remove(tmpFile)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(tmpFile)
+		return err
+	}
+
+	return os.Rename(tmpFile, path)
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xolan/did/internal/entry"
+)
+
+func TestCountOutOfOrder(t *testing.T) {
+	at := func(hour int) entry.Entry {
+		return entry.Entry{Timestamp: time.Date(2024, 1, 15, hour, 0, 0, 0, time.UTC)}
+	}
+
+	tests := []struct {
+		name    string
+		entries []entry.Entry
+		want    int
+	}{
+		{"empty", nil, 0},
+		{"sorted", []entry.Entry{at(9), at(10), at(11)}, 0},
+		{"equal timestamps", []entry.Entry{at(9), at(9), at(10)}, 0},
+		{"one late entry", []entry.Entry{at(9), at(11), at(12), at(10)}, 3},
+		{"reversed", []entry.Entry{at(12), at(11), at(10)}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CountOutOfOrder(tt.entries); got != tt.want {
+				t.Errorf("CountOutOfOrder() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+const unsortedContent = `{"timestamp":"2024-01-15T10:00:00Z","description":"second","duration_minutes":30,"raw_input":"second for 30m"}
+not json
+{"timestamp":"2024-01-15T09:00:00Z","description":"first","duration_minutes":60,"raw_input":"first for 1h"}
+{"timestamp":"2024-01-15T10:00:00Z","description":"third","duration_minutes":15,"raw_input":"third for 15m"}
+`
+
+const sortedContent = `{"timestamp":"2024-01-15T09:00:00Z","description":"first","duration_minutes":60,"raw_input":"first for 1h"}
+{"timestamp":"2024-01-15T10:00:00Z","description":"second","duration_minutes":30,"raw_input":"second for 30m"}
+{"timestamp":"2024-01-15T10:00:00Z","description":"third","duration_minutes":15,"raw_input":"third for 15m"}
+not json
+`
+
+func TestSortEntries(t *testing.T) {
+	tmpFile := createTempFile(t, unsortedContent)
+
+	result, err := SortEntries(tmpFile, false)
+	if err != nil {
+		t.Fatalf("SortEntries() returned unexpected error: %v", err)
+	}
+	if result.Entries != 3 || result.Moved != 2 || result.Corrupted != 1 || !result.Written {
+		t.Errorf("SortEntries() = %+v, want 3 entries, 2 moved, 1 corrupted, written", result)
+	}
+
+	data, err := os.ReadFile(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != sortedContent {
+		t.Errorf("Sorted file =\n%s\nwant\n%s", data, sortedContent)
+	}
+	if _, err := os.Stat(tmpFile + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Expected the temp file to be renamed")
+	}
+}
+
+func TestSortEntries_DryRun(t *testing.T) {
+	tmpFile := createTempFile(t, unsortedContent)
+
+	result, err := SortEntries(tmpFile, true)
+	if err != nil {
+		t.Fatalf("SortEntries() returned unexpected error: %v", err)
+	}
+	if result.Moved != 2 || result.Written {
+		t.Errorf("SortEntries(dryRun) = %+v, want 2 moved and nothing written", result)
+	}
+	if data, _ := os.ReadFile(tmpFile); string(data) != unsortedContent {
+		t.Errorf("Expected a dry run to leave the file unchanged, got:\n%s", data)
+	}
+}
+
+func TestSortEntries_AlreadySorted(t *testing.T) {
+	tmpFile := createTempFile(t, sortedContent)
+	info, err := os.Stat(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SortEntries(tmpFile, false)
+	if err != nil {
+		t.Fatalf("SortEntries() returned unexpected error: %v", err)
+	}
+	if result.Moved != 0 || result.Written {
+		t.Errorf("SortEntries() = %+v, want nothing moved or written", result)
+	}
+	if after, _ := os.Stat(tmpFile); !after.ModTime().Equal(info.ModTime()) {
+		t.Error("Expected a sorted file not to be rewritten")
+	}
+}
+
+func TestSortEntries_NonExistentFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.jsonl")
+
+	result, err := SortEntries(path, false)
+	if err != nil {
+		t.Fatalf("SortEntries() returned unexpected error: %v", err)
+	}
+	if result != (SortResult{}) {
+		t.Errorf("SortEntries() = %+v, want zero result", result)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected no file to be created")
+	}
+}
+
+func TestSortEntries_Directory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "alice.jsonl"), []byte(unsortedContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bob.jsonl"), []byte(sortedContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SortEntries(dir, false)
+	if err != nil {
+		t.Fatalf("SortEntries() returned unexpected error: %v", err)
+	}
+	if result.Entries != 6 || result.Moved != 2 || result.Corrupted != 2 {
+		t.Errorf("SortEntries() = %+v, want 6 entries, 2 moved, 2 corrupted", result)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "alice.jsonl")); string(data) != sortedContent {
+		t.Errorf("Expected alice.jsonl to be sorted, got:\n%s", data)
+	}
+}
+
+func TestValidateStorage_OutOfOrder(t *testing.T) {
+	SetDisplayLocation(time.UTC)
+	defer SetDisplayLocation(nil)
+
+	health, err := ValidateStorage(createTempFile(t, unsortedContent))
+	if err != nil {
+		t.Fatalf("ValidateStorage() returned unexpected error: %v", err)
+	}
+	if health.OutOfOrder != 2 {
+		t.Errorf("OutOfOrder = %d, expected 2", health.OutOfOrder)
+	}
+	// The corrupted line is skipped when comparing with the previous entry
+	expected := "dated 2024-01-15 09:00, before the entry on line 1 (2024-01-15 10:00)"
+	if len(health.Backdated) != 1 || health.Backdated[0].LineNumber != 3 || health.Backdated[0].Error != expected {
+		t.Errorf("Backdated = %+v, expected line 3 %q", health.Backdated, expected)
+	}
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/xolan/did/internal/app"
+	"github.com/xolan/did/internal/osutil"
+)
+
+const (
+	// WarningStateFile is the name of the file remembering when corrupted-line warnings were last shown
+	WarningStateFile = "warnings.json"
+)
+
+// WarningState records which set of corrupted-line warnings was last shown and when
+type WarningState struct {
+	// Hash identifies the set of warnings (see WarningsHash)
+	Hash string `json:"hash"`
+	// ShownAt is when the warnings were last shown
+	ShownAt time.Time `json:"shown_at"`
+}
+
+// GetWarningStatePath returns the path to the warning state file in the config directory.
+// The config directory is not created; without it the state isn't saved.
+func GetWarningStatePath() (string, error) {
+	configDir, err := osutil.Provider.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, app.Name, WarningStateFile), nil
+}
+
+// WarningsHash returns a hash of the content of a set of warnings, which
+// changes whenever a corrupted line is added, removed or altered
+func WarningsHash(warnings []ParseWarning) string {
+	h := sha256.New()
+	for _, w := range warnings {
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00%s\x00%s\n", w.File, w.LineNumber, w.Content, w.Error)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ReadWarningState reads the warning state file.
+// Returns an empty WarningState if the file doesn't exist.
+func ReadWarningState(path string) (WarningState, error) {
+	var state WarningState
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, err
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return WarningState{}, err
+	}
+
+	return state, nil
+}
+
+// WriteWarningState writes the warning state file.
+// Uses atomic write pattern (write to temp file, then rename) for safety.
+func WriteWarningState(path string, state WarningState) error {
+	// WarningState contains only JSON-safe types, so Marshal cannot fail
+	data, _ := json.MarshalIndent(state, "", "  ")
+
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpFile, path); err != nil {
+		_ = os.Remove(tmpFile)
+		return err
+	}
+	return nil
+}
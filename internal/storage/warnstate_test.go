@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWarningsHash(t *testing.T) {
+	warnings := []ParseWarning{
+		{LineNumber: 2, Content: "not json", Error: "invalid character"},
+		{LineNumber: 5, Content: "{broken", Error: "unexpected end of JSON input"},
+	}
+
+	if WarningsHash(warnings) != WarningsHash(append([]ParseWarning{}, warnings...)) {
+		t.Error("WarningsHash() should be stable for the same warnings")
+	}
+
+	changed := append([]ParseWarning{}, warnings...)
+	changed[1].LineNumber = 6
+	if WarningsHash(changed) == WarningsHash(warnings) {
+		t.Error("WarningsHash() should change when a line number changes")
+	}
+
+	if WarningsHash(warnings[:1]) == WarningsHash(warnings) {
+		t.Error("WarningsHash() should change when a warning is removed")
+	}
+
+	inFile := append([]ParseWarning{}, warnings...)
+	inFile[0].File = "bob.jsonl"
+	if WarningsHash(inFile) == WarningsHash(warnings) {
+		t.Error("WarningsHash() should change when the file changes")
+	}
+}
+
+func TestReadWarningState_MissingFile(t *testing.T) {
+	state, err := ReadWarningState(filepath.Join(t.TempDir(), WarningStateFile))
+	if err != nil {
+		t.Fatalf("ReadWarningState() returned error: %v", err)
+	}
+	if state.Hash != "" || !state.ShownAt.IsZero() {
+		t.Errorf("Expected empty state, got %+v", state)
+	}
+}
+
+func TestWriteWarningState_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), WarningStateFile)
+	shownAt := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+
+	if err := WriteWarningState(path, WarningState{Hash: "abc", ShownAt: shownAt}); err != nil {
+		t.Fatalf("WriteWarningState() returned error: %v", err)
+	}
+
+	state, err := ReadWarningState(path)
+	if err != nil {
+		t.Fatalf("ReadWarningState() returned error: %v", err)
+	}
+	if state.Hash != "abc" || !state.ShownAt.Equal(shownAt) {
+		t.Errorf("Expected hash 'abc' shown at %v, got %+v", shownAt, state)
+	}
+
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Temp file should not remain after WriteWarningState()")
+	}
+}
+
+func TestReadWarningState_CorruptedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), WarningStateFile)
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+
+	if _, err := ReadWarningState(path); err == nil {
+		t.Error("ReadWarningState() should return error for corrupted state file")
+	}
+}
+
+func TestWriteWarningState_MissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", WarningStateFile)
+
+	if err := WriteWarningState(path, WarningState{Hash: "abc"}); err == nil {
+		t.Error("WriteWarningState() should return error when the directory doesn't exist")
+	}
+}
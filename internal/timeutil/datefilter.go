@@ -1,6 +1,10 @@
 package timeutil
 
-import "time"
+import (
+	"os"
+	"strings"
+	"time"
+)
 
 // StartOfDay returns midnight (00:00:00) of the given day in the same timezone
 func StartOfDay(t time.Time) time.Time {
@@ -48,27 +52,27 @@ func EndOfWeekWithConfig(t time.Time, weekStartDay string) time.Time {
 	return StartOfWeekWithConfig(t, weekStartDay).AddDate(0, 0, 7).Add(-time.Nanosecond)
 }
 
-// Today returns the start and end times for today
-func Today() (start, end time.Time) {
-	now := time.Now()
+// Today returns the start and end times of the day of now. Pass the current
+// time in the configured timezone (see NowIn), so the day is that of the
+// timezone entries are shown in.
+func Today(now time.Time) (start, end time.Time) {
 	return StartOfDay(now), EndOfDay(now)
 }
 
-// Yesterday returns the start and end times for yesterday
-func Yesterday() (start, end time.Time) {
-	yesterday := time.Now().AddDate(0, 0, -1)
+// Yesterday returns the start and end times of the day before now
+func Yesterday(now time.Time) (start, end time.Time) {
+	yesterday := now.AddDate(0, 0, -1)
 	return StartOfDay(yesterday), EndOfDay(yesterday)
 }
 
-// ThisWeek returns the start and end times for the current week (Monday-Sunday)
-func ThisWeek() (start, end time.Time) {
-	now := time.Now()
+// ThisWeek returns the start and end times for the week (Monday-Sunday) of now
+func ThisWeek(now time.Time) (start, end time.Time) {
 	return StartOfWeek(now), EndOfWeek(now)
 }
 
-// LastWeek returns the start and end times for the previous week (Monday-Sunday)
-func LastWeek() (start, end time.Time) {
-	thisWeekStart, _ := ThisWeek()
+// LastWeek returns the start and end times for the week (Monday-Sunday) before that of now
+func LastWeek(now time.Time) (start, end time.Time) {
+	thisWeekStart, _ := ThisWeek(now)
 	lastWeekStart := thisWeekStart.AddDate(0, 0, -7)
 	return lastWeekStart, EndOfWeek(lastWeekStart)
 }
@@ -85,16 +89,51 @@ func EndOfMonth(t time.Time) time.Time {
 	return StartOfMonth(t).AddDate(0, 1, 0).Add(-time.Nanosecond)
 }
 
-// ThisMonth returns the start and end times for the current month
-func ThisMonth() (start, end time.Time) {
-	now := time.Now()
-	return StartOfMonth(now), EndOfMonth(now)
+// StartOfMonthWithConfig returns the start (00:00:00) of the month containing
+// the given time when months start on monthStartDay (1-28), e.g. May 25 for
+// any day from May 25 to Jun 24 with a start day of 25. A start day of 1 or
+// less is the calendar month.
+func StartOfMonthWithConfig(t time.Time, monthStartDay int) time.Time {
+	if monthStartDay <= 1 {
+		return StartOfMonth(t)
+	}
+	month := t.Month()
+	if t.Day() < monthStartDay {
+		month--
+	}
+	return time.Date(t.Year(), month, monthStartDay, 0, 0, 0, 0, t.Location())
 }
 
-// LastMonth returns the start and end times for the previous month
-func LastMonth() (start, end time.Time) {
-	lastMonth := time.Now().AddDate(0, -1, 0)
-	return StartOfMonth(lastMonth), EndOfMonth(lastMonth)
+// EndOfMonthWithConfig returns the end (23:59:59.999999999) of the month
+// containing the given time when months start on monthStartDay, the day
+// before the start day of the next month (Jun 24 for May 25)
+func EndOfMonthWithConfig(t time.Time, monthStartDay int) time.Time {
+	return StartOfMonthWithConfig(t, monthStartDay).AddDate(0, 1, 0).Add(-time.Nanosecond)
+}
+
+// ThisMonth returns the start and end times for the calendar month of now
+func ThisMonth(now time.Time) (start, end time.Time) {
+	return ThisMonthWithConfig(now, 1)
+}
+
+// LastMonth returns the start and end times for the calendar month before that of now
+func LastMonth(now time.Time) (start, end time.Time) {
+	return LastMonthWithConfig(now, 1)
+}
+
+// ThisMonthWithConfig returns the start and end times for the month of now
+// when months start on monthStartDay
+func ThisMonthWithConfig(now time.Time, monthStartDay int) (start, end time.Time) {
+	return StartOfMonthWithConfig(now, monthStartDay), EndOfMonthWithConfig(now, monthStartDay)
+}
+
+// LastMonthWithConfig returns the start and end times for the month before
+// that of now when months start on monthStartDay: the month containing the
+// day before the month of now started
+func LastMonthWithConfig(now time.Time, monthStartDay int) (start, end time.Time) {
+	thisMonthStart, _ := ThisMonthWithConfig(now, monthStartDay)
+	lastMonth := thisMonthStart.AddDate(0, 0, -1)
+	return StartOfMonthWithConfig(lastMonth, monthStartDay), EndOfMonthWithConfig(lastMonth, monthStartDay)
 }
 
 // IsInRange checks if the given time t falls within the range [start, end] (inclusive)
@@ -111,22 +150,40 @@ func LoadTimezone(tz string) (*time.Location, error) {
 	return time.LoadLocation(tz)
 }
 
-// NowIn returns the current time in the specified timezone.
-// Falls back to Local if timezone is invalid or empty.
-func NowIn(tz string) time.Time {
-	loc, err := LoadTimezone(tz)
-	if err != nil {
-		return time.Now()
+// DetectTimezone returns the IANA name of the system timezone, taken from the TZ
+// environment variable or the /etc/localtime symlink. Returns "Local" if the
+// name cannot be determined.
+func DetectTimezone() string {
+	if tz := strings.TrimPrefix(os.Getenv("TZ"), ":"); tz != "" && tz != "Local" {
+		if _, err := time.LoadLocation(tz); err == nil {
+			return tz
+		}
+	}
+
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if idx := strings.Index(target, "zoneinfo/"); idx != -1 {
+			name := target[idx+len("zoneinfo/"):]
+			if _, err := time.LoadLocation(name); err == nil {
+				return name
+			}
+		}
 	}
-	return time.Now().In(loc)
+
+	return "Local"
+}
+
+// NowIn returns the current time in the specified timezone, or Local if it is empty.
+// Returns an error naming the timezone if it is invalid (see TimezoneError).
+func NowIn(tz string) (time.Time, error) {
+	return InTimezone(time.Now(), tz)
 }
 
-// InTimezone converts a time to the specified timezone.
-// Returns the original time if timezone is invalid or empty.
-func InTimezone(t time.Time, tz string) time.Time {
+// InTimezone converts a time to the specified timezone, or Local if it is empty.
+// Returns an error naming the timezone if it is invalid (see TimezoneError).
+func InTimezone(t time.Time, tz string) (time.Time, error) {
 	loc, err := LoadTimezone(tz)
 	if err != nil {
-		return t
+		return time.Time{}, TimezoneError(tz)
 	}
-	return t.In(loc)
+	return t.In(loc), nil
 }
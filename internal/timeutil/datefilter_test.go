@@ -1,6 +1,7 @@
 package timeutil
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -274,131 +275,131 @@ func TestEndOfWeek(t *testing.T) {
 }
 
 func TestToday(t *testing.T) {
-	start, end := Today()
+	start, end := Today(time.Now())
 	now := time.Now()
 
 	// Start should be midnight today
 	if start.Year() != now.Year() || start.Month() != now.Month() || start.Day() != now.Day() {
-		t.Errorf("Today() start date mismatch: got %v, expected today %v", start, now)
+		t.Errorf("Today(time.Now()) start date mismatch: got %v, expected today %v", start, now)
 	}
 	if start.Hour() != 0 || start.Minute() != 0 || start.Second() != 0 {
-		t.Errorf("Today() start not midnight: got %02d:%02d:%02d", start.Hour(), start.Minute(), start.Second())
+		t.Errorf("Today(time.Now()) start not midnight: got %02d:%02d:%02d", start.Hour(), start.Minute(), start.Second())
 	}
 
 	// End should be end of today
 	if end.Year() != now.Year() || end.Month() != now.Month() || end.Day() != now.Day() {
-		t.Errorf("Today() end date mismatch: got %v, expected today %v", end, now)
+		t.Errorf("Today(time.Now()) end date mismatch: got %v, expected today %v", end, now)
 	}
 	if end.Hour() != 23 || end.Minute() != 59 || end.Second() != 59 {
-		t.Errorf("Today() end not end of day: got %02d:%02d:%02d", end.Hour(), end.Minute(), end.Second())
+		t.Errorf("Today(time.Now()) end not end of day: got %02d:%02d:%02d", end.Hour(), end.Minute(), end.Second())
 	}
 
 	// Start should be before end
 	if !start.Before(end) {
-		t.Errorf("Today() start %v not before end %v", start, end)
+		t.Errorf("Today(time.Now()) start %v not before end %v", start, end)
 	}
 }
 
 func TestYesterday(t *testing.T) {
-	start, end := Yesterday()
+	start, end := Yesterday(time.Now())
 	now := time.Now()
 	expectedDay := now.AddDate(0, 0, -1)
 
 	// Start should be midnight yesterday
 	if start.Year() != expectedDay.Year() || start.Month() != expectedDay.Month() || start.Day() != expectedDay.Day() {
-		t.Errorf("Yesterday() start date mismatch: got %v, expected %v", start, expectedDay)
+		t.Errorf("Yesterday(time.Now()) start date mismatch: got %v, expected %v", start, expectedDay)
 	}
 	if start.Hour() != 0 || start.Minute() != 0 || start.Second() != 0 {
-		t.Errorf("Yesterday() start not midnight: got %02d:%02d:%02d", start.Hour(), start.Minute(), start.Second())
+		t.Errorf("Yesterday(time.Now()) start not midnight: got %02d:%02d:%02d", start.Hour(), start.Minute(), start.Second())
 	}
 
 	// End should be end of yesterday
 	if end.Year() != expectedDay.Year() || end.Month() != expectedDay.Month() || end.Day() != expectedDay.Day() {
-		t.Errorf("Yesterday() end date mismatch: got %v, expected %v", end, expectedDay)
+		t.Errorf("Yesterday(time.Now()) end date mismatch: got %v, expected %v", end, expectedDay)
 	}
 	if end.Hour() != 23 || end.Minute() != 59 || end.Second() != 59 {
-		t.Errorf("Yesterday() end not end of day: got %02d:%02d:%02d", end.Hour(), end.Minute(), end.Second())
+		t.Errorf("Yesterday(time.Now()) end not end of day: got %02d:%02d:%02d", end.Hour(), end.Minute(), end.Second())
 	}
 
 	// Start should be before end
 	if !start.Before(end) {
-		t.Errorf("Yesterday() start %v not before end %v", start, end)
+		t.Errorf("Yesterday(time.Now()) start %v not before end %v", start, end)
 	}
 
 	// Yesterday should be exactly one day before today
-	todayStart, _ := Today()
+	todayStart, _ := Today(time.Now())
 	if !start.AddDate(0, 0, 1).Equal(todayStart) {
-		t.Errorf("Yesterday() start + 1 day (%v) != Today() start (%v)", start.AddDate(0, 0, 1), todayStart)
+		t.Errorf("Yesterday(time.Now()) start + 1 day (%v) != Today() start (%v)", start.AddDate(0, 0, 1), todayStart)
 	}
 }
 
 func TestThisWeek(t *testing.T) {
-	start, end := ThisWeek()
+	start, end := ThisWeek(time.Now())
 
 	// Start should be Monday
 	if start.Weekday() != time.Monday {
-		t.Errorf("ThisWeek() start weekday = %s, expected Monday", start.Weekday())
+		t.Errorf("ThisWeek(time.Now()) start weekday = %s, expected Monday", start.Weekday())
 	}
 	if start.Hour() != 0 || start.Minute() != 0 || start.Second() != 0 {
-		t.Errorf("ThisWeek() start not midnight: got %02d:%02d:%02d", start.Hour(), start.Minute(), start.Second())
+		t.Errorf("ThisWeek(time.Now()) start not midnight: got %02d:%02d:%02d", start.Hour(), start.Minute(), start.Second())
 	}
 
 	// End should be Sunday
 	if end.Weekday() != time.Sunday {
-		t.Errorf("ThisWeek() end weekday = %s, expected Sunday", end.Weekday())
+		t.Errorf("ThisWeek(time.Now()) end weekday = %s, expected Sunday", end.Weekday())
 	}
 	if end.Hour() != 23 || end.Minute() != 59 || end.Second() != 59 {
-		t.Errorf("ThisWeek() end not end of day: got %02d:%02d:%02d", end.Hour(), end.Minute(), end.Second())
+		t.Errorf("ThisWeek(time.Now()) end not end of day: got %02d:%02d:%02d", end.Hour(), end.Minute(), end.Second())
 	}
 
 	// Start should be before end
 	if !start.Before(end) {
-		t.Errorf("ThisWeek() start %v not before end %v", start, end)
+		t.Errorf("ThisWeek(time.Now()) start %v not before end %v", start, end)
 	}
 
 	// Duration should be approximately 7 days
 	duration := end.Sub(start)
 	expectedDuration := 7*24*time.Hour - time.Nanosecond
 	if duration != expectedDuration {
-		t.Errorf("ThisWeek() duration = %v, expected %v", duration, expectedDuration)
+		t.Errorf("ThisWeek(time.Now()) duration = %v, expected %v", duration, expectedDuration)
 	}
 }
 
 func TestLastWeek(t *testing.T) {
-	start, end := LastWeek()
-	thisWeekStart, _ := ThisWeek()
+	start, end := LastWeek(time.Now())
+	thisWeekStart, _ := ThisWeek(time.Now())
 
 	// Start should be Monday
 	if start.Weekday() != time.Monday {
-		t.Errorf("LastWeek() start weekday = %s, expected Monday", start.Weekday())
+		t.Errorf("LastWeek(time.Now()) start weekday = %s, expected Monday", start.Weekday())
 	}
 	if start.Hour() != 0 || start.Minute() != 0 || start.Second() != 0 {
-		t.Errorf("LastWeek() start not midnight: got %02d:%02d:%02d", start.Hour(), start.Minute(), start.Second())
+		t.Errorf("LastWeek(time.Now()) start not midnight: got %02d:%02d:%02d", start.Hour(), start.Minute(), start.Second())
 	}
 
 	// End should be Sunday
 	if end.Weekday() != time.Sunday {
-		t.Errorf("LastWeek() end weekday = %s, expected Sunday", end.Weekday())
+		t.Errorf("LastWeek(time.Now()) end weekday = %s, expected Sunday", end.Weekday())
 	}
 	if end.Hour() != 23 || end.Minute() != 59 || end.Second() != 59 {
-		t.Errorf("LastWeek() end not end of day: got %02d:%02d:%02d", end.Hour(), end.Minute(), end.Second())
+		t.Errorf("LastWeek(time.Now()) end not end of day: got %02d:%02d:%02d", end.Hour(), end.Minute(), end.Second())
 	}
 
 	// Start should be before end
 	if !start.Before(end) {
-		t.Errorf("LastWeek() start %v not before end %v", start, end)
+		t.Errorf("LastWeek(time.Now()) start %v not before end %v", start, end)
 	}
 
 	// Last week start + 7 days should equal this week start
 	if !start.AddDate(0, 0, 7).Equal(thisWeekStart) {
-		t.Errorf("LastWeek() start + 7 days (%v) != ThisWeek() start (%v)", start.AddDate(0, 0, 7), thisWeekStart)
+		t.Errorf("LastWeek(time.Now()) start + 7 days (%v) != ThisWeek() start (%v)", start.AddDate(0, 0, 7), thisWeekStart)
 	}
 
 	// Duration should be approximately 7 days
 	duration := end.Sub(start)
 	expectedDuration := 7*24*time.Hour - time.Nanosecond
 	if duration != expectedDuration {
-		t.Errorf("LastWeek() duration = %v, expected %v", duration, expectedDuration)
+		t.Errorf("LastWeek(time.Now()) duration = %v, expected %v", duration, expectedDuration)
 	}
 }
 
@@ -751,71 +752,71 @@ func TestEndOfMonth(t *testing.T) {
 }
 
 func TestThisMonth(t *testing.T) {
-	start, end := ThisMonth()
+	start, end := ThisMonth(time.Now())
 	now := time.Now()
 
 	// Start should be first day of current month at midnight
 	if start.Year() != now.Year() || start.Month() != now.Month() || start.Day() != 1 {
-		t.Errorf("ThisMonth() start date mismatch: got %v, expected first of %v %d", start, now.Month(), now.Year())
+		t.Errorf("ThisMonth(time.Now()) start date mismatch: got %v, expected first of %v %d", start, now.Month(), now.Year())
 	}
 	if start.Hour() != 0 || start.Minute() != 0 || start.Second() != 0 {
-		t.Errorf("ThisMonth() start not midnight: got %02d:%02d:%02d", start.Hour(), start.Minute(), start.Second())
+		t.Errorf("ThisMonth(time.Now()) start not midnight: got %02d:%02d:%02d", start.Hour(), start.Minute(), start.Second())
 	}
 
 	// End should be last day of current month at end of day
 	if end.Year() != now.Year() || end.Month() != now.Month() {
-		t.Errorf("ThisMonth() end month/year mismatch: got %v, expected %v %d", end, now.Month(), now.Year())
+		t.Errorf("ThisMonth(time.Now()) end month/year mismatch: got %v, expected %v %d", end, now.Month(), now.Year())
 	}
 	if end.Hour() != 23 || end.Minute() != 59 || end.Second() != 59 {
-		t.Errorf("ThisMonth() end not end of day: got %02d:%02d:%02d", end.Hour(), end.Minute(), end.Second())
+		t.Errorf("ThisMonth(time.Now()) end not end of day: got %02d:%02d:%02d", end.Hour(), end.Minute(), end.Second())
 	}
 
 	// Start should be before end
 	if !start.Before(end) {
-		t.Errorf("ThisMonth() start %v not before end %v", start, end)
+		t.Errorf("ThisMonth(time.Now()) start %v not before end %v", start, end)
 	}
 
 	// Verify start is first of month
 	if start.Day() != 1 {
-		t.Errorf("ThisMonth() start day = %d, expected 1", start.Day())
+		t.Errorf("ThisMonth(time.Now()) start day = %d, expected 1", start.Day())
 	}
 }
 
 func TestLastMonth(t *testing.T) {
-	start, end := LastMonth()
+	start, end := LastMonth(time.Now())
 	now := time.Now()
-	expectedMonth := now.AddDate(0, -1, 0)
+	expectedMonth := StartOfMonth(now).AddDate(0, -1, 0)
 
 	// Start should be first day of last month at midnight
 	if start.Year() != expectedMonth.Year() || start.Month() != expectedMonth.Month() || start.Day() != 1 {
-		t.Errorf("LastMonth() start date mismatch: got %v, expected first of %v %d", start, expectedMonth.Month(), expectedMonth.Year())
+		t.Errorf("LastMonth(time.Now()) start date mismatch: got %v, expected first of %v %d", start, expectedMonth.Month(), expectedMonth.Year())
 	}
 	if start.Hour() != 0 || start.Minute() != 0 || start.Second() != 0 {
-		t.Errorf("LastMonth() start not midnight: got %02d:%02d:%02d", start.Hour(), start.Minute(), start.Second())
+		t.Errorf("LastMonth(time.Now()) start not midnight: got %02d:%02d:%02d", start.Hour(), start.Minute(), start.Second())
 	}
 
 	// End should be last day of last month at end of day
 	if end.Year() != expectedMonth.Year() || end.Month() != expectedMonth.Month() {
-		t.Errorf("LastMonth() end month/year mismatch: got %v, expected %v %d", end, expectedMonth.Month(), expectedMonth.Year())
+		t.Errorf("LastMonth(time.Now()) end month/year mismatch: got %v, expected %v %d", end, expectedMonth.Month(), expectedMonth.Year())
 	}
 	if end.Hour() != 23 || end.Minute() != 59 || end.Second() != 59 {
-		t.Errorf("LastMonth() end not end of day: got %02d:%02d:%02d", end.Hour(), end.Minute(), end.Second())
+		t.Errorf("LastMonth(time.Now()) end not end of day: got %02d:%02d:%02d", end.Hour(), end.Minute(), end.Second())
 	}
 
 	// Start should be before end
 	if !start.Before(end) {
-		t.Errorf("LastMonth() start %v not before end %v", start, end)
+		t.Errorf("LastMonth(time.Now()) start %v not before end %v", start, end)
 	}
 
 	// Verify start is first of month
 	if start.Day() != 1 {
-		t.Errorf("LastMonth() start day = %d, expected 1", start.Day())
+		t.Errorf("LastMonth(time.Now()) start day = %d, expected 1", start.Day())
 	}
 
 	// Last month end should be before this month start
-	thisMonthStart, _ := ThisMonth()
+	thisMonthStart, _ := ThisMonth(time.Now())
 	if !end.Before(thisMonthStart) {
-		t.Errorf("LastMonth() end %v not before ThisMonth() start %v", end, thisMonthStart)
+		t.Errorf("LastMonth(time.Now()) end %v not before ThisMonth() start %v", end, thisMonthStart)
 	}
 }
 
@@ -1355,7 +1356,10 @@ func TestLoadTimezone(t *testing.T) {
 func TestNowIn(t *testing.T) {
 	t.Run("empty timezone returns local time", func(t *testing.T) {
 		before := time.Now()
-		result := NowIn("")
+		result, err := NowIn("")
+		if err != nil {
+			t.Fatalf("NowIn(\"\") returned error: %v", err)
+		}
 		after := time.Now()
 
 		if result.Before(before) || result.After(after) {
@@ -1365,7 +1369,10 @@ func TestNowIn(t *testing.T) {
 
 	t.Run("Local timezone returns local time", func(t *testing.T) {
 		before := time.Now()
-		result := NowIn("Local")
+		result, err := NowIn("Local")
+		if err != nil {
+			t.Fatalf("NowIn(\"Local\") returned error: %v", err)
+		}
 		after := time.Now()
 
 		if result.Before(before) || result.After(after) {
@@ -1374,19 +1381,15 @@ func TestNowIn(t *testing.T) {
 	})
 
 	t.Run("valid timezone returns time in that timezone", func(t *testing.T) {
-		result := NowIn("UTC")
-		if result.Location().String() != "UTC" {
+		result, err := NowIn("UTC")
+		if err != nil || result.Location().String() != "UTC" {
 			t.Errorf("NowIn(\"UTC\") location = %v, expected UTC", result.Location())
 		}
 	})
 
-	t.Run("invalid timezone falls back to local", func(t *testing.T) {
-		before := time.Now()
-		result := NowIn("Invalid/Timezone")
-		after := time.Now()
-
-		if result.Before(before) || result.After(after) {
-			t.Errorf("NowIn(\"Invalid/Timezone\") returned time outside expected range")
+	t.Run("invalid timezone returns an error", func(t *testing.T) {
+		if _, err := NowIn("Invalid/Timezone"); err == nil || !strings.Contains(err.Error(), "Invalid/Timezone") {
+			t.Errorf("NowIn(\"Invalid/Timezone\") should return an error naming the timezone, got %v", err)
 		}
 	})
 }
@@ -1399,7 +1402,6 @@ func TestInTimezone(t *testing.T) {
 		inputTime  time.Time
 		tz         string
 		wantTzName string
-		wantSame   bool
 	}{
 		{
 			name:       "empty timezone converts to local",
@@ -1425,23 +1427,13 @@ func TestInTimezone(t *testing.T) {
 			tz:         "UTC",
 			wantTzName: "UTC",
 		},
-		{
-			name:      "invalid timezone returns original time",
-			inputTime: fixedTime,
-			tz:        "Invalid/Timezone",
-			wantSame:  true,
-		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := InTimezone(tt.inputTime, tt.tz)
-
-			if tt.wantSame {
-				if !result.Equal(tt.inputTime) || result.Location() != tt.inputTime.Location() {
-					t.Errorf("InTimezone(%v, %q) = %v, expected original time unchanged", tt.inputTime, tt.tz, result)
-				}
-				return
+			result, err := InTimezone(tt.inputTime, tt.tz)
+			if err != nil {
+				t.Fatalf("InTimezone(%v, %q) returned error: %v", tt.inputTime, tt.tz, err)
 			}
 
 			if result.Location().String() != tt.wantTzName {
@@ -1455,9 +1447,19 @@ func TestInTimezone(t *testing.T) {
 	}
 }
 
+func TestInTimezone_Invalid(t *testing.T) {
+	_, err := InTimezone(time.Now(), "Europe/Olso")
+	if err == nil || !strings.Contains(err.Error(), "did you mean 'Europe/Oslo'") {
+		t.Errorf("InTimezone() error = %v, expected a suggestion", err)
+	}
+}
+
 func TestInTimezone_PreservesInstant(t *testing.T) {
 	utcTime := time.Date(2024, time.January, 15, 17, 0, 0, 0, time.UTC)
-	nyTime := InTimezone(utcTime, "America/New_York")
+	nyTime, err := InTimezone(utcTime, "America/New_York")
+	if err != nil {
+		t.Fatalf("InTimezone() returned error: %v", err)
+	}
 
 	if !utcTime.Equal(nyTime) {
 		t.Errorf("InTimezone should preserve the instant: UTC %v != NY %v", utcTime, nyTime)
@@ -1467,3 +1469,77 @@ func TestInTimezone_PreservesInstant(t *testing.T) {
 		t.Errorf("InTimezone(17:00 UTC, America/New_York) hour = %d, expected 12 (EST)", nyTime.Hour())
 	}
 }
+
+func TestDetectTimezone(t *testing.T) {
+	t.Setenv("TZ", "America/New_York")
+	if got := DetectTimezone(); got != "America/New_York" {
+		t.Errorf("DetectTimezone() with TZ set = %q, expected %q", got, "America/New_York")
+	}
+
+	t.Setenv("TZ", "Not/AZone")
+	got := DetectTimezone()
+	if got == "Not/AZone" {
+		t.Error("DetectTimezone() should ignore an invalid TZ value")
+	}
+	if _, err := LoadTimezone(got); err != nil {
+		t.Errorf("DetectTimezone() = %q, which is not loadable: %v", got, err)
+	}
+}
+
+func TestMonthWithConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         time.Time
+		monthStartDay int
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{"calendar month", makeTime(2024, time.May, 25, 10, 0, 0), 1, makeTime(2024, time.May, 1, 0, 0, 0), makeTime(2024, time.May, 31, 0, 0, 0)},
+		{"unset start day is the calendar month", makeTime(2024, time.May, 25, 10, 0, 0), 0, makeTime(2024, time.May, 1, 0, 0, 0), makeTime(2024, time.May, 31, 0, 0, 0)},
+		{"on the start day", makeTime(2024, time.May, 25, 0, 0, 0), 25, makeTime(2024, time.May, 25, 0, 0, 0), makeTime(2024, time.June, 24, 0, 0, 0)},
+		{"after the start day", makeTime(2024, time.June, 10, 9, 0, 0), 25, makeTime(2024, time.May, 25, 0, 0, 0), makeTime(2024, time.June, 24, 0, 0, 0)},
+		{"day before the start day", makeTime(2024, time.June, 24, 23, 59, 59), 25, makeTime(2024, time.May, 25, 0, 0, 0), makeTime(2024, time.June, 24, 0, 0, 0)},
+		{"into February of a leap year", makeTime(2024, time.February, 10, 12, 0, 0), 25, makeTime(2024, time.January, 25, 0, 0, 0), makeTime(2024, time.February, 24, 0, 0, 0)},
+		{"out of February of a leap year", makeTime(2024, time.March, 1, 12, 0, 0), 25, makeTime(2024, time.February, 25, 0, 0, 0), makeTime(2024, time.March, 24, 0, 0, 0)},
+		{"out of February", makeTime(2023, time.March, 10, 12, 0, 0), 28, makeTime(2023, time.February, 28, 0, 0, 0), makeTime(2023, time.March, 27, 0, 0, 0)},
+		{"February 29 with start day 28", makeTime(2024, time.February, 29, 12, 0, 0), 28, makeTime(2024, time.February, 28, 0, 0, 0), makeTime(2024, time.March, 27, 0, 0, 0)},
+		{"start day 15 in February", makeTime(2023, time.February, 20, 12, 0, 0), 15, makeTime(2023, time.February, 15, 0, 0, 0), makeTime(2023, time.March, 14, 0, 0, 0)},
+		{"into the new year", makeTime(2024, time.December, 31, 12, 0, 0), 25, makeTime(2024, time.December, 25, 0, 0, 0), makeTime(2025, time.January, 24, 0, 0, 0)},
+		{"early January belongs to December", makeTime(2025, time.January, 3, 12, 0, 0), 25, makeTime(2024, time.December, 25, 0, 0, 0), makeTime(2025, time.January, 24, 0, 0, 0)},
+		{"early January with start day 2", makeTime(2025, time.January, 1, 8, 0, 0), 2, makeTime(2024, time.December, 2, 0, 0, 0), makeTime(2025, time.January, 1, 0, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := StartOfMonthWithConfig(tt.input, tt.monthStartDay)
+			if !start.Equal(tt.expectedStart) {
+				t.Errorf("StartOfMonthWithConfig(%v, %d) = %v, expected %v", tt.input, tt.monthStartDay, start, tt.expectedStart)
+			}
+			end := EndOfMonthWithConfig(tt.input, tt.monthStartDay)
+			if expected := EndOfDay(tt.expectedEnd); !end.Equal(expected) {
+				t.Errorf("EndOfMonthWithConfig(%v, %d) = %v, expected %v", tt.input, tt.monthStartDay, end, expected)
+			}
+		})
+	}
+}
+
+func TestLastMonthWithConfig(t *testing.T) {
+	for _, monthStartDay := range []int{1, 15, 25, 28} {
+		thisStart, thisEnd := ThisMonthWithConfig(time.Now(), monthStartDay)
+		lastStart, lastEnd := LastMonthWithConfig(time.Now(), monthStartDay)
+		now := time.Now()
+
+		if now.Before(thisStart) || now.After(thisEnd) {
+			t.Errorf("start day %d: this month %v - %v does not contain now", monthStartDay, thisStart, thisEnd)
+		}
+		if thisStart.Day() != monthStartDay || lastStart.Day() != monthStartDay {
+			t.Errorf("start day %d: months start on the %d and %d", monthStartDay, lastStart.Day(), thisStart.Day())
+		}
+		if !lastEnd.Add(time.Nanosecond).Equal(thisStart) {
+			t.Errorf("start day %d: last month ends %v, expected right before %v", monthStartDay, lastEnd, thisStart)
+		}
+		if lastStart.AddDate(0, 1, 0) != thisStart {
+			t.Errorf("start day %d: last month starts %v, expected a month before %v", monthStartDay, lastStart, thisStart)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,38 +15,125 @@ var (
 	euroPartialRe   = regexp.MustCompile(`^\d{1,2}/\d{1,2}$`)
 	tooManyPartsRe  = regexp.MustCompile(`^\d+[-/]\d+[-/]\d+[-/]`)
 	relativeDaysRe  = regexp.MustCompile(`^last\s(\d+)\sdays?$`)
+	agoRe           = regexp.MustCompile(`^(\d+) (day|days|week|weeks) ago$`)
+	lastWeekdayRe   = regexp.MustCompile(`^last (\S+)$`)
 )
 
-// ParseDate parses a date string in YYYY-MM-DD or DD/MM/YYYY format.
+// relativeDateExamples lists the accepted relative date forms for error messages
+const relativeDateExamples = "'today', 'yesterday', 'monday', 'last friday', '3 days ago', '2 weeks ago'"
+
+// maxRelativeDays bounds "N days ago" and "N weeks ago" (about 100 years)
+const maxRelativeDays = 100 * 366
+
+// weekdayNames maps full and abbreviated weekday names to time.Weekday
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// ParseDate parses a date string in YYYY-MM-DD or DD/MM/YYYY format, or a
+// relative date like "yesterday", "monday", "last friday" or "3 days ago".
 // Returns the parsed date at midnight (start of day) in local timezone.
+// Relative dates are resolved against the current time.
 // For ambiguous dates (like 05/06/2024), ISO format (YYYY-MM-DD) is preferred.
 //
 // Valid inputs:
 //   - "2024-01-15" (ISO format)
 //   - "15/01/2024" (European format)
+//   - "today", "yesterday"
+//   - "monday" (most recent Monday, today included)
+//   - "last friday" (most recent Friday before today)
+//   - "3 days ago", "2 weeks ago"
 //
 // Invalid inputs return an error with suggested formats.
 func ParseDate(input string) (time.Time, error) {
+	return ParseDateAt(input, time.Now())
+}
+
+// ParseDateAt parses a date like ParseDate, resolving relative dates against now.
+// Absolute dates are interpreted in now's location, so passing the current time
+// in the configured timezone resolves every form in that timezone.
+func ParseDateAt(input string, now time.Time) (time.Time, error) {
 	if input == "" {
 		return time.Time{}, fmt.Errorf("date cannot be empty (use format YYYY-MM-DD or DD/MM/YYYY, e.g., 2024-01-15 or 15/01/2024)")
 	}
 
 	// Try ISO format first (YYYY-MM-DD) - preferred for ambiguous dates
-	t, err := time.ParseInLocation("2006-01-02", input, time.Local)
+	t, err := time.ParseInLocation("2006-01-02", input, now.Location())
 	if err == nil {
 		return StartOfDay(t), nil
 	}
 
 	// Try European format (DD/MM/YYYY)
-	t, err = time.ParseInLocation("02/01/2006", input, time.Local)
+	t, err = time.ParseInLocation("02/01/2006", input, now.Location())
 	if err == nil {
 		return StartOfDay(t), nil
 	}
 
-	// Neither format worked - provide specific error based on input pattern
+	// Try relative forms (today, yesterday, weekdays, N days ago)
+	if t, ok, err := parseRelativeDate(input, now); ok {
+		return t, err
+	}
+
+	// No format worked - provide specific error based on input pattern
 	return time.Time{}, buildDateParseError(input)
 }
 
+// parseRelativeDate resolves keyword and relative date expressions against now.
+// The boolean result reports whether the input looked like a relative date at all;
+// when it does but cannot be resolved unambiguously, an error is returned.
+func parseRelativeDate(input string, now time.Time) (time.Time, bool, error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(input)), " ")
+	today := StartOfDay(now)
+
+	switch normalized {
+	case "today":
+		return today, true, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true, nil
+	}
+
+	// Bare weekday: most recent occurrence, including today
+	if weekday, ok := weekdayNames[normalized]; ok {
+		daysBack := (int(now.Weekday()) - int(weekday) + 7) % 7
+		return today.AddDate(0, 0, -daysBack), true, nil
+	}
+
+	// "last <weekday>": most recent occurrence strictly before today
+	if matches := lastWeekdayRe.FindStringSubmatch(normalized); matches != nil {
+		weekday, ok := weekdayNames[matches[1]]
+		if !ok {
+			return time.Time{}, true, fmt.Errorf("ambiguous relative date '%s' (use a specific day, e.g., %s)", input, relativeDateExamples)
+		}
+		daysBack := (int(now.Weekday()) - int(weekday) + 7) % 7
+		if daysBack == 0 {
+			daysBack = 7
+		}
+		return today.AddDate(0, 0, -daysBack), true, nil
+	}
+
+	// "N days ago" / "N weeks ago"
+	if matches := agoRe.FindStringSubmatch(normalized); matches != nil {
+		// Only digits, but possibly too many for an int
+		n, err := strconv.Atoi(matches[1])
+		unit := 1
+		if strings.HasPrefix(matches[2], "week") {
+			unit = 7
+		}
+		if err != nil || n > maxRelativeDays/unit {
+			return time.Time{}, true, fmt.Errorf("relative date '%s' is too far back (at most %d days ago)", input, maxRelativeDays)
+		}
+		return today.AddDate(0, 0, -n*unit), true, nil
+	}
+
+	return time.Time{}, false, nil
+}
+
 func buildDateParseError(input string) error {
 	switch {
 	case yearOnlyRe.MatchString(input):
@@ -59,10 +147,36 @@ func buildDateParseError(input string) error {
 	case tooManyPartsRe.MatchString(input):
 		return fmt.Errorf("invalid date '%s': too many date parts (use format YYYY-MM-DD or DD/MM/YYYY)", input)
 	default:
-		return fmt.Errorf("invalid date format '%s' (use YYYY-MM-DD or DD/MM/YYYY, e.g., 2024-01-15 or 15/01/2024)", input)
+		return fmt.Errorf("invalid date format '%s' (use YYYY-MM-DD or DD/MM/YYYY, e.g., 2024-01-15 or 15/01/2024, or a relative date: %s)", input, relativeDateExamples)
 	}
 }
 
+// ParseTimestamp parses an exact point in time, either in RFC3339 format
+// ("2024-01-15T15:00:00+01:00") or as "YYYY-MM-DD HH:MM". The second form has no
+// offset and is interpreted in loc; an RFC3339 value keeps its own offset.
+//
+// Valid inputs:
+//   - "2024-01-15T15:00:00Z" (RFC3339)
+//   - "2024-01-15 15:00" (date and clock time in loc)
+//
+// Invalid inputs return an error with the accepted formats.
+func ParseTimestamp(input string, loc *time.Location) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return time.Time{}, fmt.Errorf("timestamp cannot be empty (use RFC3339 or YYYY-MM-DD HH:MM, e.g., 2024-01-15T15:00:00Z or '2024-01-15 15:00')")
+	}
+
+	if t, err := time.Parse(time.RFC3339, input); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02 15:04", input, loc); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid timestamp '%s' (use RFC3339 or YYYY-MM-DD HH:MM, e.g., 2024-01-15T15:00:00Z or '2024-01-15 15:00')", input)
+}
+
 // ParseRelativeDays parses relative day expressions like "last N days".
 // Returns the start and end times for the range.
 // The range includes N complete days ending today (inclusive).
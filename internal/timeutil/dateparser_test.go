@@ -591,3 +591,159 @@ func TestParseDate_TooManyParts(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDateAt_RelativeForms(t *testing.T) {
+	// Wednesday, 2024-01-17 14:30 local time
+	now := makeTime(2024, time.January, 17, 14, 30, 0)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+	}{
+		{"today", "today", makeTime(2024, time.January, 17, 0, 0, 0)},
+		{"yesterday", "yesterday", makeTime(2024, time.January, 16, 0, 0, 0)},
+		{"case insensitive", "Yesterday", makeTime(2024, time.January, 16, 0, 0, 0)},
+		{"extra whitespace", "  last   friday ", makeTime(2024, time.January, 12, 0, 0, 0)},
+		{"weekday today", "wednesday", makeTime(2024, time.January, 17, 0, 0, 0)},
+		{"weekday earlier this week", "monday", makeTime(2024, time.January, 15, 0, 0, 0)},
+		{"weekday in previous week", "friday", makeTime(2024, time.January, 12, 0, 0, 0)},
+		{"abbreviated weekday", "mon", makeTime(2024, time.January, 15, 0, 0, 0)},
+		{"last weekday same as today", "last wednesday", makeTime(2024, time.January, 10, 0, 0, 0)},
+		{"last weekday earlier", "last monday", makeTime(2024, time.January, 15, 0, 0, 0)},
+		{"last sunday", "last sunday", makeTime(2024, time.January, 14, 0, 0, 0)},
+		{"days ago", "3 days ago", makeTime(2024, time.January, 14, 0, 0, 0)},
+		{"one day ago", "1 day ago", makeTime(2024, time.January, 16, 0, 0, 0)},
+		{"zero days ago", "0 days ago", makeTime(2024, time.January, 17, 0, 0, 0)},
+		{"days ago across month", "20 days ago", makeTime(2023, time.December, 28, 0, 0, 0)},
+		{"weeks ago", "2 weeks ago", makeTime(2024, time.January, 3, 0, 0, 0)},
+		{"one week ago", "1 week ago", makeTime(2024, time.January, 10, 0, 0, 0)},
+		{"iso still works", "2024-01-01", makeTime(2024, time.January, 1, 0, 0, 0)},
+		{"european still works", "01/01/2024", makeTime(2024, time.January, 1, 0, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDateAt(tt.input, now)
+			if err != nil {
+				t.Fatalf("ParseDateAt(%q) unexpected error: %v", tt.input, err)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("ParseDateAt(%q) = %v, expected %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseDateAt_UsesLocationOfNow(t *testing.T) {
+	loc := time.FixedZone("UTC+10", 10*60*60)
+	// 2024-01-17 02:00 in UTC+10 is still 2024-01-16 in UTC
+	now := time.Date(2024, time.January, 17, 2, 0, 0, 0, loc)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"today", time.Date(2024, time.January, 17, 0, 0, 0, 0, loc)},
+		{"yesterday", time.Date(2024, time.January, 16, 0, 0, 0, 0, loc)},
+		{"2024-01-10", time.Date(2024, time.January, 10, 0, 0, 0, 0, loc)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := ParseDateAt(tt.input, now)
+			if err != nil {
+				t.Fatalf("ParseDateAt(%q) unexpected error: %v", tt.input, err)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("ParseDateAt(%q) = %v, expected %v", tt.input, result, tt.expected)
+			}
+			if result.Location() != loc {
+				t.Errorf("ParseDateAt(%q) location = %v, expected %v", tt.input, result.Location(), loc)
+			}
+		})
+	}
+}
+
+func TestParseDateAt_RelativeErrors(t *testing.T) {
+	now := makeTime(2024, time.January, 17, 14, 30, 0)
+
+	tests := []struct {
+		name           string
+		input          string
+		expectedSubstr string
+	}{
+		{"last without weekday", "last week", "ambiguous relative date 'last week'"},
+		{"last with unknown word", "last blursday", "ambiguous relative date"},
+		{"future keyword", "tomorrow", "relative date: 'today', 'yesterday'"},
+		{"next weekday", "next friday", "invalid date format 'next friday'"},
+		{"ago without unit", "3 ago", "invalid date format '3 ago'"},
+		{"negative ago", "-3 days ago", "invalid date format"},
+		{"unsupported unit", "3 months ago", "invalid date format"},
+		{"days ago overflowing int", "99999999999999999999 days ago", "is too far back (at most 36600 days ago)"},
+		{"days ago too far back", "36601 days ago", "is too far back"},
+		{"weeks ago too far back", "6000 weeks ago", "is too far back"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDateAt(tt.input, now)
+			if err == nil {
+				t.Fatalf("ParseDateAt(%q) expected error, got nil", tt.input)
+			}
+			if !containsSubstring(err.Error(), tt.expectedSubstr) {
+				t.Errorf("ParseDateAt(%q) error = %q, expected to contain %q",
+					tt.input, err.Error(), tt.expectedSubstr)
+			}
+		})
+	}
+}
+
+func TestParseDate_RelativeUsesCurrentTime(t *testing.T) {
+	result, err := ParseDate("today")
+	if err != nil {
+		t.Fatalf("ParseDate(\"today\") unexpected error: %v", err)
+	}
+	if !result.Equal(StartOfDay(time.Now())) {
+		t.Errorf("ParseDate(\"today\") = %v, expected %v", result, StartOfDay(time.Now()))
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{"RFC3339 UTC", "2024-01-15T15:00:00Z", time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC), false},
+		{"RFC3339 with offset", "2024-01-15T15:00:00-05:00", time.Date(2024, 1, 15, 20, 0, 0, 0, time.UTC), false},
+		{"date and time in location", "2024-01-15 15:00", time.Date(2024, 1, 15, 15, 0, 0, 0, loc), false},
+		{"surrounding spaces", "  2024-01-15 09:30  ", time.Date(2024, 1, 15, 9, 30, 0, 0, loc), false},
+		{"empty", "", time.Time{}, true},
+		{"date only", "2024-01-15", time.Time{}, true},
+		{"time only", "15:00", time.Time{}, true},
+		{"invalid hour", "2024-01-15 25:00", time.Time{}, true},
+		{"garbage", "tomorrow at 3", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimestamp(tt.input, loc)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseTimestamp(%q) expected error, got %v", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTimestamp(%q) unexpected error: %v", tt.input, err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("ParseTimestamp(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
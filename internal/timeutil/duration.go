@@ -0,0 +1,30 @@
+package timeutil
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// FormatDuration formats minutes as a human-readable duration: "0m", "45m",
+// "2h" or "1h 30m". Durations of a day or more stay in hours ("25h 30m") so
+// totals compare at a glance; negative durations get a leading "-".
+func FormatDuration(minutes int) string {
+	if minutes < 0 {
+		return "-" + FormatDuration(-minutes)
+	}
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	hours := minutes / 60
+	mins := minutes % 60
+	if mins == 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dh %dm", hours, mins)
+}
+
+// FormatDurationDecimal formats minutes as decimal hours with two decimals,
+// e.g. "1.50" for 90 minutes, for spreadsheets and invoices
+func FormatDurationDecimal(minutes int) string {
+	return strconv.FormatFloat(float64(minutes)/60, 'f', 2, 64)
+}
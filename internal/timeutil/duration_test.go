@@ -0,0 +1,84 @@
+package timeutil
+
+import "testing"
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		minutes  int
+		expected string
+	}{
+		{"zero", 0, "0m"},
+		{"sub-hour", 45, "45m"},
+		{"exact hour", 60, "1h"},
+		{"hours and minutes", 90, "1h 30m"},
+		{"exact hours", 120, "2h"},
+		{"full day", 24 * 60, "24h"},
+		{"over a day", 25*60 + 30, "25h 30m"},
+		{"negative", -90, "-1h 30m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDuration(tt.minutes); got != tt.expected {
+				t.Errorf("FormatDuration(%d) = %q, expected %q", tt.minutes, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatDurationDecimal(t *testing.T) {
+	tests := []struct {
+		minutes  int
+		expected string
+	}{
+		{0, "0.00"},
+		{15, "0.25"},
+		{20, "0.33"},
+		{90, "1.50"},
+		{25 * 60, "25.00"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDurationDecimal(tt.minutes); got != tt.expected {
+			t.Errorf("FormatDurationDecimal(%d) = %q, expected %q", tt.minutes, got, tt.expected)
+		}
+	}
+}
+
+func TestNumberFormat_Format(t *testing.T) {
+	tests := []struct {
+		format   string
+		value    float64
+		decimals int
+		expected string
+	}{
+		{"plain", 1234.5, 2, "1234.50"},
+		{"en", 1234.5, 2, "1,234.50"},
+		{"de", 1234.5, 2, "1.234,50"},
+		{"fr", 1234.5, 2, "1 234,50"},
+		{"ch", 1234567.891, 1, "1'234'567.9"},
+		{"de", 999.999, 2, "1.000,00"},
+		{"de", -1234.5, 1, "-1.234,5"},
+		{"en", 123, 0, "123"},
+		{"en", 0.5, 1, "0.5"},
+	}
+
+	for _, tt := range tests {
+		if got := NumberFormats[tt.format].Format(tt.value, tt.decimals); got != tt.expected {
+			t.Errorf("NumberFormats[%q].Format(%v, %d) = %q, expected %q", tt.format, tt.value, tt.decimals, got, tt.expected)
+		}
+	}
+
+	if got := NumberFormats["de"].FormatDuration(90); got != "1,50" {
+		t.Errorf("FormatDuration(90) = %q, expected %q", got, "1,50")
+	}
+	for _, name := range NumberFormatNames() {
+		if _, ok := NumberFormats[name]; !ok {
+			t.Errorf("NumberFormatNames() lists unknown format %q", name)
+		}
+	}
+	if len(NumberFormatNames()) != len(NumberFormats) {
+		t.Errorf("NumberFormatNames() = %v, expected all %d formats", NumberFormatNames(), len(NumberFormats))
+	}
+}
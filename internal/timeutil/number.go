@@ -0,0 +1,65 @@
+package timeutil
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultNumberFormat is the name of the number format used when none is
+// configured: no thousands separator and a "." decimal point, as in "1234.50"
+const DefaultNumberFormat = "plain"
+
+// NumberFormat defines how decimal numbers such as decimal hours are written
+// in human output. Machine output (CSV, JSON) always uses DefaultNumberFormat.
+type NumberFormat struct {
+	Thousands string // Separator between groups of three digits ("" for none)
+	Decimal   string // Decimal separator
+}
+
+// NumberFormats are the number formats that can be configured, by name
+var NumberFormats = map[string]NumberFormat{
+	"plain": {Thousands: "", Decimal: "."},  // 1234.50
+	"en":    {Thousands: ",", Decimal: "."}, // 1,234.50
+	"de":    {Thousands: ".", Decimal: ","}, // 1.234,50
+	"fr":    {Thousands: " ", Decimal: ","}, // 1 234,50
+	"ch":    {Thousands: "'", Decimal: "."}, // 1'234.50
+}
+
+// NumberFormatNames returns the names of NumberFormats in a stable order, for
+// help texts and error messages
+func NumberFormatNames() []string {
+	return []string{"plain", "en", "de", "fr", "ch"}
+}
+
+// Format formats v with the given number of decimals, e.g. "1.234,50" for
+// 1234.5 with two decimals in the "de" format
+func (f NumberFormat) Format(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, fraction, _ := strings.Cut(s, ".")
+
+	if f.Thousands != "" {
+		var b strings.Builder
+		for i, digit := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				b.WriteString(f.Thousands)
+			}
+			b.WriteRune(digit)
+		}
+		whole = b.String()
+	}
+
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + f.Decimal + fraction
+}
+
+// FormatDuration formats minutes as decimal hours with two decimals, like
+// FormatDurationDecimal but in this number format, e.g. "1,50" in "de"
+func (f NumberFormat) FormatDuration(minutes int) string {
+	return f.Format(float64(minutes)/60, 2)
+}
@@ -0,0 +1,96 @@
+package timeutil
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// zoneList holds the IANA timezone names of the Go time zone database, one
+// per line, used to suggest a name for a mistyped timezone
+//
+//go:embed zones.txt
+var zoneList string
+
+// TimezoneNames returns the known IANA timezone names in alphabetical order
+func TimezoneNames() []string {
+	return strings.Fields(zoneList)
+}
+
+// SuggestTimezone returns the known timezone name closest to name, e.g.
+// "Europe/Oslo" for "Europe/Olso" or "europe/oslo", or "" if none is close.
+// A name without a region ("Oslo") is matched against the city of each zone.
+func SuggestTimezone(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return ""
+	}
+
+	zones := TimezoneNames()
+	for _, zone := range zones {
+		if strings.ToLower(zone) == name {
+			return zone
+		}
+	}
+
+	best, bestDistance := "", max(2, len(name)/5)+1
+	for _, zone := range zones {
+		candidate := strings.ToLower(zone)
+		if !strings.Contains(name, "/") {
+			candidate = candidate[strings.LastIndex(candidate, "/")+1:]
+		}
+		if d := editDistance(name, candidate); d < bestDistance {
+			best, bestDistance = zone, d
+		}
+	}
+	return best
+}
+
+// TimezoneError returns the error for the invalid timezone name tz, naming
+// the closest known timezone when there is one
+func TimezoneError(tz string) error {
+	if suggestion := SuggestTimezone(tz); suggestion != "" {
+		return fmt.Errorf("'%s' is not a valid IANA timezone (did you mean '%s'?)", tz, suggestion)
+	}
+	return fmt.Errorf("'%s' is not a valid IANA timezone (e.g., 'America/New_York', 'Europe/London')", tz)
+}
+
+// MustLoadTimezone loads a timezone like LoadTimezone, and panics naming the
+// timezone if it is invalid. The configured timezone is validated on startup,
+// so an invalid one here is a bug that must not silently shift every date to
+// the local timezone. Only use it for a validated config; NowIn and
+// InTimezone return the error instead.
+func MustLoadTimezone(tz string) *time.Location {
+	loc, err := LoadTimezone(tz)
+	if err != nil {
+		panic(fmt.Sprintf("invalid timezone: %v", TimezoneError(tz)))
+	}
+	return loc
+}
+
+// editDistance returns the number of single character insertions, deletions,
+// substitutions and transpositions of adjacent characters turning a into b
+func editDistance(a, b string) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,73 @@
+package timeutil
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimezoneNames_Load(t *testing.T) {
+	names := TimezoneNames()
+	if len(names) < 400 {
+		t.Fatalf("TimezoneNames() returned %d names, expected the IANA zone list", len(names))
+	}
+	for _, name := range []string{"Europe/Oslo", "America/New_York", "UTC"} {
+		found := false
+		for _, n := range names {
+			found = found || n == name
+		}
+		if !found {
+			t.Errorf("TimezoneNames() is missing %q", name)
+		}
+	}
+	if _, err := time.LoadLocation(names[0]); err != nil {
+		t.Errorf("LoadLocation(%q) returned error: %v", names[0], err)
+	}
+}
+
+func TestSuggestTimezone(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Europe/Olso", "Europe/Oslo"},
+		{"europe/oslo", "Europe/Oslo"},
+		{"America/New_Yrok", "America/New_York"},
+		{"America/NewYork", "America/New_York"},
+		{"Oslo", "Europe/Oslo"},
+		{"utc", "UTC"},
+		{"Mars/Olympus", ""},
+		{"not_a_timezone", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := SuggestTimezone(tt.input); got != tt.expected {
+			t.Errorf("SuggestTimezone(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestTimezoneError(t *testing.T) {
+	if err := TimezoneError("Europe/Olso"); !strings.Contains(err.Error(), "did you mean 'Europe/Oslo'?") {
+		t.Errorf("TimezoneError() = %v, expected a suggestion", err)
+	}
+	if err := TimezoneError("Mars/Olympus"); !strings.Contains(err.Error(), "e.g., 'America/New_York'") {
+		t.Errorf("TimezoneError() = %v, expected examples", err)
+	}
+}
+
+func TestMustLoadTimezone(t *testing.T) {
+	if loc := MustLoadTimezone("Europe/Oslo"); loc.String() != "Europe/Oslo" {
+		t.Errorf("MustLoadTimezone() = %v, expected Europe/Oslo", loc)
+	}
+	if loc := MustLoadTimezone(""); loc != time.Local {
+		t.Errorf("MustLoadTimezone(\"\") = %v, expected Local", loc)
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "'Europe/Olso'") {
+			t.Errorf("MustLoadTimezone() should panic naming the timezone, got %v", r)
+		}
+	}()
+	MustLoadTimezone("Europe/Olso")
+}
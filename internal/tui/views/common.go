@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/xolan/did/internal/service"
+	"github.com/xolan/did/internal/timeutil"
 	"github.com/xolan/did/internal/tui/ui"
 )
 
@@ -114,22 +115,15 @@ func RenderEntryList(entries []service.IndexedEntry, styles ui.Styles, opts Entr
 
 // formatDuration formats minutes as human-readable duration
 func formatDuration(minutes int) string {
-	if minutes < 60 {
-		return fmt.Sprintf("%dm", minutes)
-	}
-	hours := minutes / 60
-	mins := minutes % 60
-	if mins == 0 {
-		return fmt.Sprintf("%dh", hours)
-	}
-	return fmt.Sprintf("%dh %dm", hours, mins)
+	return timeutil.FormatDuration(minutes)
 }
 
-func pluralize(word string, count int) string {
+// pluralize returns singular when count is 1 and plural otherwise
+func pluralize(singular, plural string, count int) string {
 	if count == 1 {
-		return word
+		return singular
 	}
-	return word + "s"
+	return plural
 }
 
 func min(a, b int) int {
@@ -382,7 +382,7 @@ func (m EntriesModel) View() string {
 	b.WriteString(fmt.Sprintf("Total: %s (%d %s)",
 		formatDuration(m.total),
 		len(m.entries),
-		pluralize("entry", len(m.entries))))
+		pluralize("entry", "entries", len(m.entries))))
 
 	return b.String()
 }
@@ -486,7 +486,7 @@ func (m EntriesModel) renderSearchView() string {
 	}
 
 	// Results count
-	b.WriteString(fmt.Sprintf("Found %d %s:\n\n", len(m.searchResults), pluralize("result", len(m.searchResults))))
+	b.WriteString(fmt.Sprintf("Found %d %s:\n\n", len(m.searchResults), pluralize("result", "results", len(m.searchResults))))
 
 	// Render results using shared renderer (always show date)
 	b.WriteString(RenderEntryList(m.searchResults, m.styles, EntryRenderOptions{
@@ -541,8 +541,8 @@ func (m EntriesModel) loadEntries() tea.Cmd {
 // addEntry creates a command to add a new entry
 func (m EntriesModel) addEntry(description, duration string) tea.Cmd {
 	return func() tea.Msg {
-		// Format: "description for duration"
-		input := description + " for " + duration
+		// Format: "description for duration", with the configured keyword
+		input := description + " " + m.services.Entry.DurationKeyword() + " " + duration
 		_, err := m.services.Entry.Create(input)
 		if err != nil {
 			return entriesLoadedMsg{err: err}
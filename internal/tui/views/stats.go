@@ -103,8 +103,8 @@ func (m StatsModel) View() string {
 	// Statistics
 	stats := m.result.Statistics
 	b.WriteString(m.renderStatLine("Total time:", formatDuration(stats.TotalMinutes)))
-	b.WriteString(m.renderStatLine("Total entries:", fmt.Sprintf("%d %s", stats.EntryCount, pluralize("entry", stats.EntryCount))))
-	b.WriteString(m.renderStatLine("Days with work:", fmt.Sprintf("%d %s", stats.DaysWithEntries, pluralize("day", stats.DaysWithEntries))))
+	b.WriteString(m.renderStatLine("Total entries:", fmt.Sprintf("%d %s", stats.EntryCount, pluralize("entry", "entries", stats.EntryCount))))
+	b.WriteString(m.renderStatLine("Days with work:", fmt.Sprintf("%d %s", stats.DaysWithEntries, pluralize("day", "days", stats.DaysWithEntries))))
 	b.WriteString(m.renderStatLine("Average per day:", formatDuration(int(stats.AverageMinutesPerDay))))
 
 	// Comparison
@@ -129,7 +129,7 @@ func (m StatsModel) View() string {
 				projectName,
 				formatDuration(ps.TotalMinutes),
 				ps.EntryCount,
-				pluralize("entry", ps.EntryCount))
+				pluralize("entry", "entries", ps.EntryCount))
 			b.WriteString(line)
 			b.WriteString("\n")
 		}
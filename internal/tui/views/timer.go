@@ -10,6 +10,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/xolan/did/internal/service"
 	"github.com/xolan/did/internal/timer"
+	"github.com/xolan/did/internal/timeutil"
 	"github.com/xolan/did/internal/tui/ui"
 )
 
@@ -274,16 +275,7 @@ func formatTimerStartTime(t time.Time) string {
 }
 
 func formatElapsedTime(d time.Duration) string {
-	totalMinutes := int(d.Minutes())
-	if totalMinutes < 60 {
-		return fmt.Sprintf("%dm", totalMinutes)
-	}
-	hours := totalMinutes / 60
-	mins := totalMinutes % 60
-	if mins == 0 {
-		return fmt.Sprintf("%dh", hours)
-	}
-	return fmt.Sprintf("%dh %dm", hours, mins)
+	return timeutil.FormatDuration(int(d.Minutes()))
 }
 
 // IsInputMode returns true when the view is capturing keyboard input
@@ -195,21 +195,22 @@ func TestFormatDuration(t *testing.T) {
 
 func TestPluralize(t *testing.T) {
 	tests := []struct {
-		word  string
-		count int
-		want  string
+		singular string
+		plural   string
+		count    int
+		want     string
 	}{
-		{"entry", 0, "entrys"},
-		{"entry", 1, "entry"},
-		{"entry", 2, "entrys"},
-		{"day", 1, "day"},
-		{"day", 5, "days"},
+		{"entry", "entries", 0, "entries"},
+		{"entry", "entries", 1, "entry"},
+		{"entry", "entries", 2, "entries"},
+		{"day", "days", 1, "day"},
+		{"day", "days", 5, "days"},
 	}
 
 	for _, tt := range tests {
-		result := pluralize(tt.word, tt.count)
+		result := pluralize(tt.singular, tt.plural, tt.count)
 		if result != tt.want {
-			t.Errorf("pluralize(%q, %d) = %q, want %q", tt.word, tt.count, result, tt.want)
+			t.Errorf("pluralize(%q, %q, %d) = %q, want %q", tt.singular, tt.plural, tt.count, result, tt.want)
 		}
 	}
 }